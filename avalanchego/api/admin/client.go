@@ -5,9 +5,15 @@ package admin
 
 import (
 	"context"
+	"time"
 
 	"github.com/flare-foundation/flare/api"
+	"github.com/flare-foundation/flare/database/backup"
+	"github.com/flare-foundation/flare/network/peerfilter"
+	"github.com/flare-foundation/flare/snow/consensus/snowman/poll"
 	"github.com/flare-foundation/flare/utils/rpc"
+
+	cjson "github.com/flare-foundation/flare/utils/json"
 )
 
 // Interface compliance
@@ -23,6 +29,15 @@ type Client interface {
 	AliasChain(ctx context.Context, chainID string, alias string) (bool, error)
 	GetChainAliases(ctx context.Context, chainID string) ([]string, error)
 	Stacktrace(context.Context) (bool, error)
+	GetPollJournal(ctx context.Context, chain string, limit uint64) ([]poll.JournalEntry, error)
+	GetBenched(context.Context) ([]BenchedPeer, error)
+	BenchPeer(ctx context.Context, nodeID string, duration time.Duration, chains []string) (bool, error)
+	UnbenchPeer(ctx context.Context, nodeID string, chains []string) (bool, error)
+	GetPeerFilter(context.Context) (*peerfilter.Config, error)
+	SetPeerFilter(ctx context.Context, allow []string, deny []string) (bool, error)
+	ReloadPeerFilter(context.Context) (bool, error)
+	SetSentries(ctx context.Context, sentryIDs []string, sentryIPs []string, privatePeerIDs []string) (bool, error)
+	BackupDatabase(ctx context.Context, path string) (*backup.Manifest, error)
 }
 
 // Client implementation for the Avalanche Platform Info API Endpoint
@@ -92,3 +107,76 @@ func (c *client) Stacktrace(ctx context.Context) (bool, error) {
 	err := c.requester.SendRequest(ctx, "stacktrace", struct{}{}, res)
 	return res.Success, err
 }
+
+func (c *client) GetPollJournal(ctx context.Context, chain string, limit uint64) ([]poll.JournalEntry, error) {
+	res := &GetPollJournalReply{}
+	err := c.requester.SendRequest(ctx, "getPollJournal", &GetPollJournalArgs{
+		Chain: chain,
+		Limit: cjson.Uint64(limit),
+	}, res)
+	return res.Entries, err
+}
+
+func (c *client) GetBenched(ctx context.Context) ([]BenchedPeer, error) {
+	res := &GetBenchedReply{}
+	err := c.requester.SendRequest(ctx, "getBenched", struct{}{}, res)
+	return res.Benched, err
+}
+
+func (c *client) BenchPeer(ctx context.Context, nodeID string, duration time.Duration, chains []string) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest(ctx, "benchPeer", &BenchPeerArgs{
+		NodeID:   nodeID,
+		Duration: cjson.Uint64(duration / time.Second),
+		Chains:   chains,
+	}, res)
+	return res.Success, err
+}
+
+func (c *client) UnbenchPeer(ctx context.Context, nodeID string, chains []string) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest(ctx, "unbenchPeer", &UnbenchPeerArgs{
+		NodeID: nodeID,
+		Chains: chains,
+	}, res)
+	return res.Success, err
+}
+
+func (c *client) GetPeerFilter(ctx context.Context) (*peerfilter.Config, error) {
+	res := &peerfilter.Config{}
+	err := c.requester.SendRequest(ctx, "getPeerFilter", struct{}{}, res)
+	return res, err
+}
+
+func (c *client) SetPeerFilter(ctx context.Context, allow []string, deny []string) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest(ctx, "setPeerFilter", &peerfilter.Config{
+		Allow: allow,
+		Deny:  deny,
+	}, res)
+	return res.Success, err
+}
+
+func (c *client) ReloadPeerFilter(ctx context.Context) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest(ctx, "reloadPeerFilter", struct{}{}, res)
+	return res.Success, err
+}
+
+func (c *client) SetSentries(ctx context.Context, sentryIDs []string, sentryIPs []string, privatePeerIDs []string) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest(ctx, "setSentries", &SetSentriesArgs{
+		SentryIDs:      sentryIDs,
+		SentryIPs:      sentryIPs,
+		PrivatePeerIDs: privatePeerIDs,
+	}, res)
+	return res.Success, err
+}
+
+func (c *client) BackupDatabase(ctx context.Context, path string) (*backup.Manifest, error) {
+	res := &backup.Manifest{}
+	err := c.requester.SendRequest(ctx, "backupDatabase", &BackupDatabaseArgs{
+		Path: path,
+	}, res)
+	return res, err
+}
@@ -4,16 +4,27 @@
 package admin
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
 	"net/http"
+	"sort"
+	"time"
 
 	"github.com/gorilla/rpc/v2"
 
 	"github.com/flare-foundation/flare/api"
 	"github.com/flare-foundation/flare/api/server"
 	"github.com/flare-foundation/flare/chains"
+	"github.com/flare-foundation/flare/database/backup"
+	"github.com/flare-foundation/flare/database/manager"
 	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/network"
+	"github.com/flare-foundation/flare/network/peerfilter"
+	"github.com/flare-foundation/flare/snow/consensus/snowman/poll"
 	"github.com/flare-foundation/flare/snow/engine/common"
+	"github.com/flare-foundation/flare/snow/networking/benchlist"
+	"github.com/flare-foundation/flare/utils"
 	"github.com/flare-foundation/flare/utils/constants"
 	"github.com/flare-foundation/flare/utils/logging"
 	"github.com/flare-foundation/flare/utils/perms"
@@ -27,20 +38,32 @@ const (
 
 	// Name of file that stacktraces are written to
 	stacktraceFile = "stacktrace.txt"
+
+	// Longest time a peer can be benched for with BenchPeer
+	maxBenchDuration = 365 * 24 * time.Hour
 )
 
 var (
 	errAliasTooLong = errors.New("alias length is too long")
 	errNoLogLevel   = errors.New("need to specify either displayLevel or logLevel")
+	errNoJournal    = errors.New("chain doesn't record its polls")
+	errNoDuration   = errors.New("bench duration must be positive")
+	errLongDuration = errors.New("bench duration is too long")
+	errNoFilterFile = errors.New("the peer filter isn't read from a file")
+	errNoBackupPath = errors.New("backup path must be specified")
 )
 
 type Config struct {
-	Log          logging.Logger
-	ProfileDir   string
-	LogFactory   logging.Factory
-	NodeConfig   interface{}
-	ChainManager chains.Manager
-	HTTPServer   *server.Server
+	Log            logging.Logger
+	ProfileDir     string
+	LogFactory     logging.Factory
+	NodeConfig     interface{}
+	ChainManager   chains.Manager
+	HTTPServer     *server.Server
+	Benchlist      benchlist.Manager
+	Network        network.Network
+	PeerFilterFile string
+	DB             *manager.VersionedDatabase
 }
 
 // Admin is the API service for node admin management
@@ -269,3 +292,251 @@ func (service *Admin) GetConfig(_ *http.Request, args *struct{}, reply *interfac
 	*reply = service.NodeConfig
 	return nil
 }
+
+// GetPollJournalArgs are the arguments for calling GetPollJournal
+type GetPollJournalArgs struct {
+	Chain string `json:"chain"`
+	// Maximum number of entries to return. If 0, every entry is returned.
+	Limit cjson.Uint64 `json:"limit"`
+}
+
+// GetPollJournalReply are the most recent polls of the given chain
+type GetPollJournalReply struct {
+	Entries []poll.JournalEntry `json:"entries"`
+}
+
+// GetPollJournal returns the most recent polls of the chain, oldest first
+func (service *Admin) GetPollJournal(_ *http.Request, args *GetPollJournalArgs, reply *GetPollJournalReply) error {
+	service.Log.Debug("Admin: GetPollJournal called with Chain: %s, Limit: %d", args.Chain, args.Limit)
+
+	chainID, err := service.ChainManager.Lookup(args.Chain)
+	if err != nil {
+		return err
+	}
+	journal, ok := service.ChainManager.PollJournal(chainID)
+	if !ok {
+		return errNoJournal
+	}
+
+	reply.Entries, err = journal.Entries(uint64(args.Limit))
+	return err
+}
+
+// BenchedPeer is a peer whose queries regarding a chain fail immediately
+type BenchedPeer struct {
+	NodeID       string    `json:"nodeID"`
+	ChainID      ids.ID    `json:"chainID"`
+	BenchedUntil time.Time `json:"benchedUntil"`
+}
+
+// GetBenchedReply are the benched peers of every chain
+type GetBenchedReply struct {
+	Benched []BenchedPeer `json:"benched"`
+}
+
+// GetBenched returns the peers that are benched, ordered by chain and by the
+// time they leave the bench
+func (service *Admin) GetBenched(_ *http.Request, _ *struct{}, reply *GetBenchedReply) error {
+	service.Log.Debug("Admin: GetBenched called")
+
+	reply.Benched = []BenchedPeer{}
+	for chainID, benched := range service.Benchlist.Benched() {
+		for nodeID, benchedUntil := range benched {
+			reply.Benched = append(reply.Benched, BenchedPeer{
+				NodeID:       nodeID.PrefixedString(constants.NodeIDPrefix),
+				ChainID:      chainID,
+				BenchedUntil: benchedUntil,
+			})
+		}
+	}
+	sort.Slice(reply.Benched, func(i, j int) bool {
+		if reply.Benched[i].ChainID != reply.Benched[j].ChainID {
+			return bytes.Compare(reply.Benched[i].ChainID[:], reply.Benched[j].ChainID[:]) < 0
+		}
+		return reply.Benched[i].BenchedUntil.Before(reply.Benched[j].BenchedUntil)
+	})
+	return nil
+}
+
+// BenchPeerArgs are the arguments for calling BenchPeer
+type BenchPeerArgs struct {
+	NodeID string `json:"nodeID"`
+	// Number of seconds the peer is benched for
+	Duration cjson.Uint64 `json:"duration"`
+	// Chains the peer is benched on. If empty, the peer is benched on every
+	// chain.
+	Chains []string `json:"chains"`
+}
+
+// BenchPeer benches a peer so that queries to it fail immediately, whether or
+// not it responds to them
+func (service *Admin) BenchPeer(_ *http.Request, args *BenchPeerArgs, reply *api.SuccessResponse) error {
+	service.Log.Debug("Admin: BenchPeer called with NodeID: %s, Duration: %d, Chains: %v", args.NodeID, args.Duration, args.Chains)
+
+	if args.Duration == 0 {
+		return errNoDuration
+	}
+	if maxSeconds := uint64(maxBenchDuration / time.Second); uint64(args.Duration) > maxSeconds {
+		return fmt.Errorf("%w: %d seconds, maximum is %d", errLongDuration, args.Duration, maxSeconds)
+	}
+	nodeID, chainIDs, err := service.parseBenchArgs(args.NodeID, args.Chains)
+	if err != nil {
+		return err
+	}
+
+	if err := service.Benchlist.Bench(nodeID, time.Duration(args.Duration)*time.Second, chainIDs); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
+
+// UnbenchPeerArgs are the arguments for calling UnbenchPeer
+type UnbenchPeerArgs struct {
+	NodeID string `json:"nodeID"`
+	// Chains the peer is unbenched on. If empty, the peer is unbenched on
+	// every chain.
+	Chains []string `json:"chains"`
+}
+
+// UnbenchPeer removes a peer from the bench
+func (service *Admin) UnbenchPeer(_ *http.Request, args *UnbenchPeerArgs, reply *api.SuccessResponse) error {
+	service.Log.Debug("Admin: UnbenchPeer called with NodeID: %s, Chains: %v", args.NodeID, args.Chains)
+
+	nodeID, chainIDs, err := service.parseBenchArgs(args.NodeID, args.Chains)
+	if err != nil {
+		return err
+	}
+
+	if err := service.Benchlist.Unbench(nodeID, chainIDs); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
+
+func (service *Admin) parseBenchArgs(nodeIDStr string, chains []string) (ids.ShortID, []ids.ID, error) {
+	nodeID, err := ids.ShortFromPrefixedString(nodeIDStr, constants.NodeIDPrefix)
+	if err != nil {
+		return ids.ShortID{}, nil, err
+	}
+	chainIDs := make([]ids.ID, len(chains))
+	for i, chain := range chains {
+		chainIDs[i], err = service.ChainManager.Lookup(chain)
+		if err != nil {
+			return ids.ShortID{}, nil, err
+		}
+	}
+	return nodeID, chainIDs, nil
+}
+
+// GetPeerFilter returns the allow and deny lists of peers
+func (service *Admin) GetPeerFilter(_ *http.Request, _ *struct{}, reply *peerfilter.Config) error {
+	service.Log.Debug("Admin: GetPeerFilter called")
+
+	*reply = service.Network.PeerFilter()
+	return nil
+}
+
+// SetPeerFilter replaces the allow and deny lists of peers and disconnects
+// from the peers that are no longer allowed. The lists are replaced again if
+// the peer filter is reloaded from its file.
+func (service *Admin) SetPeerFilter(_ *http.Request, args *peerfilter.Config, reply *api.SuccessResponse) error {
+	service.Log.Debug("Admin: SetPeerFilter called with Allow: %v, Deny: %v", args.Allow, args.Deny)
+
+	if err := service.Network.UpdatePeerFilter(*args); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
+
+// ReloadPeerFilter reads the allow and deny lists of peers from the peer filter
+// file again and disconnects from the peers that are no longer allowed
+func (service *Admin) ReloadPeerFilter(_ *http.Request, _ *struct{}, reply *api.SuccessResponse) error {
+	service.Log.Debug("Admin: ReloadPeerFilter called")
+
+	if service.PeerFilterFile == "" {
+		return errNoFilterFile
+	}
+	config, err := peerfilter.ReadConfig(service.PeerFilterFile)
+	if err != nil {
+		return err
+	}
+	if err := service.Network.UpdatePeerFilter(config); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
+
+// SetSentriesArgs are the arguments for calling SetSentries
+type SetSentriesArgs struct {
+	// Node IDs of the sentries of this validator
+	SentryIDs []string `json:"sentryIDs"`
+	// IPs of the sentries of this validator, in the order of [SentryIDs]
+	SentryIPs []string `json:"sentryIPs"`
+	// Node IDs of the validators this node is a sentry for
+	PrivatePeerIDs []string `json:"privatePeerIDs"`
+}
+
+// SetSentries replaces the sentries of this node and the validators it is a
+// sentry for. A sentry that is removed from the sentries of its validator is
+// no longer trusted by its peers once the relay certificate it holds expires.
+func (service *Admin) SetSentries(_ *http.Request, args *SetSentriesArgs, reply *api.SuccessResponse) error {
+	service.Log.Debug("Admin: SetSentries called with SentryIDs: %v, SentryIPs: %v, PrivatePeerIDs: %v", args.SentryIDs, args.SentryIPs, args.PrivatePeerIDs)
+
+	config := network.SentryConfig{}
+	for _, id := range args.SentryIDs {
+		nodeID, err := ids.ShortFromPrefixedString(id, constants.NodeIDPrefix)
+		if err != nil {
+			return fmt.Errorf("couldn't parse sentry id: %w", err)
+		}
+		config.SentryIDs = append(config.SentryIDs, nodeID)
+	}
+	for _, ip := range args.SentryIPs {
+		addr, err := utils.ToIPDesc(ip)
+		if err != nil {
+			return fmt.Errorf("couldn't parse sentry ip %s: %w", ip, err)
+		}
+		config.SentryIPs = append(config.SentryIPs, addr)
+	}
+	for _, id := range args.PrivatePeerIDs {
+		nodeID, err := ids.ShortFromPrefixedString(id, constants.NodeIDPrefix)
+		if err != nil {
+			return fmt.Errorf("couldn't parse private peer id: %w", err)
+		}
+		config.PrivatePeerIDs = append(config.PrivatePeerIDs, nodeID)
+	}
+
+	if err := service.Network.UpdateSentries(config); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
+
+// BackupDatabaseArgs are the arguments for calling BackupDatabase
+type BackupDatabaseArgs struct {
+	// Directory the backup is written to. It must not exist.
+	Path string `json:"path"`
+}
+
+// BackupDatabase writes a consistent snapshot of the node's database, which
+// includes the state of every chain, to a new directory. The node keeps
+// running while the backup is created. The backup can be restored with the
+// restore subcommand while the node is stopped.
+func (service *Admin) BackupDatabase(_ *http.Request, args *BackupDatabaseArgs, reply *backup.Manifest) error {
+	service.Log.Debug("Admin: BackupDatabase called with Path: %s", args.Path)
+
+	if args.Path == "" {
+		return errNoBackupPath
+	}
+	manifest, err := backup.Create(service.DB.Database, service.DB.Version, args.Path)
+	if err != nil {
+		return err
+	}
+	service.Log.Info("backed up %d keys of database %s to %s", manifest.NumKeys, manifest.DatabaseVersion, args.Path)
+	*reply = manifest
+	return nil
+}
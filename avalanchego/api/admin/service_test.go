@@ -0,0 +1,57 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/flare-foundation/flare/api"
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/snow/networking/benchlist"
+	"github.com/flare-foundation/flare/utils/constants"
+	"github.com/flare-foundation/flare/utils/logging"
+
+	cjson "github.com/flare-foundation/flare/utils/json"
+)
+
+func TestBenchPeerDuration(t *testing.T) {
+	benched := map[ids.ShortID]time.Duration{}
+	service := &Admin{Config: Config{
+		Log:       logging.NoLog{},
+		Benchlist: &durationBenchlist{Manager: benchlist.NewNoBenchlist(), benched: benched},
+	}}
+	nodeID := ids.GenerateTestShortID()
+	args := &BenchPeerArgs{NodeID: nodeID.PrefixedString(constants.NodeIDPrefix)}
+	reply := &api.SuccessResponse{}
+
+	args.Duration = 0
+	assert.ErrorIs(t, service.BenchPeer(nil, args, reply), errNoDuration)
+
+	// Durations that would overflow a time.Duration are rejected
+	for _, duration := range []uint64{uint64(maxBenchDuration/time.Second) + 1, math.MaxUint64 / 1000, math.MaxUint64} {
+		args.Duration = cjson.Uint64(duration)
+		assert.ErrorIs(t, service.BenchPeer(nil, args, reply), errLongDuration)
+	}
+	assert.Empty(t, benched)
+
+	args.Duration = cjson.Uint64(maxBenchDuration / time.Second)
+	assert.NoError(t, service.BenchPeer(nil, args, reply))
+	assert.True(t, reply.Success)
+	assert.Equal(t, maxBenchDuration, benched[nodeID])
+}
+
+// durationBenchlist records the duration each validator is benched for
+type durationBenchlist struct {
+	benchlist.Manager
+	benched map[ids.ShortID]time.Duration
+}
+
+func (b *durationBenchlist) Bench(validatorID ids.ShortID, duration time.Duration, _ []ids.ID) error {
+	b.benched[validatorID] = duration
+	return nil
+}
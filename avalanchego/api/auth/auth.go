@@ -6,6 +6,8 @@ package auth
 import (
 	"crypto/rand"
 	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -18,6 +20,8 @@ import (
 
 	"github.com/gorilla/rpc/v2"
 
+	"github.com/flare-foundation/flare/database"
+	"github.com/flare-foundation/flare/database/prefixdb"
 	"github.com/flare-foundation/flare/utils/logging"
 	"github.com/flare-foundation/flare/utils/password"
 	"github.com/flare-foundation/flare/utils/timer/mockable"
@@ -36,6 +40,7 @@ const (
 	defaultTokenLifespan = time.Hour * 12
 
 	maxEndpoints = 128
+	maxMethods   = 128
 )
 
 var (
@@ -47,12 +52,25 @@ var (
 	)
 	errInvalidSigningMethod        = errors.New("auth token didn't specify the HS256 signing method correctly")
 	errTokenRevoked                = errors.New("the provided auth token was revoked")
+	errUnknownTokenID              = errors.New("no unexpired token has the ID")
+	errTokenAndID                  = errors.New("can't name both a token and a token ID")
 	errTokenInsufficientPermission = errors.New("the provided auth token does not allow access to this endpoint")
 	errWrongPassword               = errors.New("incorrect password")
 	errSamePassword                = errors.New("new password can't be same as old password")
 	errNoPassword                  = errors.New("no password")
 	errNoEndpoints                 = errors.New("must name at least one endpoint")
 	errTooManyEndpoints            = fmt.Errorf("can only name at most %d endpoints", maxEndpoints)
+	errTooManyMethods              = fmt.Errorf("can only name at most %d methods", maxMethods)
+	errInvalidPasswordHash         = errors.New("invalid persisted password hash")
+
+	// Key in the database whose value is the hash of the password
+	passwordKey = []byte("password")
+	// Prefix of the database that maps the ID of each issued token, which
+	// hasn't been revoked, to its TokenInfo
+	tokensPrefix = []byte("tokens")
+	// Prefix of the database that maps the ID of each revoked token to its
+	// expiry
+	revokedPrefix = []byte("revoked")
 
 	_ Auth = &auth{}
 )
@@ -61,17 +79,27 @@ type Auth interface {
 	// Create and return a new token that allows access to each API endpoint for
 	// [duration] such that the API's path ends with an element of [endpoints].
 	// If one of the elements of [endpoints] is "*", all APIs are accessible.
-	NewToken(pw string, duration time.Duration, endpoints []string) (string, error)
+	// If [methods] is non-empty, the token only allows calls to the JSON-RPC
+	// methods in [methods], such as "avm.getUTXOs". An element "avm.*" allows
+	// every method of the avm service.
+	NewToken(pw string, duration time.Duration, endpoints, methods []string) (string, error)
 
 	// Revokes [token]; it will not be accepted as authorization for future API
-	// calls. If the token is invalid, this is a no-op.  If a token is revoked
-	// and then the password is changed, and then changed back to the current
-	// password, the token will be un-revoked. Therefore, passwords shouldn't be
-	// re-used before previously revoked tokens have expired.
+	// calls. If the token is invalid, this is a no-op. Revocations persist
+	// across restarts.
 	RevokeToken(pw, token string) error
 
-	// Authenticates [token] for access to [url].
-	AuthenticateToken(token, url string) error
+	// Revokes the token whose ID is [id], as returned by ListTokens, like
+	// RevokeToken. Returns an error if no unexpired token has the ID.
+	RevokeTokenByID(id, pw string) error
+
+	// Authenticates [token] for access to [url]. [method] is the JSON-RPC
+	// method that is called, or the empty string if the request isn't a
+	// JSON-RPC call.
+	AuthenticateToken(token, url, method string) error
+
+	// ListTokens returns the tokens that are neither expired nor revoked.
+	ListTokens(pw string) ([]TokenInfo, error)
 
 	// Change the password required to create and revoke tokens.
 	// [oldPW] is the current password.
@@ -89,6 +117,14 @@ type Auth interface {
 	WrapHandler(h http.Handler) http.Handler
 }
 
+// TokenInfo describes an issued token
+type TokenInfo struct {
+	ID        string    `json:"id"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	Endpoints []string  `json:"endpoints"`
+	Methods   []string  `json:"methods,omitempty"`
+}
+
 type auth struct {
 	// Used to mock time.
 	clock mockable.Clock
@@ -99,29 +135,55 @@ type auth struct {
 	lock sync.RWMutex
 	// Can be changed via API call.
 	password password.Hash
-	// Set of token IDs that have been revoked
-	revoked map[string]struct{}
+
+	// Persists the password, the issued tokens and the revoked tokens
+	db        database.Database
+	tokenDB   database.Database
+	revokedDB database.Database
 }
 
-func New(log logging.Logger, endpoint, pw string) (Auth, error) {
-	a := &auth{
-		log:      log,
-		endpoint: endpoint,
-		revoked:  make(map[string]struct{}),
+// New returns an auth handler whose state is persisted in [db]. Tokens that
+// were issued before a restart remain valid, unless the password was changed
+// since they were issued.
+func New(log logging.Logger, endpoint, pw string, db database.Database) (Auth, error) {
+	a := newAuth(log, endpoint, db)
+	hashBytes, err := db.Get(passwordKey)
+	switch err {
+	case nil:
+		if err := unmarshalHash(hashBytes, &a.password); err != nil {
+			return nil, err
+		}
+		if a.password.Check(pw) {
+			return a, a.pruneExpired()
+		}
+	case database.ErrNotFound:
+	default:
+		return nil, err
 	}
-	return a, a.password.Set(pw)
+
+	// Tokens issued with a different password are invalid
+	return a, a.setPassword(pw)
+}
+
+// NewFromHash returns an auth handler with the password [pw] whose tokens are
+// persisted in [db]. The password isn't persisted.
+func NewFromHash(log logging.Logger, endpoint string, pw password.Hash, db database.Database) Auth {
+	a := newAuth(log, endpoint, db)
+	a.password = pw
+	return a
 }
 
-func NewFromHash(log logging.Logger, endpoint string, pw password.Hash) Auth {
+func newAuth(log logging.Logger, endpoint string, db database.Database) *auth {
 	return &auth{
-		log:      log,
-		endpoint: endpoint,
-		password: pw,
-		revoked:  make(map[string]struct{}),
+		log:       log,
+		endpoint:  endpoint,
+		db:        db,
+		tokenDB:   prefixdb.New(tokensPrefix, db),
+		revokedDB: prefixdb.New(revokedPrefix, db),
 	}
 }
 
-func (a *auth) NewToken(pw string, duration time.Duration, endpoints []string) (string, error) {
+func (a *auth) NewToken(pw string, duration time.Duration, endpoints, methods []string) (string, error) {
 	if pw == "" {
 		return "", errNoPassword
 	}
@@ -130,9 +192,12 @@ func (a *auth) NewToken(pw string, duration time.Duration, endpoints []string) (
 	} else if l > maxEndpoints {
 		return "", errTooManyEndpoints
 	}
+	if len(methods) > maxMethods {
+		return "", errTooManyMethods
+	}
 
-	a.lock.RLock()
-	defer a.lock.RUnlock()
+	a.lock.Lock()
+	defer a.lock.Unlock()
 
 	if !a.password.Check(pw) {
 		return "", errWrongPassword
@@ -157,12 +222,27 @@ func (a *auth) NewToken(pw string, duration time.Duration, endpoints []string) (
 			ExpiresAt: a.clock.Time().Add(duration).Unix(),
 			Id:        id,
 		},
+		Methods: methods,
 	}
 	if canAccessAll {
 		claims.Endpoints = []string{"*"}
 	} else {
 		claims.Endpoints = endpoints
 	}
+
+	infoBytes, err := json.Marshal(&TokenInfo{
+		ID:        id,
+		ExpiresAt: time.Unix(claims.ExpiresAt, 0).UTC(),
+		Endpoints: claims.Endpoints,
+		Methods:   claims.Methods,
+	})
+	if err != nil {
+		return "", err
+	}
+	if err := a.tokenDB.Put([]byte(id), infoBytes); err != nil {
+		return "", err
+	}
+
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &claims)
 	return token.SignedString(a.password.Password[:]) // Sign the token and return its string repr.
 }
@@ -197,17 +277,75 @@ func (a *auth) RevokeToken(tokenStr, pw string) error {
 	if !ok {
 		return fmt.Errorf("expected auth token's claims to be type endpointClaims but is %T", token.Claims)
 	}
-	a.revoked[claims.Id] = struct{}{}
+
+	return a.revoke(claims.Id, claims.ExpiresAt)
+}
+
+func (a *auth) RevokeTokenByID(id, pw string) error {
+	if id == "" {
+		return errNoToken
+	}
+	if pw == "" {
+		return errNoPassword
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if !a.password.Check(pw) {
+		return errWrongPassword
+	}
+	if err := a.pruneExpired(); err != nil {
+		return err
+	}
+
+	infoBytes, err := a.tokenDB.Get([]byte(id))
+	if err == database.ErrNotFound {
+		return fmt.Errorf("%w %q", errUnknownTokenID, id)
+	}
+	if err != nil {
+		return err
+	}
+	info := TokenInfo{}
+	if err := json.Unmarshal(infoBytes, &info); err != nil {
+		return err
+	}
+	return a.revoke(id, info.ExpiresAt.Unix())
+}
+
+// revoke the token whose ID is [id] and that expires at [expiresAt]. Assumes
+// [a.lock] is held.
+func (a *auth) revoke(id string, expiresAt int64) error {
+	// The expiry is kept so that the revocation can be pruned once the token
+	// has expired
+	expiryBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(expiryBytes, uint64(expiresAt))
+	if err := a.revokedDB.Put([]byte(id), expiryBytes); err != nil {
+		return err
+	}
+	return a.tokenDB.Delete([]byte(id))
+}
+
+func (a *auth) AuthenticateToken(tokenStr, url, method string) error {
+	scope, err := a.verifyToken(tokenStr)
+	if err != nil {
+		return err
+	}
+	if !scope.allows(url, method) {
+		return errTokenInsufficientPermission
+	}
 	return nil
 }
 
-func (a *auth) AuthenticateToken(tokenStr, url string) error {
+// verifyToken returns the scope of [tokenStr] if it's a valid token that
+// hasn't been revoked
+func (a *auth) verifyToken(tokenStr string) (Scope, error) {
 	a.lock.RLock()
 	defer a.lock.RUnlock()
 
 	token, err := jwt.ParseWithClaims(tokenStr, &endpointClaims{}, a.getTokenKey)
 	if err != nil { // Probably because signature wrong
-		return err
+		return Scope{}, err
 	}
 
 	// Make sure this token gives access to the requested endpoint
@@ -215,20 +353,45 @@ func (a *auth) AuthenticateToken(tokenStr, url string) error {
 	if !ok {
 		// Error is intentionally dropped here as there is nothing left to do
 		// with it.
-		return fmt.Errorf("expected auth token's claims to be type endpointClaims but is %T", token.Claims)
+		return Scope{}, fmt.Errorf("expected auth token's claims to be type endpointClaims but is %T", token.Claims)
 	}
 
-	_, revoked := a.revoked[claims.Id]
+	revoked, err := a.revokedDB.Has([]byte(claims.Id))
+	if err != nil {
+		return Scope{}, err
+	}
 	if revoked {
-		return errTokenRevoked
+		return Scope{}, errTokenRevoked
+	}
+	return claims.scope(), nil
+}
+
+func (a *auth) ListTokens(pw string) ([]TokenInfo, error) {
+	if pw == "" {
+		return nil, errNoPassword
 	}
 
-	for _, endpoint := range claims.Endpoints {
-		if endpoint == "*" || strings.HasSuffix(url, endpoint) {
-			return nil
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if !a.password.Check(pw) {
+		return nil, errWrongPassword
+	}
+	if err := a.pruneExpired(); err != nil {
+		return nil, err
+	}
+
+	tokens := []TokenInfo{}
+	it := a.tokenDB.NewIterator()
+	defer it.Release()
+	for it.Next() {
+		info := TokenInfo{}
+		if err := json.Unmarshal(it.Value(), &info); err != nil {
+			return nil, err
 		}
+		tokens = append(tokens, info)
 	}
-	return errTokenInsufficientPermission
+	return tokens, it.Error()
 }
 
 func (a *auth) ChangePassword(oldPW, newPW string) error {
@@ -245,13 +408,96 @@ func (a *auth) ChangePassword(oldPW, newPW string) error {
 	if err := password.IsValid(newPW, password.OK); err != nil {
 		return err
 	}
-	if err := a.password.Set(newPW); err != nil {
+	return a.setPassword(newPW)
+}
+
+// setPassword changes the password to [pw] and forgets all the tokens, since
+// they are invalid under the new password.
+// Assumes [a.lock] is held or [a] isn't shared yet.
+func (a *auth) setPassword(pw string) error {
+	if err := a.password.Set(pw); err != nil {
+		return err
+	}
+	if err := clear(a.tokenDB); err != nil {
+		return err
+	}
+	if err := clear(a.revokedDB); err != nil {
+		return err
+	}
+	return a.db.Put(passwordKey, marshalHash(&a.password))
+}
+
+// pruneExpired deletes the expired tokens and revocations, since expired
+// tokens are rejected regardless.
+// Assumes [a.lock] is held or [a] isn't shared yet.
+func (a *auth) pruneExpired() error {
+	now := a.clock.Time().Unix()
+
+	batch := a.tokenDB.NewBatch()
+	it := a.tokenDB.NewIterator()
+	defer it.Release()
+	for it.Next() {
+		info := TokenInfo{}
+		if err := json.Unmarshal(it.Value(), &info); err != nil {
+			return err
+		}
+		if info.ExpiresAt.Unix() < now {
+			if err := batch.Delete(it.Key()); err != nil {
+				return err
+			}
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+
+	batch = a.revokedDB.NewBatch()
+	revokedIt := a.revokedDB.NewIterator()
+	defer revokedIt.Release()
+	for revokedIt.Next() {
+		if int64(binary.BigEndian.Uint64(revokedIt.Value())) < now {
+			if err := batch.Delete(revokedIt.Key()); err != nil {
+				return err
+			}
+		}
+	}
+	if err := revokedIt.Error(); err != nil {
 		return err
 	}
+	return batch.Write()
+}
 
-	// All the revoked tokens are now invalid; no need to mark specifically as
-	// revoked.
-	a.revoked = make(map[string]struct{})
+// clear deletes every key in [db]
+func clear(db database.Database) error {
+	batch := db.NewBatch()
+	it := db.NewIterator()
+	defer it.Release()
+	for it.Next() {
+		if err := batch.Delete(it.Key()); err != nil {
+			return err
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+func marshalHash(hash *password.Hash) []byte {
+	hashBytes := make([]byte, 0, len(hash.Password)+len(hash.Salt))
+	hashBytes = append(hashBytes, hash.Password[:]...)
+	return append(hashBytes, hash.Salt[:]...)
+}
+
+func unmarshalHash(hashBytes []byte, hash *password.Hash) error {
+	if len(hashBytes) != len(hash.Password)+len(hash.Salt) {
+		return errInvalidPasswordHash
+	}
+	copy(hash.Password[:], hashBytes)
+	copy(hash.Salt[:], hashBytes[len(hash.Password):])
 	return nil
 }
 
@@ -267,12 +513,18 @@ func (a *auth) CreateHandler() (http.Handler, error) {
 }
 
 func (a *auth) WrapHandler(h http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	return parseMethod(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Don't require auth token to hit auth endpoint
 		if path.Base(r.URL.Path) == a.endpoint {
 			h.ServeHTTP(w, r)
 			return
 		}
+		// Don't require auth token if the client certificate already
+		// authorized the request
+		if isCertificateAuthorized(r) {
+			h.ServeHTTP(w, r)
+			return
+		}
 
 		// Should be "Bearer AUTH.TOKEN.HERE"
 		rawHeader := r.Header.Get(headerKey)
@@ -289,13 +541,24 @@ func (a *auth) WrapHandler(h http.Handler) http.Handler {
 		// Returns actual auth token. Slice guaranteed to not go OOB
 		tokenStr := rawHeader[len(headerValStart):]
 
-		if err := a.AuthenticateToken(tokenStr, r.URL.Path); err != nil {
+		// The token is verified before the body of the request is read
+		scope, err := a.verifyToken(tokenStr)
+		if err != nil {
 			writeUnauthorizedResponse(w, err)
 			return
 		}
+		allowed, err := scope.allowsRequest(r)
+		if err != nil {
+			writeUnauthorizedResponse(w, err)
+			return
+		}
+		if !allowed {
+			writeUnauthorizedResponse(w, errTokenInsufficientPermission)
+			return
+		}
 
 		h.ServeHTTP(w, r)
-	})
+	}))
 }
 
 // getTokenKey returns the key to use when making and parsing tokens
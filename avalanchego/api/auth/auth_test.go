@@ -8,6 +8,7 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -18,6 +19,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/flare-foundation/flare/database/memdb"
 	"github.com/flare-foundation/flare/utils/logging"
 	"github.com/flare-foundation/flare/utils/password"
 )
@@ -38,24 +40,24 @@ func init() {
 var dummyHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
 
 func TestNewTokenWrongPassword(t *testing.T) {
-	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword)
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword, memdb.New())
 
-	_, err := auth.NewToken("", defaultTokenLifespan, []string{"endpoint1, endpoint2"})
+	_, err := auth.NewToken("", defaultTokenLifespan, []string{"endpoint1, endpoint2"}, nil)
 	assert.Error(t, err, "should have failed because password is wrong")
 
-	_, err = auth.NewToken("notThePassword", defaultTokenLifespan, []string{"endpoint1, endpoint2"})
+	_, err = auth.NewToken("notThePassword", defaultTokenLifespan, []string{"endpoint1, endpoint2"}, nil)
 	assert.Error(t, err, "should have failed because password is wrong")
 }
 
 func TestNewTokenHappyPath(t *testing.T) {
-	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword).(*auth)
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword, memdb.New()).(*auth)
 
 	now := time.Now()
 	auth.clock.Set(now)
 
 	// Make a token
 	endpoints := []string{"endpoint1", "endpoint2", "endpoint3"}
-	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints)
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints, nil)
 	assert.NoError(t, err)
 
 	// Parse the token
@@ -75,11 +77,11 @@ func TestNewTokenHappyPath(t *testing.T) {
 }
 
 func TestTokenHasWrongSig(t *testing.T) {
-	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword).(*auth)
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword, memdb.New()).(*auth)
 
 	// Make a token
 	endpoints := []string{"endpoint1", "endpoint2", "endpoint3"}
-	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints)
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints, nil)
 	assert.NoError(t, err)
 
 	// Try to parse the token using the wrong password
@@ -100,7 +102,7 @@ func TestTokenHasWrongSig(t *testing.T) {
 }
 
 func TestChangePassword(t *testing.T) {
-	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword).(*auth)
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword, memdb.New()).(*auth)
 
 	password2 := "fejhkefjhefjhefhje" // #nosec G101
 	var err error
@@ -128,24 +130,52 @@ func TestChangePassword(t *testing.T) {
 }
 
 func TestRevokeToken(t *testing.T) {
-	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword).(*auth)
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword, memdb.New()).(*auth)
 
 	// Make a token
 	endpoints := []string{"/ext/info", "/ext/bc/X", "/ext/metrics"}
-	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints)
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints, nil)
 	assert.NoError(t, err)
 
 	err = auth.RevokeToken(tokenStr, testPassword)
 	assert.NoError(t, err, "should have succeeded")
-	assert.Len(t, auth.revoked, 1, "revoked token list is incorrect")
+	tokens, err := auth.ListTokens(testPassword)
+	assert.NoError(t, err)
+	assert.Empty(t, tokens, "revoked token should not be listed")
+	err = auth.AuthenticateToken(tokenStr, "/ext/info", "")
+	assert.ErrorIs(t, err, errTokenRevoked)
+}
+
+func TestRevokeTokenByID(t *testing.T) {
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword, memdb.New()).(*auth)
+
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, []string{"/ext/info"}, nil)
+	assert.NoError(t, err)
+	otherTokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, []string{"/ext/info"}, nil)
+	assert.NoError(t, err)
+	token, err := jwt.ParseWithClaims(tokenStr, &endpointClaims{}, auth.getTokenKey)
+	assert.NoError(t, err)
+	id := token.Claims.(*endpointClaims).Id
+
+	assert.ErrorIs(t, auth.RevokeTokenByID(id, "notThePassword"), errWrongPassword)
+	assert.NoError(t, auth.RevokeTokenByID(id, testPassword))
+	assert.ErrorIs(t, auth.AuthenticateToken(tokenStr, "/ext/info", ""), errTokenRevoked)
+	assert.NoError(t, auth.AuthenticateToken(otherTokenStr, "/ext/info", ""))
+	tokens, err := auth.ListTokens(testPassword)
+	assert.NoError(t, err)
+	assert.Len(t, tokens, 1)
+
+	// The token can't be revoked twice, and unknown IDs are rejected
+	assert.ErrorIs(t, auth.RevokeTokenByID(id, testPassword), errUnknownTokenID)
+	assert.ErrorIs(t, auth.RevokeTokenByID("unknown", testPassword), errUnknownTokenID)
 }
 
 func TestWrapHandlerHappyPath(t *testing.T) {
-	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword)
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword, memdb.New())
 
 	// Make a token
 	endpoints := []string{"/ext/info", "/ext/bc/X", "/ext/metrics"}
-	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints)
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints, nil)
 	assert.NoError(t, err)
 
 	wrappedHandler := auth.WrapHandler(dummyHandler)
@@ -160,11 +190,11 @@ func TestWrapHandlerHappyPath(t *testing.T) {
 }
 
 func TestWrapHandlerRevokedToken(t *testing.T) {
-	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword)
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword, memdb.New())
 
 	// Make a token
 	endpoints := []string{"/ext/info", "/ext/bc/X", "/ext/metrics"}
-	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints)
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints, nil)
 	assert.NoError(t, err)
 
 	err = auth.RevokeToken(tokenStr, testPassword)
@@ -184,13 +214,13 @@ func TestWrapHandlerRevokedToken(t *testing.T) {
 }
 
 func TestWrapHandlerExpiredToken(t *testing.T) {
-	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword).(*auth)
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword, memdb.New()).(*auth)
 
 	auth.clock.Set(time.Now().Add(-2 * defaultTokenLifespan))
 
 	// Make a token that expired well in the past
 	endpoints := []string{"/ext/info", "/ext/bc/X", "/ext/metrics"}
-	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints)
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints, nil)
 	assert.NoError(t, err)
 
 	wrappedHandler := auth.WrapHandler(dummyHandler)
@@ -207,7 +237,7 @@ func TestWrapHandlerExpiredToken(t *testing.T) {
 }
 
 func TestWrapHandlerNoAuthToken(t *testing.T) {
-	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword)
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword, memdb.New())
 
 	endpoints := []string{"/ext/info", "/ext/bc/X", "/ext/metrics"}
 	wrappedHandler := auth.WrapHandler(dummyHandler)
@@ -222,11 +252,11 @@ func TestWrapHandlerNoAuthToken(t *testing.T) {
 }
 
 func TestWrapHandlerUnauthorizedEndpoint(t *testing.T) {
-	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword)
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword, memdb.New())
 
 	// Make a token
 	endpoints := []string{"/ext/info"}
-	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints)
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints, nil)
 	assert.NoError(t, err)
 
 	unauthorizedEndpoints := []string{"/ext/bc/X", "/ext/metrics", "", "/foo", "/ext/info/foo"}
@@ -244,11 +274,11 @@ func TestWrapHandlerUnauthorizedEndpoint(t *testing.T) {
 }
 
 func TestWrapHandlerAuthEndpoint(t *testing.T) {
-	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword)
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword, memdb.New())
 
 	// Make a token
 	endpoints := []string{"/ext/info", "/ext/bc/X", "/ext/metrics", "", "/foo", "/ext/info/foo"}
-	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints)
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints, nil)
 	assert.NoError(t, err)
 
 	wrappedHandler := auth.WrapHandler(dummyHandler)
@@ -260,11 +290,11 @@ func TestWrapHandlerAuthEndpoint(t *testing.T) {
 }
 
 func TestWrapHandlerAccessAll(t *testing.T) {
-	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword)
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword, memdb.New())
 
 	// Make a token that allows access to all endpoints
 	endpoints := []string{"/ext/info", "/ext/bc/X", "/ext/metrics", "", "/foo", "/ext/foo/info"}
-	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, []string{"*"})
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, []string{"*"}, nil)
 	assert.NoError(t, err)
 
 	wrappedHandler := auth.WrapHandler(dummyHandler)
@@ -285,11 +315,11 @@ func TestWriteUnauthorizedResponse(t *testing.T) {
 }
 
 func TestWrapHandlerMutatedRevokedToken(t *testing.T) {
-	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword)
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword, memdb.New())
 
 	// Make a token
 	endpoints := []string{"/ext/info", "/ext/bc/X", "/ext/metrics"}
-	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints)
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints, nil)
 	assert.NoError(t, err)
 
 	err = auth.RevokeToken(tokenStr, testPassword)
@@ -309,7 +339,7 @@ func TestWrapHandlerMutatedRevokedToken(t *testing.T) {
 }
 
 func TestWrapHandlerInvalidSigningMethod(t *testing.T) {
-	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword).(*auth)
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword, memdb.New()).(*auth)
 
 	// Make a token
 	endpoints := []string{"/ext/info", "/ext/bc/X", "/ext/metrics"}
@@ -344,3 +374,160 @@ func TestWrapHandlerInvalidSigningMethod(t *testing.T) {
 		assert.Regexp(t, unAuthorizedResponseRegex, rr.Body.String())
 	}
 }
+
+func TestRevocationPersists(t *testing.T) {
+	db := memdb.New()
+	auth1, err := New(logging.NoLog{}, "auth", testPassword, db)
+	assert.NoError(t, err)
+
+	endpoints := []string{"/ext/info"}
+	revokedToken, err := auth1.NewToken(testPassword, defaultTokenLifespan, endpoints, nil)
+	assert.NoError(t, err)
+	validToken, err := auth1.NewToken(testPassword, defaultTokenLifespan, endpoints, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, auth1.RevokeToken(revokedToken, testPassword))
+
+	// Restart with the same password
+	auth2, err := New(logging.NoLog{}, "auth", testPassword, db)
+	assert.NoError(t, err)
+	assert.ErrorIs(t, auth2.AuthenticateToken(revokedToken, "/ext/info", ""), errTokenRevoked)
+	assert.NoError(t, auth2.AuthenticateToken(validToken, "/ext/info", ""))
+
+	tokens, err := auth2.ListTokens(testPassword)
+	assert.NoError(t, err)
+	assert.Len(t, tokens, 1)
+
+	// Restart with a different password invalidates all the tokens
+	password2 := "fejhkefjhefjhefhje" // #nosec G101
+	auth3, err := New(logging.NoLog{}, "auth", password2, db)
+	assert.NoError(t, err)
+	assert.Error(t, auth3.AuthenticateToken(validToken, "/ext/info", ""))
+
+	tokens, err = auth3.ListTokens(password2)
+	assert.NoError(t, err)
+	assert.Empty(t, tokens)
+}
+
+func TestListTokens(t *testing.T) {
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword, memdb.New()).(*auth)
+
+	_, err := auth.ListTokens("notThePassword")
+	assert.ErrorIs(t, err, errWrongPassword)
+
+	endpoints := []string{"/ext/bc/X"}
+	methods := []string{"avm.getUTXOs"}
+	_, err = auth.NewToken(testPassword, defaultTokenLifespan, endpoints, methods)
+	assert.NoError(t, err)
+	_, err = auth.NewToken(testPassword, time.Minute, []string{"*"}, nil)
+	assert.NoError(t, err)
+
+	tokens, err := auth.ListTokens(testPassword)
+	assert.NoError(t, err)
+	assert.Len(t, tokens, 2)
+
+	// Expired tokens aren't listed
+	auth.clock.Set(time.Now().Add(2 * time.Minute))
+	tokens, err = auth.ListTokens(testPassword)
+	assert.NoError(t, err)
+	if assert.Len(t, tokens, 1) {
+		assert.Equal(t, endpoints, tokens[0].Endpoints)
+		assert.Equal(t, methods, tokens[0].Methods)
+	}
+}
+
+func TestNewTokenTooManyMethods(t *testing.T) {
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword, memdb.New())
+
+	methods := make([]string, maxMethods+1)
+	_, err := auth.NewToken(testPassword, defaultTokenLifespan, []string{"*"}, methods)
+	assert.ErrorIs(t, err, errTooManyMethods)
+}
+
+func TestWrapHandlerMethodScopes(t *testing.T) {
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword, memdb.New())
+
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, []string{"*"}, []string{"avm.getUTXOs", "info.*"})
+	assert.NoError(t, err)
+
+	wrappedHandler := auth.WrapHandler(dummyHandler)
+
+	tests := []struct {
+		body string
+		code int
+	}{
+		{`{"jsonrpc":"2.0","id":1,"method":"avm.getUTXOs","params":{}}`, http.StatusOK},
+		{`{"jsonrpc":"2.0","id":1,"method":"info.getNodeID","params":{}}`, http.StatusOK},
+		{`{"jsonrpc":"2.0","id":1,"method":"avm.export","params":{}}`, http.StatusUnauthorized},
+		{`{"jsonrpc":"2.0","id":1,"method":"information.getNodeID","params":{}}`, http.StatusUnauthorized},
+		{``, http.StatusUnauthorized},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodPost, "http://127.0.0.1:9650/ext/bc/X", strings.NewReader(test.body))
+		req.Header.Add("Authorization", "Bearer "+tokenStr)
+		rr := httptest.NewRecorder()
+		wrappedHandler.ServeHTTP(rr, req)
+		assert.Equal(t, test.code, rr.Code, test.body)
+	}
+}
+
+// readRecorder records whether the body of a request was read
+type readRecorder struct {
+	io.Reader
+	read bool
+}
+
+func (r *readRecorder) Read(p []byte) (int, error) {
+	r.read = true
+	return r.Reader.Read(p)
+}
+
+func TestWrapHandlerReadsBodyAfterAuthorization(t *testing.T) {
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword, memdb.New())
+
+	methodsToken, err := auth.NewToken(testPassword, defaultTokenLifespan, []string{"/ext/bc/X"}, []string{"avm.getUTXOs"})
+	assert.NoError(t, err)
+	endpointsToken, err := auth.NewToken(testPassword, defaultTokenLifespan, []string{"/ext/bc/X"}, nil)
+	assert.NoError(t, err)
+
+	wrappedHandler := auth.WrapHandler(dummyHandler)
+
+	tests := []struct {
+		name     string
+		url      string
+		token    string
+		code     int
+		bodyRead bool
+	}{
+		{"invalid token", "http://127.0.0.1:9650/ext/bc/X", "invalid", http.StatusUnauthorized, false},
+		{"unauthorized endpoint", "http://127.0.0.1:9650/ext/bc/P", methodsToken, http.StatusUnauthorized, false},
+		{"unrestricted methods", "http://127.0.0.1:9650/ext/bc/X", endpointsToken, http.StatusOK, false},
+		{"restricted methods", "http://127.0.0.1:9650/ext/bc/X", methodsToken, http.StatusOK, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			body := &readRecorder{Reader: strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"avm.getUTXOs","params":{}}`)}
+			req := httptest.NewRequest(http.MethodPost, test.url, body)
+			req.Header.Add("Authorization", "Bearer "+test.token)
+			rr := httptest.NewRecorder()
+			wrappedHandler.ServeHTTP(rr, req)
+			assert.Equal(t, test.code, rr.Code)
+			assert.Equal(t, test.bodyRead, body.read)
+		})
+	}
+}
+
+func TestWrapHandlerRequestTooLarge(t *testing.T) {
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword, memdb.New())
+
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, []string{"*"}, []string{"avm.getUTXOs"})
+	assert.NoError(t, err)
+
+	wrappedHandler := auth.WrapHandler(dummyHandler)
+
+	body := `{"jsonrpc":"2.0","id":1,"method":"avm.getUTXOs","params":{"padding":"` + strings.Repeat("a", maxRequestSize) + `"}}`
+	req := httptest.NewRequest(http.MethodPost, "http://127.0.0.1:9650/ext/bc/X", strings.NewReader(body))
+	req.Header.Add("Authorization", "Bearer "+tokenStr)
+	rr := httptest.NewRecorder()
+	wrappedHandler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
@@ -0,0 +1,173 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/flare-foundation/flare/utils/filewatch"
+	"github.com/flare-foundation/flare/utils/logging"
+)
+
+// certificateReloadInterval is how often the scopes file is checked for
+// modifications
+const certificateReloadInterval = 5 * time.Second
+
+var (
+	errNoClientCertificate               = errors.New("no verified client certificate provided")
+	errUnknownCertificate                = errors.New("client certificate subject has no scope")
+	errCertificateInsufficientPermission = errors.New("client certificate has insufficient permissions")
+)
+
+// certificateAuthorizedKey is the context key that marks a request as
+// authorized by its client certificate
+type certificateAuthorizedKey struct{}
+
+// CertificateScopes authorizes API requests by the common name of the subject
+// of their verified client certificate. Each common name maps to the scope of
+// API endpoints and methods that the certificate allows access to, just like
+// an auth token.
+//
+// The scopes are read from a JSON file of the form
+//
+//	{"<common name>": {"endpoints": ["/ext/info"], "methods": ["info.*"]}}
+//
+// that is reloaded when it is modified.
+type CertificateScopes struct {
+	log     logging.Logger
+	path    string
+	watcher *filewatch.Watcher
+
+	lock   sync.RWMutex
+	scopes map[string]Scope
+}
+
+// NewCertificateScopes returns the certificate scopes in the file at [path]
+func NewCertificateScopes(log logging.Logger, path string) (*CertificateScopes, error) {
+	return newCertificateScopes(log, path, certificateReloadInterval)
+}
+
+func newCertificateScopes(log logging.Logger, path string, reloadInterval time.Duration) (*CertificateScopes, error) {
+	watcher, err := filewatch.New(reloadInterval, path)
+	if err != nil {
+		return nil, err
+	}
+	scopes, err := loadCertificateScopes(path)
+	if err != nil {
+		return nil, err
+	}
+	return &CertificateScopes{
+		log:     log,
+		path:    path,
+		watcher: watcher,
+		scopes:  scopes,
+	}, nil
+}
+
+// WrapHandler rejects requests that aren't allowed by the scope of their
+// client certificate. Requests that are allowed don't require an auth token.
+func (c *CertificateScopes) WrapHandler(h http.Handler) http.Handler {
+	return parseMethod(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+			writeUnauthorizedResponse(w, errNoClientCertificate)
+			return
+		}
+		subject := r.TLS.VerifiedChains[0][0].Subject.CommonName
+
+		scope, ok := c.scope(subject)
+		if !ok {
+			writeUnauthorizedResponse(w, errUnknownCertificate)
+			return
+		}
+
+		// The body of the request is only read once the certificate is known
+		// to allow access to the endpoint
+		allowed, err := scope.allowsRequest(r)
+		if err != nil {
+			writeUnauthorizedResponse(w, err)
+			return
+		}
+		if !allowed {
+			writeUnauthorizedResponse(w, errCertificateInsufficientPermission)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), certificateAuthorizedKey{}, subject)
+		h.ServeHTTP(w, r.WithContext(ctx))
+	}))
+}
+
+// scope returns the scope of the certificates whose subject has the common
+// name [subject], reloading the scopes file if it was modified
+func (c *CertificateScopes) scope(subject string) (Scope, bool) {
+	c.reload()
+
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	scope, ok := c.scopes[subject]
+	return scope, ok
+}
+
+func (c *CertificateScopes) reload() {
+	changed, err := c.watcher.Changed()
+	if err != nil {
+		c.log.Warn("couldn't check the certificate scopes file for modifications: %s", err)
+		return
+	}
+	if !changed {
+		return
+	}
+
+	scopes, err := loadCertificateScopes(c.path)
+	if err != nil {
+		// Keep the previous scopes rather than rejecting every request
+		// because of a partially written file.
+		c.log.Error("couldn't reload the certificate scopes file: %s", err)
+		return
+	}
+	c.log.Info("reloaded the certificate scopes file")
+
+	c.lock.Lock()
+	c.scopes = scopes
+	c.lock.Unlock()
+}
+
+func loadCertificateScopes(path string) (map[string]Scope, error) {
+	scopesBytes, err := ioutil.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	scopes := map[string]Scope{}
+	if err := json.Unmarshal(scopesBytes, &scopes); err != nil {
+		return nil, fmt.Errorf("couldn't parse certificate scopes: %w", err)
+	}
+	for subject, scope := range scopes {
+		switch l := len(scope.Endpoints); {
+		case l == 0:
+			return nil, fmt.Errorf("scope of %q: %w", subject, errNoEndpoints)
+		case l > maxEndpoints:
+			return nil, fmt.Errorf("scope of %q: %w", subject, errTooManyEndpoints)
+		}
+		if len(scope.Methods) > maxMethods {
+			return nil, fmt.Errorf("scope of %q: %w", subject, errTooManyMethods)
+		}
+	}
+	return scopes, nil
+}
+
+// isCertificateAuthorized returns true if the client certificate of [r]
+// authorized the request
+func isCertificateAuthorized(r *http.Request) bool {
+	_, ok := r.Context().Value(certificateAuthorizedKey{}).(string)
+	return ok
+}
@@ -0,0 +1,172 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package auth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/flare-foundation/flare/database/memdb"
+	"github.com/flare-foundation/flare/utils/logging"
+)
+
+func writeScopesFile(t *testing.T, path, scopes string, modTime time.Time) {
+	assert.NoError(t, os.WriteFile(path, []byte(scopes), 0o600))
+	assert.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+func newCertificateRequest(url, body, subject string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	if subject != "" {
+		req.TLS = &tls.ConnectionState{
+			VerifiedChains: [][]*x509.Certificate{{
+				{Subject: pkix.Name{CommonName: subject}},
+			}},
+		}
+	}
+	return req
+}
+
+func TestCertificateScopes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scopes.json")
+	writeScopesFile(t, path, `{
+		"monitoring": {"endpoints": ["/ext/info", "/ext/health"]},
+		"wallet": {"endpoints": ["/ext/bc/X"], "methods": ["avm.getUTXOs"]}
+	}`, time.Now())
+
+	certificateScopes, err := NewCertificateScopes(logging.NoLog{}, path)
+	assert.NoError(t, err)
+	wrappedHandler := certificateScopes.WrapHandler(dummyHandler)
+
+	tests := []struct {
+		name    string
+		url     string
+		body    string
+		subject string
+		code    int
+	}{
+		{"allowed endpoint", "http://127.0.0.1:9650/ext/info", `{"method":"info.getNodeID"}`, "monitoring", http.StatusOK},
+		{"unauthorized endpoint", "http://127.0.0.1:9650/ext/admin", `{"method":"admin.stopCPUProfiler"}`, "monitoring", http.StatusUnauthorized},
+		{"allowed method", "http://127.0.0.1:9650/ext/bc/X", `{"method":"avm.getUTXOs"}`, "wallet", http.StatusOK},
+		{"unauthorized method", "http://127.0.0.1:9650/ext/bc/X", `{"method":"avm.export"}`, "wallet", http.StatusUnauthorized},
+		{"unknown subject", "http://127.0.0.1:9650/ext/info", `{"method":"info.getNodeID"}`, "unknown", http.StatusUnauthorized},
+		{"no certificate", "http://127.0.0.1:9650/ext/info", `{"method":"info.getNodeID"}`, "", http.StatusUnauthorized},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			wrappedHandler.ServeHTTP(rr, newCertificateRequest(test.url, test.body, test.subject))
+			assert.Equal(t, test.code, rr.Code)
+			if test.code == http.StatusUnauthorized {
+				assert.Regexp(t, unAuthorizedResponseRegex, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestCertificateScopesReload(t *testing.T) {
+	assert := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "scopes.json")
+	now := time.Now()
+	writeScopesFile(t, path, `{"monitoring": {"endpoints": ["/ext/info"]}}`, now)
+
+	certificateScopes, err := newCertificateScopes(logging.NoLog{}, path, 0)
+	assert.NoError(err)
+	wrappedHandler := certificateScopes.WrapHandler(dummyHandler)
+
+	serve := func() int {
+		rr := httptest.NewRecorder()
+		wrappedHandler.ServeHTTP(rr, newCertificateRequest("http://127.0.0.1:9650/ext/health", "", "monitoring"))
+		return rr.Code
+	}
+	assert.Equal(http.StatusUnauthorized, serve())
+
+	writeScopesFile(t, path, `{"monitoring": {"endpoints": ["/ext/info", "/ext/health"]}}`, now.Add(time.Minute))
+	assert.Equal(http.StatusOK, serve())
+
+	// An invalid file keeps the previous scopes
+	writeScopesFile(t, path, `{"monitoring": {"endpoints": []}}`, now.Add(2*time.Minute))
+	assert.Equal(http.StatusOK, serve())
+}
+
+func TestCertificateScopesInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scopes.json")
+
+	_, err := NewCertificateScopes(logging.NoLog{}, path)
+	assert.Error(t, err)
+
+	writeScopesFile(t, path, `{"monitoring": {"methods": ["info.*"]}}`, time.Now())
+	_, err = NewCertificateScopes(logging.NoLog{}, path)
+	assert.ErrorIs(t, err, errNoEndpoints)
+}
+
+func TestCertificateScopesReplaceToken(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scopes.json")
+	writeScopesFile(t, path, `{"monitoring": {"endpoints": ["/ext/info"]}}`, time.Now())
+
+	certificateScopes, err := NewCertificateScopes(logging.NoLog{}, path)
+	assert.NoError(t, err)
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword, memdb.New())
+	wrappedHandler := certificateScopes.WrapHandler(auth.WrapHandler(dummyHandler))
+
+	rr := httptest.NewRecorder()
+	wrappedHandler.ServeHTTP(rr, newCertificateRequest("http://127.0.0.1:9650/ext/info", "", "monitoring"))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	// Without the certificate layer, the request requires a token
+	rr = httptest.NewRecorder()
+	auth.WrapHandler(dummyHandler).ServeHTTP(rr, newCertificateRequest("http://127.0.0.1:9650/ext/info", "", "monitoring"))
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestCertificateScopesReadsBodyAfterAuthorization(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scopes.json")
+	writeScopesFile(t, path, `{
+		"monitoring": {"endpoints": ["/ext/info"]},
+		"wallet": {"endpoints": ["/ext/bc/X"], "methods": ["avm.getUTXOs"]}
+	}`, time.Now())
+
+	certificateScopes, err := NewCertificateScopes(logging.NoLog{}, path)
+	assert.NoError(t, err)
+	wrappedHandler := certificateScopes.WrapHandler(dummyHandler)
+
+	tests := []struct {
+		name     string
+		url      string
+		body     string
+		subject  string
+		code     int
+		bodyRead bool
+	}{
+		{"no certificate", "http://127.0.0.1:9650/ext/bc/X", `{"method":"avm.getUTXOs"}`, "", http.StatusUnauthorized, false},
+		{"unknown subject", "http://127.0.0.1:9650/ext/bc/X", `{"method":"avm.getUTXOs"}`, "unknown", http.StatusUnauthorized, false},
+		{"unauthorized endpoint", "http://127.0.0.1:9650/ext/bc/P", `{"method":"avm.getUTXOs"}`, "wallet", http.StatusUnauthorized, false},
+		{"unrestricted methods", "http://127.0.0.1:9650/ext/info", `{"method":"info.getNodeID"}`, "monitoring", http.StatusOK, false},
+		{"restricted methods", "http://127.0.0.1:9650/ext/bc/X", `{"method":"avm.getUTXOs"}`, "wallet", http.StatusOK, true},
+		{"too large", "http://127.0.0.1:9650/ext/bc/X", `{"method":"avm.getUTXOs","params":"` + strings.Repeat("a", maxRequestSize) + `"}`, "wallet", http.StatusUnauthorized, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := newCertificateRequest(test.url, "", test.subject)
+			body := &readRecorder{Reader: strings.NewReader(test.body)}
+			req.Body = io.NopCloser(body)
+			rr := httptest.NewRecorder()
+			wrappedHandler.ServeHTTP(rr, req)
+			assert.Equal(t, test.code, rr.Code)
+			assert.Equal(t, test.bodyRead, body.read)
+		})
+	}
+}
@@ -4,6 +4,9 @@
 package auth
 
 import (
+	"net/http"
+	"strings"
+
 	"github.com/golang-jwt/jwt"
 )
 
@@ -15,4 +18,80 @@ type endpointClaims struct {
 	// If endpoints has an element "*", allows access to all API endpoints
 	// In this case, "*" should be the only element of [endpoints]
 	Endpoints []string `json:"endpoints,omitempty"`
+
+	// Each element is a JSON-RPC method that the token allows calls to, such
+	// as "avm.getUTXOs", or "avm.*" to allow every method of a service
+	// If methods is empty, allows calls to all methods
+	Methods []string `json:"methods,omitempty"`
+}
+
+// scope returns the endpoints and methods that the claims allow access to
+func (c *endpointClaims) scope() Scope {
+	return Scope{
+		Endpoints: c.Endpoints,
+		Methods:   c.Methods,
+	}
+}
+
+// Scope is a set of API endpoints and JSON-RPC methods that a credential
+// allows access to
+type Scope struct {
+	// Each element is an endpoint that the credential allows access to
+	// If endpoints has an element "*", allows access to all API endpoints
+	Endpoints []string `json:"endpoints"`
+
+	// Each element is a JSON-RPC method that the credential allows calls to,
+	// such as "avm.getUTXOs", or "avm.*" to allow every method of a service
+	// If methods is empty, allows calls to all methods
+	Methods []string `json:"methods,omitempty"`
+}
+
+// allows returns true if the scope allows calls to [method] of the API at
+// [url]
+func (s *Scope) allows(url, method string) bool {
+	return s.allowsEndpoint(url) && s.allowsMethod(method)
+}
+
+// allowsRequest returns true if the scope allows the API call [r]. The body of
+// [r] is only read if the scope restricts the methods that may be called, so
+// that requests to other endpoints are rejected without reading their body.
+func (s *Scope) allowsRequest(r *http.Request) (bool, error) {
+	if !s.allowsEndpoint(r.URL.Path) {
+		return false, nil
+	}
+	if len(s.Methods) == 0 {
+		return true, nil
+	}
+	method, err := requestMethod(r)
+	if err != nil {
+		return false, err
+	}
+	return s.allowsMethod(method), nil
+}
+
+func (s *Scope) allowsEndpoint(url string) bool {
+	for _, endpoint := range s.Endpoints {
+		if endpoint == "*" || strings.HasSuffix(url, endpoint) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Scope) allowsMethod(method string) bool {
+	if len(s.Methods) == 0 {
+		return true
+	}
+	if method == "" {
+		return false
+	}
+	for _, allowed := range s.Methods {
+		if allowed == method {
+			return true
+		}
+		if service := strings.TrimSuffix(allowed, "*"); service != allowed && strings.HasSuffix(service, ".") && strings.HasPrefix(method, service) {
+			return true
+		}
+	}
+	return false
 }
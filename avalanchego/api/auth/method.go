@@ -0,0 +1,86 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+
+	"github.com/flare-foundation/flare/utils/units"
+)
+
+// Largest body of an API call whose JSON-RPC method is checked
+const maxRequestSize = 5 * units.MiB
+
+// methodKey is the context key of the body of a request whose JSON-RPC method
+// is parsed by [requestMethod]
+type methodKey struct{}
+
+// requestBody is the body of an API call whose JSON-RPC method is parsed at
+// most once. The copies of a request that the handlers pass on share it, so
+// that the body they read includes the bytes read to parse the method.
+type requestBody struct {
+	io.ReadCloser
+	w http.ResponseWriter
+
+	once   sync.Once
+	method string
+	err    error
+}
+
+func (b *requestBody) parse() {
+	body, err := ioutil.ReadAll(http.MaxBytesReader(b.w, b.ReadCloser, maxRequestSize))
+	if err != nil {
+		b.err = fmt.Errorf("couldn't read the API call: %w", err)
+		return
+	}
+	// The server closes the original body once the request is handled
+	b.ReadCloser = ioutil.NopCloser(bytes.NewReader(body))
+
+	call := struct {
+		Method string `json:"method"`
+	}{}
+	if err := json.Unmarshal(body, &call); err == nil {
+		b.method = call.Method
+	}
+}
+
+// parseMethod wraps [h] so that the JSON-RPC method of a request is parsed
+// once, the first time a handler calls [requestMethod], and shared with the
+// handlers the request is passed to. The body of a request is only read if its
+// method is needed. If the request already went through parseMethod, it's
+// passed to [h] unchanged.
+func parseMethod(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Body == nil || r.Context().Value(methodKey{}) != nil {
+			h.ServeHTTP(w, r)
+			return
+		}
+		body := &requestBody{
+			ReadCloser: r.Body,
+			w:          w,
+		}
+		r = r.WithContext(context.WithValue(r.Context(), methodKey{}, body))
+		r.Body = body
+		h.ServeHTTP(w, r)
+	})
+}
+
+// requestMethod returns the JSON-RPC method called by [r], or the empty string
+// if [r] isn't a JSON-RPC call. Bodies larger than [maxRequestSize] are
+// rejected.
+func requestMethod(r *http.Request) (string, error) {
+	body, ok := r.Context().Value(methodKey{}).(*requestBody)
+	if !ok {
+		return "", nil
+	}
+	body.once.Do(body.parse)
+	return body.method, body.err
+}
@@ -0,0 +1,71 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package auth
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// countReader counts the reads of the body of a request
+type countReader struct {
+	*strings.Reader
+	reads int
+}
+
+func (r *countReader) Read(p []byte) (int, error) {
+	r.reads++
+	return r.Reader.Read(p)
+}
+
+func TestParseMethodOnce(t *testing.T) {
+	assert := assert.New(t)
+
+	const call = `{"jsonrpc":"2.0","id":1,"method":"avm.getUTXOs","params":{}}`
+	var (
+		methods []string
+		body    []byte
+	)
+	checkMethod := func(h http.Handler) http.Handler {
+		return parseMethod(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			method, err := requestMethod(r)
+			assert.NoError(err)
+			methods = append(methods, method)
+			h.ServeHTTP(w, r)
+		}))
+	}
+	handler := checkMethod(checkMethod(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		body, err = ioutil.ReadAll(r.Body)
+		assert.NoError(err)
+	})))
+
+	reader := &countReader{Reader: strings.NewReader(call)}
+	req := httptest.NewRequest(http.MethodPost, "http://127.0.0.1:9650/ext/bc/X", reader)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	// Both handlers get the method, which is parsed by the first one only,
+	// and the wrapped handler still reads the whole body
+	assert.Equal([]string{"avm.getUTXOs", "avm.getUTXOs"}, methods)
+	readsToParse := reader.reads
+	assert.Equal(call, string(body))
+	assert.Equal(readsToParse, reader.reads)
+}
+
+func TestRequestMethodNotJSONRPC(t *testing.T) {
+	assert := assert.New(t)
+
+	handler := parseMethod(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, err := requestMethod(r)
+		assert.NoError(err)
+		assert.Empty(method)
+	}))
+	req := httptest.NewRequest(http.MethodGet, "http://127.0.0.1:9650/ext/metrics", strings.NewReader("not json"))
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+}
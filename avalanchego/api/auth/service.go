@@ -26,6 +26,12 @@ type NewTokenArgs struct {
 	// allows access to all API endpoints. [Endpoints] must have between 1 and
 	// [maxEndpoints] elements
 	Endpoints []string `json:"endpoints"`
+	// JSON-RPC methods that may be called with this token e.g. if methods is
+	// ["avm.getUTXOs", "info.*"] then the token holder can only call
+	// avm.getUTXOs and the methods of the info service. If [Methods] is empty
+	// then the token allows calls to all methods. [Methods] must have at most
+	// [maxMethods] elements
+	Methods []string `json:"methods"`
 }
 
 type Token struct {
@@ -36,20 +42,30 @@ func (s *Service) NewToken(_ *http.Request, args *NewTokenArgs, reply *Token) er
 	s.auth.log.Debug("Auth: NewToken called")
 
 	var err error
-	reply.Token, err = s.auth.NewToken(args.Password.Password, defaultTokenLifespan, args.Endpoints)
+	reply.Token, err = s.auth.NewToken(args.Password.Password, defaultTokenLifespan, args.Endpoints, args.Methods)
 	return err
 }
 
 type RevokeTokenArgs struct {
 	Password
 	Token
+	// ID of the token to revoke, as returned by ListTokens. If set, [Token]
+	// must be empty
+	ID string `json:"id"`
 }
 
 func (s *Service) RevokeToken(_ *http.Request, args *RevokeTokenArgs, reply *api.SuccessResponse) error {
 	s.auth.log.Debug("Auth: RevokeToken called")
 
 	reply.Success = true
-	return s.auth.RevokeToken(args.Token.Token, args.Password.Password)
+	switch {
+	case args.ID == "":
+		return s.auth.RevokeToken(args.Token.Token, args.Password.Password)
+	case args.Token.Token == "":
+		return s.auth.RevokeTokenByID(args.ID, args.Password.Password)
+	default:
+		return errTokenAndID
+	}
 }
 
 type ChangePasswordArgs struct {
@@ -63,3 +79,15 @@ func (s *Service) ChangePassword(_ *http.Request, args *ChangePasswordArgs, repl
 	reply.Success = true
 	return s.auth.ChangePassword(args.OldPassword, args.NewPassword)
 }
+
+type ListTokensReply struct {
+	Tokens []TokenInfo `json:"tokens"`
+}
+
+func (s *Service) ListTokens(_ *http.Request, args *Password, reply *ListTokensReply) error {
+	s.auth.log.Debug("Auth: ListTokens called")
+
+	var err error
+	reply.Tokens, err = s.auth.ListTokens(args.Password)
+	return err
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/flare-foundation/flare/ids"
 	"github.com/flare-foundation/flare/network"
+	"github.com/flare-foundation/flare/snow/networking/reputation"
 	"github.com/flare-foundation/flare/utils/rpc"
 )
 
@@ -23,9 +24,10 @@ type Client interface {
 	GetNetworkName(context.Context) (string, error)
 	GetBlockchainID(context.Context, string) (ids.ID, error)
 	Peers(context.Context) ([]network.PeerInfo, error)
+	PeerScores(context.Context) ([]reputation.PeerScore, error)
 	IsBootstrapped(context.Context, string) (bool, error)
 	GetTxFee(context.Context) (*GetTxFeeResponse, error)
-	Uptime(context.Context) (*UptimeResponse, error)
+	Uptime(context.Context, []string) (*UptimeResponse, error)
 }
 
 // Client implementation for an Info API Client
@@ -84,6 +86,12 @@ func (c *client) Peers(ctx context.Context) ([]network.PeerInfo, error) {
 	return res.Peers, err
 }
 
+func (c *client) PeerScores(ctx context.Context) ([]reputation.PeerScore, error) {
+	res := &PeerScoresReply{}
+	err := c.requester.SendRequest(ctx, "peerScores", struct{}{}, res)
+	return res.Scores, err
+}
+
 func (c *client) IsBootstrapped(ctx context.Context, chainID string) (bool, error) {
 	res := &IsBootstrappedResponse{}
 	err := c.requester.SendRequest(ctx, "isBootstrapped", &IsBootstrappedArgs{
@@ -98,8 +106,10 @@ func (c *client) GetTxFee(ctx context.Context) (*GetTxFeeResponse, error) {
 	return res, err
 }
 
-func (c *client) Uptime(ctx context.Context) (*UptimeResponse, error) {
+func (c *client) Uptime(ctx context.Context, nodeIDs []string) (*UptimeResponse, error) {
 	res := &UptimeResponse{}
-	err := c.requester.SendRequest(ctx, "uptime", struct{}{}, res)
+	err := c.requester.SendRequest(ctx, "uptime", &UptimeArgs{
+		NodeIDs: nodeIDs,
+	}, res)
 	return res, err
 }
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/rpc/v2"
 
@@ -14,6 +15,8 @@ import (
 	"github.com/flare-foundation/flare/ids"
 	"github.com/flare-foundation/flare/network"
 	"github.com/flare-foundation/flare/snow/engine/common"
+	"github.com/flare-foundation/flare/snow/networking/reputation"
+	"github.com/flare-foundation/flare/snow/uptime"
 	"github.com/flare-foundation/flare/snow/validators"
 	"github.com/flare-foundation/flare/utils/constants"
 	"github.com/flare-foundation/flare/utils/json"
@@ -27,6 +30,13 @@ var (
 	errNotValidator    = errors.New("this is not a validator node")
 )
 
+// Windows the uptimes of the validators are reported over
+const (
+	dayWindow   = 24 * time.Hour
+	weekWindow  = 7 * dayWindow
+	monthWindow = 30 * dayWindow
+)
+
 // Info is the API service for unprivileged info on a node
 type Info struct {
 	Parameters
@@ -36,6 +46,7 @@ type Info struct {
 	vmManager     vms.Manager
 	versionParser version.ApplicationParser
 	validators    validators.Set
+	uptimeTracker uptime.WindowTracker
 }
 
 type Parameters struct {
@@ -57,6 +68,7 @@ func NewService(
 	network network.Network,
 	versionParser version.ApplicationParser,
 	validators validators.Set,
+	uptimeTracker uptime.WindowTracker,
 ) (*common.HTTPHandler, error) {
 	newServer := rpc.NewServer()
 	codec := json.NewCodec()
@@ -70,6 +82,7 @@ func NewService(
 		networking:    network,
 		versionParser: versionParser,
 		validators:    validators,
+		uptimeTracker: uptimeTracker,
 	}, "info"); err != nil {
 		return nil, err
 	}
@@ -201,6 +214,38 @@ func (service *Info) Peers(_ *http.Request, args *PeersArgs, reply *PeersReply)
 	return nil
 }
 
+// PeerScoresArgs are the arguments for calling PeerScores
+type PeerScoresArgs struct {
+	NodeIDs []string `json:"nodeIDs"`
+}
+
+// PeerScoresReply are the results from calling PeerScores
+type PeerScoresReply struct {
+	// Number of elements in [Scores]
+	NumPeers json.Uint64 `json:"numPeers"`
+	// Reputation of each peer, banned peers and peers with the worst
+	// reputation first
+	Scores []reputation.PeerScore `json:"scores"`
+}
+
+// PeerScores returns the reputation of the given peers, or of every peer that
+// misbehaved recently if none are given
+func (service *Info) PeerScores(_ *http.Request, args *PeerScoresArgs, reply *PeerScoresReply) error {
+	service.log.Debug("Info: PeerScores called")
+	nodeIDs := make([]ids.ShortID, 0, len(args.NodeIDs))
+	for _, nodeID := range args.NodeIDs {
+		nID, err := ids.ShortFromPrefixedString(nodeID, constants.NodeIDPrefix)
+		if err != nil {
+			return err
+		}
+		nodeIDs = append(nodeIDs, nID)
+	}
+
+	reply.Scores = service.networking.Reputation().Scores(nodeIDs)
+	reply.NumPeers = json.Uint64(len(reply.Scores))
+	return nil
+}
+
 // IsBootstrappedArgs are the arguments for calling IsBootstrapped
 type IsBootstrappedArgs struct {
 	// Alias of the chain
@@ -230,6 +275,25 @@ func (service *Info) IsBootstrapped(_ *http.Request, args *IsBootstrappedArgs, r
 	return nil
 }
 
+// UptimeArgs are the arguments for calling Uptime
+type UptimeArgs struct {
+	// Validators to report the uptime of. If empty, every validator is
+	// reported.
+	NodeIDs []string `json:"nodeIDs"`
+}
+
+// ValidatorUptime is the uptime of a validator, as seen by this node
+type ValidatorUptime struct {
+	NodeID string `json:"nodeID"`
+	// True if the validator is currently connected to this node
+	Connected bool `json:"connected"`
+	// Percentage of the time this node was running during the last day, week
+	// and month that the validator was connected to it
+	DayPercentage   json.Float64 `json:"dayPercentage"`
+	WeekPercentage  json.Float64 `json:"weekPercentage"`
+	MonthPercentage json.Float64 `json:"monthPercentage"`
+}
+
 // UptimeResponse are the results from calling Uptime
 type UptimeResponse struct {
 	// RewardingStakePercentage shows what percent of network stake thinks we're
@@ -245,16 +309,65 @@ type UptimeResponse struct {
 	// counted (40*weight) in WeightedAveragePercentage but not in
 	// RewardingStakePercentage since 40 < 85
 	WeightedAveragePercentage json.Float64 `json:"weightedAveragePercentage"`
+
+	// Validators are the uptimes of the requested validators
+	Validators []ValidatorUptime `json:"validators"`
 }
 
-func (service *Info) Uptime(_ *http.Request, _ *struct{}, reply *UptimeResponse) error {
+// Uptime returns the uptime of the requested validators, or of every validator
+// if none are given. If this node is a validator, its own uptime, as seen by
+// the other validators, is also returned.
+func (service *Info) Uptime(_ *http.Request, args *UptimeArgs, reply *UptimeResponse) error {
 	service.log.Debug("Info: Uptime called")
 	result, isValidator := service.networking.NodeUptime()
-	if !isValidator {
+	if !isValidator && service.uptimeTracker == nil {
 		return errNotValidator
 	}
-	reply.WeightedAveragePercentage = json.Float64(result.WeightedAveragePercentage)
-	reply.RewardingStakePercentage = json.Float64(result.RewardingStakePercentage)
+	if isValidator {
+		reply.WeightedAveragePercentage = json.Float64(result.WeightedAveragePercentage)
+		reply.RewardingStakePercentage = json.Float64(result.RewardingStakePercentage)
+	}
+	if service.uptimeTracker == nil {
+		return nil
+	}
+
+	nodeIDs := make([]ids.ShortID, len(args.NodeIDs))
+	for i, nodeID := range args.NodeIDs {
+		nID, err := ids.ShortFromPrefixedString(nodeID, constants.NodeIDPrefix)
+		if err != nil {
+			return err
+		}
+		nodeIDs[i] = nID
+	}
+	if len(nodeIDs) == 0 {
+		nodeIDs = service.uptimeTracker.NodeIDs()
+		ids.SortShortIDs(nodeIDs)
+	}
+
+	reply.Validators = make([]ValidatorUptime, len(nodeIDs))
+	for i, nodeID := range nodeIDs {
+		vdrUptime := ValidatorUptime{
+			NodeID:    nodeID.PrefixedString(constants.NodeIDPrefix),
+			Connected: service.uptimeTracker.IsConnected(nodeID),
+		}
+		for _, window := range []struct {
+			duration time.Duration
+			percent  *json.Float64
+		}{
+			{duration: dayWindow, percent: &vdrUptime.DayPercentage},
+			{duration: weekWindow, percent: &vdrUptime.WeekPercentage},
+			{duration: monthWindow, percent: &vdrUptime.MonthPercentage},
+		} {
+			observed, connected, err := service.uptimeTracker.Uptime(nodeID, window.duration)
+			if err != nil {
+				return fmt.Errorf("couldn't get uptime of %s: %w", vdrUptime.NodeID, err)
+			}
+			if observed != 0 {
+				*window.percent = json.Float64(100 * float64(connected) / float64(observed))
+			}
+		}
+		reply.Validators[i] = vdrUptime
+	}
 	return nil
 }
 
@@ -20,6 +20,10 @@ type BlockchainKeystore interface {
 	// values. This Database will not perform any encrypting or decrypting of
 	// values and is not recommended to be used when implementing a VM.
 	GetRawDatabase(username, password string) (database.Database, error)
+
+	// GetSalt returns the salt that the key that encrypts the values of
+	// [username] is derived with.
+	GetSalt(username, password string) ([]byte, error)
 }
 
 type blockchainKeystore struct {
@@ -38,3 +42,9 @@ func (bks *blockchainKeystore) GetRawDatabase(username, password string) (databa
 
 	return bks.ks.GetRawDatabase(bks.blockchainID, username, password)
 }
+
+func (bks *blockchainKeystore) GetSalt(username, password string) ([]byte, error) {
+	bks.ks.log.Debug("Keystore: GetSalt called with %s from %s", username, bks.blockchainID)
+
+	return bks.ks.GetSalt(username, password)
+}
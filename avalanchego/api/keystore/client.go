@@ -25,6 +25,8 @@ type Client interface {
 	ImportUser(ctx context.Context, importTo api.UserPass, exportedUser []byte) (bool, error)
 	// Delete the given user
 	DeleteUser(context.Context, api.UserPass) (bool, error)
+	// Change the password of the given user to [newPassword]
+	RotatePassword(ctx context.Context, user api.UserPass, newPassword string) (bool, error)
 }
 
 // Client implementation for Avalanche Keystore API Endpoint
@@ -81,3 +83,12 @@ func (c *client) DeleteUser(ctx context.Context, user api.UserPass) (bool, error
 	err := c.requester.SendRequest(ctx, "deleteUser", &user, res)
 	return res.Success, err
 }
+
+func (c *client) RotatePassword(ctx context.Context, user api.UserPass, newPassword string) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest(ctx, "rotatePassword", &RotatePasswordArgs{
+		UserPass:    user,
+		NewPassword: newPassword,
+	}, res)
+	return res.Success, err
+}
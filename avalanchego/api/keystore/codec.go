@@ -7,6 +7,7 @@ import (
 	"github.com/flare-foundation/flare/codec"
 	"github.com/flare-foundation/flare/codec/linearcodec"
 	"github.com/flare-foundation/flare/codec/reflectcodec"
+	"github.com/flare-foundation/flare/database/encdb"
 	"github.com/flare-foundation/flare/utils/units"
 )
 
@@ -15,6 +16,11 @@ const (
 	maxSliceLength = 256 * 1024
 
 	codecVersion = 0
+
+	// exportVersion is the codec version of exported users. It's the format
+	// version of the encrypted values of the user, so that a node that can't
+	// decrypt the values refuses to import them.
+	exportVersion = encdb.CurrentVersion
 )
 
 var c codec.Manager
@@ -22,7 +28,10 @@ var c codec.Manager
 func init() {
 	lc := linearcodec.New(reflectcodec.DefaultTagName, maxSliceLength)
 	c = codec.NewManager(maxPackerSize)
-	if err := c.RegisterCodec(codecVersion, lc); err != nil {
-		panic(err)
+	// Exported users of every earlier format version can be imported
+	for version := uint16(codecVersion); version <= exportVersion; version++ {
+		if err := c.RegisterCodec(version, lc); err != nil {
+			panic(err)
+		}
 	}
 }
@@ -4,7 +4,9 @@
 package gkeystore
 
 import (
+	"bytes"
 	"context"
+	"sync"
 
 	"github.com/hashicorp/go-plugin"
 
@@ -22,6 +24,18 @@ var _ keystore.BlockchainKeystore = &Client{}
 type Client struct {
 	client gkeystoreproto.KeystoreClient
 	broker *plugin.GRPCBroker
+
+	// Key: username
+	// Value: The keys that encrypt that user's values. The password was
+	// checked by the keystore, and the salt changes with the password, so the
+	// keys are valid as long as the salt is the same.
+	keysLock sync.Mutex
+	keys     map[string]saltedKeys
+}
+
+type saltedKeys struct {
+	salt []byte
+	keys *encdb.Keys
 }
 
 // NewClient returns a keystore instance connected to a remote keystore instance
@@ -29,31 +43,62 @@ func NewClient(client gkeystoreproto.KeystoreClient, broker *plugin.GRPCBroker)
 	return &Client{
 		client: client,
 		broker: broker,
+		keys:   make(map[string]saltedKeys),
 	}
 }
 
 func (c *Client) GetDatabase(username, password string) (*encdb.Database, error) {
-	bcDB, err := c.GetRawDatabase(username, password)
+	bcDB, salt, err := c.getDatabase(username, password)
 	if err != nil {
 		return nil, err
 	}
-	return encdb.New([]byte(password), bcDB)
+
+	c.keysLock.Lock()
+	defer c.keysLock.Unlock()
+
+	cached, ok := c.keys[username]
+	if !ok || !bytes.Equal(cached.salt, salt) {
+		keys, err := encdb.DeriveKeys([]byte(password), salt)
+		if err != nil {
+			return nil, err
+		}
+		cached = saltedKeys{salt: salt, keys: keys}
+		c.keys[username] = cached
+	}
+	return encdb.NewWithKeys(cached.keys, bcDB)
 }
 
 func (c *Client) GetRawDatabase(username, password string) (database.Database, error) {
+	bcDB, _, err := c.getDatabase(username, password)
+	return bcDB, err
+}
+
+// GetSalt serves the database of the user only to learn the salt, so the
+// database is closed immediately.
+func (c *Client) GetSalt(username, password string) ([]byte, error) {
+	bcDB, salt, err := c.getDatabase(username, password)
+	if err != nil {
+		return nil, err
+	}
+	return salt, bcDB.Close()
+}
+
+// getDatabase returns the raw database of the user and the salt that the key
+// that encrypts its values is derived with
+func (c *Client) getDatabase(username, password string) (database.Database, []byte, error) {
 	resp, err := c.client.GetDatabase(context.Background(), &gkeystoreproto.GetDatabaseRequest{
 		Username: username,
 		Password: password,
 	})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	dbConn, err := c.broker.Dial(resp.DbServer)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	dbClient := rpcdb.NewClient(rpcdbproto.NewDatabaseClient(dbConn))
-	return dbClient, err
+	return dbClient, resp.Salt, err
 }
@@ -44,6 +44,10 @@ func (s *Server) GetDatabase(
 	if err != nil {
 		return nil, err
 	}
+	salt, err := s.ks.GetSalt(req.Username, req.Password)
+	if err != nil {
+		return nil, err
+	}
 
 	closer := dbCloser{Database: db}
 
@@ -60,7 +64,10 @@ func (s *Server) GetDatabase(
 		rpcdbproto.RegisterDatabaseServer(server, db)
 		return server
 	})
-	return &gkeystoreproto.GetDatabaseResponse{DbServer: dbBrokerID}, nil
+	return &gkeystoreproto.GetDatabaseResponse{
+		DbServer: dbBrokerID,
+		Salt:     salt,
+	}, nil
 }
 
 type dbCloser struct {
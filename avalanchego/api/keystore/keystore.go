@@ -17,6 +17,7 @@ import (
 	"github.com/flare-foundation/flare/database/manager"
 	"github.com/flare-foundation/flare/database/prefixdb"
 	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/utils/hashing"
 	"github.com/flare-foundation/flare/utils/logging"
 	"github.com/flare-foundation/flare/utils/password"
 
@@ -26,6 +27,10 @@ import (
 const (
 	// maxUserLen is the maximum allowed length of a username
 	maxUserLen = 1024
+
+	// saltPrefix separates the salt that a user's encryption key is derived
+	// with from the salt of the user's password hash
+	saltPrefix = "encdb"
 )
 
 var (
@@ -52,9 +57,14 @@ type Keystore interface {
 
 	// Get the underlying database that is able to read and write encrypted
 	// values. This Database will not perform any encrypting or decrypting of
-	// values and is not recommended to be used when implementing a VM.
+	// values and is not recommended to be used when implementing a VM. Like
+	// GetDatabase, it re-encrypts the user's values of the legacy format.
 	GetRawDatabase(bID ids.ID, username, password string) (database.Database, error)
 
+	// GetSalt returns the salt that the key that encrypts the values of
+	// [username] is derived with.
+	GetSalt(username, password string) ([]byte, error)
+
 	// CreateUser attempts to register this username and password as a new user
 	// of the keystore.
 	CreateUser(username, pw string) error
@@ -74,6 +84,10 @@ type Keystore interface {
 	// with encrypted database values.
 	ExportUser(username, pw string) ([]byte, error)
 
+	// RotatePassword changes the password of [username] to [newPW] and
+	// atomically re-encrypts all of the user's database values with it.
+	RotatePassword(username, pw, newPW string) error
+
 	// Get the password that is used by [username]. If [username] doesn't exist,
 	// no error is returned and a nil password hash is returned.
 	getPassword(username string) (*password.Hash, error)
@@ -99,6 +113,11 @@ type keystore struct {
 	// Value: The hash of that user's password
 	usernameToPassword map[string]*password.Hash
 
+	// Key: username
+	// Value: The keys that encrypt that user's values. Deriving them is
+	// expensive, so they are only derived once per password.
+	usernameToKeys map[string]*encdb.Keys
+
 	// Used to persist users and their data
 	userDB database.Database
 	bcDB   database.Database
@@ -116,6 +135,7 @@ func New(log logging.Logger, dbManager manager.Manager) Keystore {
 	return &keystore{
 		log:                log,
 		usernameToPassword: make(map[string]*password.Hash),
+		usernameToKeys:     make(map[string]*encdb.Keys),
 		userDB:             prefixdb.New(usersPrefix, currentDB.Database),
 		bcDB:               prefixdb.New(bcsPrefix, currentDB.Database),
 	}
@@ -139,12 +159,27 @@ func (ks *keystore) NewBlockchainKeyStore(blockchainID ids.ID) BlockchainKeystor
 	}
 }
 
-func (ks *keystore) GetDatabase(bID ids.ID, username, password string) (*encdb.Database, error) {
-	bcDB, err := ks.GetRawDatabase(bID, username, password)
+func (ks *keystore) GetDatabase(bID ids.ID, username, pw string) (*encdb.Database, error) {
+	if username == "" {
+		return nil, errEmptyUsername
+	}
+
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+
+	passwordHash, err := ks.checkPassword(username, pw)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := ks.getKeys(username, pw, passwordHash)
 	if err != nil {
 		return nil, err
 	}
-	return encdb.New([]byte(password), bcDB)
+
+	userDB := prefixdb.New([]byte(username), ks.bcDB)
+	bcDB := prefixdb.NewNested(bID[:], userDB)
+	return encdb.NewWithKeys(keys, bcDB)
 }
 
 func (ks *keystore) GetRawDatabase(bID ids.ID, username, pw string) (database.Database, error) {
@@ -155,12 +190,15 @@ func (ks *keystore) GetRawDatabase(bID ids.ID, username, pw string) (database.Da
 	ks.lock.Lock()
 	defer ks.lock.Unlock()
 
-	passwordHash, err := ks.getPassword(username)
+	passwordHash, err := ks.checkPassword(username, pw)
 	if err != nil {
 		return nil, err
 	}
-	if passwordHash == nil || !passwordHash.Check(pw) {
-		return nil, fmt.Errorf("incorrect password for user %q", username)
+
+	// The values of plugin VMs are decrypted by the plugin, so the legacy
+	// values are re-encrypted here before the plugin reads them
+	if _, err := ks.getKeys(username, pw, passwordHash); err != nil {
+		return nil, err
 	}
 
 	userDB := prefixdb.New([]byte(username), ks.bcDB)
@@ -168,6 +206,21 @@ func (ks *keystore) GetRawDatabase(bID ids.ID, username, pw string) (database.Da
 	return bcDB, nil
 }
 
+func (ks *keystore) GetSalt(username, pw string) ([]byte, error) {
+	if username == "" {
+		return nil, errEmptyUsername
+	}
+
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+
+	passwordHash, err := ks.checkPassword(username, pw)
+	if err != nil {
+		return nil, err
+	}
+	return encryptionSalt(passwordHash), nil
+}
+
 func (ks *keystore) CreateUser(username, pw string) error {
 	if username == "" {
 		return errEmptyUsername
@@ -259,6 +312,7 @@ func (ks *keystore) DeleteUser(username, pw string) error {
 
 	// delete from users map.
 	delete(ks.usernameToPassword, username)
+	delete(ks.usernameToKeys, username)
 	return nil
 }
 
@@ -339,13 +393,10 @@ func (ks *keystore) ExportUser(username, pw string) ([]byte, error) {
 	ks.lock.Lock()
 	defer ks.lock.Unlock()
 
-	passwordHash, err := ks.getPassword(username)
+	passwordHash, err := ks.checkPassword(username, pw)
 	if err != nil {
 		return nil, err
 	}
-	if passwordHash == nil || !passwordHash.Check(pw) {
-		return nil, fmt.Errorf("incorrect password for user %q", username)
-	}
 
 	userDB := prefixdb.New([]byte(username), ks.bcDB)
 
@@ -363,7 +414,144 @@ func (ks *keystore) ExportUser(username, pw string) ([]byte, error) {
 	}
 
 	// Return the byte representation of the user
-	return c.Marshal(codecVersion, &userData)
+	return c.Marshal(exportVersion, &userData)
+}
+
+func (ks *keystore) RotatePassword(username, pw, newPW string) error {
+	if username == "" {
+		return errEmptyUsername
+	}
+	if len(username) > maxUserLen {
+		return errUserMaxLength
+	}
+
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+
+	passwordHash, err := ks.checkPassword(username, pw)
+	if err != nil {
+		return err
+	}
+
+	if err := password.IsValid(newPW, password.OK); err != nil {
+		return err
+	}
+
+	newPasswordHash := &password.Hash{}
+	if err := newPasswordHash.Set(newPW); err != nil {
+		return err
+	}
+
+	passwordBytes, err := c.Marshal(codecVersion, newPasswordHash)
+	if err != nil {
+		return err
+	}
+
+	userBatch := ks.userDB.NewBatch()
+	if err := userBatch.Put([]byte(username), passwordBytes); err != nil {
+		return err
+	}
+
+	// The values of all of the user's blockchains are decrypted with the old
+	// password and encrypted with the new one
+	userDataDB := prefixdb.New([]byte(username), ks.bcDB)
+	oldKeys, err := ks.getKeys(username, pw, passwordHash)
+	if err != nil {
+		return err
+	}
+	oldDB, err := encdb.NewWithKeys(oldKeys, userDataDB)
+	if err != nil {
+		return err
+	}
+	newKeys, err := encdb.DeriveKeys([]byte(newPW), encryptionSalt(newPasswordHash))
+	if err != nil {
+		return err
+	}
+	newDB, err := encdb.NewWithKeys(newKeys, userDataDB)
+	if err != nil {
+		return err
+	}
+	dataBatch := newDB.NewBatch()
+
+	it := oldDB.NewIterator()
+	defer it.Release()
+
+	for it.Next() {
+		if err := dataBatch.Put(it.Key(), it.Value()); err != nil {
+			return err
+		}
+	}
+
+	if err := it.Error(); err != nil {
+		return err
+	}
+
+	if err := atomic.WriteAll(dataBatch, userBatch); err != nil {
+		return err
+	}
+	ks.usernameToPassword[username] = newPasswordHash
+	ks.usernameToKeys[username] = newKeys
+	return nil
+}
+
+// getKeys returns the keys that encrypt the values of [username], whose
+// password is [pw] and [passwordHash]. The first time the keys are derived,
+// the user's values of the legacy format are re-encrypted with them.
+//
+// The password is only known when a user opens a database or rotates their
+// password, so values of the legacy format are kept as they are until then,
+// and still encrypted with the password hash that's stored next to them. The
+// keystore never drops them, so an operator that wants none left must have
+// every user either open a database or call keystore.rotatePassword, or
+// delete the users that are no longer used.
+// Assumes [ks.lock] is held and [pw] was checked.
+func (ks *keystore) getKeys(username, pw string, passwordHash *password.Hash) (*encdb.Keys, error) {
+	if keys, exists := ks.usernameToKeys[username]; exists {
+		return keys, nil
+	}
+
+	keys, err := encdb.DeriveKeys([]byte(pw), encryptionSalt(passwordHash))
+	if err != nil {
+		return nil, err
+	}
+	userDataDB, err := encdb.NewWithKeys(keys, prefixdb.New([]byte(username), ks.bcDB))
+	if err != nil {
+		return nil, err
+	}
+	migrated, err := userDataDB.MigrateLegacy()
+	if err != nil {
+		return nil, fmt.Errorf("couldn't re-encrypt the values of user %q: %w", username, err)
+	}
+	if migrated > 0 {
+		ks.log.Info("re-encrypted %d values of user %q in the current format", migrated, username)
+	}
+	ks.usernameToKeys[username] = keys
+	return keys, nil
+}
+
+// checkPassword returns the password hash of [username] if [pw] is the user's
+// password.
+// Assumes [ks.lock] is held.
+func (ks *keystore) checkPassword(username, pw string) (*password.Hash, error) {
+	passwordHash, err := ks.getPassword(username)
+	if err != nil {
+		return nil, err
+	}
+	if passwordHash == nil || !passwordHash.Check(pw) {
+		return nil, fmt.Errorf("incorrect password for user %q", username)
+	}
+	return passwordHash, nil
+}
+
+// encryptionSalt returns the salt that the encryption key of a user's values is
+// derived with. It's bound to the salt of the user's password hash, so it's
+// unique per user, changes with the password and is carried by exported users.
+func encryptionSalt(passwordHash *password.Hash) []byte {
+	saltBytes := make([]byte, 0, len(saltPrefix)+len(passwordHash.Salt))
+	saltBytes = append(saltBytes, saltPrefix...)
+	saltBytes = append(saltBytes, passwordHash.Salt[:]...)
+	salt := hashing.ComputeHash256(saltBytes)
+	return salt[:encdb.SaltLen]
 }
 
 func (ks *keystore) getPassword(username string) (*password.Hash, error) {
@@ -98,6 +98,20 @@ func (s *service) ExportUser(_ *http.Request, args *ExportUserArgs, reply *Expor
 	return nil
 }
 
+type RotatePasswordArgs struct {
+	// The username and current password of the user
+	api.UserPass
+	// The password the user's data is re-encrypted with
+	NewPassword string `json:"newPassword"`
+}
+
+func (s *service) RotatePassword(_ *http.Request, args *RotatePasswordArgs, reply *api.SuccessResponse) error {
+	s.ks.log.Debug("Keystore: RotatePassword called for %s", args.Username)
+
+	reply.Success = true
+	return s.ks.RotatePassword(args.Username, args.Password, args.NewPassword)
+}
+
 // CreateTestKeystore returns a new keystore that can be utilized for testing
 func CreateTestKeystore() (Keystore, error) {
 	dbManager, err := manager.NewManagerFromDBs([]*manager.VersionedDatabase{
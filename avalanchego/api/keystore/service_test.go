@@ -10,9 +10,17 @@ import (
 	"reflect"
 	"testing"
 
+	"golang.org/x/crypto/chacha20poly1305"
+
 	"github.com/flare-foundation/flare/api"
+	"github.com/flare-foundation/flare/codec"
+	"github.com/flare-foundation/flare/codec/linearcodec"
+	"github.com/flare-foundation/flare/database"
+	"github.com/flare-foundation/flare/database/encdb"
+	"github.com/flare-foundation/flare/database/prefixdb"
 	"github.com/flare-foundation/flare/ids"
 	"github.com/flare-foundation/flare/utils/formatting"
+	"github.com/flare-foundation/flare/utils/hashing"
 )
 
 // strongPassword defines a password used for the following tests that
@@ -339,6 +347,288 @@ func TestServiceExportImport(t *testing.T) {
 	}
 }
 
+func TestServiceImportLegacyUser(t *testing.T) {
+	ks, err := CreateTestKeystore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := service{ks: ks.(*keystore)}
+
+	{
+		reply := api.SuccessResponse{}
+		if err := s.CreateUser(nil, &api.UserPass{
+			Username: "bob",
+			Password: strongPassword,
+		}, &reply); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Write a value in the legacy encryption format
+	writeLegacyValue(t, ks, "bob", strongPassword, []byte("hello"), []byte("world"))
+
+	userBytes, err := ks.ExportUser("bob", strongPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Re-encode the user the way it was exported before the format version
+	// was carried
+	userData := user{}
+	if version, err := c.Unmarshal(userBytes, &userData); err != nil {
+		t.Fatal(err)
+	} else if version != exportVersion {
+		t.Fatalf("user was exported with version %d ; Expected: %d", version, exportVersion)
+	}
+	legacyUserBytes, err := c.Marshal(codecVersion, &userData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newKS, err := CreateTestKeystore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := newKS.ImportUser("bob", strongPassword, legacyUserBytes); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := newKS.GetDatabase(ids.Empty, "bob", strongPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if val, err := db.Get([]byte("hello")); err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(val, []byte("world")) {
+		t.Fatalf("Should have read '%s' from the db", "world")
+	}
+}
+
+func TestServiceRotatePassword(t *testing.T) {
+	newPassword := strongPassword + "new"
+
+	ks, err := CreateTestKeystore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := service{ks: ks.(*keystore)}
+
+	{
+		reply := api.SuccessResponse{}
+		if err := s.CreateUser(nil, &api.UserPass{
+			Username: "bob",
+			Password: strongPassword,
+		}, &reply); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		db, err := ks.GetDatabase(ids.Empty, "bob", strongPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := db.Put([]byte("hello"), []byte("world")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeLegacyValue(t, ks, "bob", strongPassword, []byte("legacy"), []byte("value"))
+
+	{
+		reply := api.SuccessResponse{}
+		if err := s.RotatePassword(nil, &RotatePasswordArgs{
+			UserPass: api.UserPass{
+				Username: "bob",
+				Password: newPassword,
+			},
+			NewPassword: newPassword,
+		}, &reply); err == nil {
+			t.Fatal("Should have errored due to incorrect password")
+		}
+	}
+
+	{
+		reply := api.SuccessResponse{}
+		if err := s.RotatePassword(nil, &RotatePasswordArgs{
+			UserPass: api.UserPass{
+				Username: "bob",
+				Password: strongPassword,
+			},
+			NewPassword: "weak",
+		}, &reply); err == nil {
+			t.Fatal("Should have errored due to weak password")
+		}
+	}
+
+	{
+		reply := api.SuccessResponse{}
+		if err := s.RotatePassword(nil, &RotatePasswordArgs{
+			UserPass: api.UserPass{
+				Username: "bob",
+				Password: strongPassword,
+			},
+			NewPassword: newPassword,
+		}, &reply); err != nil {
+			t.Fatal(err)
+		}
+		if !reply.Success {
+			t.Fatalf("Password should have been rotated successfully")
+		}
+	}
+
+	if _, err := ks.GetDatabase(ids.Empty, "bob", strongPassword); err == nil {
+		t.Fatal("Should have errored due to the rotated password")
+	}
+
+	db, err := ks.GetDatabase(ids.Empty, "bob", newPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if val, err := db.Get([]byte("hello")); err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(val, []byte("world")) {
+		t.Fatalf("Should have read '%s' from the db", "world")
+	}
+	if val, err := db.Get([]byte("legacy")); err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(val, []byte("value")) {
+		t.Fatalf("Should have read '%s' from the db", "value")
+	}
+
+	// Every value is re-encrypted in the current format
+	assertCurrentFormat(t, ks, "bob")
+}
+
+func TestServiceMigrateLegacyValues(t *testing.T) {
+	ks, err := CreateTestKeystore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ks.CreateUser("bob", strongPassword); err != nil {
+		t.Fatal(err)
+	}
+	writeLegacyValue(t, ks, "bob", strongPassword, []byte("hello"), []byte("world"))
+
+	// The legacy values are re-encrypted when the user's database is opened
+	db, err := ks.GetDatabase(ids.Empty, "bob", strongPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertCurrentFormat(t, ks, "bob")
+	if val, err := db.Get([]byte("hello")); err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(val, []byte("world")) {
+		t.Fatalf("Should have read '%s' from the db", "world")
+	}
+
+	// The keys are derived once
+	keys := ks.(*keystore).usernameToKeys["bob"]
+	if keys == nil {
+		t.Fatal("Should have cached the keys of the user")
+	}
+	if _, err := ks.GetDatabase(ids.Empty, "bob", strongPassword); err != nil {
+		t.Fatal(err)
+	}
+	if ks.(*keystore).usernameToKeys["bob"] != keys {
+		t.Fatal("Should have reused the keys of the user")
+	}
+	if _, err := ks.GetDatabase(ids.Empty, "bob", strongPassword+"wrong"); err == nil {
+		t.Fatal("Should have errored due to incorrect password")
+	}
+}
+
+func TestServiceMigrateLegacyValuesRawDatabase(t *testing.T) {
+	ks, err := CreateTestKeystore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ks.CreateUser("bob", strongPassword); err != nil {
+		t.Fatal(err)
+	}
+	writeLegacyValue(t, ks, "bob", strongPassword, []byte("hello"), []byte("world"))
+
+	// Plugin VMs decrypt the values of the raw database themselves, so the
+	// legacy values are re-encrypted before the raw database is returned
+	if _, err := ks.GetRawDatabase(ids.Empty, "bob", strongPassword+"wrong"); err == nil {
+		t.Fatal("Should have errored due to incorrect password")
+	}
+	if _, err := ks.GetRawDatabase(ids.Empty, "bob", strongPassword); err != nil {
+		t.Fatal(err)
+	}
+	assertCurrentFormat(t, ks, "bob")
+
+	db, err := ks.GetDatabase(ids.Empty, "bob", strongPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if val, err := db.Get([]byte("hello")); err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(val, []byte("world")) {
+		t.Fatalf("Should have read '%s' from the db", "world")
+	}
+}
+
+// assertCurrentFormat checks that every value of [username] is encrypted in
+// the current format
+func assertCurrentFormat(t *testing.T, ks Keystore, username string) {
+	it := rawUserDB(ks, username).NewIterator()
+	defer it.Release()
+	for it.Next() {
+		if version, err := encdbCodec.Unmarshal(it.Value(), &encryptedValue{}); err != nil {
+			t.Fatal(err)
+		} else if version != encdb.CurrentVersion {
+			t.Fatalf("value was encrypted with version %d ; Expected: %d", version, encdb.CurrentVersion)
+		}
+	}
+	if err := it.Error(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// encryptedValue mirrors the serialization of the values of encdb
+type encryptedValue struct {
+	Ciphertext []byte `serialize:"true"`
+	Nonce      []byte `serialize:"true"`
+}
+
+var encdbCodec codec.Manager
+
+func init() {
+	encdbCodec = codec.NewDefaultManager()
+	lc := linearcodec.NewDefault()
+	for _, version := range []uint16{encdb.LegacyVersion, encdb.CurrentVersion} {
+		if err := encdbCodec.RegisterCodec(version, lc); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// writeLegacyValue writes [key] to the database of [username] encrypted with
+// the hash of the password, the way encdb used to encrypt values
+func writeLegacyValue(t *testing.T, ks Keystore, username, pw string, key, value []byte) {
+	aead, err := chacha20poly1305.NewX(hashing.ComputeHash256([]byte(pw)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonce := make([]byte, chacha20poly1305.NonceSizeX)
+	encValue, err := encdbCodec.Marshal(encdb.LegacyVersion, &encryptedValue{
+		Ciphertext: aead.Seal(nil, nonce, value, nil),
+		Nonce:      nonce,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rawUserDB(ks, username).Put(key, encValue); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// rawUserDB returns the encrypted values of [username], without deriving the
+// user's keys
+func rawUserDB(ks Keystore, username string) database.Database {
+	return prefixdb.NewNested(ids.Empty[:], prefixdb.New([]byte(username), ks.(*keystore).bcDB))
+}
+
 func TestServiceDeleteUser(t *testing.T) {
 	testUser := "testUser"
 	password := "passwTest@fake01ord"
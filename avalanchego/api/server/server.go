@@ -118,16 +118,18 @@ func (s *Server) Dispatch() error {
 	return s.srv.Serve(listener)
 }
 
-// DispatchTLS starts the API server with the provided TLS certificate
-func (s *Server) DispatchTLS(certBytes, keyBytes []byte) error {
+// DispatchTLS starts the API server with the provided TLS material. If
+// [tlsConfig] names a client CA file, clients must authenticate with a
+// certificate signed by one of the CAs.
+func (s *Server) DispatchTLS(tlsConfig TLSConfig) error {
 	listenAddress := fmt.Sprintf("%s:%d", s.listenHost, s.listenPort)
-	cert, err := tls.X509KeyPair(certBytes, keyBytes)
+	loader, err := newTLSLoader(s.log, tlsConfig, tlsReloadInterval)
 	if err != nil {
 		return err
 	}
 	config := &tls.Config{
-		MinVersion:   tls.VersionTLS12,
-		Certificates: []tls.Certificate{cert},
+		MinVersion:         tls.VersionTLS12,
+		GetConfigForClient: loader.GetConfigForClient,
 	}
 
 	listener, err := tls.Listen("tcp", listenAddress, config)
@@ -0,0 +1,142 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/flare-foundation/flare/utils/filewatch"
+	"github.com/flare-foundation/flare/utils/logging"
+)
+
+// tlsReloadInterval is how often the TLS files are checked for modifications
+const tlsReloadInterval = 5 * time.Second
+
+var errNoClientCAs = errors.New("client CA file doesn't contain any certificates")
+
+// TLSConfig specifies the TLS material of the API server
+type TLSConfig struct {
+	// PEM encoded certificate and private key of the server. Ignored if
+	// [CertFile] and [KeyFile] are specified.
+	Cert []byte
+	Key  []byte
+
+	// Files holding the PEM encoded certificate and private key of the
+	// server. The files are reloaded when they are modified.
+	CertFile string
+	KeyFile  string
+
+	// File holding the PEM encoded certificates of the CAs that sign client
+	// certificates. If specified, clients must present a certificate signed
+	// by one of these CAs. The file is reloaded when it is modified.
+	ClientCAFile string
+}
+
+// tlsLoader returns the TLS config of the API server, reloading the TLS files
+// when they are modified
+type tlsLoader struct {
+	log     logging.Logger
+	config  TLSConfig
+	watcher *filewatch.Watcher
+
+	lock    sync.Mutex
+	current *tls.Config
+}
+
+// newTLSLoader returns a loader of the TLS material in [config] that checks
+// the TLS files for modifications at most once every [reloadInterval]
+func newTLSLoader(log logging.Logger, config TLSConfig, reloadInterval time.Duration) (*tlsLoader, error) {
+	l := &tlsLoader{
+		log:    log,
+		config: config,
+	}
+
+	var paths []string
+	if config.CertFile != "" && config.KeyFile != "" {
+		paths = append(paths, config.CertFile, config.KeyFile)
+	}
+	if config.ClientCAFile != "" {
+		paths = append(paths, config.ClientCAFile)
+	}
+	watcher, err := filewatch.New(reloadInterval, paths...)
+	if err != nil {
+		return nil, err
+	}
+	l.watcher = watcher
+
+	l.current, err = l.load()
+	return l, err
+}
+
+// GetConfigForClient returns the current TLS config. It can be used as
+// [tls.Config.GetConfigForClient].
+func (l *tlsLoader) GetConfigForClient(*tls.ClientHelloInfo) (*tls.Config, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	changed, err := l.watcher.Changed()
+	if err != nil {
+		l.log.Warn("couldn't check the API server's TLS files for modifications: %s", err)
+		return l.current, nil
+	}
+	if !changed {
+		return l.current, nil
+	}
+
+	config, err := l.load()
+	if err != nil {
+		// Keep serving with the previous TLS material rather than failing
+		// every handshake because of a partially written file.
+		l.log.Error("couldn't reload the API server's TLS files: %s", err)
+		return l.current, nil
+	}
+	l.log.Info("reloaded the API server's TLS files")
+	l.current = config
+	return l.current, nil
+}
+
+func (l *tlsLoader) load() (*tls.Config, error) {
+	certBytes, keyBytes := l.config.Cert, l.config.Key
+	if l.config.CertFile != "" && l.config.KeyFile != "" {
+		var err error
+		certBytes, err = ioutil.ReadFile(filepath.Clean(l.config.CertFile))
+		if err != nil {
+			return nil, err
+		}
+		keyBytes, err = ioutil.ReadFile(filepath.Clean(l.config.KeyFile))
+		if err != nil {
+			return nil, err
+		}
+	}
+	cert, err := tls.X509KeyPair(certBytes, keyBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+	}
+	if l.config.ClientCAFile == "" {
+		return config, nil
+	}
+
+	caBytes, err := ioutil.ReadFile(filepath.Clean(l.config.ClientCAFile))
+	if err != nil {
+		return nil, err
+	}
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(caBytes) {
+		return nil, errNoClientCAs
+	}
+	config.ClientCAs = clientCAs
+	config.ClientAuth = tls.RequireAndVerifyClientCert
+	return config, nil
+}
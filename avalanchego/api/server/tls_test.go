@@ -0,0 +1,191 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/flare-foundation/flare/utils/logging"
+)
+
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+// newTestCert returns a certificate with the common name [name] signed by
+// [parent], or a self-signed CA certificate if [parent] is nil
+func newTestCert(t *testing.T, name string, parent *testCert) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+	}
+	signer, signerKey := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+	certBytes, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(certBytes)
+	assert.NoError(t, err)
+	keyBytes, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+
+	return &testCert{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certBytes}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyBytes}),
+	}
+}
+
+// writeFile writes [data] to [path] and bumps its modification time so that
+// the modification is detected
+func writeFile(t *testing.T, path string, data []byte, modTime time.Time) {
+	assert.NoError(t, os.WriteFile(path, data, 0o600))
+	assert.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+func TestTLSLoaderReload(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+
+	cert1 := newTestCert(t, "server1", nil)
+	now := time.Now()
+	writeFile(t, certFile, cert1.certPEM, now)
+	writeFile(t, keyFile, cert1.keyPEM, now)
+
+	loader, err := newTLSLoader(logging.NoLog{}, TLSConfig{
+		CertFile: certFile,
+		KeyFile:  keyFile,
+	}, 0)
+	assert.NoError(err)
+
+	config, err := loader.GetConfigForClient(nil)
+	assert.NoError(err)
+	assert.Equal(cert1.cert.Raw, config.Certificates[0].Certificate[0])
+	assert.Equal(tls.NoClientCert, config.ClientAuth)
+
+	// A partially written file keeps the previous certificate
+	writeFile(t, certFile, []byte("invalid"), now.Add(time.Minute))
+	config, err = loader.GetConfigForClient(nil)
+	assert.NoError(err)
+	assert.Equal(cert1.cert.Raw, config.Certificates[0].Certificate[0])
+
+	cert2 := newTestCert(t, "server2", nil)
+	writeFile(t, certFile, cert2.certPEM, now.Add(2*time.Minute))
+	writeFile(t, keyFile, cert2.keyPEM, now.Add(2*time.Minute))
+	config, err = loader.GetConfigForClient(nil)
+	assert.NoError(err)
+	assert.Equal(cert2.cert.Raw, config.Certificates[0].Certificate[0])
+}
+
+func TestTLSLoaderInvalidClientCA(t *testing.T) {
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	writeFile(t, caFile, []byte("not a certificate"), time.Now())
+
+	serverCert := newTestCert(t, "server", nil)
+	_, err := newTLSLoader(logging.NoLog{}, TLSConfig{
+		Cert:         serverCert.certPEM,
+		Key:          serverCert.keyPEM,
+		ClientCAFile: caFile,
+	}, 0)
+	assert.ErrorIs(t, err, errNoClientCAs)
+}
+
+func TestTLSLoaderClientCA(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+
+	serverCert := newTestCert(t, "server", nil)
+	ca1 := newTestCert(t, "ca1", nil)
+	ca2 := newTestCert(t, "ca2", nil)
+	client1 := newTestCert(t, "client1", ca1)
+	client2 := newTestCert(t, "client2", ca2)
+
+	now := time.Now()
+	writeFile(t, caFile, ca1.certPEM, now)
+
+	loader, err := newTLSLoader(logging.NoLog{}, TLSConfig{
+		Cert:         serverCert.certPEM,
+		Key:          serverCert.keyPEM,
+		ClientCAFile: caFile,
+	}, 0)
+	assert.NoError(err)
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		GetConfigForClient: loader.GetConfigForClient,
+	})
+	assert.NoError(err)
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.TLS.VerifiedChains[0][0].Subject.CommonName))
+	})}
+	go func() { _ = srv.Serve(listener) }()
+	defer srv.Close()
+
+	serverCAs := x509.NewCertPool()
+	serverCAs.AddCert(serverCert.cert)
+	get := func(client *testCert) error {
+		clientConfig := &tls.Config{
+			MinVersion: tls.VersionTLS12,
+			RootCAs:    serverCAs,
+		}
+		if client != nil {
+			clientConfig.Certificates = []tls.Certificate{{
+				Certificate: [][]byte{client.cert.Raw},
+				PrivateKey:  client.key,
+			}}
+		}
+		httpClient := &http.Client{Transport: &http.Transport{TLSClientConfig: clientConfig}}
+		resp, err := httpClient.Get("https://" + listener.Addr().String())
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	}
+
+	assert.Error(get(nil), "should require a client certificate")
+	assert.NoError(get(client1))
+	assert.Error(get(client2), "should reject a certificate of an unknown CA")
+
+	// Replace the CA without restarting the server
+	writeFile(t, caFile, ca2.certPEM, now.Add(time.Minute))
+	assert.Error(get(client1), "should reject a certificate of a removed CA")
+	assert.NoError(get(client2))
+}
@@ -0,0 +1,291 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+// Package archive implements the archive command, which exports accepted
+// C-Chain blocks of a stopped node to a verifiable archive and imports such an
+// archive into the data directory of a stopped node. The archive includes the
+// proposervm blocks wrapping the C-Chain blocks, so that a node can resume
+// from an imported archive.
+package archive
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/flare-foundation/flare/api/metrics"
+	"github.com/flare-foundation/flare/app/process"
+	"github.com/flare-foundation/flare/chains/atomic"
+	"github.com/flare-foundation/flare/config"
+	"github.com/flare-foundation/flare/database/manager"
+	"github.com/flare-foundation/flare/database/prefixdb"
+	"github.com/flare-foundation/flare/genesis"
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/node"
+	"github.com/flare-foundation/flare/snow"
+	"github.com/flare-foundation/flare/snow/engine/snowman/block"
+	"github.com/flare-foundation/flare/snow/validators"
+	"github.com/flare-foundation/flare/utils/constants"
+	"github.com/flare-foundation/flare/utils/logging"
+	"github.com/flare-foundation/flare/version"
+	"github.com/flare-foundation/flare/vms/platformvm"
+	"github.com/flare-foundation/flare/vms/proposervm"
+
+	commonEng "github.com/flare-foundation/flare/snow/engine/common"
+
+	coreth "github.com/flare-foundation/flare/coreth/plugin/evm"
+)
+
+const (
+	// Command is the first argument that selects the archive command
+	Command = "archive"
+
+	exportCommand = "export"
+	importCommand = "import"
+
+	fileKey  = "archive-file"
+	firstKey = "archive-first"
+	lastKey  = "archive-last"
+
+	// heightIndexPollInterval is how often the height index of the proposervm
+	// is checked while it is being repaired before exporting.
+	heightIndexPollInterval = time.Second
+)
+
+var errMissingFile = fmt.Errorf("--%s must be specified", fileKey)
+
+// Usage describes the archive command
+const Usage = `usage: %[1]s archive export --archive-file=<file> [--archive-first=<height>] [--archive-last=<height>] [node flags]
+       %[1]s archive import --archive-file=<file> [node flags]
+
+The node must not be running. The node flags select the network, database
+and C-Chain config in the same way as when running the node. Archives are
+gzipped if the file name ends with ".gz".
+`
+
+// Run executes the archive command with the arguments following [Command]
+// and returns the exit code of the process.
+func Run(args []string) int {
+	if len(args) == 0 || (args[0] != exportCommand && args[0] != importCommand) {
+		fmt.Fprintf(os.Stderr, Usage, constants.AppName)
+		return 1
+	}
+
+	fs := config.BuildFlagSet()
+	addFlags(fs)
+	v, err := config.BuildViper(fs, args[1:])
+	if errors.Is(err, pflag.ErrHelp) {
+		fmt.Fprintf(os.Stderr, Usage, constants.AppName)
+		return 0
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "couldn't configure flags: %s\n", err)
+		return 1
+	}
+
+	if err := run(args[0], v); err != nil {
+		fmt.Fprintf(os.Stderr, "archive %s failed: %s\n", args[0], err)
+		return 1
+	}
+	return 0
+}
+
+func addFlags(fs *flag.FlagSet) {
+	fs.String(fileKey, "", "Path of the archive file")
+	fs.Uint64(firstKey, 0, "First block to export")
+	fs.Uint64(lastKey, 0, "Last block to export. Defaults to the last accepted block")
+}
+
+func run(command string, v *viper.Viper) error {
+	file := os.ExpandEnv(v.GetString(fileKey))
+	if file == "" {
+		return errMissingFile
+	}
+
+	// The build directory is only used to find plugins, which are not loaded
+	// by the archive command.
+	nodeConfig, err := config.GetNodeConfig(v, os.ExpandEnv(v.GetString(config.BuildDirKey)))
+	if err != nil {
+		return fmt.Errorf("couldn't load node config: %w", err)
+	}
+
+	logFactory := logging.NewFactory(nodeConfig.LoggingConfig)
+	defer logFactory.Close()
+	log, err := logFactory.Make(Command)
+	if err != nil {
+		return err
+	}
+
+	dbManager, err := process.NewDBManager(nodeConfig.DatabaseConfig, log)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := dbManager.Close(); err != nil {
+			log.Warn("failed to close the node's DB: %s", err)
+		}
+	}()
+
+	chain, err := newCChain(&nodeConfig, dbManager, log)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := chain.shutdown(); err != nil {
+			log.Warn("failed to shutdown the C-Chain VM: %s", err)
+		}
+	}()
+
+	switch command {
+	case exportCommand:
+		if err := chain.awaitHeightIndex(); err != nil {
+			return err
+		}
+
+		chain.ctx.Lock.Lock()
+		defer chain.ctx.Lock.Unlock()
+
+		lastAccepted, err := chain.vm.LastAccepted()
+		if err != nil {
+			return err
+		}
+		lastAcceptedBlock, err := chain.vm.GetBlock(lastAccepted)
+		if err != nil {
+			return err
+		}
+		first, last := v.GetUint64(firstKey), lastAcceptedBlock.Height()
+		if v.IsSet(lastKey) {
+			last = v.GetUint64(lastKey)
+		}
+		if err := chain.vm.ExportArchiveFile(file, first, last, chain.proVM); err != nil {
+			return err
+		}
+		log.Info("exported blocks %d-%d to %s", first, last, file)
+	case importCommand:
+		chain.ctx.Lock.Lock()
+		defer chain.ctx.Lock.Unlock()
+
+		stats, err := chain.vm.ImportArchiveFile(file, chain.proVM)
+		if err != nil {
+			return err
+		}
+		log.Info("imported blocks %d-%d from %s: %d imported, %d already accepted", stats.First, stats.Last, file, stats.Imported, stats.Skipped)
+	}
+	return nil
+}
+
+// cChain is the C-Chain VM wrapped by the proposervm
+type cChain struct {
+	ctx   *snow.Context
+	vm    *coreth.VM
+	proVM *proposervm.VM
+}
+
+// newCChain initializes the C-Chain VM on top of the database of the node in
+// the same way as the chain manager does.
+func newCChain(nodeConfig *node.Config, dbManager manager.Manager, log logging.Logger) (*cChain, error) {
+	createChainTx, err := genesis.VMGenesis(nodeConfig.GenesisBytes, constants.EVMID)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't find the C-Chain in the genesis: %w", err)
+	}
+	chainID := createChainTx.ID()
+	unsignedTx, ok := createChainTx.UnsignedTx.(*platformvm.UnsignedCreateChainTx)
+	if !ok {
+		return nil, fmt.Errorf("unexpected C-Chain genesis tx type %T", createChainTx.UnsignedTx)
+	}
+
+	aliaser := ids.NewAliaser()
+	for _, alias := range append(genesis.GetCChainAliases(), chainID.String()) {
+		if err := aliaser.Alias(chainID, alias); err != nil {
+			return nil, err
+		}
+	}
+
+	chainConfig, ok := nodeConfig.ChainConfigs[chainID.String()]
+	if !ok {
+		chainConfig = nodeConfig.ChainConfigs[genesis.GetCChainAliases()[0]]
+	}
+
+	sharedMemory := &atomic.Memory{}
+	if err := sharedMemory.Initialize(log, prefixdb.New([]byte("shared memory"), dbManager.Current().Database)); err != nil {
+		return nil, err
+	}
+
+	ctx := &snow.Context{
+		NetworkID:      nodeConfig.NetworkID,
+		SubnetID:       constants.PrimaryNetworkID,
+		ChainID:        chainID,
+		AVAXAssetID:    nodeConfig.AvaxAssetID,
+		Log:            log,
+		SharedMemory:   sharedMemory.NewSharedMemory(chainID),
+		BCLookup:       aliaser,
+		Metrics:        metrics.NewOptionalGatherer(),
+		ValidatorState: validators.NewNoState(),
+	}
+	vmDBManager := dbManager.NewPrefixDBManager(chainID[:]).NewPrefixDBManager([]byte("vm"))
+
+	vm := &coreth.VM{}
+	proVM := proposervm.New(
+		vm,
+		version.GetApricotPhase4Time(nodeConfig.NetworkID),
+		version.GetApricotPhase4MinPChainHeight(nodeConfig.NetworkID),
+		false,
+	)
+	if err := proVM.Initialize(
+		ctx,
+		vmDBManager,
+		unsignedTx.GenesisData,
+		chainConfig.Upgrade,
+		chainConfig.Config,
+		make(chan commonEng.Message, 1),
+		nil,
+		nopAppSender{},
+	); err != nil {
+		return nil, fmt.Errorf("couldn't initialize the C-Chain VM: %w", err)
+	}
+	// The VM stays bootstrapping, as it is not connected to the network.
+	if err := proVM.SetState(snow.Bootstrapping); err != nil {
+		return nil, err
+	}
+	return &cChain{
+		ctx:   ctx,
+		vm:    vm,
+		proVM: proVM,
+	}, nil
+}
+
+// awaitHeightIndex waits until the proposervm finished repairing its height
+// index, which is needed to find the proposervm blocks to export.
+func (c *cChain) awaitHeightIndex() error {
+	for {
+		c.ctx.Lock.Lock()
+		_, err := c.proVM.ArchivedBlockBytes(0)
+		c.ctx.Lock.Unlock()
+		if !errors.Is(err, block.ErrIndexIncomplete) {
+			return err
+		}
+		c.ctx.Log.Info("waiting for the proposervm height index to be repaired")
+		time.Sleep(heightIndexPollInterval)
+	}
+}
+
+func (c *cChain) shutdown() error {
+	c.ctx.Lock.Lock()
+	defer c.ctx.Lock.Unlock()
+
+	return c.proVM.Shutdown()
+}
+
+// nopAppSender drops all messages, as the node is not connected to peers
+// while the archive command runs.
+type nopAppSender struct{}
+
+func (nopAppSender) SendAppRequest(ids.ShortSet, uint32, []byte) error { return nil }
+func (nopAppSender) SendAppResponse(ids.ShortID, uint32, []byte) error { return nil }
+func (nopAppSender) SendAppGossip([]byte) error                        { return nil }
+func (nopAppSender) SendAppGossipSpecific(ids.ShortSet, []byte) error  { return nil }
@@ -0,0 +1,231 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package archive
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/flare-foundation/flare/app/process"
+	"github.com/flare-foundation/flare/config"
+	"github.com/flare-foundation/flare/coreth/core/types"
+	"github.com/flare-foundation/flare/coreth/params"
+	"github.com/flare-foundation/flare/coreth/rpc"
+	"github.com/flare-foundation/flare/genesis"
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/snow"
+	"github.com/flare-foundation/flare/utils/logging"
+	"github.com/flare-foundation/flare/vms/proposervm/proposer"
+)
+
+const testNetworkID = 1337
+
+// testGenesisContent returns the local genesis with [testNetworkID] and the
+// C-Chain funds of [addr].
+func testGenesisContent(t *testing.T, addr common.Address) string {
+	var cChainGenesis map[string]interface{}
+	if err := json.Unmarshal([]byte(genesis.LocalConfig.CChainGenesis), &cChainGenesis); err != nil {
+		t.Fatal(err)
+	}
+	cChainGenesis["alloc"].(map[string]interface{})[addr.Hex()[2:]] = map[string]interface{}{
+		"balance": hexutil.EncodeBig(new(big.Int).Mul(big.NewInt(params.Ether), big.NewInt(1000))),
+	}
+	cChainGenesisBytes, err := json.Marshal(cChainGenesis)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	genesisConfig := genesis.LocalConfig
+	genesisConfig.NetworkID = testNetworkID
+	genesisConfig.CChainGenesis = string(cChainGenesisBytes)
+	unparsedConfig, err := genesisConfig.Unparse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	genesisBytes, err := json.Marshal(unparsedConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base64.StdEncoding.EncodeToString(genesisBytes)
+}
+
+// testArgs returns the node flags of a node with its data in [dataDir]
+func testArgs(dataDir, genesisContent string) []string {
+	return []string{
+		fmt.Sprintf("--%s=%d", config.NetworkNameKey, testNetworkID),
+		fmt.Sprintf("--%s=%s", config.GenesisConfigContentKey, genesisContent),
+		fmt.Sprintf("--%s=%s", config.DBPathKey, filepath.Join(dataDir, "db")),
+		fmt.Sprintf("--%s=%s", config.LogsDirKey, filepath.Join(dataDir, "logs")),
+	}
+}
+
+// openTestChain opens the C-Chain of the node with its data in [dataDir] and
+// returns it with a function closing it.
+func openTestChain(t *testing.T, dataDir, genesisContent string) (*cChain, func()) {
+	fs := config.BuildFlagSet()
+	addFlags(fs)
+	v, err := config.BuildViper(fs, testArgs(dataDir, genesisContent))
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodeConfig, err := config.GetNodeConfig(v, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dbManager, err := process.NewDBManager(nodeConfig.DatabaseConfig, logging.NoLog{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain, err := newCChain(&nodeConfig, dbManager, logging.NoLog{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return chain, func() {
+		if err := chain.shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		if err := dbManager.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// buildTestBlocks builds and accepts [count] blocks, each containing a single
+// transfer signed by [key] with a nonce starting at [nonce].
+func buildTestBlocks(t *testing.T, chain *cChain, key *ecdsa.PrivateKey, nonce uint64, count int) {
+	handlers, err := chain.vm.CreateHandlers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := rpc.DialInProc(handlers["/rpc"].Handler.(*rpc.Server))
+	defer client.Close()
+
+	var chainID hexutil.Big
+	if err := client.Call(&chainID, "eth_chainId"); err != nil {
+		t.Fatal(err)
+	}
+	signer := types.LatestSignerForChainID(chainID.ToInt())
+	gasPrice := new(big.Int).Mul(big.NewInt(params.LaunchMinGasPrice), big.NewInt(10))
+
+	chain.ctx.Lock.Lock()
+	defer chain.ctx.Lock.Unlock()
+
+	if err := chain.proVM.SetState(snow.NormalOp); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < count; i++ {
+		tx, err := types.SignTx(types.NewTransaction(nonce+uint64(i), common.Address{1}, big.NewInt(1), params.TxGas, gasPrice, nil), signer, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		txBytes, err := tx.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := client.CallContext(context.Background(), nil, "eth_sendRawTransaction", hexutil.Encode(txBytes)); err != nil {
+			t.Fatal(err)
+		}
+
+		lastAccepted, err := chain.proVM.LastAccepted()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := chain.proVM.SetPreference(lastAccepted); err != nil {
+			t.Fatal(err)
+		}
+		parent, err := chain.proVM.GetBlock(lastAccepted)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Post fork blocks are built after the proposer windows elapsed, so
+		// that they don't need to be signed.
+		chain.proVM.Set(parent.Timestamp().Add(proposer.MaxDelay))
+
+		blk, err := chain.proVM.BuildBlock()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := blk.Verify(); err != nil {
+			t.Fatal(err)
+		}
+		if err := blk.Accept(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func lastAccepted(t *testing.T, chain *cChain) (ids.ID, uint64) {
+	chain.ctx.Lock.Lock()
+	defer chain.ctx.Lock.Unlock()
+
+	blkID, err := chain.proVM.LastAccepted()
+	if err != nil {
+		t.Fatal(err)
+	}
+	blk, err := chain.proVM.GetBlock(blkID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return blkID, blk.Height()
+}
+
+func TestArchiveImportIntoFreshDataDir(t *testing.T) {
+	assert := assert.New(t)
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		genesisContent = testGenesisContent(t, crypto.PubkeyToAddress(key.PublicKey))
+		sourceDir      = t.TempDir()
+		importDir      = t.TempDir()
+		archiveFile    = filepath.Join(t.TempDir(), "archive.gz")
+	)
+
+	// The first block is built before the proposervm fork, as the genesis
+	// timestamp is before the fork, and the other blocks after it.
+	source, closeSource := openTestChain(t, sourceDir, genesisContent)
+	buildTestBlocks(t, source, key, 0, 3)
+	sourceLastAccepted, sourceHeight := lastAccepted(t, source)
+	closeSource()
+	assert.Equal(uint64(3), sourceHeight)
+
+	fileArg := fmt.Sprintf("--%s=%s", fileKey, archiveFile)
+	assert.Equal(0, Run(append([]string{exportCommand, fileArg}, testArgs(sourceDir, genesisContent)...)))
+	// Archives are never overwritten.
+	assert.Equal(1, Run(append([]string{exportCommand, fileArg}, testArgs(sourceDir, genesisContent)...)))
+	assert.Equal(0, Run(append([]string{importCommand, fileArg}, testArgs(importDir, genesisContent)...)))
+
+	// The imported node resumes from the last accepted proposervm block of the
+	// source node and keeps building on top of it.
+	imported, closeImported := openTestChain(t, importDir, genesisContent)
+	defer closeImported()
+	importedLastAccepted, importedHeight := lastAccepted(t, imported)
+	assert.Equal(sourceLastAccepted, importedLastAccepted)
+	assert.Equal(sourceHeight, importedHeight)
+
+	buildTestBlocks(t, imported, key, 3, 1)
+	_, height := lastAccepted(t, imported)
+	assert.Equal(sourceHeight+1, height)
+}
+
+func TestArchiveMissingFile(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(1, Run(nil))
+	assert.Equal(1, Run([]string{exportCommand}))
+	assert.Equal(1, Run([]string{importCommand, fmt.Sprintf("--%s=%s", fileKey, filepath.Join(t.TempDir(), "missing"))}))
+}
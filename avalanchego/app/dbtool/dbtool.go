@@ -0,0 +1,158 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+// Package dbtool implements the db command, which reports what the database
+// of a stopped node uses its space for and checks it for dangling references.
+package dbtool
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/flare-foundation/flare/app/process"
+	"github.com/flare-foundation/flare/config"
+	"github.com/flare-foundation/flare/database/inspect"
+	"github.com/flare-foundation/flare/database/manager"
+	"github.com/flare-foundation/flare/utils/constants"
+	"github.com/flare-foundation/flare/utils/logging"
+	"github.com/flare-foundation/flare/utils/units"
+)
+
+const (
+	// Command is the first argument that selects the db command
+	Command = "db"
+
+	inspectCommand = "inspect"
+	verifyCommand  = "verify"
+)
+
+// Usage describes the db command
+const Usage = `usage: %[1]s db inspect [node flags]
+       %[1]s db verify [node flags]
+
+The node must not be running. The node flags select the network and database
+in the same way as when running the node.
+
+inspect reports the number of keys and bytes used by each part of the
+database. verify checks that the proposervm height indices have no gaps and
+that the state of the last accepted C-Chain block has no missing trie nodes.
+`
+
+var errProblemsFound = errors.New("database has problems")
+
+// Run executes the db command with the arguments following [Command] and
+// returns the exit code of the process.
+func Run(args []string) int {
+	if len(args) == 0 || (args[0] != inspectCommand && args[0] != verifyCommand) {
+		fmt.Fprintf(os.Stderr, Usage, constants.AppName)
+		return 1
+	}
+
+	fs := config.BuildFlagSet()
+	v, err := config.BuildViper(fs, args[1:])
+	if errors.Is(err, pflag.ErrHelp) {
+		fmt.Fprintf(os.Stderr, Usage, constants.AppName)
+		return 0
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "couldn't configure flags: %s\n", err)
+		return 1
+	}
+
+	if err := run(args[0], v); err != nil {
+		fmt.Fprintf(os.Stderr, "db %s failed: %s\n", args[0], err)
+		return 1
+	}
+	return 0
+}
+
+func run(command string, v *viper.Viper) error {
+	// The build directory is only used to find plugins, which are not loaded
+	// by the db command.
+	nodeConfig, err := config.GetNodeConfig(v, os.ExpandEnv(v.GetString(config.BuildDirKey)))
+	if err != nil {
+		return fmt.Errorf("couldn't load node config: %w", err)
+	}
+	chains, err := genesisChains(nodeConfig.GenesisBytes)
+	if err != nil {
+		return err
+	}
+
+	// The results are written to stdout, so logs are only written to the log
+	// directory.
+	nodeConfig.LoggingConfig.DisplayLevel = logging.Off
+	logFactory := logging.NewFactory(nodeConfig.LoggingConfig)
+	defer logFactory.Close()
+	log, err := logFactory.Make(Command)
+	if err != nil {
+		return err
+	}
+
+	dbManager, err := process.NewDBManager(nodeConfig.DatabaseConfig, log)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := dbManager.Close(); err != nil {
+			log.Warn("failed to close the node's DB: %s", err)
+		}
+	}()
+
+	switch command {
+	case inspectCommand:
+		return runInspect(dbManager, chains, log)
+	default:
+		return runVerify(dbManager, chains, log)
+	}
+}
+
+func runInspect(dbManager manager.Manager, chains []chain, log logging.Logger) error {
+	current := dbManager.Current()
+	log.Info("inspecting database %s", current.Version)
+	usages, err := inspect.Inspect(current.Database, layout(chains))
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "PARTITION\tKEYS\tSIZE (MiB)\t")
+	total := inspect.Usage{Name: "total"}
+	for _, usage := range usages {
+		total.NumKeys += usage.NumKeys
+		total.Size += usage.Size
+		printUsage(w, usage)
+	}
+	printUsage(w, total)
+	return w.Flush()
+}
+
+func printUsage(w io.Writer, usage inspect.Usage) {
+	fmt.Fprintf(w, "%s\t%d\t%.2f\t\n", usage.Name, usage.NumKeys, float64(usage.Size)/units.MiB)
+}
+
+func runVerify(dbManager manager.Manager, chains []chain, log logging.Logger) error {
+	var problems []string
+	for _, c := range chains {
+		chainProblems, err := verifyChain(dbManager, c, log)
+		if err != nil {
+			return fmt.Errorf("couldn't verify the %s chain: %w", c.name, err)
+		}
+		for _, problem := range chainProblems {
+			problems = append(problems, fmt.Sprintf("%s: %s", c.name, problem))
+		}
+	}
+	for _, problem := range problems {
+		fmt.Println(problem)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: found %d", errProblemsFound, len(problems))
+	}
+	fmt.Println("no problems found")
+	return nil
+}
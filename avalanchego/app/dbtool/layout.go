@@ -0,0 +1,168 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package dbtool
+
+import (
+	"github.com/flare-foundation/flare/chains"
+	"github.com/flare-foundation/flare/coreth/core/rawdb"
+	"github.com/flare-foundation/flare/database/inspect"
+	"github.com/flare-foundation/flare/genesis"
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/utils/constants"
+	"github.com/flare-foundation/flare/utils/hashing"
+)
+
+// The prefixes below are the ones the node, the chain manager and the VMs
+// create their databases with. They have to be kept in sync with them.
+var (
+	// Created by the node
+	indexerPrefix      = []byte{0x00}
+	nodePrefixes       = []string{"benchlist", "uptime", "shared memory", "keystore"}
+	indexerTxPrefix    = byte(0x01)
+	indexerVtxPrefix   = byte(0x02)
+	indexerBlockPrefix = byte(0x03)
+
+	// Created by the chain manager
+	vmPrefix          = []byte("vm")
+	consensusPrefixes = []string{"vertex", "vertex_bs", "tx_bs", "bs", string(chains.PollJournalPrefix)}
+
+	// Created by the proposervm on top of the VM of a snowman chain
+	proposerVMPrefix        = []byte("proposervm")
+	proposerVMStatePrefixes = []string{"chain", "block"}
+	proposerVMHeightPrefix  = []byte("height")
+
+	// Created by the platformvm
+	platformVMStatePrefixes     = []string{"rewardUTXOs", "subnet", "block", "tx", "chain", "singleton"}
+	platformVMValidatorPrefixes = []string{"validator", "delegator", "subnetValidator"}
+
+	// Created by the avm
+	avmStatePrefixes = []string{"status", "singleton", "tx"}
+
+	// Created by coreth
+	ethDBPrefix          = []byte("ethdb")
+	corethAcceptedPrefix = []byte("snowman_accepted")
+	corethStatePrefixes  = []string{
+		"atomicTxDB",
+		"atomicHeightTxDB",
+		"atomicRepoMetadataDB",
+		"atomicTrieDB",
+		"atomicTrieMetaDB",
+	}
+)
+
+type chainKind int
+
+const (
+	platformChain chainKind = iota
+	exchangeChain
+	contractChain
+)
+
+// chain is a chain created in the genesis
+type chain struct {
+	id   ids.ID
+	name string
+	kind chainKind
+}
+
+// genesisChains returns the chains created in the genesis
+func genesisChains(genesisBytes []byte) ([]chain, error) {
+	_, chainAliases, err := genesis.Aliases(genesisBytes)
+	if err != nil {
+		return nil, err
+	}
+	cChainAlias := genesis.GetCChainAliases()[0]
+	xChainAlias := genesis.GetXChainAliases()[0]
+
+	genesisChains := []chain{{
+		id:   constants.PlatformChainID,
+		name: chainAliases[constants.PlatformChainID][0],
+		kind: platformChain,
+	}}
+	for chainID, aliases := range chainAliases {
+		switch aliases[0] {
+		case xChainAlias:
+			genesisChains = append(genesisChains, chain{id: chainID, name: aliases[0], kind: exchangeChain})
+		case cChainAlias:
+			genesisChains = append(genesisChains, chain{id: chainID, name: aliases[0], kind: contractChain})
+		}
+	}
+	return genesisChains, nil
+}
+
+// layout returns the partitions of the node's database
+func layout(genesisChains []chain) *inspect.Partition {
+	root := inspect.New()
+	for _, prefix := range nodePrefixes {
+		root.Nested(prefix, []byte(prefix))
+	}
+	indexer := root.Nested("indexer", indexerPrefix)
+
+	for _, c := range genesisChains {
+		for name, prefixEnd := range map[string]byte{
+			"tx":    indexerTxPrefix,
+			"vtx":   indexerVtxPrefix,
+			"block": indexerBlockPrefix,
+		} {
+			prefix := make([]byte, hashing.HashLen+1)
+			copy(prefix, c.id[:])
+			prefix[hashing.HashLen] = prefixEnd
+			indexer.Prefix(c.name+"/"+name, prefix)
+		}
+
+		chainPartition := root.Nested(c.name, c.id[:])
+		for _, prefix := range consensusPrefixes {
+			chainPartition.Prefix(prefix, []byte(prefix))
+		}
+		vm := chainPartition.Prefix("vm", vmPrefix)
+
+		switch c.kind {
+		case platformChain:
+			addProposerVM(vm)
+			validators := vm.Nested("validators", []byte("validators"))
+			for _, prefix := range []string{"current", "pending"} {
+				validatorSet := validators.Prefix(prefix, []byte(prefix))
+				for _, prefix := range platformVMValidatorPrefixes {
+					validatorSet.Prefix(prefix, []byte(prefix))
+				}
+			}
+			validators.Prefix("validatorDiffs", []byte("validatorDiffs"))
+			addUTXOState(vm.Nested("utxo", []byte("utxo")))
+			for _, prefix := range platformVMStatePrefixes {
+				vm.Nested(prefix, []byte(prefix))
+			}
+		case exchangeChain:
+			addUTXOState(vm.Nested("utxo", []byte("utxo")))
+			for _, prefix := range avmStatePrefixes {
+				vm.Nested(prefix, []byte(prefix))
+			}
+		case contractChain:
+			addProposerVM(vm)
+			vm.Nested(string(ethDBPrefix), ethDBPrefix).Categorize(rawdb.KeyCategory)
+			vm.Nested(string(corethAcceptedPrefix), corethAcceptedPrefix)
+			for _, prefix := range corethStatePrefixes {
+				vm.Nested(prefix, []byte(prefix))
+			}
+		}
+	}
+	return root
+}
+
+// addProposerVM adds the partitions of the proposervm to the [vm] partition
+// of a snowman chain
+func addProposerVM(vm *inspect.Partition) {
+	proposerVM := vm.Prefix(string(proposerVMPrefix), proposerVMPrefix)
+	for _, prefix := range proposerVMStatePrefixes {
+		proposerVM.Nested(prefix, []byte(prefix))
+	}
+	height := proposerVM.Nested(string(proposerVMHeightPrefix), proposerVMHeightPrefix)
+	height.Prefix("height", []byte("height"))
+	height.Prefix("metadata", []byte("metadata"))
+}
+
+// addUTXOState adds the partitions of an avax.UTXOState to [utxo]
+func addUTXOState(utxo *inspect.Partition) {
+	utxo.Prefix("utxo", []byte("utxo"))
+	utxo.Prefix("index", []byte("index"))
+}
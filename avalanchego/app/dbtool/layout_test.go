@@ -0,0 +1,178 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package dbtool
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/flare-foundation/flare/coreth/core/rawdb"
+	"github.com/flare-foundation/flare/coreth/core/types"
+	"github.com/flare-foundation/flare/database"
+	"github.com/flare-foundation/flare/database/inspect"
+	"github.com/flare-foundation/flare/database/manager"
+	"github.com/flare-foundation/flare/database/memdb"
+	"github.com/flare-foundation/flare/database/prefixdb"
+	"github.com/flare-foundation/flare/database/versiondb"
+	"github.com/flare-foundation/flare/genesis"
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/snow/choices"
+	"github.com/flare-foundation/flare/version"
+
+	proposerstate "github.com/flare-foundation/flare/vms/proposervm/state"
+
+	statelessblock "github.com/flare-foundation/flare/vms/proposervm/block"
+
+	coreth "github.com/flare-foundation/flare/coreth/plugin/evm"
+)
+
+func TestGenesisChains(t *testing.T) {
+	assert := assert.New(t)
+
+	genesisBytes, _, err := genesis.FromConfig(&genesis.LocalConfig)
+	assert.NoError(err)
+	chains, err := genesisChains(genesisBytes)
+	assert.NoError(err)
+
+	names := make(map[chainKind]string)
+	for _, c := range chains {
+		names[c.kind] = c.name
+	}
+	assert.Len(chains, 3)
+	assert.Equal(map[chainKind]string{
+		platformChain: "P",
+		exchangeChain: "X",
+		contractChain: "C",
+	}, names)
+}
+
+func TestLayout(t *testing.T) {
+	genesisBytes, _, err := genesis.FromConfig(&genesis.LocalConfig)
+	assert.NoError(t, err)
+	chains, err := genesisChains(genesisBytes)
+	assert.NoError(t, err)
+	chainIDs := make(map[string]ids.ID)
+	for _, c := range chains {
+		chainIDs[c.name] = c.id
+	}
+
+	// The keys are written the way the node, the chain manager and the VMs
+	// write them
+	chainDB := func(db manager.Manager, name string) manager.Manager {
+		id := chainIDs[name]
+		return db.NewPrefixDBManager(id[:])
+	}
+	vmDB := func(db manager.Manager, name string) database.Database {
+		return chainDB(db, name).NewPrefixDBManager(vmPrefix).Current().Database
+	}
+	tests := []struct {
+		name     string
+		expected []string
+		write    func(t *testing.T, db manager.Manager)
+	}{
+		{
+			name:     "keystore",
+			expected: []string{"keystore"},
+			write: func(t *testing.T, db manager.Manager) {
+				assert.NoError(t, prefixdb.New([]byte("keystore"), db.Current().Database).Put([]byte("user"), nil))
+			},
+		},
+		{
+			name:     "indexer/C/block",
+			expected: []string{"indexer/C/block"},
+			write: func(t *testing.T, db manager.Manager) {
+				id := chainIDs["C"]
+				indexerDB := prefixdb.New(indexerPrefix, db.Current().Database)
+				assert.NoError(t, prefixdb.New(append(id[:], indexerBlockPrefix), indexerDB).Put([]byte{1}, nil))
+			},
+		},
+		{
+			name:     "X/bs",
+			expected: []string{"X/bs"},
+			write: func(t *testing.T, db manager.Manager) {
+				assert.NoError(t, prefixdb.New([]byte("bs"), chainDB(db, "X").Current().Database).Put([]byte{1}, nil))
+			},
+		},
+		{
+			name:     "X/vm/utxo/utxo",
+			expected: []string{"X/vm/utxo/utxo"},
+			write: func(t *testing.T, db manager.Manager) {
+				versionDB := versiondb.New(vmDB(db, "X"))
+				utxoDB := prefixdb.New([]byte("utxo"), versionDB)
+				assert.NoError(t, prefixdb.New([]byte("utxo"), utxoDB).Put([]byte{1}, nil))
+				assert.NoError(t, versionDB.Commit())
+			},
+		},
+		{
+			name:     "C/vm/proposervm/block",
+			expected: []string{"C/vm/proposervm/block"},
+			write: func(t *testing.T, db manager.Manager) {
+				stateDB := versiondb.New(prefixdb.New(proposerVMPrefix, vmDB(db, "C")))
+				blk, err := statelessblock.BuildUnsigned(ids.GenerateTestID(), time.Unix(0, 0), 0, nil)
+				assert.NoError(t, err)
+				assert.NoError(t, proposerstate.New(stateDB).PutBlock(blk, choices.Accepted))
+				assert.NoError(t, stateDB.Commit())
+			},
+		},
+		{
+			name:     "P/vm/proposervm/height/height",
+			expected: []string{"P/vm/proposervm/height/height"},
+			write: func(t *testing.T, db manager.Manager) {
+				stateDB := versiondb.New(prefixdb.New(proposerVMPrefix, vmDB(db, "P")))
+				assert.NoError(t, proposerstate.New(stateDB).SetBlockIDAtHeight(1, ids.GenerateTestID()))
+				assert.NoError(t, stateDB.Commit())
+			},
+		},
+		{
+			name:     "C/vm/ethdb",
+			expected: []string{"C/vm/ethdb/Block hash->number", "C/vm/ethdb/Headers"},
+			write: func(t *testing.T, db manager.Manager) {
+				versionDB := versiondb.New(vmDB(db, "C"))
+				rawdb.WriteHeader(coreth.Database{Database: prefixdb.NewNested(ethDBPrefix, versionDB)}, &types.Header{
+					Number:     big.NewInt(1),
+					Difficulty: big.NewInt(0),
+				})
+				assert.NoError(t, versionDB.Commit())
+			},
+		},
+		{
+			name:     "C/vm/snowman_accepted",
+			expected: []string{"C/vm/snowman_accepted"},
+			write: func(t *testing.T, db manager.Manager) {
+				assert.NoError(t, prefixdb.NewNested(corethAcceptedPrefix, vmDB(db, "C")).Put(lastAcceptedKey, nil))
+			},
+		},
+		{
+			name:     inspect.Other,
+			expected: []string{inspect.Other},
+			write: func(t *testing.T, db manager.Manager) {
+				assert.NoError(t, db.Current().Database.Put([]byte("unknown"), nil))
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			baseDB := memdb.New()
+			db, err := manager.NewManagerFromDBs([]*manager.VersionedDatabase{{
+				Database: baseDB,
+				Version:  version.DefaultVersion1_0_0,
+			}})
+			assert.NoError(err)
+			test.write(t, db)
+
+			usages, err := inspect.Inspect(baseDB, layout(chains))
+			assert.NoError(err)
+			names := make([]string, len(usages))
+			for i, usage := range usages {
+				names[i] = usage.Name
+			}
+			assert.Equal(test.expected, names)
+		})
+	}
+}
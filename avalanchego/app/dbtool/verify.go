@@ -0,0 +1,171 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package dbtool
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/flare-foundation/flare/coreth/core/rawdb"
+	"github.com/flare-foundation/flare/coreth/core/state"
+	"github.com/flare-foundation/flare/database"
+	"github.com/flare-foundation/flare/database/manager"
+	"github.com/flare-foundation/flare/database/prefixdb"
+	"github.com/flare-foundation/flare/database/versiondb"
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/snow/choices"
+	"github.com/flare-foundation/flare/utils/logging"
+
+	proposerstate "github.com/flare-foundation/flare/vms/proposervm/state"
+
+	coreth "github.com/flare-foundation/flare/coreth/plugin/evm"
+)
+
+var (
+	// Key of the last accepted C-Chain block in its accepted prefix
+	lastAcceptedKey = []byte("last_accepted_key")
+
+	// Frequency of progress logs while trie nodes are checked
+	progressLogFrequency = 10 * time.Second
+)
+
+// verifyChain returns the problems found in the database of [c]
+func verifyChain(dbManager manager.Manager, c chain, log logging.Logger) ([]string, error) {
+	vmDB := dbManager.NewPrefixDBManager(c.id[:]).NewPrefixDBManager(vmPrefix).Current().Database
+
+	var problems []string
+	switch c.kind {
+	case platformChain, contractChain:
+		log.Info("verifying the proposervm height index of the %s chain", c.name)
+		proposerState := proposerstate.New(versiondb.New(prefixdb.New(proposerVMPrefix, vmDB)))
+		heightProblems, err := verifyHeightIndex(proposerState)
+		if err != nil {
+			return nil, err
+		}
+		problems = append(problems, heightProblems...)
+	}
+	if c.kind == contractChain {
+		log.Info("verifying the state of the last accepted %s chain block", c.name)
+		stateProblems, err := verifyEVMState(vmDB, log)
+		if err != nil {
+			return nil, err
+		}
+		problems = append(problems, stateProblems...)
+	}
+	return problems, nil
+}
+
+// verifyHeightIndex returns the gaps in the height index of the proposervm
+// and the indexed blocks that are missing
+func verifyHeightIndex(s proposerstate.State) ([]string, error) {
+	forkHeight, err := s.GetForkHeight()
+	if err == database.ErrNotFound {
+		// No block was accepted after the fork, so nothing is indexed
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	switch _, err := s.GetCheckpoint(); err {
+	case nil:
+		return []string{"proposervm height index is being rebuilt, so it's incomplete"}, nil
+	case database.ErrNotFound:
+	default:
+		return nil, err
+	}
+	lastAccepted, err := s.GetLastAccepted()
+	if err == database.ErrNotFound {
+		return []string{fmt.Sprintf("proposervm has a fork height of %d but no last accepted block", forkHeight)}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		problems []string
+		parentID ids.ID
+		height   = forkHeight
+	)
+	for ; ; height++ {
+		blkID, err := s.GetBlockIDAtHeight(height)
+		if err == database.ErrNotFound {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		blk, status, err := s.GetBlock(blkID)
+		switch {
+		case err == database.ErrNotFound:
+			problems = append(problems, fmt.Sprintf("proposervm block %s at height %d is missing", blkID, height))
+		case err != nil:
+			return nil, err
+		case status != choices.Accepted:
+			problems = append(problems, fmt.Sprintf("proposervm block %s at height %d has status %s", blkID, height, status))
+		case height > forkHeight && blk.ParentID() != parentID:
+			problems = append(problems, fmt.Sprintf("proposervm block %s at height %d isn't a child of the block at height %d", blkID, height, height-1))
+		}
+		parentID = blkID
+	}
+	if parentID != lastAccepted {
+		problems = append(problems, fmt.Sprintf("proposervm height index has no block at height %d, but doesn't include the last accepted block %s", height, lastAccepted))
+	}
+	return problems, nil
+}
+
+// verifyEVMState returns the problems found in the state of the last accepted
+// block of the coreth VM whose database is [vmDB]
+func verifyEVMState(vmDB database.Database, log logging.Logger) ([]string, error) {
+	lastAcceptedBytes, err := prefixdb.NewNested(corethAcceptedPrefix, vmDB).Get(lastAcceptedKey)
+	if err == database.ErrNotFound {
+		// Only the genesis block has been accepted
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(lastAcceptedBytes) != common.HashLength {
+		return []string{fmt.Sprintf("last accepted block hash has %d bytes", len(lastAcceptedBytes))}, nil
+	}
+	lastAccepted := common.BytesToHash(lastAcceptedBytes)
+
+	chainDB := coreth.Database{Database: prefixdb.NewNested(ethDBPrefix, vmDB)}
+	number := rawdb.ReadHeaderNumber(chainDB, lastAccepted)
+	if number == nil {
+		return []string{fmt.Sprintf("last accepted block %s has no number", lastAccepted.Hex())}, nil
+	}
+	header := rawdb.ReadHeader(chainDB, lastAccepted, *number)
+	if header == nil {
+		return []string{fmt.Sprintf("last accepted block %s has no header", lastAccepted.Hex())}, nil
+	}
+	var problems []string
+	if canonical := rawdb.ReadCanonicalHash(chainDB, *number); canonical != lastAccepted {
+		problems = append(problems, fmt.Sprintf("canonical block at height %d is %s instead of the last accepted block %s", *number, canonical.Hex(), lastAccepted.Hex()))
+	}
+
+	stateDB, err := state.New(header.Root, state.NewDatabase(chainDB), nil)
+	if err != nil {
+		// The state of the last accepted block is written when the node shuts
+		// down, so it may be missing after a crash.
+		return append(problems, fmt.Sprintf("state root %s of block %d is missing: %s", header.Root.Hex(), *number, err)), nil
+	}
+	it := state.NewNodeIterator(stateDB)
+	nodes := 0
+	lastLogged := time.Now()
+	for it.Next() {
+		nodes++
+		if time.Since(lastLogged) > progressLogFrequency {
+			log.Info("checked %d state nodes", nodes)
+			lastLogged = time.Now()
+		}
+	}
+	if it.Error != nil {
+		return append(problems, fmt.Sprintf("state of block %d is incomplete after %d nodes: %s", *number, nodes, it.Error)), nil
+	}
+	log.Info("checked %d state nodes of block %d", nodes, *number)
+	return problems, nil
+}
@@ -0,0 +1,267 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package dbtool
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/flare-foundation/flare/coreth/core/rawdb"
+	"github.com/flare-foundation/flare/coreth/core/state"
+	"github.com/flare-foundation/flare/coreth/core/types"
+	"github.com/flare-foundation/flare/database"
+	"github.com/flare-foundation/flare/database/memdb"
+	"github.com/flare-foundation/flare/database/prefixdb"
+	"github.com/flare-foundation/flare/database/versiondb"
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/snow/choices"
+	"github.com/flare-foundation/flare/utils/logging"
+
+	proposerstate "github.com/flare-foundation/flare/vms/proposervm/state"
+
+	statelessblock "github.com/flare-foundation/flare/vms/proposervm/block"
+
+	coreth "github.com/flare-foundation/flare/coreth/plugin/evm"
+)
+
+const testForkHeight = 10
+
+// newTestBlocks returns a chain of [n] proposervm blocks
+func newTestBlocks(t *testing.T, n int) []statelessblock.Block {
+	blks := make([]statelessblock.Block, n)
+	parentID := ids.GenerateTestID()
+	for i := range blks {
+		blk, err := statelessblock.BuildUnsigned(parentID, time.Unix(int64(i), 0), 0, []byte{byte(i)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		blks[i] = blk
+		parentID = blk.ID()
+	}
+	return blks
+}
+
+// indexTestBlocks accepts [blks] in [s] and indexes them from
+// [testForkHeight] on
+func indexTestBlocks(t *testing.T, s proposerstate.State, blks []statelessblock.Block) {
+	assert := assert.New(t)
+
+	assert.NoError(s.SetForkHeight(testForkHeight))
+	for i, blk := range blks {
+		assert.NoError(s.PutBlock(blk, choices.Accepted))
+		assert.NoError(s.SetBlockIDAtHeight(testForkHeight+uint64(i), blk.ID()))
+	}
+	assert.NoError(s.SetLastAccepted(blks[len(blks)-1].ID()))
+}
+
+// assertProblems checks that [problems] has one problem per entry of
+// [expected], which contains the given text.
+func assertProblems(t *testing.T, expected []string, problems []string) {
+	if !assert.Len(t, problems, len(expected), "problems: %v", problems) {
+		return
+	}
+	for i, text := range expected {
+		assert.True(t, strings.Contains(problems[i], text), "problem %q doesn't mention %q", problems[i], text)
+	}
+}
+
+func TestVerifyHeightIndex(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(t *testing.T, s proposerstate.State, blks []statelessblock.Block)
+		expected []string
+	}{
+		{
+			name:  "nothing indexed",
+			setup: func(*testing.T, proposerstate.State, []statelessblock.Block) {},
+		},
+		{
+			name:  "complete",
+			setup: indexTestBlocks,
+		},
+		{
+			name: "repair in progress",
+			setup: func(t *testing.T, s proposerstate.State, blks []statelessblock.Block) {
+				indexTestBlocks(t, s, blks)
+				assert.NoError(t, s.SetCheckpoint(blks[1].ID()))
+			},
+			expected: []string{"being rebuilt"},
+		},
+		{
+			name: "missing last accepted block",
+			setup: func(t *testing.T, s proposerstate.State, blks []statelessblock.Block) {
+				indexTestBlocks(t, s, blks)
+				assert.NoError(t, s.DeleteLastAccepted())
+			},
+			expected: []string{"no last accepted block"},
+		},
+		{
+			name: "missing block",
+			setup: func(t *testing.T, s proposerstate.State, blks []statelessblock.Block) {
+				indexTestBlocks(t, s, blks)
+				assert.NoError(t, s.SetBlockIDAtHeight(testForkHeight+1, ids.GenerateTestID()))
+			},
+			// The next block isn't a child of the missing block either
+			expected: []string{"is missing", "isn't a child"},
+		},
+		{
+			name: "block not accepted",
+			setup: func(t *testing.T, s proposerstate.State, blks []statelessblock.Block) {
+				indexTestBlocks(t, s, blks)
+				assert.NoError(t, s.PutBlock(blks[1], choices.Processing))
+			},
+			expected: []string{"has status Processing"},
+		},
+		{
+			name: "wrong parent",
+			setup: func(t *testing.T, s proposerstate.State, blks []statelessblock.Block) {
+				indexTestBlocks(t, s, blks)
+				otherBlks := newTestBlocks(t, 1)
+				assert.NoError(t, s.PutBlock(otherBlks[0], choices.Accepted))
+				assert.NoError(t, s.SetBlockIDAtHeight(testForkHeight+2, otherBlks[0].ID()))
+			},
+			expected: []string{"isn't a child", "isn't a child"},
+		},
+		{
+			name: "gap",
+			setup: func(t *testing.T, s proposerstate.State, blks []statelessblock.Block) {
+				indexTestBlocks(t, s, blks[:2])
+				for _, blk := range blks[2:] {
+					assert.NoError(t, s.PutBlock(blk, choices.Accepted))
+				}
+				assert.NoError(t, s.SetBlockIDAtHeight(testForkHeight+3, blks[3].ID()))
+				assert.NoError(t, s.SetLastAccepted(blks[3].ID()))
+			},
+			expected: []string{"doesn't include the last accepted block"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := proposerstate.New(versiondb.New(memdb.New()))
+			test.setup(t, s, newTestBlocks(t, 4))
+
+			problems, err := verifyHeightIndex(s)
+			assert.NoError(t, err)
+			assertProblems(t, test.expected, problems)
+		})
+	}
+}
+
+// writeTestEVMState writes the block at height 1 of a coreth chain to [vmDB],
+// with a state of a few accounts, and marks it as accepted. Returns the
+// header of the block.
+func writeTestEVMState(t *testing.T, vmDB database.Database) *types.Header {
+	assert := assert.New(t)
+
+	chainDB := coreth.Database{Database: prefixdb.NewNested(ethDBPrefix, vmDB)}
+	stateDatabase := state.NewDatabase(chainDB)
+	stateDB, err := state.New(common.Hash{}, stateDatabase, nil)
+	assert.NoError(err)
+	for i := byte(1); i <= 20; i++ {
+		stateDB.SetBalance(common.Address{i}, big.NewInt(int64(i)))
+	}
+	root, err := stateDB.Commit(false)
+	assert.NoError(err)
+	assert.NoError(stateDatabase.TrieDB().Commit(root, false, nil))
+
+	header := &types.Header{
+		Number:     big.NewInt(1),
+		Root:       root,
+		Difficulty: big.NewInt(0),
+	}
+	rawdb.WriteHeader(chainDB, header)
+	rawdb.WriteCanonicalHash(chainDB, header.Hash(), 1)
+	assert.NoError(prefixdb.NewNested(corethAcceptedPrefix, vmDB).Put(lastAcceptedKey, header.Hash().Bytes()))
+	return header
+}
+
+// trieNodeKeys returns the keys of the trie nodes in [vmDB] other than [root]
+func trieNodeKeys(t *testing.T, vmDB database.Database, root common.Hash) [][]byte {
+	it := prefixdb.NewNested(ethDBPrefix, vmDB).NewIterator()
+	defer it.Release()
+
+	var keys [][]byte
+	for it.Next() {
+		if key := it.Key(); rawdb.KeyCategory(key) == "Trie nodes" && common.BytesToHash(key) != root {
+			keys = append(keys, common.CopyBytes(key))
+		}
+	}
+	assert.NoError(t, it.Error())
+	return keys
+}
+
+func TestVerifyEVMState(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(t *testing.T, vmDB database.Database)
+		expected []string
+	}{
+		{
+			name:  "genesis",
+			setup: func(*testing.T, database.Database) {},
+		},
+		{
+			name:  "complete",
+			setup: func(t *testing.T, vmDB database.Database) { writeTestEVMState(t, vmDB) },
+		},
+		{
+			name: "corrupted last accepted hash",
+			setup: func(t *testing.T, vmDB database.Database) {
+				writeTestEVMState(t, vmDB)
+				assert.NoError(t, prefixdb.NewNested(corethAcceptedPrefix, vmDB).Put(lastAcceptedKey, []byte{1, 2, 3}))
+			},
+			expected: []string{"has 3 bytes"},
+		},
+		{
+			name: "missing header",
+			setup: func(t *testing.T, vmDB database.Database) {
+				header := writeTestEVMState(t, vmDB)
+				rawdb.DeleteHeader(coreth.Database{Database: prefixdb.NewNested(ethDBPrefix, vmDB)}, header.Hash(), 1)
+			},
+			expected: []string{"has no number"},
+		},
+		{
+			name: "not canonical",
+			setup: func(t *testing.T, vmDB database.Database) {
+				writeTestEVMState(t, vmDB)
+				rawdb.WriteCanonicalHash(coreth.Database{Database: prefixdb.NewNested(ethDBPrefix, vmDB)}, common.Hash{1}, 1)
+			},
+			expected: []string{"canonical block at height 1"},
+		},
+		{
+			name: "missing state root",
+			setup: func(t *testing.T, vmDB database.Database) {
+				header := writeTestEVMState(t, vmDB)
+				assert.NoError(t, prefixdb.NewNested(ethDBPrefix, vmDB).Delete(header.Root.Bytes()))
+			},
+			expected: []string{"is missing"},
+		},
+		{
+			name: "missing trie node",
+			setup: func(t *testing.T, vmDB database.Database) {
+				header := writeTestEVMState(t, vmDB)
+				keys := trieNodeKeys(t, vmDB, header.Root)
+				if assert.NotEmpty(t, keys) {
+					assert.NoError(t, prefixdb.NewNested(ethDBPrefix, vmDB).Delete(keys[0]))
+				}
+			},
+			expected: []string{"is incomplete"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			vmDB := memdb.New()
+			test.setup(t, vmDB)
+
+			problems, err := verifyEVMState(vmDB, logging.NoLog{})
+			assert.NoError(t, err)
+			assertProblems(t, test.expected, problems)
+		})
+	}
+}
@@ -0,0 +1,163 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+// Package journal implements the journal command, which exports the consensus
+// poll journal of a chain of a stopped node as JSON.
+package journal
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/flare-foundation/flare/app/process"
+	"github.com/flare-foundation/flare/chains"
+	"github.com/flare-foundation/flare/config"
+	"github.com/flare-foundation/flare/database/prefixdb"
+	"github.com/flare-foundation/flare/genesis"
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/snow/consensus/snowman/poll"
+	"github.com/flare-foundation/flare/utils/constants"
+	"github.com/flare-foundation/flare/utils/logging"
+)
+
+const (
+	// Command is the first argument that selects the journal command
+	Command = "journal"
+
+	exportCommand = "export"
+
+	chainKey = "journal-chain"
+	fileKey  = "journal-file"
+	limitKey = "journal-limit"
+)
+
+var errMissingChain = fmt.Errorf("--%s must be specified", chainKey)
+
+// Usage describes the journal command
+const Usage = `usage: %[1]s journal export --journal-chain=<alias or ID> [--journal-file=<file>] [--journal-limit=<polls>] [node flags]
+
+The node must not be running. The node flags select the network and database
+in the same way as when running the node. The journal is written to stdout if
+no file is given.
+`
+
+// Run executes the journal command with the arguments following [Command]
+// and returns the exit code of the process.
+func Run(args []string) int {
+	if len(args) == 0 || args[0] != exportCommand {
+		fmt.Fprintf(os.Stderr, Usage, constants.AppName)
+		return 1
+	}
+
+	fs := config.BuildFlagSet()
+	addFlags(fs)
+	v, err := config.BuildViper(fs, args[1:])
+	if errors.Is(err, pflag.ErrHelp) {
+		fmt.Fprintf(os.Stderr, Usage, constants.AppName)
+		return 0
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "couldn't configure flags: %s\n", err)
+		return 1
+	}
+
+	if err := run(v); err != nil {
+		fmt.Fprintf(os.Stderr, "journal %s failed: %s\n", args[0], err)
+		return 1
+	}
+	return 0
+}
+
+func addFlags(fs *flag.FlagSet) {
+	fs.String(chainKey, "", "Alias or ID of the chain whose poll journal is exported")
+	fs.String(fileKey, "", "Path of the file the journal is written to. Defaults to stdout")
+	fs.Uint64(limitKey, 0, "Maximum number of polls to export. If 0, every poll in the journal is exported")
+}
+
+func run(v *viper.Viper) error {
+	chain := v.GetString(chainKey)
+	if chain == "" {
+		return errMissingChain
+	}
+
+	// The build directory is only used to find plugins, which are not loaded
+	// by the journal command.
+	nodeConfig, err := config.GetNodeConfig(v, os.ExpandEnv(v.GetString(config.BuildDirKey)))
+	if err != nil {
+		return fmt.Errorf("couldn't load node config: %w", err)
+	}
+	chainID, err := lookupChain(nodeConfig.GenesisBytes, chain)
+	if err != nil {
+		return err
+	}
+
+	// The journal may be written to stdout, so logs are only written to the
+	// log directory.
+	nodeConfig.LoggingConfig.DisplayLevel = logging.Off
+	logFactory := logging.NewFactory(nodeConfig.LoggingConfig)
+	defer logFactory.Close()
+	log, err := logFactory.Make(Command)
+	if err != nil {
+		return err
+	}
+
+	dbManager, err := process.NewDBManager(nodeConfig.DatabaseConfig, log)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := dbManager.Close(); err != nil {
+			log.Warn("failed to close the node's DB: %s", err)
+		}
+	}()
+
+	db := dbManager.NewPrefixDBManager(chainID[:]).Current().Database
+	journal, err := poll.LoadJournal(prefixdb.New(chains.PollJournalPrefix, db))
+	if err != nil {
+		return err
+	}
+	entries, err := journal.Entries(v.GetUint64(limitKey))
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = os.Stdout
+	if file := os.ExpandEnv(v.GetString(fileKey)); file != "" {
+		f, err := os.OpenFile(file, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(entries)
+}
+
+// lookupChain returns the ID of the chain with the alias or ID [chain]
+func lookupChain(genesisBytes []byte, chain string) (ids.ID, error) {
+	_, chainAliases, err := genesis.Aliases(genesisBytes)
+	if err != nil {
+		return ids.Empty, err
+	}
+	for chainID, aliases := range chainAliases {
+		for _, alias := range aliases {
+			if alias == chain {
+				return chainID, nil
+			}
+		}
+	}
+	chainID, err := ids.FromString(chain)
+	if err != nil {
+		return ids.Empty, fmt.Errorf("unknown chain %q", chain)
+	}
+	return chainID, nil
+}
@@ -9,9 +9,11 @@ import (
 	"sync"
 
 	"github.com/flare-foundation/flare/app"
+	"github.com/flare-foundation/flare/database"
 	"github.com/flare-foundation/flare/database/leveldb"
 	"github.com/flare-foundation/flare/database/manager"
 	"github.com/flare-foundation/flare/database/memdb"
+	"github.com/flare-foundation/flare/database/pebbledb"
 	"github.com/flare-foundation/flare/database/rocksdb"
 	"github.com/flare-foundation/flare/nat"
 	"github.com/flare-foundation/flare/node"
@@ -76,24 +78,7 @@ func (p *process) Start() error {
 	}
 
 	// start the db manager
-	var dbManager manager.Manager
-	switch p.config.DatabaseConfig.Name {
-	case rocksdb.Name:
-		path := filepath.Join(p.config.DatabaseConfig.Path, rocksdb.Name)
-		dbManager, err = manager.NewRocksDB(path, p.config.DatabaseConfig.Config, log, version.CurrentDatabase)
-	case leveldb.Name:
-		dbManager, err = manager.NewLevelDB(p.config.DatabaseConfig.Path, p.config.DatabaseConfig.Config, log, version.CurrentDatabase)
-	case memdb.Name:
-		dbManager = manager.NewMemDB(version.CurrentDatabase)
-	default:
-		err = fmt.Errorf(
-			"db-type was %q but should have been one of {%s, %s, %s}",
-			p.config.DatabaseConfig.Name,
-			leveldb.Name,
-			rocksdb.Name,
-			memdb.Name,
-		)
-	}
+	dbManager, err := NewDBManager(p.config.DatabaseConfig, log)
 	if err != nil {
 		log.Fatal("couldn't create %q db manager at %s: %s", p.config.DatabaseConfig.Name, p.config.DatabaseConfig.Path, err)
 		logFactory.Close()
@@ -217,3 +202,81 @@ func (p *process) ExitCode() (int, error) {
 	p.exitWG.Wait()
 	return p.node.ExitCode(), nil
 }
+
+// NewDBManager opens the database described by [config]
+func NewDBManager(config node.DatabaseConfig, log logging.Logger) (manager.Manager, error) {
+	if config.MigrateFrom != "" {
+		if err := migrateDB(config, log); err != nil {
+			return nil, fmt.Errorf("couldn't migrate %s database to %s: %w", config.MigrateFrom, config.Name, err)
+		}
+	}
+
+	switch config.Name {
+	case rocksdb.Name:
+		path := filepath.Join(config.Path, rocksdb.Name)
+		return manager.NewRocksDB(path, config.Config, log, version.CurrentDatabase)
+	case pebbledb.Name:
+		path := filepath.Join(config.Path, pebbledb.Name)
+		return manager.NewPebbleDB(path, config.Config, log, version.CurrentDatabase)
+	case leveldb.Name:
+		return manager.NewLevelDB(config.Path, config.Config, log, version.CurrentDatabase)
+	case memdb.Name:
+		return manager.NewMemDB(version.CurrentDatabase), nil
+	default:
+		return nil, fmt.Errorf(
+			"db-type was %q but should have been one of {%s, %s, %s, %s}",
+			config.Name,
+			leveldb.Name,
+			rocksdb.Name,
+			pebbledb.Name,
+			memdb.Name,
+		)
+	}
+}
+
+// migrateDB copies the [config.MigrateFrom] database into the [config.Name]
+// database, if the latter doesn't exist yet
+func migrateDB(config node.DatabaseConfig, log logging.Logger) error {
+	newSrcDB, srcPath, err := PersistentDB(config.MigrateFrom, config.Path)
+	if err != nil {
+		return fmt.Errorf("db-migrate-from: %w", err)
+	}
+	newDstDB, dstPath, err := PersistentDB(config.Name, config.Path)
+	if err != nil {
+		return fmt.Errorf("db-type: %w", err)
+	}
+	if srcPath == dstPath {
+		return fmt.Errorf("db-migrate-from must differ from db-type %q", config.Name)
+	}
+	_, err = manager.Migrate(
+		newSrcDB,
+		srcPath,
+		newDstDB,
+		dstPath,
+		config.Config,
+		log,
+		version.CurrentDatabase,
+	)
+	return err
+}
+
+// PersistentDB returns the constructor of the [name] database, and the
+// directory its versions are stored in
+func PersistentDB(name string, path string) (func(string, []byte, logging.Logger) (database.Database, error), string, error) {
+	switch name {
+	case rocksdb.Name:
+		return rocksdb.New, filepath.Join(path, rocksdb.Name), nil
+	case pebbledb.Name:
+		return pebbledb.New, filepath.Join(path, pebbledb.Name), nil
+	case leveldb.Name:
+		return leveldb.New, path, nil
+	default:
+		return nil, "", fmt.Errorf(
+			"%q should have been one of {%s, %s, %s}",
+			name,
+			leveldb.Name,
+			rocksdb.Name,
+			pebbledb.Name,
+		)
+	}
+}
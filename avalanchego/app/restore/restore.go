@@ -0,0 +1,162 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+// Package restore implements the restore command, which replaces the database
+// of a stopped node with a backup created by admin.backupDatabase.
+package restore
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/flare-foundation/flare/app/process"
+	"github.com/flare-foundation/flare/config"
+	"github.com/flare-foundation/flare/database/backup"
+	"github.com/flare-foundation/flare/utils/constants"
+	"github.com/flare-foundation/flare/utils/logging"
+	"github.com/flare-foundation/flare/version"
+)
+
+const (
+	// Command is the first argument that selects the restore command
+	Command = "restore"
+
+	dirKey = "restore-dir"
+
+	// Suffix of the directory a backup is loaded into before it replaces
+	// the database
+	restoringSuffix = ".restoring"
+)
+
+var errMissingDir = fmt.Errorf("--%s must be specified", dirKey)
+
+// Usage describes the restore command
+const Usage = `usage: %[1]s restore --restore-dir=<backup directory> [node flags]
+
+The node must not be running. The node flags select the database in the same
+way as when running the node. The backup may be restored into a database of any
+persistent db-type. The replaced database is kept next to the restored one.
+`
+
+// Run executes the restore command with the arguments following [Command]
+// and returns the exit code of the process.
+func Run(args []string) int {
+	fs := config.BuildFlagSet()
+	addFlags(fs)
+	v, err := config.BuildViper(fs, args)
+	if errors.Is(err, pflag.ErrHelp) {
+		fmt.Fprintf(os.Stderr, Usage, constants.AppName)
+		return 0
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "couldn't configure flags: %s\n", err)
+		return 1
+	}
+
+	if err := run(v); err != nil {
+		fmt.Fprintf(os.Stderr, "restore failed: %s\n", err)
+		return 1
+	}
+	return 0
+}
+
+func addFlags(fs *flag.FlagSet) {
+	fs.String(dirKey, "", "Directory of the backup to restore")
+}
+
+func run(v *viper.Viper) error {
+	dir := os.ExpandEnv(v.GetString(dirKey))
+	if dir == "" {
+		return errMissingDir
+	}
+
+	// The build directory is only used to find plugins, which are not loaded
+	// by the restore command.
+	nodeConfig, err := config.GetNodeConfig(v, os.ExpandEnv(v.GetString(config.BuildDirKey)))
+	if err != nil {
+		return fmt.Errorf("couldn't load node config: %w", err)
+	}
+	logFactory := logging.NewFactory(nodeConfig.LoggingConfig)
+	defer logFactory.Close()
+	log, err := logFactory.Make(Command)
+	if err != nil {
+		return err
+	}
+
+	dbConfig := nodeConfig.DatabaseConfig
+	newDB, dbDirPath, err := process.PersistentDB(dbConfig.Name, dbConfig.Path)
+	if err != nil {
+		return fmt.Errorf("db-type: %w", err)
+	}
+
+	log.Info("verifying backup in %s", dir)
+	manifest, err := backup.Verify(dir, version.CurrentDatabase)
+	if err != nil {
+		return fmt.Errorf("couldn't verify backup: %w", err)
+	}
+
+	dstPath := filepath.Join(dbDirPath, version.CurrentDatabase.String())
+	exists, err := pathExists(dstPath)
+	if err != nil {
+		return err
+	}
+	if exists {
+		// Opening the database fails if the node is using it
+		db, err := newDB(dstPath, dbConfig.Config, log)
+		if err != nil {
+			return fmt.Errorf("couldn't open the database, the node may be running: %w", err)
+		}
+		if err := db.Close(); err != nil {
+			return err
+		}
+	}
+
+	tmpPath := dstPath + restoringSuffix
+	if err := os.RemoveAll(tmpPath); err != nil {
+		return err
+	}
+	db, err := newDB(tmpPath, dbConfig.Config, log)
+	if err != nil {
+		return err
+	}
+	log.Info("loading %d keys from backup created at %s", manifest.NumKeys, manifest.Time)
+	if _, err := backup.Load(dir, db); err != nil {
+		_ = db.Close()
+		return fmt.Errorf("couldn't load backup: %w", err)
+	}
+	if err := db.Close(); err != nil {
+		return err
+	}
+
+	if exists {
+		oldPath := fmt.Sprintf("%s.pre-restore-%d", dstPath, time.Now().Unix())
+		if err := os.Rename(dstPath, oldPath); err != nil {
+			return err
+		}
+		log.Info("moved the replaced database to %s", oldPath)
+	}
+	if err := os.Rename(tmpPath, dstPath); err != nil {
+		return err
+	}
+	log.Info("restored backup into %s", dstPath)
+	return nil
+}
+
+func pathExists(path string) (bool, error) {
+	_, err := os.Stat(path)
+	switch {
+	case err == nil:
+		return true, nil
+	case os.IsNotExist(err):
+		return false, nil
+	default:
+		return false, err
+	}
+}
@@ -0,0 +1,328 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+// Package signer implements the signer command, which is a reference remote
+// signer. It serves signatures by secp256k1 keys over gRPC, so that the keys
+// can be held by a process other than the node.
+package signer
+
+import (
+	"bufio"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/flare-foundation/flare/api/proto/gsignerproto"
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/utils/constants"
+	"github.com/flare-foundation/flare/utils/crypto"
+	"github.com/flare-foundation/flare/utils/formatting"
+	"github.com/flare-foundation/flare/utils/hashing"
+	"github.com/flare-foundation/flare/utils/logging"
+	"github.com/flare-foundation/flare/vms/components/keystore"
+	"github.com/flare-foundation/flare/vms/components/keystore/gsigner"
+	"github.com/flare-foundation/flare/vms/secp256k1fx"
+)
+
+const (
+	// Command is the first argument that selects the signer command
+	Command = "signer"
+
+	keysFileKey         = "signer-keys-file"
+	listenAddressKey    = "signer-listen-address"
+	allowedAddressesKey = "signer-allowed-addresses"
+	allowedChainsKey    = "signer-allowed-chains"
+	tlsCertFileKey      = "signer-tls-cert-file"
+	tlsKeyFileKey       = "signer-tls-key-file"
+	tlsClientCAFileKey  = "signer-tls-client-ca-file"
+)
+
+var (
+	errMissingKeysFile = fmt.Errorf("--%s must be specified", keysFileKey)
+	errNoKeys          = errors.New("the keys file doesn't contain any keys")
+	errNotAllowed      = errors.New("signing is not allowed for the address")
+	errChainNotAllowed = errors.New("signing is not allowed for the transactions of the chain")
+	errPartialTLS      = fmt.Errorf("--%s, --%s and --%s must be specified together", tlsCertFileKey, tlsKeyFileKey, tlsClientCAFileKey)
+	errNotLoopback     = fmt.Errorf("--%s must be a loopback address unless --%s, --%s and --%s are specified", listenAddressKey, tlsCertFileKey, tlsKeyFileKey, tlsClientCAFileKey)
+	errNoClientCAs     = errors.New("the client CA file doesn't contain any certificates")
+)
+
+// Usage describes the signer command
+const Usage = `usage: %[1]s signer --signer-keys-file=<file> [--signer-listen-address=<address>] [--signer-allowed-addresses=<addresses>]
+       [--signer-allowed-chains=<chain IDs>] [--signer-tls-cert-file=<file> --signer-tls-key-file=<file> --signer-tls-client-ca-file=<file>]
+
+The keys file contains one private key per line, formatted like the keys that
+are exported by the keystore APIs of the chains (PrivateKey-...). Empty lines
+and lines starting with # are ignored. If allowed addresses are given, only
+transactions for those addresses are signed. If allowed chains are given, only
+transactions issued on those chains are signed.
+
+Without TLS files, the signer only listens on loopback addresses. With them,
+clients must present a certificate signed by the client CA.
+`
+
+// Run executes the signer command with the arguments following [Command]
+// and returns the exit code of the process.
+func Run(args []string) int {
+	// The signer doesn't run a node, so none of the node flags are accepted
+	fs := pflag.NewFlagSet(Command, pflag.ContinueOnError)
+	addFlags(fs)
+	v, err := buildViper(fs, args)
+	if errors.Is(err, pflag.ErrHelp) {
+		fmt.Fprintf(os.Stderr, Usage, constants.AppName)
+		return 0
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "couldn't configure flags: %s\n", err)
+		return 1
+	}
+
+	if err := run(v); err != nil {
+		fmt.Fprintf(os.Stderr, "signer failed: %s\n", err)
+		return 1
+	}
+	return 0
+}
+
+func addFlags(fs *pflag.FlagSet) {
+	fs.String(keysFileKey, "", "File that contains the private keys to sign with")
+	fs.String(listenAddressKey, "127.0.0.1:9670", "Address the signer serves gRPC requests on")
+	fs.String(allowedAddressesKey, "", "Comma separated list of the addresses, such as X-flare1..., that transactions may be signed for. If empty, transactions may be signed for every key")
+	fs.String(allowedChainsKey, "", "Comma separated list of the IDs of the chains whose transactions may be signed. If empty, transactions of every chain may be signed")
+	fs.String(tlsCertFileKey, "", "TLS certificate file of the signer. Required to listen on a non-loopback address")
+	fs.String(tlsKeyFileKey, "", "TLS private key file of the signer. Required to listen on a non-loopback address")
+	fs.String(tlsClientCAFileKey, "", "File of the CA certificates that client certificates must be signed by. Required to listen on a non-loopback address")
+}
+
+func buildViper(fs *pflag.FlagSet, args []string) (*viper.Viper, error) {
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	v := viper.New()
+	return v, v.BindPFlags(fs)
+}
+
+func run(v *viper.Viper) error {
+	keysFile := os.ExpandEnv(v.GetString(keysFileKey))
+	if keysFile == "" {
+		return errMissingKeysFile
+	}
+	keychain, err := readKeys(keysFile)
+	if err != nil {
+		return fmt.Errorf("couldn't read keys: %w", err)
+	}
+	allowed, err := parseAddresses(v.GetString(allowedAddressesKey))
+	if err != nil {
+		return fmt.Errorf("couldn't parse allowed addresses: %w", err)
+	}
+	allowedChains, err := parseChainIDs(v.GetString(allowedChainsKey))
+	if err != nil {
+		return fmt.Errorf("couldn't parse allowed chains: %w", err)
+	}
+
+	logConfig, err := logging.DefaultConfig()
+	if err != nil {
+		return err
+	}
+	logFactory := logging.NewFactory(logConfig)
+	defer logFactory.Close()
+	log, err := logFactory.Make(Command)
+	if err != nil {
+		return err
+	}
+
+	signer := &policySigner{
+		ChainSigner:   keystore.NewAnyChainSigner(keystore.NewKeychainSigner(keychain)),
+		log:           log,
+		allowed:       allowed,
+		allowedChains: allowedChains,
+	}
+
+	listenAddress := v.GetString(listenAddressKey)
+	serverOpts, err := serverOptions(
+		listenAddress,
+		os.ExpandEnv(v.GetString(tlsCertFileKey)),
+		os.ExpandEnv(v.GetString(tlsKeyFileKey)),
+		os.ExpandEnv(v.GetString(tlsClientCAFileKey)),
+	)
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", listenAddress)
+	if err != nil {
+		return err
+	}
+	server := grpc.NewServer(serverOpts...)
+	gsignerproto.RegisterSignerServer(server, gsigner.NewServer(signer))
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-signals
+		server.GracefulStop()
+	}()
+
+	log.Info("serving %d keys on %s", keychain.Addrs.Len(), listener.Addr())
+	return server.Serve(listener)
+}
+
+// serverOptions returns the options of a server that listens on
+// [listenAddress]. If the TLS files are given, clients must authenticate with
+// a certificate signed by a CA of [clientCAFile]. Otherwise [listenAddress]
+// must be a loopback address, as the signatures would be served to anyone who
+// can connect.
+func serverOptions(listenAddress, certFile, keyFile, clientCAFile string) ([]grpc.ServerOption, error) {
+	if certFile == "" && keyFile == "" && clientCAFile == "" {
+		loopback, err := gsigner.IsLoopback(listenAddress)
+		if err != nil {
+			return nil, err
+		}
+		if !loopback {
+			return nil, errNotLoopback
+		}
+		return nil, nil
+	}
+	if certFile == "" || keyFile == "" || clientCAFile == "" {
+		return nil, errPartialTLS
+	}
+
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't load TLS certificate: %w", err)
+	}
+	caBytes, err := ioutil.ReadFile(clientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read client CA file: %w", err)
+	}
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(caBytes) {
+		return nil, errNoClientCAs
+	}
+	return []grpc.ServerOption{
+		grpc.Creds(credentials.NewTLS(&tls.Config{
+			Certificates: []tls.Certificate{cert},
+			ClientAuth:   tls.RequireAndVerifyClientCert,
+			ClientCAs:    clientCAs,
+			MinVersion:   tls.VersionTLS12,
+		})),
+	}, nil
+}
+
+// readKeys returns a keychain of the keys in [path]
+func readKeys(path string) (*secp256k1fx.Keychain, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	factory := crypto.FactorySECP256K1R{}
+	keychain := secp256k1fx.NewKeychain()
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		keyStr := strings.TrimSpace(scanner.Text())
+		if keyStr == "" || strings.HasPrefix(keyStr, "#") {
+			continue
+		}
+		if !strings.HasPrefix(keyStr, constants.SecretKeyPrefix) {
+			return nil, fmt.Errorf("private key on line %d is missing %s prefix", line, constants.SecretKeyPrefix)
+		}
+		keyBytes, err := formatting.Decode(formatting.CB58, strings.TrimPrefix(keyStr, constants.SecretKeyPrefix))
+		if err != nil {
+			return nil, fmt.Errorf("problem parsing private key on line %d: %w", line, err)
+		}
+		sk, err := factory.ToPrivateKey(keyBytes)
+		if err != nil {
+			return nil, fmt.Errorf("problem parsing private key on line %d: %w", line, err)
+		}
+		keychain.Add(sk.(*crypto.PrivateKeySECP256K1R))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if keychain.Addrs.Len() == 0 {
+		return nil, errNoKeys
+	}
+	return keychain, nil
+}
+
+// parseAddresses returns the IDs of the comma separated addresses in
+// [addressesStr]. Returns nil if [addressesStr] is empty.
+func parseAddresses(addressesStr string) (ids.ShortSet, error) {
+	if addressesStr == "" {
+		return nil, nil
+	}
+	addresses := ids.ShortSet{}
+	for _, addressStr := range strings.Split(addressesStr, ",") {
+		_, _, addressBytes, err := formatting.ParseAddress(strings.TrimSpace(addressStr))
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse address %q: %w", addressStr, err)
+		}
+		address, err := ids.ToShortID(addressBytes)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse address %q: %w", addressStr, err)
+		}
+		addresses.Add(address)
+	}
+	return addresses, nil
+}
+
+// parseChainIDs returns the comma separated chain IDs in [chainIDsStr].
+// Returns nil if [chainIDsStr] is empty.
+func parseChainIDs(chainIDsStr string) (ids.Set, error) {
+	if chainIDsStr == "" {
+		return nil, nil
+	}
+	chainIDs := ids.Set{}
+	for _, chainIDStr := range strings.Split(chainIDsStr, ",") {
+		chainID, err := ids.FromString(strings.TrimSpace(chainIDStr))
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse chain ID %q: %w", chainIDStr, err)
+		}
+		chainIDs.Add(chainID)
+	}
+	return chainIDs, nil
+}
+
+// policySigner only signs the transactions of the allowed chains for the
+// allowed addresses, and logs every signature
+type policySigner struct {
+	keystore.ChainSigner
+	log logging.Logger
+
+	// If nil, every address is allowed
+	allowed ids.ShortSet
+	// If nil, every chain is allowed
+	allowedChains ids.Set
+}
+
+func (s *policySigner) SignChainTx(chainID ids.ID, address ids.ShortID, unsignedTx []byte) ([]byte, error) {
+	txHash := hashing.ComputeHash256(unsignedTx)
+	if s.allowedChains != nil && !s.allowedChains.Contains(chainID) {
+		s.log.Warn("refused to sign transaction %x of chain %s for %s", txHash, chainID, address)
+		return nil, fmt.Errorf("%w %s", errChainNotAllowed, chainID)
+	}
+	if s.allowed != nil && !s.allowed.Contains(address) {
+		s.log.Warn("refused to sign transaction %x of chain %s for %s", txHash, chainID, address)
+		return nil, fmt.Errorf("%w %s", errNotAllowed, address)
+	}
+	signature, err := s.ChainSigner.SignChainTx(chainID, address, unsignedTx)
+	if err != nil {
+		return nil, err
+	}
+	s.log.Info("signed transaction %x of chain %s for %s", txHash, chainID, address)
+	return signature, nil
+}
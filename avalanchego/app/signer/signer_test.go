@@ -0,0 +1,212 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package signer
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+
+	"github.com/flare-foundation/flare/api/proto/gsignerproto"
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/utils/crypto"
+	"github.com/flare-foundation/flare/utils/logging"
+	"github.com/flare-foundation/flare/vms/components/keystore"
+	"github.com/flare-foundation/flare/vms/components/keystore/gsigner"
+	"github.com/flare-foundation/flare/vms/secp256k1fx"
+)
+
+func TestServerOptionsWithoutTLS(t *testing.T) {
+	tests := []struct {
+		address string
+		err     bool
+	}{
+		{address: "127.0.0.1:9670"},
+		{address: "localhost:9670"},
+		{address: "[::1]:9670"},
+		{address: "0.0.0.0:9670", err: true},
+		{address: ":9670", err: true},
+		{address: "10.0.0.1:9670", err: true},
+		{address: "signer.example.com:9670", err: true},
+		{address: "127.0.0.1", err: true},
+	}
+	for _, test := range tests {
+		t.Run(test.address, func(t *testing.T) {
+			_, err := serverOptions(test.address, "", "", "")
+			if test.err {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestServerOptionsPartialTLS(t *testing.T) {
+	_, err := serverOptions("0.0.0.0:9670", "cert.pem", "key.pem", "")
+	assert.ErrorIs(t, err, errPartialTLS)
+}
+
+// newTestCert returns a certificate signed by [parent], or a self-signed CA
+// certificate if [parent] is nil
+func newTestCert(t *testing.T, parent *tls.Certificate) *tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+	}
+	signerCert, signerKey := template, interface{}(key)
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+	} else {
+		signerCert, signerKey = parent.Leaf, parent.PrivateKey
+	}
+	certBytes, err := x509.CreateCertificate(rand.Reader, template, signerCert, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(certBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &tls.Certificate{
+		Certificate: [][]byte{certBytes},
+		PrivateKey:  key,
+		Leaf:        leaf,
+	}
+}
+
+// writeTestCert writes [cert] and its key as PEM files to [dir] and returns
+// their paths
+func writeTestCert(t *testing.T, dir, name string, cert *tls.Certificate) (string, string) {
+	keyBytes, err := x509.MarshalPKCS8PrivateKey(cert.PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile := filepath.Join(dir, name+".crt")
+	keyFile := filepath.Join(dir, name+".key")
+	if err := ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyBytes}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+func TestServerMutualTLS(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	ca := newTestCert(t, nil)
+	caFile, _ := writeTestCert(t, dir, "ca", ca)
+	certFile, keyFile := writeTestCert(t, dir, "server", newTestCert(t, ca))
+
+	// A non-loopback address is allowed with TLS
+	_, err := serverOptions("0.0.0.0:9670", certFile, keyFile, caFile)
+	assert.NoError(err)
+
+	serverOpts, err := serverOptions("127.0.0.1:0", certFile, keyFile, caFile)
+	assert.NoError(err)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(err)
+	keychain := secp256k1fx.NewKeychain()
+	sk, err := keychain.New()
+	assert.NoError(err)
+	server := grpc.NewServer(serverOpts...)
+	gsignerproto.RegisterSignerServer(server, gsigner.NewServer(keystore.NewAnyChainSigner(keystore.NewKeychainSigner(keychain))))
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	defer server.Stop()
+
+	publicKeys := func(config gsigner.Config) ([]*crypto.PublicKeySECP256K1R, error) {
+		config.Address = listener.Addr().String()
+		config.Timeout = 10 * time.Second
+		client, conn, err := gsigner.Dial(config)
+		if err != nil {
+			return nil, err
+		}
+		defer conn.Close()
+		return client.PublicKeys()
+	}
+
+	// Nodes with a certificate signed by the client CA are served
+	clientCertFile, clientKeyFile := writeTestCert(t, dir, "client", newTestCert(t, ca))
+	pks, err := publicKeys(gsigner.Config{CertFile: clientCertFile, KeyFile: clientKeyFile, CAFile: caFile})
+	assert.NoError(err)
+	assert.Len(pks, 1)
+	assert.Equal(sk.PublicKey().Address(), pks[0].Address())
+
+	// Nodes without a certificate, or with a certificate by another CA, aren't
+	_, err = publicKeys(gsigner.Config{})
+	assert.Error(err)
+	otherCertFile, otherKeyFile := writeTestCert(t, dir, "other", newTestCert(t, newTestCert(t, nil)))
+	_, err = publicKeys(gsigner.Config{CertFile: otherCertFile, KeyFile: otherKeyFile, CAFile: caFile})
+	assert.Error(err)
+}
+
+func TestPolicySigner(t *testing.T) {
+	assert := assert.New(t)
+
+	keychain := secp256k1fx.NewKeychain()
+	allowedKey, err := keychain.New()
+	assert.NoError(err)
+	otherKey, err := keychain.New()
+	assert.NoError(err)
+	allowedChainID := ids.GenerateTestID()
+	signer := &policySigner{
+		ChainSigner:   keystore.NewAnyChainSigner(keystore.NewKeychainSigner(keychain)),
+		log:           logging.NoLog{},
+		allowed:       ids.ShortSet{},
+		allowedChains: ids.Set{},
+	}
+	signer.allowed.Add(allowedKey.PublicKey().Address())
+	signer.allowedChains.Add(allowedChainID)
+
+	unsignedTx := []byte("unsigned tx")
+	_, err = signer.SignChainTx(allowedChainID, allowedKey.PublicKey().Address(), unsignedTx)
+	assert.NoError(err)
+	_, err = signer.SignChainTx(allowedChainID, otherKey.PublicKey().Address(), unsignedTx)
+	assert.ErrorIs(err, errNotAllowed)
+	_, err = signer.SignChainTx(ids.GenerateTestID(), allowedKey.PublicKey().Address(), unsignedTx)
+	assert.ErrorIs(err, errChainNotAllowed)
+}
+
+func TestParseChainIDs(t *testing.T) {
+	assert := assert.New(t)
+
+	chainIDs, err := parseChainIDs("")
+	assert.NoError(err)
+	assert.Nil(chainIDs)
+
+	chainID0, chainID1 := ids.GenerateTestID(), ids.GenerateTestID()
+	chainIDs, err = parseChainIDs(chainID0.String() + ", " + chainID1.String())
+	assert.NoError(err)
+	assert.True(chainIDs.Contains(chainID0))
+	assert.True(chainIDs.Contains(chainID1))
+
+	_, err = parseChainIDs("not a chain ID")
+	assert.Error(err)
+}
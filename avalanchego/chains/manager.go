@@ -22,8 +22,10 @@ import (
 	"github.com/flare-foundation/flare/ids"
 	"github.com/flare-foundation/flare/message"
 	"github.com/flare-foundation/flare/network"
+	"github.com/flare-foundation/flare/network/throttling"
 	"github.com/flare-foundation/flare/snow"
 	"github.com/flare-foundation/flare/snow/consensus/snowball"
+	"github.com/flare-foundation/flare/snow/consensus/snowman/poll"
 	"github.com/flare-foundation/flare/snow/engine/avalanche/state"
 	"github.com/flare-foundation/flare/snow/engine/avalanche/vertex"
 	"github.com/flare-foundation/flare/snow/engine/common"
@@ -31,6 +33,7 @@ import (
 	"github.com/flare-foundation/flare/snow/engine/common/tracker"
 	"github.com/flare-foundation/flare/snow/engine/snowman/block"
 	"github.com/flare-foundation/flare/snow/networking/handler"
+	"github.com/flare-foundation/flare/snow/networking/reputation"
 	"github.com/flare-foundation/flare/snow/networking/router"
 	"github.com/flare-foundation/flare/snow/networking/sender"
 	"github.com/flare-foundation/flare/snow/networking/timeout"
@@ -62,15 +65,19 @@ var (
 	errUnknownVMType    = errors.New("the vm should have type avalanche.DAGVM or snowman.ChainVM")
 	errCreatePlatformVM = errors.New("attempted to create a chain running the PlatformVM")
 
+	// PollJournalPrefix is the prefix of the poll journal in the database of
+	// a chain
+	PollJournalPrefix = []byte("poll_journal")
+
 	_ Manager = &manager{}
 )
 
 // Manager manages the chains running on this node.
 // It can:
-//   * Create a chain
-//   * Add a registrant. When a chain is created, each registrant calls
+//   - Create a chain
+//   - Add a registrant. When a chain is created, each registrant calls
 //     RegisterChain with the new chain as the argument.
-//   * Manage the aliases of chains
+//   - Manage the aliases of chains
 type Manager interface {
 	ids.Aliaser
 
@@ -99,6 +106,10 @@ type Manager interface {
 	// Returns true iff the chain with the given ID exists and is finished bootstrapping
 	IsBootstrapped(ids.ID) bool
 
+	// Returns the poll journal of the chain with the given ID, if the chain
+	// exists and records its polls
+	PollJournal(ids.ID) (*poll.Journal, bool)
+
 	Shutdown()
 }
 
@@ -176,11 +187,29 @@ type ManagerConfig struct {
 	// This node will only consider the first [AncestorsMaxContainersReceived]
 	// containers in an ancestors message it receives.
 	BootstrapAncestorsMaxContainersReceived int
+	// Chain ID --> Block that the chain must go through. Only applies to
+	// snowman chains.
+	BootstrapCheckpoints map[ids.ID]smbootstrap.Checkpoint
 
 	ApricotPhase4Time            time.Time
 	ApricotPhase4MinPChainHeight uint64
 
 	ResetProposerVMHeightIndex bool
+
+	// Number of polls kept in the poll journal of each snowman chain. If 0,
+	// polls aren't recorded.
+	PollJournalSize uint64
+
+	// Limits the unrequested inbound messages of each chain, unless the
+	// chain's subnet has its own config
+	ChainMsgThrottlerConfig throttling.ChainMsgThrottlerConfig
+
+	// Told about the peers that sent invalid blocks
+	Reputation reputation.Tracker
+
+	// True iff the chains in the primary network accept what a primary node
+	// accepted, rather than what consensus decides
+	Replica bool
 }
 
 type manager struct {
@@ -204,6 +233,11 @@ type manager struct {
 	// Value: The chain
 	chains map[ids.ID]handler.Handler
 
+	pollJournalsLock sync.Mutex
+	// Key: Chain's ID
+	// Value: The poll journal of the chain
+	pollJournals map[ids.ID]*poll.Journal
+
 	// snowman++ related interface to allow validators retrival
 	validatorState validators.State
 }
@@ -215,6 +249,7 @@ func New(config *ManagerConfig) Manager {
 		ManagerConfig: *config,
 		subnets:       make(map[ids.ID]Subnet),
 		chains:        make(map[ids.ID]handler.Handler),
+		pollJournals:  make(map[ids.ID]*poll.Journal),
 	}
 }
 
@@ -365,6 +400,7 @@ func (m *manager) buildChain(chainParams ChainParameters, sb Subnet) (*chain, er
 			BCLookup:     m,
 			SNLookup:     m,
 			Metrics:      vmMetrics,
+			ReadOnly:     m.Replica && chainParams.SubnetID == constants.PrimaryNetworkID,
 
 			ValidatorState:    m.validatorState,
 			StakingCertLeaf:   m.StakingCert.Leaf,
@@ -429,13 +465,18 @@ func (m *manager) buildChain(chainParams ChainParameters, sb Subnet) (*chain, er
 	}
 
 	// The validators of this blockchain
-	validators, ok := m.Validators.GetValidators()
+	vdrs, ok := m.Validators.GetValidators()
 	if !ok {
 		return nil, fmt.Errorf("couldn't get validator set of network with ID %d. The network may not exist", m.NetworkID)
 	}
 
-	beacons := validators
-	if chainParams.CustomBeacons != nil {
+	beacons := vdrs
+	switch {
+	case m.Replica && chainParams.SubnetID == constants.PrimaryNetworkID:
+		// A replica's chains are synced by following the primary, so they
+		// finish bootstrapping without asking anyone
+		beacons = validators.NewSet()
+	case chainParams.CustomBeacons != nil:
 		beacons = chainParams.CustomBeacons
 	}
 
@@ -447,7 +488,7 @@ func (m *manager) buildChain(chainParams ChainParameters, sb Subnet) (*chain, er
 		chain, err = m.createAvalancheChain(
 			ctx,
 			chainParams.GenesisData,
-			validators,
+			vdrs,
 			beacons,
 			vm,
 			fxs,
@@ -462,7 +503,7 @@ func (m *manager) buildChain(chainParams ChainParameters, sb Subnet) (*chain, er
 		chain, err = m.createSnowmanChain(
 			ctx,
 			chainParams.GenesisData,
-			validators,
+			vdrs,
 			beacons,
 			vm,
 			fxs,
@@ -485,6 +526,25 @@ func (m *manager) buildChain(chainParams ChainParameters, sb Subnet) (*chain, er
 	return chain, nil
 }
 
+// chainMsgThrottlerConfig returns the config of the message throttler of a
+// chain in [subnetID]
+func (m *manager) chainMsgThrottlerConfig(subnetID ids.ID) throttling.ChainMsgThrottlerConfig {
+	if sbConfigs, ok := m.SubnetConfigs[subnetID]; ok && subnetID != constants.PrimaryNetworkID {
+		return sbConfigs.ChainMsgThrottlerConfig
+	}
+	return m.ChainMsgThrottlerConfig
+}
+
+// msgsFromVM returns the channel the handler of a chain in [subnetID] reads
+// the messages that the chain's VM sends on [msgChan] from. A replica's chains
+// don't build blocks, so their handlers don't read the messages.
+func (m *manager) msgsFromVM(subnetID ids.ID, msgChan chan common.Message) <-chan common.Message {
+	if m.Replica && subnetID == constants.PrimaryNetworkID {
+		return nil
+	}
+	return msgChan
+}
+
 // Implements Manager.AddRegistrant
 func (m *manager) AddRegistrant(r Registrant) { m.registrants = append(m.registrants, r) }
 
@@ -588,9 +648,10 @@ func (m *manager) createAvalancheChain(
 		m.MsgCreator,
 		ctx,
 		vdrs,
-		msgChan,
+		m.msgsFromVM(ctx.SubnetID, msgChan),
 		sb.afterBootstrapped(),
 		m.ConsensusGossipFrequency,
+		m.chainMsgThrottlerConfig(ctx.SubnetID),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("error initializing network handler: %w", err)
@@ -792,9 +853,10 @@ func (m *manager) createSnowmanChain(
 		m.MsgCreator,
 		ctx,
 		vdrs,
-		msgChan,
+		m.msgsFromVM(ctx.SubnetID, msgChan),
 		sb.afterBootstrapped(),
 		m.ConsensusGossipFrequency,
+		m.chainMsgThrottlerConfig(ctx.SubnetID),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't initialize message handler: %w", err)
@@ -833,6 +895,9 @@ func (m *manager) createSnowmanChain(
 		WeightTracker: weightTracker,
 		Bootstrapped:  m.unblockChains,
 	}
+	if checkpoint, ok := m.BootstrapCheckpoints[ctx.ChainID]; ok {
+		bootstrapCfg.Checkpoint = &checkpoint
+	}
 	bootstrapper, err := smbootstrap.New(
 		bootstrapCfg,
 		func(lastReqID uint32) error {
@@ -845,6 +910,17 @@ func (m *manager) createSnowmanChain(
 	handler.SetBootstrapper(bootstrapper)
 
 	// create engine gear
+	var pollJournal *poll.Journal
+	if m.PollJournalSize > 0 {
+		pollJournal, err = poll.NewJournal(prefixdb.New(PollJournalPrefix, db.Database), m.PollJournalSize)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't initialize poll journal: %w", err)
+		}
+		m.pollJournalsLock.Lock()
+		m.pollJournals[ctx.ChainID] = pollJournal
+		m.pollJournalsLock.Unlock()
+	}
+
 	engineConfig := smeng.Config{
 		Ctx:           bootstrapCfg.Ctx,
 		AllGetsServer: snowGetHandler,
@@ -853,6 +929,8 @@ func (m *manager) createSnowmanChain(
 		Validators:    vdrs,
 		Params:        consensusParams,
 		Consensus:     &smcon.Topological{},
+		PollJournal:   pollJournal,
+		Reputation:    m.Reputation,
 	}
 	engine, err := smeng.New(engineConfig)
 	if err != nil {
@@ -911,6 +989,14 @@ func (m *manager) IsBootstrapped(id ids.ID) bool {
 	return chain.Context().GetState() == snow.NormalOp
 }
 
+func (m *manager) PollJournal(id ids.ID) (*poll.Journal, bool) {
+	m.pollJournalsLock.Lock()
+	defer m.pollJournalsLock.Unlock()
+
+	journal, exists := m.pollJournals[id]
+	return journal, exists
+}
+
 // Shutdown stops all the chains
 func (m *manager) Shutdown() {
 	m.Log.Info("shutting down chain manager")
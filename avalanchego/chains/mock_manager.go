@@ -5,6 +5,7 @@ package chains
 
 import (
 	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/snow/consensus/snowman/poll"
 	"github.com/flare-foundation/flare/snow/networking/router"
 )
 
@@ -26,6 +27,8 @@ func (mm MockManager) Shutdown()                           {}
 func (mm MockManager) SubnetID(ids.ID) (ids.ID, error)     { return ids.ID{}, nil }
 func (mm MockManager) IsBootstrapped(ids.ID) bool          { return false }
 
+func (mm MockManager) PollJournal(ids.ID) (*poll.Journal, bool) { return nil, false }
+
 func (mm MockManager) Lookup(s string) (ids.ID, error) {
 	id, err := ids.FromString(s)
 	if err == nil {
@@ -7,6 +7,7 @@ import (
 	"sync"
 
 	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/network/throttling"
 	"github.com/flare-foundation/flare/snow/consensus/avalanche"
 	"github.com/flare-foundation/flare/snow/engine/common"
 )
@@ -29,6 +30,8 @@ type SubnetConfig struct {
 	// ValidatorOnly indicates that this Subnet's Chains are available to only subnet validators.
 	ValidatorOnly       bool                 `json:"validatorOnly"`
 	ConsensusParameters avalanche.Parameters `json:"consensusParameters"`
+	// Limits the unrequested inbound messages of each of the Subnet's Chains
+	ChainMsgThrottlerConfig throttling.ChainMsgThrottlerConfig `json:"chainMsgThrottlerConfig"`
 }
 
 type subnet struct {
@@ -14,6 +14,7 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -27,11 +28,15 @@ import (
 	"github.com/flare-foundation/flare/nat"
 	"github.com/flare-foundation/flare/network"
 	"github.com/flare-foundation/flare/network/dialer"
+	"github.com/flare-foundation/flare/network/peerfilter"
 	"github.com/flare-foundation/flare/network/throttling"
 	"github.com/flare-foundation/flare/node"
+	"github.com/flare-foundation/flare/replica"
 	"github.com/flare-foundation/flare/snow/consensus/avalanche"
 	"github.com/flare-foundation/flare/snow/consensus/snowball"
+	"github.com/flare-foundation/flare/snow/engine/snowman/bootstrap"
 	"github.com/flare-foundation/flare/snow/networking/benchlist"
+	"github.com/flare-foundation/flare/snow/networking/reputation"
 	"github.com/flare-foundation/flare/snow/networking/router"
 	"github.com/flare-foundation/flare/staking"
 	"github.com/flare-foundation/flare/utils"
@@ -43,7 +48,9 @@ import (
 	"github.com/flare-foundation/flare/utils/storage"
 	"github.com/flare-foundation/flare/utils/timer"
 	"github.com/flare-foundation/flare/utils/ulimit"
+	"github.com/flare-foundation/flare/utils/units"
 	"github.com/flare-foundation/flare/vms"
+	"github.com/flare-foundation/flare/vms/components/keystore/gsigner"
 )
 
 const (
@@ -71,6 +78,12 @@ var (
 	errCannotWhitelistPrimaryNetwork = errors.New("cannot whitelist primary network")
 	errStakingKeyContentUnset        = fmt.Errorf("%s key not set but %s set", StakingKeyContentKey, StakingCertContentKey)
 	errStakingCertContentUnset       = fmt.Errorf("%s key set but %s not set", StakingKeyContentKey, StakingCertContentKey)
+	errDuplicateCheckpoint           = errors.New("multiple bootstrap checkpoints for chain")
+	errClientCAWithoutHTTPS          = fmt.Errorf("%s set but %s not enabled", HTTPSClientCAFileKey, HTTPSEnabledKey)
+	errClientScopesWithoutClientCA   = fmt.Errorf("%s set but %s not set", HTTPSClientScopesFileKey, HTTPSClientCAFileKey)
+	errInvalidLogRotationMaxSize     = fmt.Errorf("%s must be > 0", LogRotationMaxSizeKey)
+	errInvalidLogRotationInterval    = fmt.Errorf("%s must be > 0", LogRotationIntervalKey)
+	errInvalidLogRotationMaxAge      = fmt.Errorf("%s must be >= 0", LogRotationMaxAgeKey)
 )
 
 func GetRunnerConfig(v *viper.Viper) (runner.Config, error) {
@@ -155,7 +168,27 @@ func getLoggingConfig(v *viper.Viper) (logging.Config, error) {
 		return loggingConfig, err
 	}
 	loggingConfig.DisplayHighlight, err = logging.ToHighlight(v.GetString(LogDisplayHighlightKey), os.Stdout.Fd())
-	return loggingConfig, err
+	if err != nil {
+		return loggingConfig, err
+	}
+	loggingConfig.LogFormat, err = logging.ToFormat(v.GetString(LogFormatKey))
+	if err != nil {
+		return loggingConfig, err
+	}
+	loggingConfig.FileSize = int(v.GetUint(LogRotationMaxSizeKey)) * units.MiB
+	if loggingConfig.FileSize <= 0 {
+		return loggingConfig, errInvalidLogRotationMaxSize
+	}
+	loggingConfig.RotationInterval = v.GetDuration(LogRotationIntervalKey)
+	if loggingConfig.RotationInterval <= 0 {
+		return loggingConfig, errInvalidLogRotationInterval
+	}
+	loggingConfig.RotationSize = int(v.GetUint(LogRotationMaxFilesKey))
+	loggingConfig.RotationMaxAge = v.GetDuration(LogRotationMaxAgeKey)
+	if loggingConfig.RotationMaxAge < 0 {
+		return loggingConfig, errInvalidLogRotationMaxAge
+	}
+	return loggingConfig, nil
 }
 
 func getAPIAuthConfig(v *viper.Viper) (node.APIAuthConfig, error) {
@@ -199,9 +232,11 @@ func getIPCConfig(v *viper.Viper) node.IPCConfig {
 
 func getHTTPConfig(v *viper.Viper) (node.HTTPConfig, error) {
 	var (
-		httpsKey  []byte
-		httpsCert []byte
-		err       error
+		httpsKey      []byte
+		httpsCert     []byte
+		httpsKeyFile  string
+		httpsCertFile string
+		err           error
 	)
 	switch {
 	case v.IsSet(HTTPSKeyContentKey):
@@ -211,8 +246,8 @@ func getHTTPConfig(v *viper.Viper) (node.HTTPConfig, error) {
 			return node.HTTPConfig{}, fmt.Errorf("unable to decode base64 content: %w", err)
 		}
 	case v.IsSet(HTTPSKeyFileKey):
-		httpsKeyFilepath := os.ExpandEnv(v.GetString(HTTPSKeyFileKey))
-		if httpsKey, err = ioutil.ReadFile(filepath.Clean(httpsKeyFilepath)); err != nil {
+		httpsKeyFile = os.ExpandEnv(v.GetString(HTTPSKeyFileKey))
+		if httpsKey, err = ioutil.ReadFile(filepath.Clean(httpsKeyFile)); err != nil {
 			return node.HTTPConfig{}, err
 		}
 	}
@@ -225,12 +260,30 @@ func getHTTPConfig(v *viper.Viper) (node.HTTPConfig, error) {
 			return node.HTTPConfig{}, fmt.Errorf("unable to decode base64 content: %w", err)
 		}
 	case v.IsSet(HTTPSCertFileKey):
-		httpsKeyFilepath := os.ExpandEnv(v.GetString(HTTPSCertFileKey))
-		if httpsCert, err = ioutil.ReadFile(filepath.Clean(httpsKeyFilepath)); err != nil {
+		httpsCertFile = os.ExpandEnv(v.GetString(HTTPSCertFileKey))
+		if httpsCert, err = ioutil.ReadFile(filepath.Clean(httpsCertFile)); err != nil {
 			return node.HTTPConfig{}, err
 		}
 	}
 
+	var (
+		httpsEnabled          = v.GetBool(HTTPSEnabledKey)
+		httpsClientCAFile     string
+		httpsClientScopesFile string
+	)
+	if v.IsSet(HTTPSClientCAFileKey) {
+		if !httpsEnabled {
+			return node.HTTPConfig{}, errClientCAWithoutHTTPS
+		}
+		httpsClientCAFile = os.ExpandEnv(v.GetString(HTTPSClientCAFileKey))
+	}
+	if v.IsSet(HTTPSClientScopesFileKey) {
+		if httpsClientCAFile == "" {
+			return node.HTTPConfig{}, errClientScopesWithoutClientCA
+		}
+		httpsClientScopesFile = os.ExpandEnv(v.GetString(HTTPSClientScopesFileKey))
+	}
+
 	config := node.HTTPConfig{
 		APIConfig: node.APIConfig{
 			APIIndexerConfig: node.APIIndexerConfig{
@@ -243,11 +296,17 @@ func getHTTPConfig(v *viper.Viper) (node.HTTPConfig, error) {
 			MetricsAPIEnabled:  v.GetBool(MetricsAPIEnabledKey),
 			HealthAPIEnabled:   v.GetBool(HealthAPIEnabledKey),
 		},
-		HTTPHost:          v.GetString(HTTPHostKey),
-		HTTPPort:          uint16(v.GetUint(HTTPPortKey)),
-		HTTPSEnabled:      v.GetBool(HTTPSEnabledKey),
-		HTTPSKey:          httpsKey,
-		HTTPSCert:         httpsCert,
+		HTTPHost:      v.GetString(HTTPHostKey),
+		HTTPPort:      uint16(v.GetUint(HTTPPortKey)),
+		HTTPSEnabled:  httpsEnabled,
+		HTTPSKey:      httpsKey,
+		HTTPSCert:     httpsCert,
+		HTTPSKeyFile:  httpsKeyFile,
+		HTTPSCertFile: httpsCertFile,
+
+		HTTPSClientCAFile:     httpsClientCAFile,
+		HTTPSClientScopesFile: httpsClientScopesFile,
+
 		APIAllowedOrigins: v.GetStringSlice(HTTPAllowedOrigins),
 
 		ShutdownTimeout: v.GetDuration(HTTPShutdownTimeoutKey),
@@ -388,6 +447,21 @@ func getNetworkConfig(v *viper.Viper, halflife time.Duration) (network.Config, e
 		UptimeMetricFreq:   v.GetDuration(UptimeMetricFreqKey),
 
 		RequireValidatorToConnect: v.GetBool(NetworkRequireValidatorToConnectKey),
+
+		PeerFilterConfig: peerfilter.Config{
+			Allow: v.GetStringSlice(NetworkPeerAllowListKey),
+			Deny:  v.GetStringSlice(NetworkPeerDenyListKey),
+		},
+
+		ReputationConfig: reputation.Config{
+			HalfLife:                v.GetDuration(PeerReputationHalfLifeKey),
+			TimeoutPenalty:          v.GetFloat64(PeerReputationTimeoutPenaltyKey),
+			MalformedMessagePenalty: v.GetFloat64(PeerReputationMalformedMessagePenaltyKey),
+			ThrottledPenalty:        v.GetFloat64(PeerReputationThrottledPenaltyKey),
+			InvalidBlockPenalty:     v.GetFloat64(PeerReputationInvalidBlockPenaltyKey),
+			BanThreshold:            v.GetFloat64(PeerReputationBanThresholdKey),
+			BanDuration:             v.GetDuration(PeerReputationBanDurationKey),
+		},
 	}
 
 	switch {
@@ -425,11 +499,100 @@ func getNetworkConfig(v *viper.Viper, halflife time.Duration) (network.Config, e
 		return network.Config{}, fmt.Errorf("%s must be >= 0", NetworkReadHandshakeTimeoutKey)
 	case config.MaxClockDifference < 0:
 		return network.Config{}, fmt.Errorf("%s must be >= 0", NetworkMaxClockDifferenceKey)
+	case config.ReputationConfig.HalfLife <= 0:
+		return network.Config{}, fmt.Errorf("%s must be > 0", PeerReputationHalfLifeKey)
+	case config.ReputationConfig.TimeoutPenalty < 0:
+		return network.Config{}, fmt.Errorf("%s must be >= 0", PeerReputationTimeoutPenaltyKey)
+	case config.ReputationConfig.MalformedMessagePenalty < 0:
+		return network.Config{}, fmt.Errorf("%s must be >= 0", PeerReputationMalformedMessagePenaltyKey)
+	case config.ReputationConfig.ThrottledPenalty < 0:
+		return network.Config{}, fmt.Errorf("%s must be >= 0", PeerReputationThrottledPenaltyKey)
+	case config.ReputationConfig.InvalidBlockPenalty < 0:
+		return network.Config{}, fmt.Errorf("%s must be >= 0", PeerReputationInvalidBlockPenaltyKey)
+	case config.ReputationConfig.BanThreshold < 0:
+		return network.Config{}, fmt.Errorf("%s must be >= 0", PeerReputationBanThresholdKey)
+	case config.ReputationConfig.BanDuration < 0:
+		return network.Config{}, fmt.Errorf("%s must be >= 0", PeerReputationBanDurationKey)
+	}
+
+	if v.IsSet(NetworkPeerFilterFileKey) {
+		if v.IsSet(NetworkPeerAllowListKey) || v.IsSet(NetworkPeerDenyListKey) {
+			return network.Config{}, fmt.Errorf("%s can't be set with %s or %s", NetworkPeerFilterFileKey, NetworkPeerAllowListKey, NetworkPeerDenyListKey)
+		}
+		var err error
+		config.PeerFilterConfig, err = peerfilter.ReadConfig(getPeerFilterFile(v))
+		if err != nil {
+			return network.Config{}, err
+		}
+	}
+	if _, err := peerfilter.New(config.PeerFilterConfig); err != nil {
+		return network.Config{}, err
+	}
+
+	sentryConfig, err := getSentryConfig(v)
+	if err != nil {
+		return network.Config{}, err
+	}
+	config.SentryConfig = sentryConfig
+
+	return config, nil
+}
+
+func getSentryConfig(v *viper.Viper) (network.SentryConfig, error) {
+	config := network.SentryConfig{
+		RelayCertMaxAge: v.GetDuration(NetworkSentryRelayCertMaxAgeKey),
+	}
+	for _, id := range strings.Split(v.GetString(NetworkSentryIDsKey), ",") {
+		if id == "" {
+			continue
+		}
+		nodeID, err := ids.ShortFromPrefixedString(id, constants.NodeIDPrefix)
+		if err != nil {
+			return network.SentryConfig{}, fmt.Errorf("couldn't parse sentry id: %w", err)
+		}
+		config.SentryIDs = append(config.SentryIDs, nodeID)
+	}
+	for _, ip := range strings.Split(v.GetString(NetworkSentryIPsKey), ",") {
+		if ip == "" {
+			continue
+		}
+		addr, err := utils.ToIPDesc(ip)
+		if err != nil {
+			return network.SentryConfig{}, fmt.Errorf("couldn't parse sentry ip %s: %w", ip, err)
+		}
+		config.SentryIPs = append(config.SentryIPs, addr)
+	}
+	for _, id := range strings.Split(v.GetString(NetworkSentryPrivatePeerIDsKey), ",") {
+		if id == "" {
+			continue
+		}
+		nodeID, err := ids.ShortFromPrefixedString(id, constants.NodeIDPrefix)
+		if err != nil {
+			return network.SentryConfig{}, fmt.Errorf("couldn't parse sentry private peer id: %w", err)
+		}
+		config.PrivatePeerIDs = append(config.PrivatePeerIDs, nodeID)
 	}
 
+	switch {
+	case len(config.SentryIDs) != len(config.SentryIPs):
+		return network.SentryConfig{}, fmt.Errorf("%s and %s must have the same length", NetworkSentryIDsKey, NetworkSentryIPsKey)
+	case len(config.SentryIDs) != 0 && len(config.PrivatePeerIDs) != 0:
+		return network.SentryConfig{}, fmt.Errorf("%s can't be set with %s", NetworkSentryIDsKey, NetworkSentryPrivatePeerIDsKey)
+	case config.RelayCertMaxAge <= 0:
+		return network.SentryConfig{}, fmt.Errorf("%s must be positive", NetworkSentryRelayCertMaxAgeKey)
+	}
 	return config, nil
 }
 
+// getPeerFilterFile returns the path of the file holding the peer filter, or
+// the empty string if there isn't one
+func getPeerFilterFile(v *viper.Viper) string {
+	if !v.IsSet(NetworkPeerFilterFileKey) {
+		return ""
+	}
+	return os.ExpandEnv(v.GetString(NetworkPeerFilterFileKey))
+}
+
 func getBenchlistConfig(v *viper.Viper, alpha, k int) (benchlist.Config, error) {
 	config := benchlist.Config{
 		Threshold:              v.GetInt(BenchlistFailThresholdKey),
@@ -447,6 +610,31 @@ func getBenchlistConfig(v *viper.Viper, alpha, k int) (benchlist.Config, error)
 	return config, nil
 }
 
+func getReplicaConfig(v *viper.Viper) (replica.Config, error) {
+	config := replica.Config{
+		PrimaryURI:    v.GetString(ReplicaPrimaryURIKey),
+		PollFrequency: v.GetDuration(ReplicaPollFrequencyKey),
+	}
+	if config.Enabled() && config.PollFrequency <= 0 {
+		return replica.Config{}, fmt.Errorf("%q must be > 0", ReplicaPollFrequencyKey)
+	}
+	return config, nil
+}
+
+func getRemoteSignerConfig(v *viper.Viper) (gsigner.Config, error) {
+	config := gsigner.Config{
+		Address:  v.GetString(SignerAddressKey),
+		CertFile: os.ExpandEnv(v.GetString(SignerTLSCertFileKey)),
+		KeyFile:  os.ExpandEnv(v.GetString(SignerTLSKeyFileKey)),
+		CAFile:   os.ExpandEnv(v.GetString(SignerTLSCAFileKey)),
+		Timeout:  v.GetDuration(SignerTimeoutKey),
+	}
+	if config.Enabled() && config.Timeout <= 0 {
+		return gsigner.Config{}, fmt.Errorf("%q must be > 0", SignerTimeoutKey)
+	}
+	return config, nil
+}
+
 func getBootstrapConfig(v *viper.Viper, networkID uint32) (node.BootstrapConfig, error) {
 	config := node.BootstrapConfig{
 		RetryBootstrap:                          v.GetBool(RetryBootstrapKey),
@@ -485,9 +673,50 @@ func getBootstrapConfig(v *viper.Viper, networkID uint32) (node.BootstrapConfig,
 		}
 		config.BootstrapIDs = append(config.BootstrapIDs, nodeID)
 	}
+
+	checkpoints, err := getBootstrapCheckpoints(v.GetString(BootstrapCheckpointKey))
+	if err != nil {
+		return node.BootstrapConfig{}, err
+	}
+	config.BootstrapCheckpoints = checkpoints
 	return config, nil
 }
 
+// getBootstrapCheckpoints parses a comma separated list of checkpoints of the
+// form <chain ID>:<block ID>:<height>
+func getBootstrapCheckpoints(s string) (map[ids.ID]bootstrap.Checkpoint, error) {
+	checkpoints := make(map[ids.ID]bootstrap.Checkpoint)
+	for _, checkpointStr := range strings.Split(s, ",") {
+		if checkpointStr == "" {
+			continue
+		}
+		fields := strings.Split(checkpointStr, ":")
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("couldn't parse bootstrap checkpoint %q: expected <chain ID>:<block ID>:<height>", checkpointStr)
+		}
+		chainID, err := ids.FromString(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse bootstrap checkpoint chain ID: %w", err)
+		}
+		blkID, err := ids.FromString(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse bootstrap checkpoint block ID: %w", err)
+		}
+		height, err := strconv.ParseUint(fields[2], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse bootstrap checkpoint height: %w", err)
+		}
+		if _, ok := checkpoints[chainID]; ok {
+			return nil, fmt.Errorf("%w: %s", errDuplicateCheckpoint, chainID)
+		}
+		checkpoints[chainID] = bootstrap.Checkpoint{
+			BlockID: blkID,
+			Height:  height,
+		}
+	}
+	return checkpoints, nil
+}
+
 func getIPConfig(v *viper.Viper) (node.IPConfig, error) {
 	config := node.IPConfig{}
 	// Resolves our public IP, or does nothing
@@ -742,7 +971,8 @@ func getDatabaseConfig(v *viper.Viper, networkID uint32) (node.DatabaseConfig, e
 			os.ExpandEnv(v.GetString(DBPathKey)),
 			constants.NetworkName(networkID),
 		),
-		Config: configBytes,
+		Config:      configBytes,
+		MigrateFrom: v.GetString(DBMigrateFromKey),
 	}, nil
 }
 
@@ -914,6 +1144,9 @@ func getSubnetConfigsFromFlags(v *viper.Viper, subnetIDs []ids.ID) (map[ids.ID]c
 			if err := subnetConfig.ConsensusParameters.Valid(); err != nil {
 				return nil, err
 			}
+			if err := subnetConfig.ChainMsgThrottlerConfig.Valid(); err != nil {
+				return nil, fmt.Errorf("invalid chain message throttler config of subnet %s: %w", subnetID, err)
+			}
 			res[subnetID] = subnetConfig
 		}
 	}
@@ -974,6 +1207,9 @@ func readSubnetConfigs(subnetConfigPath string, subnetIDs []ids.ID, defaultSubne
 		if err := configData.ConsensusParameters.Valid(); err != nil {
 			return nil, err
 		}
+		if err := configData.ChainMsgThrottlerConfig.Valid(); err != nil {
+			return nil, fmt.Errorf("invalid chain message throttler config of subnet %s: %w", subnetID, err)
+		}
 		subnetConfigs[subnetID] = configData
 	}
 
@@ -982,8 +1218,19 @@ func readSubnetConfigs(subnetConfigPath string, subnetIDs []ids.ID, defaultSubne
 
 func defaultSubnetConfig(v *viper.Viper) chains.SubnetConfig {
 	return chains.SubnetConfig{
-		ConsensusParameters: getConsensusConfig(v),
-		ValidatorOnly:       false,
+		ConsensusParameters:     getConsensusConfig(v),
+		ValidatorOnly:           false,
+		ChainMsgThrottlerConfig: getChainMsgThrottlerConfig(v),
+	}
+}
+
+func getChainMsgThrottlerConfig(v *viper.Viper) throttling.ChainMsgThrottlerConfig {
+	return throttling.ChainMsgThrottlerConfig{
+		BandwidthRefillRate:      v.GetUint64(ChainThrottlerBandwidthRefillRateKey),
+		BandwidthMaxBurstSize:    v.GetUint64(ChainThrottlerBandwidthMaxBurstSizeKey),
+		MsgRefillRate:            v.GetUint64(ChainThrottlerMsgRefillRateKey),
+		MsgMaxBurstSize:          v.GetUint64(ChainThrottlerMsgMaxBurstSizeKey),
+		ConsensusReservedPortion: v.GetFloat64(ChainThrottlerConsensusReservedPortionKey),
 	}
 }
 
@@ -1002,6 +1249,13 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 	if nodeConfig.ConsensusShutdownTimeout < 0 {
 		return node.Config{}, fmt.Errorf("%q must be >= 0", ConsensusShutdownTimeoutKey)
 	}
+	nodeConfig.ConsensusPollJournalSize = v.GetUint64(ConsensusPollJournalSizeKey)
+
+	// Chain message throttling
+	nodeConfig.ChainMsgThrottlerConfig = getChainMsgThrottlerConfig(v)
+	if err := nodeConfig.ChainMsgThrottlerConfig.Valid(); err != nil {
+		return node.Config{}, fmt.Errorf("invalid chain message throttler config: %w", err)
+	}
 
 	// Gossiping
 	nodeConfig.ConsensusGossipFrequency = v.GetDuration(ConsensusGossipFrequencyKey)
@@ -1084,6 +1338,7 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 	if err != nil {
 		return node.Config{}, err
 	}
+	nodeConfig.PeerFilterFile = getPeerFilterFile(v)
 
 	// Benchlist
 	nodeConfig.BenchlistConfig, err = getBenchlistConfig(v, nodeConfig.ConsensusParams.Alpha, nodeConfig.ConsensusParams.K)
@@ -1147,5 +1402,23 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 	// reset proposerVM height index
 	nodeConfig.ResetProposerVMHeightIndex = v.GetBool(ResetProposerVMHeightIndexKey)
 
+	// Replica
+	nodeConfig.ReplicaConfig, err = getReplicaConfig(v)
+	if err != nil {
+		return node.Config{}, err
+	}
+	if nodeConfig.ReplicaConfig.Enabled() {
+		// A replica doesn't connect to any peer, so its chains finish
+		// bootstrapping immediately and then follow the primary.
+		nodeConfig.BootstrapIPs = nil
+		nodeConfig.BootstrapIDs = nil
+	}
+
+	// Remote signer
+	nodeConfig.RemoteSignerConfig, err = getRemoteSignerConfig(v)
+	if err != nil {
+		return node.Config{}, err
+	}
+
 	return nodeConfig, nil
 }
@@ -12,6 +12,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
@@ -20,8 +21,15 @@ import (
 
 	"github.com/flare-foundation/flare/chains"
 	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/network"
+	"github.com/flare-foundation/flare/network/peerfilter"
 	"github.com/flare-foundation/flare/snow/consensus/avalanche"
 	"github.com/flare-foundation/flare/snow/consensus/snowball"
+	"github.com/flare-foundation/flare/snow/engine/snowman/bootstrap"
+	"github.com/flare-foundation/flare/utils"
+	"github.com/flare-foundation/flare/utils/constants"
+	"github.com/flare-foundation/flare/utils/units"
+	"github.com/flare-foundation/flare/vms/components/keystore/gsigner"
 )
 
 func TestGetChainConfigsFromFiles(t *testing.T) {
@@ -448,6 +456,29 @@ func TestGetSubnetConfigsFromFile(t *testing.T) {
 			},
 			errMessage: "",
 		},
+		"chain message throttler config": {
+			fileName:  "2Ctt6eGAeo4MLqTmGa7AdRecuVMPGWEX9wSsCLBYrLhX4a394i.json",
+			givenJSON: `{"chainMsgThrottlerConfig":{"msgRefillRate": 10, "consensusReservedPortion": 0.8} }`,
+			testF: func(assert *assert.Assertions, given map[ids.ID]chains.SubnetConfig) {
+				id, _ := ids.FromString("2Ctt6eGAeo4MLqTmGa7AdRecuVMPGWEX9wSsCLBYrLhX4a394i")
+				config, ok := given[id]
+				assert.True(ok)
+
+				assert.EqualValues(10, config.ChainMsgThrottlerConfig.MsgRefillRate)
+				assert.Equal(0.8, config.ChainMsgThrottlerConfig.ConsensusReservedPortion)
+				// must still respect defaults
+				assert.EqualValues(4096, config.ChainMsgThrottlerConfig.MsgMaxBurstSize)
+				assert.EqualValues(4*units.MiB, config.ChainMsgThrottlerConfig.BandwidthRefillRate)
+			},
+		},
+		"invalid chain message throttler config": {
+			fileName:  "2Ctt6eGAeo4MLqTmGa7AdRecuVMPGWEX9wSsCLBYrLhX4a394i.json",
+			givenJSON: `{"chainMsgThrottlerConfig":{"consensusReservedPortion": 2} }`,
+			testF: func(assert *assert.Assertions, given map[ids.ID]chains.SubnetConfig) {
+				assert.Nil(given)
+			},
+			errMessage: "invalid chain message throttler config",
+		},
 	}
 
 	for name, test := range tests {
@@ -646,3 +677,270 @@ func setupViper(configFilePath string) *viper.Viper {
 	}
 	return v
 }
+
+func TestGetBootstrapCheckpoints(t *testing.T) {
+	chainID, _ := ids.FromString("2Ctt6eGAeo4MLqTmGa7AdRecuVMPGWEX9wSsCLBYrLhX4a394i")
+	blkID, _ := ids.FromString("Gmt4fuNsGJAd2PX86LBvycGaBpgCYKbuULdCLZs3SEs1Jx1LU")
+	checkpoint := fmt.Sprintf("%s:%s:1000", chainID, blkID)
+
+	tests := map[string]struct {
+		flag        string
+		expected    map[ids.ID]bootstrap.Checkpoint
+		expectedErr bool
+	}{
+		"no checkpoints": {
+			flag:     "",
+			expected: map[ids.ID]bootstrap.Checkpoint{},
+		},
+		"one checkpoint": {
+			flag: checkpoint,
+			expected: map[ids.ID]bootstrap.Checkpoint{
+				chainID: {BlockID: blkID, Height: 1000},
+			},
+		},
+		"duplicate chain": {
+			flag:        checkpoint + "," + checkpoint,
+			expectedErr: true,
+		},
+		"missing height": {
+			flag:        fmt.Sprintf("%s:%s", chainID, blkID),
+			expectedErr: true,
+		},
+		"invalid height": {
+			flag:        fmt.Sprintf("%s:%s:-1", chainID, blkID),
+			expectedErr: true,
+		},
+		"invalid block ID": {
+			flag:        fmt.Sprintf("%s:blk:1000", chainID),
+			expectedErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			checkpoints, err := getBootstrapCheckpoints(test.flag)
+			if test.expectedErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(test.expected, checkpoints)
+		})
+	}
+}
+
+func TestGetNetworkConfigPeerFilter(t *testing.T) {
+	filterFile := filepath.Join(t.TempDir(), "peers.json")
+	assert.NoError(t, ioutil.WriteFile(filterFile, []byte(`{"allow":["10.0.0.0/8"],"deny":["10.0.0.1"]}`), 0o600))
+
+	tests := map[string]struct {
+		values      map[string]interface{}
+		expected    peerfilter.Config
+		expectedErr bool
+	}{
+		"no filter": {
+			expected: peerfilter.Config{Allow: []string{}, Deny: []string{}},
+		},
+		"lists": {
+			values: map[string]interface{}{
+				NetworkPeerAllowListKey: "10.0.0.0/8 NodeID-6Y3kysjF9jnHnYkdS9yGAuoHyae2eNmeV",
+				NetworkPeerDenyListKey:  "10.0.0.1",
+			},
+			expected: peerfilter.Config{
+				Allow: []string{"10.0.0.0/8", "NodeID-6Y3kysjF9jnHnYkdS9yGAuoHyae2eNmeV"},
+				Deny:  []string{"10.0.0.1"},
+			},
+		},
+		"invalid list": {
+			values: map[string]interface{}{
+				NetworkPeerDenyListKey: "10.0.0.0/33",
+			},
+			expectedErr: true,
+		},
+		"file": {
+			values: map[string]interface{}{
+				NetworkPeerFilterFileKey: filterFile,
+			},
+			expected: peerfilter.Config{
+				Allow: []string{"10.0.0.0/8"},
+				Deny:  []string{"10.0.0.1"},
+			},
+		},
+		"file and list": {
+			values: map[string]interface{}{
+				NetworkPeerFilterFileKey: filterFile,
+				NetworkPeerDenyListKey:   "10.0.0.1",
+			},
+			expectedErr: true,
+		},
+		"missing file": {
+			values: map[string]interface{}{
+				NetworkPeerFilterFileKey: filepath.Join(t.TempDir(), "missing.json"),
+			},
+			expectedErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			v := setupViperFlags()
+			for key, value := range test.values {
+				v.Set(key, value)
+			}
+			config, err := getNetworkConfig(v, time.Minute)
+			if test.expectedErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(test.expected, config.PeerFilterConfig)
+		})
+	}
+}
+
+func TestGetNetworkConfigSentry(t *testing.T) {
+	nodeID, err := ids.ShortFromPrefixedString("NodeID-6Y3kysjF9jnHnYkdS9yGAuoHyae2eNmeV", constants.NodeIDPrefix)
+	assert.NoError(t, err)
+	sentryIP, err := utils.ToIPDesc("127.0.0.1:9651")
+	assert.NoError(t, err)
+
+	tests := map[string]struct {
+		values      map[string]interface{}
+		expected    network.SentryConfig
+		expectedErr bool
+	}{
+		"no sentries": {
+			expected: network.SentryConfig{
+				RelayCertMaxAge: 10 * time.Minute,
+			},
+		},
+		"validator": {
+			values: map[string]interface{}{
+				NetworkSentryIDsKey: "NodeID-6Y3kysjF9jnHnYkdS9yGAuoHyae2eNmeV",
+				NetworkSentryIPsKey: "127.0.0.1:9651",
+			},
+			expected: network.SentryConfig{
+				SentryIDs:       []ids.ShortID{nodeID},
+				SentryIPs:       []utils.IPDesc{sentryIP},
+				RelayCertMaxAge: 10 * time.Minute,
+			},
+		},
+		"sentry": {
+			values: map[string]interface{}{
+				NetworkSentryPrivatePeerIDsKey: "NodeID-6Y3kysjF9jnHnYkdS9yGAuoHyae2eNmeV",
+			},
+			expected: network.SentryConfig{
+				PrivatePeerIDs:  []ids.ShortID{nodeID},
+				RelayCertMaxAge: 10 * time.Minute,
+			},
+		},
+		"relay certificate max age": {
+			values: map[string]interface{}{
+				NetworkSentryPrivatePeerIDsKey:  "NodeID-6Y3kysjF9jnHnYkdS9yGAuoHyae2eNmeV",
+				NetworkSentryRelayCertMaxAgeKey: time.Hour,
+			},
+			expected: network.SentryConfig{
+				PrivatePeerIDs:  []ids.ShortID{nodeID},
+				RelayCertMaxAge: time.Hour,
+			},
+		},
+		"non-positive relay certificate max age": {
+			values: map[string]interface{}{
+				NetworkSentryRelayCertMaxAgeKey: time.Duration(0),
+			},
+			expectedErr: true,
+		},
+		"missing sentry ip": {
+			values: map[string]interface{}{
+				NetworkSentryIDsKey: "NodeID-6Y3kysjF9jnHnYkdS9yGAuoHyae2eNmeV",
+			},
+			expectedErr: true,
+		},
+		"invalid sentry id": {
+			values: map[string]interface{}{
+				NetworkSentryIDsKey: "NodeID-invalid",
+				NetworkSentryIPsKey: "127.0.0.1:9651",
+			},
+			expectedErr: true,
+		},
+		"validator and sentry": {
+			values: map[string]interface{}{
+				NetworkSentryIDsKey:            "NodeID-6Y3kysjF9jnHnYkdS9yGAuoHyae2eNmeV",
+				NetworkSentryIPsKey:            "127.0.0.1:9651",
+				NetworkSentryPrivatePeerIDsKey: "NodeID-6Y3kysjF9jnHnYkdS9yGAuoHyae2eNmeV",
+			},
+			expectedErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			v := setupViperFlags()
+			for key, value := range test.values {
+				v.Set(key, value)
+			}
+			config, err := getNetworkConfig(v, time.Minute)
+			if test.expectedErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(test.expected, config.SentryConfig)
+		})
+	}
+}
+
+func TestGetRemoteSignerConfig(t *testing.T) {
+	tests := map[string]struct {
+		values      map[string]interface{}
+		expected    gsigner.Config
+		expectedErr bool
+	}{
+		"disabled": {
+			expected: gsigner.Config{Timeout: 10 * time.Second},
+		},
+		"mutual TLS": {
+			values: map[string]interface{}{
+				SignerAddressKey:     "10.0.0.1:9670",
+				SignerTLSCertFileKey: "node.crt",
+				SignerTLSKeyFileKey:  "node.key",
+				SignerTLSCAFileKey:   "ca.crt",
+				SignerTimeoutKey:     time.Second,
+			},
+			expected: gsigner.Config{
+				Address:  "10.0.0.1:9670",
+				CertFile: "node.crt",
+				KeyFile:  "node.key",
+				CAFile:   "ca.crt",
+				Timeout:  time.Second,
+			},
+		},
+		"no timeout": {
+			values: map[string]interface{}{
+				SignerAddressKey: "127.0.0.1:9670",
+				SignerTimeoutKey: time.Duration(0),
+			},
+			expectedErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			v := setupViperFlags()
+			for key, value := range test.values {
+				v.Set(key, value)
+			}
+			config, err := getRemoteSignerConfig(v)
+			if test.expectedErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(test.expected, config)
+		})
+	}
+}
@@ -15,6 +15,7 @@ import (
 
 	"github.com/flare-foundation/flare/database/leveldb"
 	"github.com/flare-foundation/flare/database/memdb"
+	"github.com/flare-foundation/flare/database/pebbledb"
 	"github.com/flare-foundation/flare/database/rocksdb"
 	"github.com/flare-foundation/flare/genesis"
 	"github.com/flare-foundation/flare/utils/constants"
@@ -95,16 +96,22 @@ func addNodeFlags(fs *flag.FlagSet) {
 	fs.Uint64(CreateBlockchainTxFeeKey, genesis.LocalParams.CreateBlockchainTxFee, "Transaction fee, in nAVAX, for transactions that create new blockchains")
 
 	// Database
-	fs.String(DBTypeKey, leveldb.Name, fmt.Sprintf("Database type to use. Should be one of {%s, %s, %s}", leveldb.Name, rocksdb.Name, memdb.Name))
+	fs.String(DBTypeKey, leveldb.Name, fmt.Sprintf("Database type to use. Should be one of {%s, %s, %s, %s}", leveldb.Name, rocksdb.Name, pebbledb.Name, memdb.Name))
 	fs.String(DBPathKey, defaultDBDir, "Path to database directory")
 	fs.String(DBConfigFileKey, "", fmt.Sprintf("Path to database config file. Ignored if %s is specified", DBConfigContentKey))
 	fs.String(DBConfigContentKey, "", "Specifies base64 encoded database config content")
+	fs.String(DBMigrateFromKey, "", fmt.Sprintf("Database type to copy into the %s database when it doesn't exist yet. Should be one of {%s, %s, %s}. If empty, nothing is copied", DBTypeKey, leveldb.Name, rocksdb.Name, pebbledb.Name))
 
 	// Logging
 	fs.String(LogsDirKey, "", "Logging directory for Avalanche")
 	fs.String(LogLevelKey, "info", "The log level. Should be one of {verbo, debug, trace, info, warn, error, fatal, off}")
 	fs.String(LogDisplayLevelKey, "", "The log display level. If left blank, will inherit the value of log-level. Otherwise, should be one of {verbo, debug, info, warn, error, fatal, off}")
 	fs.String(LogDisplayHighlightKey, "auto", "Whether to color/highlight display logs. Default highlights when the output is a terminal. Otherwise, should be one of {auto, plain, colors}")
+	fs.String(LogFormatKey, "plain", "The format of logged events. Should be one of {plain, json}. With json, each event is written as a JSON object on its own line")
+	fs.Uint(LogRotationMaxSizeKey, 8, "Size, in MiB, of a log file after which it is rotated")
+	fs.Duration(LogRotationIntervalKey, 24*time.Hour, "Interval after which a log file is rotated regardless of its size")
+	fs.Uint(LogRotationMaxFilesKey, 7, "Number of rotated log files to keep per logger")
+	fs.Duration(LogRotationMaxAgeKey, 0, "Age after which rotated log files are deleted. If 0, rotated log files are kept until they exceed the number of files to keep")
 
 	// Assertions
 	fs.Bool(AssertionsEnabledKey, true, "Turn on assertion execution")
@@ -151,6 +158,13 @@ func addNodeFlags(fs *flag.FlagSet) {
 	fs.Duration(NetworkMaxClockDifferenceKey, time.Minute, "Max allowed clock difference value between this node and peers")
 	fs.Bool(NetworkAllowPrivateIPsKey, true, "Allows the node to connect peers with private IPs")
 	fs.Bool(NetworkRequireValidatorToConnectKey, false, "If true, this node will only maintain a connection with another node if this node is a validator, the other node is a validator, or the other node is a beacon")
+	fs.String(NetworkPeerAllowListKey, "", "Node IDs, IPs and CIDR blocks of the peers this node may connect to. If there are node IDs, only peers with one of them are allowed, and if there are IPs, only peers at one of them are allowed")
+	fs.String(NetworkPeerDenyListKey, "", "Node IDs, IPs and CIDR blocks of the peers this node never connects to")
+	fs.String(NetworkPeerFilterFileKey, "", fmt.Sprintf("JSON file with the \"allow\" and \"deny\" lists of peers. Reloaded by admin.reloadPeerFilter. Can't be set with %s or %s", NetworkPeerAllowListKey, NetworkPeerDenyListKey))
+	fs.String(NetworkSentryIDsKey, "", "Comma separated list of the node IDs of the sentries of this validator. If set, this node is only connected to its sentries, which relay its consensus messages. Its sentries should also be its bootstrap peers. Replaced by admin.setSentries")
+	fs.String(NetworkSentryIPsKey, "", fmt.Sprintf("Comma separated list of the IPs of the sentries of this validator, in the order of %s", NetworkSentryIDsKey))
+	fs.String(NetworkSentryPrivatePeerIDsKey, "", "Comma separated list of the node IDs of the validators this node is a sentry for. Their IPs are never gossiped. Replaced by admin.setSentries")
+	fs.Duration(NetworkSentryRelayCertMaxAgeKey, 10*time.Minute, "Maximum age of the certificates validators sign to authorize their sentries. A sentry is no longer trusted by its peers this long after its validator stops renewing its certificate")
 	// Peer alias configuration
 	fs.Duration(PeerAliasTimeoutKey, 10*time.Minute, "How often the node will attempt to connect to an IP address previously associated with a peer (i.e. a peer alias)")
 
@@ -160,9 +174,19 @@ func addNodeFlags(fs *flag.FlagSet) {
 	fs.Duration(BenchlistDurationKey, 15*time.Minute, "Max amount of time a peer is benchlisted after surpassing the threshold")
 	fs.Duration(BenchlistMinFailingDurationKey, 2*time.Minute+30*time.Second, "Minimum amount of time messages to a peer must be failing before the peer is benched")
 
+	// Peer reputation
+	fs.Duration(PeerReputationHalfLifeKey, 5*time.Minute, "Time it takes for the reputation score of a peer to decay to half its value")
+	fs.Float64(PeerReputationTimeoutPenaltyKey, 1, "Score added to a peer when a request to it times out")
+	fs.Float64(PeerReputationMalformedMessagePenaltyKey, 10, "Score added to a peer when it sends a message that can't be parsed")
+	fs.Float64(PeerReputationThrottledPenaltyKey, 0.01, "Score added to a peer when a message from it is throttled")
+	fs.Float64(PeerReputationInvalidBlockPenaltyKey, 10, "Score added to a peer when it sends a block that fails verification")
+	fs.Float64(PeerReputationBanThresholdKey, 0, "Score at which a peer is disconnected and banned. If 0, peers are never banned")
+	fs.Duration(PeerReputationBanDurationKey, 10*time.Minute, "Amount of time connections with a banned peer are refused")
+
 	// Router
 	fs.Duration(ConsensusGossipFrequencyKey, 10*time.Second, "Frequency of gossiping accepted frontiers")
 	fs.Duration(ConsensusShutdownTimeoutKey, 5*time.Second, "Timeout before killing an unresponsive chain")
+	fs.Uint64(ConsensusPollJournalSizeKey, 0, "Number of polls of each snowman chain to record in the poll journal. If 0, polls aren't recorded")
 	fs.Uint(ConsensusGossipAcceptedFrontierSizeKey, 35, "Number of peers to gossip to when gossiping accepted frontier")
 	fs.Uint(ConsensusGossipOnAcceptSizeKey, 20, "Number of peers to gossip to each accepted container to")
 	fs.Uint(AppGossipNonValidatorSizeKey, 0, "Number of peers (which may be validators or non-validators) to gossip an AppGossip message to")
@@ -181,6 +205,13 @@ func addNodeFlags(fs *flag.FlagSet) {
 	fs.Uint64(OutboundThrottlerVdrAllocSizeKey, 32*units.MiB, "Size, in bytes, of validator byte allocation in outbound message throttler")
 	fs.Uint64(OutboundThrottlerNodeMaxAtLargeBytesKey, uint64(constants.DefaultMaxMessageSize), "Max number of bytes a node can take from the outbound message throttler's at-large allocation.  Must be at least the max message size")
 
+	// Chain Throttling
+	fs.Uint64(ChainThrottlerBandwidthRefillRateKey, 4*units.MiB, "Max average bandwidth usage of the unrequested inbound messages of a chain, in bytes per second. If 0, bandwidth isn't throttled per chain")
+	fs.Uint64(ChainThrottlerBandwidthMaxBurstSizeKey, 8*units.MiB, "Max bandwidth the unrequested inbound messages of a chain can use at once. Must be at least the max message size")
+	fs.Uint64(ChainThrottlerMsgRefillRateKey, 1024, "Max average number of unrequested inbound messages per second a chain handles. If 0, the message rate isn't throttled per chain")
+	fs.Uint64(ChainThrottlerMsgMaxBurstSizeKey, 4096, "Max number of unrequested inbound messages a chain can handle at once")
+	fs.Float64(ChainThrottlerConsensusReservedPortionKey, 0.5, "Portion of each chain's bandwidth and message allocation reserved for consensus messages. Must be in [0,1]")
+
 	// HTTP APIs
 	fs.String(HTTPHostKey, "127.0.0.1", "Address of the HTTP server")
 	fs.Uint(HTTPPortKey, 9650, "Port of the HTTP server")
@@ -189,6 +220,8 @@ func addNodeFlags(fs *flag.FlagSet) {
 	fs.String(HTTPSKeyContentKey, "", "Specifies base64 encoded TLS private key for the HTTPs server")
 	fs.String(HTTPSCertFileKey, "", fmt.Sprintf("TLS certificate file for the HTTPs server. Ignored if %s is specified", HTTPSCertContentKey))
 	fs.String(HTTPSCertContentKey, "", "Specifies base64 encoded TLS certificate for the HTTPs server")
+	fs.String(HTTPSClientCAFileKey, "", "PEM file of the CAs that sign client certificates. If specified, clients of the HTTPs server must present a certificate signed by one of these CAs")
+	fs.String(HTTPSClientScopesFileKey, "", fmt.Sprintf("JSON file that maps the common names of client certificates to the API endpoints and methods they may access. If specified, requests allowed by their client certificate don't require an auth token. Requires %s", HTTPSClientCAFileKey))
 	fs.String(HTTPAllowedOrigins, "*", "Origins to allow on the HTTP port. Defaults to * which allows all origins. Example: https://*.avax.network https://*.avax-test.network")
 	fs.Duration(HTTPShutdownWaitKey, 0, "Duration to wait after receiving SIGTERM or SIGINT before initiating shutdown. The /health endpoint will return unhealthy during this duration")
 	fs.Duration(HTTPShutdownTimeoutKey, 10*time.Second, "Maximum duration to wait for existing connections to complete during node shutdown")
@@ -259,6 +292,7 @@ func addNodeFlags(fs *flag.FlagSet) {
 	fs.Duration(BootstrapMaxTimeGetAncestorsKey, 50*time.Millisecond, "Max Time to spend fetching a container and its ancestors when responding to a GetAncestors")
 	fs.Uint(BootstrapAncestorsMaxContainersSentKey, 2000, "Max number of containers in an Ancestors message sent by this node")
 	fs.Uint(BootstrapAncestorsMaxContainersReceivedKey, 2000, "This node reads at most this many containers from an incoming Ancestors message")
+	fs.String(BootstrapCheckpointKey, "", "Comma separated list of blocks the node trusts to be accepted, as <chain ID>:<block ID>:<height>. Bootstrapping a chain still fetches and verifies the blocks below its checkpoint, and fails if the beacons' chain doesn't go through it")
 
 	// Consensus
 	fs.Int(SnowSampleSizeKey, 20, "Number of nodes to query for each network poll")
@@ -285,6 +319,17 @@ func addNodeFlags(fs *flag.FlagSet) {
 	fs.Bool(IndexEnabledKey, false, "If true, index all accepted containers and transactions and expose them via an API")
 	fs.Bool(IndexAllowIncompleteKey, false, "If true, allow running the node in such a way that could cause an index to miss transactions. Ignored if index is disabled")
 
+	// Replica
+	fs.String(ReplicaPrimaryURIKey, "", "If set, this node doesn't participate in the network and instead follows the indices of the node whose API server is at this URI, e.g. http://127.0.0.1:9650. The primary must run with index-enabled from genesis. The transactions submitted to this node are rejected")
+	fs.Duration(ReplicaPollFrequencyKey, time.Second, "Frequency at which a replica checks its primary for newly accepted containers")
+
+	// Remote Signer
+	fs.String(SignerAddressKey, "", "If set, the transactions issued through the keystore APIs of the X, P and C chains are signed by the remote signer at this address, e.g. 127.0.0.1:9670, instead of the keys of the user. Users must still authenticate with their password")
+	fs.String(SignerTLSCertFileKey, "", fmt.Sprintf("TLS certificate file this node authenticates to the remote signer with. Must be set with %s and %s unless %s is a loopback address", SignerTLSKeyFileKey, SignerTLSCAFileKey, SignerAddressKey))
+	fs.String(SignerTLSKeyFileKey, "", "TLS private key file this node authenticates to the remote signer with")
+	fs.String(SignerTLSCAFileKey, "", "File with the CA certificates that the TLS certificate of the remote signer must be signed by")
+	fs.Duration(SignerTimeoutKey, 10*time.Second, "Timeout of a call to the remote signer")
+
 	// Config Directories
 	fs.String(ChainConfigDirKey, defaultChainConfigDir, fmt.Sprintf("Chain specific configurations parent directory. Ignored if %s is specified", ChainConfigContentKey))
 	fs.String(ChainConfigContentKey, "", "Specifies base64 encoded chains configurations")
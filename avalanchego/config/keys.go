@@ -33,6 +33,7 @@ const (
 	DBPathKey                                   = "db-dir"
 	DBConfigFileKey                             = "db-config-file"
 	DBConfigContentKey                          = "db-config-file-content"
+	DBMigrateFromKey                            = "db-migrate-from"
 	PublicIPKey                                 = "public-ip"
 	DynamicUpdateDurationKey                    = "dynamic-update-duration"
 	DynamicPublicIPResolverKey                  = "dynamic-public-ip"
@@ -48,6 +49,8 @@ const (
 	HTTPSKeyContentKey                          = "http-tls-key-file-content"
 	HTTPSCertFileKey                            = "http-tls-cert-file"
 	HTTPSCertContentKey                         = "http-tls-cert-file-content"
+	HTTPSClientCAFileKey                        = "http-tls-client-ca-file"
+	HTTPSClientScopesFileKey                    = "http-tls-client-scopes-file"
 	HTTPAllowedOrigins                          = "http-allowed-origins"
 	HTTPShutdownTimeoutKey                      = "http-shutdown-timeout"
 	HTTPShutdownWaitKey                         = "http-shutdown-wait"
@@ -89,15 +92,34 @@ const (
 	NetworkMaxClockDifferenceKey                = "network-max-clock-difference"
 	NetworkAllowPrivateIPsKey                   = "network-allow-private-ips"
 	NetworkRequireValidatorToConnectKey         = "network-require-validator-to-connect"
+	NetworkPeerAllowListKey                     = "network-peer-allow-list"
+	NetworkPeerDenyListKey                      = "network-peer-deny-list"
+	NetworkPeerFilterFileKey                    = "network-peer-filter-file"
+	NetworkSentryIDsKey                         = "network-sentry-ids"
+	NetworkSentryIPsKey                         = "network-sentry-ips"
+	NetworkSentryPrivatePeerIDsKey              = "network-sentry-private-peer-ids"
+	NetworkSentryRelayCertMaxAgeKey             = "network-sentry-relay-cert-max-age"
 	BenchlistFailThresholdKey                   = "benchlist-fail-threshold"
 	BenchlistPeerSummaryEnabledKey              = "benchlist-peer-summary-enabled"
 	BenchlistDurationKey                        = "benchlist-duration"
 	BenchlistMinFailingDurationKey              = "benchlist-min-failing-duration"
+	PeerReputationHalfLifeKey                   = "peer-reputation-half-life"
+	PeerReputationTimeoutPenaltyKey             = "peer-reputation-timeout-penalty"
+	PeerReputationMalformedMessagePenaltyKey    = "peer-reputation-malformed-message-penalty"
+	PeerReputationThrottledPenaltyKey           = "peer-reputation-throttled-penalty"
+	PeerReputationInvalidBlockPenaltyKey        = "peer-reputation-invalid-block-penalty"
+	PeerReputationBanThresholdKey               = "peer-reputation-ban-threshold"
+	PeerReputationBanDurationKey                = "peer-reputation-ban-duration"
 	BuildDirKey                                 = "build-dir"
 	LogsDirKey                                  = "log-dir"
 	LogLevelKey                                 = "log-level"
 	LogDisplayLevelKey                          = "log-display-level"
 	LogDisplayHighlightKey                      = "log-display-highlight"
+	LogFormatKey                                = "log-format"
+	LogRotationMaxSizeKey                       = "log-rotation-max-size"
+	LogRotationIntervalKey                      = "log-rotation-interval"
+	LogRotationMaxFilesKey                      = "log-rotation-max-files"
+	LogRotationMaxAgeKey                        = "log-rotation-max-age"
 	SnowSampleSizeKey                           = "snow-sample-size"
 	SnowQuorumSizeKey                           = "snow-quorum-size"
 	SnowVirtuousCommitThresholdKey              = "snow-virtuous-commit-threshold"
@@ -124,10 +146,18 @@ const (
 	AppGossipNonValidatorSizeKey                = "consensus-app-gossip-non-validator-size"
 	AppGossipValidatorSizeKey                   = "consensus-app-gossip-validator-size"
 	ConsensusShutdownTimeoutKey                 = "consensus-shutdown-timeout"
+	ConsensusPollJournalSizeKey                 = "consensus-poll-journal-size"
 	FdLimitKey                                  = "fd-limit"
 	IndexEnabledKey                             = "index-enabled"
 	IndexAllowIncompleteKey                     = "index-allow-incomplete"
 	ResetProposerVMHeightIndexKey               = "reset-proposervm-height-index"
+	ReplicaPrimaryURIKey                        = "replica-primary-uri"
+	ReplicaPollFrequencyKey                     = "replica-poll-frequency"
+	SignerAddressKey                            = "signer-address"
+	SignerTLSCertFileKey                        = "signer-tls-cert-file"
+	SignerTLSKeyFileKey                         = "signer-tls-key-file"
+	SignerTLSCAFileKey                          = "signer-tls-ca-file"
+	SignerTimeoutKey                            = "signer-timeout"
 	RouterHealthMaxDropRateKey                  = "router-health-max-drop-rate"
 	RouterHealthMaxOutstandingRequestsKey       = "router-health-max-outstanding-requests"
 	HealthCheckFreqKey                          = "health-check-frequency"
@@ -140,6 +170,7 @@ const (
 	BootstrapMaxTimeGetAncestorsKey             = "boostrap-max-time-get-ancestors"
 	BootstrapAncestorsMaxContainersSentKey      = "bootstrap-ancestors-max-containers-sent"
 	BootstrapAncestorsMaxContainersReceivedKey  = "bootstrap-ancestors-max-containers-received"
+	BootstrapCheckpointKey                      = "bootstrap-checkpoint"
 	ChainConfigDirKey                           = "chain-config-dir"
 	ChainConfigContentKey                       = "chain-config-content"
 	SubnetConfigDirKey                          = "subnet-config-dir"
@@ -157,6 +188,11 @@ const (
 	OutboundThrottlerAtLargeAllocSizeKey        = "throttler-outbound-at-large-alloc-size"
 	OutboundThrottlerVdrAllocSizeKey            = "throttler-outbound-validator-alloc-size"
 	OutboundThrottlerNodeMaxAtLargeBytesKey     = "throttler-outbound-node-max-at-large-bytes"
+	ChainThrottlerBandwidthRefillRateKey        = "throttler-chain-bandwidth-refill-rate"
+	ChainThrottlerBandwidthMaxBurstSizeKey      = "throttler-chain-bandwidth-max-burst-size"
+	ChainThrottlerMsgRefillRateKey              = "throttler-chain-msg-refill-rate"
+	ChainThrottlerMsgMaxBurstSizeKey            = "throttler-chain-msg-max-burst-size"
+	ChainThrottlerConsensusReservedPortionKey   = "throttler-chain-consensus-reserved-portion"
 	UptimeMetricFreqKey                         = "uptime-metric-freq"
 	VMAliasesFileKey                            = "vm-aliases-file"
 	VMAliasesContentKey                         = "vm-aliases-file-content"
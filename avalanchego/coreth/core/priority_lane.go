@@ -0,0 +1,104 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package core
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/flare-foundation/flare/coreth/core/types"
+)
+
+// PriorityLaneConfig configures the block space that is reserved for
+// transactions calling the prioritised contracts.
+type PriorityLaneConfig struct {
+	Contracts  []common.Address // Contracts that priority lane transactions must call (defaults to the prioritised contracts)
+	Senders    []common.Address // Senders registered to use the priority lane
+	GasReserve uint64           // Gas of each block reserved for, and usable by, priority lane transactions (0 = no reservation or gas limit)
+	MaxTxs     int              // Maximum number of priority lane transactions per block (0 = no count limit)
+}
+
+// PriorityLane selects the transactions that are included in a block before
+// any other transaction, regardless of their price.
+//
+// To prevent the lane from being spammed, only transactions of registered
+// senders are eligible and at most one transaction per sender is included in
+// the lane of each block.
+//
+// The gas reserve of the lane bounds the gas its transactions may use. The
+// lane is drained before any other transaction of the block, so the part of
+// the reserve it doesn't use is left to the other transactions.
+type PriorityLane struct {
+	contracts  map[common.Address]struct{}
+	senders    map[common.Address]struct{}
+	gasReserve uint64
+	maxTxs     int
+}
+
+// NewPriorityLane returns the priority lane described by [config]. The lane
+// is disabled if no senders are registered.
+func NewPriorityLane(config PriorityLaneConfig) *PriorityLane {
+	contracts := config.Contracts
+	if len(contracts) == 0 {
+		contracts = []common.Address{prioritisedFTSOContractAddress, prioritisedSubmitterContractAddress}
+	}
+	lane := &PriorityLane{
+		contracts:  make(map[common.Address]struct{}, len(contracts)),
+		senders:    make(map[common.Address]struct{}, len(config.Senders)),
+		gasReserve: config.GasReserve,
+		maxTxs:     config.MaxTxs,
+	}
+	for _, addr := range contracts {
+		lane.contracts[addr] = struct{}{}
+	}
+	for _, addr := range config.Senders {
+		lane.senders[addr] = struct{}{}
+	}
+	return lane
+}
+
+// Enabled returns true if any sender may use the priority lane.
+func (l *PriorityLane) Enabled() bool {
+	return l != nil && len(l.senders) > 0
+}
+
+// Eligible returns true if [tx], sent by [from], may use the priority lane.
+func (l *PriorityLane) Eligible(from common.Address, tx *types.Transaction) bool {
+	if !l.Enabled() || tx.To() == nil {
+		return false
+	}
+	if _, ok := l.senders[from]; !ok {
+		return false
+	}
+	_, ok := l.contracts[*tx.To()]
+	return ok
+}
+
+// Select returns, for every registered sender in [pending], its next
+// transaction if that transaction is eligible for the priority lane.
+//
+// [pending] is expected to contain nonce-ordered transactions per sender.
+func (l *PriorityLane) Select(pending map[common.Address]types.Transactions) map[common.Address]types.Transactions {
+	selected := make(map[common.Address]types.Transactions)
+	if !l.Enabled() {
+		return selected
+	}
+	for from := range l.senders {
+		txs := pending[from]
+		if len(txs) == 0 || !l.Eligible(from, txs[0]) {
+			continue
+		}
+		// Only a single transaction per sender is allowed in the lane.
+		selected[from] = txs[:1]
+	}
+	return selected
+}
+
+// Fits returns true if a transaction with a gas limit of [gas] may be added
+// to a lane that already contains [count] transactions using [gasUsed] gas.
+func (l *PriorityLane) Fits(count int, gasUsed, gas uint64) bool {
+	if l.maxTxs > 0 && count >= l.maxTxs {
+		return false
+	}
+	return l.gasReserve == 0 || gasUsed+gas <= l.gasReserve
+}
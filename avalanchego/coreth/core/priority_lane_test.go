@@ -0,0 +1,176 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package core
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+
+	"github.com/flare-foundation/flare/coreth/core/rawdb"
+	"github.com/flare-foundation/flare/coreth/core/state"
+	"github.com/flare-foundation/flare/coreth/core/types"
+	"github.com/flare-foundation/flare/coreth/params"
+)
+
+func newPriorityLaneTx(nonce uint64, to *common.Address) *types.Transaction {
+	if to == nil {
+		return types.NewContractCreation(nonce, big.NewInt(0), 100000, big.NewInt(1), nil)
+	}
+	return types.NewTransaction(nonce, *to, big.NewInt(0), 100000, big.NewInt(1), nil)
+}
+
+func TestPriorityLaneEligible(t *testing.T) {
+	sender := common.HexToAddress("0x01")
+	other := common.HexToAddress("0x02")
+	ftso := prioritisedFTSOContractAddress
+	submitter := prioritisedSubmitterContractAddress
+
+	disabled := NewPriorityLane(PriorityLaneConfig{})
+	if disabled.Enabled() {
+		t.Fatal("expected lane without senders to be disabled")
+	}
+	if disabled.Eligible(sender, newPriorityLaneTx(0, &ftso)) {
+		t.Fatal("expected disabled lane to reject every transaction")
+	}
+	var nilLane *PriorityLane
+	if nilLane.Enabled() {
+		t.Fatal("expected nil lane to be disabled")
+	}
+
+	lane := NewPriorityLane(PriorityLaneConfig{Senders: []common.Address{sender}})
+	tests := []struct {
+		from     common.Address
+		to       *common.Address
+		expected bool
+	}{
+		{from: sender, to: &ftso, expected: true},
+		{from: sender, to: &submitter, expected: true},
+		{from: sender, to: &other, expected: false},
+		{from: sender, to: nil, expected: false},
+		{from: other, to: &ftso, expected: false},
+	}
+	for i, test := range tests {
+		if got := lane.Eligible(test.from, newPriorityLaneTx(0, test.to)); got != test.expected {
+			t.Fatalf("test %d: expected %t, got %t", i, test.expected, got)
+		}
+	}
+
+	custom := NewPriorityLane(PriorityLaneConfig{Contracts: []common.Address{other}, Senders: []common.Address{sender}})
+	if custom.Eligible(sender, newPriorityLaneTx(0, &ftso)) {
+		t.Fatal("expected custom contracts to replace the default contracts")
+	}
+	if !custom.Eligible(sender, newPriorityLaneTx(0, &other)) {
+		t.Fatal("expected transaction calling custom contract to be eligible")
+	}
+}
+
+func TestPriorityLaneSelect(t *testing.T) {
+	first := common.HexToAddress("0x01")
+	second := common.HexToAddress("0x02")
+	unregistered := common.HexToAddress("0x03")
+	ftso := prioritisedFTSOContractAddress
+	other := common.HexToAddress("0x04")
+
+	lane := NewPriorityLane(PriorityLaneConfig{Senders: []common.Address{first, second}})
+	pending := map[common.Address]types.Transactions{
+		first:        {newPriorityLaneTx(0, &ftso), newPriorityLaneTx(1, &ftso)},
+		second:       {newPriorityLaneTx(0, &other), newPriorityLaneTx(1, &ftso)},
+		unregistered: {newPriorityLaneTx(0, &ftso)},
+	}
+	selected := lane.Select(pending)
+	if len(selected) != 1 {
+		t.Fatalf("expected 1 sender to be selected, got %d", len(selected))
+	}
+	txs := selected[first]
+	if len(txs) != 1 || txs[0].Nonce() != 0 {
+		t.Fatalf("expected only the first transaction of the sender to be selected, got %d", len(txs))
+	}
+}
+
+func TestPriorityLaneFits(t *testing.T) {
+	unlimited := NewPriorityLane(PriorityLaneConfig{})
+	if !unlimited.Fits(1000, 1_000_000_000, 1_000_000) {
+		t.Fatal("expected lane without limits to fit every transaction")
+	}
+
+	lane := NewPriorityLane(PriorityLaneConfig{GasReserve: 100000, MaxTxs: 2})
+	tests := []struct {
+		count    int
+		gasUsed  uint64
+		gas      uint64
+		expected bool
+	}{
+		{count: 0, gasUsed: 0, gas: 100000, expected: true},
+		{count: 0, gasUsed: 0, gas: 100001, expected: false},
+		{count: 1, gasUsed: 50000, gas: 50000, expected: true},
+		{count: 1, gasUsed: 50000, gas: 50001, expected: false},
+		{count: 2, gasUsed: 0, gas: 21000, expected: false},
+	}
+	for i, test := range tests {
+		if got := lane.Fits(test.count, test.gasUsed, test.gas); got != test.expected {
+			t.Fatalf("test %d: expected %t, got %t", i, test.expected, got)
+		}
+	}
+}
+
+func priorityLaneTransaction(nonce uint64, gasprice *big.Int, key *ecdsa.PrivateKey) *types.Transaction {
+	tx, _ := types.SignTx(types.NewTransaction(nonce, prioritisedFTSOContractAddress, big.NewInt(0), 100000, gasprice, nil), types.HomesteadSigner{}, key)
+	return tx
+}
+
+// Tests that priority lane transactions only evict other transactions from a
+// full pool to take the room reserved for their sender.
+func TestTransactionPoolPriorityLaneEviction(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := newTestBlockchain(statedb, 1000000, new(event.Feed))
+
+	remoteKey, _ := crypto.GenerateKey()
+	priorityKey, _ := crypto.GenerateKey()
+	priorityAddr := crypto.PubkeyToAddress(priorityKey.PublicKey)
+
+	config := testTxPoolConfig
+	config.GlobalSlots = 2
+	config.GlobalQueue = 2
+	config.PriorityLane = PriorityLaneConfig{Senders: []common.Address{priorityAddr}}
+
+	pool := NewTxPool(config, params.TestChainConfig, blockchain)
+	defer pool.Stop()
+
+	testAddBalance(pool, crypto.PubkeyToAddress(remoteKey.PublicKey), big.NewInt(1000000))
+	testAddBalance(pool, priorityAddr, big.NewInt(1000000))
+
+	// Fill the pool with transactions paying more than the priority lane
+	// transactions.
+	for nonce := uint64(0); nonce < 4; nonce++ {
+		if err := pool.addRemoteSync(pricedTransaction(nonce, 100000, big.NewInt(2), remoteKey)); err != nil {
+			t.Fatalf("failed to add remote transaction %d: %v", nonce, err)
+		}
+	}
+
+	// The first transaction of the registered sender takes the room reserved
+	// for it.
+	if err := pool.addRemoteSync(priorityLaneTransaction(0, big.NewInt(1), priorityKey)); err != nil {
+		t.Fatalf("failed to add priority lane transaction: %v", err)
+	}
+	pending, queued := pool.Stats()
+	if pending+queued != 4 {
+		t.Fatalf("expected 4 transactions, got %d", pending+queued)
+	}
+
+	// Further transactions of the sender compete on price.
+	if err := pool.addRemoteSync(priorityLaneTransaction(1, big.NewInt(1), priorityKey)); !errors.Is(err, ErrUnderpriced) {
+		t.Fatalf("expected %v, got %v", ErrUnderpriced, err)
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
@@ -0,0 +1,54 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package rawdb
+
+import (
+	"bytes"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// KeyCategory returns the kind of data stored under [key], using the same
+// categories as InspectDatabase. Keys that don't belong to any category are
+// "Unaccounted".
+func KeyCategory(key []byte) string {
+	switch {
+	case bytes.HasPrefix(key, headerPrefix) && len(key) == (len(headerPrefix)+8+common.HashLength):
+		return "Headers"
+	case bytes.HasPrefix(key, blockBodyPrefix) && len(key) == (len(blockBodyPrefix)+8+common.HashLength):
+		return "Bodies"
+	case bytes.HasPrefix(key, blockReceiptsPrefix) && len(key) == (len(blockReceiptsPrefix)+8+common.HashLength):
+		return "Receipt lists"
+	case bytes.HasPrefix(key, headerPrefix) && bytes.HasSuffix(key, headerHashSuffix):
+		return "Block number->hash"
+	case bytes.HasPrefix(key, headerNumberPrefix) && len(key) == (len(headerNumberPrefix)+common.HashLength):
+		return "Block hash->number"
+	case len(key) == common.HashLength:
+		return "Trie nodes"
+	case bytes.HasPrefix(key, CodePrefix) && len(key) == len(CodePrefix)+common.HashLength:
+		return "Contract codes"
+	case bytes.HasPrefix(key, txLookupPrefix) && len(key) == (len(txLookupPrefix)+common.HashLength):
+		return "Transaction index"
+	case bytes.HasPrefix(key, SnapshotAccountPrefix) && len(key) == (len(SnapshotAccountPrefix)+common.HashLength):
+		return "Account snapshot"
+	case bytes.HasPrefix(key, SnapshotStoragePrefix) && len(key) == (len(SnapshotStoragePrefix)+2*common.HashLength):
+		return "Storage snapshot"
+	case bytes.HasPrefix(key, preimagePrefix) && len(key) == (len(preimagePrefix)+common.HashLength):
+		return "Trie preimages"
+	case bytes.HasPrefix(key, configPrefix) && len(key) == (len(configPrefix)+common.HashLength):
+		return "Singleton metadata"
+	case bytes.HasPrefix(key, bloomBitsPrefix) && len(key) == (len(bloomBitsPrefix)+10+common.HashLength),
+		bytes.HasPrefix(key, BloomBitsIndexPrefix):
+		return "Bloombit index"
+	}
+	for _, meta := range [][]byte{
+		databaseVersionKey, headHeaderKey, headBlockKey,
+		snapshotRootKey, snapshotGeneratorKey, uncleanShutdownKey,
+	} {
+		if bytes.Equal(key, meta) {
+			return "Singleton metadata"
+		}
+	}
+	return "Unaccounted"
+}
@@ -47,6 +47,7 @@ import (
 	"github.com/flare-foundation/flare/coreth/ethdb"
 	"github.com/flare-foundation/flare/coreth/params"
 	"github.com/flare-foundation/flare/coreth/rpc"
+	"github.com/flare-foundation/flare/snow"
 )
 
 var (
@@ -58,6 +59,7 @@ var (
 type EthAPIBackend struct {
 	extRPCEnabled       bool
 	allowUnprotectedTxs bool
+	readOnly            bool
 	eth                 *Ethereum
 	gpo                 *gasprice.Oracle
 }
@@ -292,6 +294,9 @@ func (b *EthAPIBackend) SendTx(ctx context.Context, signedTx *types.Transaction)
 	if deadline, exists := ctx.Deadline(); exists && time.Until(deadline) < 0 {
 		return errExpired
 	}
+	if b.readOnly {
+		return snow.ErrReadOnly
+	}
 	return b.eth.txPool.AddLocal(signedTx)
 }
 
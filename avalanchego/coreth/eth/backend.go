@@ -215,6 +215,7 @@ func New(
 	eth.APIBackend = &EthAPIBackend{
 		extRPCEnabled:       stack.Config().ExtRPCEnabled(),
 		allowUnprotectedTxs: config.AllowUnprotectedTxs,
+		readOnly:            config.ReadOnly,
 		eth:                 eth,
 	}
 	if config.AllowUnprotectedTxs {
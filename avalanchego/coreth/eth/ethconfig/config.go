@@ -145,6 +145,10 @@ type Config struct {
 	// replay protection.
 	AllowUnprotectedTxs bool
 
+	// ReadOnly rejects the transactions submitted through the API, as the
+	// node doesn't participate in consensus
+	ReadOnly bool
+
 	// OfflinePruning enables offline pruning on startup of the node. If a node is started
 	// with this configuration option, it must finish pruning before resuming normal operation.
 	OfflinePruning                bool
@@ -256,30 +256,84 @@ func (api *PublicFilterAPI) NewBlockFilter() rpc.ID {
 }
 
 // NewHeads send a notification each time a new (header) block is appended to the chain.
-func (api *PublicFilterAPI) NewHeads(ctx context.Context) (*rpc.Subscription, error) {
+//
+// The optional [opts] select whether headers are sent once their block is
+// preferred or only once it is accepted, and allow a reconnecting client to
+// replay the accepted headers it missed before new headers are sent.
+func (api *PublicFilterAPI) NewHeads(ctx context.Context, opts *SubscriptionOptions) (*rpc.Subscription, error) {
 	notifier, supported := rpc.NotifierFromContext(ctx)
 	if !supported {
 		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
 	}
 
+	acceptedOnly, err := api.acceptedOnly(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		headers    = make(chan *types.Header)
+		replayed   = make(chan []*types.Header, 1)
+		headersSub event.Subscription
+	)
+
+	if acceptedOnly {
+		headersSub = api.events.SubscribeAcceptedHeads(headers)
+	} else {
+		headersSub = api.events.SubscribeNewHeads(headers)
+	}
+
+	// The range to replay is read after subscribing, so that the headers
+	// accepted in between are delivered by the subscription. Headers that are
+	// both replayed and delivered are only sent once.
+	var (
+		resume     *ResumeToken
+		start, end uint64
+	)
+	if opts != nil && opts.ResumeFrom != nil {
+		resume = opts.ResumeFrom
+		start, end, err = api.resumeRange(resume)
+		if err != nil {
+			headersSub.Unsubscribe()
+			return nil, err
+		}
+	}
+
 	rpcSub := notifier.CreateSubscription()
 
 	go func() {
 		var (
-			headers    = make(chan *types.Header)
-			headersSub event.Subscription
+			cursor    = newEventCursor(resume)
+			replaying = resume != nil
+			pending   []*types.Header
 		)
-
-		if api.backend.GetVMConfig().AllowUnfinalizedQueries {
-			headersSub = api.events.SubscribeNewHeads(headers)
-		} else {
-			headersSub = api.events.SubscribeAcceptedHeads(headers)
+		if !replaying {
+			replayed = nil
 		}
 
 		for {
 			select {
+			case replay, ok := <-replayed:
+				if !ok {
+					headersSub.Unsubscribe()
+					return
+				}
+				// Headers accepted while replaying were buffered and are
+				// sent after the replayed headers.
+				for _, h := range append(replay, pending...) {
+					if cursor.advanceHeader(h) {
+						notifier.Notify(rpcSub.ID, h)
+					}
+				}
+				replaying, replayed, pending = false, nil, nil
 			case h := <-headers:
-				notifier.Notify(rpcSub.ID, h)
+				if replaying {
+					pending = append(pending, h)
+					continue
+				}
+				if cursor.advanceHeader(h) {
+					notifier.Notify(rpcSub.ID, h)
+				}
 			case <-rpcSub.Err():
 				headersSub.Unsubscribe()
 				return
@@ -290,41 +344,105 @@ func (api *PublicFilterAPI) NewHeads(ctx context.Context) (*rpc.Subscription, er
 		}
 	}()
 
+	if resume != nil {
+		replay, err := api.replayHeaders(ctx, start, end)
+		if err != nil {
+			close(replayed)
+			return nil, err
+		}
+		replayed <- replay
+	}
+
 	return rpcSub, nil
 }
 
 // Logs creates a subscription that fires for all new log that match the given filter criteria.
-func (api *PublicFilterAPI) Logs(ctx context.Context, crit FilterCriteria) (*rpc.Subscription, error) {
+//
+// The optional [opts] select whether logs are sent once their block is
+// preferred, including logs marked as removed when a preferred block is
+// abandoned, or only once it is accepted. They also allow a reconnecting
+// client to replay the accepted logs it missed before new logs are sent.
+func (api *PublicFilterAPI) Logs(ctx context.Context, crit FilterCriteria, opts *SubscriptionOptions) (*rpc.Subscription, error) {
 	notifier, supported := rpc.NotifierFromContext(ctx)
 	if !supported {
 		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
 	}
 
+	acceptedOnly, err := api.acceptedOnly(opts)
+	if err != nil {
+		return nil, err
+	}
+
 	var (
-		rpcSub      = notifier.CreateSubscription()
 		matchedLogs = make(chan []*types.Log)
+		replayed    = make(chan []*types.Log, 1)
 		logsSub     event.Subscription
-		err         error
 	)
 
-	if api.backend.GetVMConfig().AllowUnfinalizedQueries {
-		logsSub, err = api.events.SubscribeLogs(interfaces.FilterQuery(crit), matchedLogs)
+	if acceptedOnly {
+		logsSub, err = api.events.SubscribeAcceptedLogs(interfaces.FilterQuery(crit), matchedLogs)
 		if err != nil {
 			return nil, err
 		}
 	} else {
-		logsSub, err = api.events.SubscribeAcceptedLogs(interfaces.FilterQuery(crit), matchedLogs)
+		logsSub, err = api.events.SubscribeLogs(interfaces.FilterQuery(crit), matchedLogs)
 		if err != nil {
 			return nil, err
 		}
 	}
 
+	// The range to replay is read after subscribing, so that the logs
+	// accepted in between are delivered by the subscription. Logs that are
+	// both replayed and delivered are only sent once.
+	var (
+		resume     *ResumeToken
+		start, end uint64
+	)
+	if opts != nil && opts.ResumeFrom != nil {
+		resume = opts.ResumeFrom
+		start, end, err = api.resumeRange(resume)
+		if err != nil {
+			logsSub.Unsubscribe()
+			return nil, err
+		}
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
 	go func() {
+		var (
+			cursor    = newEventCursor(resume)
+			replaying = resume != nil
+			pending   []*types.Log
+		)
+		if !replaying {
+			replayed = nil
+		}
+
 		for {
 			select {
+			case replay, ok := <-replayed:
+				if !ok {
+					logsSub.Unsubscribe()
+					return
+				}
+				// Logs accepted while replaying were buffered and are sent
+				// after the replayed logs.
+				for _, log := range append(replay, pending...) {
+					if cursor.advanceLog(log) {
+						notifier.Notify(rpcSub.ID, &log)
+					}
+				}
+				replaying, replayed, pending = false, nil, nil
 			case logs := <-matchedLogs:
+				if replaying {
+					pending = append(pending, logs...)
+					continue
+				}
 				for _, log := range logs {
-					notifier.Notify(rpcSub.ID, &log)
+					if cursor.advanceLog(log) {
+						notifier.Notify(rpcSub.ID, &log)
+					}
 				}
 			case <-rpcSub.Err(): // client send an unsubscribe request
 				logsSub.Unsubscribe()
@@ -336,6 +454,15 @@ func (api *PublicFilterAPI) Logs(ctx context.Context, crit FilterCriteria) (*rpc
 		}
 	}()
 
+	if resume != nil {
+		replay, err := api.replayLogs(ctx, interfaces.FilterQuery(crit), start, end)
+		if err != nil {
+			close(replayed)
+			return nil, err
+		}
+		replayed <- replay
+	}
+
 	return rpcSub, nil
 }
 
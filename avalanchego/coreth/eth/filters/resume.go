@@ -0,0 +1,213 @@
+// (c) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package filters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/flare-foundation/flare/coreth/core/types"
+	"github.com/flare-foundation/flare/coreth/interfaces"
+	"github.com/flare-foundation/flare/coreth/rpc"
+)
+
+const (
+	// FinalityAccepted notifies subscribers only once the block containing the
+	// event has been accepted by Snowman consensus.
+	FinalityAccepted = "accepted"
+	// FinalityPreferred notifies subscribers as soon as the block containing the
+	// event is processed. Logs of a preferred block that is later abandoned are
+	// sent again with removed set to true.
+	FinalityPreferred = "preferred"
+
+	// defaultMaxResumeBlocks bounds the number of blocks replayed when a
+	// subscription is resumed if the node doesn't limit the number of blocks
+	// per request, so that an old resume token can't trigger a scan of the
+	// whole chain.
+	defaultMaxResumeBlocks = 2048
+)
+
+var (
+	errUnknownFinality           = errors.New("unknown finality, expected \"accepted\" or \"preferred\"")
+	errUnfinalizedSubscription   = errors.New("preferred subscriptions require unfinalized queries to be allowed")
+	errResumeRequiresAcceptance  = errors.New("subscriptions can only be resumed with accepted finality")
+	errResumeFromUnacceptedBlock = errors.New("resume block is after the last accepted block")
+)
+
+// SubscriptionOptions are the optional parameters of the logs and newHeads
+// subscriptions.
+type SubscriptionOptions struct {
+	// Finality is either FinalityAccepted or FinalityPreferred. If empty, the
+	// node default is used, which is preferred if unfinalized queries are
+	// allowed and accepted otherwise.
+	Finality string `json:"finality"`
+	// ResumeFrom, if set, replays the accepted events after the token before
+	// any new events are sent.
+	ResumeFrom *ResumeToken `json:"resumeFrom"`
+}
+
+// ResumeToken identifies the last event a client received from a
+// subscription. If LogIndex is nil, every event of BlockNumber is considered
+// received.
+type ResumeToken struct {
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	LogIndex    *hexutil.Uint  `json:"logIndex"`
+}
+
+// acceptedOnly returns true if a subscription created with [opts] must only be
+// notified of accepted blocks.
+func (api *PublicFilterAPI) acceptedOnly(opts *SubscriptionOptions) (bool, error) {
+	allowUnfinalized := api.backend.GetVMConfig().AllowUnfinalizedQueries
+	if opts == nil {
+		return !allowUnfinalized, nil
+	}
+
+	switch opts.Finality {
+	case "":
+		// Resuming is only possible from the accepted index, so a resume
+		// token implies accepted finality.
+		return !allowUnfinalized || opts.ResumeFrom != nil, nil
+	case FinalityAccepted:
+		return true, nil
+	case FinalityPreferred:
+		if !allowUnfinalized {
+			return false, errUnfinalizedSubscription
+		}
+		if opts.ResumeFrom != nil {
+			return false, errResumeRequiresAcceptance
+		}
+		return false, nil
+	default:
+		return false, errUnknownFinality
+	}
+}
+
+// eventCursor tracks the position of the last event delivered to a subscriber
+// so that events are never delivered twice when replayed events overlap with
+// newly accepted ones. A nil cursor accepts every event.
+type eventCursor struct {
+	blockNumber uint64
+	// hasIndex is false if every log of [blockNumber] has been delivered.
+	hasIndex bool
+	logIndex uint
+}
+
+func newEventCursor(token *ResumeToken) *eventCursor {
+	if token == nil {
+		return nil
+	}
+	c := &eventCursor{
+		blockNumber: uint64(token.BlockNumber),
+	}
+	if token.LogIndex != nil {
+		c.hasIndex = true
+		c.logIndex = uint(*token.LogIndex)
+	}
+	return c
+}
+
+// advanceLog returns true and moves the cursor if [log] has not been delivered.
+func (c *eventCursor) advanceLog(log *types.Log) bool {
+	if c == nil {
+		return true
+	}
+	if log.BlockNumber < c.blockNumber {
+		return false
+	}
+	if log.BlockNumber == c.blockNumber && (!c.hasIndex || log.Index <= c.logIndex) {
+		return false
+	}
+	c.blockNumber = log.BlockNumber
+	c.hasIndex = true
+	c.logIndex = log.Index
+	return true
+}
+
+// advanceHeader returns true and moves the cursor if [header] has not been
+// delivered.
+func (c *eventCursor) advanceHeader(header *types.Header) bool {
+	if c == nil {
+		return true
+	}
+	number := header.Number.Uint64()
+	if number <= c.blockNumber {
+		return false
+	}
+	c.blockNumber = number
+	c.hasIndex = false
+	return true
+}
+
+// resumeRange returns the first block that must be replayed for [token] and
+// the last accepted block number.
+func (api *PublicFilterAPI) resumeRange(token *ResumeToken) (uint64, uint64, error) {
+	lastAccepted := api.backend.LastAcceptedBlock()
+	if lastAccepted == nil {
+		return 0, 0, errResumeFromUnacceptedBlock
+	}
+	end := lastAccepted.NumberU64()
+	start := uint64(token.BlockNumber)
+	if token.LogIndex == nil {
+		start++
+	}
+	if start > end+1 || (start == end+1 && token.LogIndex != nil) {
+		return 0, 0, fmt.Errorf("%w: %d > %d", errResumeFromUnacceptedBlock, token.BlockNumber, end)
+	}
+	maxBlocks := api.backend.GetMaxBlocksPerRequest()
+	if maxBlocks <= 0 {
+		maxBlocks = defaultMaxResumeBlocks
+	}
+	if int64(end+1-start) > maxBlocks {
+		return 0, 0, fmt.Errorf("resuming requires replaying %d blocks, maximum is set to %d", end+1-start, maxBlocks)
+	}
+	return start, end, nil
+}
+
+// replayLogs returns the accepted logs matching [crit] in the blocks
+// [start, end].
+func (api *PublicFilterAPI) replayLogs(ctx context.Context, crit interfaces.FilterQuery, start, end uint64) ([]*types.Log, error) {
+	var matched []*types.Log
+	for number := start; number <= end; number++ {
+		header, err := api.backend.HeaderByNumber(ctx, rpc.BlockNumber(number))
+		if err != nil {
+			return nil, err
+		}
+		if header == nil {
+			return nil, fmt.Errorf("missing accepted block %d", number)
+		}
+		if !bloomFilter(header.Bloom, crit.Addresses, crit.Topics) {
+			continue
+		}
+		// Receipts are used instead of the stored logs as only receipts
+		// have their derived fields, such as the log index, populated.
+		receipts, err := api.backend.GetReceipts(ctx, header.Hash())
+		if err != nil {
+			return nil, err
+		}
+		var unfiltered []*types.Log
+		for _, receipt := range receipts {
+			unfiltered = append(unfiltered, receipt.Logs...)
+		}
+		matched = append(matched, filterLogs(unfiltered, crit.FromBlock, crit.ToBlock, crit.Addresses, crit.Topics)...)
+	}
+	return matched, nil
+}
+
+// replayHeaders returns the accepted headers of the blocks [start, end].
+func (api *PublicFilterAPI) replayHeaders(ctx context.Context, start, end uint64) ([]*types.Header, error) {
+	headers := make([]*types.Header, 0, end+1-start)
+	for number := start; number <= end; number++ {
+		header, err := api.backend.HeaderByNumber(ctx, rpc.BlockNumber(number))
+		if err != nil {
+			return nil, err
+		}
+		if header == nil {
+			return nil, fmt.Errorf("missing accepted block %d", number)
+		}
+		headers = append(headers, header)
+	}
+	return headers, nil
+}
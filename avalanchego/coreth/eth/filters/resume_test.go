@@ -0,0 +1,418 @@
+// (c) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package filters
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/flare-foundation/flare/coreth/core"
+	"github.com/flare-foundation/flare/coreth/core/bloombits"
+	"github.com/flare-foundation/flare/coreth/core/rawdb"
+	"github.com/flare-foundation/flare/coreth/core/types"
+	"github.com/flare-foundation/flare/coreth/core/vm"
+	"github.com/flare-foundation/flare/coreth/ethdb"
+	"github.com/flare-foundation/flare/coreth/interfaces"
+	"github.com/flare-foundation/flare/coreth/rpc"
+)
+
+func TestUnmarshalSubscriptionOptions(t *testing.T) {
+	var opts SubscriptionOptions
+	vector := `{"finality": "accepted", "resumeFrom": {"blockNumber": "0x10", "logIndex": "0x2"}}`
+	if err := json.Unmarshal([]byte(vector), &opts); err != nil {
+		t.Fatal(err)
+	}
+	if opts.Finality != FinalityAccepted {
+		t.Fatalf("expected finality %q, got %q", FinalityAccepted, opts.Finality)
+	}
+	if opts.ResumeFrom == nil {
+		t.Fatal("expected resume token")
+	}
+	if opts.ResumeFrom.BlockNumber != 16 {
+		t.Fatalf("expected block number 16, got %d", opts.ResumeFrom.BlockNumber)
+	}
+	if opts.ResumeFrom.LogIndex == nil || *opts.ResumeFrom.LogIndex != 2 {
+		t.Fatalf("expected log index 2, got %v", opts.ResumeFrom.LogIndex)
+	}
+}
+
+func TestEventCursorLogs(t *testing.T) {
+	var nilCursor *eventCursor
+	if !nilCursor.advanceLog(&types.Log{BlockNumber: 1}) {
+		t.Fatal("nil cursor should accept every log")
+	}
+
+	index := uint(2)
+	cursor := newEventCursor(&ResumeToken{BlockNumber: 5, LogIndex: (*hexutil.Uint)(&index)})
+	tests := []struct {
+		blockNumber uint64
+		index       uint
+		expected    bool
+	}{
+		{blockNumber: 4, index: 7, expected: false},
+		{blockNumber: 5, index: 1, expected: false},
+		{blockNumber: 5, index: 2, expected: false},
+		{blockNumber: 5, index: 3, expected: true},
+		{blockNumber: 5, index: 3, expected: false},
+		{blockNumber: 6, index: 0, expected: true},
+		{blockNumber: 5, index: 4, expected: false},
+	}
+	for i, test := range tests {
+		log := &types.Log{BlockNumber: test.blockNumber, Index: test.index}
+		if got := cursor.advanceLog(log); got != test.expected {
+			t.Fatalf("test %d: expected %t, got %t", i, test.expected, got)
+		}
+	}
+
+	// Without a log index every log of the resume block was received.
+	cursor = newEventCursor(&ResumeToken{BlockNumber: 5})
+	if cursor.advanceLog(&types.Log{BlockNumber: 5, Index: 9}) {
+		t.Fatal("expected log of the resume block to be skipped")
+	}
+	if !cursor.advanceLog(&types.Log{BlockNumber: 6}) {
+		t.Fatal("expected log after the resume block to be delivered")
+	}
+}
+
+func TestEventCursorHeaders(t *testing.T) {
+	cursor := newEventCursor(&ResumeToken{BlockNumber: 5})
+	for i, test := range []struct {
+		number   int64
+		expected bool
+	}{
+		{number: 4, expected: false},
+		{number: 5, expected: false},
+		{number: 6, expected: true},
+		{number: 6, expected: false},
+		{number: 7, expected: true},
+	} {
+		header := &types.Header{Number: big.NewInt(test.number)}
+		if got := cursor.advanceHeader(header); got != test.expected {
+			t.Fatalf("test %d: expected %t, got %t", i, test.expected, got)
+		}
+	}
+}
+
+// testBackend is a Backend serving an accepted chain held in memory.
+type testBackend struct {
+	headers   []*types.Header
+	receipts  map[common.Hash]types.Receipts
+	maxBlocks int64
+
+	// onLastAccepted is called after the last accepted block is read
+	onLastAccepted func()
+
+	txsFeed           event.Feed
+	logsFeed          event.Feed
+	acceptedLogsFeed  event.Feed
+	rmLogsFeed        event.Feed
+	pendingLogsFeed   event.Feed
+	chainFeed         event.Feed
+	chainAcceptedFeed event.Feed
+	acceptedTxsFeed   event.Feed
+}
+
+// newTestBackend returns a backend whose accepted chain contains a block for
+// each entry of [logs], with the logs of each block emitted by one receipt.
+func newTestBackend(logs [][]*types.Log) *testBackend {
+	b := &testBackend{
+		receipts: make(map[common.Hash]types.Receipts),
+	}
+	for number, blockLogs := range logs {
+		receipts := types.Receipts{{Logs: blockLogs}}
+		receipts[0].Bloom = types.CreateBloom(receipts)
+		header := &types.Header{
+			Number: big.NewInt(int64(number)),
+			Bloom:  types.CreateBloom(receipts),
+		}
+		for _, log := range blockLogs {
+			// Topics are required when logs are sent over RPC.
+			if log.Topics == nil {
+				log.Topics = []common.Hash{}
+			}
+			log.BlockNumber = uint64(number)
+			log.BlockHash = header.Hash()
+		}
+		b.headers = append(b.headers, header)
+		b.receipts[header.Hash()] = receipts
+	}
+	return b
+}
+
+func (b *testBackend) ChainDb() ethdb.Database { return rawdb.NewMemoryDatabase() }
+
+func (b *testBackend) HeaderByNumber(_ context.Context, number rpc.BlockNumber) (*types.Header, error) {
+	if number < 0 || int(number) >= len(b.headers) {
+		return nil, nil
+	}
+	return b.headers[number], nil
+}
+
+func (b *testBackend) HeaderByHash(_ context.Context, hash common.Hash) (*types.Header, error) {
+	for _, header := range b.headers {
+		if header.Hash() == hash {
+			return header, nil
+		}
+	}
+	return nil, nil
+}
+
+func (b *testBackend) GetReceipts(_ context.Context, hash common.Hash) (types.Receipts, error) {
+	return b.receipts[hash], nil
+}
+
+func (b *testBackend) GetLogs(_ context.Context, hash common.Hash) ([][]*types.Log, error) {
+	var logs [][]*types.Log
+	for _, receipt := range b.receipts[hash] {
+		logs = append(logs, receipt.Logs)
+	}
+	return logs, nil
+}
+
+func (b *testBackend) SubscribeNewTxsEvent(ch chan<- core.NewTxsEvent) event.Subscription {
+	return b.txsFeed.Subscribe(ch)
+}
+
+func (b *testBackend) SubscribeChainEvent(ch chan<- core.ChainEvent) event.Subscription {
+	return b.chainFeed.Subscribe(ch)
+}
+
+func (b *testBackend) SubscribeChainAcceptedEvent(ch chan<- core.ChainEvent) event.Subscription {
+	return b.chainAcceptedFeed.Subscribe(ch)
+}
+
+func (b *testBackend) SubscribeRemovedLogsEvent(ch chan<- core.RemovedLogsEvent) event.Subscription {
+	return b.rmLogsFeed.Subscribe(ch)
+}
+
+func (b *testBackend) SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscription {
+	return b.logsFeed.Subscribe(ch)
+}
+
+func (b *testBackend) SubscribeAcceptedLogsEvent(ch chan<- []*types.Log) event.Subscription {
+	return b.acceptedLogsFeed.Subscribe(ch)
+}
+
+func (b *testBackend) SubscribePendingLogsEvent(ch chan<- []*types.Log) event.Subscription {
+	return b.pendingLogsFeed.Subscribe(ch)
+}
+
+func (b *testBackend) SubscribeAcceptedTransactionEvent(ch chan<- core.NewTxsEvent) event.Subscription {
+	return b.acceptedTxsFeed.Subscribe(ch)
+}
+
+func (b *testBackend) BloomStatus() (uint64, uint64) { return 0, 0 }
+
+func (b *testBackend) ServiceFilter(context.Context, *bloombits.MatcherSession) {}
+
+func (b *testBackend) GetVMConfig() *vm.Config {
+	return &vm.Config{AllowUnfinalizedQueries: true}
+}
+
+func (b *testBackend) LastAcceptedBlock() *types.Block {
+	if len(b.headers) == 0 {
+		return nil
+	}
+	block := types.NewBlockWithHeader(b.headers[len(b.headers)-1])
+	if b.onLastAccepted != nil {
+		b.onLastAccepted()
+	}
+	return block
+}
+
+func (b *testBackend) GetMaxBlocksPerRequest() int64 { return b.maxBlocks }
+
+func TestResumeRangeDefaultLimit(t *testing.T) {
+	backend := newTestBackend(make([][]*types.Log, defaultMaxResumeBlocks+2))
+	api := NewPublicFilterAPI(backend, false, time.Minute)
+
+	// Without a configured limit, replays are bounded by the default.
+	if _, _, err := api.resumeRange(&ResumeToken{BlockNumber: 0}); err == nil {
+		t.Fatal("expected replay exceeding the default limit to fail")
+	}
+	start, end, err := api.resumeRange(&ResumeToken{BlockNumber: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if start != 2 || end != defaultMaxResumeBlocks+1 {
+		t.Fatalf("expected range [2, %d], got [%d, %d]", defaultMaxResumeBlocks+1, start, end)
+	}
+
+	// A configured limit takes precedence over the default.
+	backend.maxBlocks = 10
+	if _, _, err := api.resumeRange(&ResumeToken{BlockNumber: defaultMaxResumeBlocks - 10}); err == nil {
+		t.Fatal("expected replay exceeding the configured limit to fail")
+	}
+	if _, _, err := api.resumeRange(&ResumeToken{BlockNumber: defaultMaxResumeBlocks - 9}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := api.resumeRange(&ResumeToken{BlockNumber: defaultMaxResumeBlocks + 2}); !errors.Is(err, errResumeFromUnacceptedBlock) {
+		t.Fatalf("expected %v, got %v", errResumeFromUnacceptedBlock, err)
+	}
+}
+
+func TestLogsSubscriptionReplay(t *testing.T) {
+	var (
+		addr  = common.HexToAddress("0x1")
+		other = common.HexToAddress("0x2")
+	)
+	backend := newTestBackend([][]*types.Log{
+		{{Address: addr, Index: 0}},
+		{{Address: addr, Index: 1}, {Address: addr, Index: 2}, {Address: other, Index: 3}},
+		{},
+		{{Address: addr, Index: 4}},
+	})
+	api := NewPublicFilterAPI(backend, false, time.Minute)
+
+	server := rpc.NewServer(0)
+	defer server.Stop()
+	if err := server.RegisterName("eth", api); err != nil {
+		t.Fatal(err)
+	}
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	logs := make(chan types.Log)
+	crit := map[string]interface{}{"address": addr}
+	opts := map[string]interface{}{
+		"resumeFrom": map[string]interface{}{"blockNumber": "0x1", "logIndex": "0x1"},
+	}
+	sub, err := client.EthSubscribe(context.Background(), logs, "logs", crit, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Unsubscribe()
+
+	// Logs of the resume block up to and including the resume index and logs
+	// of other addresses must not be replayed.
+	expectLog := func(blockNumber uint64, index uint) {
+		t.Helper()
+		select {
+		case log := <-logs:
+			if log.BlockNumber != blockNumber || log.Index != index || log.Address != addr {
+				t.Fatalf("expected log %d of block %d, got log %d of block %d", index, blockNumber, log.Index, log.BlockNumber)
+			}
+		case err := <-sub.Err():
+			t.Fatal(err)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for log %d of block %d", index, blockNumber)
+		}
+	}
+	expectLog(1, 2)
+	expectLog(3, 4)
+
+	// Logs accepted after the replay, or already replayed, are delivered once.
+	backend.acceptedLogsFeed.Send([]*types.Log{
+		{Address: addr, Topics: []common.Hash{}, BlockNumber: 3, Index: 4},
+		{Address: addr, Topics: []common.Hash{}, BlockNumber: 4, Index: 5},
+	})
+	expectLog(4, 5)
+}
+
+func TestNewHeadsSubscriptionReplayRace(t *testing.T) {
+	backend := newTestBackend(make([][]*types.Log, 3))
+	api := NewPublicFilterAPI(backend, false, time.Minute)
+
+	// A block is accepted right after the range to replay is read, so it is
+	// only delivered if the subscription was registered before.
+	accepted := &types.Header{Number: big.NewInt(3)}
+	backend.onLastAccepted = func() {
+		backend.onLastAccepted = nil
+		backend.chainAcceptedFeed.Send(core.ChainEvent{Block: types.NewBlockWithHeader(accepted)})
+	}
+
+	server := rpc.NewServer(0)
+	defer server.Stop()
+	if err := server.RegisterName("eth", api); err != nil {
+		t.Fatal(err)
+	}
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	headers := make(chan map[string]interface{})
+	opts := map[string]interface{}{
+		"finality":   "accepted",
+		"resumeFrom": map[string]interface{}{"blockNumber": "0x0"},
+	}
+	sub, err := client.EthSubscribe(context.Background(), headers, "newHeads", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Unsubscribe()
+
+	for _, expected := range []uint64{1, 2, 3} {
+		select {
+		case header := <-headers:
+			if header["number"] != hexutil.EncodeUint64(expected) {
+				t.Fatalf("expected block %d, got block %v", expected, header["number"])
+			}
+		case err := <-sub.Err():
+			t.Fatal(err)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for block %d", expected)
+		}
+	}
+}
+
+func TestRemovedLogsAfterReorg(t *testing.T) {
+	addr := common.HexToAddress("0x1")
+	backend := newTestBackend([][]*types.Log{{}})
+	es := NewEventSystem(backend, false)
+
+	var (
+		preferredLogs = make(chan []*types.Log)
+		acceptedLogs  = make(chan []*types.Log)
+		crit          = FilterCriteria{Addresses: []common.Address{addr}}
+	)
+	preferredSub, err := es.SubscribeLogs(interfaces.FilterQuery(crit), preferredLogs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer preferredSub.Unsubscribe()
+	acceptedSub, err := es.SubscribeAcceptedLogs(interfaces.FilterQuery(crit), acceptedLogs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer acceptedSub.Unsubscribe()
+
+	processed := &types.Log{Address: addr, BlockNumber: 1, TxHash: common.HexToHash("0xa")}
+	backend.logsFeed.Send([]*types.Log{processed})
+	select {
+	case logs := <-preferredLogs:
+		if len(logs) != 1 || logs[0].Removed {
+			t.Fatalf("expected processed log, got %v", logs)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for processed log")
+	}
+
+	// The block is abandoned in favour of another block at the same height.
+	removed := *processed
+	removed.Removed = true
+	backend.rmLogsFeed.Send(core.RemovedLogsEvent{Logs: []*types.Log{&removed}})
+	select {
+	case logs := <-preferredLogs:
+		if len(logs) != 1 || !logs[0].Removed || logs[0].TxHash != processed.TxHash {
+			t.Fatalf("expected removed log, got %v", logs)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for removed log")
+	}
+
+	// Accepted subscriptions never saw the log, so they aren't notified of
+	// its removal.
+	select {
+	case logs := <-acceptedLogs:
+		t.Fatalf("unexpected logs on accepted subscription: %v", logs)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -165,6 +165,22 @@ func (w *worker) commitNewWork() (*types.Block, error) {
 	// Fill the block with all available pending transactions.
 	pending := w.eth.TxPool().Pending(true)
 
+	// Transactions in the priority lane are committed before any other
+	// transaction, the remaining transactions of their senders follow the
+	// regular ordering. As the lane is drained first, the part of its gas
+	// reserve it doesn't use is left to the other transactions.
+	lane := w.eth.TxPool().PriorityLane()
+	if priorityTxs := lane.Select(pending); len(priorityTxs) > 0 {
+		committed := w.commitPriorityTransactions(env, lane, priorityTxs, w.coinbase)
+		for _, from := range committed {
+			if txs := pending[from][1:]; len(txs) > 0 {
+				pending[from] = txs
+			} else {
+				delete(pending, from)
+			}
+		}
+	}
+
 	// Split the pending transactions into locals and remotes
 	localTxs := make(map[common.Address]types.Transactions)
 	remoteTxs := pending
@@ -279,6 +295,45 @@ func (w *worker) commitTransactions(env *environment, txs *types.TransactionsByP
 	}
 }
 
+// commitPriorityTransactions commits the transactions selected by [lane] for
+// as long as they fit in the lane, and returns the senders of the committed
+// transactions.
+func (w *worker) commitPriorityTransactions(env *environment, lane *core.PriorityLane, priorityTxs map[common.Address]types.Transactions, coinbase common.Address) []common.Address {
+	var (
+		committed []common.Address
+		gasUsed   uint64
+		txs       = types.NewTransactionsByPriceAndNonce(env.signer, priorityTxs, env.header.BaseFee)
+	)
+	for {
+		tx := txs.Peek()
+		if tx == nil {
+			break
+		}
+		// Each sender has a single transaction in the lane, so popping the
+		// transaction also skips the sender.
+		txs.Pop()
+		if !lane.Fits(len(committed), gasUsed, tx.Gas()) {
+			log.Trace("Priority lane full, skipping transaction", "hash", tx.Hash(), "gas", tx.Gas())
+			continue
+		}
+		if tx.Protected() && !w.chainConfig.IsEIP155(env.header.Number) {
+			continue
+		}
+		from, _ := types.Sender(env.signer, tx)
+		env.state.Prepare(tx.Hash(), env.tcount)
+
+		before := env.header.GasUsed
+		if _, err := w.commitTransaction(env, tx, coinbase); err != nil {
+			log.Debug("Priority lane transaction failed", "hash", tx.Hash(), "sender", from, "err", err)
+			continue
+		}
+		env.tcount++
+		gasUsed += env.header.GasUsed - before
+		committed = append(committed, from)
+	}
+	return committed
+}
+
 // commit runs any post-transaction state modifications, assembles the final block
 // and commits new work if consensus engine is running.
 func (w *worker) commit(env *environment) (*types.Block, error) {
@@ -4,6 +4,7 @@
 package peer
 
 import (
+	"github.com/flare-foundation/flare/ids"
 	"github.com/flare-foundation/flare/version"
 )
 
@@ -19,6 +20,9 @@ type Client interface {
 
 	// Gossip sends given gossip message to peers
 	Gossip(gossip []byte) error
+
+	// GossipSpecific sends given gossip message to the peers in [nodeIDs]
+	GossipSpecific(nodeIDs ids.ShortSet, gossip []byte) error
 }
 
 // client implements Client interface
@@ -44,6 +48,10 @@ func (c *client) Gossip(gossip []byte) error {
 	return c.network.Gossip(gossip)
 }
 
+func (c *client) GossipSpecific(nodeIDs ids.ShortSet, gossip []byte) error {
+	return c.network.GossipSpecific(nodeIDs, gossip)
+}
+
 // NewClient returns Client for a given network
 func NewClient(network Network) Client {
 	return &client{
@@ -45,6 +45,9 @@ type Network interface {
 	// Gossip sends given gossip message to peers
 	Gossip(gossip []byte) error
 
+	// GossipSpecific sends given gossip message to the peers in [nodeIDs]
+	GossipSpecific(nodeIDs ids.ShortSet, gossip []byte) error
+
 	// Shutdown stops all peer channel listeners and marks the node to have stopped
 	// n.Start() can be called again but the peers will have to be reconnected
 	// by calling OnPeerConnected for each peer
@@ -252,6 +255,11 @@ func (n *network) Gossip(gossip []byte) error {
 	return n.appSender.SendAppGossip(gossip)
 }
 
+// GossipSpecific sends given gossip message to the peers in [nodeIDs]
+func (n *network) GossipSpecific(nodeIDs ids.ShortSet, gossip []byte) error {
+	return n.appSender.SendAppGossipSpecific(nodeIDs, gossip)
+}
+
 // AppGossip is called by avalanchego -> VM when there is an incoming AppGossip from a peer
 // error returned by this function is expected to be treated as fatal by the engine
 // returns error if request could not be parsed as message.Request or when the requestHandler returns an error
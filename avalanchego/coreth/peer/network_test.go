@@ -465,6 +465,12 @@ func (t *testGossipHandler) HandleEthTxs(nodeID ids.ShortID, _ *message.EthTxs)
 	return nil
 }
 
+func (t *testGossipHandler) HandlePrivateEthTxs(nodeID ids.ShortID, _ *message.PrivateEthTxs) error {
+	t.received = true
+	t.nodeID = nodeID
+	return nil
+}
+
 type testRequestHandler struct {
 	calls              uint32
 	processingDuration time.Duration
@@ -63,15 +63,28 @@ func (p *Admin) LockProfile(r *http.Request, args *struct{}, reply *api.SuccessR
 
 type SetLogLevelArgs struct {
 	Level string `json:"level"`
+	// Modules overrides the level of the modules it matches, such as
+	// "core/*=5,eth/downloader=4". If empty, the overrides are kept.
+	Modules string `json:"modules"`
 }
 
+// SetLogLevel sets the level of the EVM's log records. The records are also
+// filtered by the levels of the chain's logger, which are set with the
+// admin.setLoggerLevel API of the node.
 func (p *Admin) SetLogLevel(r *http.Request, args *SetLogLevelArgs, reply *api.SuccessResponse) error {
-	log.Info("EVM: SetLogLevel called", "logLevel", args.Level)
-	logLevel, err := log.LvlFromString(args.Level)
-	if err != nil {
-		return fmt.Errorf("failed to parse log level: %w ", err)
+	log.Info("EVM: SetLogLevel called", "logLevel", args.Level, "modules", args.Modules)
+	if args.Level != "" {
+		logLevel, err := log.LvlFromString(args.Level)
+		if err != nil {
+			return fmt.Errorf("failed to parse log level: %w ", err)
+		}
+		p.vm.setLogLevel(logLevel)
+	}
+	if args.Modules != "" {
+		if err := p.vm.setLogModules(args.Modules); err != nil {
+			return fmt.Errorf("failed to parse log modules: %w", err)
+		}
 	}
-	p.vm.setLogLevel(logLevel)
 	reply.Success = true
 	return nil
 }
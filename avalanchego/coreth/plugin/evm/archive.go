@@ -0,0 +1,345 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/flare-foundation/flare/snow/choices"
+	"github.com/flare-foundation/flare/utils/hashing"
+)
+
+// Archives contain a range of accepted blocks, including their extra data,
+// together with the atomic trie roots committed in that range and the blocks
+// of the VM wrapping the chain, such as the proposervm. The archive is an RLP
+// stream made of an [archiveHeader] followed by [archiveSegment]s of at most
+// [archiveSegmentSize] blocks, each of which is protected by a checksum.
+//
+// Version 1 archives don't contain the blocks of the wrapping VM.
+const (
+	archiveVersion     uint64 = 2
+	archiveSegmentSize        = 1024
+)
+
+var (
+	archiveMagic = []byte("flare-chain-archive")
+
+	errNotAnArchive           = errors.New("file is not a chain archive")
+	errArchiveChecksum        = errors.New("archive segment checksum mismatch")
+	errArchiveWouldOverwrite  = errors.New("location would overwrite an existing file")
+	errArchiveInvalidRange    = errors.New("last block of the archive range is before the first")
+	errArchiveUnacceptedRange = errors.New("archive range contains blocks that are not accepted")
+	errArchiveMissingWrapper  = errors.New("archive contains wrapping blocks, but no wrapping VM was provided")
+)
+
+// ArchiveWrapper is implemented by the VM wrapping the chain, so that archives
+// can restore the accepted blocks of the wrapping VM together with the blocks
+// of the chain.
+type ArchiveWrapper interface {
+	// ArchivedBlockBytes returns the bytes of the accepted block wrapping the
+	// accepted block at [height], or nil if the block isn't wrapped.
+	ArchivedBlockBytes(height uint64) ([]byte, error)
+	// AcceptArchivedBlock marks [blockBytes], which wraps the last accepted
+	// block of the chain, as accepted.
+	AcceptArchivedBlock(blockBytes []byte) error
+}
+
+type archiveHeader struct {
+	Magic       []byte
+	Version     uint64
+	ChainID     *big.Int
+	GenesisHash common.Hash
+	First       uint64
+	Last        uint64
+}
+
+type archiveSegment struct {
+	Body     []byte      // RLP encoded archiveSegmentBody
+	Checksum common.Hash // SHA-256 of Body
+}
+
+type archiveSegmentBody struct {
+	Blocks      []rlp.RawValue
+	AtomicRoots []archiveAtomicRoot
+	// Wrappers is either empty or contains the wrapping block of each block,
+	// which is empty if the block isn't wrapped.
+	Wrappers [][]byte `rlp:"optional"`
+}
+
+type archiveAtomicRoot struct {
+	Height uint64
+	Root   common.Hash
+}
+
+// ArchiveStats describes the blocks processed while importing an archive
+type ArchiveStats struct {
+	First    uint64
+	Last     uint64
+	Imported uint64
+	Skipped  uint64
+}
+
+// ExportArchiveFile writes the accepted blocks [first, last] to a new archive
+// at [file]. The archive is gzipped if [file] ends with ".gz".
+func (vm *VM) ExportArchiveFile(file string, first, last uint64, wrapper ArchiveWrapper) error {
+	if _, err := os.Stat(file); err == nil {
+		// Allowing overwrites could be a DoS vector, since [file] may point to
+		// arbitrary paths on the drive.
+		return errArchiveWouldOverwrite
+	}
+	out, err := os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	var writer io.Writer = out
+	if strings.HasSuffix(file, ".gz") {
+		gzipWriter := gzip.NewWriter(writer)
+		defer gzipWriter.Close()
+		writer = gzipWriter
+	}
+	return vm.ExportArchive(writer, first, last, wrapper)
+}
+
+// ExportArchive writes the accepted blocks [first, last] to [w]. If [wrapper]
+// isn't nil, the blocks wrapping the accepted blocks are included.
+func (vm *VM) ExportArchive(w io.Writer, first, last uint64, wrapper ArchiveWrapper) error {
+	if last < first {
+		return errArchiveInvalidRange
+	}
+	if lastAccepted := vm.chain.LastAcceptedBlock().NumberU64(); last > lastAccepted {
+		return fmt.Errorf("%w: last block %d is after the last accepted block %d", errArchiveUnacceptedRange, last, lastAccepted)
+	}
+
+	header := archiveHeader{
+		Magic:       archiveMagic,
+		Version:     archiveVersion,
+		ChainID:     vm.chainID,
+		GenesisHash: vm.genesisHash,
+		First:       first,
+		Last:        last,
+	}
+	if err := rlp.Encode(w, &header); err != nil {
+		return fmt.Errorf("couldn't write archive header: %w", err)
+	}
+
+	for start := first; start <= last; start += archiveSegmentSize {
+		end := start + archiveSegmentSize - 1
+		if end > last || end < start {
+			end = last
+		}
+		if err := vm.exportArchiveSegment(w, start, end, wrapper); err != nil {
+			return err
+		}
+		log.Info("Exported archive segment", "first", start, "last", end)
+		if end == last {
+			break
+		}
+	}
+	return nil
+}
+
+func (vm *VM) exportArchiveSegment(w io.Writer, start, end uint64, wrapper ArchiveWrapper) error {
+	var body archiveSegmentBody
+	for height := start; height <= end; height++ {
+		block := vm.chain.GetBlockByNumber(height)
+		if block == nil {
+			return fmt.Errorf("missing accepted block %d", height)
+		}
+		blockBytes, err := rlp.EncodeToBytes(block)
+		if err != nil {
+			return fmt.Errorf("couldn't encode block %d: %w", height, err)
+		}
+		body.Blocks = append(body.Blocks, blockBytes)
+
+		root, err := vm.atomicTrie.Root(height)
+		if err != nil {
+			return fmt.Errorf("couldn't get atomic trie root at height %d: %w", height, err)
+		}
+		if root != (common.Hash{}) {
+			body.AtomicRoots = append(body.AtomicRoots, archiveAtomicRoot{Height: height, Root: root})
+		}
+
+		if wrapper != nil {
+			wrapperBytes, err := wrapper.ArchivedBlockBytes(height)
+			if err != nil {
+				return fmt.Errorf("couldn't get wrapping block at height %d: %w", height, err)
+			}
+			body.Wrappers = append(body.Wrappers, wrapperBytes)
+		}
+	}
+	bodyBytes, err := rlp.EncodeToBytes(&body)
+	if err != nil {
+		return fmt.Errorf("couldn't encode archive segment: %w", err)
+	}
+	return rlp.Encode(w, &archiveSegment{
+		Body:     bodyBytes,
+		Checksum: common.BytesToHash(hashing.ComputeHash256(bodyBytes)),
+	})
+}
+
+// ImportArchiveFile imports the archive at [file], see ImportArchive
+func (vm *VM) ImportArchiveFile(file string, wrapper ArchiveWrapper) (ArchiveStats, error) {
+	in, err := os.Open(file)
+	if err != nil {
+		return ArchiveStats{}, err
+	}
+	defer in.Close()
+
+	var reader io.Reader = in
+	if strings.HasSuffix(file, ".gz") {
+		gzipReader, err := gzip.NewReader(reader)
+		if err != nil {
+			return ArchiveStats{}, err
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+	return vm.ImportArchive(reader, wrapper)
+}
+
+// ImportArchive verifies and accepts the blocks of the archive read from [r].
+//
+// Every segment is checked against its checksum, every block is verified and
+// accepted as it would be during bootstrapping, and the atomic trie roots
+// recorded in the archive must match the roots computed while accepting the
+// blocks. Blocks that are already accepted are skipped if they match the
+// archive. The wrapping blocks of the archive are passed to [wrapper] once the
+// blocks they wrap are accepted.
+//
+// ImportArchive must not run concurrently with consensus, so it should only
+// be used on a node that is not running.
+func (vm *VM) ImportArchive(r io.Reader, wrapper ArchiveWrapper) (ArchiveStats, error) {
+	stream := rlp.NewStream(r, 0)
+
+	var header archiveHeader
+	if err := stream.Decode(&header); err != nil {
+		return ArchiveStats{}, fmt.Errorf("%w: %v", errNotAnArchive, err)
+	}
+	if !bytes.Equal(header.Magic, archiveMagic) {
+		return ArchiveStats{}, errNotAnArchive
+	}
+	if header.Version == 0 || header.Version > archiveVersion {
+		return ArchiveStats{}, fmt.Errorf("unsupported archive version %d, expected %d", header.Version, archiveVersion)
+	}
+	if header.ChainID == nil || header.ChainID.Cmp(vm.chainID) != 0 {
+		return ArchiveStats{}, fmt.Errorf("archive chain ID %v does not match chain ID %v", header.ChainID, vm.chainID)
+	}
+	if header.GenesisHash != vm.genesisHash {
+		return ArchiveStats{}, fmt.Errorf("archive genesis %s does not match genesis %s", header.GenesisHash.Hex(), vm.genesisHash.Hex())
+	}
+	if header.Last < header.First {
+		return ArchiveStats{}, errArchiveInvalidRange
+	}
+	if lastAccepted := vm.chain.LastAcceptedBlock().NumberU64(); header.First > lastAccepted+1 {
+		return ArchiveStats{}, fmt.Errorf("archive starts at block %d, but the last accepted block is %d", header.First, lastAccepted)
+	}
+
+	stats := ArchiveStats{First: header.First, Last: header.Last}
+	next := header.First
+	for next <= header.Last {
+		var segment archiveSegment
+		if err := stream.Decode(&segment); err != nil {
+			return stats, fmt.Errorf("couldn't read archive segment starting at block %d: %w", next, err)
+		}
+		if common.BytesToHash(hashing.ComputeHash256(segment.Body)) != segment.Checksum {
+			return stats, fmt.Errorf("%w: segment starting at block %d", errArchiveChecksum, next)
+		}
+		var body archiveSegmentBody
+		if err := rlp.DecodeBytes(segment.Body, &body); err != nil {
+			return stats, fmt.Errorf("couldn't decode archive segment starting at block %d: %w", next, err)
+		}
+		if len(body.Blocks) == 0 || uint64(len(body.Blocks)) > header.Last-next+1 {
+			return stats, fmt.Errorf("archive segment starting at block %d contains %d blocks", next, len(body.Blocks))
+		}
+		if len(body.Wrappers) != 0 && len(body.Wrappers) != len(body.Blocks) {
+			return stats, fmt.Errorf("archive segment starting at block %d contains %d blocks, but %d wrapping blocks", next, len(body.Blocks), len(body.Wrappers))
+		}
+
+		roots := make(map[uint64]common.Hash, len(body.AtomicRoots))
+		for _, root := range body.AtomicRoots {
+			roots[root.Height] = root.Root
+		}
+		for i, blockBytes := range body.Blocks {
+			imported, err := vm.importArchiveBlock(next, blockBytes)
+			if err != nil {
+				return stats, err
+			}
+			// Wrapping blocks of skipped blocks are passed as well, in case
+			// a previous import was interrupted before recording them.
+			if len(body.Wrappers) != 0 && len(body.Wrappers[i]) != 0 {
+				if wrapper == nil {
+					return stats, errArchiveMissingWrapper
+				}
+				if err := wrapper.AcceptArchivedBlock(body.Wrappers[i]); err != nil {
+					return stats, fmt.Errorf("couldn't accept wrapping block at height %d: %w", next, err)
+				}
+			}
+			if imported {
+				stats.Imported++
+			} else {
+				stats.Skipped++
+			}
+			// The root at genesis is committed when the atomic trie is
+			// initialized past genesis, rather than when a block is
+			// accepted, so a fresh node doesn't have it yet.
+			if expected, ok := roots[next]; ok && next != 0 {
+				root, err := vm.atomicTrie.Root(next)
+				if err != nil {
+					return stats, fmt.Errorf("couldn't get atomic trie root at height %d: %w", next, err)
+				}
+				if root != expected {
+					return stats, fmt.Errorf("atomic trie root mismatch at height %d: archive %s, computed %s", next, expected.Hex(), root.Hex())
+				}
+			}
+			next++
+		}
+		log.Info("Imported archive segment", "last", next-1, "imported", stats.Imported, "skipped", stats.Skipped)
+	}
+	return stats, nil
+}
+
+// importArchiveBlock verifies and accepts the block at [height] encoded as
+// [blockBytes]. Returns false if the block was already accepted.
+func (vm *VM) importArchiveBlock(height uint64, blockBytes []byte) (bool, error) {
+	block, err := vm.ParseBlock(blockBytes)
+	if err != nil {
+		return false, fmt.Errorf("couldn't parse block %d: %w", height, err)
+	}
+	if block.Height() != height {
+		return false, fmt.Errorf("expected block %d, found block %d", height, block.Height())
+	}
+	if block.Status() == choices.Accepted {
+		return false, nil
+	}
+	lastAccepted, err := vm.LastAccepted()
+	if err != nil {
+		return false, err
+	}
+	if height <= vm.chain.LastAcceptedBlock().NumberU64() {
+		return false, fmt.Errorf("block %d (%s) conflicts with the accepted chain", height, block.ID())
+	}
+	if block.Parent() != lastAccepted {
+		return false, fmt.Errorf("block %d has parent %s, expected the last accepted block %s", height, block.Parent(), lastAccepted)
+	}
+	if err := block.Verify(); err != nil {
+		return false, fmt.Errorf("block %d failed verification: %w", height, err)
+	}
+	if err := block.Accept(); err != nil {
+		return false, fmt.Errorf("couldn't accept block %d: %w", height, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,205 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/flare-foundation/flare/coreth/core"
+	"github.com/flare-foundation/flare/coreth/core/types"
+	"github.com/flare-foundation/flare/coreth/params"
+	"github.com/flare-foundation/flare/snow/choices"
+)
+
+// archiveTestGenesis returns the AP5 test genesis funding [testEthAddrs]
+func archiveTestGenesis(t *testing.T) string {
+	genesis := &core.Genesis{}
+	if err := json.Unmarshal([]byte(genesisJSONApricotPhase5), genesis); err != nil {
+		t.Fatal(err)
+	}
+	for _, addr := range testEthAddrs {
+		genesis.Alloc[addr] = core.GenesisAccount{Balance: new(big.Int).Mul(big.NewInt(params.Ether), big.NewInt(1000))}
+	}
+	genesisJSON, err := json.Marshal(genesis)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(genesisJSON)
+}
+
+// buildArchiveTestBlocks builds and accepts [count] blocks containing a single
+// transfer each. The transfers are added synchronously, so the blocks are
+// built without waiting for the engine to be notified.
+func buildArchiveTestBlocks(t *testing.T, vm *VM, count int) {
+	key := testKeys[0].ToECDSA()
+	signer := types.LatestSignerForChainID(vm.chainID)
+	// The gas price must be high enough for a single transfer to pay the
+	// block gas cost.
+	gasPrice := new(big.Int).Mul(big.NewInt(params.LaunchMinGasPrice), big.NewInt(10))
+	for i := 0; i < count; i++ {
+		tx := types.NewTransaction(uint64(i), testEthAddrs[1], big.NewInt(1), params.TxGas, gasPrice, nil)
+		signedTx, err := types.SignTx(tx, signer, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, err := range vm.chain.AddRemoteTxsSync([]*types.Transaction{signedTx}) {
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		blk, err := vm.BuildBlock()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := blk.Verify(); err != nil {
+			t.Fatal(err)
+		}
+		if err := vm.SetPreference(blk.ID()); err != nil {
+			t.Fatal(err)
+		}
+		if err := blk.Accept(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestArchiveExportImport(t *testing.T) {
+	assert := assert.New(t)
+	genesisJSON := archiveTestGenesis(t)
+
+	_, vm, _, _, _ := GenesisVM(t, true, genesisJSON, "", "")
+	defer func() {
+		assert.NoError(vm.Shutdown())
+	}()
+	buildArchiveTestBlocks(t, vm, 3)
+
+	var archive bytes.Buffer
+	assert.NoError(vm.ExportArchive(&archive, 0, 3, nil))
+	assert.ErrorIs(vm.ExportArchive(&bytes.Buffer{}, 0, 4, nil), errArchiveUnacceptedRange)
+	assert.ErrorIs(vm.ExportArchive(&bytes.Buffer{}, 2, 1, nil), errArchiveInvalidRange)
+
+	_, importVM, _, _, _ := GenesisVM(t, false, genesisJSON, "", "")
+	defer func() {
+		assert.NoError(importVM.Shutdown())
+	}()
+	stats, err := importVM.ImportArchive(bytes.NewReader(archive.Bytes()), nil)
+	assert.NoError(err)
+	assert.Equal(ArchiveStats{First: 0, Last: 3, Imported: 3, Skipped: 1}, stats)
+
+	lastAccepted, err := importVM.LastAccepted()
+	assert.NoError(err)
+	expected, err := vm.LastAccepted()
+	assert.NoError(err)
+	assert.Equal(expected, lastAccepted)
+
+	blk, err := importVM.GetBlock(lastAccepted)
+	assert.NoError(err)
+	assert.Equal(choices.Accepted, blk.Status())
+
+	// Importing the archive again skips every block.
+	stats, err = importVM.ImportArchive(bytes.NewReader(archive.Bytes()), nil)
+	assert.NoError(err)
+	assert.Equal(uint64(0), stats.Imported)
+	assert.Equal(uint64(4), stats.Skipped)
+}
+
+func TestArchiveImportVerifiesChecksum(t *testing.T) {
+	assert := assert.New(t)
+	genesisJSON := archiveTestGenesis(t)
+
+	_, vm, _, _, _ := GenesisVM(t, true, genesisJSON, "", "")
+	defer func() {
+		assert.NoError(vm.Shutdown())
+	}()
+	buildArchiveTestBlocks(t, vm, 1)
+
+	var archive bytes.Buffer
+	assert.NoError(vm.ExportArchive(&archive, 1, 1, nil))
+
+	// Re-encode the segment with a corrupted body but the original checksum.
+	stream := rlp.NewStream(bytes.NewReader(archive.Bytes()), 0)
+	var (
+		header  archiveHeader
+		segment archiveSegment
+	)
+	assert.NoError(stream.Decode(&header))
+	assert.NoError(stream.Decode(&segment))
+	segment.Body[len(segment.Body)-1] ^= 0xff
+
+	var corrupted bytes.Buffer
+	assert.NoError(rlp.Encode(&corrupted, &header))
+	assert.NoError(rlp.Encode(&corrupted, &segment))
+
+	_, importVM, _, _, _ := GenesisVM(t, false, genesisJSON, "", "")
+	defer func() {
+		assert.NoError(importVM.Shutdown())
+	}()
+	_, err := importVM.ImportArchive(&corrupted, nil)
+	assert.True(errors.Is(err, errArchiveChecksum), "unexpected error: %v", err)
+
+	// Archives of a different genesis are rejected before any block is read.
+	header.GenesisHash = common.Hash{1}
+	var otherGenesis bytes.Buffer
+	assert.NoError(rlp.Encode(&otherGenesis, &header))
+	_, err = importVM.ImportArchive(&otherGenesis, nil)
+	assert.Error(err)
+}
+
+// testArchiveWrapper wraps every block after [forkHeight] in its height
+type testArchiveWrapper struct {
+	forkHeight uint64
+	accepted   [][]byte
+}
+
+func (w *testArchiveWrapper) ArchivedBlockBytes(height uint64) ([]byte, error) {
+	if height <= w.forkHeight {
+		return nil, nil
+	}
+	return []byte{byte(height)}, nil
+}
+
+func (w *testArchiveWrapper) AcceptArchivedBlock(blockBytes []byte) error {
+	w.accepted = append(w.accepted, blockBytes)
+	return nil
+}
+
+func TestArchiveWrappingBlocks(t *testing.T) {
+	assert := assert.New(t)
+	genesisJSON := archiveTestGenesis(t)
+
+	_, vm, _, _, _ := GenesisVM(t, true, genesisJSON, "", "")
+	defer func() {
+		assert.NoError(vm.Shutdown())
+	}()
+	buildArchiveTestBlocks(t, vm, 3)
+
+	var archive bytes.Buffer
+	assert.NoError(vm.ExportArchive(&archive, 0, 3, &testArchiveWrapper{forkHeight: 1}))
+
+	_, importVM, _, _, _ := GenesisVM(t, false, genesisJSON, "", "")
+	defer func() {
+		assert.NoError(importVM.Shutdown())
+	}()
+
+	// The wrapping blocks can't be dropped silently.
+	_, err := importVM.ImportArchive(bytes.NewReader(archive.Bytes()), nil)
+	assert.ErrorIs(err, errArchiveMissingWrapper)
+
+	// Only the blocks after the fork are wrapped, and their wrapping blocks
+	// are accepted once the blocks are accepted.
+	wrapper := &testArchiveWrapper{}
+	stats, err := importVM.ImportArchive(bytes.NewReader(archive.Bytes()), wrapper)
+	assert.NoError(err)
+	assert.Equal(uint64(3), stats.Last)
+	assert.Equal([][]byte{{2}, {3}}, wrapper.accepted)
+}
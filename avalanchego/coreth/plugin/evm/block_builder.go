@@ -265,13 +265,17 @@ func (b *blockBuilder) signalTxsReady() {
 // and notifies the VM when the tx pool has transactions to be
 // put into a new block.
 func (b *blockBuilder) awaitSubmittedTxs() {
+	// txSubmitChan is invoked when new transactions are issued as well as on re-orgs which
+	// may orphan transactions that were previously in a preferred block.
+	//
+	// Subscribe before starting the loop, so that transactions issued as soon
+	// as the VM is initialized aren't missed.
+	txSubmitChan := b.chain.GetTxSubmitCh()
+
 	b.shutdownWg.Add(1)
 	go b.ctx.Log.RecoverAndPanic(func() {
 		defer b.shutdownWg.Done()
 
-		// txSubmitChan is invoked when new transactions are issued as well as on re-orgs which
-		// may orphan transactions that were previously in a preferred block.
-		txSubmitChan := b.chain.GetTxSubmitCh()
 		for {
 			select {
 			case ethTxsEvent := <-txSubmitChan:
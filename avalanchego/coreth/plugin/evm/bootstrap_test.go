@@ -0,0 +1,173 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/flare-foundation/flare/database/memdb"
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/snow"
+	"github.com/flare-foundation/flare/snow/engine/common/queue"
+	"github.com/flare-foundation/flare/snow/engine/common/tracker"
+	"github.com/flare-foundation/flare/snow/validators"
+
+	engCommon "github.com/flare-foundation/flare/snow/engine/common"
+	smbootstrap "github.com/flare-foundation/flare/snow/engine/snowman/bootstrap"
+	snowgetter "github.com/flare-foundation/flare/snow/engine/snowman/getter"
+)
+
+// acceptedBlocks returns the IDs and bytes of the blocks accepted by [vm]
+// above the genesis, from the last accepted block down
+func acceptedBlocks(t *testing.T, vm *VM) ([]ids.ID, [][]byte) {
+	var (
+		blkIDs   []ids.ID
+		blkBytes [][]byte
+	)
+	blkID, err := vm.LastAccepted()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for {
+		blk, err := vm.GetBlock(blkID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if blk.Height() == 0 {
+			return blkIDs, blkBytes
+		}
+		blkIDs = append(blkIDs, blkID)
+		blkBytes = append(blkBytes, blk.Bytes())
+		blkID = blk.Parent()
+	}
+}
+
+// bootstrapThroughCheckpoint bootstraps [vm] from a beacon whose accepted
+// chain is [blkBytes], from the tip [tipID] down, and that must go through
+// [checkpoint]. Returns the consensus context of the bootstrapper.
+func bootstrapThroughCheckpoint(t *testing.T, vm *VM, tipID ids.ID, blkBytes [][]byte, checkpoint *smbootstrap.Checkpoint) (*snow.ConsensusContext, error) {
+	ctx := snow.DefaultConsensusContextTest()
+	beacons := validators.NewSet()
+	beacon := ids.GenerateTestShortID()
+	if err := beacons.AddWeight(beacon, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	var requestID uint32
+	sender := &engCommon.SenderTest{T: t}
+	sender.Default(true)
+	sender.CantSendGetAcceptedFrontier = false
+	sender.SendGetAncestorsF = func(_ ids.ShortID, reqID uint32, _ ids.ID) { requestID = reqID }
+
+	isBootstrapped := false
+	subnet := &engCommon.SubnetTest{
+		T:               t,
+		IsBootstrappedF: func() bool { return isBootstrapped },
+		BootstrappedF:   func(ids.ID) { isBootstrapped = true },
+	}
+
+	blocked, err := queue.NewWithMissing(memdb.New(), "", prometheus.NewRegistry())
+	if err != nil {
+		t.Fatal(err)
+	}
+	commonConfig := engCommon.Config{
+		Ctx:                            ctx,
+		Validators:                     beacons,
+		Beacons:                        beacons,
+		SampleK:                        beacons.Len(),
+		Alpha:                          beacons.Weight()/2 + 1,
+		Sender:                         sender,
+		Subnet:                         subnet,
+		Timer:                          &engCommon.TimerTest{},
+		AncestorsMaxContainersSent:     2000,
+		AncestorsMaxContainersReceived: 2000,
+		SharedCfg:                      &engCommon.SharedConfig{},
+	}
+	getter, err := snowgetter.New(vm, commonConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bs, err := smbootstrap.New(
+		smbootstrap.Config{
+			Config:        commonConfig,
+			AllGetsServer: getter,
+			Blocked:       blocked,
+			VM:            vm,
+			WeightTracker: tracker.NewWeightTracker(beacons, commonConfig.StartupAlpha),
+			Checkpoint:    checkpoint,
+		},
+		func(uint32) error { ctx.SetState(snow.NormalOp); return nil },
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bs.Start(0); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := bs.ForceAccepted([]ids.ID{tipID}); err != nil {
+		return ctx, err
+	}
+	return ctx, bs.Ancestors(beacon, requestID, blkBytes)
+}
+
+func TestBootstrapThroughCheckpoint(t *testing.T) {
+	assert := assert.New(t)
+	genesisJSON := archiveTestGenesis(t)
+
+	_, beaconVM, _, _, _ := GenesisVM(t, true, genesisJSON, "", "")
+	defer func() {
+		assert.NoError(beaconVM.Shutdown())
+	}()
+	buildArchiveTestBlocks(t, beaconVM, 3)
+	blkIDs, blkBytes := acceptedBlocks(t, beaconVM)
+
+	// The blocks below the checkpoint are executed, so that the checkpoint
+	// can be verified against the state of its parent.
+	_, vm, _, _, _ := GenesisVM(t, false, genesisJSON, "", "")
+	defer func() {
+		assert.NoError(vm.Shutdown())
+	}()
+	ctx, err := bootstrapThroughCheckpoint(t, vm, blkIDs[0], blkBytes, &smbootstrap.Checkpoint{
+		BlockID: blkIDs[1],
+		Height:  2,
+	})
+	assert.NoError(err)
+	assert.True(ctx.GetState() == snow.NormalOp)
+	lastAccepted, err := vm.LastAccepted()
+	assert.NoError(err)
+	assert.Equal(blkIDs[0], lastAccepted)
+}
+
+func TestBootstrapThroughCheckpointConflict(t *testing.T) {
+	assert := assert.New(t)
+	genesisJSON := archiveTestGenesis(t)
+
+	_, beaconVM, _, _, _ := GenesisVM(t, true, genesisJSON, "", "")
+	defer func() {
+		assert.NoError(beaconVM.Shutdown())
+	}()
+	buildArchiveTestBlocks(t, beaconVM, 3)
+	blkIDs, blkBytes := acceptedBlocks(t, beaconVM)
+
+	_, vm, _, _, _ := GenesisVM(t, false, genesisJSON, "", "")
+	defer func() {
+		assert.NoError(vm.Shutdown())
+	}()
+	genesisID, err := vm.LastAccepted()
+	assert.NoError(err)
+	ctx, err := bootstrapThroughCheckpoint(t, vm, blkIDs[0], blkBytes, &smbootstrap.Checkpoint{
+		BlockID: ids.GenerateTestID(),
+		Height:  2,
+	})
+	assert.Error(err)
+	assert.True(ctx.GetState() == snow.Bootstrapping)
+	lastAccepted, err := vm.LastAccepted()
+	assert.NoError(err)
+	assert.Equal(genesisID, lastAccepted)
+}
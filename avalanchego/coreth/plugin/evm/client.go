@@ -35,6 +35,7 @@ type Client interface {
 	MemoryProfile(ctx context.Context) (bool, error)
 	LockProfile(ctx context.Context) (bool, error)
 	SetLogLevel(ctx context.Context, level log.Lvl) (bool, error)
+	SetLogModules(ctx context.Context, modules string) (bool, error)
 }
 
 // Client implementation for interacting with EVM [chain]
@@ -227,3 +228,13 @@ func (c *client) SetLogLevel(ctx context.Context, level log.Lvl) (bool, error) {
 	}, res)
 	return res.Success, err
 }
+
+// SetLogModules dynamically overrides the log level of the C Chain modules
+// matched by [modules], such as "core/*=5,eth/downloader=4"
+func (c *client) SetLogModules(ctx context.Context, modules string) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.adminRequester.SendRequest(ctx, "setLogLevel", &SetLogLevelArgs{
+		Modules: modules,
+	}, res)
+	return res.Success, err
+}
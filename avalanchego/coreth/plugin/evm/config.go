@@ -5,9 +5,15 @@ package evm
 
 import (
 	"encoding/json"
+	"fmt"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/flare-foundation/flare/coreth/core"
 	"github.com/flare-foundation/flare/coreth/eth"
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/utils/constants"
 	"github.com/spf13/cast"
 )
 
@@ -29,6 +35,7 @@ const (
 	defaultOfflinePruningBloomFilterSize uint64 = 512 // Default size (MB) for the offline pruner to use
 	defaultLogLevel                             = "info"
 	defaultMaxOutboundActiveRequests            = 8
+	defaultPrivateTxExpiryBlocks                = 20
 )
 
 var defaultEnabledAPIs = []string{
@@ -94,6 +101,17 @@ type Config struct {
 	TxRegossipFrequency       Duration `json:"tx-regossip-frequency"`
 	TxRegossipMaxSize         int      `json:"tx-regossip-max-size"`
 
+	// Private Tx Settings
+	PrivateTxAPIEnabled    bool     `json:"private-tx-api-enabled"`
+	PrivateTxGossipNodeIDs []string `json:"private-tx-gossip-node-ids"`
+	PrivateTxExpiryBlocks  uint64   `json:"private-tx-expiry-blocks"`
+
+	// Priority Lane Settings
+	PriorityLaneContracts  []string `json:"priority-lane-contracts"`
+	PriorityLaneSenders    []string `json:"priority-lane-senders"`
+	PriorityLaneGasReserve uint64   `json:"priority-lane-gas-reserve"`
+	PriorityLaneMaxTxs     int      `json:"priority-lane-max-txs"`
+
 	// Log level
 	LogLevel string `json:"log-level"`
 
@@ -111,6 +129,52 @@ func (c Config) EthAPIs() []string {
 	return c.EnabledEthAPIs
 }
 
+// PrivateTxGossipNodes returns the set of nodes that private transactions are
+// gossiped to and accepted from
+func (c Config) PrivateTxGossipNodes() (ids.ShortSet, error) {
+	nodeIDs := ids.NewShortSet(len(c.PrivateTxGossipNodeIDs))
+	for _, nodeIDStr := range c.PrivateTxGossipNodeIDs {
+		nodeID, err := ids.ShortFromPrefixedString(nodeIDStr, constants.NodeIDPrefix)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse private tx gossip node ID %q: %w", nodeIDStr, err)
+		}
+		nodeIDs.Add(nodeID)
+	}
+	return nodeIDs, nil
+}
+
+// PriorityLane returns the tx pool priority lane configuration
+func (c Config) PriorityLane() (core.PriorityLaneConfig, error) {
+	contracts, err := parseAddresses(c.PriorityLaneContracts)
+	if err != nil {
+		return core.PriorityLaneConfig{}, fmt.Errorf("couldn't parse priority lane contracts: %w", err)
+	}
+	senders, err := parseAddresses(c.PriorityLaneSenders)
+	if err != nil {
+		return core.PriorityLaneConfig{}, fmt.Errorf("couldn't parse priority lane senders: %w", err)
+	}
+	if c.PriorityLaneMaxTxs < 0 {
+		return core.PriorityLaneConfig{}, fmt.Errorf("priority lane max txs must be non-negative, got %d", c.PriorityLaneMaxTxs)
+	}
+	return core.PriorityLaneConfig{
+		Contracts:  contracts,
+		Senders:    senders,
+		GasReserve: c.PriorityLaneGasReserve,
+		MaxTxs:     c.PriorityLaneMaxTxs,
+	}, nil
+}
+
+func parseAddresses(addrStrs []string) ([]common.Address, error) {
+	addrs := make([]common.Address, 0, len(addrStrs))
+	for _, addrStr := range addrStrs {
+		if !common.IsHexAddress(addrStr) {
+			return nil, fmt.Errorf("invalid address %q", addrStr)
+		}
+		addrs = append(addrs, common.HexToAddress(addrStr))
+	}
+	return addrs, nil
+}
+
 func (c Config) EthBackendSettings() eth.Settings {
 	return eth.Settings{MaxBlocksPerRequest: c.MaxBlocksPerRequest}
 }
@@ -134,6 +198,7 @@ func (c *Config) SetDefaults() {
 	c.OfflinePruningBloomFilterSize = defaultOfflinePruningBloomFilterSize
 	c.LogLevel = defaultLogLevel
 	c.MaxOutboundActiveRequests = defaultMaxOutboundActiveRequests
+	c.PrivateTxExpiryBlocks = defaultPrivateTxExpiryBlocks
 }
 
 func (d *Duration) UnmarshalJSON(data []byte) (err error) {
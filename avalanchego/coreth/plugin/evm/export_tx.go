@@ -12,8 +12,8 @@ import (
 	"github.com/flare-foundation/flare/chains/atomic"
 	"github.com/flare-foundation/flare/ids"
 	"github.com/flare-foundation/flare/snow"
-	"github.com/flare-foundation/flare/utils/crypto"
 	"github.com/flare-foundation/flare/vms/components/avax"
+	"github.com/flare-foundation/flare/vms/secp256k1fx"
 )
 
 // UnsignedExportTx is an unsigned ExportTx
@@ -76,7 +76,7 @@ func (vm *VM) newExportTx(
 	chainID ids.ID, // Chain to send the UTXOs to
 	to ids.ShortID, // Address of chain recipient
 	baseFee *big.Int, // fee to use post-AP3
-	keys []*crypto.PrivateKeySECP256K1R, // Pay the fee and provide the tokens
+	kc *secp256k1fx.SignerKeychain, // Pays the fee and provides the tokens
 ) (*Tx, error) {
 	return nil, errExportTxsDisabled
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/flare-foundation/flare/ids"
 	"github.com/flare-foundation/flare/snow"
 	"github.com/flare-foundation/flare/vms"
+	"github.com/flare-foundation/flare/vms/components/keystore"
 )
 
 var (
@@ -16,8 +17,12 @@ var (
 	_ vms.Factory = &Factory{}
 )
 
-type Factory struct{}
+type Factory struct {
+	// Signs the transactions issued through the keystore API instead of the
+	// keys of the user. Nil if the keys of the user are used.
+	RemoteSigner keystore.ChainSigner
+}
 
 func (f *Factory) New(*snow.Context) (interface{}, error) {
-	return &VM{}, nil
+	return &VM{remoteSigner: f.RemoteSigner}, nil
 }
@@ -36,6 +36,10 @@ const (
 	// [ethTxsGossipInterval] is how often we attempt to gossip newly seen
 	// transactions to other nodes.
 	ethTxsGossipInterval = 500 * time.Millisecond
+
+	// [acceptedChanSize] is the size of the channel listening to accepted
+	// blocks to expire private transactions.
+	acceptedChanSize = 16
 )
 
 // Gossiper handles outgoing gossip of transactions
@@ -57,6 +61,10 @@ type pushGossiper struct {
 	txPool        *core.TxPool
 	atomicMempool *Mempool
 
+	// [privateTxs] are only gossiped to [privateTxGossipNodes]
+	privateTxs           *PrivateTxs
+	privateTxGossipNodes ids.ShortSet
+
 	// We attempt to batch transactions we need to gossip to avoid runaway
 	// amplification of mempol chatter.
 	ethTxsToGossipChan chan []*types.Transaction
@@ -84,6 +92,8 @@ func (vm *VM) newPushGossiper() Gossiper {
 		blockchain:           vm.chain.BlockChain(),
 		txPool:               vm.chain.GetTxPool(),
 		atomicMempool:        vm.mempool,
+		privateTxs:           vm.privateTxs,
+		privateTxGossipNodes: vm.privateTxGossipNodes,
 		ethTxsToGossipChan:   make(chan []*types.Transaction),
 		ethTxsToGossip:       make(map[common.Hash]*types.Transaction),
 		shutdownChan:         vm.shutdownChan,
@@ -204,9 +214,17 @@ func (n *pushGossiper) queueRegossipTxs() types.Transactions {
 // awaitEthTxGossip periodically gossips transactions that have been queued for
 // gossip at least once every [ethTxsGossipInterval].
 func (n *pushGossiper) awaitEthTxGossip() {
+	// Subscribe before starting the gossip loop, as the subscription can not
+	// be created once the blockchain has been stopped.
+	var (
+		acceptedCh  = make(chan core.ChainEvent, acceptedChanSize)
+		acceptedSub = n.blockchain.SubscribeChainAcceptedEvent(acceptedCh)
+	)
+
 	n.shutdownWg.Add(1)
 	go n.ctx.Log.RecoverAndPanic(func() {
 		defer n.shutdownWg.Done()
+		defer acceptedSub.Unsubscribe()
 
 		var (
 			gossipTicker   = time.NewTicker(ethTxsGossipInterval)
@@ -245,6 +263,8 @@ func (n *pushGossiper) awaitEthTxGossip() {
 						"err", err,
 					)
 				}
+			case ev := <-acceptedCh:
+				n.expirePrivateTxs(ev.Block)
 			case <-n.shutdownChan:
 				return
 			}
@@ -252,6 +272,25 @@ func (n *pushGossiper) awaitEthTxGossip() {
 	})
 }
 
+// expirePrivateTxs stops tracking the private txs included in the accepted
+// [block] and drops the private txs that expired from the tx pool.
+func (n *pushGossiper) expirePrivateTxs(block *types.Block) {
+	if n.privateTxs.Len() == 0 {
+		return
+	}
+	for _, tx := range block.Transactions() {
+		n.privateTxs.Remove(tx.Hash())
+	}
+	for _, txHash := range n.privateTxs.Expire(block.NumberU64()) {
+		log.Debug(
+			"dropping expired private tx",
+			"tx", txHash,
+			"height", block.NumberU64(),
+		)
+		n.txPool.RemoveTx(txHash)
+	}
+}
+
 func (n *pushGossiper) GossipAtomicTxs(txs []*Tx) error {
 	if time.Now().Before(n.gossipActivationTime) {
 		log.Trace(
@@ -321,6 +360,54 @@ func (n *pushGossiper) sendEthTxs(txs []*types.Transaction) error {
 	return n.client.Gossip(msgBytes)
 }
 
+func (n *pushGossiper) sendPrivateEthTxs(txs []*types.Transaction) error {
+	if len(txs) == 0 || n.privateTxGossipNodes.Len() == 0 {
+		return nil
+	}
+
+	txBytes, err := rlp.EncodeToBytes(txs)
+	if err != nil {
+		return err
+	}
+	msg := message.PrivateEthTxs{
+		Txs: txBytes,
+	}
+	msgBytes, err := message.BuildMessage(n.codec, &msg)
+	if err != nil {
+		return err
+	}
+
+	log.Trace(
+		"gossiping private eth txs",
+		"len(txs)", len(txs),
+		"size(txs)", len(msg.Txs),
+		"nodes", n.privateTxGossipNodes.Len(),
+	)
+	return n.client.GossipSpecific(n.privateTxGossipNodes, msgBytes)
+}
+
+// sendEthTxBatches splits [txs] into messages of at most
+// [message.EthMsgSoftCapSize] and sends each of them with [send].
+func sendEthTxBatches(txs []*types.Transaction, send func([]*types.Transaction) error) error {
+	msgTxs := make([]*types.Transaction, 0)
+	msgTxsSize := common.StorageSize(0)
+	for _, tx := range txs {
+		size := tx.Size()
+		if msgTxsSize+size > message.EthMsgSoftCapSize {
+			if err := send(msgTxs); err != nil {
+				return err
+			}
+			msgTxs = msgTxs[:0]
+			msgTxsSize = 0
+		}
+		msgTxs = append(msgTxs, tx)
+		msgTxsSize += size
+	}
+
+	// Send any remaining [msgTxs]
+	return send(msgTxs)
+}
+
 func (n *pushGossiper) gossipEthTxs(force bool) (int, error) {
 	if (!force && time.Since(n.lastGossiped) < ethTxsGossipInterval) || len(n.ethTxsToGossip) == 0 {
 		return 0, nil
@@ -333,6 +420,7 @@ func (n *pushGossiper) gossipEthTxs(force bool) (int, error) {
 	}
 
 	selectedTxs := make([]*types.Transaction, 0)
+	selectedPrivateTxs := make([]*types.Transaction, 0)
 	for _, tx := range txs {
 		txHash := tx.Hash()
 		txStatus := n.txPool.Status([]common.Hash{txHash})[0]
@@ -340,7 +428,8 @@ func (n *pushGossiper) gossipEthTxs(force bool) (int, error) {
 			continue
 		}
 
-		if n.config.RemoteTxGossipOnlyEnabled && n.txPool.HasLocal(txHash) {
+		private := n.privateTxs.Has(txHash)
+		if !private && n.config.RemoteTxGossipOnlyEnabled && n.txPool.HasLocal(txHash) {
 			continue
 		}
 
@@ -353,31 +442,25 @@ func (n *pushGossiper) gossipEthTxs(force bool) (int, error) {
 		}
 		n.recentEthTxs.Put(txHash, nil)
 
-		selectedTxs = append(selectedTxs, tx)
+		if private {
+			selectedPrivateTxs = append(selectedPrivateTxs, tx)
+		} else {
+			selectedTxs = append(selectedTxs, tx)
+		}
 	}
 
-	if len(selectedTxs) == 0 {
+	attempted := len(selectedTxs) + len(selectedPrivateTxs)
+	if attempted == 0 {
 		return 0, nil
 	}
 
-	// Attempt to gossip [selectedTxs]
-	msgTxs := make([]*types.Transaction, 0)
-	msgTxsSize := common.StorageSize(0)
-	for _, tx := range selectedTxs {
-		size := tx.Size()
-		if msgTxsSize+size > message.EthMsgSoftCapSize {
-			if err := n.sendEthTxs(msgTxs); err != nil {
-				return len(selectedTxs), err
-			}
-			msgTxs = msgTxs[:0]
-			msgTxsSize = 0
-		}
-		msgTxs = append(msgTxs, tx)
-		msgTxsSize += size
+	// Attempt to gossip [selectedPrivateTxs] only to the private gossip nodes
+	if err := sendEthTxBatches(selectedPrivateTxs, n.sendPrivateEthTxs); err != nil {
+		return attempted, err
 	}
 
-	// Send any remaining [msgTxs]
-	return len(selectedTxs), n.sendEthTxs(msgTxs)
+	// Attempt to gossip [selectedTxs]
+	return attempted, sendEthTxBatches(selectedTxs, n.sendEthTxs)
 }
 
 // GossipEthTxs enqueues the provided [txs] for gossiping. At some point, the
@@ -505,6 +588,61 @@ func (h *GossipHandler) HandleEthTxs(nodeID ids.ShortID, msg *message.EthTxs) er
 	return nil
 }
 
+func (h *GossipHandler) HandlePrivateEthTxs(nodeID ids.ShortID, msg *message.PrivateEthTxs) error {
+	log.Trace(
+		"AppGossip called with PrivateEthTxs",
+		"peerID", nodeID,
+		"size(txs)", len(msg.Txs),
+	)
+
+	// Private txs are only exchanged between the private gossip nodes, so
+	// private txs sent by any other peer are dropped.
+	if !h.vm.privateTxGossipNodes.Contains(nodeID) {
+		log.Trace(
+			"AppGossip dropping PrivateEthTxs from unknown peer",
+			"peerID", nodeID,
+		)
+		return nil
+	}
+
+	if len(msg.Txs) == 0 {
+		log.Trace(
+			"AppGossip received empty PrivateEthTxs Message",
+			"peerID", nodeID,
+		)
+		return nil
+	}
+
+	// The maximum size of this encoded object is enforced by the codec.
+	txs := make([]*types.Transaction, 0)
+	if err := rlp.DecodeBytes(msg.Txs, &txs); err != nil {
+		log.Trace(
+			"AppGossip provided invalid private txs",
+			"peerID", nodeID,
+			"err", err,
+		)
+		return nil
+	}
+
+	// The txs must be marked as private before they are added to the tx pool
+	// so that they are never gossiped to the rest of the network.
+	h.vm.markPrivateTxs(txs)
+	errs := h.txPool.AddRemotes(txs)
+	for i, err := range errs {
+		if err != nil {
+			log.Trace(
+				"AppGossip failed to add private tx to mempool",
+				"err", err,
+				"tx", txs[i].Hash(),
+			)
+			if !h.txPool.Has(txs[i].Hash()) {
+				h.vm.privateTxs.Remove(txs[i].Hash())
+			}
+		}
+	}
+	return nil
+}
+
 // noopGossiper should be used when gossip communication is not supported
 type noopGossiper struct{}
 
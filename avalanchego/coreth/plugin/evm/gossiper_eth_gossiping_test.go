@@ -4,8 +4,10 @@
 package evm
 
 import (
+	"context"
 	"crypto/ecdsa"
 	"encoding/json"
+	"fmt"
 	"math/big"
 	"strings"
 	"sync"
@@ -13,6 +15,7 @@ import (
 	"time"
 
 	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/utils/constants"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -376,3 +379,113 @@ func TestMempoolEthTxsRegossip(t *testing.T) {
 	// (due to the non-deterministic way pending transactions are surfaced, this can be difficult
 	// to assert as well).
 }
+
+// show that privately issued eth txs are only gossiped to the private gossip
+// nodes
+func TestMempoolEthTxsPrivateTxsGossipedToPrivateNodes(t *testing.T) {
+	assert := assert.New(t)
+
+	key, err := crypto.GenerateKey()
+	assert.NoError(err)
+
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+
+	cfgJson, err := fundAddressByGenesis([]common.Address{addr})
+	assert.NoError(err)
+
+	privateNodeID := ids.GenerateTestShortID()
+	configJson := fmt.Sprintf(
+		`{"private-tx-gossip-node-ids": ["%s"], "allow-unprotected-txs": true}`,
+		privateNodeID.PrefixedString(constants.NodeIDPrefix),
+	)
+
+	_, vm, _, _, sender := GenesisVM(t, true, cfgJson, configJson, "")
+	defer func() {
+		err := vm.Shutdown()
+		assert.NoError(err)
+	}()
+	vm.chain.GetTxPool().SetGasPrice(common.Big1)
+	vm.chain.GetTxPool().SetMinFee(common.Big0)
+
+	ethTxs := getValidEthTxs(key, 1, common.Big1)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	sender.CantSendAppGossip = true
+	sender.CantSendAppGossipSpecific = false
+	sender.SendAppGossipSpecificF = func(nodeIDs ids.ShortSet, gossipedBytes []byte) error {
+		assert.Equal(1, nodeIDs.Len())
+		assert.True(nodeIDs.Contains(privateNodeID))
+
+		notifyMsgIntf, err := message.ParseMessage(vm.networkCodec, gossipedBytes)
+		assert.NoError(err)
+
+		requestMsg, ok := notifyMsgIntf.(*message.PrivateEthTxs)
+		assert.True(ok)
+
+		txs := make([]*types.Transaction, 0)
+		assert.NoError(rlp.DecodeBytes(requestMsg.Txs, &txs))
+		assert.Len(txs, 1)
+		assert.Equal(ethTxs[0].Hash(), txs[0].Hash())
+
+		wg.Done()
+		return nil
+	}
+
+	txBytes, err := ethTxs[0].MarshalBinary()
+	assert.NoError(err)
+	api := &PrivateTxAPI{vm}
+	txHash, err := api.SendPrivateRawTransaction(context.Background(), txBytes)
+	assert.NoError(err)
+	assert.Equal(ethTxs[0].Hash(), txHash)
+	assert.True(vm.privateTxs.Has(txHash))
+
+	attemptAwait(t, &wg, 5*time.Second)
+}
+
+// show that private eth txs are only accepted from the private gossip nodes
+func TestMempoolEthTxsPrivateTxsHandling(t *testing.T) {
+	assert := assert.New(t)
+
+	key, err := crypto.GenerateKey()
+	assert.NoError(err)
+
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+
+	cfgJson, err := fundAddressByGenesis([]common.Address{addr})
+	assert.NoError(err)
+
+	privateNodeID := ids.GenerateTestShortID()
+	configJson := fmt.Sprintf(
+		`{"private-tx-gossip-node-ids": ["%s"]}`,
+		privateNodeID.PrefixedString(constants.NodeIDPrefix),
+	)
+
+	_, vm, _, _, _ := GenesisVM(t, true, cfgJson, configJson, "")
+	defer func() {
+		err := vm.Shutdown()
+		assert.NoError(err)
+	}()
+	vm.chain.GetTxPool().SetGasPrice(common.Big1)
+	vm.chain.GetTxPool().SetMinFee(common.Big0)
+
+	ethTxs := getValidEthTxs(key, 2, common.Big1)
+	buildMsg := func(tx *types.Transaction) []byte {
+		txBytes, err := rlp.EncodeToBytes([]*types.Transaction{tx})
+		assert.NoError(err)
+		msgBytes, err := message.BuildMessage(vm.networkCodec, &message.PrivateEthTxs{
+			Txs: txBytes,
+		})
+		assert.NoError(err)
+		return msgBytes
+	}
+
+	// private txs from peers that aren't private gossip nodes are dropped
+	assert.NoError(vm.AppGossip(ids.GenerateTestShortID(), buildMsg(ethTxs[0])))
+	assert.False(vm.chain.GetTxPool().Has(ethTxs[0].Hash()))
+	assert.False(vm.privateTxs.Has(ethTxs[0].Hash()))
+
+	// private txs from private gossip nodes are added as private txs
+	assert.NoError(vm.AppGossip(privateNodeID, buildMsg(ethTxs[1])))
+	assert.True(vm.privateTxs.Has(ethTxs[1].Hash()))
+}
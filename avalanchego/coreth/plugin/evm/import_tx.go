@@ -13,7 +13,6 @@ import (
 	"github.com/flare-foundation/flare/chains/atomic"
 	"github.com/flare-foundation/flare/ids"
 	"github.com/flare-foundation/flare/snow"
-	"github.com/flare-foundation/flare/utils/crypto"
 	"github.com/flare-foundation/flare/vms/components/avax"
 	"github.com/flare-foundation/flare/vms/secp256k1fx"
 )
@@ -80,7 +79,7 @@ func (vm *VM) newImportTx(
 	chainID ids.ID, // chain to import from
 	to common.Address, // Address of recipient
 	baseFee *big.Int, // fee to use post-AP3
-	keys []*crypto.PrivateKeySECP256K1R, // Keys to import the funds
+	kc *secp256k1fx.SignerKeychain, // Keychain to import the funds
 ) (*Tx, error) {
 	return nil, errImportTxsDisabled
 }
@@ -90,7 +89,7 @@ func (vm *VM) newImportTxWithUTXOs(
 	chainID ids.ID, // chain to import from
 	to common.Address, // Address of recipient
 	baseFee *big.Int, // fee to use post-AP3
-	kc *secp256k1fx.Keychain, // Keychain to use for signing the atomic UTXOs
+	kc *secp256k1fx.SignerKeychain, // Keychain to use for signing the atomic UTXOs
 	atomicUTXOs []*avax.UTXO, // UTXOs to spend
 ) (*Tx, error) {
 	return nil, errImportTxsDisabled
@@ -0,0 +1,91 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/flare-foundation/flare/utils/logging"
+)
+
+// gethNumberKey is the key of the block number in the context of geth records
+const gethNumberKey = "number"
+
+// logHandler writes geth log records to the chain's logger, so that the events
+// of the EVM are logged in the same format and filtered by the same levels as
+// the rest of the chain.
+type logHandler struct {
+	log logging.Logger
+}
+
+// newLogHandler returns a handler that writes geth log records with a level of
+// at most [level] to [logger]. The level of each module can be overridden with
+// [GlogHandler.Vmodule].
+func newLogHandler(logger logging.Logger, level log.Lvl) *log.GlogHandler {
+	handler := log.NewGlogHandler(log.LazyHandler(&logHandler{log: logger}))
+	handler.Verbosity(level)
+	return handler
+}
+
+// Log implements the log.Handler interface
+func (h *logHandler) Log(r *log.Record) error {
+	level := toLoggingLevel(r.Lvl)
+	if level > h.log.GetLogLevel() && level > h.log.GetDisplayLevel() && level != logging.Fatal {
+		return nil
+	}
+
+	fields := make([]logging.Field, 0, len(r.Ctx)/2+1)
+	for i := 0; i+1 < len(r.Ctx); i += 2 {
+		key, ok := r.Ctx[i].(string)
+		if !ok {
+			key = fmt.Sprint(r.Ctx[i])
+		}
+		if key == gethNumberKey {
+			key = logging.HeightKey
+		}
+		fields = append(fields, logging.Field{Key: key, Value: r.Ctx[i+1]})
+	}
+	// Log the location of the geth call rather than of this handler
+	if frame := r.Call.Frame(); frame.File != "" {
+		fields = append(fields, logging.Caller(frame.File, frame.Line))
+	}
+
+	logger := h.log.With(fields...)
+	switch level {
+	case logging.Fatal:
+		logger.Fatal("%s", r.Msg)
+	case logging.Error:
+		logger.Error("%s", r.Msg)
+	case logging.Warn:
+		logger.Warn("%s", r.Msg)
+	case logging.Info:
+		logger.Info("%s", r.Msg)
+	case logging.Debug:
+		logger.Debug("%s", r.Msg)
+	default:
+		logger.Verbo("%s", r.Msg)
+	}
+	return nil
+}
+
+// toLoggingLevel returns the logging level that corresponds to the geth level
+// [lvl]
+func toLoggingLevel(lvl log.Lvl) logging.Level {
+	switch lvl {
+	case log.LvlCrit:
+		return logging.Fatal
+	case log.LvlError:
+		return logging.Error
+	case log.LvlWarn:
+		return logging.Warn
+	case log.LvlInfo:
+		return logging.Info
+	case log.LvlDebug:
+		return logging.Debug
+	default:
+		return logging.Verbo
+	}
+}
@@ -0,0 +1,87 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/flare-foundation/flare/utils/logging"
+)
+
+func TestLogHandler(t *testing.T) {
+	assert := assert.New(t)
+
+	config, err := logging.DefaultConfig()
+	assert.NoError(err)
+	config.Directory = t.TempDir()
+	config.LogFormat = logging.JSONFormat
+	config.LogLevel = logging.Info
+	config.DisableDisplaying = true
+
+	factory := logging.NewFactory(config)
+	logger, err := factory.MakeChain("C")
+	assert.NoError(err)
+
+	handler := newLogHandler(logger, log.LvlDebug)
+	gethLogger := log.New()
+	gethLogger.SetHandler(handler)
+	// Allowed by the handler and the logger
+	gethLogger.Info("Imported new chain segment", "number", uint64(5), "txs", 2)
+	// Allowed by the handler but not by the logger
+	assert.NoError(handler.Log(&log.Record{Lvl: log.LvlDebug, Msg: "debug"}))
+	// Allowed by the logger but not by the handler
+	handler.Verbosity(log.LvlWarn)
+	assert.NoError(handler.Log(&log.Record{Lvl: log.LvlInfo, Msg: "info"}))
+	assert.NoError(handler.Log(&log.Record{Lvl: log.LvlError, Msg: "failed", Ctx: []interface{}{"err", "failure"}}))
+	factory.Close()
+
+	file, err := os.Open(filepath.Join(config.Directory, "C.log"))
+	assert.NoError(err)
+	defer file.Close()
+
+	var lines []map[string]interface{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := map[string]interface{}{}
+		assert.NoError(json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+	assert.NoError(scanner.Err())
+	if !assert.Len(lines, 2) {
+		return
+	}
+
+	assert.Equal("INFO", lines[0]["level"])
+	assert.Equal("Imported new chain segment", lines[0]["msg"])
+	assert.Equal(float64(5), lines[0][logging.HeightKey])
+	assert.Equal(float64(2), lines[0]["txs"])
+	assert.Equal("C", lines[0][logging.ChainKey])
+	// The location of the geth call is logged rather than of the handler
+	assert.True(strings.HasPrefix(lines[0][logging.CallerKey].(string), "coreth/plugin/evm/log_test.go#"), lines[0][logging.CallerKey])
+
+	assert.Equal("ERROR", lines[1]["level"])
+	assert.Equal("failure", lines[1]["err"])
+}
+
+func TestToLoggingLevel(t *testing.T) {
+	tests := map[log.Lvl]logging.Level{
+		log.LvlCrit:  logging.Fatal,
+		log.LvlError: logging.Error,
+		log.LvlWarn:  logging.Warn,
+		log.LvlInfo:  logging.Info,
+		log.LvlDebug: logging.Debug,
+		log.LvlTrace: logging.Verbo,
+	}
+	for lvl, expected := range tests {
+		assert.Equal(t, expected, toLoggingLevel(lvl), lvl.String())
+	}
+}
@@ -20,6 +20,7 @@ func BuildCodec() (codec.Manager, error) {
 	errs.Add(
 		c.RegisterType(&AtomicTx{}),
 		c.RegisterType(&EthTxs{}),
+		c.RegisterType(&PrivateEthTxs{}),
 	)
 	errs.Add(codecManager.RegisterCodec(Version, c))
 	return codecManager, errs.Err
@@ -15,6 +15,7 @@ var _ GossipHandler = NoopMempoolGossipHandler{}
 type GossipHandler interface {
 	HandleAtomicTx(nodeID ids.ShortID, msg *AtomicTx) error
 	HandleEthTxs(nodeID ids.ShortID, msg *EthTxs) error
+	HandlePrivateEthTxs(nodeID ids.ShortID, msg *PrivateEthTxs) error
 }
 
 type NoopMempoolGossipHandler struct{}
@@ -29,6 +30,11 @@ func (NoopMempoolGossipHandler) HandleEthTxs(nodeID ids.ShortID, _ *EthTxs) erro
 	return nil
 }
 
+func (NoopMempoolGossipHandler) HandlePrivateEthTxs(nodeID ids.ShortID, _ *PrivateEthTxs) error {
+	log.Debug("dropping unexpected PrivateEthTxs message", "peerID", nodeID)
+	return nil
+}
+
 // RequestHandler interface handles incoming requests from peers
 // Must have methods in format of handleType(context.Context, ids.ShortID, uint32, request Type) error
 // so that the Request object of relevant Type can invoke its respective handle method
@@ -12,7 +12,7 @@ import (
 )
 
 type CounterHandler struct {
-	AtomicTx, EthTxs int
+	AtomicTx, EthTxs, PrivateEthTxs int
 }
 
 func (h *CounterHandler) HandleAtomicTx(ids.ShortID, *AtomicTx) error {
@@ -25,6 +25,11 @@ func (h *CounterHandler) HandleEthTxs(ids.ShortID, *EthTxs) error {
 	return nil
 }
 
+func (h *CounterHandler) HandlePrivateEthTxs(ids.ShortID, *PrivateEthTxs) error {
+	h.PrivateEthTxs++
+	return nil
+}
+
 func TestHandleAtomicTx(t *testing.T) {
 	assert := assert.New(t)
 
@@ -47,6 +52,20 @@ func TestHandleEthTxs(t *testing.T) {
 	assert.NoError(err)
 	assert.Zero(handler.AtomicTx)
 	assert.Equal(1, handler.EthTxs)
+	assert.Zero(handler.PrivateEthTxs)
+}
+
+func TestHandlePrivateEthTxs(t *testing.T) {
+	assert := assert.New(t)
+
+	handler := CounterHandler{}
+	msg := PrivateEthTxs{}
+
+	err := msg.Handle(&handler, ids.ShortEmpty)
+	assert.NoError(err)
+	assert.Zero(handler.AtomicTx)
+	assert.Zero(handler.EthTxs)
+	assert.Equal(1, handler.PrivateEthTxs)
 }
 
 func TestNoopHandler(t *testing.T) {
@@ -59,4 +78,7 @@ func TestNoopHandler(t *testing.T) {
 
 	err = handler.HandleEthTxs(ids.ShortEmpty, nil)
 	assert.NoError(err)
+
+	err = handler.HandlePrivateEthTxs(ids.ShortEmpty, nil)
+	assert.NoError(err)
 }
@@ -22,11 +22,13 @@ const (
 	EthMsgSoftCapSize = common.StorageSize(64 * units.KiB)
 	atomicTxType      = "atomic-tx"
 	ethTxsType        = "eth-txs"
+	privateEthTxsType = "private-eth-txs"
 )
 
 var (
 	_ Message = &AtomicTx{}
 	_ Message = &EthTxs{}
+	_ Message = &PrivateEthTxs{}
 
 	errUnexpectedCodecVersion = errors.New("unexpected codec version")
 )
@@ -80,6 +82,22 @@ func (msg *EthTxs) Type() string {
 	return ethTxsType
 }
 
+// PrivateEthTxs contains transactions that were submitted privately and must
+// only be gossiped to the configured private gossip nodes.
+type PrivateEthTxs struct {
+	message
+
+	Txs []byte `serialize:"true"`
+}
+
+func (msg *PrivateEthTxs) Handle(handler GossipHandler, nodeID ids.ShortID) error {
+	return handler.HandlePrivateEthTxs(nodeID, msg)
+}
+
+func (msg *PrivateEthTxs) Type() string {
+	return privateEthTxsType
+}
+
 func ParseMessage(codec codec.Manager, bytes []byte) (Message, error) {
 	var msg Message
 	version, err := codec.Unmarshal(bytes, &msg)
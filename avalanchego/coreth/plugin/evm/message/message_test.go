@@ -58,6 +58,29 @@ func TestEthTxs(t *testing.T) {
 	assert.Equal(msg, parsedMsg.Txs)
 }
 
+func TestPrivateEthTxs(t *testing.T) {
+	assert := assert.New(t)
+
+	msg := []byte("blah")
+	builtMsg := PrivateEthTxs{
+		Txs: msg,
+	}
+	codec, err := BuildCodec()
+	assert.NoError(err)
+	builtMsgBytes, err := BuildMessage(codec, &builtMsg)
+	assert.NoError(err)
+	assert.Equal(builtMsgBytes, builtMsg.Bytes())
+
+	parsedMsgIntf, err := ParseMessage(codec, builtMsgBytes)
+	assert.NoError(err)
+	assert.Equal(builtMsgBytes, parsedMsgIntf.Bytes())
+
+	parsedMsg, ok := parsedMsgIntf.(*PrivateEthTxs)
+	assert.True(ok)
+
+	assert.Equal(msg, parsedMsg.Txs)
+}
+
 func TestEthTxsTooLarge(t *testing.T) {
 	assert := assert.New(t)
 
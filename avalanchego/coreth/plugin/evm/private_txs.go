@@ -0,0 +1,92 @@
+// (c) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/flare-foundation/flare/coreth/core/types"
+)
+
+// PrivateTxs tracks eth transactions that were submitted privately. Private
+// transactions are only gossiped to the configured private gossip nodes and
+// are dropped from the tx pool if they are not accepted before they expire.
+type PrivateTxs struct {
+	lock sync.RWMutex
+
+	// expiries maps the hash of each private transaction to the height of the
+	// last block it may be accepted in.
+	expiries map[common.Hash]uint64
+}
+
+// NewPrivateTxs returns an empty set of private transactions
+func NewPrivateTxs() *PrivateTxs {
+	return &PrivateTxs{
+		expiries: make(map[common.Hash]uint64),
+	}
+}
+
+// Add marks [txHash] as private until the block at height [expiry] has been
+// accepted. If [txHash] is already private, the earlier expiry is kept.
+func (p *PrivateTxs) Add(txHash common.Hash, expiry uint64) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if current, ok := p.expiries[txHash]; ok && current <= expiry {
+		return
+	}
+	p.expiries[txHash] = expiry
+}
+
+// Has returns true if [txHash] is a private transaction
+func (p *PrivateTxs) Has(txHash common.Hash) bool {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	_, ok := p.expiries[txHash]
+	return ok
+}
+
+// Remove stops tracking [txHash] as a private transaction
+func (p *PrivateTxs) Remove(txHash common.Hash) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	delete(p.expiries, txHash)
+}
+
+// Len returns the number of tracked private transactions
+func (p *PrivateTxs) Len() int {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	return len(p.expiries)
+}
+
+// Expire stops tracking and returns the private transactions that may no
+// longer be accepted once the block at [height] has been accepted.
+func (p *PrivateTxs) Expire(height uint64) []common.Hash {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	var expired []common.Hash
+	for txHash, expiry := range p.expiries {
+		if expiry <= height {
+			expired = append(expired, txHash)
+			delete(p.expiries, txHash)
+		}
+	}
+	return expired
+}
+
+// markPrivateTxs marks [txs] as private until [PrivateTxExpiryBlocks] blocks
+// after the last accepted block have been accepted.
+func (vm *VM) markPrivateTxs(txs []*types.Transaction) {
+	expiry := vm.chain.LastAcceptedBlock().NumberU64() + vm.config.PrivateTxExpiryBlocks
+	for _, tx := range txs {
+		vm.privateTxs.Add(tx.Hash(), expiry)
+	}
+}
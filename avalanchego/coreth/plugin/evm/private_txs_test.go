@@ -0,0 +1,36 @@
+// (c) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrivateTxsExpire(t *testing.T) {
+	assert := assert.New(t)
+
+	var (
+		privateTxs = NewPrivateTxs()
+		tx0        = common.Hash{1}
+		tx1        = common.Hash{2}
+	)
+
+	privateTxs.Add(tx0, 5)
+	privateTxs.Add(tx1, 10)
+	// A later expiry must not extend the expiry of a private tx
+	privateTxs.Add(tx0, 20)
+	assert.Equal(2, privateTxs.Len())
+
+	assert.Empty(privateTxs.Expire(4))
+	assert.Equal([]common.Hash{tx0}, privateTxs.Expire(5))
+	assert.False(privateTxs.Has(tx0))
+	assert.True(privateTxs.Has(tx1))
+
+	privateTxs.Remove(tx1)
+	assert.Empty(privateTxs.Expire(10))
+	assert.Zero(privateTxs.Len())
+}
@@ -15,12 +15,14 @@ import (
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/flare-foundation/flare/api"
+	"github.com/flare-foundation/flare/coreth/core/types"
 	"github.com/flare-foundation/flare/coreth/params"
 	"github.com/flare-foundation/flare/ids"
 	"github.com/flare-foundation/flare/utils/constants"
 	"github.com/flare-foundation/flare/utils/crypto"
 	"github.com/flare-foundation/flare/utils/formatting"
 	"github.com/flare-foundation/flare/utils/json"
+	"github.com/flare-foundation/flare/vms/components/keystore"
 )
 
 // test constants
@@ -36,6 +38,7 @@ var (
 	errNoAddresses   = errors.New("no addresses provided")
 	errNoSourceChain = errors.New("no source chain provided")
 	errNilTxID       = errors.New("nil transaction ID")
+	errUnprotectedTx = errors.New("only replay-protected (EIP-155) transactions allowed over RPC")
 
 	initialBaseFee = big.NewInt(params.ApricotPhase3InitialBaseFee)
 )
@@ -67,6 +70,39 @@ func (api *SnowmanAPI) IssueBlock(ctx context.Context) error {
 	return nil
 }
 
+// PrivateTxAPI offers methods to submit eth transactions that are only
+// gossiped to the configured private gossip nodes
+type PrivateTxAPI struct{ vm *VM }
+
+// SendPrivateRawTransaction adds the signed transaction to the local tx pool
+// and only gossips it to the configured private gossip nodes. The transaction
+// is dropped from the tx pool if it is not accepted within
+// [PrivateTxExpiryBlocks] blocks.
+func (api *PrivateTxAPI) SendPrivateRawTransaction(ctx context.Context, input hexutil.Bytes) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input); err != nil {
+		return common.Hash{}, err
+	}
+	if !api.vm.config.AllowUnprotectedTxs && !tx.Protected() {
+		return common.Hash{}, errUnprotectedTx
+	}
+
+	// The tx must be marked as private before it is added to the tx pool so
+	// that it is never gossiped to the rest of the network.
+	txHash := tx.Hash()
+	txPool := api.vm.chain.GetTxPool()
+	api.vm.markPrivateTxs([]*types.Transaction{tx})
+	if err := txPool.AddLocal(tx); err != nil {
+		if !txPool.Has(txHash) {
+			api.vm.privateTxs.Remove(txHash)
+		}
+		return common.Hash{}, err
+	}
+
+	log.Info("Submitted private transaction", "hash", txHash.Hex(), "nonce", tx.Nonce(), "recipient", tx.To(), "nodes", api.vm.privateTxGossipNodes.Len())
+	return txHash, nil
+}
+
 // AvaxAPI offers Avalanche network related API methods
 type AvaxAPI struct{ vm *VM }
 
@@ -229,9 +265,9 @@ func (service *AvaxAPI) Import(_ *http.Request, args *ImportArgs, response *api.
 		secpFactory: &service.vm.secpFactory,
 		db:          db,
 	}
-	privKeys, err := user.getKeys()
-	if err != nil { // Get keys
-		return fmt.Errorf("couldn't get keys controlled by the user: %w", err)
+	kc, err := keystore.GetSignerKeychain(service.vm.signer(&user), nil)
+	if err != nil { // Get addresses
+		return fmt.Errorf("couldn't get addresses controlled by the user: %w", err)
 	}
 
 	var baseFee *big.Int
@@ -245,7 +281,7 @@ func (service *AvaxAPI) Import(_ *http.Request, args *ImportArgs, response *api.
 		baseFee = args.BaseFee.ToInt()
 	}
 
-	tx, err := service.vm.newImportTx(chainID, to, baseFee, privKeys)
+	tx, err := service.vm.newImportTx(chainID, to, baseFee, kc)
 	if err != nil {
 		return err
 	}
@@ -315,7 +351,7 @@ func (service *AvaxAPI) Export(_ *http.Request, args *ExportArgs, response *api.
 		secpFactory: &service.vm.secpFactory,
 		db:          db,
 	}
-	privKeys, err := user.getKeys()
+	kc, err := keystore.GetSignerKeychain(service.vm.signer(&user), nil)
 	if err != nil {
 		return fmt.Errorf("couldn't get addresses controlled by the user: %w", err)
 	}
@@ -338,7 +374,7 @@ func (service *AvaxAPI) Export(_ *http.Request, args *ExportArgs, response *api.
 		chainID,             // ID of the chain to send the funds to
 		to,                  // Address
 		baseFee,
-		privKeys, // Private keys
+		kc, // Keychain
 	)
 	if err != nil {
 		return fmt.Errorf("couldn't create tx: %w", err)
@@ -127,23 +127,23 @@ type Tx struct {
 
 // Sign this transaction with the provided signers
 func (tx *Tx) Sign(c codec.Manager, signers [][]*crypto.PrivateKeySECP256K1R) error {
+	kc, addrs := secp256k1fx.SignerAddrs(signers)
+	return tx.SignWithSigner(c, kc, addrs)
+}
+
+// SignWithSigner signs this transaction with [signer], using the keys that
+// control the addresses in [signers]
+func (tx *Tx) SignWithSigner(c codec.Manager, signer secp256k1fx.Signer, signers [][]ids.ShortID) error {
 	unsignedBytes, err := c.Marshal(codecVersion, &tx.UnsignedAtomicTx)
 	if err != nil {
 		return fmt.Errorf("couldn't marshal UnsignedAtomicTx: %w", err)
 	}
 
 	// Attach credentials
-	hash := hashing.ComputeHash256(unsignedBytes)
-	for _, keys := range signers {
-		cred := &secp256k1fx.Credential{
-			Sigs: make([][crypto.SECP256K1RSigLen]byte, len(keys)),
-		}
-		for i, key := range keys {
-			sig, err := key.SignHash(hash) // Sign hash
-			if err != nil {
-				return fmt.Errorf("problem generating credential: %w", err)
-			}
-			copy(cred.Sigs[i][:], sig)
+	for _, addrs := range signers {
+		cred, err := secp256k1fx.Sign(signer, unsignedBytes, addrs)
+		if err != nil {
+			return err
 		}
 		tx.Creds = append(tx.Creds, cred) // Attach credential
 	}
@@ -193,7 +193,7 @@ func (tx *Tx) BlockFeeContribution(fixedFee bool, avaxAssetID ids.ID, baseFee *b
 // innerSortInputsAndSigners implements sort.Interface for EVMInput
 type innerSortInputsAndSigners struct {
 	inputs  []EVMInput
-	signers [][]*crypto.PrivateKeySECP256K1R
+	signers [][]ids.ShortID
 }
 
 func (ins *innerSortInputsAndSigners) Less(i, j int) bool {
@@ -212,7 +212,7 @@ func (ins *innerSortInputsAndSigners) Swap(i, j int) {
 }
 
 // SortEVMInputsAndSigners sorts the list of EVMInputs based on the addresses and assetIDs
-func SortEVMInputsAndSigners(inputs []EVMInput, signers [][]*crypto.PrivateKeySECP256K1R) {
+func SortEVMInputsAndSigners(inputs []EVMInput, signers [][]ids.ShortID) {
 	sort.Sort(&innerSortInputsAndSigners{inputs: inputs, signers: signers})
 }
 
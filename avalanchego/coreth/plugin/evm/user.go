@@ -11,6 +11,7 @@ import (
 	"github.com/flare-foundation/flare/database/encdb"
 	"github.com/flare-foundation/flare/ids"
 	"github.com/flare-foundation/flare/utils/crypto"
+	"github.com/flare-foundation/flare/vms/components/keystore"
 )
 
 // Key in the database whose corresponding value is the list of
@@ -18,8 +19,11 @@ import (
 var addressesKey = ids.Empty[:]
 
 var (
-	errDBNil  = errors.New("db uninitialized")
-	errKeyNil = errors.New("key uninitialized")
+	errDBNil          = errors.New("db uninitialized")
+	errKeyNil         = errors.New("key uninitialized")
+	errUnknownAddress = errors.New("user doesn't control the address")
+
+	_ keystore.Signer = &user{}
 )
 
 type user struct {
@@ -143,3 +147,30 @@ func (u *user) getKeys() ([]*crypto.PrivateKeySECP256K1R, error) {
 	}
 	return keys, nil
 }
+
+// PublicKeys implements keystore.Signer
+func (u *user) PublicKeys() ([]*crypto.PublicKeySECP256K1R, error) {
+	keys, err := u.getKeys()
+	if err != nil {
+		return nil, err
+	}
+	pks := make([]*crypto.PublicKeySECP256K1R, len(keys))
+	for i, key := range keys {
+		pks[i] = key.PublicKey().(*crypto.PublicKeySECP256K1R)
+	}
+	return pks, nil
+}
+
+// SignTx implements keystore.Signer
+func (u *user) SignTx(address ids.ShortID, unsignedTx []byte) ([]byte, error) {
+	keys, err := u.getKeys()
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		if key.PublicKey().Address() == address {
+			return key.Sign(unsignedTx)
+		}
+	}
+	return nil, fmt.Errorf("%w %s", errUnknownAddress, address)
+}
@@ -67,6 +67,7 @@ import (
 	"github.com/flare-foundation/flare/utils/timer/mockable"
 	"github.com/flare-foundation/flare/vms/components/avax"
 	"github.com/flare-foundation/flare/vms/components/chain"
+	"github.com/flare-foundation/flare/vms/components/keystore"
 	"github.com/flare-foundation/flare/vms/secp256k1fx"
 
 	commonEng "github.com/flare-foundation/flare/snow/engine/common"
@@ -157,6 +158,7 @@ var (
 	errNoEVMOutputs                   = errors.New("tx has no EVM outputs")
 	errNilBaseFeeApricotPhase3        = errors.New("nil base fee is invalid after apricotPhase3")
 	errNilExtDataGasUsedApricotPhase4 = errors.New("nil extDataGasUsed is invalid after apricotPhase4")
+	errMissingPublicKey               = errors.New("missing public key of address")
 	errNilBlockGasCostApricotPhase4   = errors.New("nil blockGasCost is invalid after apricotPhase4")
 	errConflictingAtomicTx            = errors.New("conflicting atomic tx present")
 	errTooManyAtomicTx                = errors.New("too many atomic tx")
@@ -178,6 +180,8 @@ func init() {
 // VM implements the snowman.ChainVM interface
 type VM struct {
 	ctx *snow.Context
+	// [logHandler] writes geth log records to the context logger
+	logHandler *log.GlogHandler
 	// *chain.State helps to implement the VM interface by wrapping blocks
 	// with an efficient caching layer.
 	*chain.State
@@ -209,6 +213,11 @@ type VM struct {
 
 	gossiper Gossiper
 
+	// [privateTxs] tracks the eth txs that are only gossiped to
+	// [privateTxGossipNodes].
+	privateTxs           *PrivateTxs
+	privateTxGossipNodes ids.ShortSet
+
 	baseCodec codec.Registry
 	codec     codec.Manager
 	clock     mockable.Clock
@@ -219,6 +228,9 @@ type VM struct {
 
 	fx          secp256k1fx.Fx
 	secpFactory crypto.FactorySECP256K1R
+	// [remoteSigner] signs the atomic txs issued through the keystore API
+	// instead of the keys of the user, if set
+	remoteSigner keystore.ChainSigner
 
 	// Continuous Profiler
 	profiler profiler.ContinuousProfiler
@@ -242,14 +254,20 @@ func (vm *VM) Clock() *mockable.Clock { return &vm.clock }
 // Logger implements the secp256k1fx interface
 func (vm *VM) Logger() logging.Logger { return vm.ctx.Log }
 
-// setLogLevel sets the log level with the original [os.StdErr] interface along
-// with the context logger.
+// TxPool returns the pool of the pending EVM transactions of the VM
+func (vm *VM) TxPool() *core.TxPool { return vm.chain.GetTxPool() }
+
+// setLogLevel sets the level of the geth log records that are written to the
+// context logger. The records are further filtered by the levels of the
+// context logger.
 func (vm *VM) setLogLevel(logLevel log.Lvl) {
-	format := log.TerminalFormat(false)
-	log.Root().SetHandler(log.LvlFilterHandler(logLevel, log.MultiHandler(
-		log.StreamHandler(originalStderr, format),
-		log.StreamHandler(vm.ctx.Log, format),
-	)))
+	vm.logHandler.Verbosity(logLevel)
+}
+
+// setLogModules overrides the level of the geth log records of the modules
+// matched by [ruleset], such as "core/*=5,eth/downloader=4".
+func (vm *VM) setLogModules(ruleset string) error {
+	return vm.logHandler.Vmodule(ruleset)
 }
 
 /*
@@ -332,7 +350,8 @@ func (vm *VM) Initialize(
 		return fmt.Errorf("failed to initialize logger due to: %w ", err)
 	}
 
-	vm.setLogLevel(logLevel)
+	vm.logHandler = newLogHandler(vm.ctx.Log, logLevel)
+	log.Root().SetHandler(vm.logHandler)
 
 	// Set minimum price for mining and default gas price oracle value to the min
 	// gas price to prevent so transactions and blocks all use the correct fees
@@ -340,8 +359,13 @@ func (vm *VM) Initialize(
 	ethConfig.RPCEVMTimeout = vm.config.APIMaxDuration.Duration
 	ethConfig.RPCTxFeeCap = vm.config.RPCTxFeeCap
 	ethConfig.TxPool.NoLocals = !vm.config.LocalTxsEnabled
+	ethConfig.TxPool.PriorityLane, err = vm.config.PriorityLane()
+	if err != nil {
+		return err
+	}
 	ethConfig.AllowUnfinalizedQueries = vm.config.AllowUnfinalizedQueries
 	ethConfig.AllowUnprotectedTxs = vm.config.AllowUnprotectedTxs
+	ethConfig.ReadOnly = vm.ctx.ReadOnly
 	ethConfig.Preimages = vm.config.Preimages
 	ethConfig.Pruning = vm.config.Pruning
 	ethConfig.SnapshotAsync = vm.config.SnapshotAsync
@@ -417,6 +441,12 @@ func (vm *VM) Initialize(
 		return err
 	}
 
+	vm.privateTxs = NewPrivateTxs()
+	vm.privateTxGossipNodes, err = vm.config.PrivateTxGossipNodes()
+	if err != nil {
+		return err
+	}
+
 	// initialize peer network
 	vm.Network = peer.NewNetwork(appSender, vm.networkCodec, ctx.NodeID, vm.config.MaxOutboundActiveRequests)
 	vm.client = peer.NewClient(vm.Network)
@@ -925,6 +955,13 @@ func (vm *VM) CreateHandlers() (map[string]*commonEng.HTTPHandler, error) {
 		enabledAPIs = append(enabledAPIs, "snowman")
 	}
 
+	if vm.config.PrivateTxAPIEnabled {
+		if err := handler.RegisterName("eth", &PrivateTxAPI{vm}); err != nil {
+			return nil, err
+		}
+		enabledAPIs = append(enabledAPIs, "private-tx")
+	}
+
 	log.Info(fmt.Sprintf("Enabled APIs: %s", strings.Join(enabledAPIs, ", ")))
 	apis[ethRPCEndpoint] = &commonEng.HTTPHandler{
 		LockOptions: commonEng.NoLock,
@@ -1050,6 +1087,9 @@ func (vm *VM) ParseAddress(addrStr string) (ids.ID, ids.ShortID, error) {
 // issueTx verifies [tx] as valid to be issued on top of the currently preferred block
 // and then issues [tx] into the mempool if valid.
 func (vm *VM) issueTx(tx *Tx, local bool) error {
+	if local && vm.ctx.ReadOnly {
+		return snow.ErrReadOnly
+	}
 	if err := vm.verifyTxAtTip(tx); err != nil {
 		if !local {
 			// unlike local txs, invalid remote txs are recorded as discarded
@@ -1176,30 +1216,43 @@ func (vm *VM) GetAtomicUTXOs(
 	return utxos, lastAddrID, lastUTXOID, nil
 }
 
-// GetSpendableFunds returns a list of EVMInputs and keys (in corresponding
-// order) to total [amount] of [assetID] owned by [keys].
-// Note: we return [][]*crypto.PrivateKeySECP256K1R even though each input
-// corresponds to a single key, so that the signers can be passed in to
-// [tx.Sign] which supports multiple keys on a single input.
+// signer returns the signer of the atomic txs issued by [user]: the remote
+// signer if there is one, or the keys of [user] otherwise
+func (vm *VM) signer(user *user) keystore.Signer {
+	if vm.remoteSigner == nil {
+		return user
+	}
+	return keystore.NewChainSigner(vm.remoteSigner, vm.ctx.ChainID)
+}
+
+// GetSpendableFunds returns a list of EVMInputs and addresses (in corresponding
+// order) to total [amount] of [assetID] owned by the addresses of [kc].
+// Note: we return [][]ids.ShortID even though each input corresponds to a
+// single address, so that the signers can be passed in to [tx.SignWithSigner]
+// which supports multiple signatures on a single input.
 func (vm *VM) GetSpendableFunds(
-	keys []*crypto.PrivateKeySECP256K1R,
+	kc *secp256k1fx.SignerKeychain,
 	assetID ids.ID,
 	amount uint64,
-) ([]EVMInput, [][]*crypto.PrivateKeySECP256K1R, error) {
+) ([]EVMInput, [][]ids.ShortID, error) {
 	// Note: current state uses the state of the preferred block.
 	state, err := vm.chain.CurrentState()
 	if err != nil {
 		return nil, nil, err
 	}
 	inputs := []EVMInput{}
-	signers := [][]*crypto.PrivateKeySECP256K1R{}
-	// Note: we assume that each key in [keys] is unique, so that iterating over
-	// the keys will not produce duplicated nonces in the returned EVMInput slice.
-	for _, key := range keys {
+	signers := [][]ids.ShortID{}
+	// Note: the addresses of [kc] are unique, so that iterating over them will
+	// not produce duplicated nonces in the returned EVMInput slice.
+	for _, shortAddr := range kc.AddrList {
 		if amount == 0 {
 			break
 		}
-		addr := GetEthAddress(key)
+		pk, ok := kc.PublicKey(shortAddr)
+		if !ok {
+			return nil, nil, fmt.Errorf("%w %s", errMissingPublicKey, shortAddr)
+		}
+		addr := PublicKeyToEthAddress(pk)
 		var balance uint64
 		if assetID == vm.ctx.AVAXAssetID {
 			// If the asset is AVAX, we divide by the x2cRate to convert back to the correct
@@ -1224,7 +1277,7 @@ func (vm *VM) GetSpendableFunds(
 			AssetID: assetID,
 			Nonce:   nonce,
 		})
-		signers = append(signers, []*crypto.PrivateKeySECP256K1R{key})
+		signers = append(signers, []ids.ShortID{shortAddr})
 		amount -= balance
 	}
 
@@ -1235,20 +1288,21 @@ func (vm *VM) GetSpendableFunds(
 	return inputs, signers, nil
 }
 
-// GetSpendableAVAXWithFee returns a list of EVMInputs and keys (in corresponding
-// order) to total [amount] + [fee] of [AVAX] owned by [keys].
+// GetSpendableAVAXWithFee returns a list of EVMInputs and addresses (in
+// corresponding order) to total [amount] + [fee] of [AVAX] owned by the
+// addresses of [kc].
 // This function accounts for the added cost of the additional inputs needed to
-// create the transaction and makes sure to skip any keys with a balance that is
-// insufficient to cover the additional fee.
-// Note: we return [][]*crypto.PrivateKeySECP256K1R even though each input
-// corresponds to a single key, so that the signers can be passed in to
-// [tx.Sign] which supports multiple keys on a single input.
+// create the transaction and makes sure to skip any addresses with a balance
+// that is insufficient to cover the additional fee.
+// Note: we return [][]ids.ShortID even though each input corresponds to a
+// single address, so that the signers can be passed in to [tx.SignWithSigner]
+// which supports multiple signatures on a single input.
 func (vm *VM) GetSpendableAVAXWithFee(
-	keys []*crypto.PrivateKeySECP256K1R,
+	kc *secp256k1fx.SignerKeychain,
 	amount uint64,
 	cost uint64,
 	baseFee *big.Int,
-) ([]EVMInput, [][]*crypto.PrivateKeySECP256K1R, error) {
+) ([]EVMInput, [][]ids.ShortID, error) {
 	// Note: current state uses the state of the preferred block.
 	state, err := vm.chain.CurrentState()
 	if err != nil {
@@ -1267,10 +1321,10 @@ func (vm *VM) GetSpendableAVAXWithFee(
 	amount = newAmount
 
 	inputs := []EVMInput{}
-	signers := [][]*crypto.PrivateKeySECP256K1R{}
-	// Note: we assume that each key in [keys] is unique, so that iterating over
-	// the keys will not produce duplicated nonces in the returned EVMInput slice.
-	for _, key := range keys {
+	signers := [][]ids.ShortID{}
+	// Note: the addresses of [kc] are unique, so that iterating over them will
+	// not produce duplicated nonces in the returned EVMInput slice.
+	for _, shortAddr := range kc.AddrList {
 		if amount == 0 {
 			break
 		}
@@ -1288,7 +1342,11 @@ func (vm *VM) GetSpendableAVAXWithFee(
 
 		additionalFee := newFee - prevFee
 
-		addr := GetEthAddress(key)
+		pk, ok := kc.PublicKey(shortAddr)
+		if !ok {
+			return nil, nil, fmt.Errorf("%w %s", errMissingPublicKey, shortAddr)
+		}
+		addr := PublicKeyToEthAddress(pk)
 		// Since the asset is AVAX, we divide by the x2cRate to convert back to
 		// the correct denomination of AVAX that can be exported.
 		balance := new(big.Int).Div(state.GetBalance(addr), x2cRate).Uint64()
@@ -1324,7 +1382,7 @@ func (vm *VM) GetSpendableAVAXWithFee(
 			AssetID: vm.ctx.AVAXAssetID,
 			Nonce:   nonce,
 		})
-		signers = append(signers, []*crypto.PrivateKeySECP256K1R{key})
+		signers = append(signers, []ids.ShortID{shortAddr})
 		amount -= inputAmount
 	}
 
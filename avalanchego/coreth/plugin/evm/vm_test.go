@@ -4,6 +4,7 @@
 package evm
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -37,6 +38,7 @@ import (
 	engCommon "github.com/flare-foundation/flare/snow/engine/common"
 
 	"github.com/flare-foundation/flare/coreth/core"
+	"github.com/flare-foundation/flare/coreth/core/types"
 	"github.com/flare-foundation/flare/coreth/params"
 )
 
@@ -296,6 +298,30 @@ func TestVMNilConfig(t *testing.T) {
 	assert.NoError(t, vm.Shutdown())
 }
 
+func TestVMReadOnly(t *testing.T) {
+	vm := &VM{}
+	ctx, dbManager, genesisBytes, issuer, _ := setupGenesis(t, genesisJSONApricotPhase0)
+	ctx.ReadOnly = true
+	if err := vm.Initialize(
+		ctx,
+		dbManager,
+		genesisBytes,
+		nil,
+		nil,
+		issuer,
+		[]*engCommon.Fx{},
+		&engCommon.SenderTest{},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	tx := types.NewTransaction(0, common.Address{}, big.NewInt(1), params.TxGas, big.NewInt(1), nil)
+	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(vm.chainID), testKeys[0].ToECDSA())
+	assert.NoError(t, err)
+	assert.ErrorIs(t, vm.chain.APIBackend().SendTx(context.Background(), signedTx), snow.ErrReadOnly)
+	assert.NoError(t, vm.Shutdown())
+}
+
 func TestVMContinuosProfiler(t *testing.T) {
 	profilerDir := t.TempDir()
 	profilerFrequency := 500 * time.Millisecond
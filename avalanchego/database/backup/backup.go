@@ -0,0 +1,263 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+// Package backup writes the contents of a database to a backup directory and
+// loads them back into a database.
+//
+// A backup directory holds a data file, with every key-value pair of the
+// database in order, and a manifest, which describes the data file. The
+// manifest is written last, so a backup without one is incomplete.
+package backup
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/flare-foundation/flare/database"
+	"github.com/flare-foundation/flare/utils/perms"
+	"github.com/flare-foundation/flare/utils/units"
+	"github.com/flare-foundation/flare/version"
+)
+
+const (
+	dataFile     = "data"
+	manifestFile = "manifest.json"
+
+	// Number of bytes written to the database at once when a backup is loaded
+	loadBatchSize = 4 * units.MiB
+
+	// Max size of a key or value read from a backup, so that a corrupted
+	// length can't exhaust memory before the checksum is checked
+	maxBytesLength = 256 * units.MiB
+)
+
+var (
+	errNoManifest       = errors.New("backup has no manifest, so it's incomplete")
+	errChecksumMismatch = errors.New("backup data doesn't match the checksum in its manifest")
+	errTrailingData     = errors.New("backup data has more key-value pairs than its manifest")
+)
+
+// Manifest describes a backup
+type Manifest struct {
+	// Version of the database that was backed up
+	DatabaseVersion string `json:"databaseVersion"`
+	// Time the backup was started at
+	Time time.Time `json:"time"`
+	// Number of key-value pairs in the backup
+	NumKeys uint64 `json:"numKeys"`
+	// Size, in bytes, of the data file
+	Size uint64 `json:"size"`
+	// Hex encoded SHA256 of the data file
+	Checksum string `json:"checksum"`
+}
+
+// Create writes every key-value pair in [db] to a new backup directory at
+// [dir]. [dbVersion] is the version of [db].
+//
+// A single iterator is used to read [db]. Iterators see a snapshot of the
+// database from when they were created, so the backup is consistent even if
+// [db] is written to while it's created.
+func Create(db database.Iteratee, dbVersion version.Version, dir string) (Manifest, error) {
+	if err := os.MkdirAll(filepath.Dir(dir), perms.ReadWriteExecute); err != nil {
+		return Manifest{}, err
+	}
+	// Fails if [dir] already exists, so that no backup is overwritten
+	if err := os.Mkdir(dir, perms.ReadWriteExecute); err != nil {
+		return Manifest{}, err
+	}
+	manifest, err := create(db, dbVersion, dir)
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return Manifest{}, err
+	}
+	return manifest, nil
+}
+
+func create(db database.Iteratee, dbVersion version.Version, dir string) (Manifest, error) {
+	manifest := Manifest{
+		DatabaseVersion: dbVersion.String(),
+		Time:            time.Now().UTC(),
+	}
+
+	it := db.NewIterator()
+	defer it.Release()
+
+	f, err := os.OpenFile(filepath.Join(dir, dataFile), os.O_CREATE|os.O_EXCL|os.O_WRONLY, perms.ReadWrite)
+	if err != nil {
+		return Manifest{}, err
+	}
+	defer f.Close()
+
+	checksum := sha256.New()
+	w := &countingWriter{w: io.MultiWriter(f, checksum)}
+	buffered := bufio.NewWriter(w)
+	for it.Next() {
+		if err := writeBytes(buffered, it.Key()); err != nil {
+			return Manifest{}, err
+		}
+		if err := writeBytes(buffered, it.Value()); err != nil {
+			return Manifest{}, err
+		}
+		manifest.NumKeys++
+	}
+	if err := it.Error(); err != nil {
+		return Manifest{}, err
+	}
+	if err := buffered.Flush(); err != nil {
+		return Manifest{}, err
+	}
+	if err := f.Sync(); err != nil {
+		return Manifest{}, err
+	}
+	manifest.Size = w.n
+	manifest.Checksum = hex.EncodeToString(checksum.Sum(nil))
+
+	manifestBytes, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return Manifest{}, err
+	}
+	return manifest, ioutil.WriteFile(filepath.Join(dir, manifestFile), manifestBytes, perms.ReadWrite)
+}
+
+// ReadManifest returns the manifest of the backup in [dir]
+func ReadManifest(dir string) (Manifest, error) {
+	manifestBytes, err := ioutil.ReadFile(filepath.Join(dir, manifestFile))
+	if os.IsNotExist(err) {
+		return Manifest{}, errNoManifest
+	}
+	if err != nil {
+		return Manifest{}, err
+	}
+	manifest := Manifest{}
+	if err := json.Unmarshal(manifestBytes, &manifest); err != nil {
+		return Manifest{}, fmt.Errorf("couldn't parse manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+// Verify returns the manifest of the backup in [dir] if the backup is of a
+// [dbVersion] database, and its data matches the manifest
+func Verify(dir string, dbVersion version.Version) (Manifest, error) {
+	manifest, err := ReadManifest(dir)
+	if err != nil {
+		return Manifest{}, err
+	}
+	if manifest.DatabaseVersion != dbVersion.String() {
+		return Manifest{}, fmt.Errorf("backup is of database version %s but %s is expected", manifest.DatabaseVersion, dbVersion)
+	}
+	return manifest, read(dir, manifest, func([]byte, []byte) error { return nil })
+}
+
+// Load writes every key-value pair in the backup in [dir] to [db]. The data is
+// checked against the backup's manifest while it's loaded, but some of it may
+// have been written to [db] by the time a mismatch is found, so the backup
+// should be verified before it's loaded into a database that is in use.
+func Load(dir string, db database.Batcher) (Manifest, error) {
+	manifest, err := ReadManifest(dir)
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	batch := db.NewBatch()
+	err = read(dir, manifest, func(key, value []byte) error {
+		if err := batch.Put(key, value); err != nil {
+			return err
+		}
+		if batch.Size() < loadBatchSize {
+			return nil
+		}
+		if err := batch.Write(); err != nil {
+			return err
+		}
+		batch.Reset()
+		return nil
+	})
+	if err != nil {
+		return Manifest{}, err
+	}
+	return manifest, batch.Write()
+}
+
+// read passes every key-value pair in the data file in [dir] to [f], and
+// checks the data file against [manifest]
+func read(dir string, manifest Manifest, f func(key, value []byte) error) error {
+	file, err := os.Open(filepath.Join(dir, dataFile))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	checksum := sha256.New()
+	r := bufio.NewReader(io.TeeReader(file, checksum))
+	for i := uint64(0); i < manifest.NumKeys; i++ {
+		key, err := readBytes(r)
+		if err != nil {
+			return fmt.Errorf("couldn't read key %d: %w", i, err)
+		}
+		value, err := readBytes(r)
+		if err != nil {
+			return fmt.Errorf("couldn't read value %d: %w", i, err)
+		}
+		if err := f(key, value); err != nil {
+			return err
+		}
+	}
+	switch _, err := r.ReadByte(); err {
+	case io.EOF:
+	case nil:
+		return errTrailingData
+	default:
+		return err
+	}
+	if hex.EncodeToString(checksum.Sum(nil)) != manifest.Checksum {
+		return errChecksumMismatch
+	}
+	return nil
+}
+
+// writeBytes writes [b], prefixed with its length, to [w]
+func writeBytes(w io.Writer, b []byte) error {
+	var length [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(length[:], uint64(len(b)))
+	if _, err := w.Write(length[:n]); err != nil {
+		return err
+	}
+	_, err := w.Write(b)
+	return err
+}
+
+// readBytes reads bytes written by writeBytes from [r]
+func readBytes(r *bufio.Reader) ([]byte, error) {
+	length, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	if length > maxBytesLength {
+		return nil, fmt.Errorf("length %d is too large", length)
+	}
+	b := make([]byte, length)
+	_, err = io.ReadFull(r, b)
+	return b, err
+}
+
+// countingWriter counts the bytes written to [w]
+type countingWriter struct {
+	w io.Writer
+	n uint64
+}
+
+func (c *countingWriter) Write(b []byte) (int, error) {
+	n, err := c.w.Write(b)
+	c.n += uint64(n)
+	return n, err
+}
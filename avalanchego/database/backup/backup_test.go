@@ -0,0 +1,99 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package backup
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/flare-foundation/flare/database"
+	"github.com/flare-foundation/flare/database/memdb"
+	"github.com/flare-foundation/flare/utils/perms"
+	"github.com/flare-foundation/flare/version"
+)
+
+func newTestDB(t *testing.T, numKeys int) database.Database {
+	db := memdb.New()
+	for i := 0; i < numKeys; i++ {
+		assert.NoError(t, db.Put([]byte(fmt.Sprintf("key%d", i)), []byte(fmt.Sprintf("value%d", i))))
+	}
+	// Empty values must be restored as well
+	assert.NoError(t, db.Put([]byte("empty"), nil))
+	return db
+}
+
+func TestCreateAndLoad(t *testing.T) {
+	assert := assert.New(t)
+
+	db := newTestDB(t, 100)
+	dir := filepath.Join(t.TempDir(), "backup")
+
+	manifest, err := Create(db, version.CurrentDatabase, dir)
+	assert.NoError(err)
+	assert.EqualValues(101, manifest.NumKeys)
+	assert.Equal(version.CurrentDatabase.String(), manifest.DatabaseVersion)
+
+	// An existing backup isn't overwritten
+	_, err = Create(db, version.CurrentDatabase, dir)
+	assert.Error(err)
+
+	verified, err := Verify(dir, version.CurrentDatabase)
+	assert.NoError(err)
+	assert.Equal(manifest.Checksum, verified.Checksum)
+
+	restored := memdb.New()
+	_, err = Load(dir, restored)
+	assert.NoError(err)
+
+	it := db.NewIterator()
+	defer it.Release()
+	for it.Next() {
+		value, err := restored.Get(it.Key())
+		assert.NoError(err)
+		assert.True(bytes.Equal(it.Value(), value))
+	}
+	assert.NoError(it.Error())
+	count, err := database.Count(restored)
+	assert.NoError(err)
+	assert.Equal(101, count)
+}
+
+func TestVerifyWrongVersion(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "backup")
+	_, err := Create(newTestDB(t, 1), version.DefaultVersion1_0_0, dir)
+	assert.NoError(t, err)
+
+	_, err = Verify(dir, version.NewDefaultVersion(1, 0, 1))
+	assert.Error(t, err)
+}
+
+func TestVerifyCorrupted(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := filepath.Join(t.TempDir(), "backup")
+	_, err := Create(newTestDB(t, 10), version.CurrentDatabase, dir)
+	assert.NoError(err)
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, dataFile))
+	assert.NoError(err)
+	data[len(data)-1]++
+	assert.NoError(ioutil.WriteFile(filepath.Join(dir, dataFile), data, perms.ReadWrite))
+
+	_, err = Verify(dir, version.CurrentDatabase)
+	assert.ErrorIs(err, errChecksumMismatch)
+
+	assert.NoError(ioutil.WriteFile(filepath.Join(dir, dataFile), append(data, 0), perms.ReadWrite))
+	_, err = Verify(dir, version.CurrentDatabase)
+	assert.ErrorIs(err, errTrailingData)
+}
+
+func TestVerifyIncomplete(t *testing.T) {
+	_, err := Verify(t.TempDir(), version.CurrentDatabase)
+	assert.ErrorIs(t, err, errNoManifest)
+}
@@ -13,6 +13,7 @@ import (
 var (
 	_ database.Database = &Database{}
 	_ database.Batch    = &batch{}
+	_ database.Snapshot = &snapshot{}
 )
 
 // CorruptableDB is a wrapper around Database
@@ -85,6 +86,21 @@ func (db *Database) NewBatch() database.Batch {
 	}
 }
 
+// NewSnapshot returns a snapshot of the current content of the database
+func (db *Database) NewSnapshot() (database.Snapshot, error) {
+	if err := db.corrupted(); err != nil {
+		return nil, err
+	}
+	s, err := db.Database.NewSnapshot()
+	if err != nil {
+		return nil, db.handleError(err)
+	}
+	return &snapshot{
+		Snapshot: s,
+		db:       db,
+	}, nil
+}
+
 func (db *Database) corrupted() error {
 	db.errorLock.RLock()
 	defer db.errorLock.RUnlock()
@@ -123,3 +139,28 @@ func (b *batch) Write() error {
 	}
 	return b.db.handleError(b.Batch.Write())
 }
+
+// snapshot is a wrapper around a snapshot of the underlying database that
+// fails reads once the database is corrupted
+type snapshot struct {
+	database.Snapshot
+	db *Database
+}
+
+// Has returns if the key is set in the snapshot
+func (s *snapshot) Has(key []byte) (bool, error) {
+	if err := s.db.corrupted(); err != nil {
+		return false, err
+	}
+	has, err := s.Snapshot.Has(key)
+	return has, s.db.handleError(err)
+}
+
+// Get returns the value the key maps to in the snapshot
+func (s *snapshot) Get(key []byte) ([]byte, error) {
+	if err := s.db.corrupted(); err != nil {
+		return nil, err
+	}
+	value, err := s.Snapshot.Get(key)
+	return value, s.db.handleError(err)
+}
@@ -71,12 +71,36 @@ type Compacter interface {
 	Compact(start []byte, limit []byte) error
 }
 
+// Snapshot is a read-only view of a backing data store at the time the
+// snapshot was taken. Writes to the data store after that aren't visible
+// through the snapshot, so several reads from one snapshot are consistent with
+// each other.
+//
+// A snapshot must be released after use. A snapshot is safe for concurrent
+// use. Reading from a released snapshot returns [ErrClosed].
+type Snapshot interface {
+	KeyValueReader
+	Iteratee
+
+	// Release releases associated resources. Release should always succeed
+	// and can be called multiple times without causing error.
+	Release()
+}
+
+// Snapshotter wraps the NewSnapshot method of a backing data store.
+type Snapshotter interface {
+	// NewSnapshot returns a snapshot of the current content of the data
+	// store.
+	NewSnapshot() (Snapshot, error)
+}
+
 // Database contains all the methods required to allow handling different
 // key-value data stores backing the database.
 type Database interface {
 	KeyValueReaderWriterDeleter
 	Batcher
 	Iteratee
+	Snapshotter
 	Stater
 	Compacter
 	io.Closer
@@ -6,8 +6,10 @@ package encdb
 import (
 	"crypto/cipher"
 	"crypto/rand"
+	"fmt"
 	"sync"
 
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/chacha20poly1305"
 
 	"github.com/flare-foundation/flare/codec"
@@ -19,37 +21,141 @@ import (
 )
 
 const (
-	codecVersion = 0
+	// LegacyVersion is the format of values that are encrypted with the hash
+	// of the password. Values of this format are only ever read, until they
+	// are re-encrypted by MigrateLegacy.
+	LegacyVersion = 0
+
+	// CurrentVersion is the format of values that are encrypted with a key
+	// derived from the password and a salt using Argon2id. Values are always
+	// written in this format.
+	CurrentVersion = 1
+
+	// SaltLen is the length of the salt that the key is derived with
+	SaltLen = 16
+
+	// Argon2id parameters used to derive the key
+	kdfTime    = 1
+	kdfMemory  = 64 * 1024 // 64 MiB
+	kdfThreads = 4
 )
 
 var (
+	errInvalidSaltLen = fmt.Errorf("salt must be %d bytes", SaltLen)
+
 	_ database.Database = &Database{}
 	_ database.Batch    = &batch{}
 	_ database.Iterator = &iterator{}
+	_ database.Snapshot = &snapshot{}
 )
 
+// Keys decrypt the values of both formats. Deriving them is expensive, so the
+// keys of a user can be derived once and shared by the user's databases.
+type Keys struct {
+	// cipher encrypts and decrypts values of the current format
+	cipher cipher.AEAD
+	// legacyCipher decrypts values of the legacy format
+	legacyCipher cipher.AEAD
+}
+
+// DeriveKeys returns the keys of the values that are encrypted with
+// [password] and [salt], which must be [SaltLen] bytes long
+func DeriveKeys(password, salt []byte) (*Keys, error) {
+	if len(salt) != SaltLen {
+		return nil, errInvalidSaltLen
+	}
+	key := argon2.IDKey(password, salt, kdfTime, kdfMemory, kdfThreads, chacha20poly1305.KeySize)
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, err
+	}
+	legacyKey := hashing.ComputeHash256(password)
+	legacyAEAD, err := chacha20poly1305.NewX(legacyKey)
+	if err != nil {
+		return nil, err
+	}
+	return &Keys{
+		cipher:       aead,
+		legacyCipher: legacyAEAD,
+	}, nil
+}
+
 // Database encrypts all values that are provided
 type Database struct {
-	lock   sync.RWMutex
-	codec  codec.Manager
-	cipher cipher.AEAD
-	db     database.Database
+	lock  sync.RWMutex
+	codec codec.Manager
+	keys  *Keys
+	db    database.Database
 }
 
-// New returns a new encrypted database
-func New(password []byte, db database.Database) (*Database, error) {
-	h := hashing.ComputeHash256(password)
-	aead, err := chacha20poly1305.NewX(h)
+// New returns a new encrypted database. Values are encrypted with a key that is
+// derived from [password] and [salt], which must be [SaltLen] bytes long.
+// Values that were written in the legacy format remain readable.
+func New(password, salt []byte, db database.Database) (*Database, error) {
+	keys, err := DeriveKeys(password, salt)
 	if err != nil {
 		return nil, err
 	}
+	return NewWithKeys(keys, db)
+}
+
+// NewWithKeys returns a new encrypted database whose values are encrypted with
+// [keys]
+func NewWithKeys(keys *Keys, db database.Database) (*Database, error) {
+	// Both formats share the same serialization of encrypted values
 	c := linearcodec.NewDefault()
 	manager := codec.NewDefaultManager()
+	if err := manager.RegisterCodec(LegacyVersion, c); err != nil {
+		return nil, err
+	}
 	return &Database{
-		codec:  manager,
-		cipher: aead,
-		db:     db,
-	}, manager.RegisterCodec(codecVersion, c)
+		codec: manager,
+		keys:  keys,
+		db:    db,
+	}, manager.RegisterCodec(CurrentVersion, c)
+}
+
+// MigrateLegacy re-encrypts the values of the legacy format in the current
+// format, and returns the number of values it re-encrypted
+func (db *Database) MigrateLegacy() (int, error) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.db == nil {
+		return 0, database.ErrClosed
+	}
+
+	batch := db.db.NewBatch()
+	it := db.db.NewIterator()
+	defer it.Release()
+
+	migrated := 0
+	for it.Next() {
+		encVal := it.Value()
+		version, err := db.codec.Unmarshal(encVal, &encryptedValue{})
+		if err != nil {
+			return 0, err
+		}
+		if version != LegacyVersion {
+			continue
+		}
+		val, err := db.decrypt(encVal)
+		if err != nil {
+			return 0, err
+		}
+		newEncVal, err := db.encrypt(val)
+		if err != nil {
+			return 0, err
+		}
+		if err := batch.Put(it.Key(), newEncVal); err != nil {
+			return 0, err
+		}
+		migrated++
+	}
+	if err := it.Error(); err != nil {
+		return 0, err
+	}
+	return migrated, batch.Write()
 }
 
 // Has implements the Database interface
@@ -142,6 +248,24 @@ func (db *Database) NewIteratorWithStartAndPrefix(start, prefix []byte) database
 	}
 }
 
+// NewSnapshot implements the Database interface
+func (db *Database) NewSnapshot() (database.Snapshot, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return nil, database.ErrClosed
+	}
+	s, err := db.db.NewSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot{
+		Snapshot: s,
+		db:       db,
+	}, nil
+}
+
 // Stat implements the Database interface
 func (db *Database) Stat(stat string) (string, error) {
 	db.lock.RLock()
@@ -245,6 +369,44 @@ func (b *batch) Replay(w database.KeyValueWriterDeleter) error {
 	return nil
 }
 
+// snapshot decrypts the values read from a snapshot of the underlying database
+type snapshot struct {
+	database.Snapshot
+	db *Database
+}
+
+// Get implements the Snapshot interface
+func (s *snapshot) Get(key []byte) ([]byte, error) {
+	encVal, err := s.Snapshot.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	return s.db.decrypt(encVal)
+}
+
+// NewIterator implements the Snapshot interface
+func (s *snapshot) NewIterator() database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(nil, nil)
+}
+
+// NewIteratorWithStart implements the Snapshot interface
+func (s *snapshot) NewIteratorWithStart(start []byte) database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(start, nil)
+}
+
+// NewIteratorWithPrefix implements the Snapshot interface
+func (s *snapshot) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(nil, prefix)
+}
+
+// NewIteratorWithStartAndPrefix implements the Snapshot interface
+func (s *snapshot) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	return &iterator{
+		Iterator: s.Snapshot.NewIteratorWithStartAndPrefix(start, prefix),
+		db:       s.db,
+	}
+}
+
 type iterator struct {
 	database.Iterator
 	db *Database
@@ -300,8 +462,8 @@ func (db *Database) encrypt(plaintext []byte) ([]byte, error) {
 	if _, err := rand.Read(nonce); err != nil {
 		return nil, err
 	}
-	ciphertext := db.cipher.Seal(nil, nonce, plaintext, nil)
-	return db.codec.Marshal(codecVersion, &encryptedValue{
+	ciphertext := db.keys.cipher.Seal(nil, nonce, plaintext, nil)
+	return db.codec.Marshal(CurrentVersion, &encryptedValue{
 		Ciphertext: ciphertext,
 		Nonce:      nonce,
 	})
@@ -309,8 +471,13 @@ func (db *Database) encrypt(plaintext []byte) ([]byte, error) {
 
 func (db *Database) decrypt(ciphertext []byte) ([]byte, error) {
 	val := encryptedValue{}
-	if _, err := db.codec.Unmarshal(ciphertext, &val); err != nil {
+	version, err := db.codec.Unmarshal(ciphertext, &val)
+	if err != nil {
 		return nil, err
 	}
-	return db.cipher.Open(nil, val.Nonce, val.Ciphertext, nil)
+	// The codec rejects values of unknown versions
+	if version == LegacyVersion {
+		return db.keys.legacyCipher.Open(nil, val.Nonce, val.Ciphertext, nil)
+	}
+	return db.keys.cipher.Open(nil, val.Nonce, val.Ciphertext, nil)
 }
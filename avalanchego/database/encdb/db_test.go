@@ -4,17 +4,23 @@
 package encdb
 
 import (
+	"bytes"
+	"crypto/rand"
 	"testing"
 
+	"golang.org/x/crypto/chacha20poly1305"
+
 	"github.com/flare-foundation/flare/database"
 	"github.com/flare-foundation/flare/database/memdb"
+	"github.com/flare-foundation/flare/utils/hashing"
 )
 
 func TestInterface(t *testing.T) {
 	pw := "lol totally a secure password" // #nosec G101
+	salt := make([]byte, SaltLen)
 	for _, test := range database.Tests {
 		unencryptedDB := memdb.New()
-		db, err := New([]byte(pw), unencryptedDB)
+		db, err := New([]byte(pw), salt, unencryptedDB)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -23,13 +29,145 @@ func TestInterface(t *testing.T) {
 	}
 }
 
+func TestInvalidSalt(t *testing.T) {
+	pw := "lol totally a secure password" // #nosec G101
+	if _, err := New([]byte(pw), make([]byte, SaltLen-1), memdb.New()); err == nil {
+		t.Fatal("should have errored due to the short salt")
+	}
+}
+
+func TestLegacyFormat(t *testing.T) {
+	pw := "lol totally a secure password" // #nosec G101
+	salt := make([]byte, SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		t.Fatal(err)
+	}
+	unencryptedDB := memdb.New()
+	db, err := New([]byte(pw), salt, unencryptedDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Write a value the way it was written before the key was derived with a
+	// salt
+	key := []byte("hello")
+	value := []byte("world")
+	aead, err := chacha20poly1305.NewX(hashing.ComputeHash256([]byte(pw)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonce := make([]byte, chacha20poly1305.NonceSizeX)
+	encValue, err := db.codec.Marshal(LegacyVersion, &encryptedValue{
+		Ciphertext: aead.Seal(nil, nonce, value, nil),
+		Nonce:      nonce,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := unencryptedDB.Put(key, encValue); err != nil {
+		t.Fatal(err)
+	}
+
+	if readValue, err := db.Get(key); err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(value, readValue) {
+		t.Fatalf("db.Get Returned: 0x%x ; Expected: 0x%x", readValue, value)
+	}
+
+	// Values are rewritten in the current format
+	if err := db.Put(key, value); err != nil {
+		t.Fatal(err)
+	}
+	encValue, err = unencryptedDB.Get(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version, err := db.codec.Unmarshal(encValue, &encryptedValue{}); err != nil {
+		t.Fatal(err)
+	} else if version != CurrentVersion {
+		t.Fatalf("value was written with version %d ; Expected: %d", version, CurrentVersion)
+	}
+
+	// A different salt derives a different key
+	otherSalt := make([]byte, SaltLen)
+	otherDB, err := New([]byte(pw), otherSalt, unencryptedDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := otherDB.Get(key); err == nil {
+		t.Fatal("should have failed to decrypt with a different salt")
+	}
+}
+
+func TestMigrateLegacy(t *testing.T) {
+	pw := "lol totally a secure password" // #nosec G101
+	salt := make([]byte, SaltLen)
+	unencryptedDB := memdb.New()
+	keys, err := DeriveKeys([]byte(pw), salt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := NewWithKeys(keys, unencryptedDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.Put([]byte("current"), []byte("value")); err != nil {
+		t.Fatal(err)
+	}
+	aead, err := chacha20poly1305.NewX(hashing.ComputeHash256([]byte(pw)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonce := make([]byte, chacha20poly1305.NonceSizeX)
+	encValue, err := db.codec.Marshal(LegacyVersion, &encryptedValue{
+		Ciphertext: aead.Seal(nil, nonce, []byte("world"), nil),
+		Nonce:      nonce,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := unencryptedDB.Put([]byte("hello"), encValue); err != nil {
+		t.Fatal(err)
+	}
+
+	if migrated, err := db.MigrateLegacy(); err != nil {
+		t.Fatal(err)
+	} else if migrated != 1 {
+		t.Fatalf("migrated %d values ; Expected: 1", migrated)
+	}
+	for key, value := range map[string]string{"current": "value", "hello": "world"} {
+		encValue, err := unencryptedDB.Get([]byte(key))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if version, err := db.codec.Unmarshal(encValue, &encryptedValue{}); err != nil {
+			t.Fatal(err)
+		} else if version != CurrentVersion {
+			t.Fatalf("value was written with version %d ; Expected: %d", version, CurrentVersion)
+		}
+		if readValue, err := db.Get([]byte(key)); err != nil {
+			t.Fatal(err)
+		} else if !bytes.Equal([]byte(value), readValue) {
+			t.Fatalf("db.Get Returned: 0x%x ; Expected: 0x%x", readValue, value)
+		}
+	}
+
+	if migrated, err := db.MigrateLegacy(); err != nil {
+		t.Fatal(err)
+	} else if migrated != 0 {
+		t.Fatalf("migrated %d values ; Expected: 0", migrated)
+	}
+}
+
 func BenchmarkInterface(b *testing.B) {
 	pw := "lol totally a secure password" // #nosec G101
+	salt := make([]byte, SaltLen)
 	for _, size := range database.BenchmarkSizes {
 		keys, values := database.SetupBenchmark(b, size[0], size[1], size[2])
 		for _, bench := range database.Benchmarks {
 			unencryptedDB := memdb.New()
-			db, err := New([]byte(pw), unencryptedDB)
+			db, err := New([]byte(pw), salt, unencryptedDB)
 			if err != nil {
 				b.Fatal(err)
 			}
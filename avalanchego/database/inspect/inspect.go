@@ -0,0 +1,150 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+// Package inspect attributes the keys of a database to the partitions that
+// prefixdb creates in it.
+//
+// prefixdb prefixes keys with the hash of the prefix, so the partition of a key
+// can't be read from the key itself. Instead, the partitions are described up
+// front, in the same way as they're created, and the hashed prefixes of the
+// keys are matched against them.
+package inspect
+
+import (
+	"sort"
+
+	"github.com/flare-foundation/flare/database"
+	"github.com/flare-foundation/flare/utils/hashing"
+)
+
+// Name of the keys that aren't in any described partition
+const Other = "other"
+
+// Partition is a part of a database whose keys start with the same hashed
+// prefix.
+type Partition struct {
+	name string
+	// Hashed prefix of the keys in this partition. It's nil for the whole
+	// database.
+	prefix []byte
+	// Partitions whose prefixes are at the same position as [prefix]
+	siblings map[string]*Partition
+	// Partitions whose prefixes follow [prefix]
+	nested map[string]*Partition
+	// Splits the keys of this partition that aren't in a nested partition
+	// into categories
+	categorize func(key []byte) string
+}
+
+// New returns the partition that spans the whole database
+func New() *Partition {
+	return &Partition{}
+}
+
+// Prefix returns the partition created by calling prefixdb.New with [prefix]
+// on the prefixdb of [p]. prefixdb combines the two prefixes into one.
+func (p *Partition) Prefix(name string, prefix []byte) *Partition {
+	if p.prefix == nil {
+		return p.Nested(name, prefix)
+	}
+	combined := make([]byte, len(p.prefix)+len(prefix))
+	copy(combined, p.prefix)
+	copy(combined[len(p.prefix):], prefix)
+
+	child := &Partition{
+		name:     p.name + "/" + name,
+		prefix:   hashing.ComputeHash256(combined),
+		siblings: p.siblings,
+	}
+	p.siblings[string(child.prefix)] = child
+	return child
+}
+
+// Nested returns the partition created by calling prefixdb.NewNested with
+// [prefix] on [p], or by calling prefixdb.New on a database that wraps [p]
+// without being a prefixdb, such as a versiondb.
+func (p *Partition) Nested(name string, prefix []byte) *Partition {
+	if p.nested == nil {
+		p.nested = make(map[string]*Partition)
+	}
+	if p.prefix != nil {
+		name = p.name + "/" + name
+	}
+	child := &Partition{
+		name:     name,
+		prefix:   hashing.ComputeHash256(prefix),
+		siblings: p.nested,
+	}
+	p.nested[string(child.prefix)] = child
+	return child
+}
+
+// Categorize splits the keys of [p] that aren't in any partition nested in it
+// with [f]. [f] is passed the keys without the prefix of [p].
+func (p *Partition) Categorize(f func(key []byte) string) {
+	p.categorize = f
+}
+
+// Locate returns the name of the partition [key] of the database that [p]
+// spans is in. Keys with prefixes that weren't described are attributed to
+// the closest partition that encloses them.
+func (p *Partition) Locate(key []byte) string {
+	current := p
+	offset := 0
+	for len(key) >= offset+hashing.HashLen {
+		child, ok := current.nested[string(key[offset:offset+hashing.HashLen])]
+		if !ok {
+			break
+		}
+		current = child
+		offset += hashing.HashLen
+	}
+	switch {
+	case current.categorize != nil:
+		return current.name + "/" + current.categorize(key[offset:])
+	case current.prefix == nil:
+		return Other
+	default:
+		return current.name
+	}
+}
+
+// Usage is the space used by the keys of a partition
+type Usage struct {
+	Name    string `json:"name"`
+	NumKeys uint64 `json:"numKeys"`
+	// Number of bytes in the keys and values
+	Size uint64 `json:"size"`
+}
+
+// Inspect returns the usage of every partition of [db], which [root] spans,
+// that has keys. The usages are sorted by name.
+func Inspect(db database.Iteratee, root *Partition) ([]Usage, error) {
+	usages := make(map[string]*Usage)
+
+	it := db.NewIterator()
+	defer it.Release()
+	for it.Next() {
+		key := it.Key()
+		name := root.Locate(key)
+		usage, ok := usages[name]
+		if !ok {
+			usage = &Usage{Name: name}
+			usages[name] = usage
+		}
+		usage.NumKeys++
+		usage.Size += uint64(len(key) + len(it.Value()))
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	sorted := make([]Usage, 0, len(usages))
+	for _, usage := range usages {
+		sorted = append(sorted, *usage)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted, nil
+}
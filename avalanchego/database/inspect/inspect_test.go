@@ -0,0 +1,59 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package inspect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/flare-foundation/flare/database/memdb"
+	"github.com/flare-foundation/flare/database/prefixdb"
+	"github.com/flare-foundation/flare/database/versiondb"
+)
+
+func TestInspect(t *testing.T) {
+	assert := assert.New(t)
+
+	db := memdb.New()
+	chainDB := prefixdb.New([]byte("chain"), db)
+	vmDB := prefixdb.New([]byte("vm"), chainDB)
+	vdb := versiondb.New(vmDB)
+	stateDB := prefixdb.New([]byte("state"), vdb)
+	ethDB := prefixdb.NewNested([]byte("eth"), vmDB)
+	unknownDB := prefixdb.New([]byte("unknown"), stateDB)
+
+	assert.NoError(db.Put([]byte("root"), []byte("value")))
+	assert.NoError(vmDB.Put([]byte("vm"), []byte("value")))
+	assert.NoError(stateDB.Put([]byte("state1"), []byte("value")))
+	assert.NoError(stateDB.Put([]byte("state2"), []byte("value")))
+	assert.NoError(ethDB.Put([]byte("a1"), []byte("value")))
+	assert.NoError(ethDB.Put([]byte("b1"), []byte("value")))
+	assert.NoError(unknownDB.Put([]byte("unknown"), []byte("value")))
+	assert.NoError(vdb.Commit())
+
+	root := New()
+	chain := root.Nested("chain", []byte("chain"))
+	vm := chain.Prefix("vm", []byte("vm"))
+	vm.Nested("state", []byte("state"))
+	eth := vm.Nested("eth", []byte("eth"))
+	eth.Categorize(func(key []byte) string {
+		return string(key[:1])
+	})
+
+	usages, err := Inspect(db, root)
+	assert.NoError(err)
+
+	numKeys := make(map[string]uint64)
+	for _, usage := range usages {
+		numKeys[usage.Name] = usage.NumKeys
+	}
+	assert.Equal(map[string]uint64{
+		Other:            1,
+		"chain/vm":       2, // The key of the undescribed partition is in it
+		"chain/vm/state": 2,
+		"chain/vm/eth/a": 1,
+		"chain/vm/eth/b": 1,
+	}, numKeys)
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/flare-foundation/flare/database/leveldb"
 	"github.com/flare-foundation/flare/database/memdb"
 	"github.com/flare-foundation/flare/database/meterdb"
+	"github.com/flare-foundation/flare/database/pebbledb"
 	"github.com/flare-foundation/flare/database/prefixdb"
 	"github.com/flare-foundation/flare/database/rocksdb"
 	"github.com/flare-foundation/flare/utils"
@@ -112,6 +113,25 @@ func NewLevelDB(
 	)
 }
 
+// NewPebbleDB creates a database manager of pebbleDBs at [filePath] by creating
+// a database instance from each directory with a version <= [currentVersion].
+// If [includePreviousVersions], opens previous database versions and includes
+// them in the returned Manager.
+func NewPebbleDB(
+	dbDirPath string,
+	dbConfig []byte,
+	log logging.Logger,
+	currentVersion version.Version,
+) (Manager, error) {
+	return new(
+		pebbledb.New,
+		dbDirPath,
+		dbConfig,
+		log,
+		currentVersion,
+	)
+}
+
 // new creates a database manager at [filePath] by creating a database instance
 // from each directory with a version <= [currentVersion]. If
 // [includePreviousVersions], opens previous database versions and includes them
@@ -0,0 +1,123 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package manager
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/flare-foundation/flare/database"
+	"github.com/flare-foundation/flare/utils/logging"
+	"github.com/flare-foundation/flare/utils/units"
+	"github.com/flare-foundation/flare/utils/wrappers"
+	"github.com/flare-foundation/flare/version"
+)
+
+const (
+	// Number of bytes written at once while migrating
+	migrationBatchSize = 4 * units.MiB
+
+	// Suffix of the directory a database is migrated into before it's
+	// complete
+	migrationDirSuffix = ".migrating"
+)
+
+// Migrate copies the database of [currentVersion] in [srcDirPath], opened with
+// [newSrcDB], into a new database in [dstDirPath], created with [newDstDB].
+// Returns true if the database was migrated. Nothing is done if there is no
+// database to migrate, or if the new database already exists.
+// The new database is only moved into place once it's complete, so an
+// interrupted migration is started over the next time Migrate is called.
+func Migrate(
+	newSrcDB func(string, []byte, logging.Logger) (database.Database, error),
+	srcDirPath string,
+	newDstDB func(string, []byte, logging.Logger) (database.Database, error),
+	dstDirPath string,
+	dstConfig []byte,
+	log logging.Logger,
+	currentVersion version.Version,
+) (bool, error) {
+	dstDBPath := filepath.Join(dstDirPath, currentVersion.String())
+	if exists, err := pathExists(dstDBPath); err != nil || exists {
+		return false, err
+	}
+	srcDBPath := filepath.Join(srcDirPath, currentVersion.String())
+	if exists, err := pathExists(srcDBPath); err != nil || !exists {
+		return false, err
+	}
+
+	// Remove whatever an earlier, interrupted, migration left behind
+	tmpDBPath := dstDBPath + migrationDirSuffix
+	if err := os.RemoveAll(tmpDBPath); err != nil {
+		return false, err
+	}
+
+	srcDB, err := newSrcDB(srcDBPath, nil, log)
+	if err != nil {
+		return false, fmt.Errorf("couldn't open db at %s: %w", srcDBPath, err)
+	}
+	dstDB, err := newDstDB(tmpDBPath, dstConfig, log)
+	if err != nil {
+		_ = srcDB.Close()
+		return false, fmt.Errorf("couldn't create db at %s: %w", tmpDBPath, err)
+	}
+
+	log.Info("migrating the database at %s to %s", srcDBPath, dstDBPath)
+	numKeys, err := copyDB(dstDB, srcDB)
+	errs := wrappers.Errs{}
+	errs.Add(
+		err,
+		srcDB.Close(),
+		dstDB.Close(),
+	)
+	if errs.Errored() {
+		return false, errs.Err
+	}
+	if err := os.Rename(tmpDBPath, dstDBPath); err != nil {
+		return false, err
+	}
+	log.Info("migrated %d keys to %s", numKeys, dstDBPath)
+	return true, nil
+}
+
+// copyDB writes every key in [src] to [dst]. Returns the number of keys
+// written.
+func copyDB(dst database.Batcher, src database.Iteratee) (int, error) {
+	it := src.NewIterator()
+	defer it.Release()
+
+	batch := dst.NewBatch()
+	numKeys := 0
+	for it.Next() {
+		if err := batch.Put(it.Key(), it.Value()); err != nil {
+			return numKeys, err
+		}
+		numKeys++
+
+		if batch.Size() < migrationBatchSize {
+			continue
+		}
+		if err := batch.Write(); err != nil {
+			return numKeys, err
+		}
+		batch.Reset()
+	}
+	if err := it.Error(); err != nil {
+		return numKeys, err
+	}
+	return numKeys, batch.Write()
+}
+
+func pathExists(path string) (bool, error) {
+	_, err := os.Stat(path)
+	switch {
+	case err == nil:
+		return true, nil
+	case os.IsNotExist(err):
+		return false, nil
+	default:
+		return false, err
+	}
+}
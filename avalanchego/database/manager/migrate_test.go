@@ -0,0 +1,67 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package manager
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/flare-foundation/flare/database/leveldb"
+	"github.com/flare-foundation/flare/database/pebbledb"
+	"github.com/flare-foundation/flare/utils/logging"
+	"github.com/flare-foundation/flare/version"
+)
+
+func TestMigrateLevelDBToPebbleDB(t *testing.T) {
+	assert := assert.New(t)
+
+	srcDir := t.TempDir()
+	dstDir := filepath.Join(t.TempDir(), pebbledb.Name)
+	v1 := version.DefaultVersion1_0_0
+
+	srcDB, err := leveldb.New(filepath.Join(srcDir, v1.String()), nil, logging.NoLog{})
+	assert.NoError(err)
+	// Write enough to need more than one batch
+	for i := 0; i < 1024; i++ {
+		assert.NoError(srcDB.Put([]byte(fmt.Sprintf("key%d", i)), make([]byte, 8*1024)))
+	}
+	assert.NoError(srcDB.Close())
+
+	migrated, err := Migrate(leveldb.New, srcDir, pebbledb.New, dstDir, nil, logging.NoLog{}, v1)
+	assert.NoError(err)
+	assert.True(migrated)
+
+	// The new database already exists, so it isn't migrated again
+	migrated, err = Migrate(leveldb.New, srcDir, pebbledb.New, dstDir, nil, logging.NoLog{}, v1)
+	assert.NoError(err)
+	assert.False(migrated)
+
+	manager, err := NewPebbleDB(dstDir, nil, logging.NoLog{}, v1)
+	assert.NoError(err)
+	db := manager.Current().Database
+	for i := 0; i < 1024; i++ {
+		value, err := db.Get([]byte(fmt.Sprintf("key%d", i)))
+		assert.NoError(err)
+		assert.Len(value, 8*1024)
+	}
+	assert.NoError(manager.Close())
+}
+
+func TestMigrateNoSourceDB(t *testing.T) {
+	assert := assert.New(t)
+
+	dstDir := t.TempDir()
+	v1 := version.DefaultVersion1_0_0
+
+	migrated, err := Migrate(leveldb.New, t.TempDir(), pebbledb.New, dstDir, nil, logging.NoLog{}, v1)
+	assert.NoError(err)
+	assert.False(migrated)
+
+	exists, err := pathExists(filepath.Join(dstDir, v1.String()))
+	assert.NoError(err)
+	assert.False(exists)
+}
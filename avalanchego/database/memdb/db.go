@@ -25,6 +25,7 @@ var (
 	_ database.Database = &Database{}
 	_ database.Batch    = &batch{}
 	_ database.Iterator = &iterator{}
+	_ database.Snapshot = &snapshot{}
 )
 
 // Database is an ephemeral key-value store that implements the Database
@@ -157,6 +158,24 @@ func (db *Database) NewIteratorWithStartAndPrefix(start, prefix []byte) database
 	}
 }
 
+// NewSnapshot implements the Database interface
+func (db *Database) NewSnapshot() (database.Snapshot, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return nil, database.ErrClosed
+	}
+
+	// Values are replaced rather than modified, so they can be shared with the
+	// snapshot
+	copied := NewWithSize(len(db.db))
+	for key, value := range db.db {
+		copied.db[key] = value
+	}
+	return &snapshot{db: copied}, nil
+}
+
 // Stat implements the Database interface
 func (db *Database) Stat(property string) (string, error) { return "", database.ErrNotFound }
 
@@ -245,6 +264,38 @@ func (b *batch) Replay(w database.KeyValueWriterDeleter) error {
 // Inner returns itself
 func (b *batch) Inner() database.Batch { return b }
 
+// snapshot is a copy of the database that is closed when it's released
+type snapshot struct {
+	db *Database
+}
+
+// Has implements the Snapshot interface
+func (s *snapshot) Has(key []byte) (bool, error) { return s.db.Has(key) }
+
+// Get implements the Snapshot interface
+func (s *snapshot) Get(key []byte) ([]byte, error) { return s.db.Get(key) }
+
+// NewIterator implements the Snapshot interface
+func (s *snapshot) NewIterator() database.Iterator { return s.db.NewIterator() }
+
+// NewIteratorWithStart implements the Snapshot interface
+func (s *snapshot) NewIteratorWithStart(start []byte) database.Iterator {
+	return s.db.NewIteratorWithStart(start)
+}
+
+// NewIteratorWithPrefix implements the Snapshot interface
+func (s *snapshot) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return s.db.NewIteratorWithPrefix(prefix)
+}
+
+// NewIteratorWithStartAndPrefix implements the Snapshot interface
+func (s *snapshot) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	return s.db.NewIteratorWithStartAndPrefix(start, prefix)
+}
+
+// Release implements the Snapshot interface
+func (s *snapshot) Release() { _ = s.db.Close() }
+
 type iterator struct {
 	db          *Database
 	initialized bool
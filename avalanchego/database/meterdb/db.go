@@ -14,6 +14,7 @@ var (
 	_ database.Database = &Database{}
 	_ database.Batch    = &batch{}
 	_ database.Iterator = &iterator{}
+	_ database.Snapshot = &snapshot{}
 )
 
 // Database tracks the amount of time each operation takes and how many bytes
@@ -111,6 +112,20 @@ func (db *Database) NewIteratorWithStartAndPrefix(
 	return it
 }
 
+func (db *Database) NewSnapshot() (database.Snapshot, error) {
+	start := db.clock.Time()
+	s, err := db.db.NewSnapshot()
+	end := db.clock.Time()
+	db.newSnapshot.Observe(float64(end.Sub(start)))
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot{
+		snapshot: s,
+		db:       db,
+	}, nil
+}
+
 func (db *Database) Stat(stat string) (string, error) {
 	start := db.clock.Time()
 	result, err := db.db.Stat(stat)
@@ -200,6 +215,64 @@ func (b *batch) Inner() database.Batch {
 	return inner
 }
 
+type snapshot struct {
+	snapshot database.Snapshot
+	db       *Database
+}
+
+func (s *snapshot) Has(key []byte) (bool, error) {
+	start := s.db.clock.Time()
+	has, err := s.snapshot.Has(key)
+	end := s.db.clock.Time()
+	s.db.readSize.Observe(float64(len(key)))
+	s.db.sHas.Observe(float64(end.Sub(start)))
+	s.db.sHasSize.Observe(float64(len(key)))
+	return has, err
+}
+
+func (s *snapshot) Get(key []byte) ([]byte, error) {
+	start := s.db.clock.Time()
+	value, err := s.snapshot.Get(key)
+	end := s.db.clock.Time()
+	s.db.readSize.Observe(float64(len(key) + len(value)))
+	s.db.sGet.Observe(float64(end.Sub(start)))
+	s.db.sGetSize.Observe(float64(len(key) + len(value)))
+	return value, err
+}
+
+func (s *snapshot) NewIterator() database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(nil, nil)
+}
+
+func (s *snapshot) NewIteratorWithStart(start []byte) database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(start, nil)
+}
+
+func (s *snapshot) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(nil, prefix)
+}
+
+func (s *snapshot) NewIteratorWithStartAndPrefix(
+	start,
+	prefix []byte,
+) database.Iterator {
+	startTime := s.db.clock.Time()
+	it := &iterator{
+		iterator: s.snapshot.NewIteratorWithStartAndPrefix(start, prefix),
+		db:       s.db,
+	}
+	end := s.db.clock.Time()
+	s.db.newIterator.Observe(float64(end.Sub(startTime)))
+	return it
+}
+
+func (s *snapshot) Release() {
+	start := s.db.clock.Time()
+	s.snapshot.Release()
+	end := s.db.clock.Time()
+	s.db.sRelease.Observe(float64(end.Sub(start)))
+}
+
 type iterator struct {
 	iterator database.Iterator
 	db       *Database
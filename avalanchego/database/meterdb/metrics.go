@@ -45,6 +45,7 @@ type metrics struct {
 	deleteSize,
 	newBatch,
 	newIterator,
+	newSnapshot,
 	stat,
 	compact,
 	close,
@@ -63,7 +64,12 @@ type metrics struct {
 	iError,
 	iKey,
 	iValue,
-	iRelease metric.Averager
+	iRelease,
+	sHas,
+	sHasSize,
+	sGet,
+	sGetSize,
+	sRelease metric.Averager
 }
 
 func (m *metrics) Initialize(
@@ -83,6 +89,7 @@ func (m *metrics) Initialize(
 	m.deleteSize = newSizeMetric(namespace, "delete", reg, &errs)
 	m.newBatch = newTimeMetric(namespace, "new_batch", reg, &errs)
 	m.newIterator = newTimeMetric(namespace, "new_iterator", reg, &errs)
+	m.newSnapshot = newTimeMetric(namespace, "new_snapshot", reg, &errs)
 	m.stat = newTimeMetric(namespace, "stat", reg, &errs)
 	m.compact = newTimeMetric(namespace, "compact", reg, &errs)
 	m.close = newTimeMetric(namespace, "close", reg, &errs)
@@ -102,5 +109,10 @@ func (m *metrics) Initialize(
 	m.iKey = newTimeMetric(namespace, "iterator_key", reg, &errs)
 	m.iValue = newTimeMetric(namespace, "iterator_value", reg, &errs)
 	m.iRelease = newTimeMetric(namespace, "iterator_release", reg, &errs)
+	m.sHas = newTimeMetric(namespace, "snapshot_has", reg, &errs)
+	m.sHasSize = newSizeMetric(namespace, "snapshot_has", reg, &errs)
+	m.sGet = newTimeMetric(namespace, "snapshot_get", reg, &errs)
+	m.sGetSize = newSizeMetric(namespace, "snapshot_get", reg, &errs)
+	m.sRelease = newTimeMetric(namespace, "snapshot_release", reg, &errs)
 	return errs.Err
 }
@@ -34,6 +34,7 @@ type Database struct {
 	OnNewIteratorWithStart          func([]byte) database.Iterator
 	OnNewIteratorWithPrefix         func([]byte) database.Iterator
 	OnNewIteratorWithStartAndPrefix func([]byte, []byte) database.Iterator
+	OnNewSnapshot                   func() (database.Snapshot, error)
 	OnStat                          func(string) (string, error)
 	OnCompact                       func([]byte, []byte) error
 	OnClose                         func() error
@@ -111,6 +112,14 @@ func (db *Database) NewIteratorWithStartAndPrefix(start, prefix []byte) database
 	return db.OnNewIteratorWithStartAndPrefix(start, prefix)
 }
 
+// NewSnapshot implements the database.Database interface
+func (db *Database) NewSnapshot() (database.Snapshot, error) {
+	if db.OnNewSnapshot == nil {
+		return nil, errNoFunction
+	}
+	return db.OnNewSnapshot()
+}
+
 // Stat implements the database.Database interface
 func (db *Database) Stat(stat string) (string, error) {
 	if db.OnStat == nil {
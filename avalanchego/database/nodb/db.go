@@ -45,6 +45,9 @@ func (db *Database) NewIteratorWithStartAndPrefix(start, prefix []byte) database
 	return &Iterator{}
 }
 
+// NewSnapshot returns an error
+func (*Database) NewSnapshot() (database.Snapshot, error) { return nil, database.ErrClosed }
+
 // Stat returns an error
 func (*Database) Stat(string) (string, error) { return "", database.ErrClosed }
 
@@ -0,0 +1,649 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package pebbledb
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/cockroachdb/pebble/bloom"
+
+	"github.com/flare-foundation/flare/database"
+	"github.com/flare-foundation/flare/database/nodb"
+	"github.com/flare-foundation/flare/utils"
+	"github.com/flare-foundation/flare/utils/logging"
+	"github.com/flare-foundation/flare/utils/units"
+)
+
+const (
+	// Name is the name of this database for database switches
+	Name = "pebbledb"
+
+	// CacheSize is the number of bytes to use for block caching in pebble.
+	CacheSize = 512 * units.MiB
+
+	// BytesPerSync is the number of bytes written to sstables between
+	// background syncs.
+	BytesPerSync = 512 * units.KiB
+
+	// MemTableSize is the number of bytes to use for each memtable.
+	MemTableSize = 16 * units.MiB
+
+	// MemTableStopWritesThreshold is the number of queued memtables that
+	// stops writes until they are flushed.
+	MemTableStopWritesThreshold = 8
+
+	// HandleCap is the number of files descriptors to cap pebble to use.
+	HandleCap = 4096
+
+	// MaxConcurrentCompactions is the number of compactions that may run at
+	// once.
+	MaxConcurrentCompactions = 1
+
+	// BitsPerKey is the number of bits to add to the bloom filter per key.
+	BitsPerKey = 10
+
+	// pebbleByteOverhead is the number of bytes of constant overhead that
+	// should be added to a batch size per operation.
+	pebbleByteOverhead = 8
+)
+
+var (
+	_ database.Database = &Database{}
+	_ database.Batch    = &batch{}
+	_ database.Iterator = &iter{}
+	_ database.Snapshot = &snapshot{}
+)
+
+// Database is a persistent key-value store. Apart from basic data storage
+// functionality it also supports batch writes and iterating over the keyspace
+// in binary-alphabetical order.
+type Database struct {
+	// lock is held in write mode while the database is closed, and while
+	// [openIterators] or [openSnapshots] is modified
+	lock          sync.RWMutex
+	db            *pebble.DB
+	closed        bool
+	openIterators map[*iter]struct{}
+	openSnapshots map[*snapshot]struct{}
+}
+
+type config struct {
+	// CacheSize is the number of bytes used to cache blocks.
+	CacheSize int64 `json:"cacheSize"`
+	// BytesPerSync is the number of bytes written to sstables between
+	// background syncs. 0 disables background syncing.
+	BytesPerSync int `json:"bytesPerSync"`
+	// WALBytesPerSync is the number of bytes written to the write ahead log
+	// between background syncs. 0 disables background syncing.
+	WALBytesPerSync int `json:"walBytesPerSync"`
+	// MemTableSize is the number of bytes used by each memtable.
+	MemTableSize int `json:"memTableSize"`
+	// MemTableStopWritesThreshold is the number of queued memtables that
+	// stops writes until they are flushed.
+	MemTableStopWritesThreshold int `json:"memTableStopWritesThreshold"`
+	// L0CompactionThreshold is the number of level 0 read-amplification
+	// that triggers a level 0 compaction.
+	L0CompactionThreshold int `json:"l0CompactionThreshold"`
+	// L0StopWritesThreshold is the number of level 0 read-amplification
+	// that stops writes until compactions catch up.
+	L0StopWritesThreshold int `json:"l0StopWritesThreshold"`
+	// LBaseMaxBytes is the maximum number of bytes in the base level.
+	LBaseMaxBytes int64 `json:"lBaseMaxBytes"`
+	// MaxOpenFiles is a soft limit on the number of open files.
+	MaxOpenFiles int `json:"maxOpenFiles"`
+	// MaxConcurrentCompactions is the number of compactions that may run at
+	// once.
+	MaxConcurrentCompactions int `json:"maxConcurrentCompactions"`
+	FilterBitsPerKey         int `json:"filterBitsPerKey"`
+}
+
+// New returns a wrapped Pebble object.
+func New(file string, configBytes []byte, log logging.Logger) (database.Database, error) {
+	parsedConfig := config{
+		CacheSize:                   CacheSize,
+		BytesPerSync:                BytesPerSync,
+		MemTableSize:                MemTableSize,
+		MemTableStopWritesThreshold: MemTableStopWritesThreshold,
+		MaxOpenFiles:                HandleCap,
+		MaxConcurrentCompactions:    MaxConcurrentCompactions,
+		FilterBitsPerKey:            BitsPerKey,
+	}
+	if len(configBytes) > 0 {
+		if err := json.Unmarshal(configBytes, &parsedConfig); err != nil {
+			return nil, fmt.Errorf("failed to parse db config: %w", err)
+		}
+	}
+	configJSON, err := json.Marshal(&parsedConfig)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("pebbledb config: %s", string(configJSON))
+
+	cache := pebble.NewCache(parsedConfig.CacheSize)
+	defer cache.Unref()
+
+	opts := &pebble.Options{
+		BytesPerSync:                parsedConfig.BytesPerSync,
+		Cache:                       cache,
+		L0CompactionThreshold:       parsedConfig.L0CompactionThreshold,
+		L0StopWritesThreshold:       parsedConfig.L0StopWritesThreshold,
+		LBaseMaxBytes:               parsedConfig.LBaseMaxBytes,
+		Logger:                      logger{log: log},
+		MaxConcurrentCompactions:    parsedConfig.MaxConcurrentCompactions,
+		MaxOpenFiles:                parsedConfig.MaxOpenFiles,
+		MemTableSize:                parsedConfig.MemTableSize,
+		MemTableStopWritesThreshold: parsedConfig.MemTableStopWritesThreshold,
+		WALBytesPerSync:             parsedConfig.WALBytesPerSync,
+		Levels:                      []pebble.LevelOptions{{FilterPolicy: bloom.FilterPolicy(parsedConfig.FilterBitsPerKey)}},
+	}
+	// Unset fields, and the remaining levels, are filled with pebble's
+	// defaults
+	opts.EnsureDefaults()
+
+	db, err := pebble.Open(file, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Database{
+		db:            db,
+		openIterators: make(map[*iter]struct{}),
+		openSnapshots: make(map[*snapshot]struct{}),
+	}, nil
+}
+
+// Has returns if the key is set in the database
+func (db *Database) Has(key []byte) (bool, error) {
+	_, err := db.Get(key)
+	switch err {
+	case nil:
+		return true, nil
+	case database.ErrNotFound:
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// Get returns the value the key maps to in the database
+func (db *Database) Get(key []byte) ([]byte, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return nil, database.ErrClosed
+	}
+
+	value, closer, err := db.db.Get(key)
+	if err != nil {
+		return nil, updateError(err)
+	}
+	defer closer.Close()
+	return utils.CopyBytes(value), nil
+}
+
+// Put sets the value of the provided key to the provided value
+func (db *Database) Put(key []byte, value []byte) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+	return updateError(db.db.Set(key, value, pebble.Sync))
+}
+
+// Delete removes the key from the database
+func (db *Database) Delete(key []byte) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+	return updateError(db.db.Delete(key, pebble.Sync))
+}
+
+// NewBatch creates a write/delete-only buffer that is atomically committed to
+// the database when write is called
+func (db *Database) NewBatch() database.Batch {
+	return &batch{
+		batch: db.db.NewBatch(),
+		db:    db,
+	}
+}
+
+// NewIterator creates a lexicographically ordered iterator over the database
+func (db *Database) NewIterator() database.Iterator {
+	return db.newIterator(db.db, nil, nil)
+}
+
+// NewIteratorWithStart creates a lexicographically ordered iterator over the
+// database starting at the provided key
+func (db *Database) NewIteratorWithStart(start []byte) database.Iterator {
+	return db.newIterator(db.db, start, nil)
+}
+
+// NewIteratorWithPrefix creates a lexicographically ordered iterator over the
+// database ignoring keys that do not start with the provided prefix
+func (db *Database) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return db.newIterator(db.db, nil, prefix)
+}
+
+// NewIteratorWithStartAndPrefix creates a lexicographically ordered iterator
+// over the database starting at start and ignoring keys that do not start with
+// the provided prefix
+func (db *Database) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	return db.newIterator(db.db, start, prefix)
+}
+
+// newIterator returns an iterator over the keys of [reader], which is the
+// database or one of its snapshots
+func (db *Database) newIterator(reader pebble.Reader, start, prefix []byte) database.Iterator {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.closed {
+		return &nodb.Iterator{Err: database.ErrClosed}
+	}
+	if s, ok := reader.(*pebble.Snapshot); ok && !db.isOpenSnapshot(s) {
+		return &nodb.Iterator{Err: database.ErrClosed}
+	}
+
+	opts := &pebble.IterOptions{
+		LowerBound: prefix,
+		UpperBound: prefixUpperBound(prefix),
+	}
+	if bytes.Compare(start, prefix) == 1 {
+		opts.LowerBound = start
+	}
+	it := &iter{
+		db:   db,
+		iter: reader.NewIter(opts),
+	}
+	db.openIterators[it] = struct{}{}
+	return it
+}
+
+// NewSnapshot returns a snapshot of the current content of the database
+func (db *Database) NewSnapshot() (database.Snapshot, error) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.closed {
+		return nil, database.ErrClosed
+	}
+	s := &snapshot{
+		db:       db,
+		snapshot: db.db.NewSnapshot(),
+	}
+	db.openSnapshots[s] = struct{}{}
+	return s, nil
+}
+
+// isOpenSnapshot returns true if [s] hasn't been released.
+// Assumes [db.lock] is held.
+func (db *Database) isOpenSnapshot(s *pebble.Snapshot) bool {
+	for open := range db.openSnapshots {
+		if open.snapshot == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Stat returns a particular internal stat of the database. The only supported
+// property is "metrics", which describes the state of the LSM tree.
+func (db *Database) Stat(property string) (string, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	switch {
+	case db.closed:
+		return "", database.ErrClosed
+	case property != "metrics":
+		return "", database.ErrNotFound
+	default:
+		return db.db.Metrics().String(), nil
+	}
+}
+
+// Compact the underlying DB for the given key range.
+// Specifically, deleted and overwritten versions are discarded,
+// and the data is rearranged to reduce the cost of operations
+// needed to access the data. This operation should typically only
+// be invoked by users who understand the underlying implementation.
+//
+// A nil start is treated as a key before all keys in the DB.
+// And a nil limit is treated as a key after all keys in the DB.
+// Therefore if both are nil then it will compact entire DB.
+func (db *Database) Compact(start []byte, limit []byte) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+
+	if limit == nil {
+		// Pebble requires an upper bound, so compact up to just past the last
+		// key
+		it := db.db.NewIter(&pebble.IterOptions{LowerBound: start})
+		if it.Last() {
+			limit = append(utils.CopyBytes(it.Key()), 0)
+		}
+		if err := it.Close(); err != nil {
+			return updateError(err)
+		}
+	}
+	if bytes.Compare(start, limit) >= 0 {
+		// There is nothing to compact
+		return nil
+	}
+	return updateError(db.db.Compact(start, limit))
+}
+
+// Close implements the Database interface
+func (db *Database) Close() error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+	db.closed = true
+
+	// Pebble doesn't allow the database to be closed with open iterators
+	for it := range db.openIterators {
+		it.close()
+	}
+	db.openIterators = nil
+	for s := range db.openSnapshots {
+		s.close()
+	}
+	db.openSnapshots = nil
+	return updateError(db.db.Close())
+}
+
+// batch is a wrapper around a pebble batch to contain sizes.
+type batch struct {
+	batch *pebble.Batch
+	db    *Database
+	size  int
+}
+
+// Put the value into the batch for later writing
+func (b *batch) Put(key, value []byte) error {
+	b.size += len(key) + len(value) + pebbleByteOverhead
+	return b.batch.Set(key, value, nil)
+}
+
+// Delete the key during writing
+func (b *batch) Delete(key []byte) error {
+	b.size += len(key) + pebbleByteOverhead
+	return b.batch.Delete(key, nil)
+}
+
+// Size retrieves the amount of data queued up for writing.
+func (b *batch) Size() int { return b.size }
+
+// Write flushes any accumulated data to disk.
+func (b *batch) Write() error {
+	b.db.lock.RLock()
+	defer b.db.lock.RUnlock()
+
+	if b.db.closed {
+		return database.ErrClosed
+	}
+
+	// A committed pebble batch can't be committed again, so the operations are
+	// applied from a copy of it. This allows the batch to be written again,
+	// as the other databases do.
+	committed := b.db.db.NewBatch()
+	if err := committed.SetRepr(utils.CopyBytes(b.batch.Repr())); err != nil {
+		return err
+	}
+	return updateError(committed.Commit(pebble.Sync))
+}
+
+// Reset resets the batch for reuse.
+func (b *batch) Reset() {
+	b.batch.Reset()
+	b.size = 0
+}
+
+// Replay the batch contents.
+func (b *batch) Replay(w database.KeyValueWriterDeleter) error {
+	reader := b.batch.Reader()
+	for {
+		kind, key, value, ok := reader.Next()
+		if !ok {
+			return nil
+		}
+		switch kind {
+		case pebble.InternalKeyKindSet:
+			if err := w.Put(key, value); err != nil {
+				return err
+			}
+		case pebble.InternalKeyKindDelete:
+			if err := w.Delete(key); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Inner returns itself
+func (b *batch) Inner() database.Batch { return b }
+
+// snapshot is a wrapper around a pebble snapshot
+type snapshot struct {
+	db       *Database
+	snapshot *pebble.Snapshot
+
+	// true once [snapshot] has been closed, either because the snapshot was
+	// released or because the database was closed
+	closed bool
+}
+
+// Has returns if the key is set in the snapshot
+func (s *snapshot) Has(key []byte) (bool, error) {
+	_, err := s.Get(key)
+	switch err {
+	case nil:
+		return true, nil
+	case database.ErrNotFound:
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// Get returns the value the key maps to in the snapshot
+func (s *snapshot) Get(key []byte) ([]byte, error) {
+	s.db.lock.RLock()
+	defer s.db.lock.RUnlock()
+
+	if s.closed {
+		return nil, database.ErrClosed
+	}
+
+	value, closer, err := s.snapshot.Get(key)
+	if err != nil {
+		return nil, updateError(err)
+	}
+	defer closer.Close()
+	return utils.CopyBytes(value), nil
+}
+
+// NewIterator creates a lexicographically ordered iterator over the snapshot
+func (s *snapshot) NewIterator() database.Iterator {
+	return s.db.newIterator(s.snapshot, nil, nil)
+}
+
+// NewIteratorWithStart creates a lexicographically ordered iterator over the
+// snapshot starting at the provided key
+func (s *snapshot) NewIteratorWithStart(start []byte) database.Iterator {
+	return s.db.newIterator(s.snapshot, start, nil)
+}
+
+// NewIteratorWithPrefix creates a lexicographically ordered iterator over the
+// snapshot ignoring keys that do not start with the provided prefix
+func (s *snapshot) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return s.db.newIterator(s.snapshot, nil, prefix)
+}
+
+// NewIteratorWithStartAndPrefix creates a lexicographically ordered iterator
+// over the snapshot starting at start and ignoring keys that do not start with
+// the provided prefix
+func (s *snapshot) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	return s.db.newIterator(s.snapshot, start, prefix)
+}
+
+// Release implements the Snapshot interface
+func (s *snapshot) Release() {
+	s.db.lock.Lock()
+	defer s.db.lock.Unlock()
+
+	if s.closed {
+		return
+	}
+	s.close()
+	delete(s.db.openSnapshots, s)
+}
+
+// close closes the underlying pebble snapshot.
+// Assumes [s.db.lock] is held in write mode.
+func (s *snapshot) close() {
+	// Closing a pebble snapshot never fails
+	_ = s.snapshot.Close()
+	s.closed = true
+}
+
+type iter struct {
+	db   *Database
+	iter *pebble.Iterator
+
+	// true once [iter] has been closed, either because the iterator was
+	// released or because the database was closed
+	closed  bool
+	started bool
+
+	key, val []byte
+	err      error
+}
+
+func (it *iter) Next() bool {
+	it.db.lock.RLock()
+	defer it.db.lock.RUnlock()
+
+	switch {
+	case it.db.closed:
+		// Short-circuit and set an error if the underlying database has been
+		// closed.
+		it.key = nil
+		it.val = nil
+		it.err = database.ErrClosed
+		return false
+	case it.closed:
+		it.key = nil
+		it.val = nil
+		return false
+	}
+
+	var hasNext bool
+	if it.started {
+		hasNext = it.iter.Next()
+	} else {
+		hasNext = it.iter.First()
+		it.started = true
+	}
+	if hasNext {
+		it.key = utils.CopyBytes(it.iter.Key())
+		it.val = utils.CopyBytes(it.iter.Value())
+	} else {
+		it.key = nil
+		it.val = nil
+	}
+	return hasNext
+}
+
+// Error implements the Iterator interface
+func (it *iter) Error() error {
+	it.db.lock.RLock()
+	defer it.db.lock.RUnlock()
+
+	if it.err != nil || it.closed {
+		return it.err
+	}
+	return updateError(it.iter.Error())
+}
+
+// Key implements the Iterator interface
+func (it *iter) Key() []byte { return it.key }
+
+// Value implements the Iterator interface
+func (it *iter) Value() []byte { return it.val }
+
+// Release implements the Iterator interface
+func (it *iter) Release() {
+	it.db.lock.Lock()
+	defer it.db.lock.Unlock()
+
+	if it.closed {
+		return
+	}
+	it.close()
+	delete(it.db.openIterators, it)
+}
+
+// close closes the underlying pebble iterator.
+// Assumes [it.db.lock] is held in write mode.
+func (it *iter) close() {
+	if err := it.iter.Close(); err != nil && it.err == nil {
+		it.err = updateError(err)
+	}
+	it.closed = true
+}
+
+// prefixUpperBound returns the smallest key that is larger than every key
+// with [prefix], or nil if there is none.
+func prefixUpperBound(prefix []byte) []byte {
+	upperBound := utils.CopyBytes(prefix)
+	for i := len(upperBound) - 1; i >= 0; i-- {
+		if upperBound[i] != 0xff {
+			upperBound[i]++
+			return upperBound[:i+1]
+		}
+	}
+	return nil
+}
+
+// logger writes pebble's logs to the node's log
+type logger struct {
+	log logging.Logger
+}
+
+func (l logger) Infof(format string, args ...interface{}) {
+	l.log.Debug("pebble: "+format, args...)
+}
+
+func (l logger) Fatalf(format string, args ...interface{}) {
+	l.log.Fatal("pebble: "+format, args...)
+	panic(fmt.Sprintf("pebble: "+format, args...))
+}
+
+func updateError(err error) error {
+	switch err {
+	case pebble.ErrClosed:
+		return database.ErrClosed
+	case pebble.ErrNotFound:
+		return database.ErrNotFound
+	default:
+		return err
+	}
+}
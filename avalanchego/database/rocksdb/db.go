@@ -40,6 +40,7 @@ var (
 	_ database.Database = &Database{}
 	_ database.Batch    = &batch{}
 	_ database.Iterator = &iterator{}
+	_ database.Snapshot = &snapshot{}
 )
 
 // Database is a persistent key-value store. Apart from basic data storage
@@ -248,6 +249,29 @@ func (db *Database) NewIteratorWithStartAndPrefix(start, prefix []byte) database
 	}
 }
 
+// NewSnapshot returns a snapshot of the current content of the database
+func (db *Database) NewSnapshot() (database.Snapshot, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return nil, database.ErrClosed
+	}
+
+	s := db.db.NewSnapshot()
+	readOptions := grocksdb.NewDefaultReadOptions()
+	readOptions.SetSnapshot(s)
+	iteratorOptions := grocksdb.NewDefaultReadOptions()
+	iteratorOptions.SetFillCache(false)
+	iteratorOptions.SetSnapshot(s)
+	return &snapshot{
+		db:              db,
+		snapshot:        s,
+		readOptions:     readOptions,
+		iteratorOptions: iteratorOptions,
+	}, nil
+}
+
 // Stat returns a particular internal stat of the database.
 func (db *Database) Stat(property string) (string, error) {
 	return "", database.ErrNotFound
@@ -362,6 +386,110 @@ func (b *batch) Replay(w database.KeyValueWriterDeleter) error {
 	return nil
 }
 
+// snapshot is a wrapper around a RocksDB snapshot
+type snapshot struct {
+	db              *Database
+	snapshot        *grocksdb.Snapshot
+	readOptions     *grocksdb.ReadOptions
+	iteratorOptions *grocksdb.ReadOptions
+
+	// released is guarded by [db.lock]
+	released bool
+}
+
+// Has returns if the key is set in the snapshot
+func (s *snapshot) Has(key []byte) (bool, error) {
+	_, err := s.Get(key)
+	switch err {
+	case nil:
+		return true, nil
+	case database.ErrNotFound:
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// Get returns the value the key maps to in the snapshot
+func (s *snapshot) Get(key []byte) ([]byte, error) {
+	s.db.lock.RLock()
+	defer s.db.lock.RUnlock()
+
+	if s.db.db == nil || s.released {
+		return nil, database.ErrClosed
+	}
+
+	value, err := s.db.db.GetBytes(s.readOptions, key)
+	if err != nil {
+		return nil, err
+	}
+	if value != nil {
+		return value, nil
+	}
+	return nil, database.ErrNotFound
+}
+
+// NewIterator creates a lexicographically ordered iterator over the snapshot
+func (s *snapshot) NewIterator() database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(nil, nil)
+}
+
+// NewIteratorWithStart creates a lexicographically ordered iterator over the
+// snapshot starting at the provided key
+func (s *snapshot) NewIteratorWithStart(start []byte) database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(start, nil)
+}
+
+// NewIteratorWithPrefix creates a lexicographically ordered iterator over the
+// snapshot ignoring keys that do not start with the provided prefix
+func (s *snapshot) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(nil, prefix)
+}
+
+// NewIteratorWithStartAndPrefix creates a lexicographically ordered iterator
+// over the snapshot starting at start and ignoring keys that do not start with
+// the provided prefix
+func (s *snapshot) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	s.db.lock.RLock()
+	defer s.db.lock.RUnlock()
+
+	if s.db.db == nil || s.released {
+		return &nodb.Iterator{Err: database.ErrClosed}
+	}
+
+	it := s.db.db.NewIterator(s.iteratorOptions)
+	if it == nil {
+		return &nodb.Iterator{Err: errFailedToCreateIterator}
+	}
+	if bytes.Compare(start, prefix) == 1 {
+		it.Seek(start)
+	} else {
+		it.Seek(prefix)
+	}
+	return &iterator{
+		it:     it,
+		db:     s.db,
+		prefix: prefix,
+	}
+}
+
+// Release implements the Snapshot interface
+func (s *snapshot) Release() {
+	s.db.lock.Lock()
+	defer s.db.lock.Unlock()
+
+	if s.released {
+		return
+	}
+	s.released = true
+
+	s.readOptions.Destroy()
+	s.iteratorOptions.Destroy()
+	if s.db.db != nil {
+		s.db.db.ReleaseSnapshot(s.snapshot)
+	}
+}
+
 type iterator struct {
 	it      *grocksdb.Iterator
 	db      *Database
@@ -11,6 +11,7 @@ import (
 
 	"github.com/flare-foundation/flare/api/proto/rpcdbproto"
 	"github.com/flare-foundation/flare/database"
+	"github.com/flare-foundation/flare/database/nodb"
 )
 
 var errUnknownIterator = errors.New("unknown iterator")
@@ -24,6 +25,9 @@ type DatabaseServer struct {
 
 	nextIteratorID uint64
 	iterators      map[uint64]database.Iterator
+
+	nextSnapshotID uint64
+	snapshots      map[uint64]database.Snapshot
 }
 
 // NewServer returns a database instance that is managed remotely
@@ -32,6 +36,7 @@ func NewServer(db database.Database) *DatabaseServer {
 		db:        db,
 		batches:   make(map[int64]database.Batch),
 		iterators: make(map[uint64]database.Iterator),
+		snapshots: make(map[uint64]database.Snapshot),
 	}
 }
 
@@ -84,6 +89,13 @@ func (db *DatabaseServer) Compact(_ context.Context, req *rpcdbproto.CompactRequ
 
 // Close delegates the Close call to the managed database and returns the result
 func (db *DatabaseServer) Close(context.Context, *rpcdbproto.CloseRequest) (*rpcdbproto.CloseResponse, error) {
+	db.lock.Lock()
+	for id, snapshot := range db.snapshots {
+		snapshot.Release()
+		delete(db.snapshots, id)
+	}
+	db.lock.Unlock()
+
 	err := db.db.Close()
 	return &rpcdbproto.CloseResponse{Err: errorToErrCode[err]}, errorToRPCError(err)
 }
@@ -195,3 +207,93 @@ func (db *DatabaseServer) IteratorRelease(_ context.Context, req *rpcdbproto.Ite
 	it.Release()
 	return &rpcdbproto.IteratorReleaseResponse{Err: errorToErrCode[err]}, errorToRPCError(err)
 }
+
+// NewSnapshot allocates a snapshot of the managed database and returns the
+// snapshot ID
+func (db *DatabaseServer) NewSnapshot(context.Context, *rpcdbproto.NewSnapshotRequest) (*rpcdbproto.NewSnapshotResponse, error) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	snapshot, err := db.db.NewSnapshot()
+	if err != nil {
+		return &rpcdbproto.NewSnapshotResponse{Err: errorToErrCode[err]}, errorToRPCError(err)
+	}
+
+	id := db.nextSnapshotID
+	db.snapshots[id] = snapshot
+
+	db.nextSnapshotID++
+	return &rpcdbproto.NewSnapshotResponse{Id: id}, nil
+}
+
+// SnapshotHas delegates the Has call to the requested snapshot and returns the
+// result
+func (db *DatabaseServer) SnapshotHas(_ context.Context, req *rpcdbproto.SnapshotHasRequest) (*rpcdbproto.HasResponse, error) {
+	db.lock.Lock()
+	snapshot, exists := db.snapshots[req.Id]
+	db.lock.Unlock()
+
+	if !exists {
+		// The snapshot has already been released
+		return &rpcdbproto.HasResponse{Err: errorToErrCode[database.ErrClosed]}, nil
+	}
+
+	has, err := snapshot.Has(req.Key)
+	return &rpcdbproto.HasResponse{
+		Has: has,
+		Err: errorToErrCode[err],
+	}, errorToRPCError(err)
+}
+
+// SnapshotGet delegates the Get call to the requested snapshot and returns the
+// result
+func (db *DatabaseServer) SnapshotGet(_ context.Context, req *rpcdbproto.SnapshotGetRequest) (*rpcdbproto.GetResponse, error) {
+	db.lock.Lock()
+	snapshot, exists := db.snapshots[req.Id]
+	db.lock.Unlock()
+
+	if !exists {
+		// The snapshot has already been released
+		return &rpcdbproto.GetResponse{Err: errorToErrCode[database.ErrClosed]}, nil
+	}
+
+	value, err := snapshot.Get(req.Key)
+	return &rpcdbproto.GetResponse{
+		Value: value,
+		Err:   errorToErrCode[err],
+	}, errorToRPCError(err)
+}
+
+// SnapshotNewIteratorWithStartAndPrefix allocates an iterator over the
+// requested snapshot and returns the iterator ID. The iterator is served by the
+// same calls as the iterators over the managed database.
+func (db *DatabaseServer) SnapshotNewIteratorWithStartAndPrefix(_ context.Context, req *rpcdbproto.SnapshotNewIteratorWithStartAndPrefixRequest) (*rpcdbproto.NewIteratorWithStartAndPrefixResponse, error) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	var it database.Iterator
+	if snapshot, exists := db.snapshots[req.Id]; exists {
+		it = snapshot.NewIteratorWithStartAndPrefix(req.Start, req.Prefix)
+	} else {
+		// The snapshot has already been released
+		it = &nodb.Iterator{Err: database.ErrClosed}
+	}
+
+	id := db.nextIteratorID
+	db.iterators[id] = it
+
+	db.nextIteratorID++
+	return &rpcdbproto.NewIteratorWithStartAndPrefixResponse{Id: id}, nil
+}
+
+// SnapshotRelease releases the resources allocated to a snapshot
+func (db *DatabaseServer) SnapshotRelease(_ context.Context, req *rpcdbproto.SnapshotReleaseRequest) (*rpcdbproto.SnapshotReleaseResponse, error) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if snapshot, exists := db.snapshots[req.Id]; exists {
+		delete(db.snapshots, req.Id)
+		snapshot.Release()
+	}
+	return &rpcdbproto.SnapshotReleaseResponse{}, nil
+}
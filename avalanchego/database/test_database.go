@@ -35,6 +35,10 @@ var Tests = []func(t *testing.T, db Database){
 	TestIteratorClosed,
 	TestIteratorError,
 	TestIteratorErrorAfterRelease,
+	TestSnapshot,
+	TestSnapshotIterator,
+	TestSnapshotRelease,
+	TestSnapshotClosed,
 	TestStatNoPanic,
 	TestCompactNoPanic,
 	TestMemorySafetyDatabase,
@@ -1053,6 +1057,152 @@ func TestIteratorErrorAfterRelease(t *testing.T, db Database) {
 	}
 }
 
+// TestSnapshot tests to make sure that writes after a snapshot was taken
+// aren't visible through the snapshot.
+func TestSnapshot(t *testing.T, db Database) {
+	assert := assert.New(t)
+
+	key1 := []byte("hello1")
+	value1 := []byte("world1")
+	value1b := []byte("world1b")
+
+	key2 := []byte("hello2")
+	value2 := []byte("world2")
+
+	key3 := []byte("hello3")
+	value3 := []byte("world3")
+
+	assert.NoError(db.Put(key1, value1))
+	assert.NoError(db.Put(key2, value2))
+
+	snapshot, err := db.NewSnapshot()
+	assert.NoError(err)
+	defer snapshot.Release()
+
+	assert.NoError(db.Put(key1, value1b))
+	assert.NoError(db.Delete(key2))
+	assert.NoError(db.Put(key3, value3))
+
+	value, err := snapshot.Get(key1)
+	assert.NoError(err)
+	assert.Equal(value1, value)
+
+	has, err := snapshot.Has(key2)
+	assert.NoError(err)
+	assert.True(has)
+
+	value, err = snapshot.Get(key2)
+	assert.NoError(err)
+	assert.Equal(value2, value)
+
+	has, err = snapshot.Has(key3)
+	assert.NoError(err)
+	assert.False(has)
+
+	_, err = snapshot.Get(key3)
+	assert.Equal(ErrNotFound, err)
+
+	// The database itself sees the writes
+	value, err = db.Get(key1)
+	assert.NoError(err)
+	assert.Equal(value1b, value)
+}
+
+// TestSnapshotIterator tests to make sure that iterators of a snapshot iterate
+// over the content of the database at the time the snapshot was taken.
+func TestSnapshotIterator(t *testing.T, db Database) {
+	assert := assert.New(t)
+
+	key1 := []byte("hello1")
+	value1 := []byte("world1")
+
+	key2 := []byte("hello2")
+	value2 := []byte("world2")
+
+	key3 := []byte("z")
+	value3 := []byte("world3")
+
+	assert.NoError(db.Put(key1, value1))
+	assert.NoError(db.Put(key3, value3))
+
+	snapshot, err := db.NewSnapshot()
+	assert.NoError(err)
+	defer snapshot.Release()
+
+	assert.NoError(db.Put(key2, value2))
+	assert.NoError(db.Delete(key3))
+
+	iterator := snapshot.NewIterator()
+	assert.True(iterator.Next())
+	assert.Equal(key1, iterator.Key())
+	assert.Equal(value1, iterator.Value())
+	assert.True(iterator.Next())
+	assert.Equal(key3, iterator.Key())
+	assert.Equal(value3, iterator.Value())
+	assert.False(iterator.Next())
+	assert.NoError(iterator.Error())
+	iterator.Release()
+
+	iterator = snapshot.NewIteratorWithStartAndPrefix(key2, []byte("hello"))
+	assert.False(iterator.Next())
+	assert.NoError(iterator.Error())
+	iterator.Release()
+
+	iterator = snapshot.NewIteratorWithPrefix([]byte("z"))
+	assert.True(iterator.Next())
+	assert.Equal(key3, iterator.Key())
+	assert.Equal(value3, iterator.Value())
+	assert.False(iterator.Next())
+	assert.NoError(iterator.Error())
+	iterator.Release()
+
+	iterator = snapshot.NewIteratorWithStart(key2)
+	assert.True(iterator.Next())
+	assert.Equal(key3, iterator.Key())
+	assert.False(iterator.Next())
+	assert.NoError(iterator.Error())
+	iterator.Release()
+}
+
+// TestSnapshotRelease tests to make sure that a released snapshot can't be
+// read from.
+func TestSnapshotRelease(t *testing.T, db Database) {
+	assert := assert.New(t)
+
+	key := []byte("hello1")
+	value := []byte("world1")
+
+	assert.NoError(db.Put(key, value))
+
+	snapshot, err := db.NewSnapshot()
+	assert.NoError(err)
+
+	snapshot.Release()
+	snapshot.Release()
+
+	_, err = snapshot.Get(key)
+	assert.Equal(ErrClosed, err)
+
+	_, err = snapshot.Has(key)
+	assert.Equal(ErrClosed, err)
+
+	iterator := snapshot.NewIterator()
+	assert.False(iterator.Next())
+	assert.Equal(ErrClosed, iterator.Error())
+	iterator.Release()
+}
+
+// TestSnapshotClosed tests to make sure that a snapshot can't be taken of a
+// closed database.
+func TestSnapshotClosed(t *testing.T, db Database) {
+	assert := assert.New(t)
+
+	assert.NoError(db.Close())
+
+	_, err := db.NewSnapshot()
+	assert.Equal(ErrClosed, err)
+}
+
 // TestStatNoPanic tests to make sure that Stat never panics.
 func TestStatNoPanic(t *testing.T, db Database) {
 	key1 := []byte("hello1")
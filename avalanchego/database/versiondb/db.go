@@ -23,6 +23,7 @@ var (
 	_ Commitable        = &Database{}
 	_ database.Batch    = &batch{}
 	_ database.Iterator = &iterator{}
+	_ database.Snapshot = &snapshot{}
 )
 
 // Commitable defines the interface that specifies that something may be
@@ -137,27 +138,30 @@ func (db *Database) NewIteratorWithStartAndPrefix(start, prefix []byte) database
 	if db.mem == nil {
 		return &nodb.Iterator{Err: database.ErrClosed}
 	}
+	return newIterator(db, db.mem, db.db.NewIteratorWithStartAndPrefix(start, prefix), start, prefix)
+}
 
-	startString := string(start)
-	prefixString := string(prefix)
-	keys := make([]string, 0, len(db.mem))
-	for key := range db.mem {
-		if strings.HasPrefix(key, prefixString) && key >= startString {
-			keys = append(keys, key)
-		}
+// NewSnapshot implements the database.Database interface. The snapshot
+// includes the operations that haven't been committed yet.
+func (db *Database) NewSnapshot() (database.Snapshot, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.mem == nil {
+		return nil, database.ErrClosed
 	}
-	sort.Strings(keys) // Keys need to be in sorted order
-	values := make([]valueDelete, len(keys))
-	for i, key := range keys {
-		values[i] = db.mem[key]
+	s, err := db.db.NewSnapshot()
+	if err != nil {
+		return nil, err
 	}
-
-	return &iterator{
-		db:       db,
-		Iterator: db.db.NewIteratorWithStartAndPrefix(start, prefix),
-		keys:     keys,
-		values:   values,
+	mem := make(map[string]valueDelete, len(db.mem))
+	for key, value := range db.mem {
+		mem[key] = value
 	}
+	return &snapshot{
+		mem:      mem,
+		Snapshot: s,
+	}, nil
 }
 
 // Stat implements the database.Database interface
@@ -294,6 +298,90 @@ func (db *Database) isClosed() bool {
 	return db.db == nil
 }
 
+// snapshot of the operations of a database that haven't been committed and of
+// the underlying database
+type snapshot struct {
+	lock sync.RWMutex
+	// nil after the snapshot is released
+	mem map[string]valueDelete
+	database.Snapshot
+}
+
+// Has implements the database.Snapshot interface
+func (s *snapshot) Has(key []byte) (bool, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.mem == nil {
+		return false, database.ErrClosed
+	}
+	if val, has := s.mem[string(key)]; has {
+		return !val.delete, nil
+	}
+	return s.Snapshot.Has(key)
+}
+
+// Get implements the database.Snapshot interface
+func (s *snapshot) Get(key []byte) ([]byte, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.mem == nil {
+		return nil, database.ErrClosed
+	}
+	if val, has := s.mem[string(key)]; has {
+		if val.delete {
+			return nil, database.ErrNotFound
+		}
+		return utils.CopyBytes(val.value), nil
+	}
+	return s.Snapshot.Get(key)
+}
+
+// NewIterator implements the database.Snapshot interface
+func (s *snapshot) NewIterator() database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(nil, nil)
+}
+
+// NewIteratorWithStart implements the database.Snapshot interface
+func (s *snapshot) NewIteratorWithStart(start []byte) database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(start, nil)
+}
+
+// NewIteratorWithPrefix implements the database.Snapshot interface
+func (s *snapshot) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(nil, prefix)
+}
+
+// NewIteratorWithStartAndPrefix implements the database.Snapshot interface
+func (s *snapshot) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.mem == nil {
+		return &nodb.Iterator{Err: database.ErrClosed}
+	}
+	return newIterator(s, s.mem, s.Snapshot.NewIteratorWithStartAndPrefix(start, prefix), start, prefix)
+}
+
+// Release implements the database.Snapshot interface
+func (s *snapshot) Release() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.mem != nil {
+		s.mem = nil
+		s.Snapshot.Release()
+	}
+}
+
+func (s *snapshot) isClosed() bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.mem == nil
+}
+
 type keyValue struct {
 	key    []byte
 	value  []byte
@@ -368,10 +456,15 @@ func (b *batch) Replay(w database.KeyValueWriterDeleter) error {
 // Inner returns itself
 func (b *batch) Inner() database.Batch { return b }
 
+// closable is a database or a snapshot that iterators can be created from
+type closable interface {
+	isClosed() bool
+}
+
 // iterator walks over both the in memory database and the underlying database
 // at the same time.
 type iterator struct {
-	db *Database
+	db closable
 	database.Iterator
 
 	key, value []byte
@@ -383,6 +476,32 @@ type iterator struct {
 	initialized, exhausted bool
 }
 
+// newIterator returns an iterator over the keys in [mem] and [it] that start
+// with [prefix] and are not less than [start]. Must be called while [mem]
+// can't be modified.
+func newIterator(db closable, mem map[string]valueDelete, it database.Iterator, start, prefix []byte) *iterator {
+	startString := string(start)
+	prefixString := string(prefix)
+	keys := make([]string, 0, len(mem))
+	for key := range mem {
+		if strings.HasPrefix(key, prefixString) && key >= startString {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys) // Keys need to be in sorted order
+	values := make([]valueDelete, len(keys))
+	for i, key := range keys {
+		values[i] = mem[key]
+	}
+
+	return &iterator{
+		db:       db,
+		Iterator: it,
+		keys:     keys,
+		values:   values,
+	}
+}
+
 // Next moves the iterator to the next key/value pair. It returns whether the
 // iterator is exhausted. We must pay careful attention to set the proper values
 // based on if the in memory db or the underlying db should be read next
@@ -12,6 +12,7 @@ require (
 	github.com/VictoriaMetrics/fastcache v1.6.0
 	github.com/btcsuite/btcutil v1.0.2
 	github.com/cespare/cp v0.1.0
+	github.com/cockroachdb/pebble v0.0.0-20211202002240-4b595de954b1
 	github.com/davecgh/go-spew v1.1.1
 	github.com/deckarep/golang-set v1.7.1
 	github.com/decred/dcrd/dcrec/secp256k1/v3 v3.0.0-20200627015759-01fd2de07837
@@ -36,6 +37,7 @@ require (
 	github.com/jackpal/gateway v1.0.6
 	github.com/jackpal/go-nat-pmp v1.0.2
 	github.com/kardianos/osext v0.0.0-20190222173326-2bc1f35cddc0
+	github.com/klauspost/compress v1.15.15
 	github.com/linxGnu/grocksdb v1.6.34
 	github.com/mattn/go-colorable v0.1.8
 	github.com/mattn/go-isatty v0.0.12
@@ -86,7 +86,7 @@ func (c *client) GetContainerByIndex(ctx context.Context, args *GetContainer) (C
 func (c *client) GetLastAccepted(ctx context.Context, args *GetLastAcceptedArgs) (Container, error) {
 	var fc FormattedContainer
 	if err := c.requester.SendRequest(ctx, "getLastAccepted", args, &fc); err != nil {
-		return Container{}, nil
+		return Container{}, err
 	}
 	containerBytes, err := formatting.Decode(fc.Encoding, fc.Bytes)
 	if err != nil {
@@ -8,13 +8,34 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/flare-foundation/flare/app/archive"
+	"github.com/flare-foundation/flare/app/dbtool"
+	"github.com/flare-foundation/flare/app/journal"
+	"github.com/flare-foundation/flare/app/restore"
 	"github.com/flare-foundation/flare/app/runner"
+	"github.com/flare-foundation/flare/app/signer"
 	"github.com/flare-foundation/flare/config"
 	"github.com/flare-foundation/flare/version"
 	"github.com/spf13/pflag"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == archive.Command {
+		os.Exit(archive.Run(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == dbtool.Command {
+		os.Exit(dbtool.Run(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == journal.Command {
+		os.Exit(journal.Run(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == restore.Command {
+		os.Exit(restore.Run(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == signer.Command {
+		os.Exit(signer.Run(os.Args[2:]))
+	}
+
 	fs := config.BuildFlagSet()
 	v, err := config.BuildViper(fs, os.Args[1:])
 
@@ -4,6 +4,7 @@
 package message
 
 import (
+	"crypto/x509"
 	"net"
 	"testing"
 	"time"
@@ -13,6 +14,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/staking"
 	"github.com/flare-foundation/flare/utils"
 	"github.com/flare-foundation/flare/utils/units"
 	"github.com/flare-foundation/flare/version"
@@ -383,3 +385,50 @@ func TestBuildAppGossipMsg(t *testing.T) {
 		assert.Equal(t, chainID[:], parsedMsg.Get(ChainID))
 	}
 }
+
+func TestBuildRelay(t *testing.T) {
+	nodeID := ids.GenerateTestShortID()
+	relayedMsg, err := UncompressingBuilder.AppGossip(ids.GenerateTestID(), []byte{1, 2, 3})
+	assert.NoError(t, err)
+
+	msg, err := UncompressingBuilder.Relay(nodeID, relayedMsg.Bytes())
+	assert.NoError(t, err)
+	assert.NotNil(t, msg)
+	assert.Equal(t, Relay, msg.Op())
+
+	parsedMsg, err := TestCodec.Parse(msg.Bytes(), dummyNodeID, dummyOnFinishedHandling)
+	assert.NoError(t, err)
+	assert.NotNil(t, parsedMsg)
+	assert.Equal(t, Relay, parsedMsg.Op())
+	assert.Equal(t, nodeID[:], parsedMsg.Get(PeerID))
+	assert.Equal(t, relayedMsg.Bytes(), parsedMsg.Get(RelayedMsg))
+
+	innerMsg, err := TestCodec.Parse(parsedMsg.Get(RelayedMsg).([]byte), nodeID, dummyOnFinishedHandling)
+	assert.NoError(t, err)
+	assert.Equal(t, AppGossip, innerMsg.Op())
+	assert.Equal(t, nodeID, innerMsg.NodeID())
+	assert.Equal(t, []byte{1, 2, 3}, innerMsg.Get(AppBytes))
+}
+
+func TestBuildRelayCert(t *testing.T) {
+	tlsCert, err := staking.NewTLSCert()
+	assert.NoError(t, err)
+	cert := tlsCert.Leaf
+	myVersion := version.NewDefaultVersion(1, 2, 3).String()
+	myTime := uint64(time.Now().Unix())
+	sig := make([]byte, 65)
+
+	msg, err := UncompressingBuilder.RelayCert(cert, myVersion, myTime, sig)
+	assert.NoError(t, err)
+	assert.NotNil(t, msg)
+	assert.Equal(t, RelayCert, msg.Op())
+
+	parsedMsg, err := TestCodec.Parse(msg.Bytes(), dummyNodeID, dummyOnFinishedHandling)
+	assert.NoError(t, err)
+	assert.NotNil(t, parsedMsg)
+	assert.Equal(t, RelayCert, parsedMsg.Op())
+	assert.Equal(t, cert.Raw, parsedMsg.Get(Certificate).(*x509.Certificate).Raw)
+	assert.Equal(t, myVersion, parsedMsg.Get(VersionStr))
+	assert.Equal(t, myTime, parsedMsg.Get(MyTime))
+	assert.Equal(t, sig, parsedMsg.Get(SigBytes))
+}
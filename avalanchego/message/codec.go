@@ -21,8 +21,10 @@ import (
 )
 
 var (
-	errMissingField = errors.New("message missing field")
-	errBadOp        = errors.New("input field has invalid operation")
+	errMissingField           = errors.New("message missing field")
+	errBadOp                  = errors.New("input field has invalid operation")
+	errUnknownCompressionType = errors.New("unknown compression type")
+	errNotPacked              = errors.New("message wasn't packed by a codec")
 
 	_ Codec = &codec{}
 )
@@ -33,6 +35,15 @@ type Packer interface {
 		fieldValues map[Field]interface{},
 		compress bool,
 	) (OutboundMessage, error)
+
+	// Recompress returns a copy of [msg] whose payload is compressed with
+	// [compressionType]. [msg] must have been compressed by Pack. The copy is
+	// cached on [msg], so that [msg] is recompressed at most once for each
+	// algorithm however many peers it is sent to.
+	Recompress(
+		msg OutboundMessage,
+		compressionType compression.Type,
+	) (OutboundMessage, error)
 }
 
 type Parser interface {
@@ -54,21 +65,38 @@ type codec struct {
 
 	clock mockable.Clock
 
-	compressTimeMetrics   map[Op]metric.Averager
-	decompressTimeMetrics map[Op]metric.Averager
-	compressor            compression.Compressor
+	compressTimeMetrics     map[Op]metric.Averager
+	decompressTimeMetrics   map[Op]metric.Averager
+	uncompressedSizeMetrics map[Op]metric.Averager
+	compressedSizeMetrics   map[Op]metric.Averager
+	compressionRatioMetrics map[Op]metric.Averager
+
+	// Algorithm used by Pack when asked to compress
+	compressionType compression.Type
+	compressors     map[compression.Type]compression.Compressor
 }
 
 func NewCodecWithMemoryPool(namespace string, metrics prometheus.Registerer, maxMessageSize int64) (Codec, error) {
+	zstdCompressor, err := compression.NewZstdCompressor(maxMessageSize)
+	if err != nil {
+		return nil, err
+	}
 	c := &codec{
 		byteSlicePool: sync.Pool{
 			New: func() interface{} {
 				return make([]byte, 0, constants.DefaultByteSliceCap)
 			},
 		},
-		compressTimeMetrics:   make(map[Op]metric.Averager, len(ExternalOps)),
-		decompressTimeMetrics: make(map[Op]metric.Averager, len(ExternalOps)),
-		compressor:            compression.NewGzipCompressor(maxMessageSize),
+		compressTimeMetrics:     make(map[Op]metric.Averager, len(ExternalOps)),
+		decompressTimeMetrics:   make(map[Op]metric.Averager, len(ExternalOps)),
+		uncompressedSizeMetrics: make(map[Op]metric.Averager, len(ExternalOps)),
+		compressedSizeMetrics:   make(map[Op]metric.Averager, len(ExternalOps)),
+		compressionRatioMetrics: make(map[Op]metric.Averager, len(ExternalOps)),
+		compressionType:         compression.Zstd,
+		compressors: map[compression.Type]compression.Compressor{
+			compression.Gzip: compression.NewGzipCompressor(maxMessageSize),
+			compression.Zstd: zstdCompressor,
+		},
 	}
 
 	errs := wrappers.Errs{}
@@ -91,6 +119,27 @@ func NewCodecWithMemoryPool(namespace string, metrics prometheus.Registerer, max
 			metrics,
 			&errs,
 		)
+		c.uncompressedSizeMetrics[op] = metric.NewAveragerWithErrs(
+			namespace,
+			fmt.Sprintf("%s_uncompressed_size", op),
+			fmt.Sprintf("size (in bytes) of the payload of %s messages before compression", op),
+			metrics,
+			&errs,
+		)
+		c.compressedSizeMetrics[op] = metric.NewAveragerWithErrs(
+			namespace,
+			fmt.Sprintf("%s_compressed_size", op),
+			fmt.Sprintf("size (in bytes) of the payload of %s messages after compression", op),
+			metrics,
+			&errs,
+		)
+		c.compressionRatioMetrics[op] = metric.NewAveragerWithErrs(
+			namespace,
+			fmt.Sprintf("%s_compression_ratio", op),
+			fmt.Sprintf("compressed size divided by uncompressed size of the payload of %s messages", op),
+			metrics,
+			&errs,
+		)
 	}
 	return c, errs.Err
 }
@@ -104,7 +153,7 @@ func (c *codec) SetTime(t time.Time) {
 // Uses [buffer] to hold the message's byte repr.
 // [buffer]'s contents may be overwritten by this method.
 // [buffer] may be nil.
-// If [compress], compress the payload.
+// If [compress], compress the payload with the codec's compression type.
 func (c *codec) Pack(
 	op Op,
 	fieldValues map[Field]interface{},
@@ -123,9 +172,13 @@ func (c *codec) Pack(
 	// Pack the op code (message type)
 	p.PackByte(byte(op))
 
-	// Optionally, pack whether the payload is compressed
+	// Optionally, pack how the payload is compressed
+	compressionType := compression.NoCompression
+	if compress {
+		compressionType = c.compressionType
+	}
 	if op.Compressable() {
-		p.PackBool(compress)
+		p.PackByte(byte(compressionType))
 	}
 
 	// Pack the uncompressed payload
@@ -149,24 +202,119 @@ func (c *codec) Pack(
 		return msg, nil
 	}
 
-	// If [compress], compress the payload (not the op code, not isCompressed).
+	// If [compress], compress the payload (not the op code, not the
+	// compression type).
 	// The slice below is guaranteed to be in-bounds because [p.Err] == nil
 	// implies that len(msg.bytes) >= 2
-	payloadBytes := msg.bytes[wrappers.BoolLen+wrappers.ByteLen:]
-	startTime := time.Now()
-	compressedPayloadBytes, err := c.compressor.Compress(payloadBytes)
+	payloadBytes := msg.bytes[wrappers.ByteLen+wrappers.ByteLen:]
+	compressedPayloadBytes, err := c.compress(op, compressionType, payloadBytes)
 	if err != nil {
-		return nil, fmt.Errorf("couldn't compress payload of %s message: %w", op, err)
+		return nil, err
 	}
-	c.compressTimeMetrics[op].Observe(float64(time.Since(startTime)))
+	msg.compressionType = compressionType
 	msg.bytesSavedCompression = len(payloadBytes) - len(compressedPayloadBytes) // may be negative
-	// Remove the uncompressed payload (keep just the message type and isCompressed)
-	msg.bytes = msg.bytes[:wrappers.BoolLen+wrappers.ByteLen]
+	// Remove the uncompressed payload (keep just the message type and the
+	// compression type)
+	msg.bytes = msg.bytes[:wrappers.ByteLen+wrappers.ByteLen]
 	// Attach the compressed payload
 	msg.bytes = append(msg.bytes, compressedPayloadBytes...)
 	return msg, nil
 }
 
+// Recompress decompresses the payload of [msg] and compresses it again with
+// [compressionType], unless [msg] was already recompressed with
+// [compressionType]. The caller holds a reference to the returned message and
+// [msg]'s reference count is unchanged.
+func (c *codec) Recompress(msg OutboundMessage, compressionType compression.Type) (OutboundMessage, error) {
+	outMsg, ok := msg.(*outboundMessage)
+	if !ok {
+		return nil, errNotPacked
+	}
+	op := outMsg.op
+	if !op.Compressable() {
+		return nil, fmt.Errorf("%s messages can't be compressed", op)
+	}
+
+	outMsg.recompressedLock.Lock()
+	defer outMsg.recompressedLock.Unlock()
+
+	if recompressed, ok := outMsg.recompressed[compressionType]; ok {
+		recompressed.AddRef()
+		return recompressed, nil
+	}
+
+	bytes := outMsg.bytes
+	if len(bytes) < wrappers.ByteLen+wrappers.ByteLen {
+		return nil, errMissingField
+	}
+	payloadBytes, err := c.decompress(op, compression.Type(bytes[wrappers.ByteLen]), bytes[wrappers.ByteLen+wrappers.ByteLen:])
+	if err != nil {
+		return nil, err
+	}
+	compressedPayloadBytes, err := c.compress(op, compressionType, payloadBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	buffer := c.byteSlicePool.Get().([]byte)
+	buffer = append(buffer[:0], byte(op), byte(compressionType))
+	recompressed := &outboundMessage{
+		op:                    op,
+		bytes:                 append(buffer, compressedPayloadBytes...),
+		bytesSavedCompression: len(payloadBytes) - len(compressedPayloadBytes), // may be negative
+		compressionType:       compressionType,
+		refs:                  2, // Held by the caller and by [outMsg]
+		c:                     c,
+	}
+	if outMsg.recompressed == nil {
+		outMsg.recompressed = make(map[compression.Type]*outboundMessage)
+	}
+	outMsg.recompressed[compressionType] = recompressed
+	return recompressed, nil
+}
+
+// compress [payloadBytes] of an [op] message with [compressionType]
+func (c *codec) compress(op Op, compressionType compression.Type, payloadBytes []byte) ([]byte, error) {
+	if compressionType == compression.NoCompression {
+		return payloadBytes, nil
+	}
+	compressor, ok := c.compressors[compressionType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", errUnknownCompressionType, compressionType)
+	}
+	startTime := time.Now()
+	compressedPayloadBytes, err := compressor.Compress(payloadBytes)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't compress payload of %s message: %w", op, err)
+	}
+	c.compressTimeMetrics[op].Observe(float64(time.Since(startTime)))
+	c.uncompressedSizeMetrics[op].Observe(float64(len(payloadBytes)))
+	c.compressedSizeMetrics[op].Observe(float64(len(compressedPayloadBytes)))
+	if len(payloadBytes) != 0 {
+		c.compressionRatioMetrics[op].Observe(float64(len(compressedPayloadBytes)) / float64(len(payloadBytes)))
+	}
+	return compressedPayloadBytes, nil
+}
+
+// decompress [compressedPayloadBytes] of an [op] message that was compressed
+// with [compressionType]
+func (c *codec) decompress(op Op, compressionType compression.Type, compressedPayloadBytes []byte) ([]byte, error) {
+	if compressionType == compression.NoCompression {
+		return compressedPayloadBytes, nil
+	}
+	compressor, ok := c.compressors[compressionType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", errUnknownCompressionType, compressionType)
+	}
+	startTime := time.Now()
+	payloadBytes, err := compressor.Decompress(compressedPayloadBytes)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't decompress payload of %s message: %w", op, err)
+	}
+	c.decompressTimeMetrics[op].Observe(float64(time.Since(startTime)))
+	return payloadBytes, nil
+}
+
 // Parse attempts to convert bytes into a message.
 // The first byte of the message is the opcode of the message.
 func (c *codec) Parse(bytes []byte, nodeID ids.ShortID, onFinishedHandling func()) (InboundMessage, error) {
@@ -181,9 +329,9 @@ func (c *codec) Parse(bytes []byte, nodeID ids.ShortID, onFinishedHandling func(
 	}
 
 	// See if messages of this type may be compressed
-	compressed := false
+	compressionType := compression.NoCompression
 	if op.Compressable() {
-		compressed = p.UnpackBool()
+		compressionType = compression.Type(p.UnpackByte())
 	}
 	if p.Err != nil {
 		return nil, p.Err
@@ -192,21 +340,20 @@ func (c *codec) Parse(bytes []byte, nodeID ids.ShortID, onFinishedHandling func(
 	bytesSaved := 0
 
 	// If the payload is compressed, decompress it
-	if compressed {
+	if compressionType != compression.NoCompression {
 		// The slice below is guaranteed to be in-bounds because [p.Err] == nil
-		compressedPayloadBytes := p.Bytes[wrappers.ByteLen+wrappers.BoolLen:]
-		startTime := time.Now()
-		payloadBytes, err := c.compressor.Decompress(compressedPayloadBytes)
+		compressedPayloadBytes := p.Bytes[wrappers.ByteLen+wrappers.ByteLen:]
+		payloadBytes, err := c.decompress(op, compressionType, compressedPayloadBytes)
 		if err != nil {
-			return nil, fmt.Errorf("couldn't decompress payload of %s message: %w", op, err)
+			return nil, err
 		}
-		c.decompressTimeMetrics[op].Observe(float64(time.Since(startTime)))
 		// Replace the compressed payload with the decompressed payload.
-		// Remove the compressed payload and isCompressed; keep just the message type
+		// Remove the compressed payload and the compression type; keep just
+		// the message type
 		p.Bytes = p.Bytes[:wrappers.ByteLen]
-		// Rewind offset by 1 because we removed the bool flag
+		// Rewind offset by 1 because we removed the compression type
 		// since the data now is uncompressed
-		p.Offset -= wrappers.BoolLen
+		p.Offset -= wrappers.ByteLen
 		// Attach the decompressed payload.
 		p.Bytes = append(p.Bytes, payloadBytes...)
 		bytesSaved = len(payloadBytes) - len(compressedPayloadBytes)
@@ -231,6 +378,7 @@ func (c *codec) Parse(bytes []byte, nodeID ids.ShortID, onFinishedHandling func(
 		op:                    op,
 		fields:                fieldValues,
 		bytesSavedCompression: bytesSaved,
+		size:                  len(bytes),
 		nodeID:                nodeID,
 		expirationTime:        expirationTime,
 		onFinishedHandling:    onFinishedHandling,
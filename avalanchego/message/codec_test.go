@@ -16,6 +16,7 @@ import (
 
 	"github.com/flare-foundation/flare/ids"
 	"github.com/flare-foundation/flare/utils"
+	"github.com/flare-foundation/flare/utils/compression"
 	"github.com/flare-foundation/flare/utils/units"
 )
 
@@ -61,7 +62,7 @@ func TestCodecParseExtraSpace(t *testing.T) {
 }
 
 // Test packing and then parsing messages
-// when using a gzip compressor
+// when using the default compressor
 func TestCodecPackParseGzip(t *testing.T) {
 	c, err := NewCodecWithMemoryPool("", prometheus.DefaultRegisterer, 2*units.MiB)
 	assert.NoError(t, err)
@@ -212,3 +213,71 @@ func TestCodecPackParseGzip(t *testing.T) {
 		assert.EqualValues(t, len(m.fields), len(unpacked.fields))
 	}
 }
+
+// Test that messages compressed with any supported algorithm can be parsed,
+// and that a message is recompressed once for each algorithm
+func TestCodecRecompress(t *testing.T) {
+	c, err := NewCodecWithMemoryPool("", prometheus.NewRegistry(), 2*units.MiB)
+	assert.NoError(t, err)
+	id := ids.GenerateTestID()
+	container := make([]byte, 1024)
+
+	packed, err := c.Pack(
+		Put,
+		map[Field]interface{}{
+			ChainID:        id[:],
+			RequestID:      uint32(1337),
+			ContainerID:    id[:],
+			ContainerBytes: container,
+		},
+		true,
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, compression.Zstd, packed.CompressionType())
+	assert.EqualValues(t, compression.Zstd, packed.Bytes()[1])
+
+	for _, compressionType := range []compression.Type{compression.NoCompression, compression.Gzip, compression.Zstd} {
+		recompressed, err := c.Recompress(packed, compressionType)
+		assert.NoError(t, err)
+		assert.Equal(t, compressionType, recompressed.CompressionType())
+		assert.EqualValues(t, compressionType, recompressed.Bytes()[1])
+
+		parsed, err := c.Parse(recompressed.Bytes(), dummyNodeID, dummyOnFinishedHandling)
+		assert.NoError(t, err)
+		assert.Equal(t, Put, parsed.Op())
+		assert.Equal(t, container, parsed.Get(ContainerBytes))
+		assert.Equal(t, uint32(1337), parsed.Get(RequestID))
+
+		// The copy is cached on the message
+		cached, err := c.Recompress(packed, compressionType)
+		assert.NoError(t, err)
+		assert.Same(t, recompressed, cached)
+	}
+
+	// Messages that can't be compressed can't be recompressed
+	notCompressable, err := c.Pack(GetVersion, map[Field]interface{}{}, false)
+	assert.NoError(t, err)
+	_, err = c.Recompress(notCompressable, compression.Gzip)
+	assert.Error(t, err)
+}
+
+func TestCodecParseUnknownCompressionType(t *testing.T) {
+	c, err := NewCodecWithMemoryPool("", prometheus.NewRegistry(), 2*units.MiB)
+	assert.NoError(t, err)
+	id := ids.GenerateTestID()
+
+	packed, err := c.Pack(
+		AppGossip,
+		map[Field]interface{}{
+			ChainID:  id[:],
+			AppBytes: []byte{1, 2, 3},
+		},
+		true,
+	)
+	assert.NoError(t, err)
+
+	msgBytes := append([]byte{}, packed.Bytes()...)
+	msgBytes[1] = math.MaxUint8
+	_, err = c.Parse(msgBytes, dummyNodeID, dummyOnFinishedHandling)
+	assert.ErrorIs(t, err, errUnknownCompressionType)
+}
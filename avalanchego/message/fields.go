@@ -33,6 +33,9 @@ const (
 	VMMessage                        // Used internally
 	Uptime                           // Used for Pong
 	VersionStruct                    // Used internally
+	PeerID                           // Used for relaying
+	RelayedMsg                       // Used for relaying
+	Certificate                      // Used for relaying
 )
 
 // Packer returns the packer function that can be used to pack this field.
@@ -76,6 +79,12 @@ func (f Field) Packer() func(*wrappers.Packer, interface{}) {
 		return wrappers.TryPackHashes
 	case Uptime:
 		return wrappers.TryPackByte
+	case PeerID:
+		return wrappers.TryPackAddr
+	case RelayedMsg:
+		return wrappers.TryPackBytes
+	case Certificate:
+		return wrappers.TryPackX509Certificate
 	default:
 		return nil
 	}
@@ -122,6 +131,12 @@ func (f Field) Unpacker() func(*wrappers.Packer) interface{} {
 		return wrappers.TryUnpackHashes
 	case Uptime:
 		return wrappers.TryUnpackByte
+	case PeerID:
+		return wrappers.TryUnpackAddr
+	case RelayedMsg:
+		return wrappers.TryUnpackBytes
+	case Certificate:
+		return wrappers.TryUnpackX509Certificate
 	default:
 		return nil
 	}
@@ -171,6 +186,12 @@ func (f Field) String() string {
 		return "Uptime"
 	case VersionStruct:
 		return "VersionStruct"
+	case PeerID:
+		return "PeerID"
+	case RelayedMsg:
+		return "RelayedMsg"
+	case Certificate:
+		return "Certificate"
 	default:
 		return "Unknown Field"
 	}
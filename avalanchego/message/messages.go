@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/utils/compression"
 	"github.com/flare-foundation/flare/utils/constants"
 )
 
@@ -23,6 +24,7 @@ type InboundMessage interface {
 	fmt.Stringer
 
 	BytesSavedCompression() int
+	Size() int
 	Op() Op
 	Get(Field) interface{}
 	NodeID() ids.ShortID
@@ -33,6 +35,7 @@ type InboundMessage interface {
 type inboundMessage struct {
 	op                    Op
 	bytesSavedCompression int
+	size                  int
 	fields                map[Field]interface{}
 	nodeID                ids.ShortID
 	expirationTime        time.Time
@@ -48,6 +51,10 @@ func (inMsg *inboundMessage) Op() Op { return inMsg.op }
 // compressed.
 func (inMsg *inboundMessage) BytesSavedCompression() int { return inMsg.bytesSavedCompression }
 
+// Size returns the number of bytes this message took on the network. 0 for
+// messages that were not received from the network.
+func (inMsg *inboundMessage) Size() int { return inMsg.size }
+
 // Field returns the value of the specified field in this message
 func (inMsg *inboundMessage) Get(field Field) interface{} { return inMsg.fields[field] }
 
@@ -96,6 +103,7 @@ func (inMsg *inboundMessage) String() string {
 // OutboundMessage represents a set of fields for an outbound message that can be serialized into a byte stream
 type OutboundMessage interface {
 	BytesSavedCompression() int
+	CompressionType() compression.Type
 	Bytes() []byte
 	Op() Op
 
@@ -106,11 +114,18 @@ type OutboundMessage interface {
 type outboundMessage struct {
 	bytes                 []byte
 	bytesSavedCompression int
+	compressionType       compression.Type
 	op                    Op
 
 	refLock sync.Mutex
 	refs    int
 	c       *codec
+
+	// Copies of this message compressed with other algorithms, which hold a
+	// reference to each copy until this message is released.
+	// [recompressedLock] should be held when accessing this map.
+	recompressedLock sync.Mutex
+	recompressed     map[compression.Type]*outboundMessage
 }
 
 // Op returns the value of the specified operation in this message
@@ -125,6 +140,10 @@ func (outMsg *outboundMessage) Bytes() []byte { return outMsg.bytes }
 // compressed.
 func (outMsg *outboundMessage) BytesSavedCompression() int { return outMsg.bytesSavedCompression }
 
+// CompressionType returns the algorithm this message's payload is compressed
+// with. NoCompression for messages that were not compressed.
+func (outMsg *outboundMessage) CompressionType() compression.Type { return outMsg.compressionType }
+
 func (outMsg *outboundMessage) AddRef() {
 	outMsg.refLock.Lock()
 	defer outMsg.refLock.Unlock()
@@ -141,5 +160,12 @@ func (outMsg *outboundMessage) DecRef() {
 	outMsg.refs--
 	if outMsg.refs == 0 {
 		outMsg.c.byteSlicePool.Put(outMsg.bytes)
+
+		outMsg.recompressedLock.Lock()
+		for _, recompressed := range outMsg.recompressed {
+			recompressed.DecRef()
+		}
+		outMsg.recompressed = nil
+		outMsg.recompressedLock.Unlock()
 	}
 }
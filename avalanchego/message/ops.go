@@ -38,6 +38,9 @@ const (
 	AppRequest
 	AppResponse
 	AppGossip
+	// Sentry relaying:
+	Relay
+	RelayCert
 
 	// Internal messages (External messages should be added above these):
 	GetAcceptedFrontierFailed
@@ -90,6 +93,12 @@ var (
 			AppGossip,
 		)...,
 	)
+	// Messages exchanged between validators, their sentries and the peers
+	// of their sentries
+	SentryOps = []Op{
+		Relay,
+		RelayCert,
+	}
 	ConsensusInternalOps = []Op{
 		GetAcceptedFrontierFailed,
 		GetAcceptedFailed,
@@ -105,7 +114,7 @@ var (
 	}
 	ConsensusOps = append(ConsensusExternalOps, ConsensusInternalOps...)
 
-	ExternalOps = append(ConsensusExternalOps, HandshakeOps...)
+	ExternalOps = append(ConsensusExternalOps, append(HandshakeOps, SentryOps...)...)
 
 	RequestToResponseOps = map[Op]Op{
 		GetAcceptedFrontier: AcceptedFrontier,
@@ -169,6 +178,9 @@ var (
 		AppRequest:  {ChainID, RequestID, Deadline, AppBytes},
 		AppResponse: {ChainID, RequestID, AppBytes},
 		AppGossip:   {ChainID, AppBytes},
+		// Sentry relaying:
+		Relay:     {PeerID, RelayedMsg},
+		RelayCert: {Certificate, VersionStr, MyTime, SigBytes},
 	}
 )
 
@@ -223,6 +235,10 @@ func (op Op) String() string {
 		return "app_response"
 	case AppGossip:
 		return "app_gossip"
+	case Relay:
+		return "relay"
+	case RelayCert:
+		return "relay_cert"
 
 	case GetAcceptedFrontierFailed:
 		return "get_accepted_frontier_failed"
@@ -4,10 +4,12 @@
 package message
 
 import (
+	"crypto/x509"
 	"time"
 
 	"github.com/flare-foundation/flare/ids"
 	"github.com/flare-foundation/flare/utils"
+	"github.com/flare-foundation/flare/utils/compression"
 )
 
 var _ OutboundMsgBuilder = &outMsgBuilder{}
@@ -127,6 +129,22 @@ type OutboundMsgBuilder interface {
 		chainID ids.ID,
 		msg []byte,
 	) (OutboundMessage, error)
+
+	Relay(
+		nodeID ids.ShortID,
+		msg []byte,
+	) (OutboundMessage, error)
+
+	RelayCert(
+		cert *x509.Certificate,
+		myVersion string,
+		myTime uint64,
+		sig []byte,
+	) (OutboundMessage, error)
+
+	// Recompress returns a copy of [msg] compressed with [compressionType].
+	// [msg] must be a compressed message built by this builder.
+	Recompress(msg OutboundMessage, compressionType compression.Type) (OutboundMessage, error)
 }
 
 type outMsgBuilder struct {
@@ -469,3 +487,39 @@ func (b *outMsgBuilder) AppGossip(chainID ids.ID, msg []byte) (OutboundMessage,
 		b.compress && AppGossip.Compressable(), // App messages may be compressed
 	)
 }
+
+// Relay wraps [msg] so that it's forwarded to, or was forwarded from, [nodeID]
+func (b *outMsgBuilder) Relay(nodeID ids.ShortID, msg []byte) (OutboundMessage, error) {
+	return b.c.Pack(
+		Relay,
+		map[Field]interface{}{
+			PeerID:     nodeID[:],
+			RelayedMsg: msg,
+		},
+		Relay.Compressable(), // Relay messages can't be compressed
+	)
+}
+
+// RelayCert authorizes a sentry to relay messages on behalf of the owner of
+// [cert]
+func (b *outMsgBuilder) RelayCert(
+	cert *x509.Certificate,
+	myVersion string,
+	myTime uint64,
+	sig []byte,
+) (OutboundMessage, error) {
+	return b.c.Pack(
+		RelayCert,
+		map[Field]interface{}{
+			Certificate: cert,
+			VersionStr:  myVersion,
+			MyTime:      myTime,
+			SigBytes:    sig,
+		},
+		RelayCert.Compressable(), // RelayCert messages can't be compressed
+	)
+}
+
+func (b *outMsgBuilder) Recompress(msg OutboundMessage, compressionType compression.Type) (OutboundMessage, error) {
+	return b.c.Recompress(msg, compressionType)
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"crypto"
 	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"math/rand"
@@ -25,9 +26,11 @@ import (
 	"github.com/flare-foundation/flare/ids"
 	"github.com/flare-foundation/flare/message"
 	"github.com/flare-foundation/flare/network/dialer"
+	"github.com/flare-foundation/flare/network/peerfilter"
 	"github.com/flare-foundation/flare/network/throttling"
 	"github.com/flare-foundation/flare/snow"
 	"github.com/flare-foundation/flare/snow/networking/benchlist"
+	"github.com/flare-foundation/flare/snow/networking/reputation"
 	"github.com/flare-foundation/flare/snow/networking/router"
 	"github.com/flare-foundation/flare/snow/networking/sender"
 	"github.com/flare-foundation/flare/snow/uptime"
@@ -47,8 +50,12 @@ var (
 	errNetworkClosed       = errors.New("network closed")
 	errPeerIsMyself        = errors.New("peer is myself")
 	errNoPrimaryValidators = errors.New("no default subnet validators")
+	errNoCertificate       = errors.New("TLS config has no certificate")
+	errSentryIPsMismatch   = errors.New("sentry IDs and IPs must have the same length")
+	errSentryAndPrivate    = errors.New("sentries can't be set with private peers")
 
-	_ Network = &network{}
+	_ Network             = &network{}
+	_ reputation.Bannable = &network{}
 )
 
 func init() { rand.Seed(time.Now().UnixNano()) }
@@ -91,6 +98,26 @@ type Network interface {
 
 	NodeUptime() (UptimeResult, bool)
 
+	// Returns the reputation tracker of the peers of this network. Thread
+	// safety must be managed internally to the tracker.
+	Reputation() reputation.Tracker
+
+	// Returns the allow and deny lists of peers. Thread safety must be managed
+	// internally to the network.
+	PeerFilter() peerfilter.Config
+
+	// Replaces the allow and deny lists of peers and disconnects from the
+	// peers that are no longer allowed. Thread safety must be managed
+	// internally to the network.
+	UpdatePeerFilter(config peerfilter.Config) error
+
+	// Replaces the sentries of this node and the validators it is a sentry
+	// for. Disconnects from the sentries that were removed, which are no
+	// longer trusted by the other peers once their latest relay certificate
+	// expires. [config.RelayCertMaxAge] is ignored. Thread safety must be
+	// managed internally to the network.
+	UpdateSentries(config SentryConfig) error
+
 	// Has a health check
 	health.Checker
 }
@@ -157,6 +184,31 @@ type network struct {
 
 	benchlistManager benchlist.Manager
 
+	// Tracks misbehaving peers and bans them
+	reputation reputation.Tracker
+
+	// Decides which peers we may be connected to
+	peerFilter peerfilter.Filter
+
+	// If non-empty, this node is a validator that is only connected to these
+	// sentries, which relay messages to and from the rest of the network.
+	// [stateLock] should be held when accessing this set.
+	sentryIDs ids.ShortSet
+	// Validators this node is a sentry for. [stateLock] should be held when
+	// accessing this set.
+	privatePeerIDs ids.ShortSet
+	// This node's staking certificate. Only set if the TLS config has a
+	// certificate.
+	myCert *x509.Certificate
+	// Private peer ID --> Latest certificate the private peer sent to
+	// authorize us to relay its messages. [stateLock] should be held when
+	// accessing this map.
+	relayCerts map[ids.ShortID]signedRelayCert
+	// Node ID --> ID of a connected peer that relays messages to and from this
+	// node --> Timestamp of the relay certificate that authorizes the peer.
+	// [stateLock] should be held when accessing this map.
+	relays map[ids.ShortID]map[ids.ShortID]uint64
+
 	// [lastTimestampLock] should be held when touching  [lastVersionIP],
 	// [lastVersionTimestamp], and [lastVersionSignature]
 	timeForIPLock sync.Mutex
@@ -216,6 +268,37 @@ type GossipConfig struct {
 	AppGossipValidatorSize     uint `json:"appGossipValidatorSize"`
 }
 
+// SentryConfig configures the sentry node mode, where a validator is only
+// connected to a set of sentry nodes it trusts. The sentries relay consensus
+// messages between the validator and the rest of the network and never gossip
+// the IP of the validator.
+type SentryConfig struct {
+	// Set on validators. Node IDs of the sentries of this node.
+	SentryIDs []ids.ShortID `json:"sentryIDs"`
+	// Set on validators. IPs of the sentries of this node, in the order of
+	// [SentryIDs].
+	SentryIPs []utils.IPDesc `json:"sentryIPs"`
+	// Set on sentries. Node IDs of the validators this node is a sentry for.
+	PrivatePeerIDs []ids.ShortID `json:"privatePeerIDs"`
+	// Relay certificates older than this are no longer trusted. Validators
+	// sign new certificates for their sentries twice as often, so that a
+	// sentry is no longer trusted at most [RelayCertMaxAge] after its
+	// validator stops authorizing it.
+	RelayCertMaxAge time.Duration `json:"relayCertMaxAge"`
+}
+
+// verifyLists returns an error if the lists of [c] can't be used together
+func (c *SentryConfig) verifyLists() error {
+	switch {
+	case len(c.SentryIDs) != len(c.SentryIPs):
+		return errSentryIPsMismatch
+	case len(c.SentryIDs) != 0 && len(c.PrivatePeerIDs) != 0:
+		return errSentryAndPrivate
+	default:
+		return nil
+	}
+}
+
 type ThrottlerConfig struct {
 	InboundConnUpgradeThrottlerConfig throttling.InboundConnUpgradeThrottlerConfig `json:"inboundConnUpgradeThrottlerConfig"`
 	InboundMsgThrottlerConfig         throttling.InboundMsgThrottlerConfig         `json:"inboundMsgThrottlerConfig"`
@@ -229,7 +312,10 @@ type Config struct {
 	GossipConfig         `json:"gossipConfig"`
 	TimeoutConfig        `json:"timeoutConfigs"`
 	DelayConfig          `json:"delayConfig"`
-	ThrottlerConfig      ThrottlerConfig `json:"throttlerConfig"`
+	ThrottlerConfig      ThrottlerConfig   `json:"throttlerConfig"`
+	ReputationConfig     reputation.Config `json:"reputationConfig"`
+	PeerFilterConfig     peerfilter.Config `json:"peerFilterConfig"`
+	SentryConfig         SentryConfig      `json:"sentryConfig"`
 
 	DialerConfig dialer.Config `json:"dialerConfig"`
 	TLSConfig    *tls.Config   `json:"-"`
@@ -252,6 +338,8 @@ type Config struct {
 	UptimeCalculator  uptime.Calculator  `json:"-"`
 	UptimeMetricFreq  time.Duration      `json:"uptimeMetricFreq"`
 	UptimeRequirement float64            `json:"uptimeRequirement"`
+	// Tracks how long each validator is connected. May be nil.
+	UptimeTracker uptime.WindowTracker `json:"-"`
 
 	// Require that all connections must have at least one validator between the
 	// 2 peers. This can be useful to enable if the node wants to connect to the
@@ -295,6 +383,8 @@ func NewNetwork(
 		inboundConnUpgradeThrottler: throttling.NewInboundConnUpgradeThrottler(log, config.ThrottlerConfig.InboundConnUpgradeThrottlerConfig),
 		benchlistManager:            benchlistManager,
 		latestPeerIP:                make(map[ids.ShortID]signedPeerIP),
+		relayCerts:                  make(map[ids.ShortID]signedRelayCert),
+		relays:                      make(map[ids.ShortID]map[ids.ShortID]uint64),
 		versionCompatibility:        version.GetCompatibility(config.NetworkID),
 		config:                      config,
 		mc:                          msgCreator,
@@ -333,6 +423,37 @@ func NewNetwork(
 	}
 	netw.outboundMsgThrottler = outboundMsgThrottler
 
+	netw.reputation, err = reputation.NewTracker(
+		config.ReputationConfig,
+		netw,
+		log,
+		config.Namespace,
+		metricsRegisterer,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("initializing reputation tracker failed with: %w", err)
+	}
+
+	netw.peerFilter, err = peerfilter.New(config.PeerFilterConfig)
+	if err != nil {
+		return nil, fmt.Errorf("initializing peer filter failed with: %w", err)
+	}
+
+	if config.TLSConfig != nil && len(config.TLSConfig.Certificates) != 0 && len(config.TLSConfig.Certificates[0].Certificate) != 0 {
+		netw.myCert, err = x509.ParseCertificate(config.TLSConfig.Certificates[0].Certificate[0])
+		if err != nil {
+			return nil, fmt.Errorf("parsing staking certificate failed with: %w", err)
+		}
+	}
+	if err := config.SentryConfig.verifyLists(); err != nil {
+		return nil, err
+	}
+	if len(config.SentryConfig.SentryIDs) != 0 && netw.myCert == nil {
+		return nil, errNoCertificate
+	}
+	netw.sentryIDs.Add(config.SentryConfig.SentryIDs...)
+	netw.privatePeerIDs.Add(config.SentryConfig.PrivatePeerIDs...)
+
 	netw.peers.initialize()
 	netw.sendFailRateCalculator = math.NewSyncAverager(math.NewAverager(0, config.MaxSendFailRateHalflife, netw.clock.Time()))
 	if err := netw.metrics.initialize(config.Namespace, metricsRegisterer); err != nil {
@@ -345,7 +466,29 @@ func NewNetwork(
 func (n *network) Send(msg message.OutboundMessage, nodeIDs ids.ShortSet, subnetID ids.ID, validatorOnly bool) ids.ShortSet {
 	// retrieve target peers
 	peers := n.getPeers(nodeIDs, subnetID, validatorOnly)
-	return n.send(msg, true, peers)
+
+	// retrieve the peers relaying to the targets we aren't connected to
+	relayPeers := n.getRelayPeers(msg.Op(), nodeIDs, validatorOnly)
+	if len(relayPeers) == 0 {
+		return n.send(msg, true, peers)
+	}
+
+	// Keep a reference to [msg] so that it can be relayed after [send]
+	// releases the reference it was given.
+	msg.AddRef()
+	sentTo := n.send(msg, true, peers)
+	for nodeID, relayPeer := range relayPeers {
+		relayMsg, err := n.mc.Relay(nodeID, msg.Bytes())
+		if err != nil {
+			n.log.Debug("failed to build Relay message for %s to %s: %s", msg.Op(), nodeID.PrefixedString(constants.NodeIDPrefix), err)
+			continue
+		}
+		if n.send(relayMsg, true, []*peer{relayPeer}).Len() != 0 {
+			sentTo.Add(nodeID)
+		}
+	}
+	msg.DecRef()
+	return sentTo
 }
 
 // Assumes [n.stateLock] is not held.
@@ -366,6 +509,21 @@ func (n *network) Gossip(
 		msg.DecRef()
 		return nil
 	}
+
+	// The sentries of this node gossip the message to their peers on our
+	// behalf.
+	n.stateLock.RLock()
+	hasSentries := n.sentryIDs.Len() > 0
+	n.stateLock.RUnlock()
+	if hasSentries && isGossipedOnBehalf(msg.Op()) {
+		relayMsg, err := n.mc.Relay(ids.ShortEmpty, msg.Bytes())
+		msg.DecRef()
+		if err != nil {
+			n.log.Debug("failed to build Relay message for gossip: %s", err)
+			return nil
+		}
+		return n.send(relayMsg, true, peers)
+	}
 	return n.send(msg, true, peers)
 }
 
@@ -496,6 +654,10 @@ func (n *network) shouldUpgradeIncoming(ip utils.IPDesc) bool {
 		n.log.Debug("not upgrading connection to %s because it's an alias", ipStr)
 		return false
 	}
+	if !n.peerFilter.AllowIP(ip.IP) {
+		n.log.Debug("not upgrading connection to %s because it's filtered", ipStr)
+		return false
+	}
 	if !n.inboundConnUpgradeThrottler.ShouldUpgrade(ip) {
 		n.log.Debug("not upgrading connection to %s due to rate-limiting", ipStr)
 		n.metrics.inboundConnRateLimited.Inc()
@@ -520,12 +682,29 @@ func (n *network) shouldHoldConnection(peerID ids.ShortID) bool {
 		n.config.Beacons.Contains(peerID)
 }
 
+// isFiltered returns true if the peer filter refuses [nodeID] at [ip]. [nodeID]
+// is ignored if it's empty, which is the case of beacons tracked by IP, and
+// [ip] is ignored if it's zero.
+func (n *network) isFiltered(ip utils.IPDesc, nodeID ids.ShortID) bool {
+	if nodeID != ids.ShortEmpty && !n.peerFilter.AllowNode(nodeID) {
+		return true
+	}
+	// A validator with sentries is only connected to its sentries
+	if nodeID != ids.ShortEmpty && n.sentryIDs.Len() > 0 && !n.sentryIDs.Contains(nodeID) {
+		return true
+	}
+	return !ip.IsZero() && !n.peerFilter.AllowIP(ip.IP)
+}
+
 // Dispatch starts accepting connections from other nodes attempting to connect
 // to this node.
 // Assumes [n.stateLock] is not held.
 func (n *network) Dispatch() error {
 	go n.gossipPeerList()      // Periodically gossip peers
 	go n.updateUptimeMetrics() // Periodically update uptime metrics
+	if n.config.SentryConfig.RelayCertMaxAge > 0 {
+		go n.maintainRelays() // Periodically renew and expire relay certificates
+	}
 	go n.inboundConnUpgradeThrottler.Dispatch()
 	defer n.inboundConnUpgradeThrottler.Stop()
 	go func() {
@@ -699,6 +878,99 @@ func (n *network) IP() utils.IPDesc {
 	return n.currentIP.IP()
 }
 
+// Reputation implements the Network interface
+func (n *network) Reputation() reputation.Tracker {
+	return n.reputation
+}
+
+// PeerFilter implements the Network interface
+func (n *network) PeerFilter() peerfilter.Config {
+	return n.peerFilter.Config()
+}
+
+// UpdatePeerFilter implements the Network interface
+// Assumes [n.stateLock] is not held.
+func (n *network) UpdatePeerFilter(config peerfilter.Config) error {
+	if err := n.peerFilter.Update(config); err != nil {
+		return err
+	}
+	n.log.Info("updated peer filter to allow %v and deny %v", config.Allow, config.Deny)
+
+	n.stateLock.RLock()
+	filtered := []*peer(nil)
+	for _, peer := range n.peers.peersList {
+		if n.isFiltered(peer.getIP(), peer.nodeID) {
+			filtered = append(filtered, peer)
+		}
+	}
+	n.stateLock.RUnlock()
+
+	for _, peer := range filtered {
+		n.log.Debug("disconnecting from %s%s because it's filtered", constants.NodeIDPrefix, peer.nodeID)
+		peer.Close() // Grabs the stateLock
+	}
+	return nil
+}
+
+// UpdateSentries implements the Network interface
+// Assumes [n.stateLock] is not held.
+func (n *network) UpdateSentries(config SentryConfig) error {
+	if err := config.verifyLists(); err != nil {
+		return err
+	}
+	if len(config.SentryIDs) != 0 && n.myCert == nil {
+		return errNoCertificate
+	}
+	n.log.Info("updated sentries to %v and private peers to %v", config.SentryIDs, config.PrivatePeerIDs)
+
+	n.stateLock.Lock()
+	n.sentryIDs.Clear()
+	n.sentryIDs.Add(config.SentryIDs...)
+	n.privatePeerIDs.Clear()
+	n.privatePeerIDs.Add(config.PrivatePeerIDs...)
+	for nodeID := range n.relayCerts {
+		if !n.privatePeerIDs.Contains(nodeID) {
+			delete(n.relayCerts, nodeID)
+		}
+	}
+	for i, sentryIP := range config.SentryIPs {
+		n.track(sentryIP, config.SentryIDs[i])
+	}
+	var filtered, sentries []*peer
+	for _, peer := range n.peers.peersList {
+		switch {
+		case n.isFiltered(peer.getIP(), peer.nodeID):
+			filtered = append(filtered, peer)
+		case n.sentryIDs.Contains(peer.nodeID) && peer.finishedHandshake.GetValue():
+			sentries = append(sentries, peer)
+		}
+	}
+	n.stateLock.Unlock()
+
+	for _, peer := range filtered {
+		n.log.Debug("disconnecting from %s%s because it isn't a sentry", constants.NodeIDPrefix, peer.nodeID)
+		peer.Close() // Grabs the stateLock
+	}
+	for _, peer := range sentries {
+		peer.sendRelayCert()
+	}
+	return nil
+}
+
+// Banned implements the reputation.Bannable interface. The peer is disconnected
+// and connections with it are refused until [until].
+// Assumes [n.stateLock] is not held.
+func (n *network) Banned(nodeID ids.ShortID, until time.Time) {
+	n.stateLock.RLock()
+	peer, ok := n.peers.getByID(nodeID)
+	n.stateLock.RUnlock()
+	if !ok {
+		return
+	}
+	n.log.Debug("disconnecting from %s%s because it's banned until %s", constants.NodeIDPrefix, nodeID, until)
+	peer.Close() // Grabs the stateLock
+}
+
 func (n *network) NodeUptime() (UptimeResult, bool) {
 	n.stateLock.RLock()
 	defer n.stateLock.RUnlock()
@@ -764,6 +1036,9 @@ func (n *network) track(ip utils.IPDesc, nodeID ids.ShortID) {
 	if _, ok := n.myIPs[str]; ok {
 		return
 	}
+	if n.isFiltered(ip, nodeID) {
+		return
+	}
 	// If we saw an IP gossiped for this node ID
 	// with a later timestamp, don't track this old IP
 	if latestIP, ok := n.latestPeerIP[nodeID]; ok {
@@ -859,6 +1134,16 @@ func (n *network) connectTo(ip utils.IPDesc, nodeID ids.ShortID) {
 		}
 
 		n.stateLock.Lock()
+		if n.isFiltered(ip, nodeID) {
+			// The peer filter changed since we started trying to connect to
+			// this IP. Forget about it so that it's tracked again if it's
+			// allowed later on.
+			n.log.Debug("not connecting to %s at %s because it's filtered", nodeID.PrefixedString(constants.NodeIDPrefix), ip)
+			delete(n.disconnectedIPs, str)
+			delete(n.retryDelay, str)
+			n.stateLock.Unlock()
+			return
+		}
 		_, isDisconnected := n.disconnectedIPs[str]
 		_, isConnected := n.connectedIPs[str]
 		_, isMyself := n.myIPs[str]
@@ -1017,6 +1302,24 @@ func (n *network) tryAddPeer(p *peer) error {
 		return errPeerIsMyself
 	}
 
+	if n.reputation.IsBanned(p.nodeID) {
+		if !ip.IsZero() {
+			str := ip.String()
+			delete(n.disconnectedIPs, str)
+			delete(n.retryDelay, str)
+		}
+		return fmt.Errorf("connection from banned %s at %s", p.nodeID.PrefixedString(constants.NodeIDPrefix), ip)
+	}
+
+	if n.isFiltered(ip, p.nodeID) {
+		if !ip.IsZero() {
+			str := ip.String()
+			delete(n.disconnectedIPs, str)
+			delete(n.retryDelay, str)
+		}
+		return fmt.Errorf("filtered connection from %s at %s", p.nodeID.PrefixedString(constants.NodeIDPrefix), ip)
+	}
+
 	if !n.shouldHoldConnection(p.nodeID) {
 		if !ip.IsZero() {
 			str := ip.String()
@@ -1091,6 +1394,9 @@ func (n *network) validatorIPs() ([]utils.IPCertDesc, error) {
 			continue
 		case !n.config.Validators.Contains(peer.nodeID):
 			continue
+		case n.privatePeerIDs.Contains(peer.nodeID):
+			// Never reveal the IPs of the validators we're a sentry for
+			continue
 		}
 
 		peerVersion := peer.versionStruct.GetValue().(version.Application)
@@ -1160,6 +1466,22 @@ func (n *network) connected(p *peer) {
 
 	n.router.Connected(p.nodeID, peerVersion)
 	n.metrics.connected.Inc()
+	if n.config.UptimeTracker != nil {
+		n.config.UptimeTracker.Connect(p.nodeID)
+	}
+
+	switch {
+	case n.sentryIDs.Contains(p.nodeID):
+		// Authorize the sentry to relay our messages
+		go p.sendRelayCert()
+	case !n.privatePeerIDs.Contains(p.nodeID):
+		// Tell the peer which validators it can reach through us
+		for _, relayCert := range n.relayCerts {
+			if !n.relayCertExpired(relayCert.time) {
+				go p.sendSignedRelayCert(relayCert)
+			}
+		}
+	}
 }
 
 // should only be called after the peer is marked as connected.
@@ -1182,16 +1504,25 @@ func (n *network) disconnected(p *peer) {
 		delete(n.disconnectedIPs, str)
 		delete(n.connectedIPs, str)
 
-		if n.config.Validators.Contains(p.nodeID) {
+		if n.config.Validators.Contains(p.nodeID) || n.sentryIDs.Contains(p.nodeID) {
 			n.track(ip, p.nodeID)
 		}
 	}
 
 	// Only send Disconnected to router if Connected was sent
-	if p.finishedHandshake.GetValue() {
+	if p.finishedHandshake.GetValue() && len(n.relays[p.nodeID]) == 0 {
 		n.router.Disconnected(p.nodeID)
+		if n.config.UptimeTracker != nil {
+			n.config.UptimeTracker.Disconnect(p.nodeID)
+		}
 	}
 	n.metrics.disconnected.Inc()
+
+	// The peer can no longer relay our messages
+	delete(n.relayCerts, p.nodeID)
+	for nodeID := range n.relays {
+		n.removeRelay(nodeID, p.nodeID)
+	}
 }
 
 // Safe copy the peers dressed as a peerElement
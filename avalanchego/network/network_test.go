@@ -22,8 +22,10 @@ import (
 	"github.com/flare-foundation/flare/ids"
 	"github.com/flare-foundation/flare/message"
 	"github.com/flare-foundation/flare/network/dialer"
+	"github.com/flare-foundation/flare/network/peerfilter"
 	"github.com/flare-foundation/flare/network/throttling"
 	"github.com/flare-foundation/flare/snow/networking/benchlist"
+	"github.com/flare-foundation/flare/snow/networking/reputation"
 	"github.com/flare-foundation/flare/snow/networking/router"
 	"github.com/flare-foundation/flare/snow/uptime"
 	"github.com/flare-foundation/flare/snow/validators"
@@ -518,6 +520,322 @@ func TestEstablishConnection(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestBannedPeerDisconnected(t *testing.T) {
+	initCerts(t)
+
+	ip0 := utils.NewDynamicIPDesc(
+		net.IPv6loopback,
+		0,
+	)
+	id0 := ids.ShortID(hashing.ComputeHash160Array([]byte(ip0.IP().String())))
+	ip1 := utils.NewDynamicIPDesc(
+		net.IPv6loopback,
+		1,
+	)
+	id1 := ids.ShortID(hashing.ComputeHash160Array([]byte(ip1.IP().String())))
+
+	listener0 := &testListener{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 0,
+		},
+		inbound: make(chan net.Conn, 1<<10),
+		closed:  make(chan struct{}),
+	}
+	caller0 := &testDialer{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 0,
+		},
+		outbounds: make(map[string]*testListener),
+	}
+	listener1 := &testListener{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 1,
+		},
+		inbound: make(chan net.Conn, 1<<10),
+		closed:  make(chan struct{}),
+	}
+	caller1 := &testDialer{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 1,
+		},
+		outbounds: make(map[string]*testListener),
+	}
+
+	caller0.outbounds[ip1.IP().String()] = listener1
+	caller1.outbounds[ip0.IP().String()] = listener0
+
+	vdrs := validators.NewManager(0,
+		validators.WithValidator(id0, 1),
+		validators.WithValidator(id1, 1),
+	)
+	beacons := validators.NewSet()
+
+	var (
+		wg0           sync.WaitGroup
+		wg1           sync.WaitGroup
+		disconnected0 sync.WaitGroup
+		peerID0       ids.ShortID
+	)
+	wg0.Add(1)
+	wg1.Add(1)
+	disconnected0.Add(1)
+
+	metrics0 := prometheus.NewRegistry()
+	msgCreator0, err := message.NewCreator(metrics0, true /*compressionEnabled*/, "dummyNamespace" /*parentNamespace*/)
+	assert.NoError(t, err)
+	handler0 := &testHandler{
+		ConnectedF: func(id ids.ShortID, nodeVersion version.Application) {
+			if id != id0 {
+				peerID0 = id
+				wg0.Done()
+			}
+		},
+		DisconnectedF: func(id ids.ShortID) {
+			if id != id0 {
+				disconnected0.Done()
+			}
+		},
+	}
+
+	metrics1 := prometheus.NewRegistry()
+	msgCreator1, err := message.NewCreator(metrics1, true /*compressionEnabled*/, "dummyNamespace" /*parentNamespace*/)
+	assert.NoError(t, err)
+	handler1 := &testHandler{
+		ConnectedF: func(id ids.ShortID, nodeVersion version.Application) {
+			if id != id1 {
+				wg1.Done()
+			}
+		},
+	}
+
+	net0, err := newTestNetwork(
+		id0,
+		ip0,
+		defaultVersionManager,
+		vdrs,
+		beacons,
+		cert0.PrivateKey.(crypto.Signer),
+		ids.Set{},
+		tlsConfig0,
+		listener0,
+		caller0,
+		metrics0,
+		msgCreator0,
+		handler0,
+	)
+	assert.NoError(t, err)
+	assert.NotNil(t, net0)
+
+	net1, err := newTestNetwork(
+		id1,
+		ip1,
+		defaultVersionManager,
+		vdrs,
+		beacons,
+		cert1.PrivateKey.(crypto.Signer),
+		ids.Set{},
+		tlsConfig1,
+		listener1,
+		caller1,
+		metrics1,
+		msgCreator1,
+		handler1,
+	)
+	assert.NoError(t, err)
+	assert.NotNil(t, net1)
+
+	go func() {
+		err := net0.Dispatch()
+		assert.Error(t, err)
+	}()
+	go func() {
+		err := net1.Dispatch()
+		assert.Error(t, err)
+	}()
+
+	net0.Track(ip1.IP(), id1)
+
+	wg0.Wait()
+	wg1.Wait()
+
+	// Reaching the ban threshold disconnects the peer
+	net0.Reputation().Penalize(peerID0, reputation.MalformedMessage)
+	disconnected0.Wait()
+	assert.True(t, net0.Reputation().IsBanned(peerID0))
+	assert.Empty(t, net0.Peers([]ids.ShortID{peerID0}))
+
+	err = net0.Close()
+	assert.NoError(t, err)
+
+	err = net1.Close()
+	assert.NoError(t, err)
+}
+
+func TestFilteredPeerDisconnected(t *testing.T) {
+	initCerts(t)
+
+	ip0 := utils.NewDynamicIPDesc(
+		net.IPv6loopback,
+		0,
+	)
+	id0 := ids.ShortID(hashing.ComputeHash160Array([]byte(ip0.IP().String())))
+	ip1 := utils.NewDynamicIPDesc(
+		net.IPv6loopback,
+		1,
+	)
+	id1 := ids.ShortID(hashing.ComputeHash160Array([]byte(ip1.IP().String())))
+
+	listener0 := &testListener{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 0,
+		},
+		inbound: make(chan net.Conn, 1<<10),
+		closed:  make(chan struct{}),
+	}
+	caller0 := &testDialer{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 0,
+		},
+		outbounds: make(map[string]*testListener),
+	}
+	listener1 := &testListener{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 1,
+		},
+		inbound: make(chan net.Conn, 1<<10),
+		closed:  make(chan struct{}),
+	}
+	caller1 := &testDialer{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 1,
+		},
+		outbounds: make(map[string]*testListener),
+	}
+
+	caller0.outbounds[ip1.IP().String()] = listener1
+	caller1.outbounds[ip0.IP().String()] = listener0
+
+	vdrs := validators.NewManager(0,
+		validators.WithValidator(id0, 1),
+		validators.WithValidator(id1, 1),
+	)
+	beacons := validators.NewSet()
+
+	var (
+		wg0           sync.WaitGroup
+		wg1           sync.WaitGroup
+		disconnected0 sync.WaitGroup
+		peerID0       ids.ShortID
+	)
+	wg0.Add(1)
+	wg1.Add(1)
+	disconnected0.Add(1)
+
+	metrics0 := prometheus.NewRegistry()
+	msgCreator0, err := message.NewCreator(metrics0, true /*compressionEnabled*/, "dummyNamespace" /*parentNamespace*/)
+	assert.NoError(t, err)
+	handler0 := &testHandler{
+		ConnectedF: func(id ids.ShortID, nodeVersion version.Application) {
+			if id != id0 {
+				peerID0 = id
+				wg0.Done()
+			}
+		},
+		DisconnectedF: func(id ids.ShortID) {
+			if id != id0 {
+				disconnected0.Done()
+			}
+		},
+	}
+
+	metrics1 := prometheus.NewRegistry()
+	msgCreator1, err := message.NewCreator(metrics1, true /*compressionEnabled*/, "dummyNamespace" /*parentNamespace*/)
+	assert.NoError(t, err)
+	handler1 := &testHandler{
+		ConnectedF: func(id ids.ShortID, nodeVersion version.Application) {
+			if id != id1 {
+				wg1.Done()
+			}
+		},
+	}
+
+	net0, err := newTestNetwork(
+		id0,
+		ip0,
+		defaultVersionManager,
+		vdrs,
+		beacons,
+		cert0.PrivateKey.(crypto.Signer),
+		ids.Set{},
+		tlsConfig0,
+		listener0,
+		caller0,
+		metrics0,
+		msgCreator0,
+		handler0,
+	)
+	assert.NoError(t, err)
+	assert.NotNil(t, net0)
+
+	net1, err := newTestNetwork(
+		id1,
+		ip1,
+		defaultVersionManager,
+		vdrs,
+		beacons,
+		cert1.PrivateKey.(crypto.Signer),
+		ids.Set{},
+		tlsConfig1,
+		listener1,
+		caller1,
+		metrics1,
+		msgCreator1,
+		handler1,
+	)
+	assert.NoError(t, err)
+	assert.NotNil(t, net1)
+
+	go func() {
+		err := net0.Dispatch()
+		assert.Error(t, err)
+	}()
+	go func() {
+		err := net1.Dispatch()
+		assert.Error(t, err)
+	}()
+
+	net0.Track(ip1.IP(), id1)
+
+	wg0.Wait()
+	wg1.Wait()
+
+	// Denying the peer disconnects it
+	filter := peerfilter.Config{Deny: []string{peerID0.PrefixedString(constants.NodeIDPrefix)}}
+	err = net0.UpdatePeerFilter(filter)
+	assert.NoError(t, err)
+	disconnected0.Wait()
+	assert.Equal(t, filter, net0.PeerFilter())
+	assert.Empty(t, net0.Peers([]ids.ShortID{peerID0}))
+
+	err = net0.UpdatePeerFilter(peerfilter.Config{Allow: []string{"not an IP"}})
+	assert.Error(t, err)
+	assert.Equal(t, filter, net0.PeerFilter())
+
+	err = net0.Close()
+	assert.NoError(t, err)
+
+	err = net1.Close()
+	assert.NoError(t, err)
+}
+
 func TestDoubleTrack(t *testing.T) {
 	initCerts(t)
 
@@ -3085,6 +3403,434 @@ func TestAppGossip(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestSentryRelay(t *testing.T) {
+	initCerts(t)
+
+	ip0 := utils.NewDynamicIPDesc(
+		net.IPv6loopback,
+		0,
+	)
+	ip1 := utils.NewDynamicIPDesc(
+		net.IPv6loopback,
+		1,
+	)
+	ip2 := utils.NewDynamicIPDesc(
+		net.IPv6loopback,
+		2,
+	)
+
+	// id0 is a validator connected to the sentry id1 of the validator id2
+	id0 := certToID(cert0.Leaf)
+	id1 := certToID(cert1.Leaf)
+	id2 := certToID(cert2.Leaf)
+
+	listener0 := &testListener{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 0,
+		},
+		inbound: make(chan net.Conn, 1<<10),
+		closed:  make(chan struct{}),
+	}
+	caller0 := &testDialer{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 0,
+		},
+		outbounds: make(map[string]*testListener),
+	}
+	listener1 := &testListener{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 1,
+		},
+		inbound: make(chan net.Conn, 1<<10),
+		closed:  make(chan struct{}),
+	}
+	caller1 := &testDialer{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 1,
+		},
+		outbounds: make(map[string]*testListener),
+	}
+	listener2 := &testListener{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 2,
+		},
+		inbound: make(chan net.Conn, 1<<10),
+		closed:  make(chan struct{}),
+	}
+	caller2 := &testDialer{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 2,
+		},
+		outbounds: make(map[string]*testListener),
+	}
+
+	caller0.outbounds[ip1.IP().String()] = listener1
+	caller1.outbounds[ip0.IP().String()] = listener0
+	caller1.outbounds[ip2.IP().String()] = listener2
+	caller2.outbounds[ip1.IP().String()] = listener1
+
+	vdrs := validators.NewManager(
+		0,
+		validators.WithValidator(id0, 1),
+		validators.WithValidator(id1, 1),
+		validators.WithValidator(id2, 1),
+	)
+	beacons := validators.NewSet()
+
+	type gossip struct {
+		nodeID ids.ShortID
+		bytes  string
+	}
+	gossip0 := make(chan gossip, 8)
+	gossip1 := make(chan gossip, 8)
+	gossip2 := make(chan gossip, 8)
+
+	var (
+		wg0 sync.WaitGroup
+		wg1 sync.WaitGroup
+		wg2 sync.WaitGroup
+	)
+	wg0.Add(2) // connected to id1, reaches id2 through id1
+	wg1.Add(2)
+	wg2.Add(1)
+
+	metrics0 := prometheus.NewRegistry()
+	msgCreator0, err := message.NewCreator(metrics0, true /*compressionEnabled*/, "dummyNamespace" /*parentNamespace*/)
+	assert.NoError(t, err)
+	handler0 := &testHandler{
+		ConnectedF: func(id ids.ShortID, nodeVersion version.Application) {
+			assert.Contains(t, []ids.ShortID{id1, id2}, id)
+			wg0.Done()
+		},
+		AppGossipF: func(nodeID ids.ShortID, chainID ids.ID, appGossipBytes []byte, onFinishedHandling func()) {
+			gossip0 <- gossip{nodeID: nodeID, bytes: string(appGossipBytes)}
+			onFinishedHandling()
+		},
+	}
+
+	metrics1 := prometheus.NewRegistry()
+	msgCreator1, err := message.NewCreator(metrics1, true /*compressionEnabled*/, "dummyNamespace" /*parentNamespace*/)
+	assert.NoError(t, err)
+	handler1 := &testHandler{
+		ConnectedF: func(id ids.ShortID, nodeVersion version.Application) {
+			assert.Contains(t, []ids.ShortID{id0, id2}, id)
+			wg1.Done()
+		},
+		AppGossipF: func(nodeID ids.ShortID, chainID ids.ID, appGossipBytes []byte, onFinishedHandling func()) {
+			gossip1 <- gossip{nodeID: nodeID, bytes: string(appGossipBytes)}
+			onFinishedHandling()
+		},
+	}
+
+	metrics2 := prometheus.NewRegistry()
+	msgCreator2, err := message.NewCreator(metrics2, true /*compressionEnabled*/, "dummyNamespace" /*parentNamespace*/)
+	assert.NoError(t, err)
+	handler2 := &testHandler{
+		ConnectedF: func(id ids.ShortID, nodeVersion version.Application) {
+			assert.Equal(t, id1, id)
+			wg2.Done()
+		},
+		AppGossipF: func(nodeID ids.ShortID, chainID ids.ID, appGossipBytes []byte, onFinishedHandling func()) {
+			gossip2 <- gossip{nodeID: nodeID, bytes: string(appGossipBytes)}
+			onFinishedHandling()
+		},
+	}
+
+	net0, err := newTestNetwork(
+		id0,
+		ip0,
+		defaultVersionManager,
+		vdrs,
+		beacons,
+		cert0.PrivateKey.(crypto.Signer),
+		ids.Set{},
+		tlsConfig0,
+		listener0,
+		caller0,
+		metrics0,
+		msgCreator0,
+		handler0,
+	)
+	assert.NoError(t, err)
+	assert.NotNil(t, net0)
+
+	net1, err := newTestNetwork(
+		id1,
+		ip1,
+		defaultVersionManager,
+		vdrs,
+		beacons,
+		cert1.PrivateKey.(crypto.Signer),
+		ids.Set{},
+		tlsConfig1,
+		listener1,
+		caller1,
+		metrics1,
+		msgCreator1,
+		handler1,
+	)
+	assert.NoError(t, err)
+	assert.NotNil(t, net1)
+	net1.(*network).privatePeerIDs.Add(id2)
+
+	net2, err := newTestNetwork(
+		id2,
+		ip2,
+		defaultVersionManager,
+		vdrs,
+		beacons,
+		cert2.PrivateKey.(crypto.Signer),
+		ids.Set{},
+		tlsConfig2,
+		listener2,
+		caller2,
+		metrics2,
+		msgCreator2,
+		handler2,
+	)
+	assert.NoError(t, err)
+	assert.NotNil(t, net2)
+	net2.(*network).sentryIDs.Add(id1)
+	net2.(*network).myCert = cert2.Leaf
+
+	// The validator only accepts its sentry
+	assert.True(t, net2.(*network).isFiltered(utils.IPDesc{}, id0))
+	assert.False(t, net2.(*network).isFiltered(utils.IPDesc{}, id1))
+
+	go func() {
+		err := net0.Dispatch()
+		assert.Error(t, err)
+	}()
+	go func() {
+		err := net1.Dispatch()
+		assert.Error(t, err)
+	}()
+	go func() {
+		err := net2.Dispatch()
+		assert.Error(t, err)
+	}()
+
+	net0.Track(ip1.IP(), id1)
+	net2.Track(ip1.IP(), id1)
+
+	wg0.Wait()
+	wg1.Wait()
+	wg2.Wait()
+
+	chainID := ids.GenerateTestID()
+
+	// id0 -> id1 -> id2
+	msg, err := msgCreator0.AppGossip(chainID, []byte("to validator"))
+	assert.NoError(t, err)
+	nodeIDs := ids.NewShortSet(1)
+	nodeIDs.Add(id2)
+	sentTo := net0.Send(msg, nodeIDs, constants.PrimaryNetworkID, true)
+	assert.True(t, sentTo.Contains(id2))
+	assert.Equal(t, gossip{nodeID: id0, bytes: "to validator"}, <-gossip2)
+
+	// id2 -> id1 -> id0
+	msg, err = msgCreator2.AppGossip(chainID, []byte("from validator"))
+	assert.NoError(t, err)
+	nodeIDs = ids.NewShortSet(1)
+	nodeIDs.Add(id0)
+	sentTo = net2.Send(msg, nodeIDs, constants.PrimaryNetworkID, true)
+	assert.True(t, sentTo.Contains(id0))
+	assert.Equal(t, gossip{nodeID: id2, bytes: "from validator"}, <-gossip0)
+
+	// The sentry gossips on behalf of the validator
+	msg, err = msgCreator2.AppGossip(chainID, []byte("gossip"))
+	assert.NoError(t, err)
+	net2.Gossip(msg, constants.PrimaryNetworkID, false, int(net2.(*network).config.AppGossipValidatorSize), int(net2.(*network).config.AppGossipNonValidatorSize))
+	assert.Equal(t, gossip{nodeID: id2, bytes: "gossip"}, <-gossip1)
+	assert.Equal(t, gossip{nodeID: id2, bytes: "gossip"}, <-gossip0)
+
+	// The sentry never gossips the IP of the validator
+	ipCerts, err := net1.(*network).validatorIPs()
+	assert.NoError(t, err)
+	assert.False(t, isIPDescIn(ip2.IP(), ipCerts))
+
+	err = net0.Close()
+	assert.NoError(t, err)
+
+	err = net1.Close()
+	assert.NoError(t, err)
+
+	err = net2.Close()
+	assert.NoError(t, err)
+}
+
+// TestSentryLocalNodes runs a validator, its sentry and a peer of the sentry
+// over TCP on the loopback interface, and retires the sentry
+func TestSentryLocalNodes(t *testing.T) {
+	initCerts(t)
+	assert := assert.New(t)
+
+	// id0 is a validator connected to the sentry id1 of the validator id2
+	id0 := certToID(cert0.Leaf)
+	id1 := certToID(cert1.Leaf)
+	id2 := certToID(cert2.Leaf)
+	vdrs := validators.NewManager(
+		0,
+		validators.WithValidator(id0, 1),
+		validators.WithValidator(id1, 1),
+		validators.WithValidator(id2, 1),
+	)
+
+	type gossip struct {
+		nodeID ids.ShortID
+		bytes  string
+	}
+	newNode := func(id ids.ShortID, tlsConfig *tls.Config, connected, disconnected chan ids.ShortID, gossiped chan gossip) (*network, message.Creator, utils.IPDesc) {
+		listener, err := net.Listen(constants.NetworkType, "127.0.0.1:0")
+		assert.NoError(err)
+		ip, err := utils.ToIPDesc(listener.Addr().String())
+		assert.NoError(err)
+
+		metrics := prometheus.NewRegistry()
+		msgCreator, err := message.NewCreator(metrics, true /*compressionEnabled*/, "dummyNamespace" /*parentNamespace*/)
+		assert.NoError(err)
+		handler := &testHandler{
+			ConnectedF:    func(nodeID ids.ShortID, _ version.Application) { connected <- nodeID },
+			DisconnectedF: func(nodeID ids.ShortID) { disconnected <- nodeID },
+			AppGossipF: func(nodeID ids.ShortID, _ ids.ID, appGossipBytes []byte, onFinishedHandling func()) {
+				gossiped <- gossip{nodeID: nodeID, bytes: string(appGossipBytes)}
+				onFinishedHandling()
+			},
+		}
+		n, err := newTestNetwork(
+			id,
+			utils.NewDynamicIPDesc(ip.IP, ip.Port),
+			defaultVersionManager,
+			vdrs,
+			validators.NewSet(),
+			tlsConfig.Certificates[0].PrivateKey.(crypto.Signer),
+			ids.Set{},
+			tlsConfig,
+			listener,
+			dialer.NewDialer(constants.NetworkType, dialer.Config{ConnectionTimeout: 10 * time.Second}, logging.NoLog{}),
+			metrics,
+			msgCreator,
+			handler,
+		)
+		assert.NoError(err)
+		go func() {
+			err := n.Dispatch()
+			assert.Error(err)
+		}()
+		return n.(*network), msgCreator, ip
+	}
+
+	var (
+		connected0    = make(chan ids.ShortID, 8)
+		connected1    = make(chan ids.ShortID, 8)
+		connected2    = make(chan ids.ShortID, 8)
+		disconnected0 = make(chan ids.ShortID, 8)
+		disconnected1 = make(chan ids.ShortID, 8)
+		disconnected2 = make(chan ids.ShortID, 8)
+		gossip0       = make(chan gossip, 8)
+		gossip1       = make(chan gossip, 8)
+		gossip2       = make(chan gossip, 8)
+	)
+	net0, msgCreator0, _ := newNode(id0, tlsConfig0, connected0, disconnected0, gossip0)
+	net1, msgCreator1, ip1 := newNode(id1, tlsConfig1, connected1, disconnected1, gossip1)
+	net2, msgCreator2, ip2 := newNode(id2, tlsConfig2, connected2, disconnected2, gossip2)
+	defer func() {
+		assert.NoError(net0.Close())
+		assert.NoError(net1.Close())
+		assert.NoError(net2.Close())
+	}()
+
+	assert.NoError(net1.UpdateSentries(SentryConfig{PrivatePeerIDs: []ids.ShortID{id2}}))
+	assert.NoError(net2.UpdateSentries(SentryConfig{
+		SentryIDs: []ids.ShortID{id1},
+		SentryIPs: []utils.IPDesc{ip1},
+	}))
+	net0.Track(ip1, id1)
+
+	// The peer reaches the validator once the sentry relays the certificate
+	// of the validator
+	assert.Equal(id1, <-connected2)
+	assert.ElementsMatch([]ids.ShortID{id1, id2}, []ids.ShortID{<-connected0, <-connected0})
+	assert.ElementsMatch([]ids.ShortID{id0, id2}, []ids.ShortID{<-connected1, <-connected1})
+	assert.Empty(net0.Peers([]ids.ShortID{id2}))
+
+	chainID := ids.GenerateTestID()
+	nodeIDs := ids.NewShortSet(1)
+	nodeIDs.Add(id2)
+	msg, err := msgCreator0.AppGossip(chainID, []byte("to validator"))
+	assert.NoError(err)
+	sentTo := net0.Send(msg, nodeIDs, constants.PrimaryNetworkID, true)
+	assert.True(sentTo.Contains(id2))
+	assert.Equal(gossip{nodeID: id0, bytes: "to validator"}, <-gossip2)
+
+	nodeIDs = ids.NewShortSet(1)
+	nodeIDs.Add(id0)
+	msg, err = msgCreator2.AppGossip(chainID, []byte("from validator"))
+	assert.NoError(err)
+	sentTo = net2.Send(msg, nodeIDs, constants.PrimaryNetworkID, true)
+	assert.True(sentTo.Contains(id0))
+	assert.Equal(gossip{nodeID: id2, bytes: "from validator"}, <-gossip0)
+
+	ipCerts, err := net1.validatorIPs()
+	assert.NoError(err)
+	assert.False(isIPDescIn(ip2, ipCerts))
+
+	relayCertTime := func() uint64 {
+		net0.stateLock.RLock()
+		defer net0.stateLock.RUnlock()
+		return net0.relays[id2][id1]
+	}
+	net1.stateLock.RLock()
+	relayCert := net1.relayCerts[id2]
+	net1.stateLock.RUnlock()
+	maxAge := uint64(net0.config.SentryConfig.RelayCertMaxAge / time.Second)
+
+	// The validator renews the certificate of its sentry, which is still
+	// trusted after the first certificate expired
+	net2.clock.Set(time.Unix(int64(relayCert.time+maxAge/2), 0))
+	net2.renewRelayCerts()
+	assert.Eventually(func() bool { return relayCertTime() == relayCert.time+maxAge/2 }, 10*time.Second, 10*time.Millisecond)
+	net0.clock.Set(time.Unix(int64(relayCert.time+maxAge+1), 0))
+	net0.expireRelays()
+	assert.Equal(relayCert.time+maxAge/2, relayCertTime())
+
+	// The validator retires its sentry, which is no longer trusted by the peer
+	// once its certificate expires
+	assert.NoError(net2.UpdateSentries(SentryConfig{
+		SentryIDs: []ids.ShortID{ids.GenerateTestShortID()},
+		SentryIPs: []utils.IPDesc{{IP: net.IPv6loopback, Port: 1}},
+	}))
+	assert.Equal(id1, <-disconnected2)
+	assert.Equal(id2, <-disconnected1)
+	net0.clock.Set(time.Unix(int64(relayCert.time+maxAge/2+maxAge+1), 0))
+	net0.expireRelays()
+	assert.Equal(id2, <-disconnected0)
+	msg, err = msgCreator0.AppGossip(chainID, []byte("to retired sentry"))
+	assert.NoError(err)
+	nodeIDs = ids.NewShortSet(1)
+	nodeIDs.Add(id2)
+	sentTo = net0.Send(msg, nodeIDs, constants.PrimaryNetworkID, true)
+	assert.False(sentTo.Contains(id2))
+
+	// A certificate replayed by the retired sentry is refused
+	net0.stateLock.RLock()
+	peer1, ok := net0.peers.getByID(id1)
+	net0.stateLock.RUnlock()
+	assert.True(ok)
+	relayCertMsg, err := msgCreator1.RelayCert(relayCert.cert, relayCert.version, relayCert.time, relayCert.signature)
+	assert.NoError(err)
+	inMsg, err := msgCreator0.Parse(relayCertMsg.Bytes(), id1, func() {})
+	assert.NoError(err)
+	peer1.handleRelayCert(inMsg)
+	assert.Zero(relayCertTime())
+}
+
 // Helper method for TestValidatorIPs
 func createPeer(peerID ids.ShortID, peerIPDesc utils.IPDesc, peerVersion version.Application) *peer {
 	newPeer := peer{
@@ -3213,6 +3959,15 @@ func newDefaultConfig() Config {
 			AppGossipNonValidatorSize: defaultAppGossipNonValidatorSize,
 			AppGossipValidatorSize:    defaultAppGossipValidatorSize,
 		},
+		ReputationConfig: reputation.Config{
+			HalfLife:                time.Minute,
+			MalformedMessagePenalty: 1,
+			BanThreshold:            1,
+			BanDuration:             time.Hour,
+		},
+		SentryConfig: SentryConfig{
+			RelayCertMaxAge: time.Minute,
+		},
 		MaxClockDifference: time.Minute,
 		AllowPrivateIPs:    true,
 		PingFrequency:      constants.DefaultPingFrequency,
@@ -17,7 +17,9 @@ import (
 
 	"github.com/flare-foundation/flare/ids"
 	"github.com/flare-foundation/flare/message"
+	"github.com/flare-foundation/flare/snow/networking/reputation"
 	"github.com/flare-foundation/flare/utils"
+	"github.com/flare-foundation/flare/utils/compression"
 	"github.com/flare-foundation/flare/utils/constants"
 	"github.com/flare-foundation/flare/utils/formatting"
 	"github.com/flare-foundation/flare/utils/hashing"
@@ -135,16 +137,22 @@ type peer struct {
 
 	// observedUptime is the uptime of this node in peer's point of view
 	observedUptime uint8
+
+	// compressionType is the algorithm compressed messages sent to this peer
+	// use. Set from the version of the peer, before the first compressed
+	// message is sent to it. Must only be accessed atomically
+	compressionType uint32
 }
 
 // newPeer returns a properly initialized *peer.
 func newPeer(net *network, conn net.Conn, ip utils.IPDesc) *peer {
 	p := &peer{
-		sendQueueCond: sync.NewCond(&sync.Mutex{}),
-		net:           net,
-		conn:          conn,
-		ip:            ip,
-		tickerCloser:  make(chan struct{}),
+		sendQueueCond:   sync.NewCond(&sync.Mutex{}),
+		net:             net,
+		conn:            conn,
+		ip:              ip,
+		tickerCloser:    make(chan struct{}),
+		compressionType: uint32(compression.Gzip),
 	}
 	p.aliasTimer = timer.NewTimer(p.releaseExpiredAliases)
 	p.trackedSubnets.Add(constants.PrimaryNetworkID)
@@ -269,7 +277,9 @@ func (p *peer) ReadMessages() {
 		// Note that when we are done handling this message, or give up
 		// trying to read it, we must call [p.net.msgThrottler.Release]
 		// to give back the bytes used by this message.
-		p.net.inboundMsgThrottler.Acquire(uint64(msgLen), p.nodeID)
+		if p.net.inboundMsgThrottler.Acquire(uint64(msgLen), p.nodeID) {
+			p.net.reputation.Penalize(p.nodeID, reputation.Throttled)
+		}
 
 		// Invariant: When done processing this message, onFinishedHandling() is called.
 		// If this is not honored, the message throttler will leak until no new messages can be read.
@@ -300,6 +310,7 @@ func (p *peer) ReadMessages() {
 			// Couldn't parse the message. Read the next one.
 			onFinishedHandling()
 			p.net.metrics.failedToParse.Inc()
+			p.net.reputation.Penalize(p.nodeID, reputation.MalformedMessage)
 			continue
 		}
 
@@ -373,6 +384,29 @@ func (p *peer) WriteMessages() {
 // If ![canModifyMsg], [msg] will not be modified by this method.
 // [canModifyMsg] should be false if [msg] is sent in a loop, for example/.
 func (p *peer) Send(msg message.OutboundMessage) bool {
+	// Compressed messages are re-encoded if this peer doesn't use the
+	// algorithm they were compressed with
+	compressionType := p.getCompressionType()
+	if msgCompressionType := msg.CompressionType(); msgCompressionType == compression.NoCompression || msgCompressionType == compressionType {
+		return p.send(msg)
+	}
+	recompressedMsg, err := p.net.mc.Recompress(msg, compressionType)
+	if err != nil {
+		p.net.log.Debug("dropping %s message to %s%s at %s because it couldn't be compressed with %s: %s", msg.Op(), constants.NodeIDPrefix, p.nodeID, p.getIP(), compressionType, err)
+		return false
+	}
+	if !p.send(recompressedMsg) {
+		recompressedMsg.DecRef()
+		return false
+	}
+	// [recompressedMsg] is sent in place of [msg], so the reference the
+	// caller added to [msg] is removed
+	msg.DecRef()
+	return true
+}
+
+// send queues [msg] to be written to this peer
+func (p *peer) send(msg message.OutboundMessage) bool {
 	msgBytes := msg.Bytes()
 	msgLen := int64(len(msgBytes))
 
@@ -459,6 +493,16 @@ func (p *peer) handle(msg message.InboundMessage, msgLen float64) {
 		return
 	}
 
+	switch op { // Sentry-related message types
+	case message.Relay:
+		p.handleRelay(msg)
+		return
+	case message.RelayCert:
+		p.handleRelayCert(msg)
+		msg.OnFinishedHandling()
+		return
+	}
+
 	// Consensus and app-level messages
 	p.net.router.HandleInbound(msg)
 }
@@ -722,6 +766,11 @@ func (p *peer) handleVersion(msg message.InboundMessage) {
 		}
 	}
 
+	// The peer list is the first compressed message sent to the peer
+	compressionType := peerCompressionType(peerVersion)
+	p.net.log.Verbo("using %s compression for messages to %s%s at %s", compressionType, constants.NodeIDPrefix, p.nodeID, p.getIP())
+	atomic.StoreUint32(&p.compressionType, uint32(compressionType))
+
 	p.sendPeerList()
 
 	p.versionStruct.SetValue(peerVersion)
@@ -846,6 +895,22 @@ func (p *peer) handlePong(msg message.InboundMessage) {
 	}
 }
 
+// peerCompressionType returns the algorithm that compressed messages sent to a
+// peer running [peerVersion] use. Peers older than [version.MinimumZstdVersion]
+// only decompress gzip.
+func peerCompressionType(peerVersion version.Application) compression.Type {
+	if peerVersion.Before(version.MinimumZstdVersion) {
+		return compression.Gzip
+	}
+	return compression.Zstd
+}
+
+// getCompressionType returns the algorithm that compressed messages sent to
+// this peer use
+func (p *peer) getCompressionType() compression.Type {
+	return compression.Type(atomic.LoadUint32(&p.compressionType))
+}
+
 // assumes the [stateLock] is held
 func (p *peer) tryMarkFinishedHandshake() {
 	if !p.finishedHandshake.GetValue() && // not already marked as finished with handshake
@@ -14,8 +14,10 @@ import (
 	"github.com/flare-foundation/flare/message"
 	"github.com/flare-foundation/flare/snow/validators"
 	"github.com/flare-foundation/flare/utils"
+	"github.com/flare-foundation/flare/utils/compression"
 	"github.com/flare-foundation/flare/utils/constants"
 	"github.com/flare-foundation/flare/utils/hashing"
+	"github.com/flare-foundation/flare/version"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 )
@@ -45,6 +47,10 @@ func (m *TestMsg) BytesSavedCompression() int {
 	return 0
 }
 
+func (m *TestMsg) CompressionType() compression.Type {
+	return compression.NoCompression
+}
+
 func (m *TestMsg) AddRef() {}
 
 func (m *TestMsg) DecRef() {}
@@ -126,3 +132,111 @@ func TestPeer_Close(t *testing.T) {
 
 	peer.Close()
 }
+
+func TestPeerCompression(t *testing.T) {
+	initCerts(t)
+
+	ip := utils.NewDynamicIPDesc(
+		net.IPv6loopback,
+		0,
+	)
+	id := ids.ShortID(hashing.ComputeHash160Array([]byte(ip.IP().String())))
+
+	listener := &testListener{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 0,
+		},
+		inbound: make(chan net.Conn, 1<<10),
+		closed:  make(chan struct{}),
+	}
+	caller := &testDialer{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 0,
+		},
+		outbounds: make(map[string]*testListener),
+	}
+
+	vdrs := validators.NewManager(constants.LocalID,
+		validators.WithValidator(id, math.MaxUint64),
+	)
+	beacons := validators.NewSet()
+	metrics := prometheus.NewRegistry()
+	msgCreator, err := message.NewCreator(metrics, true /*compressionEnabled*/, "dummyNamespace" /*parentNamespace*/)
+	assert.NoError(t, err)
+	handler := &testHandler{}
+
+	netwrk, err := newTestNetwork(
+		id,
+		ip,
+		defaultVersionManager,
+		vdrs,
+		beacons,
+		cert0.PrivateKey.(crypto.Signer),
+		ids.Set{},
+		tlsConfig0,
+		listener,
+		caller,
+		metrics,
+		msgCreator,
+		handler,
+	)
+	assert.NoError(t, err)
+
+	ip1 := utils.NewDynamicIPDesc(
+		net.IPv6loopback,
+		1,
+	)
+	caller.outbounds[ip1.IP().String()] = listener
+	conn, err := caller.Dial(context.Background(), ip1.IP())
+	assert.NoError(t, err)
+
+	// Peers older than the first version that supports zstd get gzip
+	oldVersion := version.NewDefaultApplication(constants.PlatformName, 0, 6, 5)
+	assert.Equal(t, compression.Gzip, peerCompressionType(oldVersion))
+	assert.Equal(t, compression.Zstd, peerCompressionType(version.MinimumZstdVersion))
+	assert.Equal(t, compression.Zstd, peerCompressionType(version.CurrentApp))
+
+	testPeer := func(compressionType compression.Type) *peer {
+		peer := newPeer(netwrk.(*network), conn, ip1.IP())
+		peer.sendQueue = make([]message.OutboundMessage, 0)
+		peer.compressionType = uint32(compressionType)
+		return peer
+	}
+	gzipPeer0 := testPeer(compression.Gzip)
+	gzipPeer1 := testPeer(compression.Gzip)
+	zstdPeer := testPeer(compression.Zstd)
+
+	gossip, err := msgCreator.AppGossip(ids.GenerateTestID(), make([]byte, 1024))
+	assert.NoError(t, err)
+	assert.Equal(t, compression.Zstd, gossip.CompressionType())
+
+	gossip.AddRef()
+	assert.True(t, zstdPeer.Send(gossip))
+	assert.Len(t, zstdPeer.sendQueue, 1)
+	assert.Equal(t, gossip, zstdPeer.sendQueue[0])
+
+	// The message is recompressed once for all the gzip peers
+	gossip.AddRef()
+	assert.True(t, gzipPeer0.Send(gossip))
+	gossip.AddRef()
+	assert.True(t, gzipPeer1.Send(gossip))
+	assert.Len(t, gzipPeer0.sendQueue, 1)
+	assert.Len(t, gzipPeer1.sendQueue, 1)
+	assert.Equal(t, compression.Gzip, gzipPeer0.sendQueue[0].CompressionType())
+	assert.Same(t, gzipPeer0.sendQueue[0], gzipPeer1.sendQueue[0])
+
+	parsed, err := msgCreator.Parse(gzipPeer0.sendQueue[0].Bytes(), id, func() {})
+	assert.NoError(t, err)
+	assert.Equal(t, make([]byte, 1024), parsed.Get(message.AppBytes))
+
+	go func() {
+		err := netwrk.Close()
+		assert.NoError(t, err)
+	}()
+
+	for _, peer := range []*peer{gzipPeer0, gzipPeer1, zstdPeer} {
+		peer.Close()
+	}
+}
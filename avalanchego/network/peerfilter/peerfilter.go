@@ -0,0 +1,172 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package peerfilter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/utils/constants"
+)
+
+var _ Filter = &filter{}
+
+// Filter decides which peers this node may be connected to. Thread safe.
+//
+// A peer is refused if its node ID or IP is denied. If the allow list has node
+// IDs, only the peers with one of these node IDs are accepted, and if it has IPs,
+// only the peers at one of these IPs are accepted.
+type Filter interface {
+	// AllowIP returns false if peers at [ip] are refused
+	AllowIP(ip net.IP) bool
+	// AllowNode returns false if [nodeID] is refused
+	AllowNode(nodeID ids.ShortID) bool
+	// Config returns the lists the filter currently enforces
+	Config() Config
+	// Update replaces the lists the filter enforces. If [config] is invalid,
+	// the filter is left unchanged.
+	Update(config Config) error
+}
+
+// Config holds the allow and deny lists of peers. Each entry is either a node
+// ID, prefixed with "NodeID-", an IP or a CIDR block.
+type Config struct {
+	Allow []string `json:"allow"`
+	Deny  []string `json:"deny"`
+}
+
+// ReadConfig reads a Config from the JSON file at [path]
+func ReadConfig(path string) (Config, error) {
+	configBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+	config := Config{}
+	if err := json.Unmarshal(configBytes, &config); err != nil {
+		return Config{}, fmt.Errorf("couldn't parse peer filter file %q: %w", path, err)
+	}
+	return config, nil
+}
+
+// list is a parsed list of node IDs and IP ranges
+type list struct {
+	nodeIDs ids.ShortSet
+	ipNets  []*net.IPNet
+}
+
+func parseList(entries []string) (list, error) {
+	l := list{nodeIDs: ids.ShortSet{}}
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		switch {
+		case strings.HasPrefix(entry, constants.NodeIDPrefix):
+			nodeID, err := ids.ShortFromPrefixedString(entry, constants.NodeIDPrefix)
+			if err != nil {
+				return list{}, fmt.Errorf("invalid node ID %q: %w", entry, err)
+			}
+			l.nodeIDs.Add(nodeID)
+		case strings.Contains(entry, "/"):
+			_, ipNet, err := net.ParseCIDR(entry)
+			if err != nil {
+				return list{}, fmt.Errorf("invalid CIDR %q: %w", entry, err)
+			}
+			l.ipNets = append(l.ipNets, ipNet)
+		default:
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return list{}, fmt.Errorf("invalid IP %q", entry)
+			}
+			bits := 8 * net.IPv6len
+			if ip4 := ip.To4(); ip4 != nil {
+				ip = ip4
+				bits = 8 * net.IPv4len
+			}
+			l.ipNets = append(l.ipNets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+		}
+	}
+	return l, nil
+}
+
+func (l *list) containsIP(ip net.IP) bool {
+	for _, ipNet := range l.ipNets {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+type filter struct {
+	lock   sync.RWMutex
+	config Config
+	allow  list
+	deny   list
+}
+
+// New returns a filter enforcing the lists of [config]
+func New(config Config) (Filter, error) {
+	f := &filter{}
+	return f, f.Update(config)
+}
+
+// AllowIP implements the Filter interface
+func (f *filter) AllowIP(ip net.IP) bool {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	if f.deny.containsIP(ip) {
+		return false
+	}
+	return len(f.allow.ipNets) == 0 || f.allow.containsIP(ip)
+}
+
+// AllowNode implements the Filter interface
+func (f *filter) AllowNode(nodeID ids.ShortID) bool {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	if f.deny.nodeIDs.Contains(nodeID) {
+		return false
+	}
+	return f.allow.nodeIDs.Len() == 0 || f.allow.nodeIDs.Contains(nodeID)
+}
+
+// Config implements the Filter interface
+func (f *filter) Config() Config {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	return Config{
+		Allow: append([]string(nil), f.config.Allow...),
+		Deny:  append([]string(nil), f.config.Deny...),
+	}
+}
+
+// Update implements the Filter interface
+func (f *filter) Update(config Config) error {
+	allow, err := parseList(config.Allow)
+	if err != nil {
+		return fmt.Errorf("invalid allow list: %w", err)
+	}
+	deny, err := parseList(config.Deny)
+	if err != nil {
+		return fmt.Errorf("invalid deny list: %w", err)
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.config = Config{
+		Allow: append([]string(nil), config.Allow...),
+		Deny:  append([]string(nil), config.Deny...),
+	}
+	f.allow = allow
+	f.deny = deny
+	return nil
+}
@@ -0,0 +1,97 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package peerfilter
+
+import (
+	"io/ioutil"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/utils/constants"
+)
+
+func TestFilterEmpty(t *testing.T) {
+	assert := assert.New(t)
+	f, err := New(Config{})
+	assert.NoError(err)
+	assert.True(f.AllowIP(net.ParseIP("1.2.3.4")))
+	assert.True(f.AllowNode(ids.GenerateTestShortID()))
+}
+
+func TestFilterDeny(t *testing.T) {
+	assert := assert.New(t)
+	denied := ids.GenerateTestShortID()
+	f, err := New(Config{
+		Deny: []string{
+			denied.PrefixedString(constants.NodeIDPrefix),
+			"10.0.0.0/8",
+			"1.2.3.4",
+		},
+	})
+	assert.NoError(err)
+
+	assert.False(f.AllowNode(denied))
+	assert.True(f.AllowNode(ids.GenerateTestShortID()))
+	assert.False(f.AllowIP(net.ParseIP("10.1.2.3")))
+	assert.False(f.AllowIP(net.ParseIP("1.2.3.4")))
+	assert.False(f.AllowIP(net.ParseIP("::ffff:1.2.3.4")))
+	assert.True(f.AllowIP(net.ParseIP("1.2.3.5")))
+}
+
+func TestFilterAllow(t *testing.T) {
+	assert := assert.New(t)
+	allowed := ids.GenerateTestShortID()
+	f, err := New(Config{
+		Allow: []string{
+			allowed.PrefixedString(constants.NodeIDPrefix),
+			"192.168.0.0/16",
+		},
+		Deny: []string{"192.168.1.0/24"},
+	})
+	assert.NoError(err)
+
+	assert.True(f.AllowNode(allowed))
+	assert.False(f.AllowNode(ids.GenerateTestShortID()))
+	assert.True(f.AllowIP(net.ParseIP("192.168.2.1")))
+	assert.False(f.AllowIP(net.ParseIP("192.168.1.1")))
+	assert.False(f.AllowIP(net.ParseIP("8.8.8.8")))
+}
+
+func TestFilterUpdate(t *testing.T) {
+	assert := assert.New(t)
+	nodeID := ids.GenerateTestShortID()
+	config := Config{Deny: []string{nodeID.PrefixedString(constants.NodeIDPrefix)}}
+	f, err := New(config)
+	assert.NoError(err)
+	assert.False(f.AllowNode(nodeID))
+	assert.Equal(config, f.Config())
+
+	// An invalid update leaves the filter unchanged
+	assert.Error(f.Update(Config{Allow: []string{"not an IP"}}))
+	assert.Error(f.Update(Config{Deny: []string{"1.2.3.4/33"}}))
+	assert.Error(f.Update(Config{Deny: []string{constants.NodeIDPrefix + "invalid"}}))
+	assert.False(f.AllowNode(nodeID))
+	assert.Equal(config, f.Config())
+
+	assert.NoError(f.Update(Config{}))
+	assert.True(f.AllowNode(nodeID))
+}
+
+func TestReadConfig(t *testing.T) {
+	assert := assert.New(t)
+	path := filepath.Join(t.TempDir(), "peers.json")
+	assert.NoError(ioutil.WriteFile(path, []byte(`{"allow":["10.0.0.0/8"],"deny":["10.0.0.1"]}`), 0o600))
+
+	config, err := ReadConfig(path)
+	assert.NoError(err)
+	assert.Equal(Config{Allow: []string{"10.0.0.0/8"}, Deny: []string{"10.0.0.1"}}, config)
+
+	assert.NoError(ioutil.WriteFile(path, []byte(`{`), 0o600))
+	_, err = ReadConfig(path)
+	assert.Error(err)
+}
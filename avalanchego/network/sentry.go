@@ -0,0 +1,468 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"crypto"
+	"crypto/x509"
+	"time"
+
+	cryptorand "crypto/rand"
+
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/message"
+	"github.com/flare-foundation/flare/snow/networking/reputation"
+	"github.com/flare-foundation/flare/utils/constants"
+	"github.com/flare-foundation/flare/utils/hashing"
+	"github.com/flare-foundation/flare/utils/wrappers"
+)
+
+// Messages that may be relayed by sentries
+var relayableOps = make(map[message.Op]struct{}, len(message.ConsensusExternalOps))
+
+func init() {
+	for _, op := range message.ConsensusExternalOps {
+		relayableOps[op] = struct{}{}
+	}
+}
+
+// signedRelayCert authorizes a sentry to relay the messages of the validator
+// that owns [cert]
+type signedRelayCert struct {
+	cert      *x509.Certificate
+	version   string
+	time      uint64
+	signature []byte
+}
+
+func isRelayable(op message.Op) bool {
+	_, ok := relayableOps[op]
+	return ok
+}
+
+// isGossipedOnBehalf returns true if sentries gossip messages with [op] to
+// their peers on behalf of the validator that gossiped them
+func isGossipedOnBehalf(op message.Op) bool {
+	return op == message.AppGossip || op == message.Put
+}
+
+// relayedOp returns the op of the relayed message [msgBytes]
+func relayedOp(msgBytes []byte) (message.Op, bool) {
+	if len(msgBytes) == 0 {
+		return 0, false
+	}
+	op := message.Op(msgBytes[0])
+	return op, isRelayable(op)
+}
+
+func relayCertBytes(sentryID ids.ShortID, timestamp uint64) []byte {
+	p := wrappers.Packer{
+		Bytes: make([]byte, hashing.AddrLen+wrappers.LongLen),
+	}
+	p.PackFixedBytes(sentryID[:])
+	p.PackLong(timestamp)
+	return p.Bytes
+}
+
+// relayCertExpired returns true if a relay certificate signed at [timestamp] is
+// too old to be trusted
+func (n *network) relayCertExpired(timestamp uint64) bool {
+	return float64(n.clock.Unix())-float64(timestamp) > n.config.SentryConfig.RelayCertMaxAge.Seconds()
+}
+
+// removeRelay forgets that [relayID] relays messages to and from [nodeID].
+// Assumes [n.stateLock] is held.
+func (n *network) removeRelay(nodeID, relayID ids.ShortID) {
+	relayIDs := n.relays[nodeID]
+	if _, ok := relayIDs[relayID]; !ok {
+		return
+	}
+	delete(relayIDs, relayID)
+	if len(relayIDs) != 0 {
+		return
+	}
+	delete(n.relays, nodeID)
+	if peer, connected := n.peers.getByID(nodeID); !connected || !peer.finishedHandshake.GetValue() {
+		n.router.Disconnected(nodeID)
+		if n.config.UptimeTracker != nil {
+			n.config.UptimeTracker.Disconnect(nodeID)
+		}
+	}
+}
+
+// Assumes [n.stateLock] is not held. Only returns after the network is closed.
+func (n *network) maintainRelays() {
+	t := time.NewTicker(n.config.SentryConfig.RelayCertMaxAge / 2)
+	defer t.Stop()
+
+	for range t.C {
+		if n.closed.GetValue() {
+			return
+		}
+
+		n.renewRelayCerts()
+		n.expireRelays()
+	}
+}
+
+// renewRelayCerts sends new relay certificates to the sentries of this node, so
+// that the peers keep trusting them.
+// Assumes [n.stateLock] is not held.
+func (n *network) renewRelayCerts() {
+	n.stateLock.RLock()
+	sentries := make([]*peer, 0, n.sentryIDs.Len())
+	for sentryID := range n.sentryIDs {
+		if peer, ok := n.peers.getByID(sentryID); ok && peer.finishedHandshake.GetValue() {
+			sentries = append(sentries, peer)
+		}
+	}
+	n.stateLock.RUnlock()
+
+	for _, peer := range sentries {
+		peer.sendRelayCert()
+	}
+}
+
+// expireRelays forgets the relay certificates that are too old to be trusted.
+// A sentry whose validator stopped renewing its certificate, because the
+// sentry was removed from its sentries or isn't connected to it anymore, is
+// then no longer trusted to relay the messages of the validator.
+// Assumes [n.stateLock] is not held.
+func (n *network) expireRelays() {
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	for nodeID, relayCert := range n.relayCerts {
+		if n.relayCertExpired(relayCert.time) {
+			delete(n.relayCerts, nodeID)
+		}
+	}
+	for nodeID, relayIDs := range n.relays {
+		for relayID, relayCertTime := range relayIDs {
+			if n.relayCertExpired(relayCertTime) {
+				n.log.Debug("%s is no longer reachable through %s%s because its relay certificate expired",
+					nodeID.PrefixedString(constants.NodeIDPrefix), constants.NodeIDPrefix, relayID,
+				)
+				n.removeRelay(nodeID, relayID)
+			}
+		}
+	}
+}
+
+// Returns, for each of [nodeIDs] that we aren't connected to, a connected peer
+// that relays messages to it.
+// Assumes [n.stateLock] is not held.
+func (n *network) getRelayPeers(op message.Op, nodeIDs ids.ShortSet, validatorOnly bool) map[ids.ShortID]*peer {
+	if !isRelayable(op) {
+		return nil
+	}
+
+	n.stateLock.RLock()
+	defer n.stateLock.RUnlock()
+
+	if n.closed.GetValue() || (n.sentryIDs.Len() == 0 && len(n.relays) == 0) {
+		return nil
+	}
+
+	var relayPeers map[ids.ShortID]*peer
+	for nodeID := range nodeIDs {
+		if peer, ok := n.peers.getByID(nodeID); ok && peer.finishedHandshake.GetValue() {
+			continue
+		}
+		if validatorOnly && !n.config.Validators.Contains(nodeID) {
+			continue
+		}
+
+		relayIDs := n.sentryIDs.List()
+		if len(relayIDs) == 0 {
+			for relayID, relayCertTime := range n.relays[nodeID] {
+				if !n.relayCertExpired(relayCertTime) {
+					relayIDs = append(relayIDs, relayID)
+				}
+			}
+		}
+		for _, relayID := range relayIDs {
+			relayPeer, ok := n.peers.getByID(relayID)
+			if !ok || !relayPeer.finishedHandshake.GetValue() {
+				continue
+			}
+			if relayPeers == nil {
+				relayPeers = make(map[ids.ShortID]*peer)
+			}
+			relayPeers[nodeID] = relayPeer
+			break
+		}
+	}
+	return relayPeers
+}
+
+// relay sends [msgBytes], which was sent by [from], to [nodeID] if we are
+// connected to it.
+// Assumes [n.stateLock] is not held.
+func (n *network) relay(nodeID, from ids.ShortID, msgBytes []byte) {
+	msg, err := n.mc.Relay(from, msgBytes)
+	if err != nil {
+		n.log.Debug("failed to build Relay message to %s: %s", nodeID.PrefixedString(constants.NodeIDPrefix), err)
+		return
+	}
+	nodeIDs := ids.NewShortSet(1)
+	nodeIDs.Add(nodeID)
+	if sentTo := n.send(msg, true, n.getPeers(nodeIDs, constants.PrimaryNetworkID, false)); sentTo.Len() == 0 {
+		n.log.Debug("failed to relay message from %s to %s",
+			from.PrefixedString(constants.NodeIDPrefix),
+			nodeID.PrefixedString(constants.NodeIDPrefix),
+		)
+	}
+}
+
+// gossipOnBehalf gossips [msgBytes], which was gossiped by the validator
+// [from], to the peers of this sentry.
+// Assumes [n.stateLock] is not held.
+func (n *network) gossipOnBehalf(from ids.ShortID, op message.Op, msgBytes []byte) {
+	numValidatorsToSend, numNonValidatorsToSend := 0, int(n.config.GossipOnAcceptSize)
+	if op == message.AppGossip {
+		numValidatorsToSend = int(n.config.AppGossipValidatorSize)
+		numNonValidatorsToSend = int(n.config.AppGossipNonValidatorSize)
+	}
+	sampledPeers, err := n.selectPeersForGossip(constants.PrimaryNetworkID, false, numValidatorsToSend, numNonValidatorsToSend)
+	if err != nil {
+		n.log.Debug("failed to sample peers to gossip on behalf of %s: %s", from.PrefixedString(constants.NodeIDPrefix), err)
+		return
+	}
+	peers := make([]*peer, 0, len(sampledPeers))
+	n.stateLock.RLock()
+	for _, peer := range sampledPeers {
+		if peer != nil && !n.privatePeerIDs.Contains(peer.nodeID) {
+			peers = append(peers, peer)
+		}
+	}
+	n.stateLock.RUnlock()
+
+	msg, err := n.mc.Relay(from, msgBytes)
+	if err != nil {
+		n.log.Debug("failed to build Relay message to gossip on behalf of %s: %s", from.PrefixedString(constants.NodeIDPrefix), err)
+		return
+	}
+	n.send(msg, true, peers)
+}
+
+// assumes the [stateLock] is not held
+func (p *peer) sendRelayCert() {
+	myTime := p.net.clock.Unix()
+	msgHash := hashing.ComputeHash256(relayCertBytes(p.nodeID, myTime))
+	sig, err := p.net.config.TLSKey.Sign(cryptorand.Reader, msgHash, crypto.SHA256)
+	if err != nil {
+		p.net.log.Warn("failed to sign relay certificate for %s%s: %s", constants.NodeIDPrefix, p.nodeID, err)
+		return
+	}
+	p.sendSignedRelayCert(signedRelayCert{
+		cert:      p.net.myCert,
+		version:   p.net.versionCompatibility.Version().String(),
+		time:      myTime,
+		signature: sig,
+	})
+}
+
+// assumes the [stateLock] is not held
+func (p *peer) sendSignedRelayCert(relayCert signedRelayCert) {
+	msg, err := p.net.mc.RelayCert(relayCert.cert, relayCert.version, relayCert.time, relayCert.signature)
+	if err != nil {
+		p.net.log.Warn("failed to build RelayCert message to %s%s: %s", constants.NodeIDPrefix, p.nodeID, err)
+		return
+	}
+	p.net.send(msg, true, []*peer{p})
+}
+
+// assumes the [stateLock] is not held
+func (p *peer) handleRelayCert(msg message.InboundMessage) {
+	relayCert := signedRelayCert{
+		version:   msg.Get(message.VersionStr).(string),
+		time:      msg.Get(message.MyTime).(uint64),
+		signature: msg.Get(message.SigBytes).([]byte),
+	}
+	relayCert.cert, _ = msg.Get(message.Certificate).(*x509.Certificate)
+	if relayCert.cert == nil {
+		p.net.log.Debug("dropping RelayCert without a certificate from %s%s at %s", constants.NodeIDPrefix, p.nodeID, p.getIP())
+		p.net.reputation.Penalize(p.nodeID, reputation.MalformedMessage)
+		return
+	}
+	nodeID := certToID(relayCert.cert)
+
+	// The certificate authorizes us if it was sent by the validator, or the
+	// peer that sent it otherwise.
+	sentryID := p.nodeID
+	if nodeID == p.nodeID {
+		sentryID = p.net.config.MyNodeID
+	}
+	signed := relayCertBytes(sentryID, relayCert.time)
+	if err := relayCert.cert.CheckSignature(relayCert.cert.SignatureAlgorithm, signed, relayCert.signature); err != nil {
+		p.net.log.Debug("relay certificate verification failed for %s from %s%s at %s: %s",
+			nodeID.PrefixedString(constants.NodeIDPrefix), constants.NodeIDPrefix, p.nodeID, p.getIP(), err,
+		)
+		p.net.reputation.Penalize(p.nodeID, reputation.MalformedMessage)
+		return
+	}
+	if float64(relayCert.time)-float64(p.net.clock.Unix()) > p.net.config.MaxClockDifference.Seconds() {
+		p.net.log.Debug("dropping relay certificate of %s with timestamp (%d) too far in the future",
+			nodeID.PrefixedString(constants.NodeIDPrefix), relayCert.time,
+		)
+		return
+	}
+	if p.net.relayCertExpired(relayCert.time) {
+		p.net.log.Debug("dropping relay certificate of %s with expired timestamp (%d)",
+			nodeID.PrefixedString(constants.NodeIDPrefix), relayCert.time,
+		)
+		return
+	}
+
+	if nodeID == p.nodeID {
+		p.handlePrivatePeerRelayCert(relayCert)
+		return
+	}
+
+	peerVersion, err := p.net.parser.Parse(relayCert.version)
+	if err != nil {
+		p.net.log.Debug("version of %s relayed by %s%s could not be parsed: %s",
+			nodeID.PrefixedString(constants.NodeIDPrefix), constants.NodeIDPrefix, p.nodeID, err,
+		)
+		return
+	}
+	if err := p.net.versionCompatibility.Compatible(peerVersion); err != nil {
+		p.net.log.Verbo("version of %s relayed by %s%s (%s) not compatible: %s",
+			nodeID.PrefixedString(constants.NodeIDPrefix), constants.NodeIDPrefix, p.nodeID, peerVersion, err,
+		)
+		return
+	}
+
+	p.net.stateLock.Lock()
+	defer p.net.stateLock.Unlock()
+
+	switch {
+	case nodeID == p.net.config.MyNodeID:
+		return
+	case !p.net.config.Validators.Contains(nodeID):
+		p.net.log.Verbo("not relaying through %s%s to %s because it is not a validator",
+			constants.NodeIDPrefix, p.nodeID, nodeID.PrefixedString(constants.NodeIDPrefix),
+		)
+		return
+	case p.closed.GetValue():
+		return
+	}
+
+	relayIDs, reachable := p.net.relays[nodeID]
+	latest, relaying := relayIDs[p.nodeID]
+	if relaying && latest >= relayCert.time {
+		return
+	}
+	if !reachable {
+		relayIDs = make(map[ids.ShortID]uint64)
+		p.net.relays[nodeID] = relayIDs
+	}
+	relayIDs[p.nodeID] = relayCert.time
+	if relaying {
+		// The validator renewed the certificate of this relay
+		return
+	}
+	p.net.log.Debug("%s is reachable through %s%s",
+		nodeID.PrefixedString(constants.NodeIDPrefix), constants.NodeIDPrefix, p.nodeID,
+	)
+
+	if peer, connected := p.net.peers.getByID(nodeID); !reachable && (!connected || !peer.finishedHandshake.GetValue()) {
+		p.net.router.Connected(nodeID, peerVersion)
+		if p.net.config.UptimeTracker != nil {
+			p.net.config.UptimeTracker.Connect(nodeID)
+		}
+	}
+}
+
+// handlePrivatePeerRelayCert stores the certificate of a validator we are a
+// sentry for, and passes it on to our peers.
+// Assumes the [stateLock] is not held.
+func (p *peer) handlePrivatePeerRelayCert(relayCert signedRelayCert) {
+	p.net.stateLock.Lock()
+	defer p.net.stateLock.Unlock()
+
+	if !p.net.privatePeerIDs.Contains(p.nodeID) {
+		p.net.log.Debug("dropping relay certificate from %s%s because we aren't its sentry", constants.NodeIDPrefix, p.nodeID)
+		return
+	}
+	if latest, ok := p.net.relayCerts[p.nodeID]; ok && latest.time >= relayCert.time {
+		return
+	}
+	p.net.relayCerts[p.nodeID] = relayCert
+
+	for _, peer := range p.net.peers.peersList {
+		if peer != nil && peer.finishedHandshake.GetValue() && !p.net.privatePeerIDs.Contains(peer.nodeID) {
+			go peer.sendSignedRelayCert(relayCert)
+		}
+	}
+}
+
+// handleRelay takes ownership of [msg].
+// Assumes the [stateLock] is not held.
+func (p *peer) handleRelay(msg message.InboundMessage) {
+	nodeID, err := ids.ToShortID(msg.Get(message.PeerID).([]byte))
+	p.net.log.AssertNoError(err)
+	msgBytes := msg.Get(message.RelayedMsg).([]byte)
+
+	op, ok := relayedOp(msgBytes)
+	if !ok {
+		p.net.log.Debug("dropping Relay of unrelayable message from %s%s at %s", constants.NodeIDPrefix, p.nodeID, p.getIP())
+		p.net.reputation.Penalize(p.nodeID, reputation.MalformedMessage)
+		msg.OnFinishedHandling()
+		return
+	}
+
+	p.net.stateLock.RLock()
+	fromPrivatePeer := p.net.privatePeerIDs.Contains(p.nodeID)
+	toPrivatePeer := p.net.privatePeerIDs.Contains(nodeID)
+	relayCertTime, relaying := p.net.relays[nodeID][p.nodeID]
+	trusted := p.net.sentryIDs.Contains(p.nodeID) || (relaying && !p.net.relayCertExpired(relayCertTime))
+	p.net.stateLock.RUnlock()
+
+	switch {
+	case fromPrivatePeer && nodeID == ids.ShortEmpty:
+		// The validator gossiped the message through us
+		if isGossipedOnBehalf(op) {
+			p.net.gossipOnBehalf(p.nodeID, op, msgBytes)
+		}
+		p.deliverRelayed(p.nodeID, msgBytes, msg)
+		return
+	case fromPrivatePeer:
+		// The validator sent the message through us
+		p.net.relay(nodeID, p.nodeID, msgBytes)
+		msg.OnFinishedHandling()
+		return
+	case toPrivatePeer:
+		// The peer sent the message to the validator through us
+		p.net.relay(nodeID, p.nodeID, msgBytes)
+		msg.OnFinishedHandling()
+		return
+	}
+
+	if !trusted || nodeID == ids.ShortEmpty {
+		p.net.log.Debug("dropping message relayed from %s by unauthorized %s%s at %s",
+			nodeID.PrefixedString(constants.NodeIDPrefix), constants.NodeIDPrefix, p.nodeID, p.getIP(),
+		)
+		msg.OnFinishedHandling()
+		return
+	}
+	p.deliverRelayed(nodeID, msgBytes, msg)
+}
+
+// deliverRelayed passes [msgBytes], which was sent by [nodeID] and relayed in
+// [msg], to the router. Takes ownership of [msg].
+// Assumes the [stateLock] is not held.
+func (p *peer) deliverRelayed(nodeID ids.ShortID, msgBytes []byte, msg message.InboundMessage) {
+	relayedMsg, err := p.net.mc.Parse(msgBytes, nodeID, msg.OnFinishedHandling)
+	if err != nil {
+		p.net.log.Verbo("failed to parse message from %s relayed by %s%s at %s: %s",
+			nodeID.PrefixedString(constants.NodeIDPrefix), constants.NodeIDPrefix, p.nodeID, p.getIP(), err,
+		)
+		p.net.metrics.failedToParse.Inc()
+		msg.OnFinishedHandling()
+		return
+	}
+	p.net.router.HandleInbound(relayedMsg)
+}
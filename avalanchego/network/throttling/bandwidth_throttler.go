@@ -27,7 +27,9 @@ type BandwidthThrottler interface {
 	// AddNode([nodeID], ...) must have been called since
 	// the last time RemoveNode([nodeID]) was called, if any.
 	// It's safe for multiple goroutines to concurrently call Acquire.
-	Acquire(msgSize uint64, nodeID ids.ShortID)
+	// Returns true if [nodeID] had to wait for its bandwidth allocation to
+	// refill.
+	Acquire(msgSize uint64, nodeID ids.ShortID) bool
 
 	// Add a new node to this throttler.
 	// Must be called before Acquire(..., [nodeID]) is called.
@@ -100,7 +102,7 @@ type bandwidthThrottler struct {
 }
 
 // See BandwidthThrottler.
-func (t *bandwidthThrottler) Acquire(msgSize uint64, nodeID ids.ShortID) {
+func (t *bandwidthThrottler) Acquire(msgSize uint64, nodeID ids.ShortID) bool {
 	startTime := time.Now()
 	defer func() {
 		t.metrics.acquireLatency.Observe(float64(time.Since(startTime)))
@@ -114,13 +116,17 @@ func (t *bandwidthThrottler) Acquire(msgSize uint64, nodeID ids.ShortID) {
 	if !ok {
 		// This should never happen. If it is, the caller is misusing this struct.
 		t.log.Debug("tried to acquire %d bytes for %s but that node isn't registered", msgSize, nodeID.PrefixedString(constants.NodeIDPrefix))
-		return
+		return false
+	}
+	if limiter.AllowN(time.Now(), int(msgSize)) {
+		return false
 	}
 	// TODO Allow cancellation using context?
 	if err := limiter.WaitN(context.Background(), int(msgSize)); err != nil {
 		// This should never happen.
 		t.log.Warn("error while awaiting %d bytes for %s: %s", msgSize, nodeID.PrefixedString(constants.NodeIDPrefix), err)
 	}
+	return true
 }
 
 // See BandwidthThrottler.
@@ -44,7 +44,7 @@ func TestBandwidthThrottler(t *testing.T) {
 	assert.Len(throttler.limiters, 1)
 
 	// Should be able to acquire 8
-	throttler.Acquire(8, nodeID1)
+	assert.False(throttler.Acquire(8, nodeID1))
 
 	// Make several goroutines that acquire bytes.
 	wg := sync.WaitGroup{}
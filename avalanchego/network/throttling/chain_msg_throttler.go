@@ -0,0 +1,212 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package throttling
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+
+	"github.com/flare-foundation/flare/message"
+	"github.com/flare-foundation/flare/utils/constants"
+	"github.com/flare-foundation/flare/utils/wrappers"
+)
+
+var (
+	_ ChainMsgThrottler = &chainMsgThrottler{}
+	_ ChainMsgThrottler = &noChainMsgThrottler{}
+)
+
+// OpClass is a group of ops that share a throttling bucket
+type OpClass byte
+
+const (
+	// ConsensusOpClass contains the bootstrapping and consensus ops
+	ConsensusOpClass OpClass = iota
+	// AppOpClass contains the application level ops
+	AppOpClass
+)
+
+// OpClassOf returns the class [op] is throttled as
+func OpClassOf(op message.Op) OpClass {
+	switch op {
+	case message.AppRequest, message.AppResponse, message.AppGossip:
+		return AppOpClass
+	default:
+		return ConsensusOpClass
+	}
+}
+
+func (c OpClass) String() string {
+	switch c {
+	case ConsensusOpClass:
+		return "consensus"
+	case AppOpClass:
+		return "app"
+	default:
+		return "unknown"
+	}
+}
+
+// ChainMsgThrottler rate-limits the inbound messages of a single chain.
+// Unlike the InboundMsgThrottler, it doesn't block. Messages that exceed
+// the chain's allocation should be dropped.
+type ChainMsgThrottler interface {
+	// Returns true if a [msgSize] byte message with [op] may be handled now.
+	// It's safe for multiple goroutines to concurrently call Allow.
+	Allow(op message.Op, msgSize uint64) bool
+}
+
+type ChainMsgThrottlerConfig struct {
+	// Rate, in bytes per second, at which the bandwidth available to the
+	// chain's messages refills. If 0, bandwidth isn't throttled.
+	BandwidthRefillRate uint64 `json:"bandwidthRefillRate"`
+	// Max amount of bandwidth that can accumulate for the chain
+	BandwidthMaxBurstSize uint64 `json:"bandwidthMaxBurstSize"`
+	// Rate, in messages per second, at which the number of messages the
+	// chain may handle refills. If 0, the message rate isn't throttled.
+	MsgRefillRate uint64 `json:"msgRefillRate"`
+	// Max number of messages that can accumulate for the chain
+	MsgMaxBurstSize uint64 `json:"msgMaxBurstSize"`
+	// Portion of the bandwidth and messages reserved for the consensus op
+	// class. The app op class may only use the rest, while the consensus op
+	// class may use both.
+	ConsensusReservedPortion float64 `json:"consensusReservedPortion"`
+}
+
+// Valid returns an error if the config can't be used to create a throttler
+func (c ChainMsgThrottlerConfig) Valid() error {
+	switch {
+	case c.ConsensusReservedPortion < 0 || c.ConsensusReservedPortion > 1:
+		return fmt.Errorf("consensus reserved portion (%f) must be in [0,1]", c.ConsensusReservedPortion)
+	case c.BandwidthRefillRate != 0 && c.BandwidthMaxBurstSize < uint64(constants.DefaultMaxMessageSize):
+		return fmt.Errorf("bandwidth max burst size (%d) must be at least the max message size (%d)", c.BandwidthMaxBurstSize, constants.DefaultMaxMessageSize)
+	case c.MsgRefillRate != 0 && c.MsgMaxBurstSize == 0:
+		return fmt.Errorf("message max burst size must be > 0")
+	default:
+		return nil
+	}
+}
+
+// NewChainMsgThrottler returns a throttler that limits the messages of a
+// chain as specified by [config]. Drops are reported under [namespace].
+func NewChainMsgThrottler(
+	namespace string,
+	registerer prometheus.Registerer,
+	config ChainMsgThrottlerConfig,
+) (ChainMsgThrottler, error) {
+	if config.BandwidthRefillRate == 0 && config.MsgRefillRate == 0 {
+		return &noChainMsgThrottler{}, nil
+	}
+	if err := config.Valid(); err != nil {
+		return nil, err
+	}
+
+	t := &chainMsgThrottler{
+		shared: newBucket(config, 1-config.ConsensusReservedPortion),
+		dropped: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "chain_msg_throttler_dropped",
+			Help:      "Number of inbound messages dropped because the chain's bandwidth or message rate allocation was exhausted",
+		}, []string{"op"}),
+	}
+	if config.ConsensusReservedPortion > 0 {
+		t.reserved = newBucket(config, config.ConsensusReservedPortion)
+	}
+
+	errs := wrappers.Errs{}
+	errs.Add(registerer.Register(t.dropped))
+	return t, errs.Err
+}
+
+type chainMsgThrottler struct {
+	// Capacity only the consensus op class may use. nil if none is reserved.
+	reserved *bucket
+	// Capacity every op class may use
+	shared *bucket
+
+	dropped *prometheus.CounterVec
+}
+
+// See ChainMsgThrottler
+func (t *chainMsgThrottler) Allow(op message.Op, msgSize uint64) bool {
+	now := time.Now()
+	if OpClassOf(op) == ConsensusOpClass && t.reserved != nil && t.reserved.allow(now, msgSize) {
+		return true
+	}
+	if t.shared.allow(now, msgSize) {
+		return true
+	}
+	t.dropped.WithLabelValues(op.String()).Inc()
+	return false
+}
+
+// bucket holds the token buckets that limit the bytes and the number of
+// messages handled using some portion of a chain's allocation
+type bucket struct {
+	// nil if bandwidth isn't throttled
+	bandwidth *rate.Limiter
+	// nil if the message rate isn't throttled
+	msgs *rate.Limiter
+}
+
+// newBucket returns a bucket with [portion] of the allocation in [config]
+func newBucket(config ChainMsgThrottlerConfig, portion float64) *bucket {
+	b := &bucket{}
+	if config.BandwidthRefillRate != 0 {
+		b.bandwidth = rate.NewLimiter(
+			rate.Limit(portion*float64(config.BandwidthRefillRate)),
+			int(portion*float64(config.BandwidthMaxBurstSize)),
+		)
+	}
+	if config.MsgRefillRate != 0 {
+		b.msgs = rate.NewLimiter(
+			rate.Limit(portion*float64(config.MsgRefillRate)),
+			int(portion*float64(config.MsgMaxBurstSize)),
+		)
+	}
+	return b
+}
+
+// allow takes a [msgSize] byte message from this bucket, if there is room for
+// it at [now]
+func (b *bucket) allow(now time.Time, msgSize uint64) bool {
+	var bandwidthReservation *rate.Reservation
+	if b.bandwidth != nil {
+		bandwidthReservation = take(b.bandwidth, now, int(msgSize))
+		if bandwidthReservation == nil {
+			return false
+		}
+	}
+	if b.msgs != nil && take(b.msgs, now, 1) == nil {
+		// Give back the bandwidth of the message that won't be handled
+		if bandwidthReservation != nil {
+			bandwidthReservation.CancelAt(now)
+		}
+		return false
+	}
+	return true
+}
+
+// take removes [n] tokens from [limiter] if they are available at [now]. The
+// returned reservation can be used to put them back. Returns nil if the tokens
+// aren't available.
+func take(limiter *rate.Limiter, now time.Time, n int) *rate.Reservation {
+	reservation := limiter.ReserveN(now, n)
+	if !reservation.OK() {
+		return nil
+	}
+	if reservation.DelayFrom(now) > 0 {
+		reservation.CancelAt(now)
+		return nil
+	}
+	return reservation
+}
+
+// noChainMsgThrottler allows every message
+type noChainMsgThrottler struct{}
+
+func (*noChainMsgThrottler) Allow(message.Op, uint64) bool { return true }
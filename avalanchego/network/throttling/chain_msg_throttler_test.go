@@ -0,0 +1,146 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package throttling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/time/rate"
+
+	"github.com/flare-foundation/flare/message"
+	"github.com/flare-foundation/flare/utils/constants"
+	"github.com/flare-foundation/flare/utils/units"
+)
+
+func TestChainMsgThrottlerDisabled(t *testing.T) {
+	throttler, err := NewChainMsgThrottler("", prometheus.NewRegistry(), ChainMsgThrottlerConfig{})
+	assert.NoError(t, err)
+	for i := 0; i < 1024; i++ {
+		assert.True(t, throttler.Allow(message.AppGossip, units.MiB))
+	}
+}
+
+func TestChainMsgThrottlerMsgRate(t *testing.T) {
+	throttlerIntf, err := NewChainMsgThrottler("", prometheus.NewRegistry(), ChainMsgThrottlerConfig{
+		MsgRefillRate:   1,
+		MsgMaxBurstSize: 4,
+	})
+	assert.NoError(t, err)
+	throttler := throttlerIntf.(*chainMsgThrottler)
+
+	// Nothing is reserved, so every op class shares the burst
+	for i := 0; i < 2; i++ {
+		assert.True(t, throttler.Allow(message.AppGossip, 0))
+		assert.True(t, throttler.Allow(message.PushQuery, 0))
+	}
+	assert.False(t, throttler.Allow(message.AppGossip, 0))
+	assert.False(t, throttler.Allow(message.PushQuery, 0))
+
+	assert.EqualValues(t, 1, testutil.ToFloat64(throttler.dropped.WithLabelValues(message.AppGossip.String())))
+	assert.EqualValues(t, 1, testutil.ToFloat64(throttler.dropped.WithLabelValues(message.PushQuery.String())))
+}
+
+func TestChainMsgThrottlerConsensusReserved(t *testing.T) {
+	throttler, err := NewChainMsgThrottler("", prometheus.NewRegistry(), ChainMsgThrottlerConfig{
+		BandwidthRefillRate:      1,
+		BandwidthMaxBurstSize:    4 * units.MiB,
+		MsgRefillRate:            1,
+		MsgMaxBurstSize:          1000,
+		ConsensusReservedPortion: 0.5,
+	})
+	assert.NoError(t, err)
+
+	// App messages exhaust the shared bandwidth
+	assert.True(t, throttler.Allow(message.AppGossip, units.MiB))
+	assert.True(t, throttler.Allow(message.AppGossip, units.MiB))
+	assert.False(t, throttler.Allow(message.AppGossip, units.MiB))
+	assert.False(t, throttler.Allow(message.AppRequest, units.KiB))
+
+	// Consensus messages still have their reserved bandwidth
+	assert.True(t, throttler.Allow(message.Put, units.MiB))
+	assert.True(t, throttler.Allow(message.PushQuery, units.MiB))
+	assert.False(t, throttler.Allow(message.PushQuery, units.MiB))
+}
+
+func TestChainMsgThrottlerRefundsBandwidth(t *testing.T) {
+	b := newBucket(ChainMsgThrottlerConfig{
+		BandwidthRefillRate:   1,
+		BandwidthMaxBurstSize: 4 * units.MiB,
+		MsgRefillRate:         1,
+		MsgMaxBurstSize:       1,
+	}, 1)
+	now := time.Now()
+
+	assert.True(t, b.allow(now, 3*units.MiB))
+	// Dropped because of the message rate, so the bandwidth isn't used
+	assert.False(t, b.allow(now, units.MiB))
+
+	b.msgs = rate.NewLimiter(rate.Inf, 1)
+	assert.True(t, b.allow(now, units.MiB))
+	assert.False(t, b.allow(now, 1))
+}
+
+func TestChainMsgThrottlerConfigValid(t *testing.T) {
+	tests := []struct {
+		name   string
+		config ChainMsgThrottlerConfig
+		valid  bool
+	}{
+		{
+			name:   "disabled",
+			config: ChainMsgThrottlerConfig{},
+			valid:  true,
+		},
+		{
+			name: "valid",
+			config: ChainMsgThrottlerConfig{
+				BandwidthRefillRate:      units.MiB,
+				BandwidthMaxBurstSize:    uint64(constants.DefaultMaxMessageSize),
+				MsgRefillRate:            1,
+				MsgMaxBurstSize:          1,
+				ConsensusReservedPortion: 1,
+			},
+			valid: true,
+		},
+		{
+			name: "negative reserved portion",
+			config: ChainMsgThrottlerConfig{
+				ConsensusReservedPortion: -0.1,
+			},
+		},
+		{
+			name: "reserved portion > 1",
+			config: ChainMsgThrottlerConfig{
+				ConsensusReservedPortion: 1.1,
+			},
+		},
+		{
+			name: "burst smaller than a message",
+			config: ChainMsgThrottlerConfig{
+				BandwidthRefillRate:   units.MiB,
+				BandwidthMaxBurstSize: uint64(constants.DefaultMaxMessageSize) - 1,
+			},
+		},
+		{
+			name: "no message burst",
+			config: ChainMsgThrottlerConfig{
+				MsgRefillRate: 1,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.config.Valid()
+			if test.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
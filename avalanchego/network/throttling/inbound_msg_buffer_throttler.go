@@ -58,7 +58,9 @@ type inboundMsgBufferThrottler struct {
 // buffer so that we can read a message from [nodeID].
 // Release([nodeID]) must be called (!) when done processing the message
 // (or when we give up trying to read the message.)
-func (t *inboundMsgBufferThrottler) Acquire(nodeID ids.ShortID) {
+// Returns true if we had to wait because we were processing the maximum
+// number of messages from [nodeID].
+func (t *inboundMsgBufferThrottler) Acquire(nodeID ids.ShortID) bool {
 	startTime := time.Now()
 	defer func() {
 		t.metrics.acquireLatency.Observe(float64(time.Since(startTime)))
@@ -68,7 +70,7 @@ func (t *inboundMsgBufferThrottler) Acquire(nodeID ids.ShortID) {
 	if t.nodeToNumProcessingMsgs[nodeID] < t.maxProcessingMsgsPerNode {
 		t.nodeToNumProcessingMsgs[nodeID]++
 		t.lock.Unlock()
-		return
+		return false
 	}
 
 	// We're currently processing the maximum number of
@@ -83,6 +85,7 @@ func (t *inboundMsgBufferThrottler) Acquire(nodeID ids.ShortID) {
 	t.metrics.awaitingAcquire.Inc()
 	<-closeOnAcquireChan
 	t.metrics.awaitingAcquire.Dec()
+	return true
 }
 
 // Release marks that we've finished processing a message from [nodeID]
@@ -20,9 +20,9 @@ func TestMsgBufferThrottler(t *testing.T) {
 
 	nodeID1, nodeID2 := ids.GenerateTestShortID(), ids.GenerateTestShortID()
 	// Acquire shouldn't block for first 3
-	throttler.Acquire(nodeID1)
-	throttler.Acquire(nodeID1)
-	throttler.Acquire(nodeID1)
+	assert.False(throttler.Acquire(nodeID1))
+	assert.False(throttler.Acquire(nodeID1))
+	assert.False(throttler.Acquire(nodeID1))
 	assert.Len(throttler.nodeToNumProcessingMsgs, 1)
 	assert.EqualValues(3, throttler.nodeToNumProcessingMsgs[nodeID1])
 
@@ -37,7 +37,7 @@ func TestMsgBufferThrottler(t *testing.T) {
 	// Acquire should block for 4th acquire
 	done := make(chan struct{})
 	go func() {
-		throttler.Acquire(nodeID1)
+		assert.True(throttler.Acquire(nodeID1))
 		done <- struct{}{}
 	}()
 	select {
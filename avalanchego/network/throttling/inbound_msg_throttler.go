@@ -20,7 +20,9 @@ type InboundMsgThrottler interface {
 	// For every call to Acquire([msgSize], [nodeID]), we must (!) call
 	// Release([msgSize], [nodeID]) when done processing the message
 	// (or when we give up trying to read the message.)
-	Acquire(msgSize uint64, nodeID ids.ShortID)
+	// Returns true if we had to wait because [nodeID] sent us more messages,
+	// or more bytes, than it's allowed to.
+	Acquire(msgSize uint64, nodeID ids.ShortID) bool
 
 	// Mark that we're done processing a message of size [msgSize]
 	// from [nodeID].
@@ -101,13 +103,14 @@ type inboundMsgThrottler struct {
 // Returns when we can read a message of size [msgSize] from node [nodeID].
 // Release([msgSize], [nodeID]) must be called (!) when done with the message
 // or when we give up trying to read the message, if applicable.
-func (t *inboundMsgThrottler) Acquire(msgSize uint64, nodeID ids.ShortID) {
+func (t *inboundMsgThrottler) Acquire(msgSize uint64, nodeID ids.ShortID) bool {
 	// Acquire space on the inbound message buffer
-	t.bufferThrottler.Acquire(nodeID)
+	throttled := t.bufferThrottler.Acquire(nodeID)
 	// Acquire bandwidth
-	t.bandwidthThrottler.Acquire(msgSize, nodeID)
+	throttled = t.bandwidthThrottler.Acquire(msgSize, nodeID) || throttled
 	// Acquire space on the inbound message byte buffer
 	t.byteThrottler.Acquire(msgSize, nodeID)
+	return throttled
 }
 
 // Must correspond to a previous call of Acquire([msgSize], [nodeID]).
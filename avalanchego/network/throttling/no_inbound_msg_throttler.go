@@ -16,7 +16,7 @@ func NewNoInboundThrottler() InboundMsgThrottler {
 // [Acquire] always returns immediately.
 type noInboundMsgThrottler struct{}
 
-func (*noInboundMsgThrottler) Acquire(uint64, ids.ShortID) {}
+func (*noInboundMsgThrottler) Acquire(uint64, ids.ShortID) bool { return false }
 
 func (*noInboundMsgThrottler) Release(uint64, ids.ShortID) {}
 
@@ -12,7 +12,10 @@ import (
 	"github.com/flare-foundation/flare/ids"
 	"github.com/flare-foundation/flare/nat"
 	"github.com/flare-foundation/flare/network"
+	"github.com/flare-foundation/flare/network/throttling"
+	"github.com/flare-foundation/flare/replica"
 	"github.com/flare-foundation/flare/snow/consensus/avalanche"
+	"github.com/flare-foundation/flare/snow/engine/snowman/bootstrap"
 	"github.com/flare-foundation/flare/snow/networking/benchlist"
 	"github.com/flare-foundation/flare/snow/networking/router"
 	"github.com/flare-foundation/flare/utils"
@@ -21,6 +24,7 @@ import (
 	"github.com/flare-foundation/flare/utils/profiler"
 	"github.com/flare-foundation/flare/utils/timer"
 	"github.com/flare-foundation/flare/vms"
+	"github.com/flare-foundation/flare/vms/components/keystore/gsigner"
 )
 
 type IPCConfig struct {
@@ -47,6 +51,15 @@ type HTTPConfig struct {
 	HTTPSEnabled bool   `json:"httpsEnabled"`
 	HTTPSKey     []byte `json:"-"`
 	HTTPSCert    []byte `json:"-"`
+	// Files the HTTPs key and certificate were read from, if any. The files
+	// are reloaded when they are modified.
+	HTTPSKeyFile  string `json:"httpsKeyFile"`
+	HTTPSCertFile string `json:"httpsCertFile"`
+
+	// CAs that sign the certificates that clients must present
+	HTTPSClientCAFile string `json:"httpsClientCAFile"`
+	// Scopes of API access granted to client certificates
+	HTTPSClientScopesFile string `json:"httpsClientScopesFile"`
 
 	APIAllowedOrigins []string `json:"apiAllowedOrigins"`
 
@@ -111,6 +124,9 @@ type BootstrapConfig struct {
 
 	BootstrapIDs []ids.ShortID  `json:"bootstrapIDs"`
 	BootstrapIPs []utils.IPDesc `json:"bootstrapIPs"`
+
+	// Chain ID --> Block trusted to be accepted on that chain
+	BootstrapCheckpoints map[ids.ID]bootstrap.Checkpoint `json:"bootstrapCheckpoints"`
 }
 
 type DatabaseConfig struct {
@@ -122,6 +138,10 @@ type DatabaseConfig struct {
 
 	// Path to config file
 	Config []byte `json:"-"`
+
+	// Name of the database type to copy into this database when it doesn't
+	// exist yet. Empty if nothing should be copied.
+	MigrateFrom string `json:"migrateFrom"`
 }
 
 // Config contains all of the configurations of an Avalanche node.
@@ -152,6 +172,16 @@ type Config struct {
 	// Network configuration
 	NetworkConfig network.Config `json:"networkConfig"`
 
+	// File the peer filter of the network is reloaded from, if any
+	PeerFilterFile string `json:"peerFilterFile"`
+
+	// If enabled, this node follows a primary instead of the network
+	ReplicaConfig replica.Config `json:"replicaConfig"`
+
+	// If enabled, the transactions issued through the keystore APIs are
+	// signed by a remote signer
+	RemoteSignerConfig gsigner.Config `json:"remoteSignerConfig"`
+
 	AdaptiveTimeoutConfig timer.AdaptiveTimeoutConfig `json:"adaptiveTimeoutConfig"`
 
 	// Benchlist Configuration
@@ -176,6 +206,10 @@ type Config struct {
 	ConsensusRouter          router.Router       `json:"-"`
 	RouterHealthConfig       router.HealthConfig `json:"routerHealthConfig"`
 	ConsensusShutdownTimeout time.Duration       `json:"consensusShutdownTimeout"`
+	// Number of polls of each snowman chain kept in the poll journal
+	ConsensusPollJournalSize uint64 `json:"consensusPollJournalSize"`
+	// Limits the unrequested inbound messages of each chain
+	ChainMsgThrottlerConfig throttling.ChainMsgThrottlerConfig `json:"chainMsgThrottlerConfig"`
 	// Gossip a container in the accepted frontier every [ConsensusGossipFrequency]
 	ConsensusGossipFrequency time.Duration `json:"consensusGossipFreq"`
 
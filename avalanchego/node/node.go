@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"path/filepath"
 	"sync"
@@ -39,8 +40,10 @@ import (
 	"github.com/flare-foundation/flare/message"
 	"github.com/flare-foundation/flare/network"
 	"github.com/flare-foundation/flare/network/throttling"
+	"github.com/flare-foundation/flare/replica"
 	"github.com/flare-foundation/flare/snow/engine/common"
 	"github.com/flare-foundation/flare/snow/networking/benchlist"
+	"github.com/flare-foundation/flare/snow/networking/reputation"
 	"github.com/flare-foundation/flare/snow/networking/router"
 	"github.com/flare-foundation/flare/snow/networking/timeout"
 	"github.com/flare-foundation/flare/snow/triggers"
@@ -56,6 +59,7 @@ import (
 	"github.com/flare-foundation/flare/utils/wrappers"
 	"github.com/flare-foundation/flare/version"
 	"github.com/flare-foundation/flare/vms/avm"
+	"github.com/flare-foundation/flare/vms/components/keystore/gsigner"
 	"github.com/flare-foundation/flare/vms/nftfx"
 	"github.com/flare-foundation/flare/vms/platformvm"
 	"github.com/flare-foundation/flare/vms/propertyfx"
@@ -63,11 +67,19 @@ import (
 	"github.com/flare-foundation/flare/vms/secp256k1fx"
 
 	ipcsapi "github.com/flare-foundation/flare/api/ipcs"
+	vmkeystore "github.com/flare-foundation/flare/vms/components/keystore"
 )
 
+// Frequency at which the validators' uptimes are persisted
+const uptimeFlushFrequency = time.Minute
+
 var (
-	genesisHashKey  = []byte("genesisID")
-	indexerDBPrefix = []byte{0x00}
+	genesisHashKey    = []byte("genesisID")
+	indexerDBPrefix   = []byte{0x00}
+	benchlistDBPrefix = []byte("benchlist")
+	uptimeDBPrefix    = []byte("uptime")
+	replicaDBPrefix   = []byte("replica")
+	authDBPrefix      = []byte("auth")
 
 	errInvalidTLSKey   = errors.New("invalid TLS key")
 	errPNotCreated     = errors.New("P-Chain not created")
@@ -76,6 +88,7 @@ var (
 	errNotBootstrapped = errors.New("primary subnet has not finished bootstrapping")
 	errShuttingDown    = errors.New("server shutting down")
 	errNoValidators    = errors.New("no validators defined for network")
+	errListenerClosed  = errors.New("listener closed")
 )
 
 // Node is an instance of an Avalanche node.
@@ -98,6 +111,12 @@ type Node struct {
 	// Indexes blocks, transactions and blocks
 	indexer indexer.Indexer
 
+	// Follows the primary. Nil if this node isn't a replica.
+	replica *replica.Replica
+
+	// Connection to the remote signer. Nil if no remote signer is used.
+	remoteSignerConn io.Closer
+
 	// Handles calls to Keystore API
 	keystore keystore.Keystore
 
@@ -118,6 +137,11 @@ type Node struct {
 
 	uptimeCalculator uptime.LockedCalculator
 
+	// Tracks the uptime of the validators in [vdrs]
+	uptimeTracker uptime.WindowTracker
+	// Periodically persists the tracked uptimes
+	uptimeFlusher *timer.Repeater
+
 	// dispatcher for events as they happen in consensus
 	DecisionDispatcher  *triggers.EventDispatcher
 	ConsensusDispatcher *triggers.EventDispatcher
@@ -165,9 +189,18 @@ type Node struct {
  */
 
 func (n *Node) initNetworking() error {
-	listener, err := net.Listen(constants.NetworkType, fmt.Sprintf(":%d", n.Config.IP.Port))
-	if err != nil {
-		return err
+	var (
+		listener net.Listener
+		err      error
+	)
+	if n.Config.ReplicaConfig.Enabled() {
+		// A replica doesn't accept connections from peers
+		listener = newIdleListener(n.Config.IP.IP())
+	} else {
+		listener, err = net.Listen(constants.NetworkType, fmt.Sprintf(":%d", n.Config.IP.Port))
+		if err != nil {
+			return err
+		}
 	}
 	// Wrap listener so it will only accept a certain number of incoming connections per second
 	listener = throttling.NewThrottledListener(listener, n.Config.NetworkConfig.ThrottlerConfig.MaxIncomingConnsPerSec)
@@ -201,10 +234,27 @@ func (n *Node) initNetworking() error {
 	n.Config.BenchlistConfig.Validators = n.vdrs
 	n.Config.BenchlistConfig.Benchable = n.Config.ConsensusRouter
 	n.Config.BenchlistConfig.StakingEnabled = n.Config.EnableStaking
+	n.Config.BenchlistConfig.DB = prefixdb.New(benchlistDBPrefix, n.DB)
 	n.benchlistManager = benchlist.NewManager(&n.Config.BenchlistConfig)
 
 	n.uptimeCalculator = uptime.NewLockedCalculator()
 
+	validatorList := networkValidators.List()
+	validatorIDs := make([]ids.ShortID, len(validatorList))
+	for i, vdr := range validatorList {
+		validatorIDs[i] = vdr.ID()
+	}
+	n.uptimeTracker, err = uptime.NewWindowTracker(prefixdb.New(uptimeDBPrefix, n.DB), validatorIDs)
+	if err != nil {
+		return fmt.Errorf("couldn't initialize uptime tracker: %w", err)
+	}
+	n.uptimeFlusher = timer.NewRepeater(func() {
+		if err := n.uptimeTracker.Flush(); err != nil {
+			n.Log.Warn("couldn't persist validator uptimes: %s", err)
+		}
+	}, uptimeFlushFrequency)
+	go n.uptimeFlusher.Dispatch()
+
 	consensusRouter := n.Config.ConsensusRouter
 	if !n.Config.EnableStaking {
 		if err := networkValidators.AddWeight(n.ID, n.Config.DisabledStakingWeight); err != nil {
@@ -256,6 +306,7 @@ func (n *Node) initNetworking() error {
 	n.Config.NetworkConfig.WhitelistedSubnets = n.Config.WhitelistedSubnets
 	n.Config.NetworkConfig.UptimeCalculator = n.uptimeCalculator
 	n.Config.NetworkConfig.UptimeRequirement = n.Config.UptimeRequirement
+	n.Config.NetworkConfig.UptimeTracker = n.uptimeTracker
 
 	n.Net, err = network.NewNetwork(
 		&n.Config.NetworkConfig,
@@ -337,7 +388,13 @@ func (n *Node) Dispatch() error {
 		var err error
 		if n.Config.HTTPSEnabled {
 			n.Log.Debug("initializing API server with TLS")
-			err = n.APIServer.DispatchTLS(n.Config.HTTPSCert, n.Config.HTTPSKey)
+			err = n.APIServer.DispatchTLS(server.TLSConfig{
+				Cert:         n.Config.HTTPSCert,
+				Key:          n.Config.HTTPSKey,
+				CertFile:     n.Config.HTTPSCertFile,
+				KeyFile:      n.Config.HTTPSKeyFile,
+				ClientCAFile: n.Config.HTTPSClientCAFile,
+			})
 		} else {
 			n.Log.Debug("initializing API server without TLS")
 			err = n.APIServer.Dispatch()
@@ -353,13 +410,21 @@ func (n *Node) Dispatch() error {
 		n.Shutdown(1)
 	})
 
-	// Add bootstrap nodes to the peer network
+	// Add bootstrap nodes to the peer network. A replica has none.
 	for _, peerIP := range n.Config.BootstrapIPs {
 		if !peerIP.Equal(n.Config.IP.IP()) {
 			n.Net.TrackIP(peerIP)
 		}
 	}
 
+	// Add the sentries of this validator to the peer network
+	sentryConfig := n.Config.NetworkConfig.SentryConfig
+	if !n.Config.ReplicaConfig.Enabled() {
+		for i, sentryIP := range sentryConfig.SentryIPs {
+			n.Net.Track(sentryIP, sentryConfig.SentryIDs[i])
+		}
+	}
+
 	// Start P2P connections
 	err := n.Net.Dispatch()
 
@@ -372,6 +437,36 @@ func (n *Node) Dispatch() error {
 	return err
 }
 
+// idleListener is used in place of the staking listener of a replica. It never
+// accepts a connection.
+type idleListener struct {
+	addr      net.Addr
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+func newIdleListener(ip utils.IPDesc) net.Listener {
+	return &idleListener{
+		addr: &net.TCPAddr{
+			IP:   ip.IP,
+			Port: int(ip.Port),
+		},
+		closed: make(chan struct{}),
+	}
+}
+
+func (l *idleListener) Accept() (net.Conn, error) {
+	<-l.closed
+	return nil, errListenerClosed
+}
+
+func (l *idleListener) Close() error {
+	l.closeOnce.Do(func() { close(l.closed) })
+	return nil
+}
+
+func (l *idleListener) Addr() net.Addr { return l.addr }
+
 /*
  ******************************************************************************
  *********************** End P2P Networking Section ***************************
@@ -472,6 +567,27 @@ func (n *Node) initIndexer() error {
 	return nil
 }
 
+// Should only be called after [n.DB], [n.Log], [n.health] and
+// [n.chainManager] are initialized
+func (n *Node) initReplica() error {
+	if !n.Config.ReplicaConfig.Enabled() {
+		return nil
+	}
+	if !n.Config.IndexAPIEnabled {
+		n.Log.Warn("this node is a replica but its index API is disabled")
+	}
+	n.Log.Info("following the primary at %s", n.Config.ReplicaConfig.PrimaryURI)
+
+	n.replica = replica.New(n.Config.ReplicaConfig, prefixdb.New(replicaDBPrefix, n.DB), n.Log)
+	if err := n.health.RegisterHealthCheck("replica", health.CheckerFunc(n.replica.HealthCheck)); err != nil {
+		return fmt.Errorf("couldn't register replica health check: %w", err)
+	}
+
+	// Chain manager will notify the replica when a chain is created
+	n.chainManager.AddRegistrant(n.replica)
+	return nil
+}
+
 // Initializes the Platform chain.
 // Its genesis data specifies the other chains that should be created.
 func (n *Node) initChains(genesisBytes []byte) {
@@ -491,22 +607,27 @@ func (n *Node) initChains(genesisBytes []byte) {
 func (n *Node) initAPIServer() error {
 	n.Log.Info("initializing API server")
 
-	if !n.Config.APIRequireAuthToken {
-		n.APIServer.Initialize(
-			n.Log,
-			n.LogFactory,
-			n.Config.HTTPHost,
-			n.Config.HTTPPort,
-			n.Config.APIAllowedOrigins,
-			n.Config.ShutdownTimeout,
-			n.ID,
-		)
-		return nil
+	var (
+		a        auth.Auth
+		wrappers []server.Wrapper
+		err      error
+	)
+	if n.Config.APIRequireAuthToken {
+		a, err = auth.New(n.Log, "auth", n.Config.APIAuthPassword, prefixdb.New(authDBPrefix, n.DB))
+		if err != nil {
+			return err
+		}
+		wrappers = append(wrappers, a)
 	}
-
-	a, err := auth.New(n.Log, "auth", n.Config.APIAuthPassword)
-	if err != nil {
-		return err
+	if n.Config.HTTPSClientScopesFile != "" {
+		// Wraps the auth handler, so that requests authorized by their client
+		// certificate don't require an auth token
+		certificateScopes, err := auth.NewCertificateScopes(n.Log, n.Config.HTTPSClientScopesFile)
+		if err != nil {
+			return err
+		}
+		wrappers = append(wrappers, certificateScopes)
+		n.Log.Info("API access is restricted to the scopes of client certificates")
 	}
 
 	n.APIServer.Initialize(
@@ -517,8 +638,11 @@ func (n *Node) initAPIServer() error {
 		n.Config.APIAllowedOrigins,
 		n.Config.ShutdownTimeout,
 		n.ID,
-		a,
+		wrappers...,
 	)
+	if !n.Config.APIRequireAuthToken {
+		return nil
+	}
 
 	// only create auth service if token authorization is required
 	n.Log.Info("API authorization is enabled. Auth tokens must be passed in the header of API requests, except requests to the auth service.")
@@ -572,11 +696,11 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 		cChainID,
 	)
 
-	// Manages network timeouts
+	// Manages network timeouts. Timeouts also lower the reputation of peers.
 	timeoutManager := &timeout.Manager{}
 	if err := timeoutManager.Initialize(
 		&n.Config.AdaptiveTimeoutConfig,
-		n.benchlistManager,
+		reputation.TrackTimeouts(n.benchlistManager, n.Net.Reputation()),
 		"requests",
 		n.MetricsRegisterer,
 	); err != nil {
@@ -613,6 +737,7 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 		MsgCreator:                              n.msgCreator,
 		Router:                                  n.Config.ConsensusRouter,
 		Net:                                     n.Net,
+		Replica:                                 n.Config.ReplicaConfig.Enabled(),
 		ConsensusParams:                         n.Config.ConsensusParams,
 		Validators:                              n.vdrs,
 		NodeID:                                  n.ID,
@@ -640,9 +765,13 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 		BootstrapMaxTimeGetAncestors:            n.Config.BootstrapMaxTimeGetAncestors,
 		BootstrapAncestorsMaxContainersSent:     n.Config.BootstrapAncestorsMaxContainersSent,
 		BootstrapAncestorsMaxContainersReceived: n.Config.BootstrapAncestorsMaxContainersReceived,
+		BootstrapCheckpoints:                    n.Config.BootstrapCheckpoints,
 		ApricotPhase4Time:                       version.GetApricotPhase4Time(n.Config.NetworkID),
 		ApricotPhase4MinPChainHeight:            version.GetApricotPhase4MinPChainHeight(n.Config.NetworkID),
 		ResetProposerVMHeightIndex:              n.Config.ResetProposerVMHeightIndex,
+		PollJournalSize:                         n.Config.ConsensusPollJournalSize,
+		ChainMsgThrottlerConfig:                 n.Config.ChainMsgThrottlerConfig,
+		Reputation:                              n.Net.Reputation(),
 	})
 
 	vdrs := n.vdrs
@@ -654,6 +783,18 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 		vdrs = validators.NewManager(n.Config.NetworkID)
 	}
 
+	// The keystore APIs sign with the remote signer if there is one
+	var remoteSigner vmkeystore.ChainSigner
+	if n.Config.RemoteSignerConfig.Enabled() {
+		client, conn, err := gsigner.Dial(n.Config.RemoteSignerConfig)
+		if err != nil {
+			return fmt.Errorf("couldn't connect to the remote signer: %w", err)
+		}
+		n.Log.Info("signing the transactions of the keystore APIs with the remote signer at %s", n.Config.RemoteSignerConfig.Address)
+		remoteSigner = client
+		n.remoteSignerConn = conn
+	}
+
 	// Register the VMs that Avalanche supports
 	errs := wrappers.Errs{}
 	errs.Add(
@@ -678,15 +819,17 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 			ApricotPhase3Time:      version.GetApricotPhase3Time(n.Config.NetworkID),
 			ApricotPhase4Time:      version.GetApricotPhase4Time(n.Config.NetworkID),
 			ApricotPhase5Time:      version.GetApricotPhase5Time(n.Config.NetworkID),
+			RemoteSigner:           remoteSigner,
 		}),
 		n.Config.VMManager.RegisterFactory(constants.AVMID, &avm.Factory{
 			TxFee:            n.Config.TxFee,
 			CreateAssetTxFee: n.Config.CreateAssetTxFee,
+			RemoteSigner:     remoteSigner,
 		}),
 		n.Config.VMManager.RegisterFactory(secp256k1fx.ID, &secp256k1fx.Factory{}),
 		n.Config.VMManager.RegisterFactory(nftfx.ID, &nftfx.Factory{}),
 		n.Config.VMManager.RegisterFactory(propertyfx.ID, &propertyfx.Factory{}),
-		n.Config.VMManager.RegisterFactory(constants.EVMID, &coreth.Factory{}),
+		n.Config.VMManager.RegisterFactory(constants.EVMID, &coreth.Factory{RemoteSigner: remoteSigner}),
 		rpcchainvm.RegisterPlugins(n.Config.PluginDir, n.Config.VMManager),
 	)
 	if errs.Errored() {
@@ -848,12 +991,16 @@ func (n *Node) initAdminAPI() error {
 	n.Log.Info("initializing admin API")
 	service, err := admin.NewService(
 		admin.Config{
-			Log:          n.Log,
-			ChainManager: n.chainManager,
-			HTTPServer:   &n.APIServer,
-			ProfileDir:   n.Config.ProfilerConfig.Dir,
-			LogFactory:   n.LogFactory,
-			NodeConfig:   n.Config,
+			Log:            n.Log,
+			ChainManager:   n.chainManager,
+			HTTPServer:     &n.APIServer,
+			ProfileDir:     n.Config.ProfilerConfig.Dir,
+			LogFactory:     n.LogFactory,
+			NodeConfig:     n.Config,
+			Benchlist:      n.benchlistManager,
+			Network:        n.Net,
+			PeerFilterFile: n.Config.PeerFilterFile,
+			DB:             n.DBManager.Current(),
 		},
 	)
 	if err != nil {
@@ -909,6 +1056,7 @@ func (n *Node) initInfoAPI() error {
 		n.Net,
 		version.NewDefaultApplicationParser(),
 		validators,
+		n.uptimeTracker,
 	)
 	if err != nil {
 		return err
@@ -979,9 +1127,13 @@ func (n *Node) initHealthAPI() error {
 		return fmt.Errorf("couldn't register bootstrapped health check: %w", err)
 	}
 
-	err = healthChecker.RegisterHealthCheck("network", n.Net)
-	if err != nil {
-		return fmt.Errorf("couldn't register network health check: %w", err)
+	// A replica isn't connected to any peer. Its health depends on the
+	// primary instead, which is checked by initReplica.
+	if !n.Config.ReplicaConfig.Enabled() {
+		err = healthChecker.RegisterHealthCheck("network", n.Net)
+		if err != nil {
+			return fmt.Errorf("couldn't register network health check: %w", err)
+		}
 	}
 
 	err = healthChecker.RegisterHealthCheck("router", n.Config.ConsensusRouter)
@@ -1112,6 +1264,7 @@ func (n *Node) Initialize(
 		return fmt.Errorf("problem deriving node ID from certificate: %w", err)
 	}
 	n.LogFactory = logFactory
+	n.LogFactory.AddFields(logging.NodeID(n.ID))
 	n.DoneShuttingDown.Add(1)
 	n.Log.Info("node version is: %s", version.CurrentApp)
 	n.Log.Info("node ID is: %s", n.ID.PrefixedString(constants.NodeIDPrefix))
@@ -1196,6 +1349,9 @@ func (n *Node) Initialize(
 	if err := n.initIndexer(); err != nil {
 		return fmt.Errorf("couldn't initialize indexer: %w", err)
 	}
+	if err := n.initReplica(); err != nil {
+		return fmt.Errorf("couldn't initialize replica: %w", err)
+	}
 
 	n.health.Start(n.Config.HealthCheckFreq)
 	n.initProfiler()
@@ -1239,6 +1395,9 @@ func (n *Node) shutdown() {
 			n.Log.Debug("error during IPC shutdown: %s", err)
 		}
 	}
+	if n.replica != nil {
+		n.replica.Shutdown()
+	}
 	if n.chainManager != nil {
 		n.chainManager.Shutdown()
 	}
@@ -1249,12 +1408,23 @@ func (n *Node) shutdown() {
 		// Close already logs its own error if one occurs, so the error is ignored here
 		_ = n.Net.Close()
 	}
+	if n.uptimeTracker != nil {
+		n.uptimeFlusher.Stop()
+		if err := n.uptimeTracker.Shutdown(); err != nil {
+			n.Log.Debug("error persisting validator uptimes: %s", err)
+		}
+	}
 	if err := n.APIServer.Shutdown(); err != nil {
 		n.Log.Debug("error during API shutdown: %s", err)
 	}
 	if err := n.indexer.Close(); err != nil {
 		n.Log.Debug("error closing tx indexer: %s", err)
 	}
+	if n.remoteSignerConn != nil {
+		if err := n.remoteSignerConn.Close(); err != nil {
+			n.Log.Debug("error closing the connection to the remote signer: %s", err)
+		}
+	}
 
 	// Make sure all plugin subprocesses are killed
 	n.Log.Info("cleaning up plugin subprocesses")
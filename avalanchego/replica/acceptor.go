@@ -0,0 +1,136 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package replica
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/snow"
+	"github.com/flare-foundation/flare/snow/choices"
+	"github.com/flare-foundation/flare/snow/engine/avalanche/vertex"
+	"github.com/flare-foundation/flare/snow/engine/snowman/block"
+
+	proposerblock "github.com/flare-foundation/flare/vms/proposervm/block"
+)
+
+var (
+	// Returned when a block refers to a P-chain height the replica hasn't
+	// accepted yet. The block is accepted again after the P-chain caught up.
+	errPChainBehind = errors.New("block refers to a P-chain height that wasn't accepted yet")
+
+	_ acceptor = &blockAcceptor{}
+	_ acceptor = &txAcceptor{}
+	_ acceptor = &vtxAcceptor{}
+)
+
+// acceptor accepts the containers of one index of a chain in the same way as
+// the chain's consensus engine does. Accepting a container the chain already
+// accepted has no effect.
+type acceptor interface {
+	// accept is called with the chain's context lock held
+	accept(ctx *snow.ConsensusContext, containerID ids.ID, containerBytes []byte) error
+}
+
+// blockAcceptor accepts the blocks of a snowman chain
+type blockAcceptor struct {
+	vm block.ChainVM
+}
+
+func (a *blockAcceptor) accept(ctx *snow.ConsensusContext, blkID ids.ID, blkBytes []byte) error {
+	blk, err := a.vm.ParseBlock(blkBytes)
+	if err != nil {
+		return err
+	}
+	if blk.ID() != blkID {
+		return fmt.Errorf("block has ID %s", blk.ID())
+	}
+	if blk.Status() == choices.Accepted {
+		return nil
+	}
+	if err := checkPChainHeight(ctx, blkBytes); err != nil {
+		return err
+	}
+	if err := blk.Verify(); err != nil {
+		return err
+	}
+
+	// The dispatchers must be called before the block is accepted to honor
+	// EventDispatcher.Accept's invariant
+	if err := ctx.DecisionDispatcher.Accept(ctx, blkID, blkBytes); err != nil {
+		return err
+	}
+	if err := ctx.ConsensusDispatcher.Accept(ctx, blkID, blkBytes); err != nil {
+		return err
+	}
+	if err := blk.Accept(); err != nil {
+		return err
+	}
+	return a.vm.SetPreference(blkID)
+}
+
+// checkPChainHeight returns errPChainBehind if [blkBytes] is a proposervm block
+// that refers to a P-chain height above the height of the replica's P-chain.
+// The P-chain is followed independently of the other chains, so it may lag
+// behind them.
+func checkPChainHeight(ctx *snow.ConsensusContext, blkBytes []byte) error {
+	if ctx.ValidatorState == nil {
+		return nil
+	}
+	// Blocks from before the proposervm fork don't refer to the P-chain
+	parsed, err := proposerblock.Parse(blkBytes)
+	if err != nil {
+		return nil
+	}
+	signed, ok := parsed.(proposerblock.SignedBlock)
+	if !ok {
+		return nil
+	}
+	height, err := ctx.ValidatorState.GetCurrentHeight()
+	if err != nil {
+		return err
+	}
+	if signed.PChainHeight() > height {
+		return fmt.Errorf("%w: %d > %d", errPChainBehind, signed.PChainHeight(), height)
+	}
+	return nil
+}
+
+// txAcceptor accepts the transactions of an avalanche chain
+type txAcceptor struct {
+	vm vertex.DAGVM
+}
+
+func (a *txAcceptor) accept(ctx *snow.ConsensusContext, txID ids.ID, txBytes []byte) error {
+	tx, err := a.vm.ParseTx(txBytes)
+	if err != nil {
+		return err
+	}
+	if tx.ID() != txID {
+		return fmt.Errorf("transaction has ID %s", tx.ID())
+	}
+	if tx.Status() == choices.Accepted {
+		return nil
+	}
+	if err := tx.Verify(); err != nil {
+		return err
+	}
+
+	// The dispatcher must be called before the transaction is accepted to
+	// honor EventDispatcher.Accept's invariant
+	if err := ctx.DecisionDispatcher.Accept(ctx, txID, txBytes); err != nil {
+		return err
+	}
+	return tx.Accept()
+}
+
+// vtxAcceptor passes the vertices of an avalanche chain to the consensus
+// dispatcher, so that they're indexed. The transactions in the vertices are
+// accepted by a txAcceptor, so the vertices themselves aren't stored.
+type vtxAcceptor struct{}
+
+func (*vtxAcceptor) accept(ctx *snow.ConsensusContext, vtxID ids.ID, vtxBytes []byte) error {
+	return ctx.ConsensusDispatcher.Accept(ctx, vtxID, vtxBytes)
+}
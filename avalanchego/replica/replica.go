@@ -0,0 +1,361 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+// Package replica keeps the chains of a node that doesn't participate in the
+// network in sync with a primary node.
+//
+// The primary must index its chains. A replica reads the containers the
+// primary accepted from the primary's index API, in the order they were
+// accepted, and accepts them on its own chains. Each container is accepted
+// while the chain's context lock is held, so the APIs of the replica see the
+// state after a whole number of accepted containers.
+package replica
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/flare-foundation/flare/chains"
+	"github.com/flare-foundation/flare/database"
+	"github.com/flare-foundation/flare/database/prefixdb"
+	"github.com/flare-foundation/flare/indexer"
+	"github.com/flare-foundation/flare/snow"
+	"github.com/flare-foundation/flare/snow/engine/avalanche"
+	"github.com/flare-foundation/flare/snow/engine/avalanche/vertex"
+	"github.com/flare-foundation/flare/snow/engine/common"
+	"github.com/flare-foundation/flare/snow/engine/snowman"
+	"github.com/flare-foundation/flare/snow/engine/snowman/block"
+	"github.com/flare-foundation/flare/utils/constants"
+	"github.com/flare-foundation/flare/utils/formatting"
+	"github.com/flare-foundation/flare/utils/logging"
+
+	cjson "github.com/flare-foundation/flare/utils/json"
+)
+
+const (
+	// Timeout of a request to the primary
+	requestTimeout = 30 * time.Second
+	// Message of the error an index returns before it indexes a container
+	noneAcceptedMsg = "no containers have been accepted"
+)
+
+var (
+	errFollowingFailed = errors.New("couldn't follow the primary")
+
+	_ chains.Registrant = &Replica{}
+)
+
+// Config of a replica
+type Config struct {
+	// URI of the API server of the primary, e.g. http://127.0.0.1:9650. If
+	// empty, this node isn't a replica.
+	PrimaryURI string `json:"primaryURI"`
+	// Frequency at which the primary is checked for newly accepted containers
+	PollFrequency time.Duration `json:"pollFrequency"`
+}
+
+// Enabled returns true if this node is a replica
+func (c Config) Enabled() bool {
+	return c.PrimaryURI != ""
+}
+
+// Replica follows the indices of the primary for every chain in the primary
+// network. It's registered with the chain manager to learn about the chains.
+type Replica struct {
+	config Config
+	log    logging.Logger
+	// Stores the index of the next container of each index to accept
+	db database.Database
+
+	lock      sync.Mutex
+	followers []*follower
+	closing   chan struct{}
+	closed    bool
+	wg        sync.WaitGroup
+}
+
+// New returns a replica that stores its progress in [db]
+func New(config Config, db database.Database, log logging.Logger) *Replica {
+	return &Replica{
+		config:  config,
+		log:     log,
+		db:      db,
+		closing: make(chan struct{}),
+	}
+}
+
+// RegisterChain starts following the primary's indices of the chain
+// Assumes [engine]'s context lock is not held
+func (r *Replica) RegisterChain(name string, engine common.Engine) {
+	ctx := engine.Context()
+	if ctx.SubnetID != constants.PrimaryNetworkID {
+		r.log.Debug("not following chain %s because it's not in primary network", name)
+		return
+	}
+
+	switch engine.(type) {
+	case snowman.Engine:
+		vm, ok := engine.GetVM().(block.ChainVM)
+		if !ok {
+			r.log.Error("chain %s has unexpected VM type %T", name, engine.GetVM())
+			return
+		}
+		r.follow(name, "block", ctx, &blockAcceptor{vm: vm})
+	case avalanche.Engine:
+		vm, ok := engine.GetVM().(vertex.DAGVM)
+		if !ok {
+			r.log.Error("chain %s has unexpected VM type %T", name, engine.GetVM())
+			return
+		}
+		r.follow(name, "tx", ctx, &txAcceptor{vm: vm})
+		r.follow(name, "vtx", ctx, &vtxAcceptor{})
+	default:
+		r.log.Error("got unexpected engine type %T", engine)
+	}
+}
+
+// follow starts following the [endpoint] index of the chain [name]
+func (r *Replica) follow(name, endpoint string, ctx *snow.ConsensusContext, acceptor acceptor) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.closed {
+		return
+	}
+
+	indexName := fmt.Sprintf("%s/%s", name, endpoint)
+	f := &follower{
+		name:     indexName,
+		ctx:      ctx,
+		client:   indexer.NewClient(r.config.PrimaryURI, fmt.Sprintf("/ext/index/%s", indexName)),
+		acceptor: acceptor,
+		db:       prefixdb.New([]byte(indexName), r.db),
+	}
+	r.followers = append(r.followers, f)
+
+	r.log.Info("following index %s of the primary", indexName)
+	r.wg.Add(1)
+	go r.log.RecoverAndPanic(func() {
+		defer r.wg.Done()
+		f.run(r.closing, r.config.PollFrequency)
+	})
+}
+
+// HealthCheck returns an error if the last attempt of any chain to follow the
+// primary failed
+func (r *Replica) HealthCheck() (interface{}, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	details := make(map[string]interface{}, len(r.followers))
+	failed := false
+	for _, f := range r.followers {
+		status := f.status()
+		details[f.name] = status
+		failed = failed || status.Error != ""
+	}
+	if failed {
+		return details, errFollowingFailed
+	}
+	return details, nil
+}
+
+// Shutdown stops following the primary
+func (r *Replica) Shutdown() {
+	r.lock.Lock()
+	if !r.closed {
+		r.closed = true
+		close(r.closing)
+	}
+	r.lock.Unlock()
+
+	r.wg.Wait()
+}
+
+// status of a follower
+type status struct {
+	// Index of the next container to accept
+	NextIndex uint64 `json:"nextIndex"`
+	// Number of containers the primary accepted that haven't been accepted yet
+	Behind uint64 `json:"behind"`
+	// Error of the last attempt to follow the primary
+	Error string `json:"error,omitempty"`
+}
+
+// follower accepts the containers in one index of the primary
+type follower struct {
+	name     string
+	ctx      *snow.ConsensusContext
+	client   indexer.Client
+	acceptor acceptor
+	db       database.Database
+
+	lock      sync.Mutex
+	nextIndex uint64
+	behind    uint64
+	err       error
+}
+
+var nextIndexKey = []byte("next")
+
+func (f *follower) run(closing <-chan struct{}, pollFrequency time.Duration) {
+	nextIndex, err := database.GetUInt64(f.db, nextIndexKey)
+	switch {
+	case err == database.ErrNotFound:
+	case err != nil:
+		f.ctx.Log.Error("couldn't load the progress of index %s: %s", f.name, err)
+		f.setErr(err)
+		return
+	default:
+		f.nextIndex = nextIndex
+	}
+
+	ticker := time.NewTicker(pollFrequency)
+	defer ticker.Stop()
+	for {
+		err := f.sync(closing)
+		if err != nil {
+			f.ctx.Log.Warn("couldn't follow index %s of the primary: %s", f.name, err)
+		}
+		f.setErr(err)
+
+		select {
+		case <-closing:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sync accepts the containers the primary accepted since the last call
+func (f *follower) sync(closing <-chan struct{}) error {
+	// Containers are only accepted once the chain is done bootstrapping, which
+	// it does without fetching anything as a replica has no beacons
+	if f.ctx.GetState() != snow.NormalOp {
+		return nil
+	}
+
+	lastIndex, ok, err := f.lastIndex()
+	if err != nil || !ok {
+		return err
+	}
+	for f.next() <= lastIndex {
+		select {
+		case <-closing:
+			return nil
+		default:
+		}
+
+		numToFetch := lastIndex - f.next() + 1
+		if numToFetch > indexer.MaxFetchedByRange {
+			numToFetch = indexer.MaxFetchedByRange
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+		containers, err := f.client.GetContainerRange(ctx, &indexer.GetContainerRangeArgs{
+			StartIndex: cjson.Uint64(f.next()),
+			NumToFetch: cjson.Uint64(numToFetch),
+			Encoding:   formatting.Hex,
+		})
+		cancel()
+		if err != nil {
+			return fmt.Errorf("couldn't fetch containers from index %d: %w", f.next(), err)
+		}
+		for _, container := range containers {
+			err := f.accept(container)
+			if errors.Is(err, errPChainBehind) {
+				// Retried once the P-chain accepted the block's P-chain height
+				f.ctx.Log.Debug("waiting for the P-chain to follow index %s: %s", f.name, err)
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			f.setBehind(lastIndex + 1 - f.next())
+		}
+	}
+	return nil
+}
+
+// lastIndex returns the index of the container the primary accepted last.
+// Returns false if the primary hasn't accepted any container yet.
+func (f *follower) lastIndex() (uint64, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	lastAccepted, err := f.client.GetLastAccepted(ctx, &indexer.GetLastAcceptedArgs{Encoding: formatting.Hex})
+	switch {
+	// Errors of the API are only returned as messages
+	case err != nil && strings.Contains(err.Error(), noneAcceptedMsg):
+		return 0, false, nil
+	case err != nil:
+		return 0, false, fmt.Errorf("couldn't get the last accepted container: %w", err)
+	}
+	lastIndex, err := f.client.GetIndex(ctx, &indexer.GetIndexArgs{
+		ContainerID: lastAccepted.ID,
+		Encoding:    formatting.Hex,
+	})
+	if err != nil {
+		return 0, false, fmt.Errorf("couldn't get the index of the last accepted container: %w", err)
+	}
+	f.setBehind(lastIndex + 1 - f.next())
+	return lastIndex, true, nil
+}
+
+// accept accepts [container] and persists that the next container should be
+// accepted afterwards
+func (f *follower) accept(container indexer.Container) error {
+	f.ctx.Lock.Lock()
+	defer f.ctx.Lock.Unlock()
+
+	index := f.next()
+	if err := f.acceptor.accept(f.ctx, container.ID, container.Bytes); err != nil {
+		return fmt.Errorf("couldn't accept container %s at index %d: %w", container.ID, index, err)
+	}
+	if err := database.PutUInt64(f.db, nextIndexKey, index+1); err != nil {
+		return err
+	}
+
+	f.lock.Lock()
+	f.nextIndex = index + 1
+	f.lock.Unlock()
+	return nil
+}
+
+func (f *follower) next() uint64 {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return f.nextIndex
+}
+
+func (f *follower) setBehind(behind uint64) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.behind = behind
+}
+
+func (f *follower) setErr(err error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.err = err
+}
+
+func (f *follower) status() status {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	s := status{
+		NextIndex: f.nextIndex,
+		Behind:    f.behind,
+	}
+	if f.err != nil {
+		s.Error = f.err.Error()
+	}
+	return s
+}
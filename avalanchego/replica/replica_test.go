@@ -0,0 +1,177 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package replica
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/flare-foundation/flare/database/memdb"
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/indexer"
+	"github.com/flare-foundation/flare/snow"
+	"github.com/flare-foundation/flare/snow/choices"
+	"github.com/flare-foundation/flare/snow/consensus/snowman"
+	"github.com/flare-foundation/flare/snow/engine/snowman/block"
+	"github.com/flare-foundation/flare/snow/validators"
+
+	proposerblock "github.com/flare-foundation/flare/vms/proposervm/block"
+)
+
+var errTest = errors.New("non-nil error")
+
+// testClient serves the containers in [containers] like the index API of a
+// primary
+type testClient struct {
+	indexer.Client
+	containers []indexer.Container
+}
+
+func (c *testClient) GetLastAccepted(context.Context, *indexer.GetLastAcceptedArgs) (indexer.Container, error) {
+	if len(c.containers) == 0 {
+		return indexer.Container{}, errors.New("couldn't get last accepted container: " + noneAcceptedMsg)
+	}
+	return c.containers[len(c.containers)-1], nil
+}
+
+func (c *testClient) GetIndex(_ context.Context, args *indexer.GetIndexArgs) (uint64, error) {
+	for i, container := range c.containers {
+		if container.ID == args.ContainerID {
+			return uint64(i), nil
+		}
+	}
+	return 0, errTest
+}
+
+func (c *testClient) GetContainerRange(_ context.Context, args *indexer.GetContainerRangeArgs) ([]indexer.Container, error) {
+	start := uint64(args.StartIndex)
+	end := start + uint64(args.NumToFetch)
+	if end > uint64(len(c.containers)) {
+		end = uint64(len(c.containers))
+	}
+	return c.containers[start:end], nil
+}
+
+type testAcceptor struct {
+	accepted []ids.ID
+	failOn   ids.ID
+}
+
+func (a *testAcceptor) accept(_ *snow.ConsensusContext, containerID ids.ID, _ []byte) error {
+	if containerID == a.failOn {
+		return errTest
+	}
+	a.accepted = append(a.accepted, containerID)
+	return nil
+}
+
+func newTestFollower(client indexer.Client, acceptor acceptor) *follower {
+	ctx := snow.DefaultConsensusContextTest()
+	ctx.SetState(snow.NormalOp)
+	return &follower{
+		name:     "test",
+		ctx:      ctx,
+		client:   client,
+		acceptor: acceptor,
+		db:       memdb.New(),
+	}
+}
+
+func TestFollowerSync(t *testing.T) {
+	assert := assert.New(t)
+
+	client := &testClient{}
+	acceptor := &testAcceptor{}
+	f := newTestFollower(client, acceptor)
+	closing := make(chan struct{})
+
+	// Nothing is accepted before the primary accepts a container
+	assert.NoError(f.sync(closing))
+	assert.Empty(acceptor.accepted)
+
+	// Containers are only accepted after the chain is bootstrapped
+	f.ctx.SetState(snow.Bootstrapping)
+	client.containers = append(client.containers, indexer.Container{ID: ids.GenerateTestID()})
+	assert.NoError(f.sync(closing))
+	assert.Empty(acceptor.accepted)
+
+	f.ctx.SetState(snow.NormalOp)
+	assert.NoError(f.sync(closing))
+	assert.Equal([]ids.ID{client.containers[0].ID}, acceptor.accepted)
+
+	// Only the new containers are accepted
+	for i := 0; i < indexer.MaxFetchedByRange+1; i++ {
+		client.containers = append(client.containers, indexer.Container{ID: ids.GenerateTestID()})
+	}
+	assert.NoError(f.sync(closing))
+	assert.Len(acceptor.accepted, len(client.containers))
+	for i, container := range client.containers {
+		assert.Equal(container.ID, acceptor.accepted[i])
+	}
+	assert.Equal(status{NextIndex: uint64(len(client.containers))}, f.status())
+
+	nextIndex, err := f.db.Get(nextIndexKey)
+	assert.NoError(err)
+	assert.NotEmpty(nextIndex)
+}
+
+func TestFollowerSyncStopsOnFailure(t *testing.T) {
+	assert := assert.New(t)
+
+	client := &testClient{}
+	for i := 0; i < 3; i++ {
+		client.containers = append(client.containers, indexer.Container{ID: ids.GenerateTestID()})
+	}
+	acceptor := &testAcceptor{failOn: client.containers[1].ID}
+	f := newTestFollower(client, acceptor)
+
+	assert.Error(f.sync(make(chan struct{})))
+	assert.Equal([]ids.ID{client.containers[0].ID}, acceptor.accepted)
+	assert.Equal(status{NextIndex: 1, Behind: 2}, f.status())
+
+	// The follower resumes from the container it couldn't accept
+	acceptor.failOn = ids.Empty
+	assert.NoError(f.sync(make(chan struct{})))
+	assert.Len(acceptor.accepted, 3)
+	assert.Equal(status{NextIndex: 3}, f.status())
+}
+
+func TestFollowerSyncWaitsForPChain(t *testing.T) {
+	assert := assert.New(t)
+
+	proposerBlk, err := proposerblock.BuildUnsigned(ids.GenerateTestID(), time.Unix(0, 0), 5, []byte{1})
+	assert.NoError(err)
+	blk := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     proposerBlk.ID(),
+			StatusV: choices.Processing,
+		},
+		BytesV: proposerBlk.Bytes(),
+	}
+	vm := &block.TestVM{}
+	vm.ParseBlockF = func([]byte) (snowman.Block, error) { return blk, nil }
+	vm.SetPreferenceF = func(ids.ID) error { return nil }
+
+	client := &testClient{containers: []indexer.Container{{ID: blk.ID(), Bytes: blk.Bytes()}}}
+	f := newTestFollower(client, &blockAcceptor{vm: vm})
+	pChainHeight := uint64(4)
+	f.ctx.ValidatorState = &validators.TestState{
+		GetCurrentHeightF: func() (uint64, error) { return pChainHeight, nil },
+	}
+
+	// The block isn't accepted, nor is the follower failing, until the
+	// P-chain accepted the height the block refers to
+	assert.NoError(f.sync(make(chan struct{})))
+	assert.Equal(choices.Processing, blk.Status())
+	assert.Equal(status{Behind: 1}, f.status())
+
+	pChainHeight = 5
+	assert.NoError(f.sync(make(chan struct{})))
+	assert.Equal(choices.Accepted, blk.Status())
+	assert.Equal(status{NextIndex: 1}, f.status())
+}
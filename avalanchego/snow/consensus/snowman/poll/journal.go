@@ -0,0 +1,283 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package poll
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/flare-foundation/flare/database"
+	"github.com/flare-foundation/flare/ids"
+)
+
+const (
+	// PushQuery is the query type of polls started with a PushQuery message
+	PushQuery = "push"
+	// PullQuery is the query type of polls started with a PullQuery message
+	PullQuery = "pull"
+)
+
+// Entries are stored under 8 byte keys, so the metadata keys can't collide
+// with them.
+var (
+	// nextKey is the key of the sequence number of the next journal entry
+	nextKey = []byte("next")
+	// sizeKey is the key of the number of entries kept in the journal
+	sizeKey = []byte("size")
+)
+
+// JournalEntry is the record of a single poll
+type JournalEntry struct {
+	Sequence  uint64        `json:"sequence"`
+	RequestID uint32        `json:"requestID"`
+	Query     string        `json:"query"`
+	BlockID   ids.ID        `json:"blockID"`
+	Started   time.Time     `json:"started"`
+	Finished  time.Time     `json:"finished"`
+	Sampled   []ids.ShortID `json:"sampled"`
+	Chits     []JournalChit `json:"chits"`
+	Failed    []ids.ShortID `json:"failed"`
+	// Result is the number of votes for each block before the votes are
+	// bubbled to the blocks in consensus.
+	Result []JournalVote `json:"result"`
+	// Preference is the preferred block after the result was recorded
+	Preference ids.ID `json:"preference"`
+	Finalized  bool   `json:"finalized"`
+	Error      string `json:"error,omitempty"`
+}
+
+// JournalChit is a vote received from a sampled validator
+type JournalChit struct {
+	NodeID ids.ShortID `json:"nodeID"`
+	Vote   ids.ID      `json:"vote"`
+}
+
+// JournalVote is the number of votes a block received in a poll
+type JournalVote struct {
+	BlockID ids.ID `json:"blockID"`
+	Votes   int    `json:"votes"`
+}
+
+// Journal records the polls of a chain to a bounded ring buffer on disk, so
+// that stalls can be analyzed after the fact.
+//
+// The engine reports polls as they are started, as responses are received and
+// as they are finished. Entries are written once the result of the poll has
+// been recorded in consensus. All methods are no-ops on a nil Journal.
+type Journal struct {
+	lock sync.Mutex
+
+	db   database.Database
+	size uint64
+	// next is the sequence number of the next entry written to [db]
+	next uint64
+
+	// outstanding polls, in the order they were started
+	outstanding []*JournalEntry
+	// finished polls whose result has not been recorded yet
+	finished []*JournalEntry
+}
+
+// NewJournal returns a journal keeping the last [size] polls in [db]. If the
+// journal in [db] was written with a different size, it is cleared.
+func NewJournal(db database.Database, size uint64) (*Journal, error) {
+	j, err := LoadJournal(db)
+	if err != nil {
+		return nil, err
+	}
+	if j.size == size {
+		return j, nil
+	}
+
+	if err := database.ClearPrefix(db, db, nil); err != nil {
+		return nil, err
+	}
+	if err := database.PutUInt64(db, sizeKey, size); err != nil {
+		return nil, err
+	}
+	return &Journal{
+		db:   db,
+		size: size,
+	}, nil
+}
+
+// LoadJournal returns the journal stored in [db], to read its entries. If
+// [db] doesn't contain a journal, the returned journal is empty.
+func LoadJournal(db database.Database) (*Journal, error) {
+	size, err := database.GetUInt64(db, sizeKey)
+	if err == database.ErrNotFound {
+		return &Journal{db: db}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	next, err := database.GetUInt64(db, nextKey)
+	if err == database.ErrNotFound {
+		next, err = 0, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Journal{
+		db:   db,
+		size: size,
+		next: next,
+	}, nil
+}
+
+// Started records that a [query] poll with [requestID] for [blkID] was sent
+// to [vdrs]
+func (j *Journal) Started(requestID uint32, query string, blkID ids.ID, vdrs ids.ShortBag) {
+	if j == nil {
+		return
+	}
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	j.outstanding = append(j.outstanding, &JournalEntry{
+		RequestID: requestID,
+		Query:     query,
+		BlockID:   blkID,
+		Started:   time.Now(),
+		Sampled:   vdrs.List(),
+	})
+}
+
+// Chit records that [vdr] voted for [vote] in the poll with [requestID]
+func (j *Journal) Chit(requestID uint32, vdr ids.ShortID, vote ids.ID) {
+	if j == nil {
+		return
+	}
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	if entry := j.get(requestID); entry != nil {
+		entry.Chits = append(entry.Chits, JournalChit{NodeID: vdr, Vote: vote})
+	}
+}
+
+// Failed records that the query of [vdr] in the poll with [requestID] failed
+// or timed out
+func (j *Journal) Failed(requestID uint32, vdr ids.ShortID) {
+	if j == nil {
+		return
+	}
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	if entry := j.get(requestID); entry != nil {
+		entry.Failed = append(entry.Failed, vdr)
+	}
+}
+
+// Finished records the [results] of the oldest outstanding polls. Polls
+// finish in the order they were started, so [results] belong to the
+// len([results]) oldest outstanding polls.
+func (j *Journal) Finished(results []ids.Bag) {
+	if j == nil {
+		return
+	}
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	now := time.Now()
+	for _, result := range results {
+		if len(j.outstanding) == 0 {
+			return
+		}
+		entry := j.outstanding[0]
+		j.outstanding = j.outstanding[1:]
+
+		entry.Finished = now
+		for _, blkID := range result.List() {
+			entry.Result = append(entry.Result, JournalVote{
+				BlockID: blkID,
+				Votes:   result.Count(blkID),
+			})
+		}
+		j.finished = append(j.finished, entry)
+	}
+}
+
+// Recorded writes the finished polls to disk together with the outcome of
+// recording their results in consensus.
+func (j *Journal) Recorded(preference ids.ID, finalized bool, recordErr error) error {
+	if j == nil {
+		return nil
+	}
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	if len(j.finished) == 0 || j.size == 0 {
+		j.finished = nil
+		return nil
+	}
+
+	batch := j.db.NewBatch()
+	for _, entry := range j.finished {
+		entry.Sequence = j.next
+		entry.Preference = preference
+		entry.Finalized = finalized
+		if recordErr != nil {
+			entry.Error = recordErr.Error()
+		}
+		entryBytes, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		if err := batch.Put(database.PackUInt64(j.next%j.size), entryBytes); err != nil {
+			return err
+		}
+		j.next++
+	}
+	j.finished = nil
+	if err := database.PutUInt64(batch, nextKey, j.next); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// Entries returns up to [limit] of the most recently written entries, oldest
+// first. If [limit] is 0, every entry kept in the journal is returned.
+func (j *Journal) Entries(limit uint64) ([]JournalEntry, error) {
+	if j == nil {
+		return nil, nil
+	}
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	count := j.next
+	if count > j.size {
+		count = j.size
+	}
+	if limit != 0 && count > limit {
+		count = limit
+	}
+
+	entries := make([]JournalEntry, 0, count)
+	for seq := j.next - count; seq < j.next; seq++ {
+		entryBytes, err := j.db.Get(database.PackUInt64(seq % j.size))
+		if err != nil {
+			return nil, err
+		}
+		var entry JournalEntry
+		if err := json.Unmarshal(entryBytes, &entry); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// get returns the outstanding poll with [requestID], if any. Assumes [lock] is
+// held.
+func (j *Journal) get(requestID uint32) *JournalEntry {
+	for _, entry := range j.outstanding {
+		if entry.RequestID == requestID {
+			return entry
+		}
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package poll
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/flare-foundation/flare/database/memdb"
+	"github.com/flare-foundation/flare/ids"
+)
+
+func TestJournalRecordsPolls(t *testing.T) {
+	assert := assert.New(t)
+
+	db := memdb.New()
+	j, err := NewJournal(db, 2)
+	assert.NoError(err)
+
+	vdr1 := ids.ShortID{1}
+	vdr2 := ids.ShortID{2}
+	blkID := ids.ID{1}
+	preference := ids.ID{2}
+
+	vdrs := ids.ShortBag{}
+	vdrs.Add(vdr1, vdr2)
+
+	j.Started(1, PushQuery, blkID, vdrs)
+	j.Started(2, PullQuery, blkID, vdrs)
+	j.Chit(1, vdr1, preference)
+	j.Failed(1, vdr2)
+	// Responses to unknown polls are ignored
+	j.Chit(3, vdr1, preference)
+
+	result := ids.Bag{}
+	result.Add(preference)
+	j.Finished([]ids.Bag{result})
+	assert.NoError(j.Recorded(preference, false, nil))
+
+	entries, err := j.Entries(0)
+	assert.NoError(err)
+	assert.Len(entries, 1)
+	entry := entries[0]
+	assert.Equal(uint64(0), entry.Sequence)
+	assert.Equal(uint32(1), entry.RequestID)
+	assert.Equal(PushQuery, entry.Query)
+	assert.Equal(blkID, entry.BlockID)
+	assert.ElementsMatch([]ids.ShortID{vdr1, vdr2}, entry.Sampled)
+	assert.Equal([]JournalChit{{NodeID: vdr1, Vote: preference}}, entry.Chits)
+	assert.Equal([]ids.ShortID{vdr2}, entry.Failed)
+	assert.Equal([]JournalVote{{BlockID: preference, Votes: 1}}, entry.Result)
+	assert.Equal(preference, entry.Preference)
+	assert.False(entry.Finalized)
+	assert.Empty(entry.Error)
+
+	// The second poll is still outstanding
+	j.Finished([]ids.Bag{{}})
+	assert.NoError(j.Recorded(preference, true, errors.New("record failed")))
+
+	entries, err = j.Entries(0)
+	assert.NoError(err)
+	assert.Len(entries, 2)
+	assert.Equal(uint32(2), entries[1].RequestID)
+	assert.True(entries[1].Finalized)
+	assert.Equal("record failed", entries[1].Error)
+}
+
+func TestJournalRingBuffer(t *testing.T) {
+	assert := assert.New(t)
+
+	db := memdb.New()
+	j, err := NewJournal(db, 3)
+	assert.NoError(err)
+
+	for requestID := uint32(0); requestID < 5; requestID++ {
+		j.Started(requestID, PullQuery, ids.Empty, ids.ShortBag{})
+		j.Finished([]ids.Bag{{}})
+		assert.NoError(j.Recorded(ids.Empty, true, nil))
+	}
+
+	entries, err := j.Entries(0)
+	assert.NoError(err)
+	assert.Len(entries, 3)
+	for i, entry := range entries {
+		assert.Equal(uint64(i+2), entry.Sequence)
+	}
+
+	entries, err = j.Entries(1)
+	assert.NoError(err)
+	assert.Len(entries, 1)
+	assert.Equal(uint64(4), entries[0].Sequence)
+
+	// The journal is restored from the database
+	loaded, err := LoadJournal(db)
+	assert.NoError(err)
+	entries, err = loaded.Entries(0)
+	assert.NoError(err)
+	assert.Len(entries, 3)
+
+	// Changing the size clears the journal
+	resized, err := NewJournal(db, 4)
+	assert.NoError(err)
+	entries, err = resized.Entries(0)
+	assert.NoError(err)
+	assert.Empty(entries)
+
+	var nilJournal *Journal
+	nilJournal.Started(0, PullQuery, ids.Empty, ids.ShortBag{})
+	assert.NoError(nilJournal.Recorded(ids.Empty, true, nil))
+}
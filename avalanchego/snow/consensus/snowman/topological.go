@@ -12,6 +12,7 @@ import (
 	"github.com/flare-foundation/flare/snow/choices"
 	"github.com/flare-foundation/flare/snow/consensus/metrics"
 	"github.com/flare-foundation/flare/snow/consensus/snowball"
+	"github.com/flare-foundation/flare/utils/logging"
 )
 
 // TopologicalFactory implements Factory by returning a topological struct
@@ -550,7 +551,10 @@ func (ts *Topological) accept(n *snowmanBlock) error {
 		return err
 	}
 
-	ts.ctx.Log.Trace("accepting block %s", pref)
+	// The logger with the height is only built if trace events are logged
+	if log := ts.ctx.Log; logging.Trace <= log.GetLogLevel() || logging.Trace <= log.GetDisplayLevel() {
+		log.With(logging.Height(child.Height())).Trace("accepting block %s", pref)
+	}
 	if err := child.Accept(); err != nil {
 		return err
 	}
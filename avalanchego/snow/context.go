@@ -6,6 +6,7 @@ package snow
 import (
 	"crypto"
 	"crypto/x509"
+	"errors"
 	"sync"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -19,6 +20,10 @@ import (
 	"github.com/flare-foundation/flare/utils/logging"
 )
 
+// ErrReadOnly is returned by the VMs of a read-only chain when they're asked
+// to issue a transaction
+var ErrReadOnly = errors.New("this node is read-only and doesn't accept transactions, submit them to a node that participates in the network")
+
 type EventDispatcher interface {
 	Issuer
 	// If the returned error is non-nil, the chain associated with [ctx] should shut
@@ -60,6 +65,10 @@ type Context struct {
 	SNLookup     SubnetLookup
 	Metrics      metrics.OptionalGatherer
 
+	// True iff the chain doesn't accept transactions from its users, as it
+	// doesn't participate in consensus
+	ReadOnly bool
+
 	// snowman++ attributes
 	ValidatorState    validators.State  // interface for P-Chain validators
 	StakingLeafSigner crypto.Signer     // block signer
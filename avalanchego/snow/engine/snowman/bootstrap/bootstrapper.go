@@ -42,6 +42,7 @@ func New(config Config, onFinished func(lastReqID uint32) error) (common.Bootstr
 		},
 		executedStateTransitions: math.MaxInt32,
 		startingAcceptedFrontier: ids.Set{},
+		acceptedFrontier:         ids.Set{},
 	}
 
 	lastAcceptedID, err := b.VM.LastAccepted()
@@ -54,6 +55,12 @@ func New(config Config, onFinished func(lastReqID uint32) error) (common.Bootstr
 	}
 	b.startingHeight = lastAccepted.Height()
 
+	if config.Checkpoint != nil {
+		if err := b.verifyAccepted(lastAccepted); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := b.metrics.Initialize("bs", config.Ctx.Registerer); err != nil {
 		return nil, err
 	}
@@ -94,6 +101,8 @@ type bootstrapper struct {
 	startingHeight uint64
 	// Blocks passed into ForceAccepted
 	startingAcceptedFrontier ids.Set
+	// Blocks that the beacons reported as accepted
+	acceptedFrontier ids.Set
 	// Number of blocks that were fetched on ForceAccepted
 	initiallyFetched uint64
 	// Time that ForceAccepted was last called
@@ -250,6 +259,7 @@ func (b *bootstrapper) ForceAccepted(acceptedContainerIDs []ids.ID) error {
 			err)
 	}
 
+	b.acceptedFrontier.Add(acceptedContainerIDs...)
 	pendingContainerIDs := b.Blocked.MissingIDs()
 
 	// Append the list of accepted container IDs to pendingContainerIDs to ensure
@@ -261,6 +271,9 @@ func (b *bootstrapper) ForceAccepted(acceptedContainerIDs []ids.ID) error {
 	for _, blkID := range pendingContainerIDs {
 		b.startingAcceptedFrontier.Add(blkID)
 		if blk, err := b.VM.GetBlock(blkID); err == nil {
+			if err := b.verifyCheckpoint(blk); err != nil {
+				return err
+			}
 			if height := blk.Height(); height > b.tipHeight {
 				b.tipHeight = height
 			}
@@ -338,6 +351,10 @@ func (b *bootstrapper) process(blk snowman.Block, processingBlocks map[ids.ID]sn
 			return nil
 		}
 
+		if err := b.verifyCheckpoint(blk); err != nil {
+			return err
+		}
+
 		b.Blocked.RemoveMissingID(blkID)
 
 		pushed, err := b.Blocked.Push(&blockJob{
@@ -0,0 +1,96 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package bootstrap
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/snow/consensus/snowman"
+	"github.com/flare-foundation/flare/snow/engine/snowman/block"
+)
+
+var (
+	errCheckpointConflict      = errors.New("block conflicts with the bootstrap checkpoint")
+	errFrontierBelowCheckpoint = errors.New("accepted frontier is below the bootstrap checkpoint")
+)
+
+// Checkpoint is a block that the node trusts to be accepted, regardless of
+// what the beacons report. Bootstrapping fails rather than accept a chain
+// that doesn't go through the checkpoint.
+//
+// The checkpoint doesn't change how blocks are executed: every block above the
+// last accepted block, the checkpoint included, is fetched and verified before
+// it is accepted, as the VM builds the state of a block from its parent. The
+// ancestors of the checkpoint can't be forged since the checkpoint commits to
+// them. If the node already has the checkpoint, e.g. because the chain was
+// imported from an archive, bootstrapping only fetches the blocks above it.
+type Checkpoint struct {
+	BlockID ids.ID `json:"blockID"`
+	Height  uint64 `json:"height"`
+}
+
+func (c Checkpoint) String() string {
+	return fmt.Sprintf("%s at height %d", c.BlockID, c.Height)
+}
+
+// verifyAccepted returns an error if the block accepted at the height of the
+// checkpoint isn't the checkpoint. [lastAccepted] is the last accepted block.
+func (b *bootstrapper) verifyAccepted(lastAccepted snowman.Block) error {
+	checkpoint := b.Checkpoint
+	if lastAccepted.Height() < checkpoint.Height {
+		b.Ctx.Log.Info("bootstrapping must reach the checkpoint %s", checkpoint)
+		return nil
+	}
+
+	blkID, err := b.acceptedIDAtHeight(lastAccepted, checkpoint.Height)
+	if err != nil {
+		return fmt.Errorf("couldn't get accepted block at height %d: %w", checkpoint.Height, err)
+	}
+	if blkID != checkpoint.BlockID {
+		return fmt.Errorf("%w: accepted %s at height %d, expected %s",
+			errCheckpointConflict, blkID, checkpoint.Height, checkpoint.BlockID)
+	}
+	return nil
+}
+
+// acceptedIDAtHeight returns the ID of the accepted block at [height], which
+// must not be greater than the height of [lastAccepted]
+func (b *bootstrapper) acceptedIDAtHeight(lastAccepted snowman.Block, height uint64) (ids.ID, error) {
+	if hVM, ok := b.VM.(block.HeightIndexedChainVM); ok && hVM.VerifyHeightIndex() == nil {
+		return hVM.GetBlockIDAtHeight(height)
+	}
+
+	blk := lastAccepted
+	for blk.Height() > height {
+		parent, err := b.VM.GetBlock(blk.Parent())
+		if err != nil {
+			return ids.Empty, err
+		}
+		blk = parent
+	}
+	return blk.ID(), nil
+}
+
+// verifyCheckpoint returns an error if bootstrapping [blk] would accept a
+// chain that doesn't go through the checkpoint
+func (b *bootstrapper) verifyCheckpoint(blk snowman.Block) error {
+	checkpoint := b.Checkpoint
+	if checkpoint == nil {
+		return nil
+	}
+
+	blkID := blk.ID()
+	height := blk.Height()
+	switch {
+	case height == checkpoint.Height && blkID != checkpoint.BlockID:
+		return fmt.Errorf("%w: got %s at height %d, expected %s",
+			errCheckpointConflict, blkID, height, checkpoint.BlockID)
+	case height < checkpoint.Height && b.acceptedFrontier.Contains(blkID):
+		return fmt.Errorf("%w: got %s at height %d, expected at least height %d",
+			errFrontierBelowCheckpoint, blkID, height, checkpoint.Height)
+	}
+	return nil
+}
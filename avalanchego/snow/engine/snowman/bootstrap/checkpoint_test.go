@@ -0,0 +1,147 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package bootstrap
+
+import (
+	"errors"
+	"testing"
+
+	"gotest.tools/assert"
+
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/snow"
+	"github.com/flare-foundation/flare/snow/choices"
+	"github.com/flare-foundation/flare/snow/consensus/snowman"
+	"github.com/flare-foundation/flare/snow/engine/snowman/block"
+)
+
+// newTestChain returns a chain of [length] blocks. The first [accepted] blocks
+// are accepted and the others are processing. [vm] serves the blocks.
+func newTestChain(t *testing.T, vm *block.TestVM, length int, accepted int) []*snowman.TestBlock {
+	blks := make([]*snowman.TestBlock, length)
+	for i := range blks {
+		status := choices.Processing
+		if i < accepted {
+			status = choices.Accepted
+		}
+		blks[i] = &snowman.TestBlock{
+			TestDecidable: choices.TestDecidable{
+				IDV:     ids.Empty.Prefix(uint64(i)),
+				StatusV: status,
+			},
+			HeightV: uint64(i),
+			BytesV:  []byte{byte(i)},
+		}
+		if i > 0 {
+			blks[i].ParentV = blks[i-1].IDV
+		}
+	}
+
+	vm.CantLastAccepted = false
+	vm.LastAcceptedF = func() (ids.ID, error) { return blks[accepted-1].ID(), nil }
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		for _, blk := range blks {
+			if blk.ID() == blkID {
+				return blk, nil
+			}
+		}
+		t.Fatal(errUnknownBlock)
+		return nil, errUnknownBlock
+	}
+	vm.ParseBlockF = func(blkBytes []byte) (snowman.Block, error) {
+		for _, blk := range blks {
+			if blk.Bytes()[0] == blkBytes[0] {
+				return blk, nil
+			}
+		}
+		t.Fatal(errUnknownBlock)
+		return nil, errUnknownBlock
+	}
+	vm.CantSetState = false
+	return blks
+}
+
+func TestBootstrapperCheckpoint(t *testing.T) {
+	config, _, _, vm := newConfig(t)
+	blks := newTestChain(t, vm, 4, 1)
+	config.Checkpoint = &Checkpoint{
+		BlockID: blks[2].ID(),
+		Height:  2,
+	}
+
+	bs, err := New(
+		config,
+		func(lastReqID uint32) error { config.Ctx.SetState(snow.NormalOp); return nil },
+	)
+	assert.NilError(t, err)
+	assert.NilError(t, bs.Start(0))
+
+	assert.NilError(t, bs.ForceAccepted([]ids.ID{blks[3].ID()}))
+	assert.Assert(t, config.Ctx.GetState() == snow.NormalOp)
+	for _, blk := range blks {
+		assert.Equal(t, choices.Accepted, blk.Status())
+	}
+}
+
+func TestBootstrapperCheckpointConflict(t *testing.T) {
+	config, _, _, vm := newConfig(t)
+	blks := newTestChain(t, vm, 4, 1)
+	config.Checkpoint = &Checkpoint{
+		BlockID: ids.GenerateTestID(),
+		Height:  2,
+	}
+
+	bs, err := New(
+		config,
+		func(lastReqID uint32) error { config.Ctx.SetState(snow.NormalOp); return nil },
+	)
+	assert.NilError(t, err)
+	assert.NilError(t, bs.Start(0))
+
+	err = bs.ForceAccepted([]ids.ID{blks[3].ID()})
+	assert.Assert(t, errors.Is(err, errCheckpointConflict))
+	assert.Assert(t, config.Ctx.GetState() == snow.Bootstrapping)
+	for _, blk := range blks[1:] {
+		assert.Equal(t, choices.Processing, blk.Status())
+	}
+}
+
+func TestBootstrapperFrontierBelowCheckpoint(t *testing.T) {
+	config, _, _, vm := newConfig(t)
+	blks := newTestChain(t, vm, 4, 1)
+	config.Checkpoint = &Checkpoint{
+		BlockID: ids.GenerateTestID(),
+		Height:  5,
+	}
+
+	bs, err := New(
+		config,
+		func(lastReqID uint32) error { config.Ctx.SetState(snow.NormalOp); return nil },
+	)
+	assert.NilError(t, err)
+	assert.NilError(t, bs.Start(0))
+
+	err = bs.ForceAccepted([]ids.ID{blks[3].ID()})
+	assert.Assert(t, errors.Is(err, errFrontierBelowCheckpoint))
+	assert.Assert(t, config.Ctx.GetState() == snow.Bootstrapping)
+}
+
+func TestBootstrapperAcceptedCheckpoint(t *testing.T) {
+	config, _, _, vm := newConfig(t)
+	blks := newTestChain(t, vm, 4, 3)
+
+	config.Checkpoint = &Checkpoint{
+		BlockID: blks[1].ID(),
+		Height:  1,
+	}
+	_, err := New(config, func(uint32) error { return nil })
+	assert.NilError(t, err)
+
+	config.Checkpoint = &Checkpoint{
+		BlockID: ids.GenerateTestID(),
+		Height:  1,
+	}
+	_, err = New(config, func(uint32) error { return nil })
+	assert.Assert(t, errors.Is(err, errCheckpointConflict))
+}
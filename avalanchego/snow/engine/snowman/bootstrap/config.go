@@ -20,5 +20,8 @@ type Config struct {
 	VM            block.ChainVM
 	WeightTracker tracker.WeightTracker
 
+	// Checkpoint, if non-nil, is a block that must be accepted
+	Checkpoint *Checkpoint
+
 	Bootstrapped func()
 }
@@ -7,8 +7,10 @@ import (
 	"github.com/flare-foundation/flare/snow"
 	"github.com/flare-foundation/flare/snow/consensus/snowball"
 	"github.com/flare-foundation/flare/snow/consensus/snowman"
+	"github.com/flare-foundation/flare/snow/consensus/snowman/poll"
 	"github.com/flare-foundation/flare/snow/engine/common"
 	"github.com/flare-foundation/flare/snow/engine/snowman/block"
+	"github.com/flare-foundation/flare/snow/networking/reputation"
 	"github.com/flare-foundation/flare/snow/validators"
 )
 
@@ -22,4 +24,10 @@ type Config struct {
 	Validators validators.Set
 	Params     snowball.Parameters
 	Consensus  snowman.Consensus
+
+	// PollJournal records the polls of the engine, if not nil
+	PollJournal *poll.Journal
+
+	// Reputation is told about the peers that sent invalid blocks, if not nil
+	Reputation reputation.Tracker
 }
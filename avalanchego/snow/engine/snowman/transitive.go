@@ -14,6 +14,7 @@ import (
 	"github.com/flare-foundation/flare/snow/consensus/snowman/poll"
 	"github.com/flare-foundation/flare/snow/engine/common"
 	"github.com/flare-foundation/flare/snow/events"
+	"github.com/flare-foundation/flare/snow/networking/reputation"
 	"github.com/flare-foundation/flare/utils/formatting"
 	"github.com/flare-foundation/flare/utils/wrappers"
 	"github.com/flare-foundation/flare/version"
@@ -48,6 +49,10 @@ type Transitive struct {
 	// Block ID --> Block
 	pending map[ids.ID]snowman.Block
 
+	// peers that sent the pending blocks, if known
+	// Block ID --> Node ID
+	senders map[ids.ID]ids.ShortID
+
 	// Block ID --> Parent ID
 	nonVerifieds AncestorTree
 
@@ -73,6 +78,7 @@ func newTransitive(config Config) (*Transitive, error) {
 		AcceptedHandler:         common.NewNoOpAcceptedHandler(config.Ctx.Log),
 		AncestorsHandler:        common.NewNoOpAncestorsHandler(config.Ctx.Log),
 		pending:                 make(map[ids.ID]snowman.Block),
+		senders:                 make(map[ids.ID]ids.ShortID),
 		nonVerifieds:            NewAncestorTree(),
 		polls: poll.NewSet(factory,
 			config.Ctx.Log,
@@ -95,6 +101,7 @@ func (t *Transitive) Put(vdr ids.ShortID, requestID uint32, blkBytes []byte) err
 		// abandon the request.
 		return t.GetFailed(vdr, requestID)
 	}
+	t.addSender(vdr, blk)
 
 	// issue the block into consensus. If the block has already been issued,
 	// this will be a noop. If this block has missing dependencies, vdr will
@@ -160,6 +167,7 @@ func (t *Transitive) PushQuery(vdr ids.ShortID, requestID uint32, blkBytes []byt
 		t.Ctx.Log.Verbo("block:\n%s", formatting.DumpBytes(blkBytes))
 		return nil
 	}
+	t.addSender(vdr, blk)
 
 	// issue the block into consensus. If the block has already been issued,
 	// this will be a noop. If this block has missing dependencies, vdr will
@@ -593,6 +601,7 @@ func (t *Transitive) pullQuery(blkID ids.ID) {
 		vdrList := vdrBag.List()
 		vdrSet := ids.NewShortSet(len(vdrList))
 		vdrSet.Add(vdrList...)
+		t.PollJournal.Started(t.RequestID, poll.PullQuery, blkID, vdrBag)
 		t.Sender.SendPullQuery(vdrSet, t.RequestID, blkID)
 	} else if err != nil {
 		t.Ctx.Log.Error("query for %s was dropped due to an insufficient number of validators", blkID)
@@ -614,6 +623,7 @@ func (t *Transitive) pushQuery(blk snowman.Block) {
 		vdrSet := ids.NewShortSet(len(vdrList))
 		vdrSet.Add(vdrList...)
 
+		t.PollJournal.Started(t.RequestID, poll.PushQuery, blk.ID(), vdrBag)
 		t.Sender.SendPushQuery(vdrSet, t.RequestID, blk.ID(), blk.Bytes())
 	} else if err != nil {
 		t.Ctx.Log.Error("query for %s was dropped due to an insufficient number of validators", blk.ID())
@@ -629,6 +639,7 @@ func (t *Transitive) deliver(blk snowman.Block) error {
 
 	// we are no longer waiting on adding the block to consensus, so it is no
 	// longer pending
+	sender, hasSender := t.senders[blkID]
 	t.removeFromPending(blk)
 	parentID := blk.Parent()
 	parent, err := t.GetBlock(parentID)
@@ -651,6 +662,9 @@ func (t *Transitive) deliver(blk snowman.Block) error {
 	// make sure this block is valid
 	if err := blk.Verify(); err != nil {
 		t.Ctx.Log.Debug("block failed verification due to %s, dropping block", err)
+		if hasSender {
+			t.Reputation.Penalize(sender, reputation.InvalidBlock)
+		}
 
 		// if verify fails, then all descendants are also invalid
 		t.addToNonVerifieds(blk)
@@ -753,7 +767,19 @@ func (t *Transitive) pendingContains(blkID ids.ID) bool {
 }
 
 func (t *Transitive) removeFromPending(blk snowman.Block) {
-	delete(t.pending, blk.ID())
+	blkID := blk.ID()
+	delete(t.pending, blkID)
+	delete(t.senders, blkID)
+}
+
+// addSender records that [vdr] sent [blk], if [blk] is about to be issued, so
+// that [vdr] can be penalized if [blk] is invalid
+func (t *Transitive) addSender(vdr ids.ShortID, blk snowman.Block) {
+	blkID := blk.ID()
+	if t.Reputation == nil || t.Consensus.Decided(blk) || t.Consensus.Processing(blkID) || t.pendingContains(blkID) {
+		return
+	}
+	t.senders[blkID] = vdr
 }
 
 func (t *Transitive) addToNonVerifieds(blk snowman.Block) {
@@ -17,6 +17,7 @@ import (
 	"github.com/flare-foundation/flare/snow/engine/snowman/block"
 	"github.com/flare-foundation/flare/snow/engine/snowman/bootstrap"
 	snowgetter "github.com/flare-foundation/flare/snow/engine/snowman/getter"
+	"github.com/flare-foundation/flare/snow/networking/reputation"
 	"github.com/flare-foundation/flare/snow/validators"
 	"github.com/flare-foundation/flare/utils/constants"
 	"github.com/flare-foundation/flare/utils/wrappers"
@@ -3181,3 +3182,73 @@ func TestEngineBubbleVotesThroughInvalidChain(t *testing.T) {
 		t.Fatalf("Expected blk1 to be Accepted, but found status: %s", blk1.Status())
 	}
 }
+
+type testReputation struct {
+	reputation.Tracker
+	offenses map[ids.ShortID][]reputation.Offense
+}
+
+func (r *testReputation) Penalize(nodeID ids.ShortID, offense reputation.Offense) {
+	r.offenses[nodeID] = append(r.offenses[nodeID], offense)
+}
+
+func TestEngineInvalidBlockPenalizesSender(t *testing.T) {
+	vdr, _, sender, vm, te, gBlk := setup(t)
+
+	sender.Default(true)
+	sender.CantSendPushQuery = false
+	rep := &testReputation{offenses: make(map[ids.ShortID][]reputation.Offense)}
+	te.Reputation = rep
+
+	validBlk := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentV: gBlk.ID(),
+		HeightV: 1,
+		BytesV:  []byte{1},
+	}
+	invalidBlk := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentV: gBlk.ID(),
+		HeightV: 1,
+		VerifyV: errors.New("invalid"),
+		BytesV:  []byte{2},
+	}
+
+	vm.ParseBlockF = func(b []byte) (snowman.Block, error) {
+		switch {
+		case bytes.Equal(b, validBlk.Bytes()):
+			return validBlk, nil
+		case bytes.Equal(b, invalidBlk.Bytes()):
+			return invalidBlk, nil
+		default:
+			return nil, errUnknownBytes
+		}
+	}
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		switch blkID {
+		case gBlk.ID():
+			return gBlk, nil
+		case validBlk.ID():
+			return validBlk, nil
+		default:
+			return nil, errUnknownBlock
+		}
+	}
+
+	if err := te.Put(vdr, 0, validBlk.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, rep.offenses)
+
+	if err := te.Put(vdr, 0, invalidBlk.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []reputation.Offense{reputation.InvalidBlock}, rep.offenses[vdr])
+	assert.Empty(t, te.senders)
+}
@@ -34,14 +34,17 @@ func (v *voter) Update() {
 
 	var results []ids.Bag
 	if v.response == ids.Empty {
+		v.t.PollJournal.Failed(v.requestID, v.vdr)
 		results = v.t.polls.Drop(v.requestID, v.vdr)
 	} else {
+		v.t.PollJournal.Chit(v.requestID, v.vdr, v.response)
 		results = v.t.polls.Vote(v.requestID, v.vdr, v.response)
 	}
 
 	if len(results) == 0 {
 		return
 	}
+	v.t.PollJournal.Finished(results)
 
 	// To prevent any potential deadlocks with un-disclosed dependencies, votes
 	// must be bubbled to the nearest valid block
@@ -59,14 +62,17 @@ func (v *voter) Update() {
 	}
 
 	if v.t.errs.Errored() {
+		v.recordJournal(v.t.errs.Err)
 		return
 	}
 
 	if err := v.t.VM.SetPreference(v.t.Consensus.Preference()); err != nil {
 		v.t.errs.Add(err)
+		v.recordJournal(err)
 		return
 	}
 
+	v.recordJournal(nil)
 	if v.t.Consensus.Finalized() {
 		v.t.Ctx.Log.Debug("Snowman engine can quiesce")
 		return
@@ -76,6 +82,20 @@ func (v *voter) Update() {
 	v.t.repoll()
 }
 
+// recordJournal writes the polls finished by this vote to the poll journal.
+// Failing to write the journal doesn't affect consensus, so the error is only
+// logged.
+func (v *voter) recordJournal(err error) {
+	if v.t.PollJournal == nil {
+		return
+	}
+	preference := v.t.Consensus.Preference()
+	finalized := v.t.Consensus.Finalized()
+	if err := v.t.PollJournal.Recorded(preference, finalized, err); err != nil {
+		v.t.Ctx.Log.Warn("failed to write the poll journal: %s", err)
+	}
+}
+
 // bubbleVotes bubbles the [votes] a set of the number of votes for specific
 // blkIDs that received votes in consensus, to their most recent ancestor that
 // has been issued to consensus.
@@ -12,6 +12,7 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 
+	"github.com/flare-foundation/flare/database"
 	"github.com/flare-foundation/flare/ids"
 	"github.com/flare-foundation/flare/snow/validators"
 	"github.com/flare-foundation/flare/utils/logging"
@@ -38,6 +39,14 @@ type Benchlist interface {
 	// IsBenched returns true if messages to [validatorID]
 	// should not be sent over the network and should immediately fail.
 	IsBenched(validatorID ids.ShortID) bool
+	// Bench benches [validatorID] for [duration], regardless of its failures
+	// and of the stake that is already benched. If [validatorID] is already
+	// benched, the end of its bench is moved to [duration] from now.
+	Bench(validatorID ids.ShortID, duration time.Duration)
+	// Unbench removes [validatorID] from the bench, if it is benched
+	Unbench(validatorID ids.ShortID)
+	// Benched returns the benched validators and when they leave the bench
+	Benched() map[ids.ShortID]time.Time
 }
 
 // Data about a validator who is benched
@@ -101,6 +110,10 @@ type benchlist struct {
 	// Validator set of the network
 	vdrs validators.Set
 
+	// Validator ID --> Time the validator leaves the bench.
+	// Keeps the benched validators across restarts.
+	db database.Database
+
 	// Validator ID --> Consecutive failure information
 	// [streaklock] must be held when touching [failureStreaks]
 	streaklock     sync.Mutex
@@ -137,6 +150,7 @@ func NewBenchlist(
 	minimumFailingDuration,
 	duration time.Duration,
 	maxPortion float64,
+	db database.Database,
 	registerer prometheus.Registerer,
 ) (Benchlist, error) {
 	if maxPortion < 0 || maxPortion >= 1 {
@@ -153,10 +167,60 @@ func NewBenchlist(
 		minimumFailingDuration: minimumFailingDuration,
 		duration:               duration,
 		maxPortion:             maxPortion,
+		db:                     db,
+	}
+	if err := benchlist.metrics.Initialize(registerer); err != nil {
+		return nil, err
+	}
+	if err := benchlist.load(); err != nil {
+		return nil, fmt.Errorf("couldn't load benched validators: %w", err)
 	}
 	benchlist.timer = timer.NewTimer(benchlist.update)
+	benchlist.setNextLeaveTime()
 	go benchlist.timer.Dispatch()
-	return benchlist, benchlist.metrics.Initialize(registerer)
+	return benchlist, nil
+}
+
+// load benches the validators that were still benched when the benchlist was
+// last persisted
+func (b *benchlist) load() error {
+	it := b.db.NewIterator()
+	defer it.Release()
+
+	now := b.clock.Time()
+	expired := [][]byte(nil)
+	for it.Next() {
+		validatorID, err := ids.ToShortID(it.Key())
+		if err != nil {
+			return err
+		}
+		benchedUntil, err := database.ParseTimestamp(it.Value())
+		if err != nil {
+			return err
+		}
+		if !now.Before(benchedUntil) {
+			expired = append(expired, validatorID[:])
+			continue
+		}
+
+		b.log.Debug("restoring benched validator %s until %s", validatorID, benchedUntil)
+		b.benchlistSet.Add(validatorID)
+		b.benchable.Benched(b.chainID, validatorID)
+		heap.Push(
+			&b.benchedQueue,
+			&benchData{validatorID: validatorID, benchedUntil: benchedUntil},
+		)
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	for _, key := range expired {
+		if err := b.db.Delete(key); err != nil {
+			return err
+		}
+	}
+	b.updateMetrics()
+	return nil
 }
 
 // Update removes benched validators whose time on the bench is over
@@ -187,8 +251,14 @@ func (b *benchlist) remove(validator *benchData) {
 	heap.Remove(&b.benchedQueue, validator.index)
 	b.benchlistSet.Remove(id)
 	b.benchable.Unbenched(b.chainID, id)
+	if err := b.db.Delete(id[:]); err != nil {
+		b.log.Error("couldn't remove validator %s from the persisted benchlist: %s", id, err)
+	}
+	b.updateMetrics()
+}
 
-	// Update metrics
+// Assumes [b.lock] is held
+func (b *benchlist) updateMetrics() {
 	b.metrics.numBenched.Set(float64(b.benchedQueue.Len()))
 	benchedStake, err := b.vdrs.SubsetWeight(b.benchlistSet)
 	if err != nil {
@@ -244,6 +314,74 @@ func (b *benchlist) isBenched(validatorID ids.ShortID) bool {
 	return false
 }
 
+// Bench implements the Benchlist interface
+func (b *benchlist) Bench(validatorID ids.ShortID, duration time.Duration) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	benchedUntil := b.clock.Time().Add(duration)
+	b.log.Info("manually benching validator %s until %s", validatorID, benchedUntil)
+	if b.benchlistSet.Contains(validatorID) {
+		for _, benched := range b.benchedQueue {
+			if benched.validatorID == validatorID {
+				benched.benchedUntil = benchedUntil
+				heap.Fix(&b.benchedQueue, benched.index)
+				break
+			}
+		}
+	} else {
+		b.benchlistSet.Add(validatorID)
+		b.benchable.Benched(b.chainID, validatorID)
+		heap.Push(
+			&b.benchedQueue,
+			&benchData{validatorID: validatorID, benchedUntil: benchedUntil},
+		)
+	}
+
+	b.streaklock.Lock()
+	delete(b.failureStreaks, validatorID)
+	b.streaklock.Unlock()
+
+	b.persist(validatorID, benchedUntil)
+	b.setNextLeaveTime()
+	b.updateMetrics()
+}
+
+// Unbench implements the Benchlist interface
+func (b *benchlist) Unbench(validatorID ids.ShortID) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for _, benched := range b.benchedQueue {
+		if benched.validatorID == validatorID {
+			b.log.Info("manually unbenching validator %s", validatorID)
+			b.remove(benched)
+			b.setNextLeaveTime()
+			return
+		}
+	}
+}
+
+// Benched implements the Benchlist interface
+func (b *benchlist) Benched() map[ids.ShortID]time.Time {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	benched := make(map[ids.ShortID]time.Time, len(b.benchedQueue))
+	for _, data := range b.benchedQueue {
+		benched[data.validatorID] = data.benchedUntil
+	}
+	return benched
+}
+
+// persist records that [validatorID] is benched until [benchedUntil]
+// Assumes [b.lock] is held
+func (b *benchlist) persist(validatorID ids.ShortID, benchedUntil time.Time) {
+	if err := database.PutTimestamp(b.db, validatorID[:], benchedUntil); err != nil {
+		b.log.Error("couldn't persist benched validator %s: %s", validatorID, err)
+	}
+}
+
 // RegisterResponse notes that we received a response from validator [validatorID]
 func (b *benchlist) RegisterResponse(validatorID ids.ShortID) {
 	b.streaklock.Lock()
@@ -336,6 +474,7 @@ func (b *benchlist) bench(validatorID ids.ShortID) {
 		&b.benchedQueue,
 		&benchData{validatorID: validatorID, benchedUntil: benchedUntil},
 	)
+	b.persist(validatorID, benchedUntil)
 	b.log.Debug(
 		"benching validator %s for %s after %d consecutive failed queries.",
 		validatorID,
@@ -11,6 +11,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/flare-foundation/flare/database"
+	"github.com/flare-foundation/flare/database/memdb"
 	"github.com/flare-foundation/flare/ids"
 	"github.com/flare-foundation/flare/snow/validators"
 	"github.com/flare-foundation/flare/utils/logging"
@@ -55,6 +57,7 @@ func TestBenchlistAdd(t *testing.T) {
 		minimumFailingDuration,
 		duration,
 		maxPortion,
+		memdb.New(),
 		prometheus.NewRegistry(),
 	)
 	if err != nil {
@@ -198,6 +201,7 @@ func TestBenchlistMaxStake(t *testing.T) {
 		minimumFailingDuration,
 		duration,
 		maxPortion,
+		memdb.New(),
 		prometheus.NewRegistry(),
 	)
 	if err != nil {
@@ -338,6 +342,7 @@ func TestBenchlistRemove(t *testing.T) {
 		minimumFailingDuration,
 		duration,
 		maxPortion,
+		memdb.New(),
 		prometheus.NewRegistry(),
 	)
 	if err != nil {
@@ -419,3 +424,137 @@ func TestBenchlistRemove(t *testing.T) {
 
 	assert.Equal(t, 3, count)
 }
+
+// Test that validators can be benched and unbenched manually
+func TestBenchlistManual(t *testing.T) {
+	vdrs := validators.NewSet()
+	vdr0 := validators.GenerateRandomValidator(1000)
+	vdr1 := validators.GenerateRandomValidator(1000)
+	errs := wrappers.Errs{}
+	errs.Add(
+		vdrs.AddWeight(vdr0.ID(), vdr0.Weight()),
+		vdrs.AddWeight(vdr1.ID(), vdr1.Weight()),
+	)
+	if errs.Errored() {
+		t.Fatal(errs.Err)
+	}
+
+	benched := ids.ShortSet{}
+	benchable := &TestBenchable{
+		T: t,
+		BenchedF: func(_ ids.ID, validatorID ids.ShortID) {
+			benched.Add(validatorID)
+		},
+		UnbenchedF: func(_ ids.ID, validatorID ids.ShortID) {
+			benched.Remove(validatorID)
+		},
+	}
+
+	db := memdb.New()
+	benchIntf, err := NewBenchlist(
+		ids.Empty,
+		logging.NoLog{},
+		benchable,
+		vdrs,
+		3,
+		minimumFailingDuration,
+		time.Minute,
+		0.1, // too low to bench either validator automatically
+		db,
+		prometheus.NewRegistry(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := benchIntf.(*benchlist)
+	defer b.timer.Stop()
+
+	now := time.Now()
+	b.clock.Set(now)
+	b.Bench(vdr0.ID(), time.Hour)
+	b.Bench(vdr1.ID(), time.Hour)
+	assert.True(t, b.IsBenched(vdr0.ID()))
+	assert.True(t, b.IsBenched(vdr1.ID()))
+	assert.Equal(t, ids.ShortSet{vdr0.ID(): struct{}{}, vdr1.ID(): struct{}{}}, benched)
+
+	// Benching a benched validator again moves the end of its bench
+	b.Bench(vdr1.ID(), 2*time.Hour)
+	assert.Equal(t, map[ids.ShortID]time.Time{
+		vdr0.ID(): now.Add(time.Hour),
+		vdr1.ID(): now.Add(2 * time.Hour),
+	}, b.Benched())
+	assert.Equal(t, vdr0.ID(), b.benchedQueue[0].validatorID)
+
+	b.Unbench(vdr0.ID())
+	assert.False(t, b.IsBenched(vdr0.ID()))
+	assert.Equal(t, ids.ShortSet{vdr1.ID(): struct{}{}}, benched)
+	has, err := db.Has(vdr0.ID().Bytes())
+	assert.NoError(t, err)
+	assert.False(t, has)
+
+	// Unbenching a validator that isn't benched does nothing
+	b.Unbench(vdr0.ID())
+	assert.Len(t, b.Benched(), 1)
+}
+
+// Test that the benched validators are restored from the database
+func TestBenchlistRestore(t *testing.T) {
+	vdrs := validators.NewSet()
+	vdr0 := validators.GenerateRandomValidator(1000)
+	vdr1 := validators.GenerateRandomValidator(1000)
+	errs := wrappers.Errs{}
+	errs.Add(
+		vdrs.AddWeight(vdr0.ID(), vdr0.Weight()),
+		vdrs.AddWeight(vdr1.ID(), vdr1.Weight()),
+	)
+	if errs.Errored() {
+		t.Fatal(errs.Err)
+	}
+
+	// [vdr0] is still benched while [vdr1] left the bench while the node was
+	// down
+	db := memdb.New()
+	benchedUntil := time.Now().Add(time.Hour)
+	errs.Add(
+		database.PutTimestamp(db, vdr0.ID().Bytes(), benchedUntil),
+		database.PutTimestamp(db, vdr1.ID().Bytes(), time.Now().Add(-time.Hour)),
+	)
+	if errs.Errored() {
+		t.Fatal(errs.Err)
+	}
+
+	benched := ids.ShortSet{}
+	benchable := &TestBenchable{
+		T:             t,
+		CantUnbenched: true,
+		BenchedF: func(_ ids.ID, validatorID ids.ShortID) {
+			benched.Add(validatorID)
+		},
+	}
+	benchIntf, err := NewBenchlist(
+		ids.Empty,
+		logging.NoLog{},
+		benchable,
+		vdrs,
+		3,
+		minimumFailingDuration,
+		time.Minute,
+		0.5,
+		db,
+		prometheus.NewRegistry(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := benchIntf.(*benchlist)
+	defer b.timer.Stop()
+
+	assert.True(t, b.IsBenched(vdr0.ID()))
+	assert.False(t, b.IsBenched(vdr1.ID()))
+	assert.Equal(t, ids.ShortSet{vdr0.ID(): struct{}{}}, benched)
+	assert.True(t, benchedUntil.Equal(b.Benched()[vdr0.ID()]))
+
+	has, err := db.Has(vdr1.ID().Bytes())
+	assert.NoError(t, err)
+	assert.False(t, has)
+}
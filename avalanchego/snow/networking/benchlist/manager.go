@@ -5,15 +5,22 @@ package benchlist
 
 import (
 	"errors"
+	"fmt"
 	"sync"
 	"time"
 
+	"github.com/flare-foundation/flare/database"
+	"github.com/flare-foundation/flare/database/prefixdb"
 	"github.com/flare-foundation/flare/ids"
 	"github.com/flare-foundation/flare/snow"
 	"github.com/flare-foundation/flare/snow/validators"
 )
 
-var errUnknownValidators = errors.New("unknown validator set for provided chain")
+var (
+	errUnknownValidators = errors.New("unknown validator set for provided chain")
+	errBenchlistDisabled = errors.New("benchlist is disabled")
+	errUnknownChain      = errors.New("unknown chain")
+)
 
 // Manager provides an interface for a benchlist to register whether
 // queries have been successful or unsuccessful and place validators with
@@ -36,12 +43,22 @@ type Manager interface {
 	// [validatorID] is benched. If called on an id.ShortID that does
 	// not map to a validator, it will return an empty array.
 	GetBenched(validatorID ids.ShortID) []ids.ID
+	// Bench benches [validatorID] for [duration] on [chainIDs], or on every
+	// chain if [chainIDs] is empty, regardless of its failures.
+	Bench(validatorID ids.ShortID, duration time.Duration, chainIDs []ids.ID) error
+	// Unbench removes [validatorID] from the bench of [chainIDs], or of every
+	// chain if [chainIDs] is empty.
+	Unbench(validatorID ids.ShortID, chainIDs []ids.ID) error
+	// Benched returns, for each chain, the benched validators and when they
+	// leave the bench
+	Benched() map[ids.ID]map[ids.ShortID]time.Time
 }
 
 // Config defines the configuration for a benchlist
 type Config struct {
 	Benchable              Benchable          `json:"-"`
 	Validators             validators.Manager `json:"-"`
+	DB                     database.Database  `json:"-"`
 	StakingEnabled         bool               `json:"-"`
 	Threshold              int                `json:"threshold"`
 	MinimumFailingDuration time.Duration      `json:"minimumFailingDuration"`
@@ -125,6 +142,7 @@ func (m *manager) RegisterChain(ctx *snow.ConsensusContext) error {
 		m.config.MinimumFailingDuration,
 		m.config.Duration,
 		m.config.MaxPortion,
+		prefixdb.New(ctx.ChainID[:], m.config.DB),
 		ctx.Registerer,
 	)
 	if err != nil {
@@ -159,13 +177,79 @@ func (m *manager) RegisterFailure(chainID ids.ID, validatorID ids.ShortID) {
 	benchlist.RegisterFailure(validatorID)
 }
 
+// Bench implements the Manager interface
+func (m *manager) Bench(validatorID ids.ShortID, duration time.Duration, chainIDs []ids.ID) error {
+	benchlists, err := m.benchlists(chainIDs)
+	if err != nil {
+		return err
+	}
+	for _, benchlist := range benchlists {
+		benchlist.Bench(validatorID, duration)
+	}
+	return nil
+}
+
+// Unbench implements the Manager interface
+func (m *manager) Unbench(validatorID ids.ShortID, chainIDs []ids.ID) error {
+	benchlists, err := m.benchlists(chainIDs)
+	if err != nil {
+		return err
+	}
+	for _, benchlist := range benchlists {
+		benchlist.Unbench(validatorID)
+	}
+	return nil
+}
+
+// Benched implements the Manager interface
+func (m *manager) Benched() map[ids.ID]map[ids.ShortID]time.Time {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	benched := make(map[ids.ID]map[ids.ShortID]time.Time, len(m.chainBenchlists))
+	for chainID, benchlist := range m.chainBenchlists {
+		if chainBenched := benchlist.Benched(); len(chainBenched) > 0 {
+			benched[chainID] = chainBenched
+		}
+	}
+	return benched
+}
+
+// benchlists returns the benchlists of [chainIDs], or every benchlist if
+// [chainIDs] is empty
+func (m *manager) benchlists(chainIDs []ids.ID) ([]Benchlist, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	if len(chainIDs) == 0 {
+		benchlists := make([]Benchlist, 0, len(m.chainBenchlists))
+		for _, benchlist := range m.chainBenchlists {
+			benchlists = append(benchlists, benchlist)
+		}
+		return benchlists, nil
+	}
+
+	benchlists := make([]Benchlist, 0, len(chainIDs))
+	for _, chainID := range chainIDs {
+		benchlist, exists := m.chainBenchlists[chainID]
+		if !exists {
+			return nil, fmt.Errorf("%w %s", errUnknownChain, chainID)
+		}
+		benchlists = append(benchlists, benchlist)
+	}
+	return benchlists, nil
+}
+
 type noBenchlist struct{}
 
 // NewNoBenchlist returns an empty benchlist that will never stop any queries
 func NewNoBenchlist() Manager { return &noBenchlist{} }
 
-func (noBenchlist) RegisterChain(*snow.ConsensusContext) error { return nil }
-func (noBenchlist) RegisterResponse(ids.ID, ids.ShortID)       {}
-func (noBenchlist) RegisterFailure(ids.ID, ids.ShortID)        {}
-func (noBenchlist) IsBenched(ids.ShortID, ids.ID) bool         { return false }
-func (noBenchlist) GetBenched(ids.ShortID) []ids.ID            { return []ids.ID{} }
+func (noBenchlist) RegisterChain(*snow.ConsensusContext) error       { return nil }
+func (noBenchlist) RegisterResponse(ids.ID, ids.ShortID)             {}
+func (noBenchlist) RegisterFailure(ids.ID, ids.ShortID)              {}
+func (noBenchlist) IsBenched(ids.ShortID, ids.ID) bool               { return false }
+func (noBenchlist) GetBenched(ids.ShortID) []ids.ID                  { return []ids.ID{} }
+func (noBenchlist) Bench(ids.ShortID, time.Duration, []ids.ID) error { return errBenchlistDisabled }
+func (noBenchlist) Unbench(ids.ShortID, []ids.ID) error              { return errBenchlistDisabled }
+func (noBenchlist) Benched() map[ids.ID]map[ids.ShortID]time.Time    { return nil }
@@ -10,12 +10,14 @@ import (
 
 	"github.com/flare-foundation/flare/ids"
 	"github.com/flare-foundation/flare/message"
+	"github.com/flare-foundation/flare/network/throttling"
 	"github.com/flare-foundation/flare/snow"
 	"github.com/flare-foundation/flare/snow/engine/common"
 	"github.com/flare-foundation/flare/snow/networking/tracker"
 	"github.com/flare-foundation/flare/snow/networking/worker"
 	"github.com/flare-foundation/flare/snow/validators"
 	"github.com/flare-foundation/flare/utils/constants"
+	"github.com/flare-foundation/flare/utils/logging"
 	"github.com/flare-foundation/flare/utils/timer/mockable"
 	"github.com/flare-foundation/flare/utils/uptime"
 	"github.com/flare-foundation/flare/version"
@@ -66,6 +68,8 @@ type handler struct {
 	msgFromVMChan   <-chan common.Message
 	preemptTimeouts chan struct{}
 	gossipFrequency time.Duration
+	// Drops unrequested messages that exceed this chain's allocation
+	throttler throttling.ChainMsgThrottler
 
 	bootstrapper common.BootstrapableEngine
 	engine       common.Engine
@@ -101,6 +105,7 @@ func New(
 	msgFromVMChan <-chan common.Message,
 	preemptTimeouts chan struct{},
 	gossipFrequency time.Duration,
+	throttlerConfig throttling.ChainMsgThrottlerConfig,
 ) (Handler, error) {
 	h := &handler{
 		ctx:             ctx,
@@ -123,6 +128,10 @@ func New(
 	if err != nil {
 		return nil, fmt.Errorf("initializing handler metrics errored with: %w", err)
 	}
+	h.throttler, err = throttling.NewChainMsgThrottler("handler", h.ctx.Registerer, throttlerConfig)
+	if err != nil {
+		return nil, fmt.Errorf("initializing chain message throttler errored with: %w", err)
+	}
 	h.syncMessageQueue, err = NewMessageQueue(h.ctx.Log, h.validators, h.cpuTracker, "handler", h.ctx.Registerer)
 	if err != nil {
 		return nil, fmt.Errorf("initializing sync message queue errored with: %w", err)
@@ -170,6 +179,12 @@ func (h *handler) Start(recoverPanic bool) {
 
 // Push the message onto the handler's queue
 func (h *handler) Push(msg message.InboundMessage) {
+	if isUnrequested(msg) && !h.throttler.Allow(msg.Op(), uint64(msg.Size())) {
+		h.ctx.Log.Verbo("dropping %s from %s%s due to chain rate-limiting", msg.Op(), constants.NodeIDPrefix, msg.NodeID())
+		msg.OnFinishedHandling()
+		return
+	}
+
 	switch msg.Op() {
 	case message.AppRequest, message.AppGossip, message.AppRequestFailed, message.AppResponse:
 		h.asyncMessageQueue.Push(msg)
@@ -178,6 +193,17 @@ func (h *handler) Push(msg message.InboundMessage) {
 	}
 }
 
+// isUnrequested returns true if [msg] isn't a response to one of our requests.
+// Only such messages are throttled, as dropping a response would leave the
+// engine waiting for it.
+func isUnrequested(msg message.InboundMessage) bool {
+	op := msg.Op()
+	if _, ok := message.UnrequestedOps[op]; ok {
+		return true
+	}
+	return op == message.Put && msg.Get(message.RequestID).(uint32) == constants.GossipMsgRequestID
+}
+
 func (h *handler) RegisterTimeout(d time.Duration) {
 	go func() {
 		timer := time.NewTimer(d)
@@ -304,8 +330,22 @@ func (h *handler) dispatchChans() {
 	}
 }
 
+// logForwarded logs at the debug level that [msg], which is a [kind] message,
+// is forwarded to consensus, with the request ID of [msg] if it has one. The
+// logger with the request ID is only built if debug events are logged.
+func (h *handler) logForwarded(kind string, msg message.InboundMessage) {
+	log := h.ctx.Log
+	if logging.Debug > log.GetLogLevel() && logging.Debug > log.GetDisplayLevel() {
+		return
+	}
+	if requestID, ok := msg.Get(message.RequestID).(uint32); ok {
+		log = log.With(logging.RequestID(requestID))
+	}
+	log.Debug("Forwarding %s message to consensus: %s", kind, msg)
+}
+
 func (h *handler) handleSyncMsg(msg message.InboundMessage) error {
-	h.ctx.Log.Debug("Forwarding sync message to consensus: %s", msg)
+	h.logForwarded("sync", msg)
 
 	var (
 		nodeID    = msg.NodeID()
@@ -484,7 +524,7 @@ func (h *handler) handleAsyncMsg(msg message.InboundMessage) {
 }
 
 func (h *handler) executeAsyncMsg(msg message.InboundMessage) error {
-	h.ctx.Log.Debug("Forwarding async message to consensus: %s", msg)
+	h.logForwarded("async", msg)
 
 	var (
 		nodeID    = msg.NodeID()
@@ -538,7 +578,7 @@ func (h *handler) executeAsyncMsg(msg message.InboundMessage) error {
 }
 
 func (h *handler) handleChanMsg(msg message.InboundMessage) error {
-	h.ctx.Log.Debug("Forwarding chan message to consensus: %s", msg)
+	h.logForwarded("chan", msg)
 
 	var (
 		op        = msg.Op()
@@ -13,9 +13,11 @@ import (
 
 	"github.com/flare-foundation/flare/ids"
 	"github.com/flare-foundation/flare/message"
+	"github.com/flare-foundation/flare/network/throttling"
 	"github.com/flare-foundation/flare/snow"
 	"github.com/flare-foundation/flare/snow/engine/common"
 	"github.com/flare-foundation/flare/snow/validators"
+	"github.com/flare-foundation/flare/utils/constants"
 )
 
 func TestHandlerDropsTimedOutMessages(t *testing.T) {
@@ -39,6 +41,7 @@ func TestHandlerDropsTimedOutMessages(t *testing.T) {
 		nil,
 		nil,
 		time.Second,
+		throttling.ChainMsgThrottlerConfig{},
 	)
 	assert.NoError(t, err)
 	handler := handlerIntf.(*handler)
@@ -112,6 +115,7 @@ func TestHandlerClosesOnError(t *testing.T) {
 		nil,
 		nil,
 		time.Second,
+		throttling.ChainMsgThrottlerConfig{},
 	)
 	assert.NoError(t, err)
 	handler := handlerIntf.(*handler)
@@ -178,6 +182,7 @@ func TestHandlerDropsGossipDuringBootstrapping(t *testing.T) {
 		nil,
 		nil,
 		1,
+		throttling.ChainMsgThrottlerConfig{},
 	)
 	assert.NoError(t, err)
 	handler := handlerIntf.(*handler)
@@ -236,6 +241,7 @@ func TestHandlerDispatchInternal(t *testing.T) {
 		msgFromVMChan,
 		nil,
 		time.Second,
+		throttling.ChainMsgThrottlerConfig{},
 	)
 	assert.NoError(t, err)
 
@@ -258,3 +264,42 @@ func TestHandlerDispatchInternal(t *testing.T) {
 	case <-calledNotify:
 	}
 }
+
+func TestHandlerThrottlesUnrequestedMessages(t *testing.T) {
+	metrics := prometheus.NewRegistry()
+	mc, err := message.NewCreator(metrics, true /*compressionEnabled*/, "dummyNamespace")
+	assert.NoError(t, err)
+
+	ctx := snow.DefaultConsensusContextTest()
+
+	vdrs := validators.NewSet()
+	vdr0 := ids.GenerateTestShortID()
+	err = vdrs.AddWeight(vdr0, 1)
+	assert.NoError(t, err)
+
+	handlerIntf, err := New(
+		mc,
+		ctx,
+		vdrs,
+		nil,
+		nil,
+		time.Second,
+		throttling.ChainMsgThrottlerConfig{
+			MsgRefillRate:   1,
+			MsgMaxBurstSize: 1,
+		},
+	)
+	assert.NoError(t, err)
+	handler := handlerIntf.(*handler)
+
+	chainID := ids.ID{}
+	containerID := ids.GenerateTestID()
+	handler.Push(mc.InboundPut(chainID, constants.GossipMsgRequestID, containerID, []byte{1}, vdr0))
+	handler.Push(mc.InboundPut(chainID, constants.GossipMsgRequestID, containerID, []byte{1}, vdr0))
+	assert.Equal(t, 1, handler.syncMessageQueue.Len())
+
+	// Responses to our requests are never dropped
+	handler.Push(mc.InboundPut(chainID, 1, containerID, []byte{1}, vdr0))
+	handler.Push(mc.InboundAccepted(chainID, 2, nil, vdr0))
+	assert.Equal(t, 3, handler.syncMessageQueue.Len())
+}
@@ -0,0 +1,51 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package reputation
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/flare-foundation/flare/utils/wrappers"
+)
+
+type metrics struct {
+	offenses        [numOffenses]prometheus.Counter
+	bans            prometheus.Counter
+	banned, tracked prometheus.Gauge
+}
+
+func (m *metrics) initialize(namespace string, registerer prometheus.Registerer) error {
+	offenses := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reputation_offenses",
+		Help:      "Number of offenses committed by peers",
+	}, []string{"offense"})
+	for i := range m.offenses {
+		m.offenses[i] = offenses.WithLabelValues(Offense(i).String())
+	}
+	m.bans = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reputation_bans",
+		Help:      "Number of times a peer was banned because of its reputation",
+	})
+	m.banned = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "reputation_banned",
+		Help:      "Number of currently banned peers",
+	})
+	m.tracked = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "reputation_tracked",
+		Help:      "Number of peers with a non-zero reputation score",
+	})
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		registerer.Register(offenses),
+		registerer.Register(m.bans),
+		registerer.Register(m.banned),
+		registerer.Register(m.tracked),
+	)
+	return errs.Err
+}
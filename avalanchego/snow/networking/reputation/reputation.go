@@ -0,0 +1,319 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package reputation
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/utils/constants"
+	"github.com/flare-foundation/flare/utils/json"
+	"github.com/flare-foundation/flare/utils/logging"
+	"github.com/flare-foundation/flare/utils/timer/mockable"
+)
+
+// Scores below this value are considered to have decayed to 0
+const minScore = 0.001
+
+// Offense is a kind of misbehaviour that lowers the reputation of a node
+type Offense byte
+
+const (
+	// Timeout is a request to the node that timed out
+	Timeout Offense = iota
+	// MalformedMessage is a message from the node that couldn't be parsed
+	MalformedMessage
+	// Throttled is a message from the node that had to wait because too many
+	// messages from the node were being processed
+	Throttled
+	// InvalidBlock is a block from the node that failed verification
+	InvalidBlock
+
+	numOffenses = int(InvalidBlock) + 1
+)
+
+func (o Offense) String() string {
+	switch o {
+	case Timeout:
+		return "timeout"
+	case MalformedMessage:
+		return "malformed_message"
+	case Throttled:
+		return "throttled"
+	case InvalidBlock:
+		return "invalid_block"
+	default:
+		return fmt.Sprintf("unknown offense %d", o)
+	}
+}
+
+// Tracker keeps track of the reputation of the nodes we interact with.
+//
+// Every offense adds a penalty to the score of a node, and the score decays
+// over time. A node whose score reaches the ban threshold is disconnected and
+// connections with it are refused until the ban expires.
+type Tracker interface {
+	// Penalize registers that [nodeID] committed [offense]
+	Penalize(nodeID ids.ShortID, offense Offense)
+	// IsBanned returns true if connections with [nodeID] should be refused
+	IsBanned(nodeID ids.ShortID) bool
+	// Scores returns the reputation of the nodes in [nodeIDs], or of every node
+	// with a non-zero score if [nodeIDs] is empty
+	Scores(nodeIDs []ids.ShortID) []PeerScore
+}
+
+// Bannable is notified when a node is banned
+type Bannable interface {
+	// Banned is called when [nodeID] is banned until [until]
+	Banned(nodeID ids.ShortID, until time.Time)
+}
+
+// Config defines the penalties of each offense and when nodes are banned
+type Config struct {
+	// Time it takes for a score to decay to half its value
+	HalfLife time.Duration `json:"halfLife"`
+
+	TimeoutPenalty          float64 `json:"timeoutPenalty"`
+	MalformedMessagePenalty float64 `json:"malformedMessagePenalty"`
+	ThrottledPenalty        float64 `json:"throttledPenalty"`
+	InvalidBlockPenalty     float64 `json:"invalidBlockPenalty"`
+
+	// A node is banned once its score reaches [BanThreshold]. If 0, nodes are
+	// never banned.
+	BanThreshold float64       `json:"banThreshold"`
+	BanDuration  time.Duration `json:"banDuration"`
+}
+
+func (c *Config) penalty(offense Offense) float64 {
+	switch offense {
+	case Timeout:
+		return c.TimeoutPenalty
+	case MalformedMessage:
+		return c.MalformedMessagePenalty
+	case Throttled:
+		return c.ThrottledPenalty
+	case InvalidBlock:
+		return c.InvalidBlockPenalty
+	default:
+		return 0
+	}
+}
+
+// PeerScore is the reputation of a node
+type PeerScore struct {
+	NodeID string       `json:"nodeID"`
+	Score  json.Float64 `json:"score"`
+
+	// Number of offenses of the node since it was last seen with a score of 0
+	Timeouts          json.Uint64 `json:"timeouts"`
+	MalformedMessages json.Uint64 `json:"malformedMessages"`
+	Throttled         json.Uint64 `json:"throttled"`
+	InvalidBlocks     json.Uint64 `json:"invalidBlocks"`
+
+	BannedUntil *time.Time `json:"bannedUntil,omitempty"`
+}
+
+type nodeScore struct {
+	// Score at [updated]
+	score   float64
+	updated time.Time
+
+	offenses    [numOffenses]uint64
+	bannedUntil time.Time
+}
+
+type tracker struct {
+	config   Config
+	log      logging.Logger
+	bannable Bannable
+	metrics  metrics
+
+	// Tells the time. Can be faked for testing.
+	clock mockable.Clock
+
+	lock sync.Mutex
+	// Node ID --> Reputation of the node
+	// Nodes whose score decayed to 0 and that aren't banned are removed.
+	scores map[ids.ShortID]*nodeScore
+	// Time of the last removal of nodes whose score decayed to 0
+	lastPruned time.Time
+}
+
+// NewTracker returns a new reputation tracker. [bannable] is notified of bans.
+func NewTracker(
+	config Config,
+	bannable Bannable,
+	log logging.Logger,
+	namespace string,
+	registerer prometheus.Registerer,
+) (Tracker, error) {
+	if config.HalfLife <= 0 {
+		return nil, fmt.Errorf("reputation half-life must be positive but got %s", config.HalfLife)
+	}
+	if config.BanThreshold < 0 {
+		return nil, fmt.Errorf("reputation ban threshold must be non-negative but got %f", config.BanThreshold)
+	}
+	t := &tracker{
+		config:   config,
+		log:      log,
+		bannable: bannable,
+		scores:   make(map[ids.ShortID]*nodeScore),
+	}
+	return t, t.metrics.initialize(namespace, registerer)
+}
+
+// Penalize implements the Tracker interface
+func (t *tracker) Penalize(nodeID ids.ShortID, offense Offense) {
+	if int(offense) >= numOffenses {
+		return
+	}
+	t.metrics.offenses[offense].Inc()
+
+	t.lock.Lock()
+	now := t.clock.Time()
+	t.prune(now)
+
+	s, ok := t.scores[nodeID]
+	if !ok {
+		s = &nodeScore{updated: now}
+		t.scores[nodeID] = s
+		t.metrics.tracked.Set(float64(len(t.scores)))
+	}
+	s.score = t.decayed(s, now) + t.config.penalty(offense)
+	s.updated = now
+	s.offenses[offense]++
+
+	if t.config.BanThreshold == 0 || s.score < t.config.BanThreshold || now.Before(s.bannedUntil) {
+		t.lock.Unlock()
+		return
+	}
+
+	// The score starts over once the node is banned, so that the node isn't
+	// banned again as soon as the ban expires.
+	s.score = 0
+	s.bannedUntil = now.Add(t.config.BanDuration)
+	until := s.bannedUntil
+	total := s.total()
+	t.metrics.bans.Inc()
+	t.metrics.banned.Set(float64(t.numBanned(now)))
+	t.lock.Unlock()
+
+	t.log.Info("banning %s%s until %s after %d offenses, last one being %s",
+		constants.NodeIDPrefix, nodeID, until, total, offense)
+	t.bannable.Banned(nodeID, until)
+}
+
+// IsBanned implements the Tracker interface
+func (t *tracker) IsBanned(nodeID ids.ShortID) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	s, ok := t.scores[nodeID]
+	return ok && t.clock.Time().Before(s.bannedUntil)
+}
+
+// Scores implements the Tracker interface
+func (t *tracker) Scores(nodeIDs []ids.ShortID) []PeerScore {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	now := t.clock.Time()
+	t.prune(now)
+
+	if len(nodeIDs) == 0 {
+		nodeIDs = make([]ids.ShortID, 0, len(t.scores))
+		for nodeID := range t.scores {
+			nodeIDs = append(nodeIDs, nodeID)
+		}
+		ids.SortShortIDs(nodeIDs)
+	}
+
+	scores := make([]PeerScore, 0, len(nodeIDs))
+	for _, nodeID := range nodeIDs {
+		score := PeerScore{
+			NodeID: nodeID.PrefixedString(constants.NodeIDPrefix),
+		}
+		if s, ok := t.scores[nodeID]; ok {
+			score.Score = json.Float64(t.decayed(s, now))
+			score.Timeouts = json.Uint64(s.offenses[Timeout])
+			score.MalformedMessages = json.Uint64(s.offenses[MalformedMessage])
+			score.Throttled = json.Uint64(s.offenses[Throttled])
+			score.InvalidBlocks = json.Uint64(s.offenses[InvalidBlock])
+			if now.Before(s.bannedUntil) {
+				bannedUntil := s.bannedUntil
+				score.BannedUntil = &bannedUntil
+			}
+		}
+		scores = append(scores, score)
+	}
+	// Banned nodes come first, then the nodes with the worst reputation
+	sort.SliceStable(scores, func(i, j int) bool {
+		if iBanned, jBanned := scores[i].BannedUntil != nil, scores[j].BannedUntil != nil; iBanned != jBanned {
+			return iBanned
+		}
+		return scores[i].Score > scores[j].Score
+	})
+	return scores
+}
+
+// decayed returns the score of [s] at [now]
+func (t *tracker) decayed(s *nodeScore, now time.Time) float64 {
+	elapsed := now.Sub(s.updated)
+	if elapsed <= 0 {
+		return s.score
+	}
+	return s.score * math.Exp2(-float64(elapsed)/float64(t.config.HalfLife))
+}
+
+// prune removes the nodes whose score decayed to 0 and that aren't banned, at
+// most once per half-life. Assumes [t.lock] is held.
+func (t *tracker) prune(now time.Time) {
+	if now.Sub(t.lastPruned) < t.config.HalfLife {
+		return
+	}
+	t.lastPruned = now
+
+	for nodeID, s := range t.scores {
+		if t.decayed(s, now) < minScore && !now.Before(s.bannedUntil) {
+			delete(t.scores, nodeID)
+		}
+	}
+	t.metrics.tracked.Set(float64(len(t.scores)))
+	t.metrics.banned.Set(float64(t.numBanned(now)))
+}
+
+// numBanned returns the number of nodes banned at [now]. Assumes [t.lock] is
+// held.
+func (t *tracker) numBanned(now time.Time) int {
+	numBanned := 0
+	for _, s := range t.scores {
+		if now.Before(s.bannedUntil) {
+			numBanned++
+		}
+	}
+	return numBanned
+}
+
+func (s *nodeScore) total() uint64 {
+	total := uint64(0)
+	for _, count := range s.offenses {
+		total += count
+	}
+	return total
+}
+
+type noTracker struct{}
+
+// NewNoTracker returns a tracker that ignores offenses and never bans nodes
+func NewNoTracker() Tracker { return noTracker{} }
+
+func (noTracker) Penalize(ids.ShortID, Offense)    {}
+func (noTracker) IsBanned(ids.ShortID) bool        { return false }
+func (noTracker) Scores([]ids.ShortID) []PeerScore { return nil }
@@ -0,0 +1,140 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package reputation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/snow/networking/benchlist"
+	"github.com/flare-foundation/flare/utils/logging"
+)
+
+type testBannable map[ids.ShortID]time.Time
+
+func (b testBannable) Banned(nodeID ids.ShortID, until time.Time) { b[nodeID] = until }
+
+var testConfig = Config{
+	HalfLife:                time.Minute,
+	TimeoutPenalty:          1,
+	MalformedMessagePenalty: 10,
+	ThrottledPenalty:        0.1,
+	InvalidBlockPenalty:     20,
+	BanThreshold:            30,
+	BanDuration:             10 * time.Minute,
+}
+
+func newTestTracker(t *testing.T, config Config) (*tracker, testBannable) {
+	bannable := testBannable{}
+	trackerIntf, err := NewTracker(config, bannable, logging.NoLog{}, "", prometheus.NewRegistry())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tracker := trackerIntf.(*tracker)
+	tracker.clock.Set(time.Unix(1000000, 0))
+	return tracker, bannable
+}
+
+func TestTrackerInvalidConfig(t *testing.T) {
+	config := testConfig
+	config.HalfLife = 0
+	_, err := NewTracker(config, testBannable{}, logging.NoLog{}, "", prometheus.NewRegistry())
+	assert.Error(t, err)
+
+	config = testConfig
+	config.BanThreshold = -1
+	_, err = NewTracker(config, testBannable{}, logging.NoLog{}, "", prometheus.NewRegistry())
+	assert.Error(t, err)
+}
+
+func TestTrackerScoreDecays(t *testing.T) {
+	assert := assert.New(t)
+	tracker, _ := newTestTracker(t, testConfig)
+	nodeID := ids.GenerateTestShortID()
+
+	tracker.Penalize(nodeID, MalformedMessage)
+	tracker.Penalize(nodeID, Timeout)
+	tracker.Penalize(nodeID, Timeout)
+
+	scores := tracker.Scores(nil)
+	assert.Len(scores, 1)
+	assert.InDelta(12, float64(scores[0].Score), 0.001)
+	assert.EqualValues(2, scores[0].Timeouts)
+	assert.EqualValues(1, scores[0].MalformedMessages)
+	assert.Nil(scores[0].BannedUntil)
+
+	tracker.clock.Set(tracker.clock.Time().Add(time.Minute))
+	scores = tracker.Scores([]ids.ShortID{nodeID})
+	assert.InDelta(6, float64(scores[0].Score), 0.001)
+
+	// The node is forgotten once its score decayed to 0
+	tracker.clock.Set(tracker.clock.Time().Add(time.Hour))
+	assert.Empty(tracker.Scores(nil))
+	scores = tracker.Scores([]ids.ShortID{nodeID})
+	assert.Len(scores, 1)
+	assert.Zero(scores[0].Score)
+	assert.Zero(scores[0].Timeouts)
+}
+
+func TestTrackerBan(t *testing.T) {
+	assert := assert.New(t)
+	tracker, bannable := newTestTracker(t, testConfig)
+	nodeID := ids.GenerateTestShortID()
+	otherNodeID := ids.GenerateTestShortID()
+
+	tracker.Penalize(otherNodeID, Throttled)
+	tracker.Penalize(nodeID, InvalidBlock)
+	assert.False(tracker.IsBanned(nodeID))
+	assert.Empty(bannable)
+
+	tracker.Penalize(nodeID, MalformedMessage)
+	assert.True(tracker.IsBanned(nodeID))
+	assert.False(tracker.IsBanned(otherNodeID))
+	assert.Equal(testBannable{nodeID: tracker.clock.Time().Add(testConfig.BanDuration)}, bannable)
+
+	scores := tracker.Scores(nil)
+	assert.Len(scores, 2)
+	assert.NotNil(scores[0].BannedUntil)
+
+	// Offenses while banned don't extend the ban
+	delete(bannable, nodeID)
+	tracker.Penalize(nodeID, InvalidBlock)
+	tracker.Penalize(nodeID, InvalidBlock)
+	assert.Empty(bannable)
+
+	tracker.clock.Set(tracker.clock.Time().Add(testConfig.BanDuration))
+	assert.False(tracker.IsBanned(nodeID))
+}
+
+func TestTrackerBansDisabled(t *testing.T) {
+	config := testConfig
+	config.BanThreshold = 0
+	tracker, bannable := newTestTracker(t, config)
+	nodeID := ids.GenerateTestShortID()
+
+	for i := 0; i < 10; i++ {
+		tracker.Penalize(nodeID, InvalidBlock)
+	}
+	assert.False(t, tracker.IsBanned(nodeID))
+	assert.Empty(t, bannable)
+}
+
+func TestTrackTimeouts(t *testing.T) {
+	assert := assert.New(t)
+	tracker, _ := newTestTracker(t, testConfig)
+	manager := TrackTimeouts(benchlist.NewNoBenchlist(), tracker)
+	nodeID := ids.GenerateTestShortID()
+
+	manager.RegisterResponse(ids.Empty, nodeID)
+	assert.Empty(tracker.Scores(nil))
+
+	manager.RegisterFailure(ids.Empty, nodeID)
+	scores := tracker.Scores(nil)
+	assert.Len(scores, 1)
+	assert.EqualValues(1, scores[0].Timeouts)
+}
@@ -0,0 +1,33 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package reputation
+
+import (
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/snow/networking/benchlist"
+)
+
+var _ benchlist.Manager = &timeoutTracker{}
+
+// timeoutTracker penalizes the validators whose requests time out, in addition
+// to reporting them to the benchlist
+type timeoutTracker struct {
+	benchlist.Manager
+	tracker Tracker
+}
+
+// TrackTimeouts returns a benchlist manager that reports request timeouts to
+// [tracker] as well as to [manager]
+func TrackTimeouts(manager benchlist.Manager, tracker Tracker) benchlist.Manager {
+	return &timeoutTracker{
+		Manager: manager,
+		tracker: tracker,
+	}
+}
+
+// RegisterFailure implements the benchlist.Manager interface
+func (t *timeoutTracker) RegisterFailure(chainID ids.ID, validatorID ids.ShortID) {
+	t.tracker.Penalize(validatorID, Timeout)
+	t.Manager.RegisterFailure(chainID, validatorID)
+}
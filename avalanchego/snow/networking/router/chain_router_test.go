@@ -13,6 +13,7 @@ import (
 
 	"github.com/flare-foundation/flare/ids"
 	"github.com/flare-foundation/flare/message"
+	"github.com/flare-foundation/flare/network/throttling"
 	"github.com/flare-foundation/flare/snow"
 	"github.com/flare-foundation/flare/snow/engine/common"
 	"github.com/flare-foundation/flare/snow/networking/benchlist"
@@ -65,6 +66,7 @@ func TestShutdown(t *testing.T) {
 		nil,
 		nil,
 		time.Second,
+		throttling.ChainMsgThrottlerConfig{},
 	)
 	assert.NoError(t, err)
 
@@ -164,6 +166,7 @@ func TestShutdownTimesOut(t *testing.T) {
 		nil,
 		nil,
 		time.Second,
+		throttling.ChainMsgThrottlerConfig{},
 	)
 	assert.NoError(t, err)
 
@@ -272,6 +275,7 @@ func TestRouterTimeout(t *testing.T) {
 		nil,
 		nil,
 		time.Second,
+		throttling.ChainMsgThrottlerConfig{},
 	)
 	assert.NoError(t, err)
 
@@ -385,6 +389,7 @@ func TestRouterClearTimeouts(t *testing.T) {
 		nil,
 		nil,
 		time.Second,
+		throttling.ChainMsgThrottlerConfig{},
 	)
 	assert.NoError(t, err)
 
@@ -498,6 +503,7 @@ func TestValidatorOnlyMessageDrops(t *testing.T) {
 		nil,
 		nil,
 		time.Second,
+		throttling.ChainMsgThrottlerConfig{},
 	)
 	assert.NoError(t, err)
 
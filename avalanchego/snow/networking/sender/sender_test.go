@@ -15,6 +15,7 @@ import (
 
 	"github.com/flare-foundation/flare/ids"
 	"github.com/flare-foundation/flare/message"
+	"github.com/flare-foundation/flare/network/throttling"
 	"github.com/flare-foundation/flare/snow"
 	"github.com/flare-foundation/flare/snow/engine/common"
 	"github.com/flare-foundation/flare/snow/networking/benchlist"
@@ -99,6 +100,7 @@ func TestTimeout(t *testing.T) {
 		nil,
 		nil,
 		time.Hour,
+		throttling.ChainMsgThrottlerConfig{},
 	)
 	assert.NoError(t, err)
 
@@ -185,6 +187,7 @@ func TestReliableMessages(t *testing.T) {
 		nil,
 		nil,
 		1,
+		throttling.ChainMsgThrottlerConfig{},
 	)
 	assert.NoError(t, err)
 
@@ -277,6 +280,7 @@ func TestReliableMessagesToMyself(t *testing.T) {
 		nil,
 		nil,
 		time.Second,
+		throttling.ChainMsgThrottlerConfig{},
 	)
 	assert.NoError(t, err)
 
@@ -0,0 +1,33 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package simulator
+
+import (
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/message"
+)
+
+var (
+	_ Byzantine = ByzantineFunc(nil)
+
+	// Mute drops every message sent by a node to other nodes
+	Mute Byzantine = ByzantineFunc(func(ids.ShortID, message.InboundMessage, message.Creator) []message.InboundMessage {
+		return nil
+	})
+)
+
+// Byzantine tampers with the messages sent by a node to other nodes
+type Byzantine interface {
+	// Tamper returns the messages delivered to the node [to] instead of
+	// [msg]. [mc] can be used to forge messages. Forged messages must be sent
+	// from the node of [msg] for the receiver to accept them.
+	Tamper(to ids.ShortID, msg message.InboundMessage, mc message.Creator) []message.InboundMessage
+}
+
+// ByzantineFunc is a function that implements Byzantine
+type ByzantineFunc func(to ids.ShortID, msg message.InboundMessage, mc message.Creator) []message.InboundMessage
+
+func (f ByzantineFunc) Tamper(to ids.ShortID, msg message.InboundMessage, mc message.Creator) []message.InboundMessage {
+	return f(to, msg, mc)
+}
@@ -0,0 +1,53 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package simulator
+
+import (
+	"bytes"
+	"container/heap"
+	"time"
+)
+
+var _ heap.Interface = &eventQueue{}
+
+// event is an action executed by the simulator at a point in simulated time
+type event struct {
+	time time.Time
+	// key orders events scheduled at the same time. It is derived from the
+	// content of the event rather than from the order in which events were
+	// scheduled, so that the order doesn't depend on map iteration order in
+	// the engine.
+	key []byte
+	// seq orders events with the same time and key
+	seq uint64
+	run func() error
+}
+
+// eventQueue is a min-heap of events ordered by time, key and sequence
+type eventQueue []*event
+
+func (q eventQueue) Len() int { return len(q) }
+
+func (q eventQueue) Less(i, j int) bool {
+	if !q[i].time.Equal(q[j].time) {
+		return q[i].time.Before(q[j].time)
+	}
+	if cmp := bytes.Compare(q[i].key, q[j].key); cmp != 0 {
+		return cmp < 0
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q eventQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *eventQueue) Push(x interface{}) { *q = append(*q, x.(*event)) }
+
+func (q *eventQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	e := old[n-1]
+	old[n-1] = nil
+	*q = old[:n-1]
+	return e
+}
@@ -0,0 +1,65 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package simulator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/flare-foundation/flare/coreth/core"
+	"github.com/flare-foundation/flare/coreth/core/types"
+	"github.com/flare-foundation/flare/coreth/params"
+	"github.com/flare-foundation/flare/coreth/plugin/evm"
+	"github.com/flare-foundation/flare/snow/engine/snowman/block"
+	"github.com/flare-foundation/flare/utils/wrappers"
+)
+
+const evmGasLimit = 100_000_000
+
+var (
+	errNotEVM = errors.New("node doesn't run the EVM")
+
+	// EVMChainID is the EIP-155 chain ID of the chain built by EVMGenesis
+	EVMChainID = big.NewInt(31337)
+)
+
+// NewEVM returns the EVM of the C-Chain, to be used as [Config.NewVM]
+func NewEVM(int) block.ChainVM { return &evm.VM{} }
+
+// EVMGenesis returns the genesis of an EVM chain where every network upgrade
+// is active from the start and the accounts in [alloc] are funded.
+func EVMGenesis(alloc core.GenesisAlloc) ([]byte, error) {
+	config := *params.TestChainConfig
+	config.ChainID = EVMChainID
+	return json.Marshal(&core.Genesis{
+		Config:     &config,
+		GasLimit:   evmGasLimit,
+		Difficulty: big.NewInt(0),
+		Alloc:      alloc,
+	})
+}
+
+// IssueEthTxs adds [txs] to the pending transactions of the EVM of node [i] at
+// the current simulated time. The EVM doesn't gossip transactions during
+// simulations, so [txs] are only built into blocks by node [i].
+func (s *Simulator) IssueEthTxs(i int, txs []*types.Transaction) {
+	node := s.nodes[i]
+	s.schedule(s.Now(), eventKey("issue", i), func() error {
+		vm, ok := node.VM.(*evm.VM)
+		if !ok {
+			return fmt.Errorf("%w: %d", errNotEVM, i)
+		}
+
+		node.ctx.Lock.Lock()
+		errs := wrappers.Errs{}
+		errs.Add(vm.TxPool().AddRemotesSync(txs)...)
+		node.ctx.Lock.Unlock()
+		if errs.Errored() {
+			return fmt.Errorf("node %d couldn't issue transactions: %w", i, errs.Err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,282 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package simulator
+
+import (
+	"time"
+
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/message"
+	"github.com/flare-foundation/flare/network"
+	"github.com/flare-foundation/flare/network/peerfilter"
+	"github.com/flare-foundation/flare/snow"
+	"github.com/flare-foundation/flare/snow/networking/reputation"
+	"github.com/flare-foundation/flare/snow/networking/router"
+	"github.com/flare-foundation/flare/utils"
+)
+
+var (
+	_ network.Network = &memoryNetwork{}
+	_ router.Router   = &localRouter{}
+	_ message.Creator = &localCreator{}
+)
+
+// memoryNetwork is the network of a simulated node. Messages are handed to the
+// simulator, which delivers them in simulated time.
+type memoryNetwork struct {
+	simulator *Simulator
+	node      *Node
+	closed    chan struct{}
+}
+
+func newMemoryNetwork(s *Simulator, n *Node) *memoryNetwork {
+	return &memoryNetwork{
+		simulator: s,
+		node:      n,
+		closed:    make(chan struct{}),
+	}
+}
+
+// Send hands [msg] to the simulator for every node in [nodeIDs]. Like the real
+// network, messages that are lost in transit are reported as sent.
+func (n *memoryNetwork) Send(msg message.OutboundMessage, nodeIDs ids.ShortSet, _ ids.ID, _ bool) ids.ShortSet {
+	sentTo := ids.NewShortSet(nodeIDs.Len())
+	for _, nodeID := range sortedShortIDs(nodeIDs) {
+		if n.simulator.send(n.node, nodeID, msg) {
+			sentTo.Add(nodeID)
+		}
+	}
+	return sentTo
+}
+
+// Gossip sends [msg] to a sample of the other nodes. The sample only depends
+// on the seed of the simulation and the content of the message.
+func (n *memoryNetwork) Gossip(
+	msg message.OutboundMessage,
+	_ ids.ID,
+	_ bool,
+	numValidatorsToSend int,
+	numNonValidatorsToSend int,
+) ids.ShortSet {
+	// Every node of the simulation is a validator, so non-validator slots are
+	// filled with validators as well.
+	peers := n.simulator.peers(n.node)
+	rng := n.simulator.rng([]byte{byte(n.node.Index >> 8), byte(n.node.Index)}, msg.Bytes())
+	rng.Shuffle(len(peers), func(i, j int) { peers[i], peers[j] = peers[j], peers[i] })
+	if size := numValidatorsToSend + numNonValidatorsToSend; size < len(peers) {
+		peers = peers[:size]
+	}
+	ids.SortShortIDs(peers)
+
+	sentTo := ids.NewShortSet(len(peers))
+	for _, nodeID := range peers {
+		if n.simulator.send(n.node, nodeID, msg) {
+			sentTo.Add(nodeID)
+		}
+	}
+	return sentTo
+}
+
+func (n *memoryNetwork) Accept(*snow.ConsensusContext, ids.ID, []byte) error { return nil }
+
+func (n *memoryNetwork) Dispatch() error {
+	<-n.closed
+	return errNetworkClosed
+}
+
+func (n *memoryNetwork) TrackIP(utils.IPDesc) {}
+
+func (n *memoryNetwork) Track(utils.IPDesc, ids.ShortID) {}
+
+func (n *memoryNetwork) Peers([]ids.ShortID) []network.PeerInfo { return nil }
+
+func (n *memoryNetwork) Close() error {
+	select {
+	case <-n.closed:
+	default:
+		close(n.closed)
+	}
+	return nil
+}
+
+func (n *memoryNetwork) IP() utils.IPDesc { return utils.IPDesc{} }
+
+func (n *memoryNetwork) NodeUptime() (network.UptimeResult, bool) {
+	return network.UptimeResult{
+		WeightedAveragePercentage: 100,
+		RewardingStakePercentage:  100,
+	}, true
+}
+
+func (n *memoryNetwork) Reputation() reputation.Tracker { return reputation.NewNoTracker() }
+
+func (n *memoryNetwork) PeerFilter() peerfilter.Config { return peerfilter.Config{} }
+
+func (n *memoryNetwork) UpdatePeerFilter(peerfilter.Config) error { return nil }
+
+func (n *memoryNetwork) UpdateSentries(network.SentryConfig) error { return nil }
+
+func (n *memoryNetwork) HealthCheck() (interface{}, error) { return nil, nil }
+
+// The sender hands messages addressed to the node itself, and failures of
+// requests it couldn't send, to the router in a new goroutine. localCreator
+// and localRouter take these messages out of the goroutine and hand them to
+// the simulator instead, so that they're delivered deterministically.
+//
+// localCreator counts the messages created by the sender, which are handed to
+// the router in a goroutine, so that the simulator can wait for them.
+type localCreator struct {
+	message.Creator
+	node *Node
+}
+
+func (c *localCreator) track(msg message.InboundMessage) message.InboundMessage {
+	c.node.pending.Add(1)
+	return msg
+}
+
+func (c *localCreator) InboundGetAcceptedFrontier(
+	chainID ids.ID,
+	requestID uint32,
+	deadline time.Duration,
+	nodeID ids.ShortID,
+) message.InboundMessage {
+	return c.track(c.Creator.InboundGetAcceptedFrontier(chainID, requestID, deadline, nodeID))
+}
+
+func (c *localCreator) InboundAcceptedFrontier(
+	chainID ids.ID,
+	requestID uint32,
+	containerIDs []ids.ID,
+	nodeID ids.ShortID,
+) message.InboundMessage {
+	return c.track(c.Creator.InboundAcceptedFrontier(chainID, requestID, containerIDs, nodeID))
+}
+
+func (c *localCreator) InboundGetAccepted(
+	chainID ids.ID,
+	requestID uint32,
+	deadline time.Duration,
+	containerIDs []ids.ID,
+	nodeID ids.ShortID,
+) message.InboundMessage {
+	return c.track(c.Creator.InboundGetAccepted(chainID, requestID, deadline, containerIDs, nodeID))
+}
+
+func (c *localCreator) InboundAccepted(
+	chainID ids.ID,
+	requestID uint32,
+	containerIDs []ids.ID,
+	nodeID ids.ShortID,
+) message.InboundMessage {
+	return c.track(c.Creator.InboundAccepted(chainID, requestID, containerIDs, nodeID))
+}
+
+func (c *localCreator) InboundAncestors(
+	chainID ids.ID,
+	requestID uint32,
+	containers [][]byte,
+	nodeID ids.ShortID,
+) message.InboundMessage {
+	return c.track(c.Creator.InboundAncestors(chainID, requestID, containers, nodeID))
+}
+
+func (c *localCreator) InboundPushQuery(
+	chainID ids.ID,
+	requestID uint32,
+	deadline time.Duration,
+	containerID ids.ID,
+	container []byte,
+	nodeID ids.ShortID,
+) message.InboundMessage {
+	return c.track(c.Creator.InboundPushQuery(chainID, requestID, deadline, containerID, container, nodeID))
+}
+
+func (c *localCreator) InboundPullQuery(
+	chainID ids.ID,
+	requestID uint32,
+	deadline time.Duration,
+	containerID ids.ID,
+	nodeID ids.ShortID,
+) message.InboundMessage {
+	return c.track(c.Creator.InboundPullQuery(chainID, requestID, deadline, containerID, nodeID))
+}
+
+func (c *localCreator) InboundChits(
+	chainID ids.ID,
+	requestID uint32,
+	containerIDs []ids.ID,
+	nodeID ids.ShortID,
+) message.InboundMessage {
+	return c.track(c.Creator.InboundChits(chainID, requestID, containerIDs, nodeID))
+}
+
+func (c *localCreator) InboundAppRequest(
+	chainID ids.ID,
+	requestID uint32,
+	deadline time.Duration,
+	msg []byte,
+	nodeID ids.ShortID,
+) message.InboundMessage {
+	return c.track(c.Creator.InboundAppRequest(chainID, requestID, deadline, msg, nodeID))
+}
+
+func (c *localCreator) InboundAppResponse(
+	chainID ids.ID,
+	requestID uint32,
+	msg []byte,
+	nodeID ids.ShortID,
+) message.InboundMessage {
+	return c.track(c.Creator.InboundAppResponse(chainID, requestID, msg, nodeID))
+}
+
+func (c *localCreator) InboundGet(
+	chainID ids.ID,
+	requestID uint32,
+	deadline time.Duration,
+	containerID ids.ID,
+	nodeID ids.ShortID,
+) message.InboundMessage {
+	return c.track(c.Creator.InboundGet(chainID, requestID, deadline, containerID, nodeID))
+}
+
+func (c *localCreator) InboundPut(
+	chainID ids.ID,
+	requestID uint32,
+	containerID ids.ID,
+	container []byte,
+	nodeID ids.ShortID,
+) message.InboundMessage {
+	return c.track(c.Creator.InboundPut(chainID, requestID, containerID, container, nodeID))
+}
+
+func (c *localCreator) InternalFailedRequest(
+	op message.Op,
+	nodeID ids.ShortID,
+	chainID ids.ID,
+	requestID uint32,
+) message.InboundMessage {
+	return c.track(c.Creator.InternalFailedRequest(op, nodeID, chainID, requestID))
+}
+
+// localRouter is the router of the sender of a node. Requests are registered
+// with the router of the node, but messages handed to it are scheduled by the
+// simulator.
+type localRouter struct {
+	router.Router
+	simulator *Simulator
+	node      *Node
+}
+
+func (r *localRouter) HandleInbound(msg message.InboundMessage) {
+	r.simulator.sendLocal(r.node, msg)
+	r.node.pending.Done()
+}
+
+// sortedShortIDs returns the IDs in [set] in ascending order
+func sortedShortIDs(set ids.ShortSet) []ids.ShortID {
+	list := set.List()
+	ids.SortShortIDs(list)
+	return list
+}
@@ -0,0 +1,559 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package simulator
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/flare-foundation/flare/api/metrics"
+	"github.com/flare-foundation/flare/chains/atomic"
+	"github.com/flare-foundation/flare/database/manager"
+	"github.com/flare-foundation/flare/database/prefixdb"
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/message"
+	"github.com/flare-foundation/flare/network/throttling"
+	"github.com/flare-foundation/flare/snow"
+	smcon "github.com/flare-foundation/flare/snow/consensus/snowman"
+	"github.com/flare-foundation/flare/snow/engine/common"
+	"github.com/flare-foundation/flare/snow/engine/common/queue"
+	"github.com/flare-foundation/flare/snow/engine/common/tracker"
+	smeng "github.com/flare-foundation/flare/snow/engine/snowman"
+	"github.com/flare-foundation/flare/snow/engine/snowman/block"
+	smbootstrap "github.com/flare-foundation/flare/snow/engine/snowman/bootstrap"
+	snowgetter "github.com/flare-foundation/flare/snow/engine/snowman/getter"
+	"github.com/flare-foundation/flare/snow/networking/benchlist"
+	"github.com/flare-foundation/flare/snow/networking/handler"
+	"github.com/flare-foundation/flare/snow/networking/router"
+	"github.com/flare-foundation/flare/snow/networking/sender"
+	"github.com/flare-foundation/flare/snow/networking/timeout"
+	"github.com/flare-foundation/flare/snow/validators"
+	"github.com/flare-foundation/flare/utils/constants"
+	"github.com/flare-foundation/flare/utils/hashing"
+	"github.com/flare-foundation/flare/utils/timer"
+	"github.com/flare-foundation/flare/utils/timer/mockable"
+	"github.com/flare-foundation/flare/version"
+	"github.com/flare-foundation/flare/vms/proposervm"
+)
+
+const (
+	// The simulator never lets the handler gossip on its own, gossip is
+	// triggered explicitly through [Simulator.Gossip].
+	gossipFrequency = 365 * 24 * time.Hour
+	closeTimeout    = 10 * time.Second
+
+	stakingKeyBits = 2048
+	rsaExponent    = 65537
+)
+
+var (
+	_ validators.State = &staticState{}
+	_ common.Subnet    = &subnet{}
+	_ handler.Handler  = &trackedHandler{}
+	_ common.AppSender = &vmAppSender{}
+
+	// ChainID is the ID of the chain run by every simulated node
+	ChainID = ids.ID{'s', 'i', 'm', 'u', 'l', 'a', 't', 'o', 'r'}
+
+	// certificates must not expire while the simulation is running, and must
+	// not depend on the wall clock to be deterministic
+	certNotBefore = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+	certNotAfter  = time.Date(2100, time.January, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// Node is a validator of the simulated network
+type Node struct {
+	// Index of the node in the simulator
+	Index int
+	// ID of the node, derived from its staking certificate
+	ID ids.ShortID
+	// VM built by [Config.NewVM], wrapped by the proposervm
+	VM block.ChainVM
+
+	ctx        *snow.ConsensusContext
+	proposerVM *proposervm.VM
+	router     *router.ChainRouter
+	handler    *trackedHandler
+	timeouts   timeout.Manager
+	network    *memoryNetwork
+	vmMsgs     chan common.Message
+	closed     chan struct{}
+
+	// pending counts the messages of this node that are being handled, or
+	// that are about to be handed to the simulator
+	pending sync.WaitGroup
+
+	// accepted are the IDs of the blocks accepted by consensus, in order
+	acceptedLock sync.Mutex
+	accepted     []ids.ID
+}
+
+// Accepted returns the IDs of the blocks this node accepted since the start
+// of the simulation, in the order they were accepted.
+func (n *Node) Accepted() []ids.ID {
+	n.acceptedLock.Lock()
+	defer n.acceptedLock.Unlock()
+
+	accepted := make([]ids.ID, len(n.accepted))
+	copy(accepted, n.accepted)
+	return accepted
+}
+
+// LastAccepted returns the ID of the last block accepted by this node
+func (n *Node) LastAccepted() (ids.ID, error) {
+	n.ctx.Lock.Lock()
+	defer n.ctx.Lock.Unlock()
+
+	return n.proposerVM.LastAccepted()
+}
+
+// Bootstrapped returns true once the node finished bootstrapping
+func (n *Node) Bootstrapped() bool {
+	return n.ctx.GetState() == snow.NormalOp
+}
+
+// Stopped returns true if the chain of the node was shut down, either by
+// [Simulator.Shutdown] or because of a fatal error.
+func (n *Node) Stopped() bool {
+	select {
+	case <-n.handler.Stopped():
+		return true
+	default:
+		return false
+	}
+}
+
+func (n *Node) Accept(_ *snow.ConsensusContext, containerID ids.ID, _ []byte) error {
+	n.acceptedLock.Lock()
+	defer n.acceptedLock.Unlock()
+
+	n.accepted = append(n.accepted, containerID)
+	return nil
+}
+
+func (n *Node) Issue(*snow.ConsensusContext, ids.ID, []byte) error  { return nil }
+func (n *Node) Reject(*snow.ConsensusContext, ids.ID, []byte) error { return nil }
+
+// newNode creates the [index]th node of [s], whose staking key is [key].
+// The chain of the node is started but it isn't connected to any peer yet.
+func newNode(s *Simulator, index int, key *rsa.PrivateKey, cert *x509.Certificate, vdrIDs []ids.ShortID) (*Node, error) {
+	n := &Node{
+		Index:  index,
+		ID:     nodeID(cert),
+		VM:     s.config.NewVM(index),
+		vmMsgs: make(chan common.Message, 1),
+		closed: make(chan struct{}),
+	}
+
+	log := s.config.Log
+	registerer := prometheus.NewRegistry()
+	n.ctx = &snow.ConsensusContext{
+		Context: &snow.Context{
+			NetworkID:         constants.LocalID,
+			SubnetID:          constants.PrimaryNetworkID,
+			ChainID:           ChainID,
+			NodeID:            n.ID,
+			Log:               log,
+			BCLookup:          ids.NewAliaser(),
+			Metrics:           metrics.NewOptionalGatherer(),
+			ValidatorState:    &staticState{validators: vdrIDs},
+			StakingLeafSigner: key,
+			StakingCertLeaf:   cert,
+		},
+		Registerer:          registerer,
+		DecisionDispatcher:  n,
+		ConsensusDispatcher: n,
+	}
+
+	vdrs := validators.NewSet()
+	for _, vdrID := range vdrIDs {
+		if err := vdrs.AddWeight(vdrID, 1); err != nil {
+			return nil, err
+		}
+	}
+
+	// Timeouts are driven by the simulator, so the dispatcher of the
+	// timeout manager is never started.
+	if err := n.timeouts.Initialize(
+		&timer.AdaptiveTimeoutConfig{
+			InitialTimeout:     s.config.RequestTimeout,
+			MinimumTimeout:     s.config.RequestTimeout,
+			MaximumTimeout:     s.config.RequestTimeout,
+			TimeoutCoefficient: 1.25,
+			TimeoutHalflife:    5 * time.Minute,
+		},
+		benchlist.NewNoBenchlist(),
+		"timeouts",
+		registerer,
+	); err != nil {
+		return nil, err
+	}
+	if err := n.timeouts.RegisterChain(n.ctx); err != nil {
+		return nil, err
+	}
+
+	n.router = &router.ChainRouter{}
+	if err := n.router.Initialize(
+		n.ID,
+		log,
+		s.msgCreator,
+		&n.timeouts,
+		closeTimeout,
+		ids.Set{},
+		nil,
+		router.HealthConfig{},
+		"router",
+		registerer,
+	); err != nil {
+		return nil, err
+	}
+
+	n.ctx.Lock.Lock()
+	defer n.ctx.Lock.Unlock()
+
+	dbManager := manager.NewMemDB(version.DefaultVersion1_0_0)
+	vmDBManager := dbManager.NewPrefixDBManager([]byte("vm"))
+	atomicMemory := &atomic.Memory{}
+	if err := atomicMemory.Initialize(log, prefixdb.New([]byte("atomic"), dbManager.Current().Database)); err != nil {
+		return nil, err
+	}
+	n.ctx.SharedMemory = atomicMemory.NewSharedMemory(ChainID)
+	blocked, err := queue.NewWithMissing(prefixdb.New([]byte("bs"), dbManager.Current().Database), "block", registerer)
+	if err != nil {
+		return nil, err
+	}
+
+	n.network = newMemoryNetwork(s, n)
+	sender := &sender.Sender{}
+	if err := sender.Initialize(
+		n.ctx,
+		&localCreator{Creator: s.msgCreator, node: n},
+		n.network,
+		&localRouter{Router: n.router, simulator: s, node: n},
+		&n.timeouts,
+		len(vdrIDs),
+		0,
+		len(vdrIDs),
+	); err != nil {
+		return nil, fmt.Errorf("couldn't initialize sender: %w", err)
+	}
+
+	// The proposervm is always enabled so that snowman++ is exercised by
+	// every simulation.
+	n.proposerVM = proposervm.New(n.VM, s.config.StartTime, 0, false)
+	n.setTime(s.now)
+	// Messages sent by the VM to the engine are dropped, as the proposervm
+	// forwards them on the wall clock. The simulator notifies the engines in
+	// turn every [Config.BuildInterval] instead.
+	go func() {
+		for {
+			select {
+			case <-n.vmMsgs:
+			case <-n.closed:
+				return
+			}
+		}
+	}()
+	if err := n.proposerVM.Initialize(
+		n.ctx.Context,
+		vmDBManager,
+		s.config.Genesis,
+		nil,
+		nil,
+		n.vmMsgs,
+		nil,
+		&vmAppSender{AppSender: sender},
+	); err != nil {
+		return nil, fmt.Errorf("couldn't initialize VM: %w", err)
+	}
+
+	h, err := handler.New(
+		s.msgCreator,
+		n.ctx,
+		vdrs,
+		nil,
+		nil,
+		gossipFrequency,
+		throttling.ChainMsgThrottlerConfig{},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't initialize message handler: %w", err)
+	}
+	n.handler = &trackedHandler{Handler: h, pending: &n.pending}
+
+	weight := uint64(len(vdrIDs))
+	sampleK := s.config.Params.K
+	if uint64(sampleK) > weight {
+		sampleK = int(weight)
+	}
+	commonCfg := common.Config{
+		Ctx:                            n.ctx,
+		Validators:                     vdrs,
+		Beacons:                        vdrs,
+		SampleK:                        sampleK,
+		StartupAlpha:                   (3*weight + 3) / 4,
+		Alpha:                          weight/2 + 1,
+		Sender:                         sender,
+		Subnet:                         &subnet{},
+		Timer:                          &nodeTimer{simulator: s, node: n},
+		RetryBootstrap:                 true,
+		RetryBootstrapWarnFrequency:    50,
+		MaxTimeGetAncestors:            50 * time.Millisecond,
+		AncestorsMaxContainersSent:     2000,
+		AncestorsMaxContainersReceived: 2000,
+		SharedCfg:                      &common.SharedConfig{},
+	}
+
+	getter, err := snowgetter.New(n.proposerVM, commonCfg)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't initialize snow base message handler: %w", err)
+	}
+	bootstrapper, err := smbootstrap.New(
+		smbootstrap.Config{
+			Config:        commonCfg,
+			AllGetsServer: getter,
+			Blocked:       blocked,
+			VM:            n.proposerVM,
+			WeightTracker: tracker.NewWeightTracker(vdrs, commonCfg.StartupAlpha),
+			Bootstrapped:  func() {},
+		},
+		func(lastReqID uint32) error {
+			return h.Consensus().Start(lastReqID + 1)
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing snowman bootstrapper: %w", err)
+	}
+	h.SetBootstrapper(bootstrapper)
+
+	engine, err := smeng.New(smeng.Config{
+		Ctx:           n.ctx,
+		AllGetsServer: getter,
+		VM:            n.proposerVM,
+		Sender:        sender,
+		Validators:    vdrs,
+		Params:        s.config.Params,
+		Consensus:     &smcon.Topological{},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error initializing snowman engine: %w", err)
+	}
+	h.SetConsensus(engine)
+
+	n.router.AddChain(n.handler)
+	if err := bootstrapper.Start(0); err != nil {
+		return nil, err
+	}
+	h.Start(false)
+	return n, nil
+}
+
+// setTime sets the clocks of the node to [t]. Assumes the node is idle.
+func (n *Node) setTime(t time.Time) {
+	n.proposerVM.Set(t)
+	if vm, ok := n.VM.(clockedVM); ok {
+		vm.Clock().Set(t)
+	}
+}
+
+// engine returns the engine that currently handles the messages of the chain.
+// Assumes the context lock is held.
+func (n *Node) engine() common.Engine {
+	if n.ctx.GetState() == snow.NormalOp {
+		return n.handler.Consensus()
+	}
+	return n.handler.Bootstrapper()
+}
+
+type stakingKey struct {
+	key  *rsa.PrivateKey
+	cert *x509.Certificate
+}
+
+// newStakingKey deterministically generates the staking key and certificate
+// of a node from [rng].
+func newStakingKey(rng *rand.Rand, serial int64) (*rsa.PrivateKey, *x509.Certificate, error) {
+	key, err := newRSAKey(rng, stakingKeyBits)
+	if err != nil {
+		return nil, nil, fmt.Errorf("couldn't generate staking key: %w", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(serial),
+		Subject:               pkix.Name{CommonName: "simulator"},
+		NotBefore:             certNotBefore,
+		NotAfter:              certNotAfter,
+		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+	}
+	// RSA signatures are deterministic, so the certificate only depends on
+	// the key and the template.
+	certBytes, err := x509.CreateCertificate(rng, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("couldn't create staking certificate: %w", err)
+	}
+	cert, err := x509.ParseCertificate(certBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	return key, cert, nil
+}
+
+// newRSAKey returns an RSA key of [bits] bits whose primes are drawn from
+// [rng]. rsa.GenerateKey isn't used because it doesn't generate the same key
+// from the same source of randomness.
+func newRSAKey(rng *rand.Rand, bits int) (*rsa.PrivateKey, error) {
+	e := big.NewInt(rsaExponent)
+	one := big.NewInt(1)
+	for {
+		p := newPrime(rng, bits/2)
+		q := newPrime(rng, bits/2)
+		if p.Cmp(q) == 0 {
+			continue
+		}
+		phi := new(big.Int).Mul(new(big.Int).Sub(p, one), new(big.Int).Sub(q, one))
+		d := new(big.Int).ModInverse(e, phi)
+		if d == nil {
+			continue
+		}
+		key := &rsa.PrivateKey{
+			PublicKey: rsa.PublicKey{
+				N: new(big.Int).Mul(p, q),
+				E: rsaExponent,
+			},
+			D:      d,
+			Primes: []*big.Int{p, q},
+		}
+		if err := key.Validate(); err != nil {
+			return nil, err
+		}
+		key.Precompute()
+		return key, nil
+	}
+}
+
+// newPrime returns a prime of [bits] bits drawn from [rng]. The two most
+// significant bits are set so that the product of two such primes has twice
+// as many bits.
+func newPrime(rng *rand.Rand, bits int) *big.Int {
+	b := make([]byte, bits/8)
+	_, _ = rng.Read(b)
+	b[0] |= 0xc0
+	b[len(b)-1] |= 1
+
+	two := big.NewInt(2)
+	p := new(big.Int).SetBytes(b)
+	for !p.ProbablyPrime(20) {
+		p.Add(p, two)
+	}
+	return p
+}
+
+func nodeID(cert *x509.Certificate) ids.ShortID {
+	return hashing.ComputeHash160Array(hashing.ComputeHash256(cert.Raw))
+}
+
+// staticState is a validator state whose validator set never changes. Every
+// validator has a weight of 1.
+type staticState struct {
+	validators []ids.ShortID
+}
+
+func (s *staticState) GetCurrentHeight() (uint64, error) { return 0, nil }
+
+func (s *staticState) GetValidatorSet(uint64, ids.ID) (map[ids.ShortID]uint64, error) {
+	vdrs := make(map[ids.ShortID]uint64, len(s.validators))
+	for _, vdrID := range s.validators {
+		vdrs[vdrID] = 1
+	}
+	return vdrs, nil
+}
+
+// subnet is a subnet with a single chain, so it is bootstrapped as soon as
+// the chain is.
+type subnet struct{}
+
+func (subnet) IsBootstrapped() bool { return true }
+func (subnet) Bootstrapped(ids.ID)  {}
+
+// clockedVM is a VM whose clock is driven by the simulator, such as the EVM
+type clockedVM interface {
+	Clock() *mockable.Clock
+}
+
+// vmAppSender drops the gossip of the VM of a node. VMs gossip from their own
+// goroutines, outside of the events of the simulation, so their gossip would
+// be delivered at arbitrary times. Transactions are issued to the nodes
+// explicitly instead, see [Simulator.IssueEthTxs].
+type vmAppSender struct {
+	common.AppSender
+}
+
+func (*vmAppSender) SendAppGossip([]byte) error { return nil }
+
+func (*vmAppSender) SendAppGossipSpecific(ids.ShortSet, []byte) error { return nil }
+
+// nodeTimer schedules the timeouts requested by the engine of a node in
+// simulated time
+type nodeTimer struct {
+	simulator *Simulator
+	node      *Node
+}
+
+func (t *nodeTimer) RegisterTimeout(d time.Duration) {
+	t.simulator.scheduleTimeout(t.node, d)
+}
+
+// trackedHandler counts the messages pushed to a handler so that the simulator
+// can wait for all of them to be handled before it moves on.
+type trackedHandler struct {
+	handler.Handler
+
+	pending *sync.WaitGroup
+}
+
+func (h *trackedHandler) Push(msg message.InboundMessage) {
+	h.pending.Add(1)
+	h.Handler.Push(&trackedMessage{
+		InboundMessage: msg,
+		onFinished:     h.pending.Done,
+	})
+}
+
+type trackedMessage struct {
+	message.InboundMessage
+
+	once       sync.Once
+	onFinished func()
+}
+
+func (m *trackedMessage) OnFinishedHandling() {
+	m.once.Do(func() {
+		m.InboundMessage.OnFinishedHandling()
+		m.onFinished()
+	})
+}
+
+// wait blocks until every message of the node has been handled. Returns false
+// if the chain of the node stopped.
+func (n *Node) wait() bool {
+	done := make(chan struct{})
+	go func() {
+		n.pending.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-n.handler.Stopped():
+		return false
+	}
+}
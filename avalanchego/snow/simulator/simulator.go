@@ -0,0 +1,603 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+// Package simulator runs a network of full snowman nodes in a single process.
+// Every node runs its own router, handler, bootstrapper, engine and proposervm,
+// on top of either the TestVM or the EVM of the C-Chain, over an in-memory
+// network. Messages, timeouts and block production are
+// scheduled in simulated time by a single event loop, so a simulation is
+// reproducible from its seed: running the same configuration twice delivers
+// the same messages in the same order and accepts the same blocks.
+//
+// Only one simulation may run at a time in a process, as the sampler used by
+// the consensus engine is seeded globally.
+package simulator
+
+import (
+	"container/heap"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/message"
+	"github.com/flare-foundation/flare/snow/consensus/snowball"
+	"github.com/flare-foundation/flare/snow/engine/common"
+	"github.com/flare-foundation/flare/snow/engine/snowman/block"
+	"github.com/flare-foundation/flare/utils/logging"
+	"github.com/flare-foundation/flare/utils/sampler"
+	"github.com/flare-foundation/flare/version"
+)
+
+const (
+	defaultRequestTimeout = 5 * time.Second
+	defaultBuildInterval  = time.Second
+	defaultMinLatency     = 10 * time.Millisecond
+	defaultMaxLatency     = 100 * time.Millisecond
+)
+
+var (
+	errNoNodes       = errors.New("a simulation needs at least one node")
+	errNetworkClosed = errors.New("network closed")
+	errUnknownNode   = errors.New("unknown node")
+	errInvalidGroup  = errors.New("node assigned to more than one group")
+
+	// DefaultStartTime is the time at which simulations start by default
+	DefaultStartTime = time.Date(2021, time.July, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// LatencyFunc returns the time it takes a message to go from node [from] to
+// node [to]. [rng] only depends on the seed of the simulation and on the
+// message.
+type LatencyFunc func(from, to int, rng *rand.Rand) time.Duration
+
+// UniformLatency returns a LatencyFunc whose latencies are uniformly
+// distributed in [min, max).
+func UniformLatency(min, max time.Duration) LatencyFunc {
+	return func(_, _ int, rng *rand.Rand) time.Duration {
+		if max <= min {
+			return min
+		}
+		return min + time.Duration(rng.Int63n(int64(max-min)))
+	}
+}
+
+// Config of a simulation
+type Config struct {
+	// Seed from which every random choice of the simulation is derived
+	Seed int64
+	// Nodes is the number of validators in the network
+	Nodes int
+	// Params are the consensus parameters of every node. Defaults to K set
+	// to the number of nodes and Alpha to a majority of them.
+	Params snowball.Parameters
+	// NewVM returns the VM of the node with the given index, which is
+	// wrapped in the proposervm. The VM must be deterministic for the
+	// simulation to be. Defaults to a TestVM. Set it to NewEVM, and Genesis
+	// to the result of EVMGenesis, to run the EVM of the C-Chain.
+	NewVM func(node int) block.ChainVM
+	// Genesis of the chain. Defaults to the genesis of the TestVM.
+	Genesis []byte
+	// StartTime of the simulation. The proposervm is active from the start.
+	// Defaults to DefaultStartTime.
+	StartTime time.Time
+	// Latency of messages between nodes. Defaults to a uniform latency
+	// between 10ms and 100ms.
+	Latency LatencyFunc
+	// DropRate is the probability that a message between two nodes is lost
+	DropRate float64
+	// RequestTimeout is the time after which requests that didn't get a
+	// response fail. Defaults to 5s.
+	RequestTimeout time.Duration
+	// BuildInterval is the interval at which a node is notified that its VM
+	// has pending transactions. Nodes are notified in turn. Defaults to 1s.
+	BuildInterval time.Duration
+	// Log of every node. Defaults to no logging.
+	Log logging.Logger
+}
+
+func (c *Config) setDefaults() {
+	if c.Params == (snowball.Parameters{}) {
+		c.Params = snowball.Parameters{
+			K:                     c.Nodes,
+			Alpha:                 c.Nodes/2 + 1,
+			BetaVirtuous:          5,
+			BetaRogue:             10,
+			ConcurrentRepolls:     1,
+			OptimalProcessing:     10,
+			MaxOutstandingItems:   1024,
+			MaxItemProcessingTime: 30 * time.Second,
+		}
+	}
+	if c.NewVM == nil {
+		c.NewVM = func(int) block.ChainVM { return &TestVM{} }
+	}
+	if c.Genesis == nil {
+		c.Genesis = TestGenesis()
+	}
+	if c.StartTime.IsZero() {
+		c.StartTime = DefaultStartTime
+	}
+	if c.Latency == nil {
+		c.Latency = UniformLatency(defaultMinLatency, defaultMaxLatency)
+	}
+	if c.RequestTimeout == 0 {
+		c.RequestTimeout = defaultRequestTimeout
+	}
+	if c.BuildInterval == 0 {
+		c.BuildInterval = defaultBuildInterval
+	}
+	if c.Log == nil {
+		c.Log = logging.NoLog{}
+	}
+}
+
+// Simulator runs a simulated network of nodes
+type Simulator struct {
+	config     Config
+	msgCreator message.Creator
+
+	nodes   []*Node
+	indices map[ids.ShortID]int
+
+	lock     sync.Mutex
+	now      time.Time
+	events   eventQueue
+	seq      uint64
+	latency  LatencyFunc
+	dropRate float64
+	// groups maps every node to its side of the partition. Nodes that aren't
+	// in any group are isolated. Nil if the network isn't partitioned.
+	groups    map[int]int
+	byzantine map[int]Byzantine
+	// delivered is the number of messages delivered so far
+	delivered uint64
+
+	shutdownOnce sync.Once
+}
+
+// New creates the nodes of the network described by [config] and connects
+// them. Nodes start bootstrapping once the simulation runs.
+func New(config Config) (*Simulator, error) {
+	if config.Nodes <= 0 {
+		return nil, errNoNodes
+	}
+	config.setDefaults()
+	if err := config.Params.Verify(); err != nil {
+		return nil, err
+	}
+
+	msgCreator, err := message.NewCreator(prometheus.NewRegistry(), false, "simulator")
+	if err != nil {
+		return nil, err
+	}
+	s := &Simulator{
+		config:     config,
+		msgCreator: msgCreator,
+		indices:    make(map[ids.ShortID]int, config.Nodes),
+		now:        config.StartTime,
+		latency:    config.Latency,
+		dropRate:   config.DropRate,
+		byzantine:  make(map[int]Byzantine),
+	}
+	sampler.Seed(config.Seed)
+
+	keyRNG := s.rng([]byte("staking"))
+	keys := make([]*stakingKey, config.Nodes)
+	vdrIDs := make([]ids.ShortID, config.Nodes)
+	for i := range keys {
+		key, cert, err := newStakingKey(keyRNG, int64(i))
+		if err != nil {
+			return nil, err
+		}
+		keys[i] = &stakingKey{key: key, cert: cert}
+		vdrIDs[i] = nodeID(cert)
+		s.indices[vdrIDs[i]] = i
+	}
+
+	s.nodes = make([]*Node, config.Nodes)
+	for i, key := range keys {
+		node, err := newNode(s, i, key.key, key.cert, vdrIDs)
+		if err != nil {
+			s.Shutdown()
+			return nil, fmt.Errorf("couldn't create node %d: %w", i, err)
+		}
+		s.nodes[i] = node
+	}
+
+	// Every node is connected to every other node at the start of the
+	// simulation. Nodes are connected to themselves by their router.
+	for _, node := range s.nodes {
+		node := node
+		for _, peer := range s.nodes {
+			if peer == node {
+				continue
+			}
+			peerID := peer.ID
+			s.schedule(s.now, eventKey("connected", node.Index, peer.Index), func() error {
+				node.handler.Push(s.msgCreator.InternalConnected(peerID, version.CurrentApp))
+				return s.wait(node)
+			})
+		}
+	}
+	s.scheduleBuild(0)
+	return s, nil
+}
+
+// Nodes returns the nodes of the network
+func (s *Simulator) Nodes() []*Node { return s.nodes }
+
+// Node returns the [i]th node of the network
+func (s *Simulator) Node(i int) *Node { return s.nodes[i] }
+
+// Now returns the current simulated time
+func (s *Simulator) Now() time.Time {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.now
+}
+
+// Delivered returns the number of messages delivered to nodes so far
+func (s *Simulator) Delivered() uint64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.delivered
+}
+
+// Step runs the next event of the simulation. Returns false if there are no
+// events left.
+func (s *Simulator) Step() (bool, error) {
+	s.lock.Lock()
+	if s.events.Len() == 0 {
+		s.lock.Unlock()
+		return false, nil
+	}
+	e := heap.Pop(&s.events).(*event)
+	s.now = e.time
+	s.lock.Unlock()
+
+	// Every node is idle between events, so their clocks can be updated
+	// safely.
+	for _, node := range s.nodes {
+		node.setTime(e.time)
+	}
+	return true, e.run()
+}
+
+// Run runs the simulation for [duration] of simulated time
+func (s *Simulator) Run(duration time.Duration) error {
+	_, err := s.RunUntil(func() bool { return false }, duration)
+	return err
+}
+
+// RunUntil runs the simulation until [done] returns true or [maxDuration] of
+// simulated time elapsed. Returns true if [done] returned true.
+func (s *Simulator) RunUntil(done func() bool, maxDuration time.Duration) (bool, error) {
+	end := s.Now().Add(maxDuration)
+	for !done() {
+		s.lock.Lock()
+		if s.events.Len() == 0 || s.events[0].time.After(end) {
+			s.now = end
+			s.lock.Unlock()
+			return false, nil
+		}
+		s.lock.Unlock()
+
+		if _, err := s.Step(); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// Partition splits the network into [groups] of node indices. Messages
+// between nodes of different groups are lost, as are messages of nodes that
+// aren't in any group. Messages already in transit are still delivered.
+func (s *Simulator) Partition(groups ...[]int) error {
+	partition := make(map[int]int)
+	for group, indices := range groups {
+		for _, i := range indices {
+			if i < 0 || i >= len(s.nodes) {
+				return fmt.Errorf("%w: %d", errUnknownNode, i)
+			}
+			if _, ok := partition[i]; ok {
+				return fmt.Errorf("%w: %d", errInvalidGroup, i)
+			}
+			partition[i] = group
+		}
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.groups = partition
+	return nil
+}
+
+// Heal removes the partition of the network
+func (s *Simulator) Heal() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.groups = nil
+}
+
+// SetDropRate sets the probability that a message between two nodes is lost
+func (s *Simulator) SetDropRate(dropRate float64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.dropRate = dropRate
+}
+
+// SetLatency sets the latency of messages sent from now on
+func (s *Simulator) SetLatency(latency LatencyFunc) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.latency = latency
+}
+
+// SetByzantine makes the messages sent by node [i] to other nodes go through
+// [b]. If [b] is nil, the node behaves correctly again.
+func (s *Simulator) SetByzantine(i int, b Byzantine) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if b == nil {
+		delete(s.byzantine, i)
+		return
+	}
+	s.byzantine[i] = b
+}
+
+// Notify notifies the engine of node [i] that its VM has pending transactions
+// at the current simulated time.
+func (s *Simulator) Notify(i int) {
+	node := s.nodes[i]
+	s.schedule(s.Now(), eventKey("notify", i), func() error {
+		return s.invoke(node, func(engine common.Engine) error {
+			return engine.Notify(common.PendingTxs)
+		})
+	})
+}
+
+// Gossip makes node [i] gossip its accepted frontier at the current simulated
+// time.
+func (s *Simulator) Gossip(i int) {
+	node := s.nodes[i]
+	s.schedule(s.Now(), eventKey("gossip", i), func() error {
+		return s.invoke(node, func(engine common.Engine) error {
+			return engine.Gossip()
+		})
+	})
+}
+
+// Shutdown stops the chains of every node
+func (s *Simulator) Shutdown() {
+	s.shutdownOnce.Do(func() {
+		for _, node := range s.nodes {
+			if node == nil {
+				continue
+			}
+			node.router.Shutdown()
+			_ = node.network.Close()
+			close(node.closed)
+		}
+	})
+}
+
+// send schedules the delivery of [msg] from node [from] to the node [to].
+// If [msg] is a request, its failure is scheduled as well. Returns false if
+// [to] isn't a node of the network.
+func (s *Simulator) send(from *Node, to ids.ShortID, msg message.OutboundMessage) bool {
+	toIndex, ok := s.indices[to]
+	if !ok {
+		return false
+	}
+	bytes := msg.Bytes()
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	// The fate of a message only depends on the seed and on the message, so
+	// that it doesn't depend on the order in which the engine sent it.
+	key := eventKey("message", from.Index, toIndex, bytes)
+	rng := s.rng(key)
+	if s.connected(from.Index, toIndex) && rng.Float64() >= s.dropRate {
+		deliverAt := s.now.Add(s.latency(from.Index, toIndex, rng))
+		s.scheduleLocked(deliverAt, key, func() error {
+			return s.deliver(from, s.nodes[toIndex], bytes)
+		})
+	}
+
+	responseOp, isRequest := message.RequestToResponseOps[msg.Op()]
+	if !isRequest {
+		return true
+	}
+	// The request is parsed to know which request fails. A message that
+	// can't be parsed would be dropped by the receiver and never answered,
+	// so it is left to fail on its own.
+	inMsg, err := s.msgCreator.Parse(bytes, from.ID, nil)
+	if err != nil {
+		return true
+	}
+	requestID := inMsg.Get(message.RequestID).(uint32)
+	failedOp := message.ResponseToFailedOps[responseOp]
+	s.scheduleLocked(s.now.Add(s.config.RequestTimeout), eventKey("timeout", from.Index, toIndex, bytes), func() error {
+		from.router.HandleInbound(s.msgCreator.InternalFailedRequest(failedOp, to, ChainID, requestID))
+		return s.wait(from)
+	})
+	return true
+}
+
+// sendLocal schedules the delivery of [msg], which node [n] sent to itself,
+// at the current simulated time.
+func (s *Simulator) sendLocal(n *Node, msg message.InboundMessage) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.scheduleLocked(s.now, eventKey("local", n.Index, []byte(msg.String())), func() error {
+		n.router.HandleInbound(msg)
+		return s.wait(n)
+	})
+}
+
+// deliver hands [bytes], sent by node [from], to the router of node [to]
+func (s *Simulator) deliver(from, to *Node, bytes []byte) error {
+	msg, err := s.msgCreator.Parse(bytes, from.ID, nil)
+	if err != nil {
+		return fmt.Errorf("couldn't parse message from node %d: %w", from.Index, err)
+	}
+
+	s.lock.Lock()
+	b, byzantine := s.byzantine[from.Index]
+	s.delivered++
+	s.lock.Unlock()
+
+	msgs := []message.InboundMessage{msg}
+	if byzantine {
+		msgs = b.Tamper(to.ID, msg, s.msgCreator)
+	}
+	for _, msg := range msgs {
+		to.router.HandleInbound(msg)
+	}
+	return s.wait(to)
+}
+
+// invoke calls [f] with the current engine of node [n]
+func (s *Simulator) invoke(n *Node, f func(common.Engine) error) error {
+	if n.Stopped() {
+		return nil
+	}
+
+	n.ctx.Lock.Lock()
+	err := f(n.engine())
+	n.ctx.Lock.Unlock()
+	if err != nil {
+		n.handler.StopWithError(err)
+		return fmt.Errorf("node %d failed: %w", n.Index, err)
+	}
+	return s.wait(n)
+}
+
+// wait blocks until node [n] handled all of its messages
+func (s *Simulator) wait(n *Node) error {
+	if !n.wait() {
+		return fmt.Errorf("node %d stopped", n.Index)
+	}
+	return nil
+}
+
+// scheduleTimeout schedules a timeout of the engine of node [n] in [d]
+func (s *Simulator) scheduleTimeout(n *Node, d time.Duration) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.scheduleLocked(s.now.Add(d), eventKey("engine timeout", n.Index), func() error {
+		return s.invoke(n, func(engine common.Engine) error {
+			return engine.Timeout()
+		})
+	})
+}
+
+// scheduleBuild notifies node [i] that its VM has pending transactions in
+// [Config.BuildInterval], and then notifies the next node. Notifying every
+// node at once would make them all build conflicting blocks at heights where
+// the proposervm doesn't assign proposers, which snowman can't resolve when
+// the votes are split evenly.
+func (s *Simulator) scheduleBuild(i int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	node := s.nodes[i]
+	s.scheduleLocked(s.now.Add(s.config.BuildInterval), eventKey("build", i), func() error {
+		s.scheduleBuild((i + 1) % len(s.nodes))
+		return s.invoke(node, func(engine common.Engine) error {
+			return engine.Notify(common.PendingTxs)
+		})
+	})
+}
+
+func (s *Simulator) schedule(t time.Time, key []byte, run func() error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.scheduleLocked(t, key, run)
+}
+
+// scheduleLocked assumes the lock is held
+func (s *Simulator) scheduleLocked(t time.Time, key []byte, run func() error) {
+	s.seq++
+	heap.Push(&s.events, &event{
+		time: t,
+		key:  key,
+		seq:  s.seq,
+		run:  run,
+	})
+}
+
+// connected returns true if messages can go from node [from] to node [to].
+// Assumes the lock is held.
+func (s *Simulator) connected(from, to int) bool {
+	if from == to || s.groups == nil {
+		return true
+	}
+	fromGroup, ok := s.groups[from]
+	if !ok {
+		return false
+	}
+	toGroup, ok := s.groups[to]
+	return ok && fromGroup == toGroup
+}
+
+// peers returns the IDs of every node other than [n]
+func (s *Simulator) peers(n *Node) []ids.ShortID {
+	peers := make([]ids.ShortID, 0, len(s.nodes)-1)
+	for _, peer := range s.nodes {
+		if peer != n {
+			peers = append(peers, peer.ID)
+		}
+	}
+	return peers
+}
+
+// rng returns a source of randomness that only depends on the seed of the
+// simulation and on [data]
+func (s *Simulator) rng(data ...[]byte) *rand.Rand {
+	h := sha256.New()
+	var seed [8]byte
+	binary.BigEndian.PutUint64(seed[:], uint64(s.config.Seed))
+	_, _ = h.Write(seed[:])
+	for _, d := range data {
+		_, _ = h.Write(d)
+	}
+	derived := binary.BigEndian.Uint64(h.Sum(nil))
+	return rand.New(rand.NewSource(int64(derived))) // #nosec G404
+}
+
+// eventKey returns the key of an event of type [kind]. [parts] are node
+// indices and the content of messages the event concerns.
+func eventKey(kind string, parts ...interface{}) []byte {
+	key := []byte(kind)
+	for _, part := range parts {
+		switch part := part.(type) {
+		case int:
+			var b [4]byte
+			binary.BigEndian.PutUint32(b[:], uint32(part))
+			key = append(key, b[:]...)
+		case []byte:
+			digest := sha256.Sum256(part)
+			key = append(key, digest[:]...)
+		}
+	}
+	return key
+}
@@ -0,0 +1,254 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package simulator
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/flare-foundation/flare/coreth/core"
+	"github.com/flare-foundation/flare/coreth/core/types"
+	"github.com/flare-foundation/flare/coreth/params"
+	"github.com/flare-foundation/flare/coreth/plugin/evm"
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/message"
+)
+
+// minAccepted returns the smallest number of blocks accepted by a node of [s]
+func minAccepted(s *Simulator) int {
+	min := -1
+	for _, node := range s.Nodes() {
+		if accepted := len(node.Accepted()); min == -1 || accepted < min {
+			min = accepted
+		}
+	}
+	return min
+}
+
+// assertConsistent checks that no two nodes of [s] accepted different blocks
+// at the same height
+func assertConsistent(t *testing.T, s *Simulator) {
+	nodes := s.Nodes()
+	reference := nodes[0].Accepted()
+	for _, node := range nodes[1:] {
+		accepted := node.Accepted()
+		for i := 0; i < len(accepted) && i < len(reference); i++ {
+			assert.Equal(t, reference[i], accepted[i], "node %d accepted a different block at height %d", node.Index, i+1)
+		}
+		if len(accepted) > len(reference) {
+			reference = accepted
+		}
+	}
+}
+
+// acceptedEthTxs returns the number of transactions in the blocks accepted by
+// the EVM of [node]
+func acceptedEthTxs(node *Node) (int, error) {
+	vm := node.VM.(*evm.VM)
+	lastAccepted, err := vm.LastAccepted()
+	if err != nil {
+		return 0, err
+	}
+	lastAcceptedBlk, err := vm.GetBlock(lastAccepted)
+	if err != nil {
+		return 0, err
+	}
+	accepted := 0
+	for height := uint64(1); height <= lastAcceptedBlk.Height(); height++ {
+		blkID, err := vm.GetBlockIDAtHeight(height)
+		if err != nil {
+			return 0, err
+		}
+		blk, err := vm.GetBlock(blkID)
+		if err != nil {
+			return 0, err
+		}
+		ethBlock := new(types.Block)
+		if err := rlp.DecodeBytes(blk.Bytes(), ethBlock); err != nil {
+			return 0, err
+		}
+		accepted += len(ethBlock.Transactions())
+	}
+	return accepted, nil
+}
+
+func TestSimulatorAcceptsBlocks(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := New(Config{Seed: 1, Nodes: 5})
+	assert.NoError(err)
+	defer s.Shutdown()
+
+	done, err := s.RunUntil(func() bool { return minAccepted(s) >= 5 }, time.Minute)
+	assert.NoError(err)
+	assert.True(done)
+
+	for _, node := range s.Nodes() {
+		assert.True(node.Bootstrapped())
+		assert.False(node.Stopped())
+	}
+	assertConsistent(t, s)
+}
+
+func TestSimulatorDeterministic(t *testing.T) {
+	assert := assert.New(t)
+
+	run := func() ([][]ids.ID, uint64, time.Time) {
+		s, err := New(Config{
+			Seed:     7,
+			Nodes:    4,
+			DropRate: 0.1,
+		})
+		assert.NoError(err)
+		defer s.Shutdown()
+
+		assert.NoError(s.Run(30 * time.Second))
+		accepted := make([][]ids.ID, len(s.Nodes()))
+		for i, node := range s.Nodes() {
+			accepted[i] = node.Accepted()
+		}
+		return accepted, s.Delivered(), s.Now()
+	}
+
+	accepted, delivered, now := run()
+	assert.NotEmpty(accepted[0])
+
+	replayAccepted, replayDelivered, replayNow := run()
+	assert.Equal(accepted, replayAccepted)
+	assert.Equal(delivered, replayDelivered)
+	assert.Equal(now, replayNow)
+}
+
+func TestSimulatorPartition(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := New(Config{Seed: 2, Nodes: 5})
+	assert.NoError(err)
+	defer s.Shutdown()
+
+	done, err := s.RunUntil(func() bool { return minAccepted(s) >= 2 }, time.Minute)
+	assert.NoError(err)
+	assert.True(done)
+
+	// Only the majority can accept blocks while the network is partitioned
+	assert.NoError(s.Partition([]int{0, 1}, []int{2, 3, 4}))
+	assert.NoError(s.Run(5 * time.Second))
+	minority := len(s.Node(0).Accepted())
+	majority := len(s.Node(2).Accepted())
+	assert.NoError(s.Run(30 * time.Second))
+	for _, node := range s.Nodes()[:2] {
+		assert.Len(node.Accepted(), minority)
+	}
+	for _, node := range s.Nodes()[2:] {
+		assert.Greater(len(node.Accepted()), majority)
+	}
+
+	// The minority catches up once the partition heals
+	s.Heal()
+	target := len(s.Node(2).Accepted())
+	done, err = s.RunUntil(func() bool { return minAccepted(s) >= target }, 2*time.Minute)
+	assert.NoError(err)
+	assert.True(done)
+	assertConsistent(t, s)
+}
+
+func TestSimulatorByzantine(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := New(Config{Seed: 3, Nodes: 5})
+	assert.NoError(err)
+	defer s.Shutdown()
+
+	// Node 4 votes for a block that doesn't exist and node 3 doesn't answer
+	fakeBlkID := ids.ID{'f', 'a', 'k', 'e'}
+	s.SetByzantine(4, ByzantineFunc(func(_ ids.ShortID, msg message.InboundMessage, mc message.Creator) []message.InboundMessage {
+		if msg.Op() != message.Chits {
+			return []message.InboundMessage{msg}
+		}
+		requestID := msg.Get(message.RequestID).(uint32)
+		return []message.InboundMessage{
+			mc.InboundChits(ChainID, requestID, []ids.ID{fakeBlkID}, msg.NodeID()),
+		}
+	}))
+	s.SetByzantine(3, Mute)
+
+	done, err := s.RunUntil(func() bool {
+		for _, node := range s.Nodes()[:3] {
+			if len(node.Accepted()) < 3 {
+				return false
+			}
+		}
+		return true
+	}, 2*time.Minute)
+	assert.NoError(err)
+	assert.True(done)
+	assertConsistent(t, s)
+	for _, node := range s.Nodes() {
+		assert.NotContains(node.Accepted(), fakeBlkID)
+	}
+}
+
+func TestSimulatorEVM(t *testing.T) {
+	assert := assert.New(t)
+
+	key, err := crypto.HexToECDSA("56289e99c94b6912bfc12adc093c9b51124f0dc54ac7a766b2bc5ccf558d8027")
+	assert.NoError(err)
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	genesis, err := EVMGenesis(core.GenesisAlloc{
+		addr: {Balance: new(big.Int).Mul(big.NewInt(params.Ether), big.NewInt(1000))},
+	})
+	assert.NoError(err)
+
+	signer := types.LatestSignerForChainID(EVMChainID)
+	gasPrice := new(big.Int).Mul(big.NewInt(params.ApricotPhase3InitialBaseFee), big.NewInt(100))
+	txs := make([]*types.Transaction, 3)
+	for i := range txs {
+		txs[i], err = types.SignTx(types.NewTransaction(uint64(i), common.Address{1}, big.NewInt(1), params.TxGas, gasPrice, nil), signer, key)
+		assert.NoError(err)
+	}
+
+	run := func() [][]ids.ID {
+		s, err := New(Config{
+			Seed:          4,
+			Nodes:         4,
+			NewVM:         NewEVM,
+			Genesis:       genesis,
+			BuildInterval: 2 * time.Second,
+		})
+		assert.NoError(err)
+		defer s.Shutdown()
+
+		// Transactions are issued to every node, as they would be gossiped
+		for i := range s.Nodes() {
+			s.IssueEthTxs(i, txs)
+		}
+		done, err := s.RunUntil(func() bool {
+			for _, node := range s.Nodes() {
+				if accepted, err := acceptedEthTxs(node); err != nil || accepted != len(txs) {
+					return false
+				}
+			}
+			return true
+		}, time.Minute)
+		assert.NoError(err)
+		assert.True(done)
+		assertConsistent(t, s)
+
+		accepted := make([][]ids.ID, len(s.Nodes()))
+		for i, node := range s.Nodes() {
+			accepted[i] = node.Accepted()
+		}
+		return accepted
+	}
+
+	accepted := run()
+	assert.NotEmpty(accepted[0])
+	assert.Equal(accepted, run())
+}
@@ -0,0 +1,221 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package simulator
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/flare-foundation/flare/database/manager"
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/snow"
+	"github.com/flare-foundation/flare/snow/choices"
+	"github.com/flare-foundation/flare/snow/consensus/snowman"
+	"github.com/flare-foundation/flare/snow/engine/common"
+	"github.com/flare-foundation/flare/snow/engine/snowman/block"
+	"github.com/flare-foundation/flare/utils/hashing"
+	"github.com/flare-foundation/flare/utils/wrappers"
+	"github.com/flare-foundation/flare/version"
+)
+
+var (
+	errUnknownBlock   = errors.New("unknown block")
+	errUnknownParent  = errors.New("unknown parent")
+	errInvalidHeight  = errors.New("block height isn't one above its parent's")
+	errRejectedParent = errors.New("parent was rejected")
+
+	_ block.ChainVM = &TestVM{}
+	_ snowman.Block = &testBlock{}
+
+	testGenesisTime = time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// TestGenesis returns the genesis of the TestVM
+func TestGenesis() []byte {
+	return (&testBlock{timestamp: testGenesisTime}).pack()
+}
+
+// TestVM is a deterministic in-memory chain VM. Every call to BuildBlock
+// builds a block on top of the preferred block, whose payload identifies the
+// node that built it, so that blocks built by different nodes conflict.
+type TestVM struct {
+	ctx *snow.Context
+
+	blocks       map[ids.ID]*testBlock
+	lastAccepted *testBlock
+	preferred    ids.ID
+	// number of blocks built by this VM, to make its blocks unique
+	built uint64
+}
+
+func (vm *TestVM) Initialize(
+	ctx *snow.Context,
+	_ manager.Manager,
+	genesisBytes []byte,
+	_ []byte,
+	_ []byte,
+	_ chan<- common.Message,
+	_ []*common.Fx,
+	_ common.AppSender,
+) error {
+	vm.ctx = ctx
+	vm.blocks = make(map[ids.ID]*testBlock)
+
+	genesis, err := vm.parse(genesisBytes)
+	if err != nil {
+		return fmt.Errorf("couldn't parse genesis: %w", err)
+	}
+	genesis.status = choices.Accepted
+	vm.blocks[genesis.id] = genesis
+	vm.lastAccepted = genesis
+	vm.preferred = genesis.id
+	return nil
+}
+
+func (vm *TestVM) BuildBlock() (snowman.Block, error) {
+	parent, ok := vm.blocks[vm.preferred]
+	if !ok {
+		return nil, errUnknownParent
+	}
+	vm.built++
+	p := wrappers.Packer{MaxSize: wrappers.LongLen + hashing.AddrLen}
+	p.PackFixedBytes(vm.ctx.NodeID[:])
+	p.PackLong(vm.built)
+
+	blk := &testBlock{
+		vm:        vm,
+		parent:    parent.id,
+		height:    parent.height + 1,
+		timestamp: parent.timestamp.Add(time.Second),
+		payload:   p.Bytes,
+		status:    choices.Processing,
+	}
+	blk.bytes = blk.pack()
+	blk.id = hashing.ComputeHash256Array(blk.bytes)
+	vm.blocks[blk.id] = blk
+	return blk, nil
+}
+
+func (vm *TestVM) ParseBlock(blkBytes []byte) (snowman.Block, error) {
+	blk, err := vm.parse(blkBytes)
+	if err != nil {
+		return nil, err
+	}
+	if known, ok := vm.blocks[blk.id]; ok {
+		return known, nil
+	}
+	vm.blocks[blk.id] = blk
+	return blk, nil
+}
+
+func (vm *TestVM) GetBlock(blkID ids.ID) (snowman.Block, error) {
+	blk, ok := vm.blocks[blkID]
+	if !ok {
+		return nil, errUnknownBlock
+	}
+	return blk, nil
+}
+
+func (vm *TestVM) SetPreference(blkID ids.ID) error {
+	vm.preferred = blkID
+	return nil
+}
+
+func (vm *TestVM) LastAccepted() (ids.ID, error) { return vm.lastAccepted.id, nil }
+
+func (vm *TestVM) SetState(snow.State) error { return nil }
+
+func (vm *TestVM) Shutdown() error { return nil }
+
+func (vm *TestVM) Version() (string, error) { return "simulator", nil }
+
+func (vm *TestVM) CreateStaticHandlers() (map[string]*common.HTTPHandler, error) { return nil, nil }
+
+func (vm *TestVM) CreateHandlers() (map[string]*common.HTTPHandler, error) { return nil, nil }
+
+func (vm *TestVM) HealthCheck() (interface{}, error) { return nil, nil }
+
+func (vm *TestVM) Connected(ids.ShortID, version.Application) error { return nil }
+
+func (vm *TestVM) Disconnected(ids.ShortID) error { return nil }
+
+func (vm *TestVM) AppRequest(ids.ShortID, uint32, time.Time, []byte) error { return nil }
+
+func (vm *TestVM) AppRequestFailed(ids.ShortID, uint32) error { return nil }
+
+func (vm *TestVM) AppResponse(ids.ShortID, uint32, []byte) error { return nil }
+
+func (vm *TestVM) AppGossip(ids.ShortID, []byte) error { return nil }
+
+func (vm *TestVM) parse(blkBytes []byte) (*testBlock, error) {
+	p := wrappers.Packer{Bytes: blkBytes}
+	blk := &testBlock{
+		vm:     vm,
+		status: choices.Processing,
+		bytes:  blkBytes,
+		id:     hashing.ComputeHash256Array(blkBytes),
+	}
+	copy(blk.parent[:], p.UnpackFixedBytes(hashing.HashLen))
+	blk.height = p.UnpackLong()
+	blk.timestamp = time.Unix(int64(p.UnpackLong()), 0).UTC()
+	blk.payload = p.UnpackBytes()
+	if p.Errored() {
+		return nil, p.Err
+	}
+	return blk, nil
+}
+
+type testBlock struct {
+	vm *TestVM
+
+	id        ids.ID
+	parent    ids.ID
+	height    uint64
+	timestamp time.Time
+	payload   []byte
+	bytes     []byte
+	status    choices.Status
+}
+
+func (b *testBlock) ID() ids.ID             { return b.id }
+func (b *testBlock) Parent() ids.ID         { return b.parent }
+func (b *testBlock) Height() uint64         { return b.height }
+func (b *testBlock) Timestamp() time.Time   { return b.timestamp }
+func (b *testBlock) Bytes() []byte          { return b.bytes }
+func (b *testBlock) Status() choices.Status { return b.status }
+
+func (b *testBlock) Verify() error {
+	parent, ok := b.vm.blocks[b.parent]
+	if !ok {
+		return errUnknownParent
+	}
+	if parent.status == choices.Rejected {
+		return errRejectedParent
+	}
+	if b.height != parent.height+1 {
+		return errInvalidHeight
+	}
+	return nil
+}
+
+func (b *testBlock) Accept() error {
+	b.status = choices.Accepted
+	b.vm.lastAccepted = b
+	return nil
+}
+
+func (b *testBlock) Reject() error {
+	b.status = choices.Rejected
+	return nil
+}
+
+func (b *testBlock) pack() []byte {
+	p := wrappers.Packer{MaxSize: hashing.HashLen + 2*wrappers.LongLen + wrappers.IntLen + len(b.payload)}
+	p.PackFixedBytes(b.parent[:])
+	p.PackLong(b.height)
+	p.PackLong(uint64(b.timestamp.Unix()))
+	p.PackBytes(b.payload)
+	return p.Bytes
+}
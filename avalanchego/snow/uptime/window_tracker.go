@@ -0,0 +1,295 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package uptime
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/flare-foundation/flare/database"
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/utils/timer/mockable"
+	"github.com/flare-foundation/flare/utils/wrappers"
+)
+
+const (
+	// Granularity at which connection time is recorded
+	bucketDuration = time.Hour
+	// Longest window uptime can be reported over
+	maxWindow = 30 * 24 * time.Hour
+	// Number of buckets kept for every node. The bucket currently being
+	// filled is kept in addition to the ones covering [maxWindow].
+	numBuckets = int(maxWindow/bucketDuration) + 1
+)
+
+var (
+	// Key of the time this node was running. Node IDs are longer than it, so
+	// it can't collide with them.
+	observedKey = []byte("observed")
+
+	errUnknownNode    = errors.New("node isn't tracked")
+	errWindowTooLarge = fmt.Errorf("window must be at most %s", maxWindow)
+
+	_ WindowTracker = &windowTracker{}
+)
+
+// WindowTracker records how long a fixed set of nodes has been connected to
+// this node over rolling windows. Time this node wasn't running doesn't count
+// against the tracked nodes.
+// It's safe for multiple goroutines to concurrently call its methods.
+type WindowTracker interface {
+	// Connect marks [nodeID] as connected. Untracked nodes are ignored.
+	Connect(nodeID ids.ShortID)
+	// Disconnect marks [nodeID] as disconnected. Untracked nodes are ignored.
+	Disconnect(nodeID ids.ShortID)
+	IsConnected(nodeID ids.ShortID) bool
+
+	// Uptime returns how long this node was running during the last [window],
+	// and how much of that time [nodeID] was connected to it. The window is
+	// extended back to the start of the hourly bucket it begins in.
+	Uptime(nodeID ids.ShortID, window time.Duration) (observed time.Duration, connected time.Duration, err error)
+
+	// NodeIDs returns the tracked nodes
+	NodeIDs() []ids.ShortID
+
+	// Flush persists the recorded uptimes
+	Flush() error
+	// Shutdown flushes the recorded uptimes. The tracker shouldn't be used
+	// after it's called.
+	Shutdown() error
+}
+
+type windowTracker struct {
+	lock sync.Mutex
+	// Used to get time. Useful for faking time during tests.
+	clock mockable.Clock
+
+	db database.Database
+	// Time the histories were last brought up to date
+	lastUpdated time.Time
+	// Time this node was running
+	observed *history
+	// Time each tracked node was connected
+	nodes     map[ids.ShortID]*history
+	connected ids.ShortSet
+}
+
+// NewWindowTracker returns a tracker for [nodeIDs] that persists the recorded
+// uptimes in [db]
+func NewWindowTracker(db database.Database, nodeIDs []ids.ShortID) (WindowTracker, error) {
+	t := &windowTracker{
+		db:    db,
+		nodes: make(map[ids.ShortID]*history, len(nodeIDs)),
+	}
+	t.lastUpdated = t.clock.Time()
+
+	var err error
+	t.observed, err = t.load(observedKey)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't load observed uptime: %w", err)
+	}
+	for _, nodeID := range nodeIDs {
+		t.nodes[nodeID], err = t.load(nodeID[:])
+		if err != nil {
+			return nil, fmt.Errorf("couldn't load uptime of %s: %w", nodeID, err)
+		}
+	}
+	return t, nil
+}
+
+func (t *windowTracker) Connect(nodeID ids.ShortID) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if _, ok := t.nodes[nodeID]; !ok {
+		return
+	}
+	t.advance(t.clock.Time())
+	t.connected.Add(nodeID)
+}
+
+func (t *windowTracker) Disconnect(nodeID ids.ShortID) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if !t.connected.Contains(nodeID) {
+		return
+	}
+	t.advance(t.clock.Time())
+	t.connected.Remove(nodeID)
+}
+
+func (t *windowTracker) IsConnected(nodeID ids.ShortID) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return t.connected.Contains(nodeID)
+}
+
+func (t *windowTracker) Uptime(nodeID ids.ShortID, window time.Duration) (time.Duration, time.Duration, error) {
+	if window > maxWindow {
+		return 0, 0, errWindowTooLarge
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	h, ok := t.nodes[nodeID]
+	if !ok {
+		return 0, 0, errUnknownNode
+	}
+	now := t.clock.Time()
+	t.advance(now)
+	return t.observed.sum(now, window), h.sum(now, window), nil
+}
+
+func (t *windowTracker) NodeIDs() []ids.ShortID {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	nodeIDs := make([]ids.ShortID, 0, len(t.nodes))
+	for nodeID := range t.nodes {
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+	return nodeIDs
+}
+
+func (t *windowTracker) Flush() error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	now := t.clock.Time()
+	t.advance(now)
+
+	batch := t.db.NewBatch()
+	if err := batch.Put(observedKey, t.observed.bytes(now)); err != nil {
+		return err
+	}
+	for nodeID, h := range t.nodes {
+		if err := batch.Put(nodeID[:], h.bytes(now)); err != nil {
+			return err
+		}
+	}
+	return batch.Write()
+}
+
+func (t *windowTracker) Shutdown() error {
+	return t.Flush()
+}
+
+// load returns the history stored under [key], or an empty history if there
+// is none
+func (t *windowTracker) load(key []byte) (*history, error) {
+	h := &history{}
+	b, err := t.db.Get(key)
+	switch {
+	case err == database.ErrNotFound:
+		return h, nil
+	case err != nil:
+		return nil, err
+	}
+	return h, h.parse(b)
+}
+
+// advance records the time since the last update as observed, and as
+// connected for the connected nodes.
+// Assumes [t.lock] is held.
+func (t *windowTracker) advance(now time.Time) {
+	// If time has moved backwards, don't record anything until it catches up
+	if !now.After(t.lastUpdated) {
+		return
+	}
+	t.observed.add(t.lastUpdated, now)
+	for nodeID := range t.connected {
+		t.nodes[nodeID].add(t.lastUpdated, now)
+	}
+	t.lastUpdated = now
+}
+
+// history is a ring of the durations recorded in the last [numBuckets] periods
+type history struct {
+	// periods[i] is the period durations[i] was recorded in
+	periods   [numBuckets]uint64
+	durations [numBuckets]time.Duration
+}
+
+func periodOf(t time.Time) uint64 {
+	return uint64(t.Unix()) / uint64(bucketDuration/time.Second)
+}
+
+func startOf(period uint64) time.Time {
+	return time.Unix(int64(period*uint64(bucketDuration/time.Second)), 0)
+}
+
+// add records the time between [start] and [end]
+func (h *history) add(start, end time.Time) {
+	for start.Before(end) {
+		period := periodOf(start)
+		periodEnd := startOf(period + 1)
+		if periodEnd.After(end) {
+			periodEnd = end
+		}
+		h.addToPeriod(period, periodEnd.Sub(start))
+		start = periodEnd
+	}
+}
+
+func (h *history) addToPeriod(period uint64, duration time.Duration) {
+	i := period % uint64(numBuckets)
+	if h.periods[i] != period {
+		h.periods[i] = period
+		h.durations[i] = 0
+	}
+	h.durations[i] += duration
+}
+
+// sum returns the time recorded in the periods that overlap the [window]
+// ending at [now]
+func (h *history) sum(now time.Time, window time.Duration) time.Duration {
+	first := periodOf(now.Add(-window))
+	current := periodOf(now)
+	total := time.Duration(0)
+	for i, period := range h.periods {
+		if h.durations[i] != 0 && period >= first && period <= current {
+			total += h.durations[i]
+		}
+	}
+	return total
+}
+
+// bytes returns the serialized periods that haven't expired at [now]
+func (h *history) bytes(now time.Time) []byte {
+	current := periodOf(now)
+	p := wrappers.Packer{MaxSize: wrappers.IntLen + numBuckets*2*wrappers.LongLen}
+	count := uint32(0)
+	for i, period := range h.periods {
+		if h.durations[i] != 0 && period <= current && current-period < uint64(numBuckets) {
+			count++
+		}
+	}
+	p.PackInt(count)
+	for i, period := range h.periods {
+		if h.durations[i] != 0 && period <= current && current-period < uint64(numBuckets) {
+			p.PackLong(period)
+			p.PackLong(uint64(h.durations[i]))
+		}
+	}
+	return p.Bytes
+}
+
+func (h *history) parse(b []byte) error {
+	p := wrappers.Packer{Bytes: b}
+	count := p.UnpackInt()
+	if count > uint32(numBuckets) {
+		return fmt.Errorf("too many periods (%d)", count)
+	}
+	for i := uint32(0); i < count && !p.Errored(); i++ {
+		period := p.UnpackLong()
+		duration := time.Duration(p.UnpackLong())
+		h.addToPeriod(period, duration)
+	}
+	return p.Err
+}
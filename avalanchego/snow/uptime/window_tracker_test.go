@@ -0,0 +1,118 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package uptime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/flare-foundation/flare/database/memdb"
+	"github.com/flare-foundation/flare/ids"
+)
+
+func newTestWindowTracker(t *testing.T, now time.Time, nodeIDs ...ids.ShortID) *windowTracker {
+	tracker, err := NewWindowTracker(memdb.New(), nodeIDs)
+	assert.NoError(t, err)
+	wt := tracker.(*windowTracker)
+	wt.clock.Set(now)
+	wt.lastUpdated = now
+	return wt
+}
+
+func TestWindowTrackerUptime(t *testing.T) {
+	assert := assert.New(t)
+
+	nodeID0 := ids.GenerateTestShortID()
+	nodeID1 := ids.GenerateTestShortID()
+	start := startOf(periodOf(time.Now()))
+	tracker := newTestWindowTracker(t, start, nodeID0)
+
+	tracker.Connect(nodeID0)
+	tracker.Connect(nodeID1)
+	assert.True(tracker.IsConnected(nodeID0))
+	assert.False(tracker.IsConnected(nodeID1))
+
+	tracker.clock.Set(start.Add(30 * time.Minute))
+	tracker.Disconnect(nodeID0)
+	assert.False(tracker.IsConnected(nodeID0))
+
+	tracker.clock.Set(start.Add(2 * time.Hour))
+	observed, connected, err := tracker.Uptime(nodeID0, 24*time.Hour)
+	assert.NoError(err)
+	assert.Equal(2*time.Hour, observed)
+	assert.Equal(30*time.Minute, connected)
+
+	observed, connected, err = tracker.Uptime(nodeID0, time.Hour)
+	assert.NoError(err)
+	assert.Equal(time.Hour, observed)
+	assert.Equal(time.Duration(0), connected)
+
+	_, _, err = tracker.Uptime(nodeID1, time.Hour)
+	assert.ErrorIs(err, errUnknownNode)
+
+	_, _, err = tracker.Uptime(nodeID0, 31*24*time.Hour)
+	assert.ErrorIs(err, errWindowTooLarge)
+}
+
+func TestWindowTrackerExpires(t *testing.T) {
+	assert := assert.New(t)
+
+	nodeID := ids.GenerateTestShortID()
+	start := startOf(periodOf(time.Now()))
+	tracker := newTestWindowTracker(t, start, nodeID)
+
+	tracker.Connect(nodeID)
+	tracker.clock.Set(start.Add(48 * time.Hour))
+
+	observed, connected, err := tracker.Uptime(nodeID, 24*time.Hour)
+	assert.NoError(err)
+	assert.Equal(24*time.Hour, observed)
+	assert.Equal(24*time.Hour, connected)
+
+	observed, connected, err = tracker.Uptime(nodeID, 7*24*time.Hour)
+	assert.NoError(err)
+	assert.Equal(48*time.Hour, observed)
+	assert.Equal(48*time.Hour, connected)
+
+	// The ring wraps around once the oldest periods expire
+	tracker.clock.Set(start.Add(31 * 24 * time.Hour))
+	observed, connected, err = tracker.Uptime(nodeID, 30*24*time.Hour)
+	assert.NoError(err)
+	assert.Equal(30*24*time.Hour, observed)
+	assert.Equal(30*24*time.Hour, connected)
+}
+
+func TestWindowTrackerPersists(t *testing.T) {
+	assert := assert.New(t)
+
+	nodeID := ids.GenerateTestShortID()
+	start := startOf(periodOf(time.Now()))
+	db := memdb.New()
+
+	trackerIntf, err := NewWindowTracker(db, []ids.ShortID{nodeID})
+	assert.NoError(err)
+	tracker := trackerIntf.(*windowTracker)
+	tracker.clock.Set(start)
+	tracker.lastUpdated = start
+
+	tracker.Connect(nodeID)
+	tracker.clock.Set(start.Add(time.Hour))
+	assert.NoError(tracker.Shutdown())
+
+	trackerIntf, err = NewWindowTracker(db, []ids.ShortID{nodeID})
+	assert.NoError(err)
+	tracker = trackerIntf.(*windowTracker)
+	// The time the node wasn't running isn't observed
+	tracker.clock.Set(start.Add(2 * time.Hour))
+	tracker.lastUpdated = start.Add(2 * time.Hour)
+
+	assert.False(tracker.IsConnected(nodeID))
+	tracker.clock.Set(start.Add(3 * time.Hour))
+	observed, connected, err := tracker.Uptime(nodeID, 24*time.Hour)
+	assert.NoError(err)
+	assert.Equal(2*time.Hour, observed)
+	assert.Equal(time.Hour, connected)
+}
@@ -3,6 +3,20 @@
 
 package compression
 
+import (
+	"fmt"
+	"strings"
+)
+
+// Type is the algorithm a message is compressed with
+type Type byte
+
+const (
+	NoCompression Type = iota
+	Gzip
+	Zstd
+)
+
 // Compressor compresss and decompresses messages.
 // Decompress is the inverse of Compress.
 // Decompress(Compress(msg)) == msg.
@@ -10,3 +24,30 @@ type Compressor interface {
 	Compress([]byte) ([]byte, error)
 	Decompress([]byte) ([]byte, error)
 }
+
+func (t Type) String() string {
+	switch t {
+	case NoCompression:
+		return "none"
+	case Gzip:
+		return "gzip"
+	case Zstd:
+		return "zstd"
+	default:
+		return "unknown"
+	}
+}
+
+// TypeFromString returns the algorithm named [s]
+func TypeFromString(s string) (Type, error) {
+	switch strings.ToLower(s) {
+	case NoCompression.String():
+		return NoCompression, nil
+	case Gzip.String():
+		return Gzip, nil
+	case Zstd.String():
+		return Zstd, nil
+	default:
+		return 0, fmt.Errorf("unknown compression type %q", s)
+	}
+}
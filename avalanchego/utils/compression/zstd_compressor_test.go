@@ -0,0 +1,70 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package compression
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	"github.com/flare-foundation/flare/utils/units"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestZstdCompressDecompress(t *testing.T) {
+	data := make([]byte, 4096)
+	for i := 0; i < len(data); i++ {
+		data[i] = byte(rand.Intn(256)) // #nosec G404
+	}
+
+	data2 := bytes.Repeat([]byte{1, 2, 3, 4}, 1024)
+
+	compressor, err := NewZstdCompressor(2 * units.MiB)
+	assert.NoError(t, err)
+
+	dataCompressed, err := compressor.Compress(data)
+	assert.NoError(t, err)
+
+	data2Compressed, err := compressor.Compress(data2)
+	assert.NoError(t, err)
+	assert.Less(t, len(data2Compressed), len(data2))
+
+	dataDecompressed, err := compressor.Decompress(dataCompressed)
+	assert.NoError(t, err)
+	assert.EqualValues(t, data, dataDecompressed)
+
+	data2Decompressed, err := compressor.Decompress(data2Compressed)
+	assert.NoError(t, err)
+	assert.EqualValues(t, data2, data2Decompressed)
+
+	nonZstdData := []byte{1, 2, 3}
+	_, err = compressor.Decompress(nonZstdData)
+	assert.Error(t, err)
+}
+
+func TestZstdSizeLimit(t *testing.T) {
+	compressor, err := NewZstdCompressor(1024)
+	assert.NoError(t, err)
+
+	_, err = compressor.Compress(make([]byte, 1025))
+	assert.Error(t, err)
+
+	largeCompressor, err := NewZstdCompressor(2 * units.MiB)
+	assert.NoError(t, err)
+	largeCompressed, err := largeCompressor.Compress(make([]byte, 2048))
+	assert.NoError(t, err)
+
+	_, err = compressor.Decompress(largeCompressed)
+	assert.Error(t, err)
+}
+
+func TestTypeFromString(t *testing.T) {
+	for _, compressionType := range []Type{NoCompression, Gzip, Zstd} {
+		parsed, err := TypeFromString(compressionType.String())
+		assert.NoError(t, err)
+		assert.Equal(t, compressionType, parsed)
+	}
+	_, err := TypeFromString("lz4")
+	assert.Error(t, err)
+}
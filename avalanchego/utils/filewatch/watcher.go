@@ -0,0 +1,79 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package filewatch
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"github.com/flare-foundation/flare/utils/timer/mockable"
+)
+
+// Watcher reports whether any file in a set of files has been modified.
+// The files are checked at most once every [interval], so that Changed can be
+// called on hot paths.
+type Watcher struct {
+	clock    mockable.Clock
+	interval time.Duration
+	paths    []string
+
+	lock      sync.Mutex
+	lastCheck time.Time
+	modTimes  []time.Time
+}
+
+// New returns a watcher of the files at [paths]. Modifications made before New
+// is called aren't reported.
+func New(interval time.Duration, paths ...string) (*Watcher, error) {
+	w := &Watcher{
+		interval: interval,
+		paths:    paths,
+	}
+	modTimes, err := w.stat()
+	if err != nil {
+		return nil, err
+	}
+	w.lastCheck = w.clock.Time()
+	w.modTimes = modTimes
+	return w, nil
+}
+
+// Changed returns true if a file has been modified since the last time that
+// Changed returned true, or since the watcher was created.
+func (w *Watcher) Changed() (bool, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	now := w.clock.Time()
+	if now.Sub(w.lastCheck) < w.interval {
+		return false, nil
+	}
+	w.lastCheck = now
+
+	modTimes, err := w.stat()
+	if err != nil {
+		return false, err
+	}
+	changed := false
+	for i, modTime := range modTimes {
+		if !modTime.Equal(w.modTimes[i]) {
+			changed = true
+		}
+	}
+	w.modTimes = modTimes
+	return changed, nil
+}
+
+func (w *Watcher) stat() ([]time.Time, error) {
+	modTimes := make([]time.Time, len(w.paths))
+	for i, path := range w.paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		modTimes[i] = info.ModTime()
+	}
+	return modTimes, nil
+}
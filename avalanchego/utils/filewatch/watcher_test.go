@@ -0,0 +1,64 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package filewatch
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatcherChanged(t *testing.T) {
+	assert := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "file")
+	assert.NoError(os.WriteFile(path, []byte("a"), 0o600))
+
+	w, err := New(time.Second, path)
+	assert.NoError(err)
+
+	now := time.Now()
+	w.clock.Set(now)
+
+	changed, err := w.Changed()
+	assert.NoError(err)
+	assert.False(changed)
+
+	modTime := now.Add(time.Minute)
+	assert.NoError(os.Chtimes(path, modTime, modTime))
+
+	// Not checked again until [interval] has passed
+	changed, err = w.Changed()
+	assert.NoError(err)
+	assert.False(changed)
+
+	w.clock.Set(now.Add(time.Second))
+	changed, err = w.Changed()
+	assert.NoError(err)
+	assert.True(changed)
+
+	w.clock.Set(now.Add(2 * time.Second))
+	changed, err = w.Changed()
+	assert.NoError(err)
+	assert.False(changed)
+}
+
+func TestWatcherMissingFile(t *testing.T) {
+	assert := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "file")
+	_, err := New(time.Second, path)
+	assert.Error(err)
+
+	assert.NoError(os.WriteFile(path, []byte("a"), 0o600))
+	w, err := New(0, path)
+	assert.NoError(err)
+
+	assert.NoError(os.Remove(path))
+	_, err = w.Changed()
+	assert.Error(err)
+}
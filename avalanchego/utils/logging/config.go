@@ -21,6 +21,7 @@ type Config struct {
 	RotationInterval            time.Duration `json:"rotationInterval"`
 	FileSize                    int           `json:"fileSize"`
 	RotationSize                int           `json:"rotationSize"`
+	RotationMaxAge              time.Duration `json:"rotationMaxAge"`
 	FlushSize                   int           `json:"flushSize"`
 	DisableLogging              bool          `json:"disableLogging"`
 	DisableDisplaying           bool          `json:"disableDisplaying"`
@@ -30,9 +31,12 @@ type Config struct {
 	LogLevel                    Level         `json:"logLevel"`
 	DisplayLevel                Level         `json:"displayLevel"`
 	DisplayHighlight            Highlight     `json:"displayHighlight"`
+	LogFormat                   Format        `json:"logFormat"`
 	Directory                   string        `json:"-"`
 	MsgPrefix                   string        `json:"-"`
 	LoggerName                  string        `json:"-"`
+	// Fields attached to every event of the logger
+	Fields []Field `json:"-"`
 }
 
 // DefaultConfig returns a logger configuration with default parameters
@@ -45,6 +49,7 @@ func DefaultConfig() (Config, error) {
 		FlushSize:        1,
 		DisplayLevel:     Info,
 		DisplayHighlight: Plain,
+		LogFormat:        PlainFormat,
 		LogLevel:         Debug,
 		Directory:        dir,
 	}, err
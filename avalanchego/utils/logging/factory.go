@@ -34,6 +34,10 @@ type Factory interface {
 	// GetLoggerNames returns the names of all logs created by this factory
 	GetLoggerNames() []string
 
+	// AddFields attaches [fields] to every event of the loggers created by
+	// this factory, including the loggers that are created later
+	AddFields(fields ...Field)
+
 	// Close stops and clears all of a Factory's instantiated loggers
 	Close()
 }
@@ -88,6 +92,7 @@ func (f *factory) MakeChain(chainID string) (Logger, error) {
 	config := f.config
 	config.MsgPrefix = chainID + " Chain"
 	config.LoggerName = chainID
+	config.Fields = appendFields(config.Fields, Chain(chainID))
 	return f.makeLogger(config)
 }
 
@@ -99,6 +104,7 @@ func (f *factory) MakeChainChild(chainID string, name string) (Logger, error) {
 	config := f.config
 	config.MsgPrefix = chainID + " Chain"
 	config.LoggerName = chainID + "." + name
+	config.Fields = appendFields(config.Fields, Chain(chainID))
 	return f.makeLogger(config)
 }
 
@@ -164,6 +170,19 @@ func (f *factory) GetLoggerNames() []string {
 	return names
 }
 
+// AddFields implements the Factory interface
+func (f *factory) AddFields(fields ...Field) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.config.Fields = appendFields(f.config.Fields, fields...)
+	for _, logger := range f.loggers {
+		if l, ok := logger.(*Log); ok {
+			l.addFields(fields)
+		}
+	}
+}
+
 // Close implements the Factory interface
 func (f *factory) Close() {
 	f.lock.Lock()
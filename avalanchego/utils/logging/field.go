@@ -0,0 +1,220 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/utils/constants"
+)
+
+// Keys of the fields that are shared by the logged events of all modules, so
+// that the events can be correlated without parsing their messages
+const (
+	ChainKey     = "chain"
+	NodeIDKey    = "nodeID"
+	HeightKey    = "height"
+	RequestIDKey = "requestID"
+	CallerKey    = "caller"
+)
+
+// Field is a key-value pair that is attached to a logged event
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// Chain returns the field of the chain an event relates to
+func Chain(chain string) Field { return Field{Key: ChainKey, Value: chain} }
+
+// NodeID returns the field of the node an event relates to
+func NodeID(nodeID ids.ShortID) Field {
+	return Field{Key: NodeIDKey, Value: nodeID.PrefixedString(constants.NodeIDPrefix)}
+}
+
+// Height returns the field of the height of the block an event relates to
+func Height(height uint64) Field { return Field{Key: HeightKey, Value: height} }
+
+// RequestID returns the field of the request an event relates to
+func RequestID(requestID uint32) Field { return Field{Key: RequestIDKey, Value: requestID} }
+
+// caller is the location in the code that an event is logged from
+type caller struct {
+	file string
+	line int
+}
+
+func (c caller) String() string {
+	return fmt.Sprintf("%s#%d", strings.TrimPrefix(c.file, filePrefix), c.line)
+}
+
+// Caller returns the field of the location in the code that an event is
+// logged from. It replaces the location of the call to the logger, for events
+// that are forwarded from another logger, such as those of geth.
+func Caller(file string, line int) Field {
+	return Field{Key: CallerKey, Value: caller{file: file, line: line}}
+}
+
+// splitCaller returns the location set by a Caller field of [fields], if any,
+// and the other fields
+func splitCaller(fields []Field) (string, []Field, bool) {
+	for i, field := range fields {
+		if c, ok := field.Value.(caller); ok && field.Key == CallerKey {
+			return c.String(), appendFields(fields[:i], fields[i+1:]...), true
+		}
+	}
+	return "", fields, false
+}
+
+// appendFields returns [fields] followed by [more], without modifying the
+// backing array of [fields]
+func appendFields(fields []Field, more ...Field) []Field {
+	newFields := make([]Field, 0, len(fields)+len(more))
+	newFields = append(newFields, fields...)
+	return append(newFields, more...)
+}
+
+// writePlainFields writes [fields] to [buf] as " key=value" pairs
+func writePlainFields(buf *bytes.Buffer, fields []Field) {
+	for _, field := range fields {
+		fmt.Fprintf(buf, " %s=%s", field.Key, Sanitize(fmt.Sprint(field.Value)))
+	}
+}
+
+// writeJSONField writes [key] and [value] to [buf] as a member of a JSON
+// object
+func writeJSONField(buf *bytes.Buffer, key string, value interface{}) {
+	if buf.Len() > 1 {
+		buf.WriteByte(',')
+	}
+	keyBytes, _ := json.Marshal(key)
+	buf.Write(keyBytes)
+	buf.WriteByte(':')
+
+	switch v := value.(type) {
+	case json.Marshaler:
+	case error:
+		value = v.Error()
+	case fmt.Stringer:
+		value = v.String()
+	}
+	valueBytes, err := json.Marshal(value)
+	if err != nil {
+		valueBytes, _ = json.Marshal(fmt.Sprint(value))
+	}
+	buf.Write(valueBytes)
+}
+
+// fieldLog is a logger that attaches fields to the events logged at each
+// level
+type fieldLog struct {
+	*Log
+	fields []Field
+}
+
+// With implements the Logger interface
+func (l *fieldLog) With(fields ...Field) Logger {
+	return &fieldLog{
+		Log:    l.Log,
+		fields: appendFields(l.fields, fields...),
+	}
+}
+
+// Fatal implements the Logger interface
+func (l *fieldLog) Fatal(format string, args ...interface{}) {
+	l.log(Fatal, l.fields, format, args...)
+}
+
+// Error implements the Logger interface
+func (l *fieldLog) Error(format string, args ...interface{}) {
+	l.log(Error, l.fields, format, args...)
+}
+
+// Warn implements the Logger interface
+func (l *fieldLog) Warn(format string, args ...interface{}) {
+	l.log(Warn, l.fields, format, args...)
+}
+
+// Info implements the Logger interface
+func (l *fieldLog) Info(format string, args ...interface{}) {
+	l.log(Info, l.fields, format, args...)
+}
+
+// Trace implements the Logger interface
+func (l *fieldLog) Trace(format string, args ...interface{}) {
+	l.log(Trace, l.fields, format, args...)
+}
+
+// Debug implements the Logger interface
+func (l *fieldLog) Debug(format string, args ...interface{}) {
+	l.log(Debug, l.fields, format, args...)
+}
+
+// Verbo implements the Logger interface
+func (l *fieldLog) Verbo(format string, args ...interface{}) {
+	l.log(Verbo, l.fields, format, args...)
+}
+
+// AssertNoError implements the Logger interface
+func (l *fieldLog) AssertNoError(err error) {
+	if err != nil {
+		l.log(Fatal, l.fields, "%s", err)
+		l.assertionFailed(err)
+	}
+}
+
+// AssertTrue implements the Logger interface
+func (l *fieldLog) AssertTrue(b bool, format string, args ...interface{}) {
+	if !b {
+		l.log(Fatal, l.fields, format, args...)
+		l.assertionFailed(fmt.Sprintf(format, args...))
+	}
+}
+
+// AssertDeferredTrue implements the Logger interface
+func (l *fieldLog) AssertDeferredTrue(f func() bool, format string, args ...interface{}) {
+	if l.config.Assertions && !f() {
+		err := fmt.Sprintf(format, args...)
+		l.log(Fatal, l.fields, "%s", err)
+		l.assertionFailed(err)
+	}
+}
+
+// AssertDeferredNoError implements the Logger interface
+func (l *fieldLog) AssertDeferredNoError(f func() error) {
+	if !l.config.Assertions {
+		return
+	}
+	if err := f(); err != nil {
+		l.log(Fatal, l.fields, "%s", err)
+		l.assertionFailed(err)
+	}
+}
+
+// StopOnPanic implements the Logger interface
+func (l *fieldLog) StopOnPanic() {
+	if r := recover(); r != nil {
+		l.Fatal("Panicking due to:\n%s\nFrom:\n%s", r, Stacktrace{})
+		l.Stop()
+		panic(r)
+	}
+}
+
+// RecoverAndPanic implements the Logger interface
+func (l *fieldLog) RecoverAndPanic(f func()) { defer l.StopOnPanic(); f() }
+
+func (l *fieldLog) stopAndExit(exit func()) {
+	if r := recover(); r != nil {
+		l.Fatal("Panicking due to:\n%s\nFrom:\n%s", r, Stacktrace{})
+		l.Stop()
+		exit()
+	}
+}
+
+// RecoverAndExit implements the Logger interface
+func (l *fieldLog) RecoverAndExit(f, exit func()) { defer l.stopAndExit(exit); f() }
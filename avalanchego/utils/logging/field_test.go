@@ -0,0 +1,188 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package logging
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/flare-foundation/flare/ids"
+)
+
+// readJSONLines returns the JSON objects in the log file of [loggerName]
+func readJSONLines(t *testing.T, dir, loggerName string) []map[string]interface{} {
+	file, err := os.Open(filepath.Join(dir, loggerName+".log"))
+	assert.NoError(t, err)
+	defer file.Close()
+
+	var lines []map[string]interface{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := map[string]interface{}{}
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &line), scanner.Text())
+		lines = append(lines, line)
+	}
+	assert.NoError(t, scanner.Err())
+	return lines
+}
+
+func TestJSONFormat(t *testing.T) {
+	assert := assert.New(t)
+
+	config, err := DefaultConfig()
+	assert.NoError(err)
+	config.Directory = t.TempDir()
+	config.LogFormat = JSONFormat
+	config.LogLevel = Debug
+	config.DisableDisplaying = true
+
+	nodeID := ids.ShortID{1}
+	factory := NewFactory(config)
+	log, err := factory.MakeChain("C")
+	assert.NoError(err)
+	factory.AddFields(NodeID(nodeID))
+
+	log.Info("accepted %d blocks", 2)
+	log.With(Height(5), RequestID(7)).Debug("accepted block\nwith newline")
+	log.With(Field{Key: "err", Value: errors.New("failure")}).Warn("failed")
+	log.Verbo("not logged")
+	_, err = log.Write([]byte("pre-formatted\n"))
+	assert.NoError(err)
+	factory.Close()
+
+	lines := readJSONLines(t, config.Directory, "C")
+	if !assert.Len(lines, 4) {
+		return
+	}
+	for _, line := range lines {
+		assert.Equal("C", line["logger"])
+		assert.Equal("C", line[ChainKey])
+		assert.Equal(nodeID.PrefixedString("NodeID-"), line[NodeIDKey])
+		_, err := time.Parse(time.RFC3339Nano, line["timestamp"].(string))
+		assert.NoError(err)
+	}
+
+	assert.Equal("INFO", lines[0]["level"])
+	assert.Equal("accepted 2 blocks", lines[0]["msg"])
+	assert.True(strings.HasPrefix(lines[0]["caller"].(string), "utils/logging/field_test.go#"))
+
+	assert.Equal("DEBUG", lines[1]["level"])
+	assert.Equal("accepted block\nwith newline", lines[1]["msg"])
+	assert.Equal(float64(5), lines[1][HeightKey])
+	assert.Equal(float64(7), lines[1][RequestIDKey])
+
+	assert.Equal("failure", lines[2]["err"])
+
+	assert.Equal("pre-formatted", lines[3]["msg"])
+	assert.NotContains(lines[3], "level")
+}
+
+func TestAssertionFields(t *testing.T) {
+	assert := assert.New(t)
+
+	config, err := DefaultConfig()
+	assert.NoError(err)
+	config.Directory = t.TempDir()
+	config.LogFormat = JSONFormat
+	config.DisableDisplaying = true
+
+	factory := NewFactory(config)
+	log, err := factory.MakeChain("C")
+	assert.NoError(err)
+
+	fieldLog := log.With(Height(5))
+	fieldLog.AssertNoError(errors.New("failure"))
+	fieldLog.AssertTrue(false, "not %s", "true")
+	exited := false
+	fieldLog.RecoverAndExit(func() { panic("panicked") }, func() { exited = true })
+	assert.True(exited)
+	factory.Close()
+
+	// The failed assertions and the recovered panic keep the fields of the
+	// logger
+	lines := readJSONLines(t, config.Directory, "C")
+	if !assert.Len(lines, 3) {
+		return
+	}
+	for _, line := range lines {
+		assert.Equal("FATAL", line["level"])
+		assert.Equal(float64(5), line[HeightKey])
+	}
+	assert.Equal("failure", lines[0]["msg"])
+	assert.True(strings.HasPrefix(lines[0]["caller"].(string), "utils/logging/field_test.go#"))
+	assert.Equal("not true", lines[1]["msg"])
+	assert.True(strings.HasPrefix(lines[2]["msg"].(string), "Panicking due to:\npanicked"))
+}
+
+func TestPlainFormatFields(t *testing.T) {
+	assert := assert.New(t)
+
+	config, err := DefaultConfig()
+	assert.NoError(err)
+	config.MsgPrefix = "C Chain"
+	config.Fields = []Field{Chain("C")}
+
+	log, err := NewTestLog(config)
+	assert.NoError(err)
+
+	output := log.format(Info, []Field{Height(5), {Key: "reason", Value: "a\nb"}}, "accepting block %s", "a")
+	assert.Contains(output, "<C Chain>")
+	assert.Contains(output, "accepting block a height=5 reason=a\\nb\n")
+	assert.NotContains(output, "chain=C")
+}
+
+func TestCallerField(t *testing.T) {
+	assert := assert.New(t)
+
+	config, err := DefaultConfig()
+	assert.NoError(err)
+
+	log, err := NewTestLog(config)
+	assert.NoError(err)
+
+	// The caller field replaces the location of the call to the logger
+	output := log.format(Info, []Field{Caller(filePrefix+"core/blockchain.go", 12), Height(5)}, "accepting block")
+	assert.Contains(output, " core/blockchain.go#12: accepting block height=5\n")
+	assert.NotContains(output, "caller=")
+
+	// A field that is only named like the caller field is logged as is
+	output = log.format(Info, []Field{{Key: CallerKey, Value: "geth"}}, "accepting block")
+	assert.Contains(output, "accepting block caller=geth\n")
+}
+
+func TestWithDoesNotShareFields(t *testing.T) {
+	config, err := DefaultConfig()
+	assert.NoError(t, err)
+
+	log, err := NewTestLog(config)
+	assert.NoError(t, err)
+
+	parent := log.With(Height(1)).(*fieldLog)
+	child1 := parent.With(RequestID(1)).(*fieldLog)
+	child2 := parent.With(RequestID(2)).(*fieldLog)
+	assert.Equal(t, []Field{Height(1)}, parent.fields)
+	assert.Equal(t, []Field{Height(1), RequestID(1)}, child1.fields)
+	assert.Equal(t, []Field{Height(1), RequestID(2)}, child2.fields)
+}
+
+func TestToFormat(t *testing.T) {
+	format, err := ToFormat("json")
+	assert.NoError(t, err)
+	assert.Equal(t, JSONFormat, format)
+
+	format, err = ToFormat("PLAIN")
+	assert.NoError(t, err)
+	assert.Equal(t, PlainFormat, format)
+
+	_, err = ToFormat("xml")
+	assert.Error(t, err)
+}
@@ -0,0 +1,46 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Formats available for logged events
+const (
+	// PlainFormat writes each event as a human readable line
+	PlainFormat Format = iota
+	// JSONFormat writes each event as a JSON object on its own line
+	JSONFormat
+)
+
+var errUnknownFormat = errors.New("unknown format")
+
+// Format of logged events
+type Format int
+
+// ToFormat chooses a format
+func ToFormat(f string) (Format, error) {
+	switch strings.ToUpper(f) {
+	case "PLAIN":
+		return PlainFormat, nil
+	case "JSON":
+		return JSONFormat, nil
+	default:
+		return PlainFormat, fmt.Errorf("unknown log format: %s", f)
+	}
+}
+
+func (f Format) MarshalJSON() ([]byte, error) {
+	switch f {
+	case PlainFormat:
+		return []byte("\"PLAIN\""), nil
+	case JSONFormat:
+		return []byte("\"JSON\""), nil
+	default:
+		return nil, errUnknownFormat
+	}
+}
@@ -5,6 +5,7 @@ package logging
 
 import (
 	"bufio"
+	"bytes"
 	"errors"
 	"fmt"
 	"os"
@@ -21,6 +22,9 @@ var (
 	fileSuffix = "utils/logging/log.go"
 	filePrefix string
 	timeFormat = "01-02|15:04:05.000"
+
+	_ Logger = &Log{}
+	_ Logger = &fieldLog{}
 )
 
 func init() {
@@ -133,9 +137,14 @@ func (l *Log) Write(p []byte) (int, error) {
 	defer l.configLock.Unlock()
 
 	if !l.config.DisableLogging {
+		msg := string(p)
+		if l.config.LogFormat == JSONFormat {
+			msg = l.formatWrite(msg)
+		}
+
 		l.flushLock.Lock()
-		l.messages = append(l.messages, string(p))
-		l.size += len(p)
+		l.messages = append(l.messages, msg)
+		l.size += len(msg)
 		l.needsFlush.Signal()
 		l.flushLock.Unlock()
 	}
@@ -154,7 +163,7 @@ func (l *Log) Stop() {
 }
 
 // Should only be called from [Level] functions.
-func (l *Log) log(level Level, format string, args ...interface{}) {
+func (l *Log) log(level Level, fields []Field, format string, args ...interface{}) {
 	if l == nil {
 		return
 	}
@@ -171,7 +180,7 @@ func (l *Log) log(level Level, format string, args ...interface{}) {
 
 	args = SanitizeArgs(args)
 
-	output := l.format(level, format, args...)
+	output := l.format(level, fields, format, args...)
 
 	if shouldLog {
 		l.flushLock.Lock()
@@ -185,7 +194,7 @@ func (l *Log) log(level Level, format string, args ...interface{}) {
 		switch {
 		case l.config.DisableContextualDisplaying:
 			fmt.Println(fmt.Sprintf(format, args...))
-		case l.config.DisplayHighlight == Plain:
+		case l.config.DisplayHighlight == Plain || l.config.LogFormat == JSONFormat:
 			fmt.Print(output)
 		default:
 			fmt.Print(level.Color().Wrap(output))
@@ -193,13 +202,39 @@ func (l *Log) log(level Level, format string, args ...interface{}) {
 	}
 }
 
-func (l *Log) format(level Level, format string, args ...interface{}) string {
-	loc := "?"
-	if _, file, no, ok := runtime.Caller(3); ok {
-		localFile := strings.TrimPrefix(file, filePrefix)
-		loc = fmt.Sprintf("%s#%d", localFile, no)
+func (l *Log) format(level Level, fields []Field, format string, args ...interface{}) string {
+	loc, fields, ok := splitCaller(fields)
+	if !ok {
+		loc = "?"
+		if _, file, no, ok := runtime.Caller(3); ok {
+			loc = caller{file: file, line: no}.String()
+		}
+	}
+	msg := fmt.Sprintf(format, args...)
+
+	if l.config.LogFormat == JSONFormat {
+		buf := bytes.Buffer{}
+		buf.WriteByte('{')
+		writeJSONField(&buf, "timestamp", time.Now().UTC().Format(time.RFC3339Nano))
+		writeJSONField(&buf, "level", level.String())
+		writeJSONField(&buf, "logger", l.config.LoggerName)
+		writeJSONField(&buf, "caller", loc)
+		writeJSONField(&buf, "msg", msg)
+		for _, field := range l.config.Fields {
+			writeJSONField(&buf, field.Key, field.Value)
+		}
+		for _, field := range fields {
+			writeJSONField(&buf, field.Key, field.Value)
+		}
+		buf.WriteString("}\n")
+		return buf.String()
 	}
-	text := fmt.Sprintf("%s: %s", loc, fmt.Sprintf(format, args...))
+
+	// The fields of the logger, such as the chain, are already conveyed by
+	// the prefix
+	text := bytes.Buffer{}
+	fmt.Fprintf(&text, "%s: %s", loc, msg)
+	writePlainFields(&text, fields)
 
 	prefix := ""
 	if l.config.MsgPrefix != "" {
@@ -210,49 +245,66 @@ func (l *Log) format(level Level, format string, args ...interface{}) string {
 		level.AlignedString(),
 		time.Now().Format(timeFormat),
 		prefix,
-		text)
+		text.String())
+}
+
+// formatWrite wraps a pre-formatted message into a JSON event, so that every
+// line of the log file is a JSON object
+func (l *Log) formatWrite(msg string) string {
+	buf := bytes.Buffer{}
+	buf.WriteByte('{')
+	writeJSONField(&buf, "timestamp", time.Now().UTC().Format(time.RFC3339Nano))
+	writeJSONField(&buf, "logger", l.config.LoggerName)
+	writeJSONField(&buf, "msg", strings.TrimRight(msg, "\n"))
+	for _, field := range l.config.Fields {
+		writeJSONField(&buf, field.Key, field.Value)
+	}
+	buf.WriteString("}\n")
+	return buf.String()
 }
 
 // Fatal implements the Logger interface
-func (l *Log) Fatal(format string, args ...interface{}) { l.log(Fatal, format, args...) }
+func (l *Log) Fatal(format string, args ...interface{}) { l.log(Fatal, nil, format, args...) }
 
 // Error implements the Logger interface
-func (l *Log) Error(format string, args ...interface{}) { l.log(Error, format, args...) }
+func (l *Log) Error(format string, args ...interface{}) { l.log(Error, nil, format, args...) }
 
 // Warn implements the Logger interface
-func (l *Log) Warn(format string, args ...interface{}) { l.log(Warn, format, args...) }
+func (l *Log) Warn(format string, args ...interface{}) { l.log(Warn, nil, format, args...) }
 
 // Info implements the Logger interface
-func (l *Log) Info(format string, args ...interface{}) { l.log(Info, format, args...) }
+func (l *Log) Info(format string, args ...interface{}) { l.log(Info, nil, format, args...) }
 
 // Trace implements the Logger interface
-func (l *Log) Trace(format string, args ...interface{}) { l.log(Trace, format, args...) }
+func (l *Log) Trace(format string, args ...interface{}) { l.log(Trace, nil, format, args...) }
 
 // Debug implements the Logger interface
-func (l *Log) Debug(format string, args ...interface{}) { l.log(Debug, format, args...) }
+func (l *Log) Debug(format string, args ...interface{}) { l.log(Debug, nil, format, args...) }
 
 // Verbo implements the Logger interface
-func (l *Log) Verbo(format string, args ...interface{}) { l.log(Verbo, format, args...) }
+func (l *Log) Verbo(format string, args ...interface{}) { l.log(Verbo, nil, format, args...) }
+
+// With implements the Logger interface
+func (l *Log) With(fields ...Field) Logger {
+	return &fieldLog{
+		Log:    l,
+		fields: fields,
+	}
+}
 
 // AssertNoError implements the Logger interface
 func (l *Log) AssertNoError(err error) {
 	if err != nil {
-		l.log(Fatal, "%s", err)
-	}
-	if l.config.Assertions && err != nil {
-		l.Stop()
-		panic(err)
+		l.log(Fatal, nil, "%s", err)
+		l.assertionFailed(err)
 	}
 }
 
 // AssertTrue implements the Logger interface
 func (l *Log) AssertTrue(b bool, format string, args ...interface{}) {
 	if !b {
-		l.log(Fatal, format, args...)
-	}
-	if l.config.Assertions && !b {
-		l.Stop()
-		panic(fmt.Sprintf(format, args...))
+		l.log(Fatal, nil, format, args...)
+		l.assertionFailed(fmt.Sprintf(format, args...))
 	}
 }
 
@@ -261,23 +313,28 @@ func (l *Log) AssertDeferredTrue(f func() bool, format string, args ...interface
 	// Note, the logger will only be notified here if assertions are enabled
 	if l.config.Assertions && !f() {
 		err := fmt.Sprintf(format, args...)
-		l.log(Fatal, err)
-		l.Stop()
-		panic(err)
+		l.log(Fatal, nil, "%s", err)
+		l.assertionFailed(err)
 	}
 }
 
 // AssertDeferredNoError implements the Logger interface
 func (l *Log) AssertDeferredNoError(f func() error) {
+	if !l.config.Assertions {
+		return
+	}
+	if err := f(); err != nil {
+		l.log(Fatal, nil, "%s", err)
+		l.assertionFailed(err)
+	}
+}
+
+// assertionFailed stops the logger and panics with [failure] if assertions
+// are enabled
+func (l *Log) assertionFailed(failure interface{}) {
 	if l.config.Assertions {
-		err := f()
-		if err != nil {
-			l.log(Fatal, "%s", err)
-		}
-		if l.config.Assertions && err != nil {
-			l.Stop()
-			panic(err)
-		}
+		l.Stop()
+		panic(failure)
 	}
 }
 
@@ -359,6 +416,14 @@ func (l *Log) SetDisplayingEnabled(enabled bool) {
 	l.config.DisableDisplaying = !enabled
 }
 
+// addFields attaches [fields] to every event of the logger
+func (l *Log) addFields(fields []Field) {
+	l.configLock.Lock()
+	defer l.configLock.Unlock()
+
+	l.config.Fields = appendFields(l.config.Fields, fields...)
+}
+
 // SetContextualDisplayingEnabled implements the Logger interface
 func (l *Log) SetContextualDisplayingEnabled(enabled bool) {
 	l.configLock.Lock()
@@ -417,6 +482,31 @@ func (fw *fileWriter) Rotate() error {
 	}
 	fw.file = file
 	fw.writer = writer
+	return fw.removeExpired()
+}
+
+// removeExpired removes the rotated log files that are older than
+// [RotationMaxAge]
+func (fw *fileWriter) removeExpired() error {
+	if fw.config.RotationMaxAge <= 0 {
+		return nil
+	}
+	expiry := time.Now().Add(-fw.config.RotationMaxAge)
+	for i := 1; i <= fw.config.RotationSize; i++ {
+		filename := filepath.Join(fw.config.Directory, fmt.Sprintf("%s.log.%d", fw.config.LoggerName, i))
+		info, err := os.Stat(filename)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if info.ModTime().Before(expiry) {
+			if err := os.Remove(filename); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
 
@@ -34,6 +34,10 @@ type Logger interface {
 	// aspect of the program
 	Verbo(format string, args ...interface{})
 
+	// Returns a logger that attaches [fields] to the events it logs at each
+	// level. The returned logger shares the output and levels of this logger.
+	With(fields ...Field) Logger
+
 	// If assertions are enabled, will result in a panic if err is non-nil
 	AssertNoError(err error)
 	// If assertions are enabled, will result in a panic if b is false
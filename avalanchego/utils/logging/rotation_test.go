@@ -0,0 +1,45 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRotateRemovesExpiredFiles(t *testing.T) {
+	assert := assert.New(t)
+
+	config, err := DefaultConfig()
+	assert.NoError(err)
+	config.Directory = t.TempDir()
+	config.LoggerName = "main"
+	config.RotationSize = 3
+	config.RotationMaxAge = time.Hour
+
+	fw := &fileWriter{}
+	_, err = fw.Initialize(config)
+	assert.NoError(err)
+
+	// main.log.1 becomes main.log.2 on rotation and is expired
+	expired := filepath.Join(config.Directory, "main.log.1")
+	assert.NoError(os.WriteFile(expired, []byte("old"), 0o600))
+	old := time.Now().Add(-2 * time.Hour)
+	assert.NoError(os.Chtimes(expired, old, old))
+
+	assert.NoError(fw.Close())
+	assert.NoError(fw.Rotate())
+	assert.NoError(fw.Close())
+
+	_, err = os.Stat(filepath.Join(config.Directory, "main.log.1"))
+	assert.NoError(err, "recently rotated file should be kept")
+	_, err = os.Stat(filepath.Join(config.Directory, "main.log.2"))
+	assert.ErrorIs(err, os.ErrNotExist, "expired file should be removed")
+	_, err = os.Stat(filepath.Join(config.Directory, "main.log"))
+	assert.NoError(err)
+}
@@ -11,6 +11,8 @@ func (NoFactory) MakeChain(string) (Logger, error) { return NoLog{}, nil }
 
 func (NoFactory) MakeChainChild(string, string) (Logger, error) { return NoLog{}, nil }
 
+func (NoFactory) AddFields(...Field) {}
+
 func (NoFactory) Close() {}
 
 func (NoFactory) SetLogLevel(name string, level Level) error { return nil }
@@ -28,6 +28,8 @@ func (NoLog) Debug(format string, args ...interface{}) {}
 
 func (NoLog) Verbo(format string, args ...interface{}) {}
 
+func (NoLog) With(...Field) Logger { return NoLog{} }
+
 func (NoLog) AssertNoError(error) {}
 
 func (NoLog) AssertTrue(b bool, format string, args ...interface{}) {}
@@ -12,12 +12,14 @@ import (
 // These are globals that describe network upgrades and node versions
 var (
 	// Flare versioning constants.
-	Current                      = NewDefaultVersion(0, 6, 5)
+	Current                      = NewDefaultVersion(0, 6, 6)
 	CurrentApp                   = NewDefaultApplication(constants.PlatformName, Current.Major(), Current.Minor(), Current.Patch())
 	MinimumCompatibleVersion     = NewDefaultApplication(constants.PlatformName, 0, 6, 0)
 	PrevMinimumCompatibleVersion = NewDefaultApplication(constants.PlatformName, 0, 5, 1)
 	MinimumUnmaskedVersion       = NewDefaultApplication(constants.PlatformName, 0, 5, 1)
 	PrevMinimumUnmaskedVersion   = NewDefaultApplication(constants.PlatformName, 0, 5, 1)
+	// Oldest version that decompresses messages compressed with zstd
+	MinimumZstdVersion = NewDefaultApplication(constants.PlatformName, 0, 6, 6)
 
 	VersionParser = NewDefaultApplicationParser()
 
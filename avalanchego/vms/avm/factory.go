@@ -5,11 +5,16 @@ package avm
 
 import (
 	"github.com/flare-foundation/flare/snow"
+	"github.com/flare-foundation/flare/vms/components/keystore"
 )
 
 type Factory struct {
 	TxFee            uint64
 	CreateAssetTxFee uint64
+
+	// Signs the transactions issued through the keystore API instead of the
+	// keys of the user. Nil if the keys of the user are used.
+	RemoteSigner keystore.ChainSigner
 }
 
 func (f *Factory) New(*snow.Context) (interface{}, error) {
@@ -11,7 +11,6 @@ import (
 	"github.com/flare-foundation/flare/codec"
 	"github.com/flare-foundation/flare/ids"
 	"github.com/flare-foundation/flare/utils"
-	"github.com/flare-foundation/flare/utils/crypto"
 	"github.com/flare-foundation/flare/vms/components/avax"
 	"github.com/flare-foundation/flare/vms/components/verify"
 )
@@ -75,7 +74,7 @@ func isSortedAndUniqueOperations(ops []*Operation, c codec.Manager) bool {
 
 type innerSortOperationsWithSigners struct {
 	ops     []*Operation
-	signers [][]*crypto.PrivateKeySECP256K1R
+	signers [][]ids.ShortID
 	codec   codec.Manager
 }
 
@@ -99,6 +98,6 @@ func (ops *innerSortOperationsWithSigners) Swap(i, j int) {
 	ops.signers[j], ops.signers[i] = ops.signers[i], ops.signers[j]
 }
 
-func sortOperationsWithSigners(ops []*Operation, signers [][]*crypto.PrivateKeySECP256K1R, codec codec.Manager) {
+func sortOperationsWithSigners(ops []*Operation, signers [][]ids.ShortID, codec codec.Manager) {
 	sort.Sort(&innerSortOperationsWithSigners{ops: ops, signers: signers, codec: codec})
 }
@@ -518,10 +518,10 @@ func (service *Service) CreateAsset(r *http.Request, args *CreateAssetArgs, repl
 	}
 
 	// Parse the change address.
-	if len(kc.Keys) == 0 {
+	if len(kc.AddrList) == 0 {
 		return errNoKeys
 	}
-	changeAddr, err := service.vm.selectChangeAddr(kc.Keys[0].PublicKey().Address(), args.ChangeAddr)
+	changeAddr, err := service.vm.selectChangeAddr(kc.AddrList[0], args.ChangeAddr)
 	if err != nil {
 		return err
 	}
@@ -600,7 +600,7 @@ func (service *Service) CreateAsset(r *http.Request, args *CreateAssetArgs, repl
 		Denomination: args.Denomination,
 		States:       []*InitialState{initialState},
 	}}
-	if err := tx.SignSECP256K1Fx(service.vm.codec, keys); err != nil {
+	if err := tx.SignSECP256K1FxWithSigner(service.vm.codec, kc, keys); err != nil {
 		return err
 	}
 
@@ -669,10 +669,10 @@ func (service *Service) CreateNFTAsset(r *http.Request, args *CreateNFTAssetArgs
 	}
 
 	// Parse the change address.
-	if len(kc.Keys) == 0 {
+	if len(kc.AddrList) == 0 {
 		return errNoKeys
 	}
-	changeAddr, err := service.vm.selectChangeAddr(kc.Keys[0].PublicKey().Address(), args.ChangeAddr)
+	changeAddr, err := service.vm.selectChangeAddr(kc.AddrList[0], args.ChangeAddr)
 	if err != nil {
 		return err
 	}
@@ -738,7 +738,7 @@ func (service *Service) CreateNFTAsset(r *http.Request, args *CreateNFTAssetArgs
 		Denomination: 0, // NFTs are non-fungible
 		States:       []*InitialState{initialState},
 	}}
-	if err := tx.SignSECP256K1Fx(service.vm.codec, keys); err != nil {
+	if err := tx.SignSECP256K1FxWithSigner(service.vm.codec, kc, keys); err != nil {
 		return err
 	}
 
@@ -971,10 +971,10 @@ func (service *Service) SendMultiple(r *http.Request, args *SendMultipleArgs, re
 	}
 
 	// Parse the change address.
-	if len(kc.Keys) == 0 {
+	if len(kc.AddrList) == 0 {
 		return errNoKeys
 	}
-	changeAddr, err := service.vm.selectChangeAddr(kc.Keys[0].PublicKey().Address(), args.ChangeAddr)
+	changeAddr, err := service.vm.selectChangeAddr(kc.AddrList[0], args.ChangeAddr)
 	if err != nil {
 		return err
 	}
@@ -1072,7 +1072,7 @@ func (service *Service) SendMultiple(r *http.Request, args *SendMultipleArgs, re
 		Ins:          ins,
 		Memo:         memoBytes,
 	}}}
-	if err := tx.SignSECP256K1Fx(service.vm.codec, keys); err != nil {
+	if err := tx.SignSECP256K1FxWithSigner(service.vm.codec, kc, keys); err != nil {
 		return err
 	}
 
@@ -1125,10 +1125,10 @@ func (service *Service) Mint(r *http.Request, args *MintArgs, reply *api.JSONTxI
 	}
 
 	// Parse the change address.
-	if len(feeKc.Keys) == 0 {
+	if len(feeKc.AddrList) == 0 {
 		return errNoKeys
 	}
-	changeAddr, err := service.vm.selectChangeAddr(feeKc.Keys[0].PublicKey().Address(), args.ChangeAddr)
+	changeAddr, err := service.vm.selectChangeAddr(feeKc.AddrList[0], args.ChangeAddr)
 	if err != nil {
 		return err
 	}
@@ -1187,7 +1187,7 @@ func (service *Service) Mint(r *http.Request, args *MintArgs, reply *api.JSONTxI
 		}},
 		Ops: ops,
 	}}
-	if err := tx.SignSECP256K1Fx(service.vm.codec, keys); err != nil {
+	if err := tx.SignSECP256K1FxWithSigner(service.vm.codec, kc, keys); err != nil {
 		return err
 	}
 
@@ -1238,10 +1238,10 @@ func (service *Service) SendNFT(r *http.Request, args *SendNFTArgs, reply *api.J
 	}
 
 	// Parse the change address.
-	if len(kc.Keys) == 0 {
+	if len(kc.AddrList) == 0 {
 		return errNoKeys
 	}
-	changeAddr, err := service.vm.selectChangeAddr(kc.Keys[0].PublicKey().Address(), args.ChangeAddr)
+	changeAddr, err := service.vm.selectChangeAddr(kc.AddrList[0], args.ChangeAddr)
 	if err != nil {
 		return err
 	}
@@ -1292,10 +1292,10 @@ func (service *Service) SendNFT(r *http.Request, args *SendNFTArgs, reply *api.J
 		}},
 		Ops: ops,
 	}}
-	if err := tx.SignSECP256K1Fx(service.vm.codec, secpKeys); err != nil {
+	if err := tx.SignSECP256K1FxWithSigner(service.vm.codec, kc, secpKeys); err != nil {
 		return err
 	}
-	if err := tx.SignNFTFx(service.vm.codec, nftKeys); err != nil {
+	if err := tx.SignNFTFxWithSigner(service.vm.codec, kc, nftKeys); err != nil {
 		return err
 	}
 
@@ -1350,10 +1350,10 @@ func (service *Service) MintNFT(r *http.Request, args *MintNFTArgs, reply *api.J
 	}
 
 	// Parse the change address.
-	if len(feeKc.Keys) == 0 {
+	if len(feeKc.AddrList) == 0 {
 		return errNoKeys
 	}
-	changeAddr, err := service.vm.selectChangeAddr(feeKc.Keys[0].PublicKey().Address(), args.ChangeAddr)
+	changeAddr, err := service.vm.selectChangeAddr(feeKc.AddrList[0], args.ChangeAddr)
 	if err != nil {
 		return err
 	}
@@ -1410,10 +1410,10 @@ func (service *Service) MintNFT(r *http.Request, args *MintNFTArgs, reply *api.J
 		}},
 		Ops: ops,
 	}}
-	if err := tx.SignSECP256K1Fx(service.vm.codec, secpKeys); err != nil {
+	if err := tx.SignSECP256K1FxWithSigner(service.vm.codec, kc, secpKeys); err != nil {
 		return err
 	}
-	if err := tx.SignNFTFx(service.vm.codec, nftKeys); err != nil {
+	if err := tx.SignNFTFxWithSigner(service.vm.codec, kc, nftKeys); err != nil {
 		return err
 	}
 
@@ -1471,7 +1471,7 @@ func (service *Service) Import(_ *http.Request, args *ImportArgs, reply *api.JSO
 	}
 
 	ins := []*avax.TransferableInput{}
-	keys := [][]*crypto.PrivateKeySECP256K1R{}
+	keys := [][]ids.ShortID{}
 
 	if amountSpent := amountsSpent[service.vm.feeAssetID]; amountSpent < service.vm.TxFee {
 		var localAmountsSpent map[ids.ID]uint64
@@ -1528,7 +1528,7 @@ func (service *Service) Import(_ *http.Request, args *ImportArgs, reply *api.JSO
 		SourceChain: chainID,
 		ImportedIns: importInputs,
 	}}
-	if err := tx.SignSECP256K1Fx(service.vm.codec, keys); err != nil {
+	if err := tx.SignSECP256K1FxWithSigner(service.vm.codec, kc, keys); err != nil {
 		return err
 	}
 
@@ -1589,10 +1589,10 @@ func (service *Service) Export(_ *http.Request, args *ExportArgs, reply *api.JSO
 	}
 
 	// Parse the change address.
-	if len(kc.Keys) == 0 {
+	if len(kc.AddrList) == 0 {
 		return errNoKeys
 	}
-	changeAddr, err := service.vm.selectChangeAddr(kc.Keys[0].PublicKey().Address(), args.ChangeAddr)
+	changeAddr, err := service.vm.selectChangeAddr(kc.AddrList[0], args.ChangeAddr)
 	if err != nil {
 		return err
 	}
@@ -1655,7 +1655,7 @@ func (service *Service) Export(_ *http.Request, args *ExportArgs, reply *api.JSO
 		DestinationChain: chainID,
 		ExportedOuts:     exportOuts,
 	}}
-	if err := tx.SignSECP256K1Fx(service.vm.codec, keys); err != nil {
+	if err := tx.SignSECP256K1FxWithSigner(service.vm.codec, kc, keys); err != nil {
 		return err
 	}
 
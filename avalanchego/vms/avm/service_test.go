@@ -2570,6 +2570,53 @@ func TestSend(t *testing.T) {
 	}
 }
 
+func TestSendRemoteSigner(t *testing.T) {
+	_, vm, s, _, genesisTx := setup(t, true)
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		vm.ctx.Lock.Unlock()
+	}()
+
+	// The user doesn't hold any key, the remote signer does
+	keychain := secp256k1fx.NewKeychain(keys...)
+	vm.RemoteSigner = keystore.NewAnyChainSigner(keystore.NewKeychainSigner(keychain))
+
+	addrStr, err := vm.FormatLocalAddress(keys[0].PublicKey().Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := &SendArgs{
+		JSONSpendHeader: api.JSONSpendHeader{
+			UserPass: api.UserPass{
+				Username: username,
+				Password: password,
+			},
+		},
+		SendOutput: SendOutput{
+			Amount:  500,
+			AssetID: genesisTx.ID().String(),
+			To:      addrStr,
+		},
+	}
+	reply := &api.JSONTxIDChangeAddr{}
+	vm.timer.Cancel()
+	if err := s.Send(nil, args, reply); err != nil {
+		t.Fatalf("Failed to send transaction: %s", err)
+	}
+	pendingTxs := vm.txs
+	if len(pendingTxs) != 1 || pendingTxs[0].ID() != reply.TxID {
+		t.Fatal("Transaction returned by Send isn't pending")
+	}
+
+	// The user must still authenticate
+	args.Password = "wrong password"
+	if err := s.Send(nil, args, reply); err == nil {
+		t.Fatal("should have failed with the wrong password")
+	}
+}
+
 func TestSendMultiple(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
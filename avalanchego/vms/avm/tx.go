@@ -121,32 +121,16 @@ func (t *Tx) SemanticVerify(vm *VM, tx UnsignedTx) error {
 }
 
 func (t *Tx) SignSECP256K1Fx(c codec.Manager, signers [][]*crypto.PrivateKeySECP256K1R) error {
-	unsignedBytes, err := c.Marshal(codecVersion, &t.UnsignedTx)
-	if err != nil {
-		return fmt.Errorf("problem creating transaction: %w", err)
-	}
-
-	hash := hashing.ComputeHash256(unsignedBytes)
-	for _, keys := range signers {
-		cred := &secp256k1fx.Credential{
-			Sigs: make([][crypto.SECP256K1RSigLen]byte, len(keys)),
-		}
-		for i, key := range keys {
-			sig, err := key.SignHash(hash)
-			if err != nil {
-				return fmt.Errorf("problem creating transaction: %w", err)
-			}
-			copy(cred.Sigs[i][:], sig)
-		}
-		t.Creds = append(t.Creds, &FxCredential{Verifiable: cred})
-	}
+	kc, addrs := secp256k1fx.SignerAddrs(signers)
+	return t.SignSECP256K1FxWithSigner(c, kc, addrs)
+}
 
-	signedBytes, err := c.Marshal(codecVersion, t)
-	if err != nil {
-		return fmt.Errorf("problem creating transaction: %w", err)
-	}
-	t.Initialize(unsignedBytes, signedBytes)
-	return nil
+// SignSECP256K1FxWithSigner adds a credential for each element of [signers],
+// signed by [signer] with the keys that control the addresses of the element
+func (t *Tx) SignSECP256K1FxWithSigner(c codec.Manager, signer secp256k1fx.Signer, signers [][]ids.ShortID) error {
+	return t.signWithSigner(c, signer, signers, func(cred *secp256k1fx.Credential) verify.Verifiable {
+		return cred
+	})
 }
 
 func (t *Tx) SignPropertyFx(c codec.Manager, signers [][]*crypto.PrivateKeySECP256K1R) error {
@@ -179,24 +163,37 @@ func (t *Tx) SignPropertyFx(c codec.Manager, signers [][]*crypto.PrivateKeySECP2
 }
 
 func (t *Tx) SignNFTFx(c codec.Manager, signers [][]*crypto.PrivateKeySECP256K1R) error {
+	kc, addrs := secp256k1fx.SignerAddrs(signers)
+	return t.SignNFTFxWithSigner(c, kc, addrs)
+}
+
+// SignNFTFxWithSigner adds an nftfx credential for each element of [signers],
+// signed by [signer] with the keys that control the addresses of the element
+func (t *Tx) SignNFTFxWithSigner(c codec.Manager, signer secp256k1fx.Signer, signers [][]ids.ShortID) error {
+	return t.signWithSigner(c, signer, signers, func(cred *secp256k1fx.Credential) verify.Verifiable {
+		return &nftfx.Credential{Credential: *cred}
+	})
+}
+
+// signWithSigner adds the credentials of [signers], which are wrapped into the
+// credential type of their fx by [wrap]
+func (t *Tx) signWithSigner(
+	c codec.Manager,
+	signer secp256k1fx.Signer,
+	signers [][]ids.ShortID,
+	wrap func(*secp256k1fx.Credential) verify.Verifiable,
+) error {
 	unsignedBytes, err := c.Marshal(codecVersion, &t.UnsignedTx)
 	if err != nil {
 		return fmt.Errorf("problem creating transaction: %w", err)
 	}
 
-	hash := hashing.ComputeHash256(unsignedBytes)
-	for _, keys := range signers {
-		cred := &nftfx.Credential{Credential: secp256k1fx.Credential{
-			Sigs: make([][crypto.SECP256K1RSigLen]byte, len(keys)),
-		}}
-		for i, key := range keys {
-			sig, err := key.SignHash(hash)
-			if err != nil {
-				return fmt.Errorf("problem creating transaction: %w", err)
-			}
-			copy(cred.Sigs[i][:], sig)
+	for _, addrs := range signers {
+		cred, err := secp256k1fx.Sign(signer, unsignedBytes, addrs)
+		if err != nil {
+			return fmt.Errorf("problem creating transaction: %w", err)
 		}
-		t.Creds = append(t.Creds, &FxCredential{Verifiable: cred})
+		t.Creds = append(t.Creds, &FxCredential{Verifiable: wrap(cred)})
 	}
 
 	signedBytes, err := c.Marshal(codecVersion, t)
@@ -27,7 +27,6 @@ import (
 	"github.com/flare-foundation/flare/snow/consensus/snowstorm"
 	"github.com/flare-foundation/flare/snow/engine/avalanche/vertex"
 	"github.com/flare-foundation/flare/snow/engine/common"
-	"github.com/flare-foundation/flare/utils/crypto"
 	"github.com/flare-foundation/flare/utils/timer"
 	"github.com/flare-foundation/flare/utils/timer/mockable"
 	"github.com/flare-foundation/flare/version"
@@ -365,6 +364,9 @@ func (vm *VM) GetTx(txID ids.ID) (snowstorm.Tx, error) {
 // either accepted or rejected with the appropriate status. This function will
 // go out of scope when the transaction is removed from memory.
 func (vm *VM) IssueTx(b []byte) (ids.ID, error) {
+	if vm.ctx.ReadOnly {
+		return ids.ID{}, snow.ErrReadOnly
+	}
 	if !vm.bootstrapped {
 		return ids.ID{}, errBootstrapping
 	}
@@ -661,9 +663,26 @@ func (vm *VM) verifyOperation(tx UnsignedTx, op *Operation, cred verify.Verifiab
 	return fx.VerifyOperation(tx, op.Op, cred, utxos)
 }
 
+// signer returns the signer of the transactions issued by [username]: the
+// remote signer if there is one, or the keys of the user otherwise. The user
+// must authenticate in both cases.
+func (vm *VM) signer(username, password string) (keystore.Signer, error) {
+	if vm.RemoteSigner == nil {
+		return keystore.NewKeystoreSigner(vm.ctx.Keystore, username, password), nil
+	}
+	db, err := vm.ctx.Keystore.GetDatabase(username, password)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Close(); err != nil {
+		return nil, err
+	}
+	return keystore.NewChainSigner(vm.RemoteSigner, vm.ctx.ChainID), nil
+}
+
 // LoadUser returns:
 // 1) The UTXOs that reference one or more addresses controlled by the given user
-// 2) A keychain that contains this user's keys
+// 2) A keychain that signs with this user's keys
 // If [addrsToUse] has positive length, returns UTXOs that reference one or more
 // addresses controlled by the given user that are also in [addrsToUse].
 func (vm *VM) LoadUser(
@@ -672,45 +691,40 @@ func (vm *VM) LoadUser(
 	addrsToUse ids.ShortSet,
 ) (
 	[]*avax.UTXO,
-	*secp256k1fx.Keychain,
+	*secp256k1fx.SignerKeychain,
 	error,
 ) {
-	user, err := keystore.NewUserFromKeystore(vm.ctx.Keystore, username, password)
+	signer, err := vm.signer(username, password)
 	if err != nil {
 		return nil, nil, err
 	}
-	// Drop any potential error closing the database to report the original
-	// error
-	defer user.Close()
-
-	kc, err := keystore.GetKeychain(user, addrsToUse)
+	kc, err := keystore.GetSignerKeychain(signer, addrsToUse)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	utxos, err := avax.GetAllUTXOs(vm.state, kc.Addresses())
+	utxos, err := avax.GetAllUTXOs(vm.state, kc.Addrs)
 	if err != nil {
 		return nil, nil, fmt.Errorf("problem retrieving user's UTXOs: %w", err)
 	}
-
-	return utxos, kc, user.Close()
+	return utxos, kc, nil
 }
 
 func (vm *VM) Spend(
 	utxos []*avax.UTXO,
-	kc *secp256k1fx.Keychain,
+	kc *secp256k1fx.SignerKeychain,
 	amounts map[ids.ID]uint64,
 ) (
 	map[ids.ID]uint64,
 	[]*avax.TransferableInput,
-	[][]*crypto.PrivateKeySECP256K1R,
+	[][]ids.ShortID,
 	error,
 ) {
 	amountsSpent := make(map[ids.ID]uint64, len(amounts))
 	time := vm.clock.Unix()
 
 	ins := []*avax.TransferableInput{}
-	keys := [][]*crypto.PrivateKeySECP256K1R{}
+	keys := [][]ids.ShortID{}
 	for _, utxo := range utxos {
 		assetID := utxo.AssetID()
 		amount := amounts[assetID]
@@ -764,19 +778,19 @@ func (vm *VM) Spend(
 
 func (vm *VM) SpendNFT(
 	utxos []*avax.UTXO,
-	kc *secp256k1fx.Keychain,
+	kc *secp256k1fx.SignerKeychain,
 	assetID ids.ID,
 	groupID uint32,
 	to ids.ShortID,
 ) (
 	[]*Operation,
-	[][]*crypto.PrivateKeySECP256K1R,
+	[][]ids.ShortID,
 	error,
 ) {
 	time := vm.clock.Unix()
 
 	ops := []*Operation{}
-	keys := [][]*crypto.PrivateKeySECP256K1R{}
+	keys := [][]ids.ShortID{}
 
 	for _, utxo := range utxos {
 		// makes sure that the variable isn't overwritten with the next iteration
@@ -838,18 +852,18 @@ func (vm *VM) SpendNFT(
 
 func (vm *VM) SpendAll(
 	utxos []*avax.UTXO,
-	kc *secp256k1fx.Keychain,
+	kc *secp256k1fx.SignerKeychain,
 ) (
 	map[ids.ID]uint64,
 	[]*avax.TransferableInput,
-	[][]*crypto.PrivateKeySECP256K1R,
+	[][]ids.ShortID,
 	error,
 ) {
 	amountsSpent := make(map[ids.ID]uint64)
 	time := vm.clock.Unix()
 
 	ins := []*avax.TransferableInput{}
-	keys := [][]*crypto.PrivateKeySECP256K1R{}
+	keys := [][]ids.ShortID{}
 	for _, utxo := range utxos {
 		assetID := utxo.AssetID()
 		amountSpent := amountsSpent[assetID]
@@ -887,18 +901,18 @@ func (vm *VM) SpendAll(
 
 func (vm *VM) Mint(
 	utxos []*avax.UTXO,
-	kc *secp256k1fx.Keychain,
+	kc *secp256k1fx.SignerKeychain,
 	amounts map[ids.ID]uint64,
 	to ids.ShortID,
 ) (
 	[]*Operation,
-	[][]*crypto.PrivateKeySECP256K1R,
+	[][]ids.ShortID,
 	error,
 ) {
 	time := vm.clock.Unix()
 
 	ops := []*Operation{}
-	keys := [][]*crypto.PrivateKeySECP256K1R{}
+	keys := [][]ids.ShortID{}
 
 	for _, utxo := range utxos {
 		// makes sure that the variable isn't overwritten with the next iteration
@@ -960,19 +974,19 @@ func (vm *VM) Mint(
 
 func (vm *VM) MintNFT(
 	utxos []*avax.UTXO,
-	kc *secp256k1fx.Keychain,
+	kc *secp256k1fx.SignerKeychain,
 	assetID ids.ID,
 	payload []byte,
 	to ids.ShortID,
 ) (
 	[]*Operation,
-	[][]*crypto.PrivateKeySECP256K1R,
+	[][]ids.ShortID,
 	error,
 ) {
 	time := vm.clock.Unix()
 
 	ops := []*Operation{}
-	keys := [][]*crypto.PrivateKeySECP256K1R{}
+	keys := [][]ids.ShortID{}
 
 	for _, utxo := range utxos {
 		// makes sure that the variable isn't overwritten with the next iteration
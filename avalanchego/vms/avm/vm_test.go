@@ -712,6 +712,27 @@ func TestIssueTx(t *testing.T) {
 	}
 }
 
+// Test that a read-only VM rejects the transactions issued to it
+func TestIssueTxReadOnly(t *testing.T) {
+	genesisBytes, _, vm, _ := GenesisVM(t)
+	ctx := vm.ctx
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		ctx.Lock.Unlock()
+	}()
+	ctx.ReadOnly = true
+
+	newTx := NewTx(t, genesisBytes, vm)
+	if _, err := vm.IssueTx(newTx.Bytes()); err != snow.ErrReadOnly {
+		t.Fatalf("expected %s but got %v", snow.ErrReadOnly, err)
+	}
+	if txs := vm.PendingTxs(); len(txs) != 0 {
+		t.Fatalf("Should have returned %d tx(s)", 0)
+	}
+}
+
 // Test issuing a transaction that consumes a currently pending UTXO. The
 // transaction should be issued successfully.
 func TestIssueDependentTx(t *testing.T) {
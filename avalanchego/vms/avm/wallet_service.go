@@ -143,10 +143,10 @@ func (w *WalletService) SendMultiple(r *http.Request, args *SendMultipleArgs, re
 	}
 
 	// Parse the change address.
-	if len(kc.Keys) == 0 {
+	if len(kc.AddrList) == 0 {
 		return errNoKeys
 	}
-	changeAddr, err := w.vm.selectChangeAddr(kc.Keys[0].PublicKey().Address(), args.ChangeAddr)
+	changeAddr, err := w.vm.selectChangeAddr(kc.AddrList[0], args.ChangeAddr)
 	if err != nil {
 		return err
 	}
@@ -244,7 +244,7 @@ func (w *WalletService) SendMultiple(r *http.Request, args *SendMultipleArgs, re
 		Ins:          ins,
 		Memo:         memoBytes,
 	}}}
-	if err := tx.SignSECP256K1Fx(w.vm.codec, keys); err != nil {
+	if err := tx.SignSECP256K1FxWithSigner(w.vm.codec, kc, keys); err != nil {
 		return err
 	}
 
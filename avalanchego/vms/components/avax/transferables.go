@@ -12,7 +12,6 @@ import (
 	"github.com/flare-foundation/flare/ids"
 	"github.com/flare-foundation/flare/snow"
 	"github.com/flare-foundation/flare/utils"
-	"github.com/flare-foundation/flare/utils/crypto"
 	"github.com/flare-foundation/flare/vms/components/verify"
 )
 
@@ -174,7 +173,7 @@ func IsSortedAndUniqueTransferableInputs(ins []*TransferableInput) bool {
 
 type innerSortTransferableInputsWithSigners struct {
 	ins     []*TransferableInput
-	signers [][]*crypto.PrivateKeySECP256K1R
+	signers [][]ids.ShortID
 }
 
 func (ins *innerSortTransferableInputsWithSigners) Less(i, j int) bool {
@@ -197,14 +196,14 @@ func (ins *innerSortTransferableInputsWithSigners) Swap(i, j int) {
 }
 
 // SortTransferableInputsWithSigners sorts the inputs and signers based on the
-// input's utxo ID
-func SortTransferableInputsWithSigners(ins []*TransferableInput, signers [][]*crypto.PrivateKeySECP256K1R) {
+// input's utxo ID. The signers of an input are the addresses that sign it.
+func SortTransferableInputsWithSigners(ins []*TransferableInput, signers [][]ids.ShortID) {
 	sort.Sort(&innerSortTransferableInputsWithSigners{ins: ins, signers: signers})
 }
 
 // IsSortedAndUniqueTransferableInputsWithSigners returns true if the inputs are
 // sorted and unique
-func IsSortedAndUniqueTransferableInputsWithSigners(ins []*TransferableInput, signers [][]*crypto.PrivateKeySECP256K1R) bool {
+func IsSortedAndUniqueTransferableInputsWithSigners(ins []*TransferableInput, signers [][]ids.ShortID) bool {
 	return utils.IsSortedAndUnique(&innerSortTransferableInputsWithSigners{ins: ins, signers: signers})
 }
 
@@ -0,0 +1,113 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package gsigner
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/flare-foundation/flare/api/proto/gsignerproto"
+)
+
+var (
+	errPartialTLS  = errors.New("the TLS certificate, key and CA files of a remote signer must be set together")
+	errNotLoopback = errors.New("a remote signer must have a loopback address unless the TLS certificate, key and CA files are set")
+	errNoCAs       = errors.New("the CA file doesn't contain any certificates")
+)
+
+// Config of the connection to a remote signer
+type Config struct {
+	// Address of the remote signer, e.g. 127.0.0.1:9670. If empty, no remote
+	// signer is used.
+	Address string `json:"address"`
+
+	// The certificate and key the node authenticates to the signer with, and
+	// the CA certificates that the signer's certificate must be signed by.
+	// Either all or none of them are set. If none are set, the connection isn't
+	// encrypted, so [Address] must be a loopback address.
+	CertFile string `json:"certFile"`
+	KeyFile  string `json:"keyFile"`
+	CAFile   string `json:"caFile"`
+
+	// Timeout of a call to the signer
+	Timeout time.Duration `json:"timeout"`
+}
+
+// Enabled returns true if a remote signer is used
+func (c Config) Enabled() bool {
+	return c.Address != ""
+}
+
+// Dial returns a client of the remote signer of [config], and the connection
+// to close once the client is no longer used. The connection is established
+// lazily, so Dial doesn't fail if the signer isn't running yet.
+func Dial(config Config) (*Client, io.Closer, error) {
+	creds, err := clientCredentials(config)
+	if err != nil {
+		return nil, nil, err
+	}
+	conn, err := grpc.Dial(config.Address, creds)
+	if err != nil {
+		return nil, nil, err
+	}
+	return NewClient(gsignerproto.NewSignerClient(conn), config.Timeout), conn, nil
+}
+
+// clientCredentials returns the option that authenticates the node to the
+// signer of [config] and the signer to the node with mutual TLS
+func clientCredentials(config Config) (grpc.DialOption, error) {
+	if config.CertFile == "" && config.KeyFile == "" && config.CAFile == "" {
+		loopback, err := IsLoopback(config.Address)
+		if err != nil {
+			return nil, err
+		}
+		if !loopback {
+			return nil, errNotLoopback
+		}
+		return grpc.WithInsecure(), nil
+	}
+	if config.CertFile == "" || config.KeyFile == "" || config.CAFile == "" {
+		return nil, errPartialTLS
+	}
+
+	cert, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't load TLS certificate: %w", err)
+	}
+	caBytes, err := ioutil.ReadFile(config.CAFile)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read CA file: %w", err)
+	}
+	rootCAs := x509.NewCertPool()
+	if !rootCAs.AppendCertsFromPEM(caBytes) {
+		return nil, errNoCAs
+	}
+	return grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      rootCAs,
+		MinVersion:   tls.VersionTLS12,
+	})), nil
+}
+
+// IsLoopback returns true if the host of [address] is a loopback address
+func IsLoopback(address string) (bool, error) {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return false, fmt.Errorf("couldn't parse address %q: %w", address, err)
+	}
+	if host == "localhost" {
+		return true, nil
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback(), nil
+}
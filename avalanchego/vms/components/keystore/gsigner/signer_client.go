@@ -0,0 +1,67 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package gsigner
+
+import (
+	"context"
+	"time"
+
+	"github.com/flare-foundation/flare/api/proto/gsignerproto"
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/utils/crypto"
+	"github.com/flare-foundation/flare/vms/components/keystore"
+)
+
+var _ keystore.ChainSigner = &Client{}
+
+// Client is a keystore.ChainSigner that talks over RPC.
+type Client struct {
+	client  gsignerproto.SignerClient
+	timeout time.Duration
+	factory crypto.FactorySECP256K1R
+}
+
+// NewClient returns a signer connected to a remote signer. Calls fail if the
+// remote signer doesn't reply within [timeout].
+func NewClient(client gsignerproto.SignerClient, timeout time.Duration) *Client {
+	return &Client{
+		client:  client,
+		timeout: timeout,
+	}
+}
+
+func (c *Client) PublicKeys() ([]*crypto.PublicKeySECP256K1R, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	resp, err := c.client.PublicKeys(ctx, &gsignerproto.PublicKeysRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	pks := make([]*crypto.PublicKeySECP256K1R, len(resp.PublicKeys))
+	for i, pkBytes := range resp.PublicKeys {
+		pk, err := c.factory.ToPublicKey(pkBytes)
+		if err != nil {
+			return nil, err
+		}
+		pks[i] = pk.(*crypto.PublicKeySECP256K1R)
+	}
+	return pks, nil
+}
+
+func (c *Client) SignChainTx(chainID ids.ID, address ids.ShortID, unsignedTx []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	resp, err := c.client.SignTx(ctx, &gsignerproto.SignTxRequest{
+		ChainId:    chainID[:],
+		Address:    address[:],
+		UnsignedTx: unsignedTx,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Signature, nil
+}
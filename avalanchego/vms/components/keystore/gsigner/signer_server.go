@@ -0,0 +1,55 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package gsigner
+
+import (
+	"context"
+
+	"github.com/flare-foundation/flare/api/proto/gsignerproto"
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/vms/components/keystore"
+)
+
+var _ gsignerproto.SignerServer = &Server{}
+
+// Server is a keystore.ChainSigner that is managed over RPC.
+type Server struct {
+	gsignerproto.UnimplementedSignerServer
+	signer keystore.ChainSigner
+}
+
+// NewServer returns a signer that is managed remotely
+func NewServer(signer keystore.ChainSigner) *Server {
+	return &Server{signer: signer}
+}
+
+func (s *Server) PublicKeys(context.Context, *gsignerproto.PublicKeysRequest) (*gsignerproto.PublicKeysResponse, error) {
+	pks, err := s.signer.PublicKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	pkBytes := make([][]byte, len(pks))
+	for i, pk := range pks {
+		pkBytes[i] = pk.Bytes()
+	}
+	return &gsignerproto.PublicKeysResponse{PublicKeys: pkBytes}, nil
+}
+
+func (s *Server) SignTx(_ context.Context, req *gsignerproto.SignTxRequest) (*gsignerproto.SignTxResponse, error) {
+	chainID, err := ids.ToID(req.ChainId)
+	if err != nil {
+		return nil, err
+	}
+	address, err := ids.ToShortID(req.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := s.signer.SignChainTx(chainID, address, req.UnsignedTx)
+	if err != nil {
+		return nil, err
+	}
+	return &gsignerproto.SignTxResponse{Signature: signature}, nil
+}
@@ -0,0 +1,131 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package gsigner
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/flare-foundation/flare/api/proto/gsignerproto"
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/utils/crypto"
+	"github.com/flare-foundation/flare/vms/components/keystore"
+	"github.com/flare-foundation/flare/vms/secp256k1fx"
+)
+
+const bufSize = 1024 * 1024
+
+func setupSigner(t *testing.T, signer keystore.ChainSigner) (keystore.ChainSigner, func()) {
+	listener := bufconn.Listen(bufSize)
+	server := grpc.NewServer()
+	gsignerproto.RegisterSignerServer(server, NewServer(signer))
+	go func() {
+		if err := server.Serve(listener); err != nil {
+			t.Logf("Server exited with error: %v", err)
+		}
+	}()
+
+	dialer := grpc.WithContextDialer(
+		func(context.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	)
+
+	conn, err := grpc.DialContext(context.Background(), "", dialer, grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("Failed to dial: %s", err)
+	}
+
+	close := func() {
+		server.Stop()
+		_ = conn.Close()
+		_ = listener.Close()
+	}
+	return NewClient(gsignerproto.NewSignerClient(conn), 10*time.Second), close
+}
+
+func TestRemoteSigner(t *testing.T) {
+	assert := assert.New(t)
+
+	keychain := secp256k1fx.NewKeychain()
+	sk, err := keychain.New()
+	assert.NoError(err)
+	address := sk.PublicKey().Address()
+
+	signer, close := setupSigner(t, keystore.NewAnyChainSigner(keystore.NewKeychainSigner(keychain)))
+	defer close()
+
+	pks, err := signer.PublicKeys()
+	assert.NoError(err)
+	assert.Len(pks, 1)
+	assert.Equal(sk.PublicKey().Bytes(), pks[0].Bytes())
+
+	unsignedTx := []byte("unsigned tx")
+	sig, err := signer.SignChainTx(ids.GenerateTestID(), address, unsignedTx)
+	assert.NoError(err)
+
+	factory := crypto.FactorySECP256K1R{}
+	pk, err := factory.RecoverPublicKey(unsignedTx, sig)
+	assert.NoError(err)
+	assert.Equal(address, pk.Address())
+
+	_, err = signer.SignChainTx(ids.GenerateTestID(), ids.GenerateTestShortID(), unsignedTx)
+	assert.Error(err)
+}
+
+// chainRecorder records the chains whose transactions are signed
+type chainRecorder struct {
+	keystore.ChainSigner
+	chainIDs []ids.ID
+}
+
+func (r *chainRecorder) SignChainTx(chainID ids.ID, address ids.ShortID, unsignedTx []byte) ([]byte, error) {
+	r.chainIDs = append(r.chainIDs, chainID)
+	return r.ChainSigner.SignChainTx(chainID, address, unsignedTx)
+}
+
+func TestRemoteSignerChainID(t *testing.T) {
+	assert := assert.New(t)
+
+	keychain := secp256k1fx.NewKeychain()
+	sk, err := keychain.New()
+	assert.NoError(err)
+	recorder := &chainRecorder{ChainSigner: keystore.NewAnyChainSigner(keystore.NewKeychainSigner(keychain))}
+
+	client, close := setupSigner(t, recorder)
+	defer close()
+
+	// The chain ID is passed to the remote signer
+	chainID := ids.GenerateTestID()
+	signer := keystore.NewChainSigner(client, chainID)
+	_, err = signer.SignTx(sk.PublicKey().Address(), []byte("unsigned tx"))
+	assert.NoError(err)
+	assert.Equal([]ids.ID{chainID}, recorder.chainIDs)
+}
+
+func TestClientCredentials(t *testing.T) {
+	tests := []struct {
+		config Config
+		err    error
+	}{
+		{config: Config{Address: "127.0.0.1:9670"}},
+		{config: Config{Address: "localhost:9670"}},
+		{config: Config{Address: "10.0.0.1:9670"}, err: errNotLoopback},
+		{config: Config{Address: "signer.example.com:9670"}, err: errNotLoopback},
+		{config: Config{Address: "10.0.0.1:9670", CertFile: "cert.pem"}, err: errPartialTLS},
+		{config: Config{Address: "10.0.0.1:9670", CertFile: "cert.pem", KeyFile: "key.pem"}, err: errPartialTLS},
+	}
+	for _, test := range tests {
+		t.Run(test.config.Address, func(t *testing.T) {
+			_, err := clientCredentials(test.config)
+			assert.ErrorIs(t, err, test.err)
+		})
+	}
+}
@@ -0,0 +1,213 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package keystore
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/flare-foundation/flare/api/keystore"
+	"github.com/flare-foundation/flare/database"
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/utils/crypto"
+	"github.com/flare-foundation/flare/vms/secp256k1fx"
+)
+
+var (
+	errUnknownAddress = errors.New("no key controls the address")
+
+	_ Signer      = &userSigner{}
+	_ Signer      = &keystoreSigner{}
+	_ Signer      = &keychainSigner{}
+	_ Signer      = &chainSigner{}
+	_ ChainSigner = &anyChainSigner{}
+)
+
+// Signer signs transactions with secp256k1 keys without exposing them. This
+// allows the keys to be held by a separate process.
+type Signer interface {
+	secp256k1fx.Signer
+
+	// PublicKeys returns the public keys of the keys of the signer
+	PublicKeys() ([]*crypto.PublicKeySECP256K1R, error)
+}
+
+// ChainSigner signs the transactions of every chain. It's implemented by the
+// signers that decide what they sign by the chain a transaction is issued on,
+// such as remote signers.
+type ChainSigner interface {
+	// PublicKeys returns the public keys of the keys of the signer
+	PublicKeys() ([]*crypto.PublicKeySECP256K1R, error)
+
+	// SignChainTx returns the recoverable signature of the hash of
+	// [unsignedTx], which is issued on [chainID], by the key that controls
+	// [address]
+	SignChainTx(chainID ids.ID, address ids.ShortID, unsignedTx []byte) ([]byte, error)
+}
+
+type userSigner struct {
+	user User
+}
+
+// NewUserSigner returns a signer that signs with the keys of [user]
+func NewUserSigner(user User) Signer {
+	return &userSigner{user: user}
+}
+
+func (s *userSigner) PublicKeys() ([]*crypto.PublicKeySECP256K1R, error) {
+	addresses, err := s.user.GetAddresses()
+	if err != nil {
+		return nil, err
+	}
+	pks := make([]*crypto.PublicKeySECP256K1R, len(addresses))
+	for i, address := range addresses {
+		sk, err := s.user.GetKey(address)
+		if err != nil {
+			return nil, err
+		}
+		pks[i] = sk.PublicKey().(*crypto.PublicKeySECP256K1R)
+	}
+	return pks, nil
+}
+
+func (s *userSigner) SignTx(address ids.ShortID, unsignedTx []byte) ([]byte, error) {
+	sk, err := s.user.GetKey(address)
+	if err == database.ErrNotFound {
+		return nil, fmt.Errorf("%w %s", errUnknownAddress, address)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return sk.Sign(unsignedTx)
+}
+
+type keystoreSigner struct {
+	keystore           keystore.BlockchainKeystore
+	username, password string
+}
+
+// NewKeystoreSigner returns a signer that signs with the keys of a user of
+// [ks]. The user is opened for every call, so the signer can be used after the
+// calls that load the user have returned.
+func NewKeystoreSigner(ks keystore.BlockchainKeystore, username, password string) Signer {
+	return &keystoreSigner{
+		keystore: ks,
+		username: username,
+		password: password,
+	}
+}
+
+func (s *keystoreSigner) PublicKeys() ([]*crypto.PublicKeySECP256K1R, error) {
+	user, err := NewUserFromKeystore(s.keystore, s.username, s.password)
+	if err != nil {
+		return nil, err
+	}
+	// Drop any potential error closing the database to report the original
+	// error
+	defer user.Close()
+
+	pks, err := NewUserSigner(user).PublicKeys()
+	if err != nil {
+		return nil, err
+	}
+	return pks, user.Close()
+}
+
+func (s *keystoreSigner) SignTx(address ids.ShortID, unsignedTx []byte) ([]byte, error) {
+	user, err := NewUserFromKeystore(s.keystore, s.username, s.password)
+	if err != nil {
+		return nil, err
+	}
+	// Drop any potential error closing the database to report the original
+	// error
+	defer user.Close()
+
+	signature, err := NewUserSigner(user).SignTx(address, unsignedTx)
+	if err != nil {
+		return nil, err
+	}
+	return signature, user.Close()
+}
+
+type keychainSigner struct {
+	keychain *secp256k1fx.Keychain
+}
+
+// NewKeychainSigner returns a signer that signs with the keys in [keychain]
+func NewKeychainSigner(keychain *secp256k1fx.Keychain) Signer {
+	return &keychainSigner{keychain: keychain}
+}
+
+func (s *keychainSigner) PublicKeys() ([]*crypto.PublicKeySECP256K1R, error) {
+	pks := make([]*crypto.PublicKeySECP256K1R, len(s.keychain.Keys))
+	for i, sk := range s.keychain.Keys {
+		pks[i] = sk.PublicKey().(*crypto.PublicKeySECP256K1R)
+	}
+	return pks, nil
+}
+
+func (s *keychainSigner) SignTx(address ids.ShortID, unsignedTx []byte) ([]byte, error) {
+	sk, exists := s.keychain.Get(address)
+	if !exists {
+		return nil, fmt.Errorf("%w %s", errUnknownAddress, address)
+	}
+	return sk.Sign(unsignedTx)
+}
+
+type chainSigner struct {
+	signer  ChainSigner
+	chainID ids.ID
+}
+
+// NewChainSigner returns a signer that signs the transactions of [chainID]
+// with [signer]
+func NewChainSigner(signer ChainSigner, chainID ids.ID) Signer {
+	return &chainSigner{
+		signer:  signer,
+		chainID: chainID,
+	}
+}
+
+func (s *chainSigner) PublicKeys() ([]*crypto.PublicKeySECP256K1R, error) {
+	return s.signer.PublicKeys()
+}
+
+func (s *chainSigner) SignTx(address ids.ShortID, unsignedTx []byte) ([]byte, error) {
+	return s.signer.SignChainTx(s.chainID, address, unsignedTx)
+}
+
+type anyChainSigner struct {
+	signer Signer
+}
+
+// NewAnyChainSigner returns a signer that signs the transactions of every
+// chain with [signer]
+func NewAnyChainSigner(signer Signer) ChainSigner {
+	return &anyChainSigner{signer: signer}
+}
+
+func (s *anyChainSigner) PublicKeys() ([]*crypto.PublicKeySECP256K1R, error) {
+	return s.signer.PublicKeys()
+}
+
+func (s *anyChainSigner) SignChainTx(_ ids.ID, address ids.ShortID, unsignedTx []byte) ([]byte, error) {
+	return s.signer.SignTx(address, unsignedTx)
+}
+
+// GetSignerKeychain returns a keychain that spends outputs with the addresses
+// of [signer], and knows their public keys. If [addresses] is non-empty, only
+// the addresses of [signer] in [addresses] are used.
+func GetSignerKeychain(signer Signer, addresses ids.ShortSet) (*secp256k1fx.SignerKeychain, error) {
+	pks, err := signer.PublicKeys()
+	if err != nil {
+		return nil, err
+	}
+	kc := secp256k1fx.NewSignerKeychain(signer)
+	for _, pk := range pks {
+		if addresses.Len() == 0 || addresses.Contains(pk.Address()) {
+			kc.AddPublicKeys(pk)
+		}
+	}
+	return kc, nil
+}
@@ -0,0 +1,119 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package keystore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/flare-foundation/flare/api/keystore"
+	"github.com/flare-foundation/flare/database/encdb"
+	"github.com/flare-foundation/flare/database/manager"
+	"github.com/flare-foundation/flare/database/memdb"
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/utils/crypto"
+	"github.com/flare-foundation/flare/utils/logging"
+	"github.com/flare-foundation/flare/version"
+	"github.com/flare-foundation/flare/vms/secp256k1fx"
+)
+
+func testSigner(t *testing.T, signer Signer, sk *crypto.PrivateKeySECP256K1R) {
+	assert := assert.New(t)
+
+	address := sk.PublicKey().Address()
+	pks, err := signer.PublicKeys()
+	assert.NoError(err)
+	if assert.Len(pks, 1) {
+		assert.Equal(sk.PublicKey().Bytes(), pks[0].Bytes())
+	}
+
+	unsignedTx := []byte("transaction")
+	sig, err := signer.SignTx(address, unsignedTx)
+	assert.NoError(err)
+
+	factory := crypto.FactorySECP256K1R{}
+	pk, err := factory.RecoverPublicKey(unsignedTx, sig)
+	assert.NoError(err)
+	assert.Equal(address, pk.Address())
+
+	_, err = signer.SignTx(ids.GenerateTestShortID(), unsignedTx)
+	assert.ErrorIs(err, errUnknownAddress)
+}
+
+func TestUserSigner(t *testing.T) {
+	db, err := encdb.New([]byte(testPassword), make([]byte, encdb.SaltLen), memdb.New())
+	assert.NoError(t, err)
+
+	u := NewUserFromDB(db)
+	sk, err := NewKey(u)
+	assert.NoError(t, err)
+
+	testSigner(t, NewUserSigner(u), sk)
+}
+
+func TestKeystoreSigner(t *testing.T) {
+	assert := assert.New(t)
+
+	ks := keystore.New(logging.NoLog{}, manager.NewMemDB(version.DefaultVersion1_0_0))
+	assert.NoError(ks.CreateUser("bob", testPassword))
+	bcKeystore := ks.NewBlockchainKeyStore(ids.GenerateTestID())
+
+	u, err := NewUserFromKeystore(bcKeystore, "bob", testPassword)
+	assert.NoError(err)
+	sk, err := NewKey(u)
+	assert.NoError(err)
+	// The signer opens the user itself
+	assert.NoError(u.Close())
+
+	testSigner(t, NewKeystoreSigner(bcKeystore, "bob", testPassword), sk)
+
+	_, err = NewKeystoreSigner(bcKeystore, "bob", "wrong password").PublicKeys()
+	assert.Error(err)
+}
+
+func TestKeychainSigner(t *testing.T) {
+	keychain := secp256k1fx.NewKeychain()
+	sk, err := keychain.New()
+	assert.NoError(t, err)
+
+	testSigner(t, NewKeychainSigner(keychain), sk)
+}
+
+func TestChainSigner(t *testing.T) {
+	keychain := secp256k1fx.NewKeychain()
+	sk, err := keychain.New()
+	assert.NoError(t, err)
+
+	testSigner(t, NewChainSigner(NewAnyChainSigner(NewKeychainSigner(keychain)), ids.GenerateTestID()), sk)
+}
+
+func TestGetSignerKeychain(t *testing.T) {
+	assert := assert.New(t)
+
+	keychain := secp256k1fx.NewKeychain()
+	sks := make([]*crypto.PrivateKeySECP256K1R, 2)
+	for i := range sks {
+		sk, err := keychain.New()
+		assert.NoError(err)
+		sks[i] = sk
+	}
+	signer := NewKeychainSigner(keychain)
+
+	kc, err := GetSignerKeychain(signer, nil)
+	assert.NoError(err)
+	assert.Equal([]ids.ShortID{sks[0].PublicKey().Address(), sks[1].PublicKey().Address()}, kc.AddrList)
+	pk, exists := kc.PublicKey(sks[1].PublicKey().Address())
+	assert.True(exists)
+	assert.Equal(sks[1].PublicKey().Bytes(), pk.Bytes())
+
+	// Only the requested addresses of the signer are used
+	addresses := ids.ShortSet{}
+	addresses.Add(sks[1].PublicKey().Address(), ids.GenerateTestShortID())
+	kc, err = GetSignerKeychain(signer, addresses)
+	assert.NoError(err)
+	assert.Equal([]ids.ShortID{sks[1].PublicKey().Address()}, kc.AddrList)
+	_, exists = kc.PublicKey(sks[0].PublicKey().Address())
+	assert.False(exists)
+}
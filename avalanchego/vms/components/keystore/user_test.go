@@ -20,7 +20,7 @@ const testPassword = "ShaggyPassword1Zoinks!"
 func TestUserClosedDB(t *testing.T) {
 	assert := assert.New(t)
 
-	db, err := encdb.New([]byte(testPassword), memdb.New())
+	db, err := encdb.New([]byte(testPassword), make([]byte, encdb.SaltLen), memdb.New())
 	assert.NoError(err)
 
 	err = db.Close()
@@ -48,7 +48,7 @@ func TestUserClosedDB(t *testing.T) {
 func TestUser(t *testing.T) {
 	assert := assert.New(t)
 
-	db, err := encdb.New([]byte(testPassword), memdb.New())
+	db, err := encdb.New([]byte(testPassword), make([]byte, encdb.SaltLen), memdb.New())
 	assert.NoError(err)
 
 	u := NewUserFromDB(db)
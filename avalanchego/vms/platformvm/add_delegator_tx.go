@@ -13,7 +13,6 @@ import (
 	"github.com/flare-foundation/flare/ids"
 	"github.com/flare-foundation/flare/snow"
 	"github.com/flare-foundation/flare/utils/constants"
-	"github.com/flare-foundation/flare/utils/crypto"
 	"github.com/flare-foundation/flare/utils/math"
 	"github.com/flare-foundation/flare/vms/components/avax"
 	"github.com/flare-foundation/flare/vms/components/verify"
@@ -287,10 +286,10 @@ func (vm *VM) newAddDelegatorTx(
 	endTime uint64, // Unix time they stop delegating
 	nodeID ids.ShortID, // ID of the node we are delegating to
 	rewardAddress ids.ShortID, // Address to send reward to, if applicable
-	keys []*crypto.PrivateKeySECP256K1R, // Keys providing the staked tokens
+	kc *secp256k1fx.SignerKeychain, // Addresses providing the staked tokens
 	changeAddr ids.ShortID, // Address to send change to, if there is any
 ) (*Tx, error) {
-	ins, unlockedOuts, lockedOuts, signers, err := vm.stake(keys, stakeAmt, vm.AddStakerTxFee, changeAddr)
+	ins, unlockedOuts, lockedOuts, signers, err := vm.stake(kc, stakeAmt, vm.AddStakerTxFee, changeAddr)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't generate tx inputs/outputs: %w", err)
 	}
@@ -316,7 +315,7 @@ func (vm *VM) newAddDelegatorTx(
 		},
 	}
 	tx := &Tx{UnsignedTx: utx}
-	if err := tx.Sign(Codec, signers); err != nil {
+	if err := tx.SignWithSigner(Codec, kc, signers); err != nil {
 		return nil, err
 	}
 	return tx, utx.SyntacticVerify(vm.ctx)
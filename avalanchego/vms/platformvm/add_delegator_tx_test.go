@@ -45,7 +45,7 @@ func TestAddDelegatorTxSyntacticVerify(t *testing.T) {
 		uint64(defaultValidateEndTime.Unix()),
 		nodeID,
 		rewardAddress,
-		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		testKeychain(keys[0]),
 		ids.ShortEmpty, // change addr
 	)
 	if err != nil {
@@ -65,7 +65,7 @@ func TestAddDelegatorTxSyntacticVerify(t *testing.T) {
 		uint64(defaultValidateEndTime.Unix()),
 		nodeID,
 		rewardAddress,
-		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		testKeychain(keys[0]),
 		ids.ShortEmpty, // change addr
 	); err != nil {
 		t.Fatal(err)
@@ -93,14 +93,14 @@ func TestAddDelegatorTxExecute(t *testing.T) {
 	// pending validator set with the minimum staking amount
 	addMinStakeValidator := func(vm *VM) {
 		tx, err := vm.newAddValidatorTx(
-			vm.MinValidatorStake,                    // stake amount
-			newValidatorStartTime,                   // start time
-			newValidatorEndTime,                     // end time
-			newValidatorID,                          // node ID
-			rewardAddress,                           // Reward Address
-			reward.PercentDenominator,               // subnet
-			[]*crypto.PrivateKeySECP256K1R{keys[0]}, // key
-			ids.ShortEmpty,                          // change addr
+			vm.MinValidatorStake,      // stake amount
+			newValidatorStartTime,     // start time
+			newValidatorEndTime,       // end time
+			newValidatorID,            // node ID
+			rewardAddress,             // Reward Address
+			reward.PercentDenominator, // subnet
+			testKeychain(keys[0]),     // key
+			ids.ShortEmpty,            // change addr
 		)
 		if err != nil {
 			t.Fatal(err)
@@ -120,14 +120,14 @@ func TestAddDelegatorTxExecute(t *testing.T) {
 	// pending validator set with the maximum staking amount
 	addMaxStakeValidator := func(vm *VM) {
 		tx, err := vm.newAddValidatorTx(
-			vm.MaxValidatorStake,                    // stake amount
-			newValidatorStartTime,                   // start time
-			newValidatorEndTime,                     // end time
-			newValidatorID,                          // node ID
-			rewardAddress,                           // Reward Address
-			reward.PercentDenominator,               // subnet
-			[]*crypto.PrivateKeySECP256K1R{keys[0]}, // key
-			ids.ShortEmpty,                          // change addr
+			vm.MaxValidatorStake,      // stake amount
+			newValidatorStartTime,     // start time
+			newValidatorEndTime,       // end time
+			newValidatorID,            // node ID
+			rewardAddress,             // Reward Address
+			reward.PercentDenominator, // subnet
+			testKeychain(keys[0]),     // key
+			ids.ShortEmpty,            // change addr
 		)
 		if err != nil {
 			t.Fatal(err)
@@ -324,7 +324,7 @@ func TestAddDelegatorTxExecute(t *testing.T) {
 				tt.endTime,
 				tt.nodeID,
 				tt.rewardAddress,
-				tt.feeKeys,
+				testKeychain(tt.feeKeys...),
 				ids.ShortEmpty, // change addr
 			)
 			if err != nil {
@@ -371,7 +371,7 @@ func TestAddDelegatorTxOverDelegatedRegression(t *testing.T) {
 		id,
 		id,
 		reward.PercentDenominator,
-		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		testKeychain(keys[0]),
 		ids.ShortEmpty, // change addr
 	)
 	assert.NoError(err)
@@ -402,7 +402,7 @@ func TestAddDelegatorTxOverDelegatedRegression(t *testing.T) {
 		uint64(firstDelegatorEndTime.Unix()),
 		id,
 		keys[0].PublicKey().Address(),
-		[]*crypto.PrivateKeySECP256K1R{keys[0], keys[1]},
+		testKeychain(keys[0], keys[1]),
 		ids.ShortEmpty, // change addr
 	)
 	assert.NoError(err)
@@ -435,7 +435,7 @@ func TestAddDelegatorTxOverDelegatedRegression(t *testing.T) {
 		uint64(secondDelegatorEndTime.Unix()),
 		id,
 		keys[0].PublicKey().Address(),
-		[]*crypto.PrivateKeySECP256K1R{keys[0], keys[1], keys[3]},
+		testKeychain(keys[0], keys[1], keys[3]),
 		ids.ShortEmpty, // change addr
 	)
 	assert.NoError(err)
@@ -459,7 +459,7 @@ func TestAddDelegatorTxOverDelegatedRegression(t *testing.T) {
 		uint64(thirdDelegatorEndTime.Unix()),
 		id,
 		keys[0].PublicKey().Address(),
-		[]*crypto.PrivateKeySECP256K1R{keys[0], keys[1], keys[4]},
+		testKeychain(keys[0], keys[1], keys[4]),
 		ids.ShortEmpty, // change addr
 	)
 	assert.NoError(err)
@@ -537,7 +537,7 @@ func TestAddDelegatorTxHeapCorruption(t *testing.T) {
 				id,
 				id,
 				reward.PercentDenominator,
-				[]*crypto.PrivateKeySECP256K1R{keys[0], keys[1]},
+				testKeychain(keys[0], keys[1]),
 				changeAddr,
 			)
 			assert.NoError(err)
@@ -559,7 +559,7 @@ func TestAddDelegatorTxHeapCorruption(t *testing.T) {
 				uint64(delegator1EndTime.Unix()),
 				id,
 				keys[0].PublicKey().Address(),
-				[]*crypto.PrivateKeySECP256K1R{keys[0], keys[1]},
+				testKeychain(keys[0], keys[1]),
 				changeAddr,
 			)
 			assert.NoError(err)
@@ -581,7 +581,7 @@ func TestAddDelegatorTxHeapCorruption(t *testing.T) {
 				uint64(delegator2EndTime.Unix()),
 				id,
 				keys[0].PublicKey().Address(),
-				[]*crypto.PrivateKeySECP256K1R{keys[0], keys[1]},
+				testKeychain(keys[0], keys[1]),
 				changeAddr,
 			)
 			assert.NoError(err)
@@ -603,7 +603,7 @@ func TestAddDelegatorTxHeapCorruption(t *testing.T) {
 				uint64(delegator3EndTime.Unix()),
 				id,
 				keys[0].PublicKey().Address(),
-				[]*crypto.PrivateKeySECP256K1R{keys[0], keys[1]},
+				testKeychain(keys[0], keys[1]),
 				changeAddr,
 			)
 			assert.NoError(err)
@@ -625,7 +625,7 @@ func TestAddDelegatorTxHeapCorruption(t *testing.T) {
 				uint64(delegator4EndTime.Unix()),
 				id,
 				keys[0].PublicKey().Address(),
-				[]*crypto.PrivateKeySECP256K1R{keys[0], keys[1]},
+				testKeychain(keys[0], keys[1]),
 				changeAddr,
 			)
 			assert.NoError(err)
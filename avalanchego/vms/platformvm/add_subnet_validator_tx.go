@@ -12,9 +12,9 @@ import (
 	"github.com/flare-foundation/flare/ids"
 	"github.com/flare-foundation/flare/snow"
 	"github.com/flare-foundation/flare/utils/constants"
-	"github.com/flare-foundation/flare/utils/crypto"
 	"github.com/flare-foundation/flare/vms/components/avax"
 	"github.com/flare-foundation/flare/vms/components/verify"
+	"github.com/flare-foundation/flare/vms/secp256k1fx"
 )
 
 var (
@@ -251,15 +251,15 @@ func (vm *VM) newAddSubnetValidatorTx(
 	endTime uint64, // Unix time they top delegating
 	nodeID ids.ShortID, // ID of the node validating
 	subnetID ids.ID, // ID of the subnet the validator will validate
-	keys []*crypto.PrivateKeySECP256K1R, // Keys to use for adding the validator
+	kc *secp256k1fx.SignerKeychain, // Addresses to use for adding the validator
 	changeAddr ids.ShortID, // Address to send change to, if there is any
 ) (*Tx, error) {
-	ins, outs, _, signers, err := vm.stake(keys, 0, vm.TxFee, changeAddr)
+	ins, outs, _, signers, err := vm.stake(kc, 0, vm.TxFee, changeAddr)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't generate tx inputs/outputs: %w", err)
 	}
 
-	subnetAuth, subnetSigners, err := vm.authorize(vm.internalState, subnetID, keys)
+	subnetAuth, subnetSigners, err := vm.authorize(vm.internalState, subnetID, kc)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't authorize tx's subnet restrictions: %w", err)
 	}
@@ -285,7 +285,7 @@ func (vm *VM) newAddSubnetValidatorTx(
 		SubnetAuth: subnetAuth,
 	}
 	tx := &Tx{UnsignedTx: utx}
-	if err := tx.Sign(Codec, signers); err != nil {
+	if err := tx.SignWithSigner(Codec, kc, signers); err != nil {
 		return nil, err
 	}
 	return tx, utx.SyntacticVerify(vm.ctx)
@@ -8,7 +8,6 @@ import (
 	"time"
 
 	"github.com/flare-foundation/flare/ids"
-	"github.com/flare-foundation/flare/utils/crypto"
 	"github.com/flare-foundation/flare/utils/hashing"
 	"github.com/flare-foundation/flare/vms/platformvm/reward"
 	"github.com/flare-foundation/flare/vms/platformvm/status"
@@ -41,7 +40,7 @@ func TestAddSubnetValidatorTxSyntacticVerify(t *testing.T) {
 		uint64(defaultValidateEndTime.Unix()),
 		nodeID,
 		testSubnet1.ID(),
-		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+		testKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]),
 		ids.ShortEmpty, // change addr
 	)
 	if err != nil {
@@ -61,7 +60,7 @@ func TestAddSubnetValidatorTxSyntacticVerify(t *testing.T) {
 		uint64(defaultValidateEndTime.Unix()),
 		nodeID,
 		testSubnet1.ID(),
-		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+		testKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]),
 		ids.ShortEmpty, // change addr
 	)
 	if err != nil {
@@ -81,7 +80,7 @@ func TestAddSubnetValidatorTxSyntacticVerify(t *testing.T) {
 		uint64(defaultValidateEndTime.Unix()),
 		nodeID,
 		testSubnet1.ID(),
-		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+		testKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]),
 		ids.ShortEmpty, // change addr
 	)
 	if err != nil {
@@ -101,7 +100,7 @@ func TestAddSubnetValidatorTxSyntacticVerify(t *testing.T) {
 		uint64(defaultValidateEndTime.Unix())-1,
 		nodeID,
 		testSubnet1.ID(),
-		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+		testKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]),
 		ids.ShortEmpty, // change addr
 	)
 	if err != nil {
@@ -122,7 +121,7 @@ func TestAddSubnetValidatorTxSyntacticVerify(t *testing.T) {
 		uint64(defaultValidateEndTime.Unix()),
 		nodeID,
 		testSubnet1.ID(),
-		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+		testKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]),
 		ids.ShortEmpty, // change addr
 	); err != nil {
 		t.Fatal(err)
@@ -153,7 +152,7 @@ func TestAddSubnetValidatorTxExecute(t *testing.T) {
 		uint64(defaultValidateEndTime.Unix())+1,
 		nodeID,
 		testSubnet1.ID(),
-		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+		testKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]),
 		ids.ShortEmpty, // change addr
 	); err != nil {
 		t.Fatal(err)
@@ -171,7 +170,7 @@ func TestAddSubnetValidatorTxExecute(t *testing.T) {
 		uint64(defaultValidateEndTime.Unix()),
 		nodeID,
 		testSubnet1.ID(),
-		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+		testKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]),
 		ids.ShortEmpty, // change addr
 	); err != nil {
 		t.Fatal(err)
@@ -191,14 +190,14 @@ func TestAddSubnetValidatorTxExecute(t *testing.T) {
 	DSEndTime := DSStartTime.Add(5 * defaultMinStakingDuration)
 
 	addDSTx, err := vm.newAddValidatorTx(
-		vm.MinValidatorStake,                    // stake amount
-		uint64(DSStartTime.Unix()),              // start time
-		uint64(DSEndTime.Unix()),                // end time
-		pendingDSValidatorID,                    // node ID
-		nodeID,                                  // reward address
-		reward.PercentDenominator,               // shares
-		[]*crypto.PrivateKeySECP256K1R{keys[0]}, // key
-		ids.ShortEmpty,                          // change addr
+		vm.MinValidatorStake,       // stake amount
+		uint64(DSStartTime.Unix()), // start time
+		uint64(DSEndTime.Unix()),   // end time
+		pendingDSValidatorID,       // node ID
+		nodeID,                     // reward address
+		reward.PercentDenominator,  // shares
+		testKeychain(keys[0]),      // key
+		ids.ShortEmpty,             // change addr
 
 	)
 	if err != nil {
@@ -212,7 +211,7 @@ func TestAddSubnetValidatorTxExecute(t *testing.T) {
 		uint64(DSEndTime.Unix()),
 		pendingDSValidatorID,
 		testSubnet1.ID(),
-		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+		testKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]),
 		ids.ShortEmpty, // change addr
 	); err != nil {
 		t.Fatal(err)
@@ -239,7 +238,7 @@ func TestAddSubnetValidatorTxExecute(t *testing.T) {
 		uint64(DSEndTime.Unix()),
 		pendingDSValidatorID,
 		testSubnet1.ID(),
-		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+		testKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]),
 		ids.ShortEmpty, // change addr
 	); err != nil {
 		t.Fatal(err)
@@ -256,7 +255,7 @@ func TestAddSubnetValidatorTxExecute(t *testing.T) {
 		uint64(DSEndTime.Unix())+1, // stop validating subnet after stopping validating primary network
 		pendingDSValidatorID,
 		testSubnet1.ID(),
-		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+		testKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]),
 		ids.ShortEmpty, // change addr
 	); err != nil {
 		t.Fatal(err)
@@ -273,7 +272,7 @@ func TestAddSubnetValidatorTxExecute(t *testing.T) {
 		uint64(DSEndTime.Unix()),   // same end time as for primary network
 		pendingDSValidatorID,
 		testSubnet1.ID(),
-		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+		testKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]),
 		ids.ShortEmpty, // change addr
 	); err != nil {
 		t.Fatal(err)
@@ -292,7 +291,7 @@ func TestAddSubnetValidatorTxExecute(t *testing.T) {
 		uint64(newTimestamp.Add(defaultMinStakingDuration).Unix()), // end time
 		nodeID,           // node ID
 		testSubnet1.ID(), // subnet ID
-		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+		testKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]),
 		ids.ShortEmpty, // change addr
 	); err != nil {
 		t.Fatal(err)
@@ -311,7 +310,7 @@ func TestAddSubnetValidatorTxExecute(t *testing.T) {
 		uint64(defaultValidateEndTime.Unix()),   // end time
 		nodeID,                                  // node ID
 		testSubnet1.ID(),                        // subnet ID
-		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+		testKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]),
 		ids.ShortEmpty, // change addr
 	)
 	if err != nil {
@@ -334,7 +333,7 @@ func TestAddSubnetValidatorTxExecute(t *testing.T) {
 		uint64(defaultValidateEndTime.Unix()),   // end time
 		nodeID,                                  // node ID
 		testSubnet1.ID(),                        // subnet ID
-		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+		testKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]),
 		ids.ShortEmpty, // change addr
 	)
 	if err != nil {
@@ -360,7 +359,7 @@ func TestAddSubnetValidatorTxExecute(t *testing.T) {
 		uint64(defaultGenesisTime.Add(defaultMinStakingDuration).Unix())+1, // end time
 		nodeID,           // node ID
 		testSubnet1.ID(), // subnet ID
-		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1], testSubnet1ControlKeys[2]},
+		testKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[1], testSubnet1ControlKeys[2]),
 		ids.ShortEmpty, // change addr
 	)
 	if err != nil {
@@ -377,7 +376,7 @@ func TestAddSubnetValidatorTxExecute(t *testing.T) {
 		uint64(defaultGenesisTime.Add(defaultMinStakingDuration).Unix()), // end time
 		nodeID,           // node ID
 		testSubnet1.ID(), // subnet ID
-		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[2]},
+		testKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[2]),
 		ids.ShortEmpty, // change addr
 	)
 	if err != nil {
@@ -399,7 +398,7 @@ func TestAddSubnetValidatorTxExecute(t *testing.T) {
 		uint64(defaultGenesisTime.Add(defaultMinStakingDuration).Unix()), // end time
 		nodeID,           // node ID
 		testSubnet1.ID(), // subnet ID
-		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], keys[1]},
+		testKeychain(testSubnet1ControlKeys[0], keys[1]),
 		ids.ShortEmpty, // change addr
 	)
 	if err != nil {
@@ -423,7 +422,7 @@ func TestAddSubnetValidatorTxExecute(t *testing.T) {
 		uint64(defaultGenesisTime.Add(defaultMinStakingDuration).Unix())+1, // end time
 		nodeID,           // node ID
 		testSubnet1.ID(), // subnet ID
-		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+		testKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]),
 		ids.ShortEmpty, // change addr
 	)
 	if err != nil {
@@ -465,7 +464,7 @@ func TestAddSubnetValidatorMarshal(t *testing.T) {
 		uint64(defaultValidateEndTime.Unix()),
 		keys[0].PublicKey().Address(),
 		testSubnet1.ID(),
-		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+		testKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]),
 		ids.ShortEmpty, // change addr
 	)
 	if err != nil {
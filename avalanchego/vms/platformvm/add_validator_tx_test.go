@@ -9,7 +9,6 @@ import (
 	"time"
 
 	"github.com/flare-foundation/flare/ids"
-	"github.com/flare-foundation/flare/utils/crypto"
 	"github.com/flare-foundation/flare/vms/components/avax"
 	"github.com/flare-foundation/flare/vms/platformvm/reward"
 	"github.com/flare-foundation/flare/vms/platformvm/status"
@@ -47,7 +46,7 @@ func TestAddValidatorTxSyntacticVerify(t *testing.T) {
 		nodeID,
 		nodeID,
 		reward.PercentDenominator,
-		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		testKeychain(keys[0]),
 		ids.ShortEmpty, // change addr
 
 	)
@@ -69,7 +68,7 @@ func TestAddValidatorTxSyntacticVerify(t *testing.T) {
 		nodeID,
 		nodeID,
 		reward.PercentDenominator,
-		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		testKeychain(keys[0]),
 		ids.ShortEmpty, // change addr
 
 	)
@@ -101,7 +100,7 @@ func TestAddValidatorTxSyntacticVerify(t *testing.T) {
 		nodeID,
 		nodeID,
 		reward.PercentDenominator,
-		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		testKeychain(keys[0]),
 		ids.ShortEmpty, // change addr
 
 	)
@@ -127,7 +126,7 @@ func TestAddValidatorTxSyntacticVerify(t *testing.T) {
 		nodeID,
 		nodeID,
 		reward.PercentDenominator,
-		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		testKeychain(keys[0]),
 		ids.ShortEmpty, // change addr
 
 	)
@@ -149,7 +148,7 @@ func TestAddValidatorTxSyntacticVerify(t *testing.T) {
 		nodeID,
 		nodeID,
 		reward.PercentDenominator,
-		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		testKeychain(keys[0]),
 		ids.ShortEmpty, // change addr
 
 	); err != nil {
@@ -185,7 +184,7 @@ func TestAddValidatorTxExecute(t *testing.T) {
 		nodeID,
 		nodeID,
 		reward.PercentDenominator,
-		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		testKeychain(keys[0]),
 		ids.ShortEmpty, // change addr
 	); err != nil {
 		t.Fatal(err)
@@ -201,7 +200,7 @@ func TestAddValidatorTxExecute(t *testing.T) {
 		nodeID,
 		nodeID,
 		reward.PercentDenominator,
-		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		testKeychain(keys[0]),
 		ids.ShortEmpty, // change addr
 	); err != nil {
 		t.Fatal(err)
@@ -217,7 +216,7 @@ func TestAddValidatorTxExecute(t *testing.T) {
 		nodeID, // node ID
 		nodeID, // reward address
 		reward.PercentDenominator,
-		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		testKeychain(keys[0]),
 		ids.ShortEmpty, // change addr
 	); err != nil {
 		t.Fatal(err)
@@ -238,7 +237,7 @@ func TestAddValidatorTxExecute(t *testing.T) {
 		nodeID,                     // node ID
 		key2.PublicKey().Address(), // reward address
 		reward.PercentDenominator,  // shares
-		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		testKeychain(keys[0]),
 		ids.ShortEmpty, // change addr // key
 	)
 	if err != nil {
@@ -266,7 +265,7 @@ func TestAddValidatorTxExecute(t *testing.T) {
 		nodeID,
 		nodeID,
 		reward.PercentDenominator,
-		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		testKeychain(keys[0]),
 		ids.ShortEmpty, // change addr
 	); err != nil {
 		t.Fatal(err)
@@ -309,10 +309,10 @@ func TestAdvanceTimeTxUpdateStakers(t *testing.T) {
 					10, // Weight
 					uint64(staker.startTime.Unix()),
 					uint64(staker.endTime.Unix()),
-					staker.nodeID,    // validator ID
-					testSubnet1.ID(), // Subnet ID
-					[]*crypto.PrivateKeySECP256K1R{keys[0], keys[1]}, // Keys
-					ids.ShortEmpty, // reward address
+					staker.nodeID,                  // validator ID
+					testSubnet1.ID(),               // Subnet ID
+					testKeychain(keys[0], keys[1]), // Keys
+					ids.ShortEmpty,                 // reward address
 				)
 				assert.NoError(err)
 				vm.internalState.AddPendingStaker(tx)
@@ -394,8 +394,8 @@ func TestAdvanceTimeTxRemoveSubnetValidator(t *testing.T) {
 		uint64(subnetVdr1EndTime.Unix()),   // end time
 		subnetValidatorNodeID,              // Node ID
 		testSubnet1.ID(),                   // Subnet ID
-		[]*crypto.PrivateKeySECP256K1R{keys[0], keys[1]}, // Keys
-		ids.ShortEmpty, // reward address
+		testKeychain(keys[0], keys[1]),     // Keys
+		ids.ShortEmpty,                     // reward address
 	)
 	if err != nil {
 		t.Fatal(err)
@@ -418,10 +418,10 @@ func TestAdvanceTimeTxRemoveSubnetValidator(t *testing.T) {
 		1, // Weight
 		uint64(subnetVdr1EndTime.Add(time.Second).Unix()),                                // Start time
 		uint64(subnetVdr1EndTime.Add(time.Second).Add(defaultMinStakingDuration).Unix()), // end time
-		subnetVdr2NodeID, // Node ID
-		testSubnet1.ID(), // Subnet ID
-		[]*crypto.PrivateKeySECP256K1R{keys[0], keys[1]}, // Keys
-		ids.ShortEmpty, // reward address
+		subnetVdr2NodeID,               // Node ID
+		testSubnet1.ID(),               // Subnet ID
+		testKeychain(keys[0], keys[1]), // Keys
+		ids.ShortEmpty,                 // reward address
 	)
 	if err != nil {
 		t.Fatal(err)
@@ -494,8 +494,8 @@ func TestWhitelistedSubnet(t *testing.T) {
 				uint64(subnetVdr1EndTime.Unix()),   // end time
 				subnetValidatorNodeID,              // Node ID
 				testSubnet1.ID(),                   // Subnet ID
-				[]*crypto.PrivateKeySECP256K1R{keys[0], keys[1]}, // Keys
-				ids.ShortEmpty, // reward address
+				testKeychain(keys[0], keys[1]),     // Keys
+				ids.ShortEmpty,                     // reward address
 			)
 			if err != nil {
 				t.Fatal(err)
@@ -570,7 +570,7 @@ func TestAdvanceTimeTxDelegatorStakerWeight(t *testing.T) {
 		uint64(pendingDelegatorEndTime.Unix()),
 		nodeID,
 		keys[0].PublicKey().Address(),
-		[]*crypto.PrivateKeySECP256K1R{keys[0], keys[1], keys[4]},
+		testKeychain(keys[0], keys[1], keys[4]),
 		ids.ShortEmpty, // change addr
 	)
 	assert.NoError(t, err)
@@ -634,7 +634,7 @@ func TestAdvanceTimeTxDelegatorStakers(t *testing.T) {
 		uint64(pendingDelegatorEndTime.Unix()),
 		nodeID,
 		keys[0].PublicKey().Address(),
-		[]*crypto.PrivateKeySECP256K1R{keys[0], keys[1], keys[4]},
+		testKeychain(keys[0], keys[1], keys[4]),
 		ids.ShortEmpty, // change addr
 	)
 	assert.NoError(t, err)
@@ -725,7 +725,7 @@ func addPendingValidator(vm *VM, startTime time.Time, endTime time.Time, nodeID
 		nodeID,
 		nodeID,
 		reward.PercentDenominator,
-		keys,
+		testKeychain(keys...),
 		ids.ShortEmpty, // change addr
 	)
 	if err != nil {
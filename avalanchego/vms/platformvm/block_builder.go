@@ -10,6 +10,7 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 
+	"github.com/flare-foundation/flare/snow"
 	"github.com/flare-foundation/flare/snow/consensus/snowman"
 	"github.com/flare-foundation/flare/utils/timer"
 	"github.com/flare-foundation/flare/utils/timer/mockable"
@@ -71,6 +72,10 @@ func (m *blockBuilder) Initialize(vm *VM, registerer prometheus.Registerer) erro
 
 // AddUnverifiedTx verifies a transaction and attempts to add it to the mempool
 func (m *blockBuilder) AddUnverifiedTx(tx *Tx) error {
+	if m.vm.ctx.ReadOnly {
+		return snow.ErrReadOnly
+	}
+
 	// Initialize the transaction
 	if err := tx.Sign(Codec, nil); err != nil {
 		return err
@@ -14,10 +14,10 @@ import (
 	"github.com/flare-foundation/flare/ids"
 	"github.com/flare-foundation/flare/snow"
 	"github.com/flare-foundation/flare/utils/constants"
-	"github.com/flare-foundation/flare/utils/crypto"
 	"github.com/flare-foundation/flare/utils/units"
 	"github.com/flare-foundation/flare/vms/components/avax"
 	"github.com/flare-foundation/flare/vms/components/verify"
+	"github.com/flare-foundation/flare/vms/secp256k1fx"
 )
 
 var (
@@ -174,17 +174,17 @@ func (vm *VM) newCreateChainTx(
 	vmID ids.ID, // VM this chain runs
 	fxIDs []ids.ID, // fxs this chain supports
 	chainName string, // Name of the chain
-	keys []*crypto.PrivateKeySECP256K1R, // Keys to sign the tx
+	kc *secp256k1fx.SignerKeychain, // Addresses to sign the tx
 	changeAddr ids.ShortID, // Address to send change to, if there is any
 ) (*Tx, error) {
 	timestamp := vm.internalState.GetTimestamp()
 	createBlockchainTxFee := vm.getCreateBlockchainTxFee(timestamp)
-	ins, outs, _, signers, err := vm.stake(keys, 0, createBlockchainTxFee, changeAddr)
+	ins, outs, _, signers, err := vm.stake(kc, 0, createBlockchainTxFee, changeAddr)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't generate tx inputs/outputs: %w", err)
 	}
 
-	subnetAuth, subnetSigners, err := vm.authorize(vm.internalState, subnetID, keys)
+	subnetAuth, subnetSigners, err := vm.authorize(vm.internalState, subnetID, kc)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't authorize tx's subnet restrictions: %w", err)
 	}
@@ -209,7 +209,7 @@ func (vm *VM) newCreateChainTx(
 		SubnetAuth:  subnetAuth,
 	}
 	tx := &Tx{UnsignedTx: utx}
-	if err := tx.Sign(Codec, signers); err != nil {
+	if err := tx.SignWithSigner(Codec, kc, signers); err != nil {
 		return nil, err
 	}
 	return tx, utx.SyntacticVerify(vm.ctx)
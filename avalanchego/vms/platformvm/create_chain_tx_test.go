@@ -136,7 +136,7 @@ func TestUnsignedCreateChainTxVerify(t *testing.T) {
 			test.vmID,
 			test.fxIDs,
 			test.chainName,
-			test.keys,
+			testKeychain(test.keys...),
 			ids.ShortEmpty, // change addr
 		)
 		if err != nil {
@@ -170,7 +170,7 @@ func TestCreateChainTxInsufficientControlSigs(t *testing.T) {
 		constants.AVMID,
 		nil,
 		"chain name",
-		[]*crypto.PrivateKeySECP256K1R{keys[0], keys[1]},
+		testKeychain(keys[0], keys[1]),
 		ids.ShortEmpty, // change addr
 	)
 	if err != nil {
@@ -208,7 +208,7 @@ func TestCreateChainTxWrongControlSig(t *testing.T) {
 		constants.AVMID,
 		nil,
 		"chain name",
-		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+		testKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]),
 		ids.ShortEmpty, // change addr
 	)
 	if err != nil {
@@ -258,7 +258,7 @@ func TestCreateChainTxNoSuchSubnet(t *testing.T) {
 		constants.AVMID,
 		nil,
 		"chain name",
-		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+		testKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]),
 		ids.ShortEmpty, // change addr
 	)
 	if err != nil {
@@ -296,7 +296,7 @@ func TestCreateChainTxValid(t *testing.T) {
 		constants.AVMID,
 		nil,
 		"chain name",
-		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+		testKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]),
 		ids.ShortEmpty, // change addr
 	)
 	if err != nil {
@@ -357,10 +357,11 @@ func TestCreateChainTxAP3FeeChange(t *testing.T) {
 				vm.ctx.Lock.Unlock()
 			}()
 
-			ins, outs, _, signers, err := vm.stake(keys, 0, test.fee, ids.ShortEmpty)
+			kc := testKeychain(keys...)
+			ins, outs, _, signers, err := vm.stake(kc, 0, test.fee, ids.ShortEmpty)
 			assert.NoError(err)
 
-			subnetAuth, subnetSigners, err := vm.authorize(vm.internalState, testSubnet1.ID(), keys)
+			subnetAuth, subnetSigners, err := vm.authorize(vm.internalState, testSubnet1.ID(), kc)
 			assert.NoError(err)
 
 			signers = append(signers, subnetSigners)
@@ -378,7 +379,7 @@ func TestCreateChainTxAP3FeeChange(t *testing.T) {
 				SubnetAuth: subnetAuth,
 			}
 			tx := &Tx{UnsignedTx: utx}
-			err = tx.Sign(Codec, signers)
+			err = tx.SignWithSigner(Codec, kc, signers)
 			assert.NoError(err)
 
 			vs := newVersionedState(
@@ -10,7 +10,6 @@ import (
 	"github.com/flare-foundation/flare/chains/atomic"
 	"github.com/flare-foundation/flare/ids"
 	"github.com/flare-foundation/flare/snow"
-	"github.com/flare-foundation/flare/utils/crypto"
 	"github.com/flare-foundation/flare/vms/components/avax"
 	"github.com/flare-foundation/flare/vms/secp256k1fx"
 )
@@ -108,12 +107,12 @@ func (tx *UnsignedCreateSubnetTx) Execute(
 func (vm *VM) newCreateSubnetTx(
 	threshold uint32, // [threshold] of [ownerAddrs] needed to manage this subnet
 	ownerAddrs []ids.ShortID, // control addresses for the new subnet
-	keys []*crypto.PrivateKeySECP256K1R, // pay the fee
+	kc *secp256k1fx.SignerKeychain, // pay the fee
 	changeAddr ids.ShortID, // Address to send change to, if there is any
 ) (*Tx, error) {
 	timestamp := vm.internalState.GetTimestamp()
 	createSubnetTxFee := vm.getCreateSubnetTxFee(timestamp)
-	ins, outs, _, signers, err := vm.stake(keys, 0, createSubnetTxFee, changeAddr)
+	ins, outs, _, signers, err := vm.stake(kc, 0, createSubnetTxFee, changeAddr)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't generate tx inputs/outputs: %w", err)
 	}
@@ -135,7 +134,7 @@ func (vm *VM) newCreateSubnetTx(
 		},
 	}
 	tx := &Tx{UnsignedTx: utx}
-	if err := tx.Sign(Codec, signers); err != nil {
+	if err := tx.SignWithSigner(Codec, kc, signers); err != nil {
 		return nil, err
 	}
 
@@ -56,7 +56,8 @@ func TestCreateSubnetTxAP3FeeChange(t *testing.T) {
 				vm.ctx.Lock.Unlock()
 			}()
 
-			ins, outs, _, signers, err := vm.stake(keys, 0, test.fee, ids.ShortEmpty)
+			kc := testKeychain(keys...)
+			ins, outs, _, signers, err := vm.stake(kc, 0, test.fee, ids.ShortEmpty)
 			assert.NoError(err)
 
 			// Create the tx
@@ -70,7 +71,7 @@ func TestCreateSubnetTxAP3FeeChange(t *testing.T) {
 				Owner: &secp256k1fx.OutputOwners{},
 			}
 			tx := &Tx{UnsignedTx: utx}
-			err = tx.Sign(Codec, signers)
+			err = tx.SignWithSigner(Codec, kc, signers)
 			assert.NoError(err)
 
 			vs := newVersionedState(
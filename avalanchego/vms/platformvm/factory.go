@@ -11,6 +11,7 @@ import (
 	"github.com/flare-foundation/flare/snow"
 	"github.com/flare-foundation/flare/snow/uptime"
 	"github.com/flare-foundation/flare/snow/validators"
+	"github.com/flare-foundation/flare/vms/components/keystore"
 	"github.com/flare-foundation/flare/vms/platformvm/reward"
 )
 
@@ -78,6 +79,10 @@ type Factory struct {
 
 	// Time of the AP5 network upgrade
 	ApricotPhase5Time time.Time
+
+	// Signs the transactions issued through the keystore API instead of the
+	// keys of the user. Nil if the keys of the user are used.
+	RemoteSigner keystore.ChainSigner
 }
 
 // New returns a new instance of the Platform Chain
@@ -11,7 +11,6 @@ import (
 	"github.com/flare-foundation/flare/database"
 	"github.com/flare-foundation/flare/ids"
 	"github.com/flare-foundation/flare/snow"
-	"github.com/flare-foundation/flare/utils/crypto"
 	"github.com/flare-foundation/flare/utils/math"
 	"github.com/flare-foundation/flare/vms/components/avax"
 	"github.com/flare-foundation/flare/vms/components/verify"
@@ -200,18 +199,16 @@ func (tx *UnsignedImportTx) AtomicAccept(ctx *snow.Context, batch database.Batch
 func (vm *VM) newImportTx(
 	chainID ids.ID, // chain to import from
 	to ids.ShortID, // Address of recipient
-	keys []*crypto.PrivateKeySECP256K1R, // Keys to import the funds
+	kc *secp256k1fx.SignerKeychain, // Addresses to import the funds
 	changeAddr ids.ShortID, // Address to send change to, if there is any
 ) (*Tx, error) {
-	kc := secp256k1fx.NewKeychain(keys...)
-
-	atomicUTXOs, _, _, err := vm.GetAtomicUTXOs(chainID, kc.Addresses(), ids.ShortEmpty, ids.Empty, maxPageSize)
+	atomicUTXOs, _, _, err := vm.GetAtomicUTXOs(chainID, kc.Addrs, ids.ShortEmpty, ids.Empty, maxPageSize)
 	if err != nil {
 		return nil, fmt.Errorf("problem retrieving atomic UTXOs: %w", err)
 	}
 
 	importedInputs := []*avax.TransferableInput{}
-	signers := [][]*crypto.PrivateKeySECP256K1R{}
+	signers := [][]ids.ShortID{}
 
 	importedAmount := uint64(0)
 	now := vm.clock.Unix()
@@ -247,8 +244,8 @@ func (vm *VM) newImportTx(
 	ins := []*avax.TransferableInput{}
 	outs := []*avax.TransferableOutput{}
 	if importedAmount < vm.TxFee { // imported amount goes toward paying tx fee
-		var baseSigners [][]*crypto.PrivateKeySECP256K1R
-		ins, outs, _, baseSigners, err = vm.stake(keys, 0, vm.TxFee-importedAmount, changeAddr)
+		var baseSigners [][]ids.ShortID
+		ins, outs, _, baseSigners, err = vm.stake(kc, 0, vm.TxFee-importedAmount, changeAddr)
 		if err != nil {
 			return nil, fmt.Errorf("couldn't generate tx inputs/outputs: %w", err)
 		}
@@ -279,7 +276,7 @@ func (vm *VM) newImportTx(
 		ImportedInputs: importedInputs,
 	}
 	tx := &Tx{UnsignedTx: utx}
-	if err := tx.Sign(Codec, signers); err != nil {
+	if err := tx.SignWithSigner(Codec, kc, signers); err != nil {
 		return nil, err
 	}
 	return tx, utx.SyntacticVerify(vm.ctx)
@@ -130,7 +130,7 @@ func TestNewImportTx(t *testing.T) {
 
 			vm.ctx.SharedMemory = tt.sharedMemory
 			vm.AtomicUTXOManager = avax.NewAtomicUTXOManager(tt.sharedMemory, Codec)
-			tx, err := vm.newImportTx(tt.sourceChainID, to, tt.sourceKeys, ids.ShortEmpty)
+			tx, err := vm.newImportTx(tt.sourceChainID, to, testKeychain(tt.sourceKeys...), ids.ShortEmpty)
 			if tt.shouldErr {
 				assert.Error(err)
 				return
@@ -9,7 +9,6 @@ import (
 
 	"github.com/flare-foundation/flare/ids"
 	"github.com/flare-foundation/flare/utils/constants"
-	"github.com/flare-foundation/flare/utils/crypto"
 	"github.com/flare-foundation/flare/vms/platformvm/message"
 	"github.com/stretchr/testify/assert"
 )
@@ -21,7 +20,7 @@ func getValidTx(vm *VM, t *testing.T) *Tx {
 		constants.AVMID,
 		nil,
 		"chain name",
-		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+		testKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]),
 		ids.ShortEmpty, // change addr
 	)
 	if err != nil {
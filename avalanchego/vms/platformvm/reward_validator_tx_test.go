@@ -16,7 +16,6 @@ import (
 	"github.com/flare-foundation/flare/snow/engine/common"
 	"github.com/flare-foundation/flare/snow/uptime"
 	"github.com/flare-foundation/flare/snow/validators"
-	"github.com/flare-foundation/flare/utils/crypto"
 	"github.com/flare-foundation/flare/utils/math"
 	"github.com/flare-foundation/flare/version"
 	"github.com/flare-foundation/flare/vms/components/avax"
@@ -211,8 +210,8 @@ func TestRewardDelegatorTxExecuteOnCommit(t *testing.T) {
 		vdrNodeID,        // node ID
 		vdrRewardAddress, // reward address
 		reward.PercentDenominator/4,
-		[]*crypto.PrivateKeySECP256K1R{keys[0]}, // fee payer
-		ids.ShortEmpty,                          // change addr
+		testKeychain(keys[0]), // fee payer
+		ids.ShortEmpty,        // change addr
 	)
 	assert.NoError(err)
 
@@ -222,10 +221,10 @@ func TestRewardDelegatorTxExecuteOnCommit(t *testing.T) {
 		vm.MinDelegatorStake, // stakeAmt
 		delStartTime,
 		delEndTime,
-		vdrNodeID,                               // node ID
-		delRewardAddress,                        // reward address
-		[]*crypto.PrivateKeySECP256K1R{keys[0]}, // fee payer
-		ids.ShortEmpty,                          // change addr
+		vdrNodeID,             // node ID
+		delRewardAddress,      // reward address
+		testKeychain(keys[0]), // fee payer
+		ids.ShortEmpty,        // change addr
 	)
 	assert.NoError(err)
 
@@ -317,8 +316,8 @@ func TestRewardDelegatorTxExecuteOnAbort(t *testing.T) {
 		vdrNodeID,        // node ID
 		vdrRewardAddress, // reward address
 		reward.PercentDenominator/4,
-		[]*crypto.PrivateKeySECP256K1R{keys[0]}, // fee payer
-		ids.ShortEmpty,                          // change addr
+		testKeychain(keys[0]), // fee payer
+		ids.ShortEmpty,        // change addr
 	)
 	assert.NoError(err)
 
@@ -328,10 +327,10 @@ func TestRewardDelegatorTxExecuteOnAbort(t *testing.T) {
 		vm.MinDelegatorStake, // stakeAmt
 		delStartTime,
 		delEndTime,
-		vdrNodeID,                               // node ID
-		delRewardAddress,                        // reward address
-		[]*crypto.PrivateKeySECP256K1R{keys[0]}, // fee payer
-		ids.ShortEmpty,                          // change addr
+		vdrNodeID,             // node ID
+		delRewardAddress,      // reward address
+		testKeychain(keys[0]), // fee payer
+		ids.ShortEmpty,        // change addr
 	)
 	assert.NoError(err)
 
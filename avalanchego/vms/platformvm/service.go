@@ -1035,16 +1035,16 @@ func (service *Service) AddValidator(_ *http.Request, args *AddValidatorArgs, re
 	defer user.Close()
 
 	// Get the user's keys
-	privKeys, err := keystore.GetKeychain(user, fromAddrs)
+	kc, err := keystore.GetSignerKeychain(service.vm.signer(user), fromAddrs)
 	if err != nil {
 		return fmt.Errorf("couldn't get addresses controlled by the user: %w", err)
 	}
 
 	// Parse the change address.
-	if len(privKeys.Keys) == 0 {
+	if len(kc.AddrList) == 0 {
 		return errNoKeys
 	}
-	changeAddr := privKeys.Keys[0].PublicKey().Address() // By default, use a key controlled by the user
+	changeAddr := kc.AddrList[0] // By default, use a key controlled by the user
 	if args.ChangeAddr != "" {
 		changeAddr, err = service.vm.ParseLocalAddress(args.ChangeAddr)
 		if err != nil {
@@ -1060,7 +1060,7 @@ func (service *Service) AddValidator(_ *http.Request, args *AddValidatorArgs, re
 		nodeID,                               // Node ID
 		rewardAddress,                        // Reward Address
 		uint32(10000*args.DelegationFeeRate), // Shares
-		privKeys.Keys,                        // Private keys
+		kc,                                   // Keychain
 		changeAddr,                           // Change address
 	)
 	if err != nil {
@@ -1141,17 +1141,17 @@ func (service *Service) AddDelegator(_ *http.Request, args *AddDelegatorArgs, re
 	}
 	defer user.Close()
 
-	privKeys, err := keystore.GetKeychain(user, fromAddrs)
+	kc, err := keystore.GetSignerKeychain(service.vm.signer(user), fromAddrs)
 	if err != nil {
 		return fmt.Errorf("couldn't get addresses controlled by the user: %w", err)
 	}
 
 	// Parse the change address. Assumes that if the user has no keys,
 	// this operation will fail so the change address can be anything.
-	if len(privKeys.Keys) == 0 {
+	if len(kc.AddrList) == 0 {
 		return errNoKeys
 	}
-	changeAddr := privKeys.Keys[0].PublicKey().Address() // By default, use a key controlled by the user
+	changeAddr := kc.AddrList[0] // By default, use a key controlled by the user
 	if args.ChangeAddr != "" {
 		changeAddr, err = service.vm.ParseLocalAddress(args.ChangeAddr)
 		if err != nil {
@@ -1166,7 +1166,7 @@ func (service *Service) AddDelegator(_ *http.Request, args *AddDelegatorArgs, re
 		uint64(args.EndTime),   // End time
 		nodeID,                 // Node ID
 		rewardAddress,          // Reward Address
-		privKeys.Keys,          // Private keys
+		kc,                     // Keychain
 		changeAddr,             // Change address
 	)
 	if err != nil {
@@ -1245,16 +1245,16 @@ func (service *Service) AddSubnetValidator(_ *http.Request, args *AddSubnetValid
 	}
 	defer user.Close()
 
-	keys, err := keystore.GetKeychain(user, fromAddrs)
+	kc, err := keystore.GetSignerKeychain(service.vm.signer(user), fromAddrs)
 	if err != nil {
 		return fmt.Errorf("couldn't get addresses controlled by the user: %w", err)
 	}
 
 	// Parse the change address.
-	if len(keys.Keys) == 0 {
+	if len(kc.AddrList) == 0 {
 		return errNoKeys
 	}
-	changeAddr := keys.Keys[0].PublicKey().Address() // By default, use a key controlled by the user
+	changeAddr := kc.AddrList[0] // By default, use a key controlled by the user
 	if args.ChangeAddr != "" {
 		changeAddr, err = service.vm.ParseLocalAddress(args.ChangeAddr)
 		if err != nil {
@@ -1269,7 +1269,7 @@ func (service *Service) AddSubnetValidator(_ *http.Request, args *AddSubnetValid
 		uint64(args.EndTime),   // End time
 		nodeID,                 // Node ID
 		subnetID,               // Subnet ID
-		keys.Keys,              // Keys
+		kc,                     // Keychain
 		changeAddr,             // Change address
 	)
 	if err != nil {
@@ -1319,17 +1319,17 @@ func (service *Service) CreateSubnet(_ *http.Request, args *CreateSubnetArgs, re
 	}
 	defer user.Close()
 
-	privKeys, err := keystore.GetKeychain(user, fromAddrs)
+	kc, err := keystore.GetSignerKeychain(service.vm.signer(user), fromAddrs)
 	if err != nil {
 		return fmt.Errorf("couldn't get addresses controlled by the user: %w", err)
 	}
 
 	// Parse the change address. Assumes that if the user has no keys,
 	// this operation will fail so the change address can be anything.
-	if len(privKeys.Keys) == 0 {
+	if len(kc.AddrList) == 0 {
 		return errNoKeys
 	}
-	changeAddr := privKeys.Keys[0].PublicKey().Address() // By default, use a key controlled by the user
+	changeAddr := kc.AddrList[0] // By default, use a key controlled by the user
 	if args.ChangeAddr != "" {
 		changeAddr, err = service.vm.ParseLocalAddress(args.ChangeAddr)
 		if err != nil {
@@ -1341,7 +1341,7 @@ func (service *Service) CreateSubnet(_ *http.Request, args *CreateSubnetArgs, re
 	tx, err := service.vm.newCreateSubnetTx(
 		uint32(args.Threshold), // Threshold
 		controlKeys.List(),     // Control Addresses
-		privKeys.Keys,          // Private keys
+		kc,                     // Keychain
 		changeAddr,             // Change address
 	)
 	if err != nil {
@@ -1400,17 +1400,17 @@ func (service *Service) ExportAVAX(_ *http.Request, args *ExportAVAXArgs, respon
 	}
 	defer user.Close()
 
-	privKeys, err := keystore.GetKeychain(user, fromAddrs)
+	kc, err := keystore.GetSignerKeychain(service.vm.signer(user), fromAddrs)
 	if err != nil {
 		return fmt.Errorf("couldn't get addresses controlled by the user: %w", err)
 	}
 
 	// Parse the change address. Assumes that if the user has no keys,
 	// this operation will fail so the change address can be anything.
-	if len(privKeys.Keys) == 0 {
+	if len(kc.AddrList) == 0 {
 		return errNoKeys
 	}
-	changeAddr := privKeys.Keys[0].PublicKey().Address() // By default, use a key controlled by the user
+	changeAddr := kc.AddrList[0] // By default, use a key controlled by the user
 	if args.ChangeAddr != "" {
 		changeAddr, err = service.vm.ParseLocalAddress(args.ChangeAddr)
 		if err != nil {
@@ -1423,7 +1423,7 @@ func (service *Service) ExportAVAX(_ *http.Request, args *ExportAVAXArgs, respon
 		uint64(args.Amount), // Amount
 		chainID,             // ID of the chain to send the funds to
 		to,                  // Address
-		privKeys.Keys,       // Private keys
+		kc,                  // Keychain
 		changeAddr,          // Change address
 	)
 	if err != nil {
@@ -1483,17 +1483,17 @@ func (service *Service) ImportAVAX(_ *http.Request, args *ImportAVAXArgs, respon
 	}
 	defer user.Close()
 
-	privKeys, err := keystore.GetKeychain(user, fromAddrs)
+	kc, err := keystore.GetSignerKeychain(service.vm.signer(user), fromAddrs)
 	if err != nil { // Get keys
 		return fmt.Errorf("couldn't get keys controlled by the user: %w", err)
 	}
 
 	// Parse the change address. Assumes that if the user has no keys,
 	// this operation will fail so the change address can be anything.
-	if len(privKeys.Keys) == 0 {
+	if len(kc.AddrList) == 0 {
 		return errNoKeys
 	}
-	changeAddr := privKeys.Keys[0].PublicKey().Address() // By default, use a key controlled by the user
+	changeAddr := kc.AddrList[0] // By default, use a key controlled by the user
 	if args.ChangeAddr != "" {
 		changeAddr, err = service.vm.ParseLocalAddress(args.ChangeAddr)
 		if err != nil {
@@ -1501,7 +1501,7 @@ func (service *Service) ImportAVAX(_ *http.Request, args *ImportAVAXArgs, respon
 		}
 	}
 
-	tx, err := service.vm.newImportTx(chainID, to, privKeys.Keys, changeAddr)
+	tx, err := service.vm.newImportTx(chainID, to, kc, changeAddr)
 	if err != nil {
 		return err
 	}
@@ -1595,17 +1595,17 @@ func (service *Service) CreateBlockchain(_ *http.Request, args *CreateBlockchain
 	}
 	defer user.Close()
 
-	keys, err := keystore.GetKeychain(user, fromAddrs)
+	kc, err := keystore.GetSignerKeychain(service.vm.signer(user), fromAddrs)
 	if err != nil {
 		return fmt.Errorf("couldn't get addresses controlled by the user: %w", err)
 	}
 
 	// Parse the change address. Assumes that if the user has no keys,
 	// this operation will fail so the change address can be anything.
-	if len(keys.Keys) == 0 {
+	if len(kc.AddrList) == 0 {
 		return errNoKeys
 	}
-	changeAddr := keys.Keys[0].PublicKey().Address() // By default, use a key controlled by the user
+	changeAddr := kc.AddrList[0] // By default, use a key controlled by the user
 	if args.ChangeAddr != "" {
 		changeAddr, err = service.vm.ParseLocalAddress(args.ChangeAddr)
 		if err != nil {
@@ -1620,7 +1620,7 @@ func (service *Service) CreateBlockchain(_ *http.Request, args *CreateBlockchain
 		vmID,
 		fxIDs,
 		args.Name,
-		keys.Keys,
+		kc,
 		changeAddr, // Change address
 	)
 	if err != nil {
@@ -236,7 +236,7 @@ func TestGetTxStatus(t *testing.T) {
 	newAtomicUTXOManager := avax.NewAtomicUTXOManager(sm, Codec)
 
 	service.vm.AtomicUTXOManager = newAtomicUTXOManager
-	tx, err := service.vm.newImportTx(xChainID, ids.ShortEmpty, []*crypto.PrivateKeySECP256K1R{recipientKey}, ids.ShortEmpty)
+	tx, err := service.vm.newImportTx(xChainID, ids.ShortEmpty, testKeychain(recipientKey), ids.ShortEmpty)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -328,7 +328,7 @@ func TestGetTx(t *testing.T) {
 					constants.AVMID,
 					nil,
 					"chain name",
-					[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+					testKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]),
 					keys[0].PublicKey().Address(), // change addr
 				)
 			},
@@ -343,7 +343,7 @@ func TestGetTx(t *testing.T) {
 					ids.GenerateTestShortID(),
 					ids.GenerateTestShortID(),
 					0,
-					[]*crypto.PrivateKeySECP256K1R{keys[0]},
+					testKeychain(keys[0]),
 					keys[0].PublicKey().Address(), // change addr
 				)
 			},
@@ -355,7 +355,7 @@ func TestGetTx(t *testing.T) {
 					100,
 					service.vm.ctx.XChainID,
 					ids.GenerateTestShortID(),
-					[]*crypto.PrivateKeySECP256K1R{keys[0]},
+					testKeychain(keys[0]),
 					keys[0].PublicKey().Address(), // change addr
 				)
 			},
@@ -529,7 +529,7 @@ func TestGetStake(t *testing.T) {
 		delegatorEndTime,
 		delegatorNodeID,
 		ids.GenerateTestShortID(),
-		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		testKeychain(keys[0]),
 		keys[0].PublicKey().Address(), // change addr
 	)
 	assert.NoError(err)
@@ -573,7 +573,7 @@ func TestGetStake(t *testing.T) {
 		pendingStakerNodeID,
 		ids.GenerateTestShortID(),
 		0,
-		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		testKeychain(keys[0]),
 		keys[0].PublicKey().Address(), // change addr
 	)
 	assert.NoError(err)
@@ -676,7 +676,7 @@ func TestGetCurrentValidators(t *testing.T) {
 		delegatorEndTime,
 		validatorNodeID,
 		ids.GenerateTestShortID(),
-		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		testKeychain(keys[0]),
 		keys[0].PublicKey().Address(), // change addr
 	)
 	if err != nil {
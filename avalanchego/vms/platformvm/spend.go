@@ -8,7 +8,6 @@ import (
 	"fmt"
 
 	"github.com/flare-foundation/flare/ids"
-	"github.com/flare-foundation/flare/utils/crypto"
 	"github.com/flare-foundation/flare/utils/hashing"
 	"github.com/flare-foundation/flare/utils/math"
 	"github.com/flare-foundation/flare/vms/components/avax"
@@ -25,7 +24,7 @@ var (
 
 // stake the provided amount while deducting the provided fee.
 // Arguments:
-// - [keys] are the owners of the funds
+// - [kc] spends with the addresses of the owners of the funds
 // - [amount] is the amount of funds that are trying to be staked
 // - [fee] is the amount of AVAX that should be burned
 // - [changeAddr] is the address that change, if there is any, is sent to
@@ -37,7 +36,7 @@ var (
 //                   staking period
 // - [signers] the proof of ownership of the funds being moved
 func (vm *VM) stake(
-	keys []*crypto.PrivateKeySECP256K1R,
+	kc *secp256k1fx.SignerKeychain,
 	amount uint64,
 	fee uint64,
 	changeAddr ids.ShortID,
@@ -45,27 +44,21 @@ func (vm *VM) stake(
 	[]*avax.TransferableInput, // inputs
 	[]*avax.TransferableOutput, // returnedOutputs
 	[]*avax.TransferableOutput, // stakedOutputs
-	[][]*crypto.PrivateKeySECP256K1R, // signers
+	[][]ids.ShortID, // signers
 	error,
 ) {
-	addrs := ids.NewShortSet(len(keys)) // The addresses controlled by [keys]
-	for _, key := range keys {
-		addrs.Add(key.PublicKey().Address())
-	}
-	utxos, err := avax.GetAllUTXOs(vm.internalState, addrs) // The UTXOs controlled by [keys]
+	utxos, err := avax.GetAllUTXOs(vm.internalState, kc.Addrs) // The UTXOs controlled by [kc]
 	if err != nil {
 		return nil, nil, nil, nil, fmt.Errorf("couldn't get UTXOs: %w", err)
 	}
 
-	kc := secp256k1fx.NewKeychain(keys...) // Keychain consumes UTXOs and creates new ones
-
 	// Minimum time this transaction will be issued at
 	now := uint64(vm.clock.Time().Unix())
 
 	ins := []*avax.TransferableInput{}
 	returnedOuts := []*avax.TransferableOutput{}
 	stakedOuts := []*avax.TransferableOutput{}
-	signers := [][]*crypto.PrivateKeySECP256K1R{}
+	signers := [][]ids.ShortID{}
 
 	// Amount of AVAX that has been staked
 	amountStaked := uint64(0)
@@ -275,14 +268,15 @@ func (vm *VM) stake(
 	return ins, returnedOuts, stakedOuts, signers, nil
 }
 
-// authorize an operation on behalf of the named subnet with the provided keys.
+// authorize an operation on behalf of the named subnet with the addresses of
+// [kc].
 func (vm *VM) authorize(
 	vs MutableState,
 	subnetID ids.ID,
-	keys []*crypto.PrivateKeySECP256K1R,
+	kc *secp256k1fx.SignerKeychain,
 ) (
 	verify.Verifiable, // Input that names owners
-	[]*crypto.PrivateKeySECP256K1R, // Keys that prove ownership
+	[]ids.ShortID, // Addresses whose keys prove ownership
 	error,
 ) {
 	subnetTx, _, err := vs.GetTx(subnetID)
@@ -304,9 +298,6 @@ func (vm *VM) authorize(
 		return nil, nil, errUnknownOwners
 	}
 
-	// Add the keys to a keychain
-	kc := secp256k1fx.NewKeychain(keys...)
-
 	// Make sure that the operation is valid after a minimum time
 	now := uint64(vm.clock.Time().Unix())
 
@@ -10,7 +10,6 @@ import (
 	"github.com/flare-foundation/flare/chains/atomic"
 	"github.com/flare-foundation/flare/database/prefixdb"
 	"github.com/flare-foundation/flare/ids"
-	"github.com/flare-foundation/flare/utils/crypto"
 	"github.com/flare-foundation/flare/utils/logging"
 	"github.com/flare-foundation/flare/vms/components/avax"
 	"github.com/flare-foundation/flare/vms/platformvm/status"
@@ -74,7 +73,7 @@ func TestAtomicTxImports(t *testing.T) {
 	tx, err := vm.newImportTx(
 		vm.ctx.XChainID,
 		recipientKey.PublicKey().Address(),
-		[]*crypto.PrivateKeySECP256K1R{recipientKey},
+		testKeychain(recipientKey),
 		ids.ShortEmpty, // change addr
 	)
 	if err != nil {
@@ -13,7 +13,6 @@ import (
 	"github.com/flare-foundation/flare/ids"
 	"github.com/flare-foundation/flare/snow"
 	"github.com/flare-foundation/flare/utils/crypto"
-	"github.com/flare-foundation/flare/utils/hashing"
 	"github.com/flare-foundation/flare/vms/components/verify"
 	"github.com/flare-foundation/flare/vms/secp256k1fx"
 )
@@ -99,23 +98,23 @@ type Tx struct {
 
 // Sign this transaction with the provided signers
 func (tx *Tx) Sign(c codec.Manager, signers [][]*crypto.PrivateKeySECP256K1R) error {
+	kc, addrs := secp256k1fx.SignerAddrs(signers)
+	return tx.SignWithSigner(c, kc, addrs)
+}
+
+// SignWithSigner signs this transaction with [signer], using the keys that
+// control the addresses in [signers]
+func (tx *Tx) SignWithSigner(c codec.Manager, signer secp256k1fx.Signer, signers [][]ids.ShortID) error {
 	unsignedBytes, err := c.Marshal(CodecVersion, &tx.UnsignedTx)
 	if err != nil {
 		return fmt.Errorf("couldn't marshal UnsignedTx: %w", err)
 	}
 
 	// Attach credentials
-	hash := hashing.ComputeHash256(unsignedBytes)
-	for _, keys := range signers {
-		cred := &secp256k1fx.Credential{
-			Sigs: make([][crypto.SECP256K1RSigLen]byte, len(keys)),
-		}
-		for i, key := range keys {
-			sig, err := key.SignHash(hash) // Sign hash
-			if err != nil {
-				return fmt.Errorf("problem generating credential: %w", err)
-			}
-			copy(cred.Sigs[i][:], sig)
+	for _, addrs := range signers {
+		cred, err := secp256k1fx.Sign(signer, unsignedBytes, addrs)
+		if err != nil {
+			return err
 		}
 		tx.Creds = append(tx.Creds, cred) // Attach credential
 	}
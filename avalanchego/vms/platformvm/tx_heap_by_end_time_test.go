@@ -7,7 +7,6 @@ import (
 	"testing"
 
 	"github.com/flare-foundation/flare/ids"
-	"github.com/flare-foundation/flare/utils/crypto"
 )
 
 func TestTxHeapStop(t *testing.T) {
@@ -27,11 +26,11 @@ func TestTxHeapStop(t *testing.T) {
 		vm.MinValidatorStake,                                               // stake amount
 		uint64(defaultGenesisTime.Unix()+1),                                // startTime
 		uint64(defaultGenesisTime.Add(defaultMinStakingDuration).Unix()+1), // endTime
-		ids.ShortID{},                           // node ID
-		ids.ShortID{1, 2, 3, 4, 5, 6, 7},        // reward address
-		0,                                       // shares
-		[]*crypto.PrivateKeySECP256K1R{keys[0]}, // key
-		ids.ShortEmpty,                          // change addr
+		ids.ShortID{},                    // node ID
+		ids.ShortID{1, 2, 3, 4, 5, 6, 7}, // reward address
+		0,                                // shares
+		testKeychain(keys[0]),            // key
+		ids.ShortEmpty,                   // change addr
 	)
 	if err != nil {
 		t.Fatal(err)
@@ -42,11 +41,11 @@ func TestTxHeapStop(t *testing.T) {
 		vm.MinValidatorStake,                                               // stake amount
 		uint64(defaultGenesisTime.Unix()+1),                                // startTime
 		uint64(defaultGenesisTime.Add(defaultMinStakingDuration).Unix()+2), // endTime
-		ids.ShortID{1},                          // node ID
-		ids.ShortID{1, 2, 3, 4, 5, 6, 7},        // reward address
-		0,                                       // shares
-		[]*crypto.PrivateKeySECP256K1R{keys[0]}, // key
-		ids.ShortEmpty,                          // change addr
+		ids.ShortID{1},                   // node ID
+		ids.ShortID{1, 2, 3, 4, 5, 6, 7}, // reward address
+		0,                                // shares
+		testKeychain(keys[0]),            // key
+		ids.ShortEmpty,                   // change addr
 	)
 	if err != nil {
 		t.Fatal(err)
@@ -57,11 +56,11 @@ func TestTxHeapStop(t *testing.T) {
 		vm.MinValidatorStake,                                               // stake amount
 		uint64(defaultGenesisTime.Unix()+1),                                // startTime
 		uint64(defaultGenesisTime.Add(defaultMinStakingDuration).Unix()+3), // endTime
-		ids.ShortID{},                           // node ID
-		ids.ShortID{1, 2, 3, 4, 5, 6, 7},        // reward address
-		0,                                       // shares
-		[]*crypto.PrivateKeySECP256K1R{keys[0]}, // key
-		ids.ShortEmpty,                          // change addr
+		ids.ShortID{},                    // node ID
+		ids.ShortID{1, 2, 3, 4, 5, 6, 7}, // reward address
+		0,                                // shares
+		testKeychain(keys[0]),            // key
+		ids.ShortEmpty,                   // change addr
 	)
 	if err != nil {
 		t.Fatal(err)
@@ -35,6 +35,7 @@ import (
 	"github.com/flare-foundation/flare/utils/wrappers"
 	"github.com/flare-foundation/flare/version"
 	"github.com/flare-foundation/flare/vms/components/avax"
+	"github.com/flare-foundation/flare/vms/components/keystore"
 	"github.com/flare-foundation/flare/vms/platformvm/reward"
 	"github.com/flare-foundation/flare/vms/secp256k1fx"
 
@@ -587,6 +588,15 @@ func (vm *VM) Clock() *mockable.Clock { return &vm.clock }
 
 func (vm *VM) Logger() logging.Logger { return vm.ctx.Log }
 
+// signer returns the signer of the transactions issued by [user]: the remote
+// signer if there is one, or the keys of [user] otherwise
+func (vm *VM) signer(user keystore.User) keystore.Signer {
+	if vm.RemoteSigner == nil {
+		return keystore.NewUserSigner(user)
+	}
+	return keystore.NewChainSigner(vm.RemoteSigner, vm.ctx.ChainID)
+}
+
 // Returns the percentage of the total stake on the Primary Network of nodes
 // connected to this node.
 func (vm *VM) getPercentConnected() (float64, error) {
@@ -21,6 +21,7 @@ import (
 	"github.com/flare-foundation/flare/database/prefixdb"
 	"github.com/flare-foundation/flare/ids"
 	"github.com/flare-foundation/flare/message"
+	"github.com/flare-foundation/flare/network/throttling"
 	"github.com/flare-foundation/flare/snow"
 	"github.com/flare-foundation/flare/snow/choices"
 	"github.com/flare-foundation/flare/snow/consensus/snowball"
@@ -128,6 +129,15 @@ func init() {
 	testSubnet1ControlKeys = keys[0:3]
 }
 
+// testKeychain returns a keychain that spends with, and signs with, [keys]
+func testKeychain(keys ...*crypto.PrivateKeySECP256K1R) *secp256k1fx.SignerKeychain {
+	kc := secp256k1fx.NewSignerKeychain(secp256k1fx.NewKeychain(keys...))
+	for _, key := range keys {
+		kc.Add(key.PublicKey().Address())
+	}
+	return kc
+}
+
 type snLookup struct {
 	chainsToSubnet map[ids.ID]ids.ID
 }
@@ -371,8 +381,8 @@ func defaultVM() (*VM, database.Database, *common.SenderTest) {
 		2, // threshold; 2 sigs from keys[0], keys[1], keys[2] needed to add validator to this subnet
 		// control keys are keys[0], keys[1], keys[2]
 		[]ids.ShortID{keys[0].PublicKey().Address(), keys[1].PublicKey().Address(), keys[2].PublicKey().Address()},
-		[]*crypto.PrivateKeySECP256K1R{keys[0]}, // pays tx fee
-		keys[0].PublicKey().Address(),           // change addr
+		testKeychain(keys[0]),         // pays tx fee
+		keys[0].PublicKey().Address(), // change addr
 	); err != nil {
 		panic(err)
 	} else if err := vm.blockBuilder.AddUnverifiedTx(tx); err != nil {
@@ -445,8 +455,8 @@ func GenesisVMWithArgs(t *testing.T, args *BuildGenesisArgs) ([]byte, chan commo
 		2, // threshold; 2 sigs from keys[0], keys[1], keys[2] needed to add validator to this subnet
 		// control keys are keys[0], keys[1], keys[2]
 		[]ids.ShortID{keys[0].PublicKey().Address(), keys[1].PublicKey().Address(), keys[2].PublicKey().Address()},
-		[]*crypto.PrivateKeySECP256K1R{keys[0]}, // pays tx fee
-		keys[0].PublicKey().Address(),           // change addr
+		testKeychain(keys[0]),         // pays tx fee
+		keys[0].PublicKey().Address(), // change addr
 	); err != nil {
 		panic(err)
 	} else if err := vm.blockBuilder.AddUnverifiedTx(tx); err != nil {
@@ -573,7 +583,7 @@ func TestAddValidatorCommit(t *testing.T) {
 		nodeID,
 		nodeID,
 		reward.PercentDenominator,
-		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		testKeychain(keys[0]),
 		ids.ShortEmpty, // change addr
 	)
 	if err != nil {
@@ -651,7 +661,7 @@ func TestInvalidAddValidatorCommit(t *testing.T) {
 		nodeID,
 		nodeID,
 		reward.PercentDenominator,
-		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		testKeychain(keys[0]),
 		ids.ShortEmpty, // change addr
 	)
 	if err != nil {
@@ -708,7 +718,7 @@ func TestAddValidatorReject(t *testing.T) {
 		nodeID,
 		nodeID,
 		reward.PercentDenominator,
-		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		testKeychain(keys[0]),
 		ids.ShortEmpty, // change addr
 	)
 	if err != nil {
@@ -785,7 +795,7 @@ func TestAddValidatorInvalidNotReissued(t *testing.T) {
 		repeatNodeID,
 		repeatNodeID,
 		reward.PercentDenominator,
-		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		testKeychain(keys[0]),
 		ids.ShortEmpty, // change addr
 	)
 	if err != nil {
@@ -823,7 +833,7 @@ func TestAddSubnetValidatorAccept(t *testing.T) {
 		uint64(endTime.Unix()),
 		nodeID,
 		testSubnet1.ID(),
-		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+		testKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]),
 		ids.ShortEmpty, // change addr
 	)
 	if err != nil {
@@ -907,7 +917,7 @@ func TestAddSubnetValidatorReject(t *testing.T) {
 		uint64(endTime.Unix()),
 		nodeID,
 		testSubnet1.ID(),
-		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[1], testSubnet1ControlKeys[2]},
+		testKeychain(testSubnet1ControlKeys[1], testSubnet1ControlKeys[2]),
 		ids.ShortEmpty, // change addr
 	)
 	if err != nil {
@@ -1276,7 +1286,7 @@ func TestCreateChain(t *testing.T) {
 		ids.ID{'t', 'e', 's', 't', 'v', 'm'},
 		nil,
 		"name",
-		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+		testKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]),
 		ids.ShortEmpty, // change addr
 	)
 	if err != nil {
@@ -1335,8 +1345,8 @@ func TestCreateSubnet(t *testing.T) {
 			keys[0].PublicKey().Address(),
 			keys[1].PublicKey().Address(),
 		},
-		[]*crypto.PrivateKeySECP256K1R{keys[0]}, // payer
-		keys[0].PublicKey().Address(),           // change addr
+		testKeychain(keys[0]),         // payer
+		keys[0].PublicKey().Address(), // change addr
 	)
 	if err != nil {
 		t.Fatal(err)
@@ -1380,7 +1390,7 @@ func TestCreateSubnet(t *testing.T) {
 		uint64(endTime.Unix()),
 		nodeID,
 		createSubnetTx.ID(),
-		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		testKeychain(keys[0]),
 		ids.ShortEmpty, // change addr
 	); err != nil {
 		t.Fatal(err)
@@ -1578,7 +1588,7 @@ func TestAtomicImport(t *testing.T) {
 	if _, err := vm.newImportTx(
 		vm.ctx.XChainID,
 		recipientKey.PublicKey().Address(),
-		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		testKeychain(keys[0]),
 		ids.ShortEmpty, // change addr
 	); err == nil {
 		t.Fatalf("should have errored due to missing utxos")
@@ -1615,7 +1625,7 @@ func TestAtomicImport(t *testing.T) {
 	tx, err := vm.newImportTx(
 		vm.ctx.XChainID,
 		recipientKey.PublicKey().Address(),
-		[]*crypto.PrivateKeySECP256K1R{recipientKey},
+		testKeychain(recipientKey),
 		ids.ShortEmpty, // change addr
 	)
 	if err != nil {
@@ -2109,6 +2119,7 @@ func TestBootstrapPartiallyAccepted(t *testing.T) {
 		msgChan,
 		nil,
 		time.Hour,
+		throttling.ChainMsgThrottlerConfig{},
 	)
 	assert.NoError(t, err)
 
@@ -2420,15 +2431,15 @@ func TestUnverifiedParentPanic(t *testing.T) {
 	addr0 := key0.PublicKey().Address()
 	addr1 := key1.PublicKey().Address()
 
-	addSubnetTx0, err := vm.newCreateSubnetTx(1, []ids.ShortID{addr0}, []*crypto.PrivateKeySECP256K1R{key0}, addr0)
+	addSubnetTx0, err := vm.newCreateSubnetTx(1, []ids.ShortID{addr0}, testKeychain(key0), addr0)
 	if err != nil {
 		t.Fatal(err)
 	}
-	addSubnetTx1, err := vm.newCreateSubnetTx(1, []ids.ShortID{addr1}, []*crypto.PrivateKeySECP256K1R{key1}, addr1)
+	addSubnetTx1, err := vm.newCreateSubnetTx(1, []ids.ShortID{addr1}, testKeychain(key1), addr1)
 	if err != nil {
 		t.Fatal(err)
 	}
-	addSubnetTx2, err := vm.newCreateSubnetTx(1, []ids.ShortID{addr1}, []*crypto.PrivateKeySECP256K1R{key1}, addr0)
+	addSubnetTx2, err := vm.newCreateSubnetTx(1, []ids.ShortID{addr1}, testKeychain(key1), addr0)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -2502,7 +2513,7 @@ func TestRejectedStateRegressionInvalidValidatorTimestamp(t *testing.T) {
 		nodeID,
 		nodeID,
 		reward.PercentDenominator,
-		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		testKeychain(keys[0]),
 		ids.ShortEmpty,
 	)
 	assert.NoError(err)
@@ -2730,7 +2741,7 @@ func TestRejectedStateRegressionInvalidValidatorReward(t *testing.T) {
 		nodeID0,
 		nodeID0,
 		reward.PercentDenominator,
-		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		testKeychain(keys[0]),
 		ids.ShortEmpty,
 	)
 	assert.NoError(err)
@@ -2917,7 +2928,7 @@ func TestRejectedStateRegressionInvalidValidatorReward(t *testing.T) {
 		nodeID1,
 		nodeID1,
 		reward.PercentDenominator,
-		[]*crypto.PrivateKeySECP256K1R{keys[1]},
+		testKeychain(keys[1]),
 		ids.ShortEmpty,
 	)
 	assert.NoError(err)
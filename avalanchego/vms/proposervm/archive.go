@@ -0,0 +1,131 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/flare-foundation/flare/database"
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/snow/choices"
+	"github.com/flare-foundation/flare/snow/engine/snowman/block"
+
+	statelessblock "github.com/flare-foundation/flare/vms/proposervm/block"
+)
+
+var (
+	errArchivedBlockNotAccepted = errors.New("archived block doesn't wrap the last accepted inner block")
+	errArchivedBlockParent      = errors.New("archived block doesn't extend the last accepted block")
+)
+
+// ArchivedBlockBytes returns the bytes of the accepted block at [height], or
+// nil if the block at [height] was accepted before the fork. Returns
+// ErrIndexIncomplete while the height index is being repaired.
+//
+// vm.ctx.Lock should be held
+func (vm *VM) ArchivedBlockBytes(height uint64) ([]byte, error) {
+	switch _, err := vm.State.GetCheckpoint(); err {
+	case nil:
+		return nil, block.ErrIndexIncomplete
+	case database.ErrNotFound:
+	default:
+		return nil, err
+	}
+
+	switch forkHeight, err := vm.State.GetForkHeight(); err {
+	case nil:
+		if height < forkHeight {
+			return nil, nil
+		}
+
+	case database.ErrNotFound:
+		// The fork height is stored once the first post fork block is
+		// indexed, so it is only missing if no post fork block was accepted.
+		if _, err := vm.State.GetLastAccepted(); err != database.ErrNotFound {
+			if err != nil {
+				return nil, err
+			}
+			return nil, block.ErrIndexIncomplete
+		}
+		return nil, nil
+
+	default:
+		return nil, err
+	}
+
+	blkID, err := vm.State.GetBlockIDAtHeight(height)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get block ID at height %d: %w", height, err)
+	}
+	blk, status, err := vm.State.GetBlock(blkID)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get block %s: %w", blkID, err)
+	}
+	if status != choices.Accepted {
+		return nil, fmt.Errorf("block %s at height %d has status %s", blkID, height, status)
+	}
+	return blk.Bytes(), nil
+}
+
+// AcceptArchivedBlock marks the block [blockBytes], which wraps the last
+// accepted block of the inner VM, as accepted. It restores the state of
+// blocks that the inner VM accepted outside of consensus, such as blocks
+// imported from an archive, and must not be called while the chain is
+// running. Blocks that are already accepted are ignored.
+//
+// vm.ctx.Lock should be held
+func (vm *VM) AcceptArchivedBlock(blockBytes []byte) error {
+	statelessBlk, err := statelessblock.Parse(blockBytes)
+	if err != nil {
+		return fmt.Errorf("couldn't parse archived block: %w", err)
+	}
+	blkID := statelessBlk.ID()
+	switch _, status, err := vm.State.GetBlock(blkID); {
+	case err == nil && status == choices.Accepted:
+		return nil
+	case err != nil && err != database.ErrNotFound:
+		return err
+	}
+
+	innerBlk, err := vm.ChainVM.ParseBlock(statelessBlk.Block())
+	if err != nil {
+		return fmt.Errorf("couldn't parse inner block of archived block %s: %w", blkID, err)
+	}
+	innerLastAcceptedID, err := vm.ChainVM.LastAccepted()
+	if err != nil {
+		return err
+	}
+	if innerBlk.ID() != innerLastAcceptedID {
+		return fmt.Errorf("%w: block %s wraps %s, last accepted is %s", errArchivedBlockNotAccepted, blkID, innerBlk.ID(), innerLastAcceptedID)
+	}
+
+	// The first post fork block extends the last pre fork block, which is the
+	// inner block itself.
+	expectedParentID, err := vm.State.GetLastAccepted()
+	if err == database.ErrNotFound {
+		expectedParentID = innerBlk.Parent()
+	} else if err != nil {
+		return err
+	}
+	if statelessBlk.ParentID() != expectedParentID {
+		return fmt.Errorf("%w: block %s has parent %s, expected %s", errArchivedBlockParent, blkID, statelessBlk.ParentID(), expectedParentID)
+	}
+	if signedBlk, ok := statelessBlk.(statelessblock.SignedBlock); ok {
+		if err := signedBlk.Verify(signedBlk.Proposer() != ids.ShortEmpty, vm.ctx.ChainID); err != nil {
+			return fmt.Errorf("archived block %s failed verification: %w", blkID, err)
+		}
+	}
+
+	if err := vm.State.SetLastAccepted(blkID); err != nil {
+		return err
+	}
+	if err := vm.State.PutBlock(statelessBlk, choices.Accepted); err != nil {
+		return err
+	}
+	if err := vm.updateHeightIndex(innerBlk.Height(), blkID); err != nil {
+		return err
+	}
+	return vm.db.Commit()
+}
@@ -73,42 +73,79 @@ func (kc *Keychain) New() (*crypto.PrivateKeySECP256K1R, error) {
 
 // Spend attempts to create an input
 func (kc *Keychain) Spend(out verify.Verifiable, time uint64) (verify.Verifiable, []*crypto.PrivateKeySECP256K1R, error) {
+	var keys []*crypto.PrivateKeySECP256K1R
+	in, err := spend(out, func(owners *OutputOwners) ([]uint32, bool) {
+		sigIndices, matchedKeys, able := kc.Match(owners, time)
+		keys = matchedKeys
+		return sigIndices, able
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return in, keys, nil
+}
+
+// Match attempts to match a list of addresses up to the provided threshold
+func (kc *Keychain) Match(owners *OutputOwners, time uint64) ([]uint32, []*crypto.PrivateKeySECP256K1R, bool) {
+	sigs, able := match(owners, time, func(addr ids.ShortID) bool {
+		_, exists := kc.addrToKeyIndex[addr]
+		return exists
+	})
+	keys := make([]*crypto.PrivateKeySECP256K1R, len(sigs))
+	for i, sig := range sigs {
+		keys[i], _ = kc.Get(owners.Addrs[sig])
+	}
+	return sigs, keys, able
+}
+
+// SignTx implements Signer
+func (kc *Keychain) SignTx(addr ids.ShortID, unsignedTx []byte) ([]byte, error) {
+	key, exists := kc.Get(addr)
+	if !exists {
+		return nil, fmt.Errorf("%w %s", errUnknownAddress, addr)
+	}
+	return key.Sign(unsignedTx)
+}
+
+// spend returns an input that spends [out] with the signatures at the indices
+// returned by [match], if [match] is able to spend the owners of [out]
+func spend(out verify.Verifiable, match func(*OutputOwners) ([]uint32, bool)) (verify.Verifiable, error) {
 	switch out := out.(type) {
 	case *MintOutput:
-		if sigIndices, keys, able := kc.Match(&out.OutputOwners, time); able {
+		if sigIndices, able := match(&out.OutputOwners); able {
 			return &Input{
 				SigIndices: sigIndices,
-			}, keys, nil
+			}, nil
 		}
-		return nil, nil, errCantSpend
+		return nil, errCantSpend
 	case *TransferOutput:
-		if sigIndices, keys, able := kc.Match(&out.OutputOwners, time); able {
+		if sigIndices, able := match(&out.OutputOwners); able {
 			return &TransferInput{
 				Amt: out.Amt,
 				Input: Input{
 					SigIndices: sigIndices,
 				},
-			}, keys, nil
+			}, nil
 		}
-		return nil, nil, errCantSpend
+		return nil, errCantSpend
 	}
-	return nil, nil, fmt.Errorf("can't spend UTXO because it is unexpected type %T", out)
+	return nil, fmt.Errorf("can't spend UTXO because it is unexpected type %T", out)
 }
 
-// Match attempts to match a list of addresses up to the provided threshold
-func (kc *Keychain) Match(owners *OutputOwners, time uint64) ([]uint32, []*crypto.PrivateKeySECP256K1R, bool) {
+// match returns the indices of the addresses of [owners] that [controls]
+// returns true for, up to the threshold of [owners]. Returns true if the
+// threshold is reached.
+func match(owners *OutputOwners, time uint64, controls func(ids.ShortID) bool) ([]uint32, bool) {
 	if time < owners.Locktime {
-		return nil, nil, false
+		return nil, false
 	}
 	sigs := make([]uint32, 0, owners.Threshold)
-	keys := make([]*crypto.PrivateKeySECP256K1R, 0, owners.Threshold)
-	for i := uint32(0); i < uint32(len(owners.Addrs)) && uint32(len(keys)) < owners.Threshold; i++ {
-		if key, exists := kc.Get(owners.Addrs[i]); exists {
+	for i := uint32(0); i < uint32(len(owners.Addrs)) && uint32(len(sigs)) < owners.Threshold; i++ {
+		if controls(owners.Addrs[i]) {
 			sigs = append(sigs, i)
-			keys = append(keys, key)
 		}
 	}
-	return sigs, keys, uint32(len(keys)) == owners.Threshold
+	return sigs, uint32(len(sigs)) == owners.Threshold
 }
 
 // PrefixedString returns the key chain as a string representation with [prefix]
@@ -0,0 +1,142 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package secp256k1fx
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/utils/crypto"
+	"github.com/flare-foundation/flare/vms/components/verify"
+)
+
+var (
+	errUnknownAddress = errors.New("no key controls the address")
+
+	_ Signer = &Keychain{}
+	_ Signer = &SignerKeychain{}
+)
+
+// Signer signs transactions with the keys that control addresses, without
+// exposing the keys. The signer is given the transaction rather than its hash,
+// so that it can decide what it signs.
+type Signer interface {
+	// SignTx returns the recoverable signature of the hash of [unsignedTx] by
+	// the key that controls [addr]
+	SignTx(addr ids.ShortID, unsignedTx []byte) ([]byte, error)
+}
+
+// SignerKeychain spends outputs with the addresses whose keys are held by a
+// Signer
+type SignerKeychain struct {
+	Signer
+
+	// These can be used to iterate over. However, they should not be modified
+	// externally.
+	Addrs ids.ShortSet
+	// The addresses in the order they were added
+	AddrList []ids.ShortID
+
+	// The public keys of the addresses that were added with AddPublicKeys
+	publicKeys map[ids.ShortID]*crypto.PublicKeySECP256K1R
+}
+
+// NewSignerKeychain returns a keychain that spends outputs with [addrs], whose
+// keys are held by [signer]
+func NewSignerKeychain(signer Signer, addrs ...ids.ShortID) *SignerKeychain {
+	kc := &SignerKeychain{Signer: signer}
+	kc.Add(addrs...)
+	return kc
+}
+
+// Add adds [addrs] to the addresses that outputs are spent with
+func (kc *SignerKeychain) Add(addrs ...ids.ShortID) {
+	for _, addr := range addrs {
+		if !kc.Addrs.Contains(addr) {
+			kc.Addrs.Add(addr)
+			kc.AddrList = append(kc.AddrList, addr)
+		}
+	}
+}
+
+// AddPublicKeys adds the addresses of [pks] to the addresses that outputs are
+// spent with, and remembers the public keys of the addresses
+func (kc *SignerKeychain) AddPublicKeys(pks ...*crypto.PublicKeySECP256K1R) {
+	if kc.publicKeys == nil {
+		kc.publicKeys = make(map[ids.ShortID]*crypto.PublicKeySECP256K1R, len(pks))
+	}
+	for _, pk := range pks {
+		addr := pk.Address()
+		kc.publicKeys[addr] = pk
+		kc.Add(addr)
+	}
+}
+
+// PublicKey returns the public key of [addr], if it was added with
+// AddPublicKeys
+func (kc *SignerKeychain) PublicKey(addr ids.ShortID) (*crypto.PublicKeySECP256K1R, bool) {
+	pk, exists := kc.publicKeys[addr]
+	return pk, exists
+}
+
+// Spend attempts to create an input. Returns the addresses that must sign the
+// input.
+func (kc *SignerKeychain) Spend(out verify.Verifiable, time uint64) (verify.Verifiable, []ids.ShortID, error) {
+	var addrs []ids.ShortID
+	in, err := spend(out, func(owners *OutputOwners) ([]uint32, bool) {
+		sigIndices, matchedAddrs, able := kc.Match(owners, time)
+		addrs = matchedAddrs
+		return sigIndices, able
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return in, addrs, nil
+}
+
+// Match attempts to match a list of addresses up to the provided threshold
+func (kc *SignerKeychain) Match(owners *OutputOwners, time uint64) ([]uint32, []ids.ShortID, bool) {
+	sigs, able := match(owners, time, kc.Addrs.Contains)
+	addrs := make([]ids.ShortID, len(sigs))
+	for i, sig := range sigs {
+		addrs[i] = owners.Addrs[sig]
+	}
+	return sigs, addrs, able
+}
+
+// Sign returns a credential with the signatures of [unsignedTx] by the keys
+// that control [addrs], in order
+func Sign(signer Signer, unsignedTx []byte, addrs []ids.ShortID) (*Credential, error) {
+	cred := &Credential{
+		Sigs: make([][crypto.SECP256K1RSigLen]byte, len(addrs)),
+	}
+	for i, addr := range addrs {
+		sig, err := signer.SignTx(addr, unsignedTx)
+		if err != nil {
+			return nil, fmt.Errorf("problem generating credential: %w", err)
+		}
+		if len(sig) != crypto.SECP256K1RSigLen {
+			return nil, fmt.Errorf("problem generating credential: signature has %d bytes instead of %d", len(sig), crypto.SECP256K1RSigLen)
+		}
+		copy(cred.Sigs[i][:], sig)
+	}
+	return cred, nil
+}
+
+// SignerAddrs returns the addresses of the keys in [signers]. Together with a
+// keychain of the keys, they allow transactions that are signed by private keys
+// to be signed by a Signer.
+func SignerAddrs(signers [][]*crypto.PrivateKeySECP256K1R) (*Keychain, [][]ids.ShortID) {
+	kc := NewKeychain()
+	addrs := make([][]ids.ShortID, len(signers))
+	for i, keys := range signers {
+		addrs[i] = make([]ids.ShortID, len(keys))
+		for j, key := range keys {
+			kc.Add(key)
+			addrs[i][j] = key.PublicKey().Address()
+		}
+	}
+	return kc, addrs
+}
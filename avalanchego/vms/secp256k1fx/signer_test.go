@@ -0,0 +1,104 @@
+// (c) 2021, Flare Networks Limited. All rights reserved.
+// Please see the file LICENSE for licensing terms.
+
+package secp256k1fx
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/utils/crypto"
+	"github.com/flare-foundation/flare/utils/hashing"
+)
+
+func TestSignerKeychainSpendAndSign(t *testing.T) {
+	kc := NewKeychain()
+	sks := []*crypto.PrivateKeySECP256K1R{}
+	for i := 0; i < 3; i++ {
+		sk, err := kc.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		sks = append(sks, sk)
+	}
+
+	transfer := TransferOutput{
+		Amt: 12345,
+		OutputOwners: OutputOwners{
+			Threshold: 2,
+			Addrs: []ids.ShortID{
+				sks[1].PublicKey().Address(),
+				sks[2].PublicKey().Address(),
+			},
+		},
+	}
+	ids.SortShortIDs(transfer.Addrs)
+	if err := transfer.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the addresses of the signer keychain are spent with
+	signerKC := NewSignerKeychain(kc, sks[0].PublicKey().Address(), sks[1].PublicKey().Address())
+	if _, _, err := signerKC.Spend(&transfer, 0); err == nil {
+		t.Fatal("Shouldn't have been able to spend without the third address")
+	}
+
+	signerKC.Add(sks[2].PublicKey().Address(), sks[0].PublicKey().Address())
+	if len(signerKC.AddrList) != 3 || signerKC.AddrList[2] != sks[2].PublicKey().Address() {
+		t.Fatalf("Addresses should be kept in the order they were added: %v", signerKC.AddrList)
+	}
+
+	input, signers, err := signerKC.Spend(&transfer, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := input.(*TransferInput); !ok {
+		t.Fatal("Wrong input type returned")
+	}
+	if len(signers) != 2 || signers[0] != transfer.Addrs[0] || signers[1] != transfer.Addrs[1] {
+		t.Fatalf("Should have returned the owners of the output, returned %v", signers)
+	}
+
+	unsignedTx := []byte("transaction")
+	hash := hashing.ComputeHash256(unsignedTx)
+	cred, err := Sign(signerKC, unsignedTx, signers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	factory := crypto.FactorySECP256K1R{}
+	for i, sig := range cred.Sigs {
+		pk, err := factory.RecoverHashPublicKey(hash, sig[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pk.Address() != signers[i] {
+			t.Fatalf("Signature %d is by the wrong key", i)
+		}
+	}
+
+	if _, err := Sign(signerKC, unsignedTx, []ids.ShortID{ids.GenerateTestShortID()}); !errors.Is(err, errUnknownAddress) {
+		t.Fatalf("Should have failed to sign with an unknown address, returned %v", err)
+	}
+}
+
+func TestSignerKeychainPublicKeys(t *testing.T) {
+	kc := NewKeychain()
+	sk, err := kc.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk := sk.PublicKey().(*crypto.PublicKeySECP256K1R)
+
+	signerKC := NewSignerKeychain(kc)
+	if _, exists := signerKC.PublicKey(pk.Address()); exists {
+		t.Fatal("Shouldn't know the public key before it's added")
+	}
+	signerKC.AddPublicKeys(pk)
+	if !signerKC.Addrs.Contains(pk.Address()) {
+		t.Fatal("Should spend with the address of the public key")
+	}
+	if got, exists := signerKC.PublicKey(pk.Address()); !exists || got != pk {
+		t.Fatal("Should have returned the added public key")
+	}
+}